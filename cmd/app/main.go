@@ -1,11 +1,14 @@
 package main
 
 import (
+	"os"
 	"project-skbackend/configs"
 	"project-skbackend/internal/apps"
 )
 
 func main() {
 	cfg := configs.GetInstance()
-	apps.Run(cfg)
+
+	// * the first argument is the command, serve when there is none
+	apps.Execute(cfg, os.Args[1:])
 }
@@ -1,7 +1,6 @@
 package configs
 
 import (
-	"fmt"
 	"project-skbackend/packages/utils/utlogger"
 	"sync"
 
@@ -20,8 +19,17 @@ type (
 		DB
 		Mail
 		JWT
-		Credential
 		Order
+		Encryption
+		Security
+		Webhook
+		Audit
+		Analytics
+		Search
+		Health
+		Cron
+		Tracing
+		Log
 
 		// * external config
 		Redis
@@ -30,6 +38,7 @@ type (
 		Queue
 		DistanceMatrix
 		Telegram
+		OIDC
 	}
 
 	API struct {
@@ -38,10 +47,18 @@ type (
 		Timezone          string `env:"TZ" env-default:"Asia/Makassar"`
 		Environment       string `env:"API_ENV" env-default:"production"`
 		APIResetPassword
+		APICaregiverInvitation
+		APIDataExport
 	}
 	APIResetPassword struct {
 		Cooldown int `env:"API_RESET_PASSWORD_COOLDOWN" env-default:"5"`
 	}
+	APICaregiverInvitation struct {
+		Life int `env:"API_CAREGIVER_INVITATION_LIFE" env-default:"72"`
+	}
+	APIDataExport struct {
+		Life int `env:"API_DATA_EXPORT_LIFE" env-default:"72"`
+	}
 
 	Order struct {
 		OrderBuffer
@@ -58,12 +75,13 @@ type (
 	}
 
 	App struct {
-		Name        string `env:"APP_NAME" env-default:"meals-app"`
-		Version     string `env:"APP_VERSION" env-default:"1.0"`
-		Url         string `env:"APP_URL"`
-		Env         string `env:"APP_ENV" env-default:"development"`
-		Timeout     int    `env:"APP_TIMEOUT" env-default:"30"`
-		DeeplinkUrl string `env:"DEEPLINK_URL"`
+		Name            string `env:"APP_NAME" env-default:"meals-app"`
+		Version         string `env:"APP_VERSION" env-default:"1.0"`
+		Url             string `env:"APP_URL"`
+		Env             string `env:"APP_ENV" env-default:"development"`
+		Timeout         int    `env:"APP_TIMEOUT" env-default:"30"`
+		ShutdownTimeout int    `env:"APP_SHUTDOWN_TIMEOUT" env-default:"30"`
+		DeeplinkUrl     string `env:"DEEPLINK_URL"`
 	}
 
 	Web struct {
@@ -84,15 +102,16 @@ type (
 	}
 
 	DB struct {
-		PoolMax  int    `env:"DB_POOL_MAX" env-default:"10"`
-		Name     string `env:"DB_NAME" env-default:"meals-pg"`
-		User     string `env:"DB_USER" env-default:"root"`
-		Password string `env:"DB_PASSWORD" env-default:"password"`
-		Host     string `env:"DB_HOST" env-default:"localhost"`
-		Port     string `env:"DB_PORT" env-default:"5432"`
-		LogMode  bool   `env:"DB_LOG_MODE" env-default:"false"`
-		SslMode  string `env:"DB_SSL_MODE" env-default:"disable"`
-		Timezone string `env:"DB_TIMEZONE" env-default:"Asia/Makassar"`
+		PoolMax   int    `env:"DB_POOL_MAX" env-default:"10"`
+		Name      string `env:"DB_NAME" env-default:"meals-pg"`
+		User      string `env:"DB_USER" env-default:"root"`
+		Password  string `env:"DB_PASSWORD" env-default:"password"`
+		Host      string `env:"DB_HOST" env-default:"localhost"`
+		Port      string `env:"DB_PORT" env-default:"5432"`
+		SlowQuery int    `env:"DB_SLOW_QUERY" env-default:"200"`
+		SslMode   string `env:"DB_SSL_MODE" env-default:"disable"`
+		Timezone  string `env:"DB_TIMEZONE" env-default:"Asia/Makassar"`
+		Migrate   bool   `env:"DB_MIGRATE" env-default:"true"`
 	}
 
 	Mail struct {
@@ -111,24 +130,111 @@ type (
 		JWTRefreshToken
 	}
 	JWTAccessToken struct {
-		PublicKey  string `env:"ACCESS_TOKEN_PUBLIC_KEY"`
-		PrivateKey string `env:"ACCESS_TOKEN_PRIVATE_KEY"`
-		Life       int    `env:"ACCESS_TOKEN_LIFE" env-default:"3600"`
+		PublicKey         string `env:"ACCESS_TOKEN_PUBLIC_KEY"`
+		PrivateKey        string `env:"ACCESS_TOKEN_PRIVATE_KEY"`
+		PreviousPublicKey string `env:"ACCESS_TOKEN_PREVIOUS_PUBLIC_KEY"`
+		Life              int    `env:"ACCESS_TOKEN_LIFE" env-default:"3600"`
 	}
 	JWTRefreshToken struct {
-		PublicKey  string `env:"REFRESH_TOKEN_PUBLIC_KEY"`
-		PrivateKey string `env:"REFRESH_TOKEN_PRIVATE_KEY"`
-		Life       int    `env:"REFRESH_TOKEN_LIFE" env-default:"86400"`
+		PublicKey         string `env:"REFRESH_TOKEN_PUBLIC_KEY"`
+		PrivateKey        string `env:"REFRESH_TOKEN_PRIVATE_KEY"`
+		PreviousPublicKey string `env:"REFRESH_TOKEN_PREVIOUS_PUBLIC_KEY"`
+		Life              int    `env:"REFRESH_TOKEN_LIFE" env-default:"86400"`
+	}
+
+	Encryption struct {
+		Keys         map[string]string `env:"ENCRYPTION_KEYS"`
+		CurrentKeyID string            `env:"ENCRYPTION_CURRENT_KEY_ID"`
+		EncryptionReEncrypt
+	}
+	EncryptionReEncrypt struct {
+		Interval  int `env:"ENCRYPTION_REENCRYPT_INTERVAL" env-default:"60"`
+		BatchSize int `env:"ENCRYPTION_REENCRYPT_BATCH_SIZE" env-default:"100"`
 	}
 
-	Credential struct {
-		CredentialAdmin
+	Security struct {
+		SecurityThrottle
+		SecurityLockout
+		SecurityTwoFactor
+		SecurityAPIKey
+	}
+	SecurityThrottle struct {
+		Window                int `env:"SECURITY_THROTTLE_WINDOW" env-default:"15"`
+		SigninIP              int `env:"SECURITY_THROTTLE_SIGNIN_IP" env-default:"20"`
+		SigninAccount         int `env:"SECURITY_THROTTLE_SIGNIN_ACCOUNT" env-default:"10"`
+		ForgotPasswordIP      int `env:"SECURITY_THROTTLE_FORGOT_PASSWORD_IP" env-default:"10"`
+		ForgotPasswordAccount int `env:"SECURITY_THROTTLE_FORGOT_PASSWORD_ACCOUNT" env-default:"3"`
+		VerifySendIP          int `env:"SECURITY_THROTTLE_VERIFY_SEND_IP" env-default:"10"`
+		VerifySendAccount     int `env:"SECURITY_THROTTLE_VERIFY_SEND_ACCOUNT" env-default:"3"`
+		RegisterIP            int `env:"SECURITY_THROTTLE_REGISTER_IP" env-default:"5"`
+		RegisterAccount       int `env:"SECURITY_THROTTLE_REGISTER_ACCOUNT" env-default:"3"`
+	}
+	SecurityLockout struct {
+		Threshold   int `env:"SECURITY_LOCKOUT_THRESHOLD" env-default:"5"`
+		Duration    int `env:"SECURITY_LOCKOUT_DURATION" env-default:"15"`
+		MaxDuration int `env:"SECURITY_LOCKOUT_MAX_DURATION" env-default:"1440"`
+	}
+	SecurityTwoFactor struct {
+		Issuer            string `env:"SECURITY_TWO_FACTOR_ISSUER" env-default:"meals-app"`
+		ChallengeLife     int    `env:"SECURITY_TWO_FACTOR_CHALLENGE_LIFE" env-default:"5"`
+		ChallengeAttempts int    `env:"SECURITY_TWO_FACTOR_CHALLENGE_ATTEMPTS" env-default:"5"`
+		RecoveryCodes     int    `env:"SECURITY_TWO_FACTOR_RECOVERY_CODES" env-default:"10"`
+		AdminPolicy       string `env:"SECURITY_TWO_FACTOR_ADMIN_POLICY" env-default:"required"`
+		PartnerPolicy     string `env:"SECURITY_TWO_FACTOR_PARTNER_POLICY" env-default:"optional"`
 	}
-	CredentialAdmin struct {
-		Email     string `env:"ADMIN_EMAIL"`
-		Password  string `env:"ADMIN_PASSWORD"`
-		FirstName string `env:"ADMIN_FIRST_NAME"`
-		LastName  string `env:"ADMIN_LAST_NAME"`
+	SecurityAPIKey struct {
+		Life          int `env:"SECURITY_API_KEY_LIFE" env-default:"90"`
+		MaxLife       int `env:"SECURITY_API_KEY_MAX_LIFE" env-default:"365"`
+		TouchInterval int `env:"SECURITY_API_KEY_TOUCH_INTERVAL" env-default:"60"`
+	}
+
+	Webhook struct {
+		Timeout        int `env:"WEBHOOK_TIMEOUT" env-default:"10"`
+		Workers        int `env:"WEBHOOK_WORKERS" env-default:"4"`
+		MaxAttempts    int `env:"WEBHOOK_MAX_ATTEMPTS" env-default:"6"`
+		Backoff        int `env:"WEBHOOK_BACKOFF" env-default:"30"`
+		MaxBackoff     int `env:"WEBHOOK_MAX_BACKOFF" env-default:"3600"`
+		RetryBatchSize int `env:"WEBHOOK_RETRY_BATCH_SIZE" env-default:"100"`
+
+		// * only for development, lets a webhook point to a local address
+		AllowPrivate bool `env:"WEBHOOK_ALLOW_PRIVATE" env-default:"false"`
+	}
+
+	Audit struct {
+		ExportLimit int `env:"AUDIT_EXPORT_LIMIT" env-default:"10000"`
+	}
+
+	Analytics struct {
+		DefaultRange int `env:"ANALYTICS_DEFAULT_RANGE" env-default:"30"`
+		MaxRange     int `env:"ANALYTICS_MAX_RANGE" env-default:"366"`
+		TopLimit     int `env:"ANALYTICS_TOP_LIMIT" env-default:"10"`
+	}
+
+	Search struct {
+		DefaultLimit int `env:"SEARCH_DEFAULT_LIMIT" env-default:"20"`
+		Reindex      int `env:"SEARCH_REINDEX" env-default:"10"`
+	}
+
+	Health struct {
+		Timeout    int    `env:"HEALTH_CHECK_TIMEOUT" env-default:"2"`
+		WorkerPort string `env:"HEALTH_WORKER_PORT" env-default:"8081"`
+	}
+
+	Cron struct {
+		// * the replica running the jobs renews its lease on every run,
+		// * another one takes over once the lease expired
+		LeaderTTL int `env:"CRON_LEADER_TTL" env-default:"90"`
+	}
+
+	Tracing struct {
+		Enabled     bool    `env:"TRACING_ENABLED" env-default:"false"`
+		Endpoint    string  `env:"TRACING_ENDPOINT" env-default:"localhost:4318"`
+		Insecure    bool    `env:"TRACING_INSECURE" env-default:"true"`
+		SampleRatio float64 `env:"TRACING_SAMPLE_RATIO" env-default:"1"`
+	}
+
+	Log struct {
+		Level string `env:"LOG_LEVEL"`
 	}
 
 	Redis struct {
@@ -159,7 +265,12 @@ type (
 		Port     string `env:"RABBIT_MQ_PORT"`
 		Username string `env:"RABBIT_MQ_USERNAME"`
 		Password string `env:"RABBIT_MQ_PASSWORD"`
+		QueueDeadLetter
 		QueueMail
+		QueueWebhook
+	}
+	QueueDeadLetter struct {
+		Suffix string `env:"DEAD_LETTER_QUEUE_SUFFIX" env-default:"_dlq"`
 	}
 	QueueMail struct {
 		QueueName    string `env:"MAIL_QUEUE_NAME"`
@@ -167,6 +278,12 @@ type (
 		ExchangeType string `env:"MAIL_EXCHANGE_TYPE"`
 		BindingKey   string `env:"MAIL_BINDING_KEY"`
 	}
+	QueueWebhook struct {
+		QueueName    string `env:"WEBHOOK_QUEUE_NAME" env-default:"q_webhook"`
+		ExchangeName string `env:"WEBHOOK_EXCHANGE_NAME" env-default:"x_webhook"`
+		ExchangeType string `env:"WEBHOOK_EXCHANGE_TYPE" env-default:"direct"`
+		BindingKey   string `env:"WEBHOOK_BINDING_KEY" env-default:"webhook"`
+	}
 
 	DistanceMatrix struct {
 		Timeout int    `env:"DISTANCE_MATRIX_TIMEOUT" env-default:"10"`
@@ -180,6 +297,18 @@ type (
 		ToChatID string `env:"TG_TO_CHAT_ID"`
 		Timeout  int    `env:"TG_TIMEOUT" env-default:"30"`
 	}
+
+	OIDC struct {
+		Enabled      bool     `env:"OIDC_ENABLED" env-default:"false"`
+		Issuer       string   `env:"OIDC_ISSUER" env-default:"https://accounts.google.com"`
+		ClientID     string   `env:"OIDC_CLIENT_ID"`
+		ClientSecret string   `env:"OIDC_CLIENT_SECRET"`
+		RedirectURL  string   `env:"OIDC_REDIRECT_URL"`
+		Scopes       []string `env:"OIDC_SCOPES" env-default:"openid,email,profile"`
+		Timeout      int      `env:"OIDC_TIMEOUT" env-default:"10"`
+		StateLife    int      `env:"OIDC_STATE_LIFE" env-default:"10"`
+		SignupLife   int      `env:"OIDC_SIGNUP_LIFE" env-default:"30"`
+	}
 )
 
 var (
@@ -197,6 +326,7 @@ func GetInstance() *Config {
 			}
 
 			instance = cfg
+			utlogger.SetLevel(instance.Log.GetLevel(instance.API.Environment))
 			rdb = instance.GetRedisClient()
 		})
 	}
@@ -208,7 +338,7 @@ func newConfig() (*Config, error) {
 	cfg := &Config{}
 	err := cleanenv.ReadConfig(".env", cfg)
 	if err != nil {
-		fmt.Println("using environment variable")
+		utlogger.Info("using environment variable")
 	}
 
 	err = cleanenv.ReadEnv(cfg)
@@ -3,11 +3,15 @@ package configs
 import (
 	"errors"
 	"fmt"
-	"project-skbackend/internal/models"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/metrics"
+	"project-skbackend/packages/tracing"
 	"project-skbackend/packages/utils/utlogger"
+	"slices"
+	"time"
 
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 )
 
 func (db DB) GetDbConnectionUrl() string {
@@ -18,113 +22,111 @@ func (db DB) GetDbConnectionUrl() string {
 	return url
 }
 
-func (db DB) DBSetup(gdb *gorm.DB) error {
-	if err := db.SetupExtension(gdb); err != nil {
-		utlogger.Error(err)
-		return err
-	}
-
-	if err := db.AutoSeedEnum(gdb); err != nil {
+func (db DB) Connect() (*gorm.DB, error) {
+	gdb, err := gorm.Open(postgres.Open(db.GetDbConnectionUrl()), &gorm.Config{
+		Logger: utlogger.NewGormLogger(time.Duration(db.SlowQuery) * time.Millisecond),
+	})
+	if err != nil {
 		utlogger.Error(err)
-		return err
+		return nil, err
 	}
 
-	if err := db.AutoMigrate(gdb); err != nil {
+	// * records the duration of every query
+	if err := gdb.Use(metrics.GormPlugin{}); err != nil {
 		utlogger.Error(err)
-		return err
+		return nil, err
 	}
 
-	if err := db.AutoSeedData(gdb); err != nil {
+	// * runs every query in a span
+	if err := gdb.Use(tracing.GormPlugin{}); err != nil {
 		utlogger.Error(err)
-		return err
+		return nil, err
 	}
 
-	return nil
+	return gdb, nil
 }
 
-func (db DB) SetupExtension(gdb *gorm.DB) error {
-	extfuncs := []func(*gorm.DB) error{
-		InstallUUIDExtension,
-	}
-
-	var (
-		errs []error
-	)
+// * the schema is built by the versioned migrations, they run here when the
+// * migration on start is enabled and otherwise through the migrate command
+func (db DB) DBSetup(gdb *gorm.DB) error {
+	if db.Migrate {
+		migrator, err := NewMigrator(gdb)
+		if err != nil {
+			utlogger.Error(err)
+			return err
+		}
 
-	for _, extfunc := range extfuncs {
-		if err := extfunc(gdb); err != nil {
-			errs = append(errs, err)
+		if _, err := migrator.Up(0); err != nil {
+			utlogger.Error(err)
+			return err
 		}
 	}
 
-	if len(errs) > 0 {
-		utlogger.Error(errs...)
-		return errors.New("error setting up extension")
+	if err := db.AutoSeedData(gdb); err != nil {
+		utlogger.Error(err)
+		return err
 	}
 
 	return nil
 }
 
-func (db DB) AutoSeedEnum(gdb *gorm.DB) error {
-	seedfuncs := []func(*gorm.DB) error{
-		// ! ---------------------------------- enum ---------------------------------- ! //
-		SeedAllergensEnum,
-		SeedGenderEnum,
-		SeedMealStatusEnum,
-		SeedDonationStatusEnum,
-		SeedImageTypeEnum,
-		SeedPatronTypeEnum,
-		SeedOrganizationTypeEnum,
-		SeedUserRoleEnum,
-		SeedOrderStatusEnum,
+type (
+	Seeder struct {
+		Name string
+		Seed func(*gorm.DB) error
 	}
+)
+
+// * the seeders in the order they run, the seed command runs them by name
+var SEEDERS = []Seeder{
+	// * dependent
+	{Name: "meal-category", Seed: SeedMealCategoryData},
+	{Name: "role-permission", Seed: SeedRolePermissionData},
+
+	// ! ------------------------------- credentials ------------------------------ ! //
+	{Name: "organization", Seed: SeedOrganizationCredentials},
+	{Name: "partner", Seed: SeedPartnerCredentials},
+	{Name: "member", Seed: SeedMemberCredentials},
+	{Name: "patron", Seed: SeedPatronCredentials},
+
+	// ! ---------------------------------- data ---------------------------------- ! //
+	// * independent
+	{Name: "allergy", Seed: SeedAllergyData},
+	{Name: "illness", Seed: SeedIllnessData},
+
+	// * dependent
+	{Name: "meal", Seed: SeedMealData},
+	{Name: "cart", Seed: SeedCartData},
+	{Name: "member-health-history", Seed: SeedMemberHealthHistoryData},
+}
+
+func (db DB) AutoSeedData(gdb *gorm.DB) error {
+	return SeedData(gdb)
+}
 
+// * SeedData runs the named seeders in the order of the seeders, every
+// * seeder when no name is given
+func SeedData(gdb *gorm.DB, names ...string) error {
 	var (
-		errs []error
+		selected = make(map[string]bool, len(names))
+		errs     []error
 	)
 
-	for _, seedfunc := range seedfuncs {
-		if err := seedfunc(gdb); err != nil {
-			errs = append(errs, err)
+	// * every name is checked before anything is seeded
+	for _, name := range names {
+		if !slices.ContainsFunc(SEEDERS, func(s Seeder) bool { return s.Name == name }) {
+			return fmt.Errorf("%w: %s", consttypes.ErrSeederUnknown, name)
 		}
-	}
-
-	if len(errs) > 0 {
-		utlogger.Error(errs...)
-		return errors.New("error seeding enum")
-	}
-
-	return nil
-}
 
-func (db DB) AutoSeedData(gdb *gorm.DB) error {
-	seedfuncs := []func(*gorm.DB) error{
-		// * dependent
-		SeedMealCategoryData,
-
-		// ! ------------------------------- credentials ------------------------------ ! //
-		SeedAdminCredentials,
-		SeedOrganizationCredentials,
-		SeedPartnerCredentials,
-		SeedMemberCredentials,
-		SeedPatronCredentials,
-
-		// ! ---------------------------------- data ---------------------------------- ! //
-		// * independent
-		SeedAllergyData,
-		SeedIllnessData,
-
-		// * dependent
-		SeedMealData,
-		SeedCartData,
+		selected[name] = true
 	}
 
-	var (
-		errs []error
-	)
+	for _, seeder := range SEEDERS {
+		if len(names) > 0 && !selected[seeder.Name] {
+			continue
+		}
 
-	for _, seedfunc := range seedfuncs {
-		if err := seedfunc(gdb); err != nil {
+		if err := seeder.Seed(gdb); err != nil {
 			errs = append(errs, err)
 		}
 	}
@@ -136,45 +138,3 @@ func (db DB) AutoSeedData(gdb *gorm.DB) error {
 
 	return nil
 }
-
-func (db DB) GetLogLevel() logger.LogLevel {
-	loglevel := logger.Warn
-	if db.LogMode {
-		loglevel = logger.Info
-	}
-
-	return loglevel
-}
-
-func (db DB) AutoMigrate(gdb *gorm.DB) error {
-	return gdb.AutoMigrate(
-		&models.User{},
-		&models.UserImage{},
-		&models.Address{},
-		&models.AddressDetail{},
-
-		&models.Admin{},
-		&models.Allergy{},
-		&models.Caregiver{},
-		&models.Patron{},
-		&models.Donation{},
-		&models.DonationProof{},
-		&models.Illness{},
-		&models.Image{},
-		&models.Meal{},
-		&models.MealAllergy{},
-		&models.MealIllness{},
-		&models.MealImage{},
-		&models.MealCategory{},
-		&models.Member{},
-		&models.MemberAllergy{},
-		&models.MemberIllness{},
-		&models.Organization{},
-		&models.Partner{},
-		&models.Rating{},
-		&models.Cart{},
-		&models.Order{},
-		&models.OrderHistory{},
-		&models.OrderMeal{},
-	)
-}
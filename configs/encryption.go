@@ -0,0 +1,18 @@
+package configs
+
+import (
+	"project-skbackend/packages/customs/ctserializer"
+	"project-skbackend/packages/utils/utcrypto"
+)
+
+func (e Encryption) InitEncryption() error {
+	if err := utcrypto.Init(e.Keys, e.CurrentKeyID); err != nil {
+		return err
+	}
+
+	// * the serializer has to be registered before
+	// * gorm parses any model that uses it
+	ctserializer.Register()
+
+	return nil
+}
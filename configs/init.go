@@ -3,14 +3,13 @@ package configs
 import (
 	"context"
 	"project-skbackend/packages/utils/utlogger"
+	"time"
 
 	"github.com/minio/minio-go/v7"
 	"github.com/rabbitmq/amqp091-go"
 	amqp "github.com/rabbitmq/amqp091-go"
 	"github.com/redis/go-redis/v9"
-	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 )
 
 type (
@@ -23,7 +22,8 @@ type (
 		RedisDB *redis.Client
 		Minio   *minio.Client
 
-		clqueue func()
+		clqueue   func()
+		cltracing func(context.Context) error
 	}
 )
 
@@ -35,6 +35,20 @@ func NewInitConfig(ctx context.Context, cfg Config) *Init {
 }
 
 func (i *Init) InitConfig() (*Init, error) {
+	// * setup tracing, before the clients it instruments
+	cltracing, err := i.cfg.Tracing.Init(i.ctx, i.cfg.App)
+	if err != nil {
+		return nil, err
+	}
+	i.cltracing = cltracing
+
+	// * setup encryption
+	err = i.cfg.Encryption.InitEncryption()
+	if err != nil {
+		utlogger.Error(err)
+		return nil, err
+	}
+
 	// * setup database
 	db, err := i.initDB()
 	if err != nil {
@@ -66,11 +80,8 @@ func (i *Init) InitConfig() (*Init, error) {
 
 func (i *Init) initDB() (*gorm.DB, error) {
 	// * get the database object
-	db, err := gorm.Open(postgres.Open(i.cfg.DB.GetDbConnectionUrl()), &gorm.Config{
-		Logger: logger.Default.LogMode(i.cfg.GetLogLevel()),
-	})
+	db, err := i.cfg.DB.Connect()
 	if err != nil {
-		utlogger.Error(err)
 		return nil, err
 	}
 
@@ -99,10 +110,35 @@ func (i *Init) initQueue() (*amqp.Channel, func(), error) {
 	return ch, clqueue, nil
 }
 
+// * closes the connections once the components using them are stopped
 func (i *Init) Close() {
 	if i.clqueue != nil {
 		i.clqueue()
 	}
+
+	if i.RedisDB != nil {
+		if err := i.RedisDB.Close(); err != nil {
+			utlogger.Error(err)
+		}
+	}
+
+	if i.GormDB != nil {
+		if sqldb, err := i.GormDB.DB(); err == nil {
+			if err := sqldb.Close(); err != nil {
+				utlogger.Error(err)
+			}
+		}
+	}
+
+	// * the spans of the stopped components are exported last
+	if i.cltracing != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(i.cfg.App.ShutdownTimeout)*time.Second)
+		defer cancel()
+
+		if err := i.cltracing(ctx); err != nil {
+			utlogger.Error(err)
+		}
+	}
 }
 
 func (i *Init) initMinio() (*minio.Client, error) {
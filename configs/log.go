@@ -0,0 +1,26 @@
+package configs
+
+import (
+	"fmt"
+	"log/slog"
+	"project-skbackend/packages/utils/utlogger"
+)
+
+// * the level set by LOG_LEVEL, otherwise info in production and debug
+// * everywhere else
+func (l Log) GetLevel(env string) slog.Level {
+	if l.Level != "" {
+		var lvl slog.Level
+		if err := lvl.UnmarshalText([]byte(l.Level)); err == nil {
+			return lvl
+		}
+
+		utlogger.Warn(fmt.Sprintf("unknown log level %q, using the level of the environment", l.Level))
+	}
+
+	if env == "production" {
+		return slog.LevelInfo
+	}
+
+	return slog.LevelDebug
+}
@@ -0,0 +1,382 @@
+package configs
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/utils/utlogger"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// * the migrations are embedded, so the binary migrates without the source
+//
+//go:embed migrations/*.sql
+var migrationfs embed.FS
+
+const (
+	MIGRATION_DIR = "configs/migrations"
+
+	// * the key of the advisory lock held while migrating, so the
+	// * replicas starting together do not run the same migration
+	migrationlock = 7243519
+)
+
+var (
+	migrationfile = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+)
+
+type (
+	// * a versioned migration, the down is empty when it cannot be reverted
+	Migration struct {
+		Version int64
+		Name    string
+		Up      string
+		Down    string
+	}
+
+	MigrationStatus struct {
+		Version   int64
+		Name      string
+		AppliedAt *time.Time
+	}
+
+	Migrator struct {
+		gdb        *gorm.DB
+		migrations []Migration
+	}
+
+	schemamigration struct {
+		Version   int64
+		Name      string
+		AppliedAt time.Time
+	}
+)
+
+func NewMigrator(gdb *gorm.DB) (*Migrator, error) {
+	fsys, err := fs.Sub(migrationfs, "migrations")
+	if err != nil {
+		utlogger.Error(err)
+		return nil, err
+	}
+
+	migrations, err := loadMigrations(fsys)
+	if err != nil {
+		utlogger.Error(err)
+		return nil, err
+	}
+
+	return &Migrator{
+		gdb:        gdb,
+		migrations: migrations,
+	}, nil
+}
+
+// * reads the up and down files of every version, sorted by version
+func loadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		byversion = map[int64]*Migration{}
+	)
+
+	for _, entry := range entries {
+		matches := migrationfile.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			return nil, fmt.Errorf("%w: %s", consttypes.ErrMigrationInvalidFile, entry.Name())
+		}
+
+		version, err := strconv.ParseInt(matches[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", consttypes.ErrMigrationInvalidFile, entry.Name())
+		}
+
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byversion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: matches[2]}
+			byversion[version] = m
+		}
+
+		if m.Name != matches[2] {
+			return nil, fmt.Errorf("%w: %d", consttypes.ErrMigrationDuplicate, version)
+		}
+
+		if matches[3] == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byversion))
+	for _, m := range byversion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("%w: %d_%s", consttypes.ErrMigrationMissingUp, m.Version, m.Name)
+		}
+
+		migrations = append(migrations, *m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// * runs fn on one connection holding the migration lock, the schema
+// * migrations table is created first so every command can read it
+func (m *Migrator) locked(fn func(conn *gorm.DB) error) error {
+	return m.gdb.Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec("SELECT pg_advisory_lock(?)", migrationlock).Error; err != nil {
+			return err
+		}
+
+		defer conn.Exec("SELECT pg_advisory_unlock(?)", migrationlock)
+
+		err := conn.Exec(`
+			CREATE TABLE IF NOT EXISTS schema_migrations (
+				version bigint PRIMARY KEY,
+				name text NOT NULL,
+				applied_at timestamptz NOT NULL DEFAULT now()
+			)
+		`).Error
+		if err != nil {
+			return err
+		}
+
+		return fn(conn)
+	})
+}
+
+func (m *Migrator) applied(conn *gorm.DB) (map[int64]schemamigration, error) {
+	var (
+		rows []schemamigration
+	)
+
+	err := conn.
+		Table("schema_migrations").
+		Order("version").
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int64]schemamigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+
+	return applied, nil
+}
+
+// * Up applies the pending migrations in order, all of them when steps is
+// * zero. every migration runs in its own transaction with its record
+func (m *Migrator) Up(steps int) ([]Migration, error) {
+	var (
+		migrated []Migration
+	)
+
+	err := m.locked(func(conn *gorm.DB) error {
+		applied, err := m.applied(conn)
+		if err != nil {
+			return err
+		}
+
+		for _, mg := range m.migrations {
+			if _, ok := applied[mg.Version]; ok {
+				continue
+			}
+
+			if steps > 0 && len(migrated) == steps {
+				break
+			}
+
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Exec(mg.Up).Error; err != nil {
+					return err
+				}
+
+				return tx.
+					Exec("INSERT INTO schema_migrations (version, name) VALUES (?, ?)", mg.Version, mg.Name).Error
+			})
+			if err != nil {
+				return fmt.Errorf("migrate up %d_%s: %w", mg.Version, mg.Name, err)
+			}
+
+			utlogger.Info(fmt.Sprintf("Migrated up %d_%s", mg.Version, mg.Name))
+			migrated = append(migrated, mg)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		utlogger.Error(err)
+		return migrated, err
+	}
+
+	return migrated, nil
+}
+
+// * Down reverts the last applied migrations, newest first
+func (m *Migrator) Down(steps int) ([]Migration, error) {
+	var (
+		migrated []Migration
+	)
+
+	err := m.locked(func(conn *gorm.DB) error {
+		applied, err := m.applied(conn)
+		if err != nil {
+			return err
+		}
+
+		byversion := make(map[int64]Migration, len(m.migrations))
+		for _, mg := range m.migrations {
+			byversion[mg.Version] = mg
+		}
+
+		versions := make([]int64, 0, len(applied))
+		for version := range applied {
+			versions = append(versions, version)
+		}
+
+		sort.Slice(versions, func(i, j int) bool {
+			return versions[i] > versions[j]
+		})
+
+		for _, version := range versions {
+			if len(migrated) == steps {
+				break
+			}
+
+			mg, ok := byversion[version]
+			if !ok {
+				return fmt.Errorf("%w: %d_%s", consttypes.ErrMigrationUnknown, version, applied[version].Name)
+			}
+
+			if mg.Down == "" {
+				return fmt.Errorf("%w: %d_%s", consttypes.ErrMigrationIrreversible, mg.Version, mg.Name)
+			}
+
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Exec(mg.Down).Error; err != nil {
+					return err
+				}
+
+				return tx.
+					Exec("DELETE FROM schema_migrations WHERE version = ?", mg.Version).Error
+			})
+			if err != nil {
+				return fmt.Errorf("migrate down %d_%s: %w", mg.Version, mg.Name, err)
+			}
+
+			utlogger.Info(fmt.Sprintf("Migrated down %d_%s", mg.Version, mg.Name))
+			migrated = append(migrated, mg)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		utlogger.Error(err)
+		return migrated, err
+	}
+
+	return migrated, nil
+}
+
+// * Status lists every migration file with when it was applied, along
+// * with the applied versions that have no file anymore
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	var (
+		statuses []MigrationStatus
+	)
+
+	err := m.locked(func(conn *gorm.DB) error {
+		applied, err := m.applied(conn)
+		if err != nil {
+			return err
+		}
+
+		for _, mg := range m.migrations {
+			status := MigrationStatus{Version: mg.Version, Name: mg.Name}
+			if row, ok := applied[mg.Version]; ok {
+				status.AppliedAt = &row.AppliedAt
+				delete(applied, mg.Version)
+			}
+
+			statuses = append(statuses, status)
+		}
+
+		for _, row := range applied {
+			statuses = append(statuses, MigrationStatus{Version: row.Version, Name: row.Name, AppliedAt: &row.AppliedAt})
+		}
+
+		sort.SliceStable(statuses, func(i, j int) bool {
+			return statuses[i].Version < statuses[j].Version
+		})
+
+		return nil
+	})
+
+	if err != nil {
+		utlogger.Error(err)
+		return nil, err
+	}
+
+	return statuses, nil
+}
+
+// * CreateMigration writes empty up and down files for the next version
+// * into the migration directory of the source tree
+func CreateMigration(dir string, name string) ([]string, error) {
+	migrations, err := loadMigrations(os.DirFS(dir))
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		version int64 = 1
+	)
+
+	if len(migrations) > 0 {
+		version = migrations[len(migrations)-1].Version + 1
+	}
+
+	var (
+		files []string
+	)
+
+	for _, direction := range []string{"up", "down"} {
+		file := filepath.Join(dir, fmt.Sprintf("%06d_%s.%s.sql", version, name, direction))
+		if !migrationfile.MatchString(filepath.Base(file)) {
+			return nil, fmt.Errorf("%w: %s", consttypes.ErrMigrationInvalidFile, filepath.Base(file))
+		}
+
+		content := fmt.Sprintf("-- * %s %s\n", strings.ReplaceAll(name, "_", " "), direction)
+		if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+			return nil, err
+		}
+
+		files = append(files, file)
+	}
+
+	return files, nil
+}
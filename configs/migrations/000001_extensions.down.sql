@@ -0,0 +1,3 @@
+DROP EXTENSION IF EXISTS pg_trgm;
+DROP EXTENSION IF EXISTS "uuid-ossp";
+DROP FUNCTION IF EXISTS uuid_generate_v7();
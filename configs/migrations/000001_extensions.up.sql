@@ -0,0 +1,28 @@
+-- * the ids of every table default to a uuid v7, so they follow the creation order
+CREATE OR REPLACE FUNCTION uuid_generate_v7()
+RETURNS uuid AS $$
+BEGIN
+	-- Use random v4 UUID as starting point (which has the same variant we need)
+	-- Then overlay timestamp
+	-- Finally set version 7 by flipping the 2 and 1 bit in the version 4 string
+	RETURN encode(
+		set_bit(
+			set_bit(
+				overlay(
+					uuid_send(gen_random_uuid())
+					PLACING substring(int8send(floor(extract(epoch FROM clock_timestamp()) * 1000)::bigint) FROM 3)
+					FROM 1 FOR 6
+				),
+				52, 1
+			),
+			53, 1
+		),
+		'hex'
+	)::uuid;
+END
+$$ LANGUAGE plpgsql VOLATILE;
+
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+-- * the trigram extension backs the fuzzy matching of the meal search
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
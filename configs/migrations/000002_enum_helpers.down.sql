@@ -0,0 +1,4 @@
+DROP FUNCTION IF EXISTS migrate_drop_enum_value(text, text);
+DROP FUNCTION IF EXISTS migrate_rename_enum_value(text, text, text);
+DROP FUNCTION IF EXISTS migrate_create_enum(text, text[]);
+DROP FUNCTION IF EXISTS migrate_add_enum_value(text, text);
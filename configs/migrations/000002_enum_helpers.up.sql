@@ -0,0 +1,92 @@
+-- * helpers for the migrations that change an enum, they skip what is already
+-- * done so a migration can call them again after it failed halfway
+
+-- * adds the value at the end of the enum
+CREATE OR REPLACE FUNCTION migrate_add_enum_value(enumname text, enumvalue text)
+RETURNS void AS $$
+BEGIN
+	EXECUTE format('ALTER TYPE %I ADD VALUE IF NOT EXISTS %L', enumname, enumvalue);
+END
+$$ LANGUAGE plpgsql;
+
+-- * creates the enum, or adds the values it is missing when it exists
+CREATE OR REPLACE FUNCTION migrate_create_enum(enumname text, enumvalues text[])
+RETURNS void AS $$
+DECLARE
+	enumvalue text;
+BEGIN
+	IF to_regtype(enumname) IS NULL THEN
+		EXECUTE format('CREATE TYPE %I AS ENUM (%s)', enumname, (
+			SELECT string_agg(quote_literal(v), ', ') FROM unnest(enumvalues) v
+		));
+		RETURN;
+	END IF;
+
+	FOREACH enumvalue IN ARRAY enumvalues LOOP
+		PERFORM migrate_add_enum_value(enumname, enumvalue);
+	END LOOP;
+END
+$$ LANGUAGE plpgsql;
+
+-- * the rows holding the value are renamed with it
+CREATE OR REPLACE FUNCTION migrate_rename_enum_value(enumname text, oldvalue text, newvalue text)
+RETURNS void AS $$
+BEGIN
+	IF EXISTS (SELECT 1 FROM pg_enum WHERE enumtypid = to_regtype(enumname) AND enumlabel = oldvalue) THEN
+		EXECUTE format('ALTER TYPE %I RENAME VALUE %L TO %L', enumname, oldvalue, newvalue);
+	END IF;
+END
+$$ LANGUAGE plpgsql;
+
+-- * postgres cannot drop an enum value, so the enum is created again without
+-- * it and every column of the enum is moved over. the rows still holding the
+-- * value have to be moved to another value first or the cast fails
+CREATE OR REPLACE FUNCTION migrate_drop_enum_value(enumname text, enumvalue text)
+RETURNS void AS $$
+DECLARE
+	oldname text := enumname || '_old';
+	col record;
+BEGIN
+	IF NOT EXISTS (SELECT 1 FROM pg_enum WHERE enumtypid = to_regtype(enumname) AND enumlabel = enumvalue) THEN
+		RETURN;
+	END IF;
+
+	EXECUTE format('ALTER TYPE %I RENAME TO %I', enumname, oldname);
+
+	EXECUTE format('CREATE TYPE %I AS ENUM (%s)', enumname, (
+		SELECT string_agg(quote_literal(enumlabel), ', ' ORDER BY enumsortorder)
+		FROM pg_enum
+		WHERE enumtypid = to_regtype(oldname) AND enumlabel <> enumvalue
+	));
+
+	FOR col IN
+		SELECT
+			c.relname AS tablename,
+			a.attname AS columnname,
+			pg_get_expr(d.adbin, d.adrelid) AS defaultvalue
+		FROM pg_attribute a
+		JOIN pg_class c ON c.oid = a.attrelid AND c.relkind = 'r'
+		JOIN pg_namespace n ON n.oid = c.relnamespace AND n.nspname = current_schema()
+		LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+		WHERE a.atttypid = to_regtype(oldname) AND a.attnum > 0 AND NOT a.attisdropped
+	LOOP
+		IF col.defaultvalue IS NOT NULL THEN
+			EXECUTE format('ALTER TABLE %I ALTER COLUMN %I DROP DEFAULT', col.tablename, col.columnname);
+		END IF;
+
+		EXECUTE format(
+			'ALTER TABLE %I ALTER COLUMN %I TYPE %I USING %I::text::%I',
+			col.tablename, col.columnname, enumname, col.columnname, enumname
+		);
+
+		IF col.defaultvalue IS NOT NULL THEN
+			EXECUTE format(
+				'ALTER TABLE %I ALTER COLUMN %I SET DEFAULT %s',
+				col.tablename, col.columnname, replace(col.defaultvalue, oldname, enumname)
+			);
+		END IF;
+	END LOOP;
+
+	EXECUTE format('DROP TYPE %I', oldname);
+END
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,192 @@
+-- * the schema as it was built by the automigration of the models, the
+-- * statements skip what exists so the databases built before keep their data
+
+-- ! ---------------------------------- enum ---------------------------------- ! --
+SELECT migrate_create_enum('allergens_enum', ARRAY['Food', 'Medical', 'Environmental', 'Contact']);
+SELECT migrate_create_enum('gender_enum', ARRAY['Male', 'Female', 'Other']);
+SELECT migrate_create_enum('meal_status_enum', ARRAY['Active', 'Inactive', 'Out of Stock']);
+SELECT migrate_create_enum('donation_status_enum', ARRAY['Accepted', 'Rejected', 'Pending']);
+SELECT migrate_create_enum('image_type_enum', ARRAY['Profile', 'Meal', 'Meal Category', 'Donation Proof']);
+SELECT migrate_create_enum('patron_type_enum', ARRAY['Organization', 'Personal']);
+SELECT migrate_create_enum('organization_type_enum', ARRAY['Nursing Home']);
+SELECT migrate_create_enum('user_role_enum', ARRAY['1', '2', '3', '6', '4', '5', '0']);
+SELECT migrate_create_enum('order_status_enum', ARRAY['Placed', 'Confirmed', 'Being Prepared', 'Prepared', 'Picked Up', 'Completed', 'Cancelled']);
+SELECT migrate_create_enum('caregiver_invitation_status_enum', ARRAY['Pending', 'Accepted', 'Declined', 'Revoked']);
+SELECT migrate_create_enum('data_request_status_enum', ARRAY['Pending', 'Processing', 'Completed', 'Failed', 'Expired']);
+SELECT migrate_create_enum('security_event_type_enum', ARRAY['Signin Failed', 'Throttled', 'Account Locked', 'Account Unlocked', 'Refresh Token Reused', 'Two Factor Failed', 'Two Factor Enabled', 'Two Factor Disabled', 'Recovery Code Used', 'API Key Created', 'API Key Revoked']);
+SELECT migrate_create_enum('webhook_delivery_status_enum', ARRAY['Pending', 'Retrying', 'Delivered', 'Failed']);
+SELECT migrate_create_enum('audit_action_enum', ARRAY['Create', 'Update', 'Delete']);
+
+-- ! ---------------------------------- table --------------------------------- ! --
+CREATE TABLE IF NOT EXISTS "users" ("id" uuid DEFAULT uuid_generate_v7(),"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"email" text,"password" varchar(255),"role" user_role_enum,"confirmation_token" text,"confirmed_at" timestamptz,"confirmation_sent_at" timestamptz,"reset_password_token" text,"reset_password_sent_at" timestamptz,PRIMARY KEY ("id"),CONSTRAINT "uni_users_email" UNIQUE ("email"));
+CREATE INDEX IF NOT EXISTS "idx_users_deleted_at" ON "users" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "images" ("id" uuid DEFAULT uuid_generate_v7(),"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"name" text,"path" text,"type" image_type_enum,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_images_deleted_at" ON "images" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "user_images" ("id" uuid DEFAULT uuid_generate_v7(),"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"user_id" uuid,"image_id" uuid,PRIMARY KEY ("id"),CONSTRAINT "fk_user_images_image" FOREIGN KEY ("image_id") REFERENCES "images"("id"),CONSTRAINT "fk_users_image" FOREIGN KEY ("user_id") REFERENCES "users"("id"));
+CREATE INDEX IF NOT EXISTS "idx_user_images_deleted_at" ON "user_images" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "address_details" ("id" uuid DEFAULT uuid_generate_v7(),"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"longitude" text,"latitude" text,"formatted_address" text DEFAULT null,"post_code" text DEFAULT null,"country" text DEFAULT null,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_address_details_deleted_at" ON "address_details" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "addresses" ("id" uuid DEFAULT uuid_generate_v7(),"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"user_id" uuid,"name" text,"address" text,"note" text,"address_detail_id" uuid,PRIMARY KEY ("id"),CONSTRAINT "fk_addresses_address_detail" FOREIGN KEY ("address_detail_id") REFERENCES "address_details"("id"),CONSTRAINT "fk_users_addresses" FOREIGN KEY ("user_id") REFERENCES "users"("id"));
+CREATE INDEX IF NOT EXISTS "idx_addresses_deleted_at" ON "addresses" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "admins" ("id" uuid DEFAULT uuid_generate_v7(),"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"user_id" uuid,"first_name" text,"last_name" text,"gender" gender_enum,"date_of_birth" text,PRIMARY KEY ("id"),CONSTRAINT "fk_admins_user" FOREIGN KEY ("user_id") REFERENCES "users"("id"));
+CREATE INDEX IF NOT EXISTS "idx_admins_deleted_at" ON "admins" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "allergies" ("id" uuid DEFAULT uuid_generate_v7(),"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"name" text,"description" text,"allergens" allergens_enum,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_allergies_deleted_at" ON "allergies" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "caregivers" ("id" uuid DEFAULT uuid_generate_v7(),"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"user_id" uuid,"gender" gender_enum,"first_name" text,"last_name" text,"date_of_birth" text,PRIMARY KEY ("id"),CONSTRAINT "fk_caregivers_user" FOREIGN KEY ("user_id") REFERENCES "users"("id"));
+CREATE INDEX IF NOT EXISTS "idx_caregivers_deleted_at" ON "caregivers" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "patrons" ("id" uuid DEFAULT uuid_generate_v7(),"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"user_id" uuid,"type" patron_type_enum,"name" text,PRIMARY KEY ("id"),CONSTRAINT "fk_patrons_user" FOREIGN KEY ("user_id") REFERENCES "users"("id"));
+CREATE INDEX IF NOT EXISTS "idx_patrons_deleted_at" ON "patrons" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "donations" ("id" uuid DEFAULT uuid_generate_v7(),"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"patron_id" uuid NOT NULL,"value" decimal NOT NULL,"status" donation_status_enum NOT NULL,PRIMARY KEY ("id"),CONSTRAINT "fk_patrons_donations" FOREIGN KEY ("patron_id") REFERENCES "patrons"("id"));
+CREATE INDEX IF NOT EXISTS "idx_donations_deleted_at" ON "donations" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "donation_proofs" ("id" uuid DEFAULT uuid_generate_v7(),"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"donation_id" uuid NOT NULL,"image_id" uuid NOT NULL,PRIMARY KEY ("id"),CONSTRAINT "fk_donation_proofs_image" FOREIGN KEY ("image_id") REFERENCES "images"("id"),CONSTRAINT "fk_donations_proof" FOREIGN KEY ("donation_id") REFERENCES "donations"("id") ON DELETE CASCADE);
+CREATE INDEX IF NOT EXISTS "idx_donation_proofs_deleted_at" ON "donation_proofs" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "illnesses" ("id" uuid DEFAULT uuid_generate_v7(),"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"name" text,"description" text,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_illnesses_deleted_at" ON "illnesses" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "partners" ("id" uuid DEFAULT uuid_generate_v7(),"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"user_id" uuid,"name" text,PRIMARY KEY ("id"),CONSTRAINT "fk_partners_user" FOREIGN KEY ("user_id") REFERENCES "users"("id"));
+CREATE INDEX IF NOT EXISTS "idx_partners_deleted_at" ON "partners" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "meal_categories" ("id" uuid DEFAULT uuid_generate_v7(),"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"name" text,"image_id" uuid,PRIMARY KEY ("id"),CONSTRAINT "fk_meal_categories_image" FOREIGN KEY ("image_id") REFERENCES "images"("id"));
+CREATE INDEX IF NOT EXISTS "idx_meal_categories_deleted_at" ON "meal_categories" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "meals" ("id" uuid DEFAULT uuid_generate_v7(),"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"partner_id" uuid,"meal_category_id" uuid,"name" text,"status" meal_status_enum,"description" text,PRIMARY KEY ("id"),CONSTRAINT "fk_meals_partner" FOREIGN KEY ("partner_id") REFERENCES "partners"("id"),CONSTRAINT "fk_meals_meal_category" FOREIGN KEY ("meal_category_id") REFERENCES "meal_categories"("id"));
+CREATE INDEX IF NOT EXISTS "idx_meals_meal_category_id" ON "meals" ("meal_category_id");
+CREATE INDEX IF NOT EXISTS "idx_meals_deleted_at" ON "meals" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "meal_allergies" ("id" uuid DEFAULT uuid_generate_v7(),"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"meal_id" uuid,"allergy_id" uuid,PRIMARY KEY ("id"),CONSTRAINT "fk_meal_allergies_allergy" FOREIGN KEY ("allergy_id") REFERENCES "allergies"("id"),CONSTRAINT "fk_meals_allergies" FOREIGN KEY ("meal_id") REFERENCES "meals"("id"));
+CREATE INDEX IF NOT EXISTS "idx_meal_allergies_deleted_at" ON "meal_allergies" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "meal_illnesses" ("id" uuid DEFAULT uuid_generate_v7(),"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"meal_id" uuid,"illness_id" uuid,PRIMARY KEY ("id"),CONSTRAINT "fk_meal_illnesses_illness" FOREIGN KEY ("illness_id") REFERENCES "illnesses"("id"),CONSTRAINT "fk_meals_illnesses" FOREIGN KEY ("meal_id") REFERENCES "meals"("id"));
+CREATE INDEX IF NOT EXISTS "idx_meal_illnesses_deleted_at" ON "meal_illnesses" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "meal_images" ("id" uuid DEFAULT uuid_generate_v7(),"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"meal_id" uuid,"image_id" uuid,PRIMARY KEY ("id"),CONSTRAINT "fk_meal_images_image" FOREIGN KEY ("image_id") REFERENCES "images"("id"),CONSTRAINT "fk_meals_images" FOREIGN KEY ("meal_id") REFERENCES "meals"("id"));
+CREATE INDEX IF NOT EXISTS "idx_meal_images_deleted_at" ON "meal_images" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "organizations" ("id" uuid DEFAULT uuid_generate_v7(),"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"user_id" uuid,"type" organization_type_enum,"name" text,PRIMARY KEY ("id"),CONSTRAINT "fk_organizations_user" FOREIGN KEY ("user_id") REFERENCES "users"("id"));
+CREATE INDEX IF NOT EXISTS "idx_organizations_deleted_at" ON "organizations" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "members" ("id" uuid DEFAULT uuid_generate_v7(),"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"user_id" uuid,"organization_id" uuid,"height" text,"weight" text,"bmi" text,"first_name" text,"last_name" text,"gender" gender_enum,"date_of_birth" text,PRIMARY KEY ("id"),CONSTRAINT "fk_members_user" FOREIGN KEY ("user_id") REFERENCES "users"("id"),CONSTRAINT "fk_members_organization" FOREIGN KEY ("organization_id") REFERENCES "organizations"("id"));
+CREATE INDEX IF NOT EXISTS "idx_members_deleted_at" ON "members" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "member_allergies" ("id" uuid DEFAULT uuid_generate_v7(),"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"member_id" uuid,"allergy_id" text,PRIMARY KEY ("id"),CONSTRAINT "fk_members_allergies" FOREIGN KEY ("member_id") REFERENCES "members"("id"));
+CREATE INDEX IF NOT EXISTS "idx_member_allergies_deleted_at" ON "member_allergies" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "member_illnesses" ("id" uuid DEFAULT uuid_generate_v7(),"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"member_id" uuid,"illness_id" text,PRIMARY KEY ("id"),CONSTRAINT "fk_members_illnesses" FOREIGN KEY ("member_id") REFERENCES "members"("id"));
+CREATE INDEX IF NOT EXISTS "idx_member_illnesses_deleted_at" ON "member_illnesses" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "partner_meal_category_composites" ("partner_id" uuid DEFAULT uuid_generate_v7(),"meal_category_id" uuid DEFAULT uuid_generate_v7(),PRIMARY KEY ("partner_id","meal_category_id"),CONSTRAINT "fk_partner_meal_category_composites_partner" FOREIGN KEY ("partner_id") REFERENCES "partners"("id"),CONSTRAINT "fk_partner_meal_category_composites_meal_category" FOREIGN KEY ("meal_category_id") REFERENCES "meal_categories"("id"));
+
+CREATE TABLE IF NOT EXISTS "ratings" ("id" uuid DEFAULT uuid_generate_v7(),"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"meal_id" uuid,"user_id" uuid,"value" decimal,"description" text,PRIMARY KEY ("id"),CONSTRAINT "fk_ratings_meal" FOREIGN KEY ("meal_id") REFERENCES "meals"("id"),CONSTRAINT "fk_ratings_user" FOREIGN KEY ("user_id") REFERENCES "users"("id"));
+CREATE INDEX IF NOT EXISTS "idx_ratings_deleted_at" ON "ratings" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "carts" ("id" uuid DEFAULT uuid_generate_v7(),"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"meal_id" uuid,"partner_id" uuid,"member_id" uuid,"quantity" bigint,PRIMARY KEY ("id"),CONSTRAINT "fk_carts_partner" FOREIGN KEY ("partner_id") REFERENCES "partners"("id"),CONSTRAINT "fk_carts_member" FOREIGN KEY ("member_id") REFERENCES "members"("id"),CONSTRAINT "fk_carts_meal" FOREIGN KEY ("meal_id") REFERENCES "meals"("id"));
+CREATE INDEX IF NOT EXISTS "idx_carts_deleted_at" ON "carts" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "orders" ("id" uuid DEFAULT uuid_generate_v7(),"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"member_id" uuid,"partner_id" uuid,"status" order_status_enum,PRIMARY KEY ("id"),CONSTRAINT "fk_orders_member" FOREIGN KEY ("member_id") REFERENCES "members"("id"),CONSTRAINT "fk_orders_partner" FOREIGN KEY ("partner_id") REFERENCES "partners"("id"));
+CREATE INDEX IF NOT EXISTS "idx_orders_deleted_at" ON "orders" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "order_histories" ("id" uuid DEFAULT uuid_generate_v7(),"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"order_id" uuid,"user_id" uuid,"status" order_status_enum,"description" text,PRIMARY KEY ("id"),CONSTRAINT "fk_orders_history" FOREIGN KEY ("order_id") REFERENCES "orders"("id"),CONSTRAINT "fk_order_histories_user" FOREIGN KEY ("user_id") REFERENCES "users"("id"));
+CREATE INDEX IF NOT EXISTS "idx_order_histories_deleted_at" ON "order_histories" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "order_meals" ("id" uuid DEFAULT uuid_generate_v7(),"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"order_id" uuid,"meal_id" uuid,"partner_id" uuid,"quantity" bigint,PRIMARY KEY ("id"),CONSTRAINT "fk_order_meals_meal" FOREIGN KEY ("meal_id") REFERENCES "meals"("id"),CONSTRAINT "fk_order_meals_partner" FOREIGN KEY ("partner_id") REFERENCES "partners"("id"),CONSTRAINT "fk_orders_meals" FOREIGN KEY ("order_id") REFERENCES "orders"("id"));
+CREATE INDEX IF NOT EXISTS "idx_order_meals_deleted_at" ON "order_meals" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "role_permissions" ("id" uuid DEFAULT uuid_generate_v7(),"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"role" user_role_enum,"permission" text,PRIMARY KEY ("id"));
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_role_permission" ON "role_permissions" ("role","permission");
+CREATE INDEX IF NOT EXISTS "idx_role_permissions_deleted_at" ON "role_permissions" ("deleted_at");
+
+-- * the default grants that were seeded, so a grant is seeded once and a
+-- * grant an admin removed is not seeded again
+CREATE TABLE IF NOT EXISTS "role_permission_seeds" ("role" user_role_enum NOT NULL,"permission" text NOT NULL,"seeded_at" timestamptz NOT NULL DEFAULT NOW(),PRIMARY KEY ("role","permission"));
+
+CREATE TABLE IF NOT EXISTS "member_caregivers" ("id" uuid DEFAULT uuid_generate_v7(),"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"member_id" uuid,"caregiver_id" uuid,"can_order" boolean NOT NULL DEFAULT false,"can_edit_health" boolean NOT NULL DEFAULT false,PRIMARY KEY ("id"),CONSTRAINT "fk_member_caregivers_caregiver" FOREIGN KEY ("caregiver_id") REFERENCES "caregivers"("id"),CONSTRAINT "fk_members_caregivers" FOREIGN KEY ("member_id") REFERENCES "members"("id"));
+CREATE INDEX IF NOT EXISTS "idx_member_caregivers_deleted_at" ON "member_caregivers" ("deleted_at");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_member_caregiver" ON "member_caregivers" ("member_id","caregiver_id");
+
+CREATE TABLE IF NOT EXISTS "caregiver_invitations" ("id" uuid DEFAULT uuid_generate_v7(),"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"member_id" uuid,"email" text,"token" text,"can_order" boolean NOT NULL DEFAULT false,"can_edit_health" boolean NOT NULL DEFAULT false,"status" caregiver_invitation_status_enum,"expires_at" timestamptz,"responded_at" timestamptz,PRIMARY KEY ("id"),CONSTRAINT "fk_caregiver_invitations_member" FOREIGN KEY ("member_id") REFERENCES "members"("id"));
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_caregiver_invitations_token" ON "caregiver_invitations" ("token");
+CREATE INDEX IF NOT EXISTS "idx_caregiver_invitations_deleted_at" ON "caregiver_invitations" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "member_health_histories" ("id" uuid DEFAULT uuid_generate_v7(),"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"member_id" text,"version" bigint,"changed_by_id" text,"changed_by_role" user_role_enum,"previous" text,"current" text,"height" text,"weight" text,"bmi" text,PRIMARY KEY ("id"));
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_member_health_history_version" ON "member_health_histories" ("member_id","version");
+CREATE INDEX IF NOT EXISTS "idx_member_health_histories_deleted_at" ON "member_health_histories" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "data_exports" ("id" uuid DEFAULT uuid_generate_v7(),"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"user_id" uuid,"status" data_request_status_enum,"object_name" text,"size" bigint,"error" text,"completed_at" timestamptz,"expires_at" timestamptz,PRIMARY KEY ("id"),CONSTRAINT "fk_data_exports_user" FOREIGN KEY ("user_id") REFERENCES "users"("id"));
+CREATE INDEX IF NOT EXISTS "idx_data_exports_deleted_at" ON "data_exports" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "data_erasures" ("id" uuid DEFAULT uuid_generate_v7(),"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"user_id" text,"requested_by_id" text,"status" data_request_status_enum,"error" text,"completed_at" timestamptz,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_data_erasures_deleted_at" ON "data_erasures" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "security_events" ("id" uuid DEFAULT uuid_generate_v7(),"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"user_id" text,"email" text,"type" security_event_type_enum,"ip" text,"user_agent" text,"detail" text,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_security_events_deleted_at" ON "security_events" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_security_events_user_id" ON "security_events" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "user_two_factors" ("id" uuid DEFAULT uuid_generate_v7(),"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"user_id" text,"secret" text,"last_used_step" bigint,"enabled_at" timestamptz,PRIMARY KEY ("id"),CONSTRAINT "uni_user_two_factors_user_id" UNIQUE ("user_id"));
+CREATE INDEX IF NOT EXISTS "idx_user_two_factors_deleted_at" ON "user_two_factors" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "user_recovery_codes" ("id" uuid DEFAULT uuid_generate_v7(),"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"user_id" text,"code_hash" text,"used_at" timestamptz,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_user_recovery_codes_code_hash" ON "user_recovery_codes" ("code_hash");
+CREATE INDEX IF NOT EXISTS "idx_user_recovery_codes_user_id" ON "user_recovery_codes" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_user_recovery_codes_deleted_at" ON "user_recovery_codes" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "user_identities" ("id" uuid DEFAULT uuid_generate_v7(),"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"user_id" text,"issuer" text,"subject" text,"email" text,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_user_identities_deleted_at" ON "user_identities" ("deleted_at");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_user_identity_issuer_subject" ON "user_identities" ("issuer","subject");
+CREATE INDEX IF NOT EXISTS "idx_user_identities_user_id" ON "user_identities" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "service_accounts" ("id" uuid DEFAULT uuid_generate_v7(),"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"user_id" uuid,"name" text,"description" text,"created_by_id" text,"disabled_at" timestamptz,PRIMARY KEY ("id"),CONSTRAINT "fk_service_accounts_user" FOREIGN KEY ("user_id") REFERENCES "users"("id"));
+CREATE INDEX IF NOT EXISTS "idx_service_accounts_user_id" ON "service_accounts" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_service_accounts_deleted_at" ON "service_accounts" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "service_account_scopes" ("id" uuid DEFAULT uuid_generate_v7(),"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"service_account_id" uuid,"scope" text,PRIMARY KEY ("id"),CONSTRAINT "fk_service_accounts_scopes" FOREIGN KEY ("service_account_id") REFERENCES "service_accounts"("id"));
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_service_account_scope" ON "service_account_scopes" ("service_account_id","scope");
+CREATE INDEX IF NOT EXISTS "idx_service_account_scopes_deleted_at" ON "service_account_scopes" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "api_keys" ("id" uuid DEFAULT uuid_generate_v7(),"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"service_account_id" uuid,"name" text,"prefix" text,"key_hash" text,"expires_at" timestamptz,"last_used_at" timestamptz,"last_used_ip" text,"revoked_at" timestamptz,PRIMARY KEY ("id"),CONSTRAINT "fk_api_keys_service_account" FOREIGN KEY ("service_account_id") REFERENCES "service_accounts"("id"));
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_api_keys_key_hash" ON "api_keys" ("key_hash");
+CREATE INDEX IF NOT EXISTS "idx_api_keys_service_account_id" ON "api_keys" ("service_account_id");
+CREATE INDEX IF NOT EXISTS "idx_api_keys_deleted_at" ON "api_keys" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "webhooks" ("id" uuid DEFAULT uuid_generate_v7(),"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"user_id" text,"url" text,"description" text,"event_types" jsonb,"secret" text,"active" boolean NOT NULL DEFAULT true,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_webhooks_user_id" ON "webhooks" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_webhooks_deleted_at" ON "webhooks" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "webhook_deliveries" ("id" uuid DEFAULT uuid_generate_v7(),"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"webhook_id" uuid,"event_id" text,"event_type" text,"payload" text,"status" webhook_delivery_status_enum,"attempts" bigint,"next_attempt_at" timestamptz,"response_status" bigint,"response_body" text,"error" text,"duration" bigint,"delivered_at" timestamptz,PRIMARY KEY ("id"),CONSTRAINT "fk_webhook_deliveries_webhook" FOREIGN KEY ("webhook_id") REFERENCES "webhooks"("id"));
+CREATE INDEX IF NOT EXISTS "idx_webhook_deliveries_event_id" ON "webhook_deliveries" ("event_id");
+CREATE INDEX IF NOT EXISTS "idx_webhook_deliveries_webhook_id" ON "webhook_deliveries" ("webhook_id");
+CREATE INDEX IF NOT EXISTS "idx_webhook_deliveries_deleted_at" ON "webhook_deliveries" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "audit_logs" ("id" uuid DEFAULT uuid_generate_v7(),"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"actor_id" text,"actor_email" text,"actor_role" user_role_enum,"action" audit_action_enum,"entity" text,"entity_id" text,"method" text,"path" text,"route" text,"status_code" bigint,"ip" text,"user_agent" text,"duration" bigint,"before" jsonb,"after" jsonb,"changes" jsonb,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_deleted_at" ON "audit_logs" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_entity_id" ON "audit_logs" ("entity_id");
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_entity" ON "audit_logs" ("entity");
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_actor_id" ON "audit_logs" ("actor_id");
+
+-- ! -------------------------------- analytics ------------------------------- ! --
+CREATE INDEX IF NOT EXISTS idx_orders_created_at ON orders (created_at);
+CREATE INDEX IF NOT EXISTS idx_order_histories_order_status_created_at ON order_histories (order_id, status, created_at);
+CREATE INDEX IF NOT EXISTS idx_order_histories_status_created_at ON order_histories (status, created_at);
+CREATE INDEX IF NOT EXISTS idx_order_meals_order_id ON order_meals (order_id);
+CREATE INDEX IF NOT EXISTS idx_donations_created_at ON donations (created_at);
+CREATE INDEX IF NOT EXISTS idx_members_organization_id ON members (organization_id);
+
+-- ! --------------------------------- search --------------------------------- ! --
+ALTER TABLE meals ADD COLUMN IF NOT EXISTS search_tags text NOT NULL DEFAULT '';
+ALTER TABLE meals ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+	setweight(to_tsvector('simple', coalesce(name, '')), 'A') ||
+	setweight(to_tsvector('simple', search_tags), 'B') ||
+	setweight(to_tsvector('simple', coalesce(description, '')), 'C')
+) STORED;
+CREATE INDEX IF NOT EXISTS idx_meals_search_vector ON meals USING gin (search_vector);
+CREATE INDEX IF NOT EXISTS idx_meals_name_trgm ON meals USING gin (name gin_trgm_ops);
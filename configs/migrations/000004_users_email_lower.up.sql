@@ -0,0 +1,3 @@
+-- * the users are looked up by their email case insensitively, the
+-- * accounts made before the emails were lowercased are kept as they are
+CREATE INDEX IF NOT EXISTS "idx_users_email_lower" ON "users" (LOWER("email"));
@@ -0,0 +1,11 @@
+-- * a member can only keep one caregiver, the one linked first
+ALTER TABLE "members" ADD COLUMN IF NOT EXISTS "caregiver_id" uuid;
+ALTER TABLE "members" DROP CONSTRAINT IF EXISTS "fk_members_caregiver";
+ALTER TABLE "members" ADD CONSTRAINT "fk_members_caregiver" FOREIGN KEY ("caregiver_id") REFERENCES "caregivers"("id");
+
+UPDATE "members" SET "caregiver_id" = (
+	SELECT "caregiver_id" FROM "member_caregivers"
+	WHERE "member_caregivers"."member_id" = "members"."id" AND "member_caregivers"."deleted_at" IS NULL
+	ORDER BY "member_caregivers"."created_at"
+	LIMIT 1
+);
@@ -0,0 +1,13 @@
+-- * members used to have a single caregiver_id column, every existing
+-- * caregiver is moved into the member caregiver link table with full
+-- * access and the old column is dropped afterwards
+DO $$
+BEGIN
+	IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = 'members' AND column_name = 'caregiver_id') THEN
+		INSERT INTO "member_caregivers" ("member_id", "caregiver_id", "can_order", "can_edit_health", "created_at", "updated_at")
+		SELECT "id", "caregiver_id", true, true, NOW(), NOW() FROM "members" WHERE "caregiver_id" IS NOT NULL
+		ON CONFLICT DO NOTHING;
+
+		ALTER TABLE "members" DROP COLUMN "caregiver_id";
+	END IF;
+END $$;
@@ -0,0 +1,16 @@
+-- * only the values that were not re-encrypted yet can be cast back, the
+-- * cast fails on an encrypted value so no value is lost silently
+ALTER TABLE "members"
+	ALTER COLUMN "height" TYPE decimal USING NULLIF("height", '')::decimal,
+	ALTER COLUMN "weight" TYPE decimal USING NULLIF("weight", '')::decimal,
+	ALTER COLUMN "bmi" TYPE decimal(10,2) USING NULLIF("bmi", '')::decimal(10,2),
+	ALTER COLUMN "date_of_birth" TYPE timestamptz USING NULLIF("date_of_birth", '')::timestamptz;
+
+ALTER TABLE "caregivers" ALTER COLUMN "date_of_birth" TYPE timestamptz USING NULLIF("date_of_birth", '')::timestamptz;
+ALTER TABLE "admins" ALTER COLUMN "date_of_birth" TYPE timestamptz USING NULLIF("date_of_birth", '')::timestamptz;
+
+ALTER TABLE "member_illnesses" ALTER COLUMN "illness_id" TYPE uuid USING NULLIF("illness_id", '')::uuid;
+ALTER TABLE "member_illnesses" ADD CONSTRAINT "fk_member_illnesses_illness" FOREIGN KEY ("illness_id") REFERENCES "illnesses"("id");
+
+ALTER TABLE "member_allergies" ALTER COLUMN "allergy_id" TYPE uuid USING NULLIF("allergy_id", '')::uuid;
+ALTER TABLE "member_allergies" ADD CONSTRAINT "fk_member_allergies_allergy" FOREIGN KEY ("allergy_id") REFERENCES "allergies"("id");
@@ -0,0 +1,19 @@
+-- * the databases made before the versioned migrations keep their typed
+-- * health and date of birth columns, the encrypted values are text, the
+-- * values already there are read as they are until they are re-encrypted
+ALTER TABLE "members"
+	ALTER COLUMN "height" TYPE text USING "height"::text,
+	ALTER COLUMN "weight" TYPE text USING "weight"::text,
+	ALTER COLUMN "bmi" TYPE text USING "bmi"::text,
+	ALTER COLUMN "date_of_birth" TYPE text USING "date_of_birth"::text;
+
+ALTER TABLE "caregivers" ALTER COLUMN "date_of_birth" TYPE text USING "date_of_birth"::text;
+ALTER TABLE "admins" ALTER COLUMN "date_of_birth" TYPE text USING "date_of_birth"::text;
+
+-- * the illnesses and allergies of the members are encrypted too, an
+-- * encrypted id can not reference the illness or allergy it points to
+ALTER TABLE "member_illnesses" DROP CONSTRAINT IF EXISTS "fk_member_illnesses_illness";
+ALTER TABLE "member_illnesses" ALTER COLUMN "illness_id" TYPE text USING "illness_id"::text;
+
+ALTER TABLE "member_allergies" DROP CONSTRAINT IF EXISTS "fk_member_allergies_allergy";
+ALTER TABLE "member_allergies" ALTER COLUMN "allergy_id" TYPE text USING "allergy_id"::text;
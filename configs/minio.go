@@ -3,6 +3,7 @@ package configs
 import (
 	"context"
 	"fmt"
+	"project-skbackend/packages/tracing"
 	"project-skbackend/packages/utils/utlogger"
 
 	"github.com/minio/minio-go/v7"
@@ -19,10 +20,17 @@ func (m Minio) Init() (*minio.Client, error) {
 		client          *minio.Client
 	)
 
+	// * every call to minio is run in a span
+	transport, err := minio.DefaultTransport(useSSL)
+	if err != nil {
+		utlogger.Fatal(err)
+	}
+
 	// * initialize minio client object.
 	client, err = minio.New(endpoint, &minio.Options{
-		Creds:  credentials.NewStaticV4(publicAccessKey, secretAccessKey, ""),
-		Secure: useSSL,
+		Creds:     credentials.NewStaticV4(publicAccessKey, secretAccessKey, ""),
+		Secure:    useSSL,
+		Transport: tracing.NewTransport("minio", transport),
 	})
 	if err != nil {
 		utlogger.Fatal(err)
@@ -68,13 +76,13 @@ func (m Minio) CreateBucket(client *minio.Client, ctx context.Context) error {
 		// * check to see if we already own this bucket (which happens if you run this twice)
 		exists, errBucketExists := client.BucketExists(ctx, bucketName)
 		if errBucketExists == nil && exists {
-			utlogger.Info(fmt.Sprintf("We already own %s", bucketName))
+			utlogger.InfoContext(ctx, fmt.Sprintf("We already own %s", bucketName))
 		} else {
 			utlogger.Fatal(err)
 			return err
 		}
 	} else {
-		utlogger.Info(fmt.Sprintf("Successfully created %s", bucketName))
+		utlogger.InfoContext(ctx, fmt.Sprintf("Successfully created %s", bucketName))
 		return err
 	}
 
@@ -27,14 +27,27 @@ func (rmq *Queue) Init() (*amqp.Channel, func()) {
 
 func (rmq *Queue) SetupRabbitMQ(ch *amqp.Channel, cfg Config) {
 	rmq.SetupMailQueue(ch, cfg.Queue)
+	rmq.SetupWebhookQueue(ch, cfg.Queue)
 }
 
 func (rmq *Queue) SetupMailQueue(ch *amqp.Channel, cfg Queue) {
-	xname := cfg.QueueMail.ExchangeName
-	xtype := cfg.QueueMail.ExchangeType
-	qname := cfg.QueueMail.QueueName
-	bkey := cfg.QueueMail.BindingKey
+	setupQueue(ch, cfg.QueueMail.ExchangeName, cfg.QueueMail.ExchangeType, cfg.QueueMail.QueueName, cfg.QueueMail.BindingKey)
+	setupDeadLetterQueue(ch, cfg.QueueDeadLetter.QueueName(cfg.QueueMail.QueueName))
+}
+
+func (rmq *Queue) SetupWebhookQueue(ch *amqp.Channel, cfg Queue) {
+	setupQueue(ch, cfg.QueueWebhook.ExchangeName, cfg.QueueWebhook.ExchangeType, cfg.QueueWebhook.QueueName, cfg.QueueWebhook.BindingKey)
+	setupDeadLetterQueue(ch, cfg.QueueDeadLetter.QueueName(cfg.QueueWebhook.QueueName))
+}
+
+// * the dead letter queue of a queue keeps the messages its consumer
+// * failed on, they are published to it through the default exchange
+// * by the consumer and moved back by the replay-dlq command
+func (dl QueueDeadLetter) QueueName(qname string) string {
+	return qname + dl.Suffix
+}
 
+func setupQueue(ch *amqp.Channel, xname string, xtype string, qname string, bkey string) {
 	// Declare Exchange
 	err := ch.ExchangeDeclare(
 		xname, // name
@@ -67,3 +80,16 @@ func (rmq *Queue) SetupMailQueue(ch *amqp.Channel, cfg Queue) {
 		nil)
 	utlogger.Fatal(err)
 }
+
+func setupDeadLetterQueue(ch *amqp.Channel, qname string) {
+	// Declare Queue
+	_, err := ch.QueueDeclare(
+		qname, // name
+		true,  // durable
+		false, // delete when unused
+		false, // exclusive
+		false, // no-wait
+		nil,   // arguments
+	)
+	utlogger.Fatal(err)
+}
@@ -3,15 +3,14 @@ package configs
 import (
 	"errors"
 	"fmt"
-	"os"
 	"project-skbackend/internal/models"
 	"project-skbackend/internal/models/base"
 	"project-skbackend/packages/consttypes"
 	"project-skbackend/packages/customs/ctdatatype"
+	"slices"
 
 	"project-skbackend/packages/utils/utlogger"
 	"project-skbackend/packages/utils/utstring"
-	"strings"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -33,232 +32,6 @@ func getGlobalHashedPassword(password string) string {
 	return hash
 }
 
-func checkEnumIsExist(db *gorm.DB, key string) bool {
-	var (
-		count int64
-	)
-
-	if err := db.Table("pg_type").Where("typname = ?", key).Count(&count).Error; err != nil {
-		return false
-	}
-
-	return count > 0
-}
-
-func createEnum(db *gorm.DB, enumname string, enumvalues ...any) error {
-	if !checkEnumIsExist(db, enumname) {
-		values := make([]string, len(enumvalues))
-		for i, v := range enumvalues {
-			values[i] = fmt.Sprintf("%v", v)
-		}
-
-		query := fmt.Sprintf("CREATE TYPE %s AS ENUM ('%s');", enumname, strings.Join(values, "','"))
-		err := db.Exec(query).Error
-		if err != nil {
-			utlogger.Error(err)
-			return err
-		}
-	}
-	return nil
-}
-
-func InstallUUIDExtension(db *gorm.DB) error {
-	// Check if the UUID generation function already exists
-	checkFuncSQL := `
-	SELECT EXISTS (
-		SELECT 1 
-		FROM pg_proc 
-		WHERE proname = 'uuid_generate_v7'
-	);
-	`
-
-	var exists bool
-	if err := db.Raw(checkFuncSQL).Scan(&exists).Error; err != nil {
-		utlogger.Error(err)
-		return err
-	}
-
-	// If the function doesn't exist, create it
-	if !exists {
-		uuidsql := `
-		CREATE OR REPLACE FUNCTION uuid_generate_v7()
-		RETURNS uuid AS $$
-		BEGIN
-			-- Use random v4 UUID as starting point (which has the same variant we need)
-			-- Then overlay timestamp
-			-- Finally set version 7 by flipping the 2 and 1 bit in the version 4 string
-			RETURN encode(
-				set_bit(
-					set_bit(
-						overlay(
-							uuid_send(gen_random_uuid())
-							PLACING substring(int8send(floor(extract(epoch FROM clock_timestamp()) * 1000)::bigint) FROM 3)
-							FROM 1 FOR 6
-						),
-						52, 1
-					),
-					53, 1
-				),
-				'hex'
-			)::uuid;
-		END
-		$$ LANGUAGE plpgsql VOLATILE;
-		`
-
-		if err := db.Exec(uuidsql).Error; err != nil {
-			utlogger.Error(err)
-			return err
-		}
-	}
-
-	// Create the UUID-OSSP extension if it doesn't exist
-	if err := db.Exec("CREATE EXTENSION IF NOT EXISTS \"uuid-ossp\";").Error; err != nil {
-		utlogger.Error(err)
-		return err
-	}
-
-	return nil
-}
-
-func SeedAllergensEnum(db *gorm.DB) error {
-	return createEnum(db,
-		"allergens_enum",
-		consttypes.A_FOOD.String(),
-		consttypes.A_MEDICAL.String(),
-		consttypes.A_ENVIRONMENTAL.String(),
-		consttypes.A_CONTACT.String(),
-	)
-}
-
-func SeedUserRoleEnum(db *gorm.DB) error {
-	return createEnum(db,
-		"user_role_enum",
-		consttypes.UR_ADMIN.Uint(),
-		consttypes.UR_CAREGIVER.Uint(),
-		consttypes.UR_MEMBER.Uint(),
-		consttypes.UR_ORGANIZATION.Uint(),
-		consttypes.UR_PARTNER.Uint(),
-		consttypes.UR_PATRON.Uint(),
-		consttypes.UR_USER.Uint(),
-	)
-}
-
-func SeedGenderEnum(db *gorm.DB) error {
-	return createEnum(db,
-		"gender_enum",
-		consttypes.G_MALE.String(),
-		consttypes.G_FEMALE.String(),
-		consttypes.G_OTHER.String(),
-	)
-}
-
-func SeedMealStatusEnum(db *gorm.DB) error {
-	return createEnum(db,
-		"meal_status_enum",
-		consttypes.MS_ACTIVE.String(),
-		consttypes.MS_INACTIVE.String(),
-		consttypes.MS_OUTOFSTOCK.String(),
-	)
-}
-
-func SeedDonationStatusEnum(db *gorm.DB) error {
-	return createEnum(db,
-		"donation_status_enum",
-		consttypes.DS_ACCEPTED.String(),
-		consttypes.DS_REJECTED.String(),
-		consttypes.DS_PENDING.String(),
-	)
-}
-
-func SeedImageTypeEnum(db *gorm.DB) error {
-	return createEnum(db,
-		"image_type_enum",
-		consttypes.IT_PROFILE.String(),
-		consttypes.IT_MEAL.String(),
-		consttypes.IT_MEAL_CATEGORY.String(),
-		consttypes.IT_DONATION_PROOF.String(),
-	)
-}
-
-func SeedPatronTypeEnum(db *gorm.DB) error {
-	return createEnum(db,
-		"patron_type_enum",
-		consttypes.PT_ORGANIZATION.String(),
-		consttypes.PT_PERSONAL.String(),
-	)
-}
-
-func SeedOrganizationTypeEnum(db *gorm.DB) error {
-	return createEnum(db,
-		"organization_type_enum",
-		consttypes.OT_NURSINGHOME.String(),
-	)
-}
-
-func SeedOrderStatusEnum(db *gorm.DB) error {
-	return createEnum(db,
-		"order_status_enum",
-		consttypes.OS_PLACED.String(),
-		consttypes.OS_CONFIRMED.String(),
-		consttypes.OS_BEING_PREPARED.String(),
-		consttypes.OS_PREPARED.String(),
-		consttypes.OS_PICKED_UP.String(),
-		consttypes.OS_COMPLETED.String(),
-		consttypes.OS_CANCELLED.String(),
-	)
-}
-
-func SeedAdminCredentials(db *gorm.DB) error {
-	if db.Migrator().HasTable(&models.User{}) && db.Migrator().HasTable(&models.Admin{}) {
-		if err := db.First(&models.Admin{}).Error; errors.Is(err, gorm.ErrRecordNotFound) {
-			admins := []*models.Admin{
-				{
-					Model: base.Model{ID: id},
-					User: models.User{
-						Image: &models.UserImage{
-							Image: models.Image{
-								Name: "Profile Image",
-								Path: "https://meals-minio-api.103.127.137.58.nip.io/meals-bucket/image/jpeg-01918055-96aa-7a39-bcea-6f7fd1440165.jpg",
-								Type: consttypes.IT_PROFILE,
-							},
-						},
-						ConfirmedAt: consttypes.TimeNow(),
-						Email:       os.Getenv("ADMIN_EMAIL"),
-						Password:    getGlobalHashedPassword(os.Getenv("ADMIN_PASSWORD")),
-						Role:        consttypes.UR_ADMIN,
-						Addresses: []*models.Address{
-							{
-								Name:    "Indian Ocean Address",
-								Address: "Indian Ocean Address, 1st floor,",
-								Note:    "Ocean with the blue water",
-								AddressDetail: &models.AddressDetail{
-									Geolocation: models.Geolocation{
-										Longitude: "-26.10305",
-										Latitude:  "56.91996",
-									},
-									FormattedAddress: "Indian Ocean",
-								},
-							},
-						},
-					},
-					FirstName:   os.Getenv("ADMIN_FIRSTNAME"),
-					LastName:    os.Getenv("ADMIN_LASTNAME"),
-					Gender:      consttypes.G_MALE,
-					DateOfBirth: ctdatatype.CDT_DATE{Time: consttypes.TimeNow()},
-				},
-			}
-
-			err := db.Session(&gorm.Session{FullSaveAssociations: true}).Create(&admins).Error
-			if err != nil {
-				utlogger.Error(err)
-				return err
-			}
-		}
-	}
-
-	return nil
-}
-
 func SeedPatronCredentials(db *gorm.DB) error {
 	if db.Migrator().HasTable(&models.User{}) && db.Migrator().HasTable(&models.Patron{}) {
 		if err := db.First(&models.Patron{}).Error; errors.Is(err, gorm.ErrRecordNotFound) {
@@ -362,40 +135,46 @@ func SeedMemberCredentials(db *gorm.DB) error {
 					Weight:      60.0,
 					Gender:      consttypes.G_MALE,
 					DateOfBirth: ctdatatype.CDT_DATE{Time: consttypes.TimeNow()},
-					Caregiver: &models.Caregiver{
-						Model: base.Model{ID: id},
-						User: models.User{
-							Image: &models.UserImage{
-								Image: models.Image{
-									Name: "Profile Image",
-									Path: "https://meals-minio-api.103.127.137.58.nip.io/meals-bucket/image/jpeg-01918055-96aa-7a39-bcea-6f7fd1440165.jpg",
-									Type: consttypes.IT_PROFILE,
-								},
-							},
-							ConfirmedAt: consttypes.TimeNow(),
-							Email:       "caregiver@test.com",
-							Password:    getGlobalHashedPassword("password"),
-							Role:        consttypes.UR_CAREGIVER,
-							Addresses: []*models.Address{
-								{
-									Name:    "Home Address",
-									Address: "Elm Avenue",
-									Note:    "Corner house with a white picket fence.",
-									AddressDetail: &models.AddressDetail{
-										Geolocation: models.Geolocation{
-											Longitude: "58.70232",
-											Latitude:  "62.81751",
+					Caregivers: []*models.MemberCaregiver{
+						{
+							CanOrder:      true,
+							CanEditHealth: true,
+							Caregiver: models.Caregiver{
+								Model: base.Model{ID: id},
+								User: models.User{
+									Image: &models.UserImage{
+										Image: models.Image{
+											Name: "Profile Image",
+											Path: "https://meals-minio-api.103.127.137.58.nip.io/meals-bucket/image/jpeg-01918055-96aa-7a39-bcea-6f7fd1440165.jpg",
+											Type: consttypes.IT_PROFILE,
+										},
+									},
+									ConfirmedAt: consttypes.TimeNow(),
+									Email:       "caregiver@test.com",
+									Password:    getGlobalHashedPassword("password"),
+									Role:        consttypes.UR_CAREGIVER,
+									Addresses: []*models.Address{
+										{
+											Name:    "Home Address",
+											Address: "Elm Avenue",
+											Note:    "Corner house with a white picket fence.",
+											AddressDetail: &models.AddressDetail{
+												Geolocation: models.Geolocation{
+													Longitude: "58.70232",
+													Latitude:  "62.81751",
+												},
+												FormattedAddress: "Alapayevskiy Rayon, Sverdlovsk Oblast, Russia",
+												Country:          "Russia",
+											},
 										},
-										FormattedAddress: "Alapayevskiy Rayon, Sverdlovsk Oblast, Russia",
-										Country:          "Russia",
 									},
 								},
+								FirstName:   "Care",
+								LastName:    "Giver",
+								Gender:      consttypes.G_FEMALE,
+								DateOfBirth: ctdatatype.CDT_DATE{Time: consttypes.TimeNow()},
 							},
 						},
-						FirstName:   "Care",
-						LastName:    "Giver",
-						Gender:      consttypes.G_FEMALE,
-						DateOfBirth: ctdatatype.CDT_DATE{Time: consttypes.TimeNow()},
 					},
 				},
 			}
@@ -1464,3 +1243,90 @@ func SeedCartData(db *gorm.DB) error {
 
 	return nil
 }
+
+func SeedRolePermissionData(db *gorm.DB) error {
+	if db.Migrator().HasTable(&models.RolePermission{}) && db.Migrator().HasTable(&models.RolePermissionSeed{}) {
+		var (
+			seeded []*models.RolePermissionSeed
+			rps    []*models.RolePermission
+			rpss   []*models.RolePermissionSeed
+		)
+
+		if err := db.Find(&seeded).Error; err != nil {
+			utlogger.Error(err)
+			return err
+		}
+
+		// * only seed the default grants that were never seeded, so the
+		// * grants removed by admins stay removed while new grants, also of
+		// * a permission other roles already have, are still seeded
+		for role, perms := range consttypes.DefaultRolePermissions() {
+			for _, perm := range perms {
+				if slices.ContainsFunc(seeded, func(rps *models.RolePermissionSeed) bool {
+					return rps.Role == role && rps.Permission == perm
+				}) {
+					continue
+				}
+
+				rps = append(rps, models.NewRolePermission(role, perm))
+				rpss = append(rpss, &models.RolePermissionSeed{Role: role, Permission: perm})
+			}
+		}
+
+		if len(rps) == 0 {
+			return nil
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&rps).Error; err != nil {
+				return err
+			}
+
+			return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&rpss).Error
+		})
+
+		if err != nil {
+			utlogger.Error(err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// * record the current health data of members that were created
+// * before the health history existed as their first version
+func SeedMemberHealthHistoryData(db *gorm.DB) error {
+	if db.Migrator().HasTable(&models.MemberHealthHistory{}) {
+		var (
+			members []*models.Member
+			mhhs    []*models.MemberHealthHistory
+		)
+
+		err := db.
+			Preload("Illnesses.Illness").
+			Preload("Allergies.Allergy").
+			Where("id NOT IN (?)", db.Model(&models.MemberHealthHistory{}).Select("member_id")).
+			Find(&members).Error
+		if err != nil {
+			utlogger.Error(err)
+			return err
+		}
+
+		for _, member := range members {
+			mhhs = append(mhhs, models.NewMemberHealthHistory(*member, 1, nil, nil))
+		}
+
+		if len(mhhs) == 0 {
+			return nil
+		}
+
+		err = db.Clauses(clause.OnConflict{DoNothing: true}).Create(&mhhs).Error
+		if err != nil {
+			utlogger.Error(err)
+			return err
+		}
+	}
+
+	return nil
+}
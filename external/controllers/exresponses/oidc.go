@@ -0,0 +1,13 @@
+package exresponses
+
+type (
+	OIDCClaims struct {
+		Issuer        string `json:"iss"`
+		Subject       string `json:"sub"`
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+		Name          string `json:"name"`
+		GivenName     string `json:"given_name"`
+		FamilyName    string `json:"family_name"`
+	}
+)
@@ -1,6 +1,7 @@
 package distancematrixservice
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -8,6 +9,7 @@ import (
 	"project-skbackend/external/controllers/exrequests"
 	"project-skbackend/external/controllers/exresponses"
 	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/tracing"
 	"project-skbackend/packages/utils/utlogger"
 	"time"
 )
@@ -21,8 +23,8 @@ type (
 	}
 
 	IDistanceMatrixService interface {
-		GetGeocoding(loc exrequests.Geolocation) (*exresponses.Geocode, error)
-		GetDistanceMatrix(dismat exrequests.DistanceMatrix) (*exresponses.DistanceMatrix, error)
+		GetGeocoding(ctx context.Context, loc exrequests.Geolocation) (*exresponses.Geocode, error)
+		GetDistanceMatrix(ctx context.Context, dismat exrequests.DistanceMatrix) (*exresponses.DistanceMatrix, error)
 	}
 )
 
@@ -34,28 +36,29 @@ func NewDistanceMatrixService(
 		url:    cfg.DistanceMatrix.BaseURL,
 
 		httpclient: &http.Client{
-			Timeout: time.Second * time.Duration(cfg.DistanceMatrix.Timeout), // Example: Timeout after 10 seconds
+			Transport: tracing.NewTransport("distancematrix", http.DefaultTransport),
+			Timeout:   time.Second * time.Duration(cfg.DistanceMatrix.Timeout), // Example: Timeout after 10 seconds
 		},
 	}
 }
 
-func (s *DistanceMatrixService) GetGeocoding(loc exrequests.Geolocation) (*exresponses.Geocode, error) {
+func (s *DistanceMatrixService) GetGeocoding(ctx context.Context, loc exrequests.Geolocation) (*exresponses.Geocode, error) {
 	url := fmt.Sprintf(
 		"%sgeocode/json?address=%s, %s&key=%s",
 		s.url, loc.Lat, loc.Lng, s.apikey,
 	)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		utlogger.Error(err)
-		return nil, consttypes.ErrFailedToDeclareNewRequest
+		utlogger.ErrorContext(ctx, err)
+		return nil, consttypes.ErrFailedToDeclareNewRequest.Wrap(err)
 	}
 
 	req.Header.Set(consttypes.T_ACCESS, "Bearer "+s.apikey)
 	resp, err := s.httpclient.Do(req)
 	if err != nil {
-		utlogger.Error(err)
-		return nil, consttypes.ErrFailedToCallExternalAPI
+		utlogger.ErrorContext(ctx, err)
+		return nil, consttypes.ErrFailedToCallExternalAPI.Wrap(err)
 	}
 	defer resp.Body.Close()
 
@@ -65,36 +68,36 @@ func (s *DistanceMatrixService) GetGeocoding(loc exrequests.Geolocation) (*exres
 
 	var res *exresponses.Geocode
 	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	// * return nil if the status is not OK
 	if res.Status != consttypes.DMS_OK.String() {
-		utlogger.Info("error reference data: ", res)
+		utlogger.InfoContext(ctx, "error reference data: ", res)
 		return nil, consttypes.ErrInvalidGeolocation
 	}
 
 	return res, nil
 }
 
-func (s *DistanceMatrixService) GetDistanceMatrix(dismat exrequests.DistanceMatrix) (*exresponses.DistanceMatrix, error) {
+func (s *DistanceMatrixService) GetDistanceMatrix(ctx context.Context, dismat exrequests.DistanceMatrix) (*exresponses.DistanceMatrix, error) {
 	url := fmt.Sprintf(
 		"%sdistancematrix/json?origins=%s, %s&destinations=%s, %s&key=%s",
 		s.url, dismat.Origins.Lat, dismat.Origins.Lng, dismat.Destinations.Lat, dismat.Destinations.Lng, s.apikey,
 	)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		utlogger.Error(err)
-		return nil, consttypes.ErrFailedToDeclareNewRequest
+		utlogger.ErrorContext(ctx, err)
+		return nil, consttypes.ErrFailedToDeclareNewRequest.Wrap(err)
 	}
 
 	req.Header.Set(consttypes.T_ACCESS, "Bearer "+s.apikey)
 	resp, err := s.httpclient.Do(req)
 	if err != nil {
-		utlogger.Error(err)
-		return nil, consttypes.ErrFailedToCallExternalAPI
+		utlogger.ErrorContext(ctx, err)
+		return nil, consttypes.ErrFailedToCallExternalAPI.Wrap(err)
 	}
 	defer resp.Body.Close()
 
@@ -104,13 +107,13 @@ func (s *DistanceMatrixService) GetDistanceMatrix(dismat exrequests.DistanceMatr
 
 	var res *exresponses.DistanceMatrix
 	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	// * return nil if the status is not OK
 	if res.Rows[0].Elements[0].Status != consttypes.DMS_OK.String() || res.Status != consttypes.DMS_OK.String() {
-		utlogger.Info("error reference data: ", res)
+		utlogger.InfoContext(ctx, "error reference data: ", res)
 		return nil, consttypes.ErrInvalidDistanceMatrix
 	}
 
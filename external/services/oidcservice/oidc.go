@@ -0,0 +1,129 @@
+package oidcservice
+
+import (
+	"context"
+	"net/http"
+	"project-skbackend/configs"
+	"project-skbackend/external/controllers/exresponses"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/utils/utlogger"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+type (
+	OIDCService struct {
+		cfg *configs.Config
+
+		httpclient *http.Client
+
+		// * the provider is discovered on the first use so the api
+		// * still starts when the issuer is not reachable
+		mu       sync.Mutex
+		provider *oidc.Provider
+	}
+
+	IOIDCService interface {
+		AuthCodeURL(ctx context.Context, state string, nonce string, verifier string) (string, error)
+		Exchange(ctx context.Context, code string, nonce string, verifier string) (*exresponses.OIDCClaims, error)
+	}
+)
+
+func NewOIDCService(
+	cfg *configs.Config,
+) *OIDCService {
+	return &OIDCService{
+		cfg: cfg,
+
+		httpclient: &http.Client{
+			Timeout: time.Second * time.Duration(cfg.OIDC.Timeout),
+		},
+	}
+}
+
+func (s *OIDCService) AuthCodeURL(ctx context.Context, state string, nonce string, verifier string) (string, error) {
+	oauthcfg, _, err := s.config(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return oauthcfg.AuthCodeURL(
+		state,
+		oidc.Nonce(nonce),
+		oauth2.S256ChallengeOption(verifier),
+	), nil
+}
+
+// * exchanges the authorization code and verifies the signature, audience,
+// * expiry and nonce of the returned id token before reading its claims
+func (s *OIDCService) Exchange(ctx context.Context, code string, nonce string, verifier string) (*exresponses.OIDCClaims, error) {
+	var (
+		claims exresponses.OIDCClaims
+	)
+
+	oauthcfg, provider, err := s.config(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = oidc.ClientContext(ctx, s.httpclient)
+
+	token, err := oauthcfg.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, consttypes.ErrOIDCExchangeFailed.Wrap(err)
+	}
+
+	rawidtoken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, consttypes.ErrOIDCTokenInvalid
+	}
+
+	idtoken, err := provider.
+		Verifier(&oidc.Config{ClientID: s.cfg.OIDC.ClientID}).
+		Verify(ctx, rawidtoken)
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, consttypes.ErrOIDCTokenInvalid.Wrap(err)
+	}
+
+	if idtoken.Nonce != nonce {
+		return nil, consttypes.ErrOIDCTokenInvalid
+	}
+
+	if err := idtoken.Claims(&claims); err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, consttypes.ErrOIDCTokenInvalid.Wrap(err)
+	}
+
+	claims.Issuer = idtoken.Issuer
+	claims.Subject = idtoken.Subject
+
+	return &claims, nil
+}
+
+func (s *OIDCService) config(ctx context.Context) (*oauth2.Config, *oidc.Provider, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.provider == nil {
+		provider, err := oidc.NewProvider(oidc.ClientContext(ctx, s.httpclient), s.cfg.OIDC.Issuer)
+		if err != nil {
+			utlogger.ErrorContext(ctx, err)
+			return nil, nil, consttypes.ErrOIDCProviderUnavailable.Wrap(err)
+		}
+
+		s.provider = provider
+	}
+
+	return &oauth2.Config{
+		ClientID:     s.cfg.OIDC.ClientID,
+		ClientSecret: s.cfg.OIDC.ClientSecret,
+		RedirectURL:  s.cfg.OIDC.RedirectURL,
+		Endpoint:     s.provider.Endpoint(),
+		Scopes:       s.cfg.OIDC.Scopes,
+	}, s.provider, nil
+}
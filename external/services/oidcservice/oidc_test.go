@@ -0,0 +1,97 @@
+package oidcservice
+
+import (
+	"context"
+	"errors"
+	"project-skbackend/configs"
+	"project-skbackend/external/services/oidcservice/oidctest"
+	"project-skbackend/packages/consttypes"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+func newTestService(t *testing.T) (*OIDCService, *oidctest.Issuer) {
+	iss := oidctest.NewIssuer(t, "client")
+
+	cfg := &configs.Config{}
+	cfg.OIDC.Issuer = iss.URL
+	cfg.OIDC.ClientID = "client"
+	cfg.OIDC.ClientSecret = "secret"
+	cfg.OIDC.RedirectURL = "http://localhost/callback"
+	cfg.OIDC.Scopes = []string{"openid", "email"}
+	cfg.OIDC.Timeout = 5
+
+	return NewOIDCService(cfg), iss
+}
+
+func TestExchange(t *testing.T) {
+	var (
+		ctx      = context.Background()
+		verifier = oauth2.GenerateVerifier()
+	)
+
+	s, iss := newTestService(t)
+
+	authurl, err := s.AuthCodeURL(ctx, "state", "nonce", verifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	code := iss.Grant(t, authurl, oidctest.Claims{Subject: "sub", Email: "Someone@Example.com", EmailVerified: true})
+
+	claims, err := s.Exchange(ctx, code, "nonce", verifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if claims.Issuer != iss.URL || claims.Subject != "sub" || claims.Email != "Someone@Example.com" || !claims.EmailVerified {
+		t.Fatalf("claims = %+v", claims)
+	}
+
+	// * a code can only be exchanged once
+	if _, err := s.Exchange(ctx, code, "nonce", verifier); !errors.Is(err, consttypes.ErrOIDCExchangeFailed) {
+		t.Fatalf("second exchange err = %v, want %v", err, consttypes.ErrOIDCExchangeFailed)
+	}
+}
+
+func TestExchangeRejectsAnotherVerifier(t *testing.T) {
+	var (
+		ctx = context.Background()
+	)
+
+	s, iss := newTestService(t)
+
+	authurl, err := s.AuthCodeURL(ctx, "state", "nonce", oauth2.GenerateVerifier())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	code := iss.Grant(t, authurl, oidctest.Claims{Subject: "sub"})
+
+	_, err = s.Exchange(ctx, code, "nonce", oauth2.GenerateVerifier())
+	if !errors.Is(err, consttypes.ErrOIDCExchangeFailed) {
+		t.Fatalf("err = %v, want %v", err, consttypes.ErrOIDCExchangeFailed)
+	}
+}
+
+func TestExchangeRejectsAnotherNonce(t *testing.T) {
+	var (
+		ctx      = context.Background()
+		verifier = oauth2.GenerateVerifier()
+	)
+
+	s, iss := newTestService(t)
+
+	authurl, err := s.AuthCodeURL(ctx, "state", "nonce", verifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	code := iss.Grant(t, authurl, oidctest.Claims{Subject: "sub", Nonce: "replayed"})
+
+	_, err = s.Exchange(ctx, code, "nonce", verifier)
+	if !errors.Is(err, consttypes.ErrOIDCTokenInvalid) {
+		t.Fatalf("err = %v, want %v", err, consttypes.ErrOIDCTokenInvalid)
+	}
+}
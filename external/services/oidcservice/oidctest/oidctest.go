@@ -0,0 +1,225 @@
+// * a mock openid connect issuer for the tests, it serves the discovery
+// * document, the signing keys and the token endpoint of a provider
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/google/uuid"
+)
+
+const (
+	keyID = "oidctest"
+)
+
+type (
+	// * the claims of the id token the issuer gives for a code
+	Claims struct {
+		Subject       string
+		Email         string
+		EmailVerified bool
+		GivenName     string
+		FamilyName    string
+
+		// * overrides the nonce of the authorization request when set
+		Nonce string
+	}
+
+	Issuer struct {
+		*httptest.Server
+
+		ClientID string
+
+		key *rsa.PrivateKey
+
+		mu     sync.Mutex
+		grants map[string]grant
+	}
+
+	grant struct {
+		claims    Claims
+		nonce     string
+		challenge string
+	}
+)
+
+func NewIssuer(t *testing.T, clientID string) *Issuer {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	iss := &Issuer{
+		ClientID: clientID,
+		key:      key,
+		grants:   map[string]grant{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", iss.discovery)
+	mux.HandleFunc("GET /keys", iss.keys)
+	mux.HandleFunc("POST /token", iss.token)
+
+	iss.Server = httptest.NewServer(mux)
+	t.Cleanup(iss.Close)
+
+	return iss
+}
+
+// * signs the user in at the authorization url the service made, the
+// * returned code is bound to the nonce and the pkce challenge of it
+func (iss *Issuer) Grant(t *testing.T, authurl string, claims Claims) string {
+	t.Helper()
+
+	u, err := url.Parse(authurl)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	q := u.Query()
+	if q.Get("client_id") != iss.ClientID {
+		t.Fatalf("client_id = %q, want %q", q.Get("client_id"), iss.ClientID)
+	}
+
+	if q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "" {
+		t.Fatalf("authorization url %s has no S256 pkce challenge", authurl)
+	}
+
+	if q.Get("nonce") == "" || q.Get("state") == "" {
+		t.Fatalf("authorization url %s has no nonce or state", authurl)
+	}
+
+	nonce := q.Get("nonce")
+	if claims.Nonce != "" {
+		nonce = claims.Nonce
+	}
+
+	code := uuid.NewString()
+
+	iss.mu.Lock()
+	iss.grants[code] = grant{
+		claims:    claims,
+		nonce:     nonce,
+		challenge: q.Get("code_challenge"),
+	}
+	iss.mu.Unlock()
+
+	return code
+}
+
+func (iss *Issuer) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"issuer":                                iss.URL,
+		"authorization_endpoint":                iss.URL + "/authorize",
+		"token_endpoint":                        iss.URL + "/token",
+		"jwks_uri":                              iss.URL + "/keys",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+	})
+}
+
+func (iss *Issuer) keys(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, jose.JSONWebKeySet{
+		Keys: []jose.JSONWebKey{{
+			Key:       &iss.key.PublicKey,
+			KeyID:     keyID,
+			Algorithm: string(jose.RS256),
+			Use:       "sig",
+		}},
+	})
+}
+
+// * a code can only be exchanged once and only with the verifier of the
+// * challenge it was granted with
+func (iss *Issuer) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	code := r.PostForm.Get("code")
+
+	iss.mu.Lock()
+	g, ok := iss.grants[code]
+	delete(iss.grants, code)
+	iss.mu.Unlock()
+
+	if !ok {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(sum[:]) != g.challenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	idtoken, err := iss.sign(g)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": uuid.NewString(),
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     idtoken,
+	})
+}
+
+func (iss *Issuer) sign(g grant) (string, error) {
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.RS256, Key: iss.key},
+		(&jose.SignerOptions{}).WithHeader("kid", keyID),
+	)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+
+	payload, err := json.Marshal(map[string]any{
+		"iss":            iss.URL,
+		"sub":            g.claims.Subject,
+		"aud":            iss.ClientID,
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Hour).Unix(),
+		"nonce":          g.nonce,
+		"email":          g.claims.Email,
+		"email_verified": g.claims.EmailVerified,
+		"given_name":     g.claims.GivenName,
+		"family_name":    g.claims.FamilyName,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	jws, err := signer.Sign(payload)
+	if err != nil {
+		return "", err
+	}
+
+	return jws.CompactSerialize()
+}
+
+func writeJSON(w http.ResponseWriter, status int, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(data)
+}
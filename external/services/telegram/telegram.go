@@ -6,6 +6,7 @@ import (
 	"net/url"
 	"os"
 	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/tracing"
 	"strconv"
 	"sync"
 	"time"
@@ -39,7 +40,8 @@ func NewTelegramService() *TelegramService {
 		tochatid: os.Getenv("TG_TO_CHAT_ID"),
 
 		httpclient: &http.Client{
-			Timeout: time.Second * time.Duration(timeoutint), // Example: Timeout after 10 seconds
+			Transport: tracing.NewTransport("telegram", http.DefaultTransport),
+			Timeout:   time.Second * time.Duration(timeoutint), // Example: Timeout after 10 seconds
 		},
 	}
 }
@@ -0,0 +1,119 @@
+package apps
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/mail"
+	"os"
+	"project-skbackend/configs"
+	"project-skbackend/internal/models"
+	"project-skbackend/internal/repositories/adminrepo"
+	"project-skbackend/internal/repositories/userrepo"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/customs/ctdatatype"
+	"project-skbackend/packages/utils/utlogger"
+	"project-skbackend/packages/utils/utstring"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	errCreateAdminUsage = errors.New("usage: create-admin -email <email> -first-name <name> -last-name <name> -date-of-birth <yyyy-mm-dd> [-gender <gender>] [-password <password>]")
+)
+
+// * CreateAdmin creates a confirmed admin, the password is read from the
+// * standard input when it is not given so it is not kept in the history
+func CreateAdmin(cfg *configs.Config, args []string) {
+	fset := flag.NewFlagSet("create-admin", flag.ExitOnError)
+	email := fset.String("email", "", "the email of the admin")
+	password := fset.String("password", "", "the password of the admin, read from the standard input when empty")
+	firstname := fset.String("first-name", "", "the first name of the admin")
+	lastname := fset.String("last-name", "", "the last name of the admin")
+	gender := fset.String("gender", consttypes.G_OTHER.String(), "the gender of the admin, Male, Female or Other")
+	dob := fset.String("date-of-birth", "", "the date of birth of the admin as yyyy-mm-dd")
+	_ = fset.Parse(args)
+
+	if *email == "" || *firstname == "" || *lastname == "" || *dob == "" {
+		utlogger.Fatal(errCreateAdminUsage)
+	}
+
+	if _, err := mail.ParseAddress(*email); err != nil {
+		utlogger.Fatal(fmt.Errorf("invalid email: %w", err))
+	}
+
+	g := consttypes.Gender(*gender)
+	if g != consttypes.G_MALE && g != consttypes.G_FEMALE && g != consttypes.G_OTHER {
+		utlogger.Fatal(fmt.Errorf("invalid gender: %s", *gender))
+	}
+
+	birth, err := time.Parse(consttypes.DATEFORMAT, *dob)
+	if err != nil {
+		utlogger.Fatal(fmt.Errorf("invalid date of birth: %w", err))
+	}
+
+	if *password == "" {
+		fmt.Print("Password: ")
+
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			utlogger.Fatal(err)
+		}
+
+		*password = strings.TrimRight(line, "\r\n")
+	}
+
+	if *password == "" {
+		utlogger.Fatal(errCreateAdminUsage)
+	}
+
+	// * the date of birth is an encrypted field
+	err = cfg.Encryption.InitEncryption()
+	if err != nil {
+		utlogger.Fatal(err)
+	}
+
+	gdb, err := cfg.DB.Connect()
+	if err != nil {
+		utlogger.Fatal(err)
+	}
+
+	ruser := userrepo.NewUserRepository(gdb)
+	radmn := adminrepo.NewAdminRepository(gdb)
+
+	_, err = ruser.GetByEmail(context.Background(), *email)
+	if err == nil {
+		utlogger.Fatal(consttypes.ErrUserAlreadyExist)
+	}
+
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		utlogger.Fatal(err)
+	}
+
+	hash, err := utstring.HashPassword(*password)
+	if err != nil {
+		utlogger.Fatal(err)
+	}
+
+	admin, err := radmn.Create(context.Background(), models.Admin{
+		User: models.User{
+			Email:       *email,
+			Password:    hash,
+			Role:        consttypes.UR_ADMIN,
+			ConfirmedAt: consttypes.TimeNow(),
+		},
+		FirstName:   *firstname,
+		LastName:    *lastname,
+		Gender:      g,
+		DateOfBirth: ctdatatype.CDT_DATE{Time: birth},
+	})
+	if err != nil {
+		utlogger.Fatal(err)
+	}
+
+	fmt.Printf("created admin %s with user %s\n", admin.ID, admin.UserID)
+}
@@ -2,19 +2,28 @@ package apps
 
 import (
 	"context"
+	"flag"
 	"os"
 	"os/signal"
 	"project-skbackend/configs"
 	v1 "project-skbackend/internal/controllers/http/v1"
 	"project-skbackend/internal/di"
+	"project-skbackend/packages/lifecycle"
 	"project-skbackend/packages/servers"
 	"project-skbackend/packages/utils/utlogger"
 	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-func Run(cfg *configs.Config) {
+// * Serve runs the http server, the worker runs on its own through the
+// * worker command unless it is enabled here, e.g. for a single instance
+func Serve(cfg *configs.Config, args []string) {
+	fset := flag.NewFlagSet("serve", flag.ExitOnError)
+	worker := fset.Bool("worker", false, "run the queue consumers and the cron in the same process")
+	_ = fset.Parse(args)
+
 	// * setup context
 	ctx := context.Background()
 
@@ -25,36 +34,86 @@ func Run(cfg *configs.Config) {
 		utlogger.Fatal(err)
 	}
 
-	// * close the init after finished
-	defer i.Close()
-
 	// * setup new dependency injection
 	di := di.NewDependencyInjection(ctx, i.GormDB, i.Channel, cfg, i.RedisDB, i.Minio)
 
-	// * setup consumer
-	di.InitServices()
+	lm := newLifecycleManager(cfg)
 
-	var forever chan struct{}
+	// * setup consumer and cron
+	if *worker {
+		lm.Add(di.WorkerComponents()...)
+	}
 
-	// * HTTP Server
+	// * HTTP Server, started last so it only takes requests once the rest
+	// * runs and stopped first so the requests are drained before the rest
 	handler := gin.New()
-	v1.NewRouter(handler, i.GormDB, cfg, di, i.RedisDB)
-	server := servers.NewServer(handler, servers.Port(cfg.HTTP.Port))
+	v1.NewRouter(handler, i.GormDB, cfg, di, i.RedisDB, lm)
+	lm.Add(servers.NewServer(
+		handler,
+		servers.Port(cfg.HTTP.Port),
+		servers.ShutdownTimeout(time.Duration(cfg.App.ShutdownTimeout)*time.Second),
+	))
+
+	// * the connections are closed once every component is stopped
+	err = run(lm)
+	i.Close()
+
+	if err != nil {
+		utlogger.Fatal(err)
+	}
+}
 
-	interrupt := make(chan os.Signal, 1)
-	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+// * Worker runs the queue consumers and the cron without the http server
+func Worker(cfg *configs.Config, args []string) {
+	fset := flag.NewFlagSet("worker", flag.ExitOnError)
+	_ = fset.Parse(args)
 
-	select {
-	case s := <-interrupt:
-		utlogger.Info("app run: " + s.String())
-	case err := <-server.Notify():
+	// * setup context
+	ctx := context.Background()
+
+	// * init config
+	i := configs.NewInitConfig(ctx, *cfg)
+	i, err := i.InitConfig()
+	if err != nil {
 		utlogger.Fatal(err)
 	}
 
-	err = server.Shutdown()
+	// * setup new dependency injection
+	di := di.NewDependencyInjection(ctx, i.GormDB, i.Channel, cfg, i.RedisDB, i.Minio)
+
+	lm := newLifecycleManager(cfg)
+
+	// * setup consumer and cron
+	lm.Add(di.WorkerComponents()...)
+
+	// * the worker serves only the health routes, for its probes
+	handler := gin.New()
+	v1.NewHealthRouter(handler, cfg, di.HealthService, lm)
+	lm.Add(servers.NewServer(
+		handler,
+		servers.Port(cfg.Health.WorkerPort),
+		servers.ShutdownTimeout(time.Duration(cfg.App.ShutdownTimeout)*time.Second),
+	))
+
+	// * the connections are closed once every component is stopped
+	err = run(lm)
+	i.Close()
+
 	if err != nil {
 		utlogger.Fatal(err)
 	}
+}
+
+func newLifecycleManager(cfg *configs.Config) *lifecycle.Manager {
+	return lifecycle.NewManager(
+		lifecycle.ShutdownTimeout(time.Duration(cfg.App.ShutdownTimeout) * time.Second),
+	)
+}
+
+// * runs the components until a signal or until one of them fails
+func run(lm *lifecycle.Manager) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	<-forever
+	return lm.Run(ctx)
 }
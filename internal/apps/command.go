@@ -0,0 +1,63 @@
+package apps
+
+import (
+	"errors"
+	"log/slog"
+	"project-skbackend/configs"
+	"project-skbackend/packages/utils/utlogger"
+
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	command struct {
+		name  string
+		usage string
+		run   func(cfg *configs.Config, args []string)
+	}
+)
+
+var (
+	commands = []command{
+		{name: "serve", usage: "serve [-worker]", run: Serve},
+		{name: "worker", usage: "worker", run: Worker},
+		{name: "migrate", usage: "migrate up [steps] | down [steps] | status | create <name>", run: Migrate},
+		{name: "seed", usage: "seed [-list] [seeder...]", run: Seed},
+		{name: "create-admin", usage: "create-admin -email <email> -first-name <name> -last-name <name> -date-of-birth <yyyy-mm-dd> [-gender <gender>] [-password <password>]", run: CreateAdmin},
+		{name: "rotate-jwt-keys", usage: "rotate-jwt-keys [-bits 4096]", run: RotateJWTKeys},
+		{name: "replay-dlq", usage: "replay-dlq [-limit n] mail | webhook", run: ReplayDLQ},
+	}
+)
+
+// * Execute runs the named command with the rest of the arguments,
+// * the server is started when no command is given
+func Execute(cfg *configs.Config, args []string) {
+	// * the route table and the warnings of gin are not json, they are
+	// * only printed when debugging
+	if cfg.Log.GetLevel(cfg.API.Environment) > slog.LevelDebug {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	name := "serve"
+	if len(args) > 0 {
+		name, args = args[0], args[1:]
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == name {
+			cmd.run(cfg, args)
+			return
+		}
+	}
+
+	utlogger.Fatal(errors.New(usage()))
+}
+
+func usage() string {
+	text := "usage:"
+	for _, cmd := range commands {
+		text += "\n  " + cmd.usage
+	}
+
+	return text
+}
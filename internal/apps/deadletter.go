@@ -0,0 +1,50 @@
+package apps
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"project-skbackend/configs"
+	"project-skbackend/internal/services/producerservice"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/utils/utlogger"
+)
+
+// * ReplayDLQ moves the dead letters of the mail or the webhook queue
+// * back to their queue, so they are consumed again by the worker
+func ReplayDLQ(cfg *configs.Config, args []string) {
+	fset := flag.NewFlagSet("replay-dlq", flag.ExitOnError)
+	limit := fset.Int("limit", 0, "the most messages to replay, all of them when zero")
+	_ = fset.Parse(args)
+
+	if fset.NArg() != 1 || *limit < 0 {
+		utlogger.Fatal(fmt.Errorf("usage: replay-dlq [-limit n] mail | webhook"))
+	}
+
+	var (
+		qname string
+	)
+
+	switch fset.Arg(0) {
+	case "mail":
+		qname = cfg.Queue.QueueMail.QueueName
+	case "webhook":
+		qname = cfg.Queue.QueueWebhook.QueueName
+	default:
+		utlogger.Fatal(fmt.Errorf("%w: %s", consttypes.ErrDeadLetterUnknown, fset.Arg(0)))
+	}
+
+	ch, clqueue := cfg.Queue.Init()
+	defer clqueue()
+
+	cfg.Queue.SetupRabbitMQ(ch, *cfg)
+
+	sprod := producerservice.NewProducerService(ch, cfg, context.Background())
+
+	replayed, err := sprod.ReplayDeadLetter(qname, *limit)
+	if err != nil {
+		utlogger.Fatal(err)
+	}
+
+	fmt.Printf("replayed %d messages to %s\n", replayed, qname)
+}
@@ -0,0 +1,109 @@
+package apps
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"project-skbackend/configs"
+	"project-skbackend/packages/utils/utlogger"
+	"strconv"
+	"text/tabwriter"
+)
+
+var (
+	errMigrateUsage = errors.New("usage: migrate up [steps] | down [steps] | status | create <name>")
+)
+
+// * Migrate runs the migrate command, up applies every pending migration
+// * unless the steps are given while down reverts only the last one
+func Migrate(cfg *configs.Config, args []string) {
+	if len(args) == 0 {
+		utlogger.Fatal(errMigrateUsage)
+	}
+
+	// * create only writes the files, it needs no database
+	if args[0] == "create" {
+		if len(args) != 2 {
+			utlogger.Fatal(errMigrateUsage)
+		}
+
+		files, err := configs.CreateMigration(configs.MIGRATION_DIR, args[1])
+		if err != nil {
+			utlogger.Fatal(err)
+		}
+
+		for _, file := range files {
+			fmt.Println(file)
+		}
+		return
+	}
+
+	gdb, err := cfg.DB.Connect()
+	if err != nil {
+		utlogger.Fatal(err)
+	}
+
+	migrator, err := configs.NewMigrator(gdb)
+	if err != nil {
+		utlogger.Fatal(err)
+	}
+
+	switch args[0] {
+	case "up":
+		steps, err := migrateSteps(args, 0)
+		if err != nil {
+			utlogger.Fatal(err)
+		}
+
+		migrations, err := migrator.Up(steps)
+		if err != nil {
+			utlogger.Fatal(err)
+		}
+
+		fmt.Printf("applied %d migrations\n", len(migrations))
+	case "down":
+		steps, err := migrateSteps(args, 1)
+		if err != nil {
+			utlogger.Fatal(err)
+		}
+
+		migrations, err := migrator.Down(steps)
+		if err != nil {
+			utlogger.Fatal(err)
+		}
+
+		fmt.Printf("reverted %d migrations\n", len(migrations))
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			utlogger.Fatal(err)
+		}
+
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "VERSION\tNAME\tAPPLIED AT")
+		for _, status := range statuses {
+			applied := "pending"
+			if status.AppliedAt != nil {
+				applied = status.AppliedAt.Format("2006-01-02 15:04:05 MST")
+			}
+
+			fmt.Fprintf(tw, "%d\t%s\t%s\n", status.Version, status.Name, applied)
+		}
+		tw.Flush()
+	default:
+		utlogger.Fatal(errMigrateUsage)
+	}
+}
+
+func migrateSteps(args []string, fallback int) (int, error) {
+	if len(args) < 2 {
+		return fallback, nil
+	}
+
+	steps, err := strconv.Atoi(args[1])
+	if err != nil || steps < 1 {
+		return 0, errMigrateUsage
+	}
+
+	return steps, nil
+}
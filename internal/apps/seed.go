@@ -0,0 +1,41 @@
+package apps
+
+import (
+	"flag"
+	"fmt"
+	"project-skbackend/configs"
+	"project-skbackend/packages/utils/utlogger"
+)
+
+// * Seed runs the named seeders in their order, every seeder when no
+// * name is given, the schema is expected to be migrated already
+func Seed(cfg *configs.Config, args []string) {
+	fset := flag.NewFlagSet("seed", flag.ExitOnError)
+	list := fset.Bool("list", false, "list the seeders in the order they run")
+	_ = fset.Parse(args)
+
+	if *list {
+		for _, seeder := range configs.SEEDERS {
+			fmt.Println(seeder.Name)
+		}
+		return
+	}
+
+	// * the seeded data has encrypted fields
+	err := cfg.Encryption.InitEncryption()
+	if err != nil {
+		utlogger.Fatal(err)
+	}
+
+	gdb, err := cfg.DB.Connect()
+	if err != nil {
+		utlogger.Fatal(err)
+	}
+
+	err = configs.SeedData(gdb, fset.Args()...)
+	if err != nil {
+		utlogger.Fatal(err)
+	}
+
+	fmt.Println("seeded")
+}
@@ -0,0 +1,36 @@
+package apps
+
+import (
+	"flag"
+	"fmt"
+	"project-skbackend/configs"
+	"project-skbackend/packages/utils/utlogger"
+	"project-skbackend/packages/utils/uttoken"
+)
+
+// * RotateJWTKeys prints new access and refresh token keys as env lines,
+// * the current public keys become the previous ones so the tokens they
+// * signed stay valid until they expire. nothing is written, the lines
+// * replace the ones of the env of every instance
+func RotateJWTKeys(cfg *configs.Config, args []string) {
+	fset := flag.NewFlagSet("rotate-jwt-keys", flag.ExitOnError)
+	bits := fset.Int("bits", 4096, "the size of the rsa keys")
+	_ = fset.Parse(args)
+
+	aprivate, apublic, err := uttoken.GenerateKeyPair(*bits)
+	if err != nil {
+		utlogger.Fatal(err)
+	}
+
+	rprivate, rpublic, err := uttoken.GenerateKeyPair(*bits)
+	if err != nil {
+		utlogger.Fatal(err)
+	}
+
+	fmt.Printf("ACCESS_TOKEN_PUBLIC_KEY=%q\n", apublic)
+	fmt.Printf("ACCESS_TOKEN_PRIVATE_KEY=%q\n", aprivate)
+	fmt.Printf("ACCESS_TOKEN_PREVIOUS_PUBLIC_KEY=%q\n", cfg.JWT.JWTAccessToken.PublicKey)
+	fmt.Printf("REFRESH_TOKEN_PUBLIC_KEY=%q\n", rpublic)
+	fmt.Printf("REFRESH_TOKEN_PRIVATE_KEY=%q\n", rprivate)
+	fmt.Printf("REFRESH_TOKEN_PREVIOUS_PUBLIC_KEY=%q\n", cfg.JWT.JWTRefreshToken.PublicKey)
+}
@@ -42,7 +42,7 @@ func (r *allergyroutes) findAllergies(ctx *gin.Context) {
 		reqpage = utrequest.GeneratePaginationFromRequest(ctx)
 	)
 
-	allergies, err := r.salle.FindAll(reqpage)
+	allergies, err := r.salle.FindAll(ctx, reqpage)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
@@ -73,7 +73,7 @@ func (r *allergyroutes) findAllergiesRaw(ctx *gin.Context) {
 		entity = "allergies"
 	)
 
-	allergies, err := r.salle.Read()
+	allergies, err := r.salle.Read(ctx)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
@@ -115,7 +115,7 @@ func (r *allergyroutes) getAllergy(ctx *gin.Context) {
 		return
 	}
 
-	allergy, err := r.salle.GetByID(aliduuid)
+	allergy, err := r.salle.GetByID(ctx, aliduuid)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
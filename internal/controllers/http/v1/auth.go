@@ -3,62 +3,72 @@ package controllers
 import (
 	"project-skbackend/configs"
 	"project-skbackend/internal/controllers/requests"
+	"project-skbackend/internal/controllers/responses"
 	"project-skbackend/internal/middlewares"
 	"project-skbackend/internal/services/authservice"
+	"project-skbackend/internal/services/securityservice"
+	"project-skbackend/internal/services/sessionservice"
+	"project-skbackend/internal/services/twofactorservice"
 	"project-skbackend/internal/services/userservice"
 	"project-skbackend/packages/consttypes"
 	"project-skbackend/packages/utils/utresponse"
 	"project-skbackend/packages/utils/uttoken"
 
 	"github.com/gin-gonic/gin"
-	"github.com/redis/go-redis/v9"
 )
 
 type (
 	authroutes struct {
 		cfg   *configs.Config
-		rdb   *redis.Client
 		sauth authservice.IAuthService
 		suser userservice.IUserService
+		ssess sessionservice.ISessionService
+		ssecu securityservice.ISecurityService
+		stfa  twofactorservice.ITwoFactorService
 	}
 )
 
 func newAuthRoutes(
 	rg *gin.RouterGroup,
 	cfg *configs.Config,
-	rdb *redis.Client,
 	sauth authservice.IAuthService,
 	suser userservice.IUserService,
+	ssess sessionservice.ISessionService,
+	ssecu securityservice.ISecurityService,
+	stfa twofactorservice.ITwoFactorService,
 ) {
 	r := &authroutes{
 		cfg:   cfg,
-		rdb:   rdb,
 		sauth: sauth,
 		suser: suser,
+		ssess: ssess,
+		ssecu: ssecu,
+		stfa:  stfa,
 	}
 
 	h := rg.Group("auth")
 	{
-		h.POST("signin", r.signin)
+		h.POST("signin", middlewares.ThrottleMiddleware(ssecu, consttypes.TA_SIGNIN), r.signin)
 		h.POST("signout", r.signout)
 
-		gverif := h.Group("verify").Use(middlewares.JWTAuthMiddleware(cfg,
-			consttypes.UR_ADMIN,
-			consttypes.UR_CAREGIVER,
-			consttypes.UR_MEMBER,
-			consttypes.UR_ORGANIZATION,
-			consttypes.UR_PARTNER,
-			consttypes.UR_PATRON,
-			consttypes.UR_USER,
-		))
+		// * second step of the signin, guarded by the challenge token
+		gtwofactor := h.Group("two-factor").Use(middlewares.ThrottleMiddleware(ssecu, consttypes.TA_SIGNIN))
+		{
+			gtwofactor.POST("verify", r.verifyTwoFactor)
+			gtwofactor.POST("enrol", r.beginTwoFactorEnrolment)
+			gtwofactor.POST("enrol/confirm", r.confirmTwoFactorEnrolment)
+		}
+
+		gverif := h.Group("verify").Use(middlewares.JWTAuthMiddleware(cfg, ssess))
 		{
 			gverif.POST("", r.verifyToken)
-			gverif.POST("send", r.sendVerifyEmail)
+			gverif.POST("send", middlewares.ThrottleMiddleware(ssecu, consttypes.TA_VERIFY_SEND), r.sendVerifyEmail)
 		}
 
-		h.POST("forgot-password", r.forgotPassword)
+		h.POST("forgot-password", middlewares.ThrottleMiddleware(ssecu, consttypes.TA_FORGOT_PASSWORD), r.forgotPassword)
 		h.POST("reset-password", r.resetPassword)
 		h.GET("refresh-token", r.refreshAuthToken)
+		h.POST("unlock", r.unlockAccount)
 	}
 }
 
@@ -82,7 +92,66 @@ func (r *authroutes) signin(
 		return
 	}
 
-	resuser, thead, err := r.sauth.Signin(req, ctx)
+	err = r.ssecu.ThrottleAccount(ctx, consttypes.TA_SIGNIN, req.Email, ctx.ClientIP())
+	if err != nil {
+		utresponse.GeneralTooManyRequests(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	resuser, thead, tfch, err := r.sauth.Signin(req, ctx)
+	if err != nil {
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	// * the password is right but the tokens are only given
+	// * once the two factor challenge is passed
+	if tfch != nil {
+		utresponse.GeneralSuccess(
+			function,
+			ctx,
+			tfch,
+		)
+		return
+	}
+
+	resauth := thead.ToAuthResponse(*resuser)
+	utresponse.GeneralSuccessAuth(
+		function,
+		ctx,
+		resauth,
+		thead,
+	)
+}
+
+func (r *authroutes) verifyTwoFactor(
+	ctx *gin.Context,
+) {
+	var (
+		function = "verify two factor"
+		req      requests.VerifyTwoFactor
+	)
+
+	err := ctx.ShouldBind(&req)
+	if err != nil {
+		ve := utresponse.ValidationResponse(err)
+		utresponse.GeneralInvalidRequest(
+			function,
+			ctx,
+			ve,
+			err,
+		)
+		return
+	}
+
+	resuser, thead, err := r.sauth.VerifyTwoFactor(req, ctx)
 	if err != nil {
 		utresponse.GeneralInternalServerError(
 			function,
@@ -101,6 +170,86 @@ func (r *authroutes) signin(
 	)
 }
 
+func (r *authroutes) beginTwoFactorEnrolment(
+	ctx *gin.Context,
+) {
+	var (
+		function = "begin two factor enrolment"
+		req      requests.TwoFactorChallenge
+	)
+
+	err := ctx.ShouldBind(&req)
+	if err != nil {
+		ve := utresponse.ValidationResponse(err)
+		utresponse.GeneralInvalidRequest(
+			function,
+			ctx,
+			ve,
+			err,
+		)
+		return
+	}
+
+	tferes, err := r.stfa.BeginChallengeEnrolment(ctx, req)
+	if err != nil {
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccess(
+		function,
+		ctx,
+		tferes,
+	)
+}
+
+func (r *authroutes) confirmTwoFactorEnrolment(
+	ctx *gin.Context,
+) {
+	var (
+		function = "confirm two factor enrolment"
+		req      requests.ConfirmTwoFactorChallenge
+	)
+
+	err := ctx.ShouldBind(&req)
+	if err != nil {
+		ve := utresponse.ValidationResponse(err)
+		utresponse.GeneralInvalidRequest(
+			function,
+			ctx,
+			ve,
+			err,
+		)
+		return
+	}
+
+	resuser, thead, rcres, err := r.sauth.ConfirmTwoFactorEnrolment(req, ctx)
+	if err != nil {
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	restfauth := responses.TwoFactorAuth{
+		Auth:          *thead.ToAuthResponse(*resuser),
+		RecoveryCodes: rcres.RecoveryCodes,
+	}
+
+	utresponse.GeneralSuccessAuth(
+		function,
+		ctx,
+		restfauth,
+		thead,
+	)
+}
+
 func (r *authroutes) forgotPassword(
 	ctx *gin.Context,
 ) {
@@ -121,7 +270,16 @@ func (r *authroutes) forgotPassword(
 		return
 	}
 
-	err = r.sauth.ForgotPassword(req)
+	err = r.ssecu.ThrottleAccount(ctx, consttypes.TA_FORGOT_PASSWORD, req.Email, ctx.ClientIP())
+	if err != nil {
+		utresponse.GeneralTooManyRequests(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	err = r.sauth.ForgotPassword(ctx, req)
 	if err != nil {
 		utresponse.GeneralInternalServerError(
 			function,
@@ -158,7 +316,44 @@ func (r *authroutes) resetPassword(
 		return
 	}
 
-	err = r.sauth.ResetPassword(req)
+	err = r.sauth.ResetPassword(ctx, req)
+	if err != nil {
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccess(
+		function,
+		ctx,
+		nil,
+	)
+}
+
+func (r *authroutes) unlockAccount(
+	ctx *gin.Context,
+) {
+	var (
+		function = "unlock account"
+		req      requests.UnlockAccount
+	)
+
+	err := ctx.ShouldBind(&req)
+	if err != nil {
+		ve := utresponse.ValidationResponse(err)
+		utresponse.GeneralInvalidRequest(
+			function,
+			ctx,
+			ve,
+			err,
+		)
+		return
+	}
+
+	err = r.ssecu.Unlock(ctx, req, ctx.ClientIP(), ctx.Request.UserAgent())
 	if err != nil {
 		utresponse.GeneralInternalServerError(
 			function,
@@ -263,7 +458,16 @@ func (r *authroutes) sendVerifyEmail(ctx *gin.Context) {
 		return
 	}
 
-	err = r.sauth.SendVerificationEmail(user.ID)
+	err = r.ssecu.ThrottleAccount(ctx, consttypes.TA_VERIFY_SEND, user.ID.String(), ctx.ClientIP())
+	if err != nil {
+		utresponse.GeneralTooManyRequests(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	err = r.sauth.SendVerificationEmail(ctx, user.ID)
 	if err != nil {
 		utresponse.GeneralInternalServerError(
 			function,
@@ -306,7 +510,7 @@ func (r *authroutes) signout(ctx *gin.Context) {
 		return
 	}
 
-	taccessparsed, err := uttoken.ParseToken(taccess, r.cfg.JWT.JWTAccessToken.PublicKey)
+	err = r.sauth.Signout(taccess, ctx)
 	if err != nil {
 		utresponse.GeneralUnauthorized(
 			ctx,
@@ -315,35 +519,6 @@ func (r *authroutes) signout(ctx *gin.Context) {
 		return
 	}
 
-	trefreshparsed, err := uttoken.ParseToken(trefresh, r.cfg.JWT.JWTRefreshToken.PublicKey)
-	if err != nil {
-		utresponse.GeneralUnauthorized(
-			ctx,
-			err,
-		)
-		return
-	}
-
-	// * delete access token from Redis
-	if err = r.rdb.Del(ctx, taccessparsed.TokenUUID.String()).Err(); err != nil {
-		utresponse.GeneralUnauthorized(
-			ctx,
-			err,
-		)
-		return
-	}
-
-	// * delete refresh token from Redis
-	if err = r.rdb.Del(ctx, trefreshparsed.TokenUUID.String()).Err(); err != nil {
-		utresponse.GeneralUnauthorized(
-			ctx,
-			err,
-		)
-		return
-	}
-
-	uttoken.DeleteToken(ctx)
-
 	utresponse.GeneralSuccess(
 		function,
 		ctx,
@@ -0,0 +1,451 @@
+package controllers
+
+import (
+	"errors"
+	"project-skbackend/configs"
+	"project-skbackend/internal/controllers/requests"
+	"project-skbackend/internal/middlewares"
+	"project-skbackend/internal/services/baseroleservice"
+	"project-skbackend/internal/services/caregiverservice"
+	"project-skbackend/internal/services/memberservice"
+	"project-skbackend/internal/services/permissionservice"
+	"project-skbackend/internal/services/sessionservice"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/utils/utrequest"
+	"project-skbackend/packages/utils/utresponse"
+	"project-skbackend/packages/utils/uttoken"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type (
+	caregiverroutes struct {
+		cfg   *configs.Config
+		scare caregiverservice.ICaregiverService
+		smemb memberservice.IMemberService
+		sbase baseroleservice.IBaseRoleService
+		sperm permissionservice.IPermissionService
+		ssess sessionservice.ISessionService
+	}
+)
+
+func newCaregiverRoutes(
+	rg *gin.RouterGroup,
+	cfg *configs.Config,
+	scare caregiverservice.ICaregiverService,
+	smemb memberservice.IMemberService,
+	sbase baseroleservice.IBaseRoleService,
+	sperm permissionservice.IPermissionService,
+	ssess sessionservice.ISessionService,
+) {
+	r := &caregiverroutes{
+		cfg:   cfg,
+		scare: scare,
+		smemb: smemb,
+		sbase: sbase,
+		sperm: sperm,
+		ssess: ssess,
+	}
+
+	gcaregiverspub := rg.Group("caregivers")
+	{
+		ginvitation := gcaregiverspub.Group("invitations")
+		{
+			ginvitation.POST("accept", r.caregiverAcceptInvitation)
+			ginvitation.POST("decline", r.caregiverDeclineInvitation)
+		}
+	}
+
+	gcaregiverspvt := rg.Group("caregivers")
+	gcaregiverspvt.Use(middlewares.JWTAuthMiddleware(cfg, ssess))
+	{
+		gmember := gcaregiverspvt.Group("members")
+		{
+			gmember.GET("", middlewares.PermissionMiddleware(sperm, consttypes.P_MEMBER_READ_MEDICAL), r.caregiverGetMembers)
+			gmember.PATCH(":mid/health", middlewares.PermissionMiddleware(sperm, consttypes.P_MEMBER_UPDATE_MEDICAL), r.caregiverUpdateMemberHealth)
+			gmember.GET(":mid/health/history", middlewares.PermissionMiddleware(sperm, consttypes.P_MEMBER_READ_MEDICAL), r.caregiverGetMemberHealthHistory)
+			gmember.GET(":mid/health/trend", middlewares.PermissionMiddleware(sperm, consttypes.P_MEMBER_READ_MEDICAL), r.caregiverGetMemberHealthTrend)
+		}
+	}
+}
+
+func (r *caregiverroutes) caregiverAcceptInvitation(ctx *gin.Context) {
+	var (
+		function = "accept caregiver invitation"
+		entity   = "caregiver invitation"
+		req      *requests.AcceptCaregiverInvitation
+	)
+
+	if err := ctx.ShouldBind(&req); err != nil {
+		ve := utresponse.ValidationResponse(err)
+		utresponse.GeneralInvalidRequest(
+			function,
+			ctx,
+			ve,
+			err,
+		)
+		return
+	}
+
+	reslink, err := r.scare.AcceptInvitation(ctx, *req)
+	if err != nil {
+		if errors.Is(err, consttypes.ErrCaregiverInvitationNotFound) {
+			utresponse.GeneralNotFound(
+				entity,
+				ctx,
+				err,
+			)
+			return
+		}
+
+		if errors.Is(err, consttypes.ErrCaregiverInvitationExpired) ||
+			errors.Is(err, consttypes.ErrCaregiverInvitationNotPending) ||
+			errors.Is(err, consttypes.ErrCaregiverInvitationInvalidRole) ||
+			errors.Is(err, consttypes.ErrCaregiverDataRequired) ||
+			errors.Is(err, consttypes.ErrCaregiverAlreadyLinked) {
+			utresponse.GeneralInvalidRequest(
+				function,
+				ctx,
+				nil,
+				err,
+			)
+			return
+		}
+
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessCreate(
+		entity,
+		ctx,
+		reslink,
+	)
+}
+
+func (r *caregiverroutes) caregiverDeclineInvitation(ctx *gin.Context) {
+	var (
+		function = "decline caregiver invitation"
+		entity   = "caregiver invitation"
+		req      *requests.DeclineCaregiverInvitation
+	)
+
+	if err := ctx.ShouldBind(&req); err != nil {
+		ve := utresponse.ValidationResponse(err)
+		utresponse.GeneralInvalidRequest(
+			function,
+			ctx,
+			ve,
+			err,
+		)
+		return
+	}
+
+	err := r.scare.DeclineInvitation(ctx, *req)
+	if err != nil {
+		if errors.Is(err, consttypes.ErrCaregiverInvitationNotFound) {
+			utresponse.GeneralNotFound(
+				entity,
+				ctx,
+				err,
+			)
+			return
+		}
+
+		if errors.Is(err, consttypes.ErrCaregiverInvitationExpired) || errors.Is(err, consttypes.ErrCaregiverInvitationNotPending) {
+			utresponse.GeneralInvalidRequest(
+				function,
+				ctx,
+				nil,
+				err,
+			)
+			return
+		}
+
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessUpdate(
+		entity,
+		ctx,
+		nil,
+	)
+}
+
+func (r *caregiverroutes) caregiverGetMembers(ctx *gin.Context) {
+	var (
+		function = "get caregiver members"
+		entity   = "members"
+	)
+
+	userres, err := uttoken.GetUser(ctx)
+	if err != nil {
+		utresponse.GeneralUnauthorized(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	rescare, err := r.scare.GetByUserID(ctx, userres.ID)
+	if err != nil {
+		utresponse.GeneralNotFound(
+			entity,
+			ctx,
+			consttypes.ErrCaregiverNotFound,
+		)
+		return
+	}
+
+	reslinks, err := r.scare.FindMembersByCaregiverID(ctx, rescare.ID)
+	if err != nil {
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessFetch(
+		entity,
+		ctx,
+		reslinks,
+	)
+}
+
+func (r *caregiverroutes) caregiverUpdateMemberHealth(ctx *gin.Context) {
+	var (
+		function = "update member health"
+		entity   = "member health"
+		req      *requests.UpdateMemberHealth
+	)
+
+	if err := ctx.ShouldBind(&req); err != nil {
+		ve := utresponse.ValidationResponse(err)
+		utresponse.GeneralInvalidRequest(
+			function,
+			ctx,
+			ve,
+			err,
+		)
+		return
+	}
+
+	mid, err := uuid.Parse(ctx.Param("mid"))
+	if err != nil {
+		utresponse.GeneralInputRequiredError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	userres, err := uttoken.GetUser(ctx)
+	if err != nil {
+		utresponse.GeneralUnauthorized(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	rescare, err := r.scare.GetByUserID(ctx, userres.ID)
+	if err != nil {
+		utresponse.GeneralNotFound(
+			entity,
+			ctx,
+			consttypes.ErrCaregiverNotFound,
+		)
+		return
+	}
+
+	// * make sure the caregiver is linked to the member
+	// * and is allowed to edit the member's health data
+	modmem, err := r.sbase.GetMemberByCaregiverID(ctx, rescare.ID, &mid, consttypes.CA_EDIT_HEALTH)
+	if err != nil {
+		if errors.Is(err, consttypes.ErrCaregiverNotLinked) || errors.Is(err, consttypes.ErrCaregiverAccessDenied) {
+			utresponse.GeneralForbidden(
+				ctx,
+				err,
+			)
+			return
+		}
+
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	resmem, err := r.smemb.UpdateHealth(ctx, modmem.ID, *req, userres.ID)
+	if err != nil {
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessUpdate(
+		entity,
+		ctx,
+		resmem,
+	)
+}
+
+func (r *caregiverroutes) caregiverGetMemberHealthHistory(ctx *gin.Context) {
+	var (
+		function = "get member health history"
+		entity   = "member health history"
+		reqpage  = utrequest.GeneratePaginationFromRequest(ctx)
+	)
+
+	mid, err := uuid.Parse(ctx.Param("mid"))
+	if err != nil {
+		utresponse.GeneralInputRequiredError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	userres, err := uttoken.GetUser(ctx)
+	if err != nil {
+		utresponse.GeneralUnauthorized(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	rescare, err := r.scare.GetByUserID(ctx, userres.ID)
+	if err != nil {
+		utresponse.GeneralNotFound(
+			entity,
+			ctx,
+			consttypes.ErrCaregiverNotFound,
+		)
+		return
+	}
+
+	modmem, err := r.sbase.GetMemberByCaregiverID(ctx, rescare.ID, &mid, consttypes.CA_VIEW)
+	if err != nil {
+		if errors.Is(err, consttypes.ErrCaregiverNotLinked) || errors.Is(err, consttypes.ErrCaregiverAccessDenied) {
+			utresponse.GeneralForbidden(
+				ctx,
+				err,
+			)
+			return
+		}
+
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	reshist, err := r.smemb.FindHealthHistory(ctx, modmem.ID, reqpage)
+	if err != nil {
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessFetch(
+		entity,
+		ctx,
+		reshist,
+	)
+}
+
+func (r *caregiverroutes) caregiverGetMemberHealthTrend(ctx *gin.Context) {
+	var (
+		function = "get member health trend"
+		entity   = "member health trend"
+		reqpage  = utrequest.GeneratePaginationFromRequest(ctx)
+	)
+
+	mid, err := uuid.Parse(ctx.Param("mid"))
+	if err != nil {
+		utresponse.GeneralInputRequiredError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	userres, err := uttoken.GetUser(ctx)
+	if err != nil {
+		utresponse.GeneralUnauthorized(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	rescare, err := r.scare.GetByUserID(ctx, userres.ID)
+	if err != nil {
+		utresponse.GeneralNotFound(
+			entity,
+			ctx,
+			consttypes.ErrCaregiverNotFound,
+		)
+		return
+	}
+
+	modmem, err := r.sbase.GetMemberByCaregiverID(ctx, rescare.ID, &mid, consttypes.CA_VIEW)
+	if err != nil {
+		if errors.Is(err, consttypes.ErrCaregiverNotLinked) || errors.Is(err, consttypes.ErrCaregiverAccessDenied) {
+			utresponse.GeneralForbidden(
+				ctx,
+				err,
+			)
+			return
+		}
+
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	restrend, err := r.smemb.GetHealthTrend(ctx, modmem.ID, reqpage.Filter)
+	if err != nil {
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessFetch(
+		entity,
+		ctx,
+		restrend,
+	)
+}
@@ -1,9 +1,12 @@
 package controllers
 
 import (
+	"errors"
 	"project-skbackend/configs"
 	"project-skbackend/internal/middlewares"
 	"project-skbackend/internal/services/cartservice"
+	"project-skbackend/internal/services/permissionservice"
+	"project-skbackend/internal/services/sessionservice"
 	"project-skbackend/internal/services/userservice"
 	"project-skbackend/packages/consttypes"
 	"project-skbackend/packages/utils/utresponse"
@@ -17,6 +20,8 @@ type (
 		cfg   *configs.Config
 		scart cartservice.ICartService
 		suser userservice.IUserService
+		sperm permissionservice.IPermissionService
+		ssess sessionservice.ISessionService
 	}
 )
 
@@ -25,17 +30,21 @@ func newCartRoutes(
 	cfg *configs.Config,
 	scart cartservice.ICartService,
 	suser userservice.IUserService,
+	sperm permissionservice.IPermissionService,
+	ssess sessionservice.ISessionService,
 ) {
 	r := &cartroutes{
 		cfg:   cfg,
 		scart: scart,
 		suser: suser,
+		sperm: sperm,
+		ssess: ssess,
 	}
 
 	gcartspvt := rg.Group("carts")
-	gcartspvt.Use(middlewares.JWTAuthMiddleware(cfg, consttypes.UR_MEMBER, consttypes.UR_CAREGIVER))
+	gcartspvt.Use(middlewares.JWTAuthMiddleware(cfg, ssess))
 	{
-		gcartspvt.GET("own", r.getOwnCart)
+		gcartspvt.GET("own", middlewares.PermissionMiddleware(sperm, consttypes.P_CART_READ), r.getOwnCart)
 	}
 }
 
@@ -55,7 +64,7 @@ func (r *cartroutes) getOwnCart(ctx *gin.Context) {
 		return
 	}
 
-	roleres, err := r.suser.GetRoleDataByUserID(userres.ID)
+	roleres, err := r.suser.GetRoleDataByUserID(ctx, userres.ID)
 	if err != nil {
 		utresponse.GeneralUnauthorized(
 			ctx,
@@ -73,8 +82,27 @@ func (r *cartroutes) getOwnCart(ctx *gin.Context) {
 		return
 	}
 
-	rescart, err := r.scart.FindByRoleRes(*roleres)
+	mid, err := parseMemberIDQuery(ctx)
 	if err != nil {
+		utresponse.GeneralInvalidRequest(
+			function,
+			ctx,
+			nil,
+			err,
+		)
+		return
+	}
+
+	rescart, err := r.scart.FindByRoleRes(ctx, *roleres, mid)
+	if err != nil {
+		if errors.Is(err, consttypes.ErrCaregiverNotLinked) || errors.Is(err, consttypes.ErrCaregiverAccessDenied) {
+			utresponse.GeneralForbidden(
+				ctx,
+				err,
+			)
+			return
+		}
+
 		utresponse.GeneralInternalServerError(
 			function,
 			ctx,
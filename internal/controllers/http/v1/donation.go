@@ -42,7 +42,7 @@ func (r *donationroutes) findDonations(ctx *gin.Context) {
 		reqpage = utrequest.GeneratePaginationFromRequest(ctx)
 	)
 
-	donations, err := r.sdonation.FindAll(reqpage)
+	donations, err := r.sdonation.FindAll(ctx, reqpage)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
@@ -73,7 +73,7 @@ func (r *donationroutes) findDonationsRaw(ctx *gin.Context) {
 		entity = "donations"
 	)
 
-	donations, err := r.sdonation.Read()
+	donations, err := r.sdonation.Read(ctx)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
@@ -115,7 +115,7 @@ func (r *donationroutes) getDonation(ctx *gin.Context) {
 		return
 	}
 
-	donation, err := r.sdonation.GetByID(doniduuid)
+	donation, err := r.sdonation.GetByID(ctx, doniduuid)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
@@ -75,7 +75,7 @@ func (r *fileroutes) uploadFile(ctx *gin.Context) {
 	}
 
 	fileupload := utfile.NewFileUpload(multipart)
-	url, err := r.sfile.Upload(*fileupload)
+	url, err := r.sfile.Upload(ctx, *fileupload)
 	if err != nil {
 		utresponse.GeneralInternalServerError(
 			function,
@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"net/http"
+	"project-skbackend/configs"
+	"project-skbackend/internal/middlewares"
+	"project-skbackend/internal/services/healthservice"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/lifecycle"
+
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	healthroutes struct {
+		cfg   *configs.Config
+		shlth healthservice.IHealthService
+		lm    *lifecycle.Manager
+	}
+)
+
+// * the health routes are not under the api, so the probes of the
+// * orchestrator do not depend on the api version
+func newHealthRoutes(
+	ge *gin.Engine,
+	cfg *configs.Config,
+	shlth healthservice.IHealthService,
+	lm *lifecycle.Manager,
+) {
+	r := &healthroutes{
+		cfg:   cfg,
+		shlth: shlth,
+		lm:    lm,
+	}
+
+	ghealth := ge.Group("health")
+	{
+		ghealth.GET("", r.live)
+		ghealth.GET("live", r.live)
+		ghealth.GET("ready", r.ready)
+	}
+}
+
+// * NewHealthRouter serves only the health and the metrics routes, for
+// * the worker that has no api but is probed and scraped the same way
+func NewHealthRouter(ge *gin.Engine, cfg *configs.Config, shlth healthservice.IHealthService, lm *lifecycle.Manager) {
+	ge.Use(middlewares.RecoveryMiddleware())
+
+	newHealthRoutes(ge, cfg, shlth, lm)
+	newMetricsRoutes(ge)
+}
+
+func (r *healthroutes) live(ctx *gin.Context) {
+	health := r.shlth.Live(r.lm)
+
+	ctx.JSON(healthStatusCode(health.Status), health)
+}
+
+func (r *healthroutes) ready(ctx *gin.Context) {
+	health := r.shlth.Ready(ctx.Request.Context(), r.lm)
+
+	ctx.JSON(healthStatusCode(health.Status), health)
+}
+
+func healthStatusCode(status consttypes.HealthStatus) int {
+	if status != consttypes.HS_OK {
+		return http.StatusServiceUnavailable
+	}
+
+	return http.StatusOK
+}
@@ -42,7 +42,7 @@ func (r *illnessroutes) findIllnesses(ctx *gin.Context) {
 		reqpage = utrequest.GeneratePaginationFromRequest(ctx)
 	)
 
-	illnesses, err := r.sillness.FindAll(reqpage)
+	illnesses, err := r.sillness.FindAll(ctx, reqpage)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
@@ -73,7 +73,7 @@ func (r *illnessroutes) findIllnessesRaw(ctx *gin.Context) {
 		entity = "illnesses"
 	)
 
-	illnesses, err := r.sillness.Read()
+	illnesses, err := r.sillness.Read(ctx)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
@@ -115,7 +115,7 @@ func (r *illnessroutes) getIllnesses(ctx *gin.Context) {
 		return
 	}
 
-	illness, err := r.sillness.GetByID(illiduuid)
+	illness, err := r.sillness.GetByID(ctx, illiduuid)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
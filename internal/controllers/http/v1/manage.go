@@ -2,20 +2,32 @@ package controllers
 
 import (
 	"errors"
+	"fmt"
+	"net/http"
 	"project-skbackend/configs"
 	"project-skbackend/internal/controllers/requests"
 	"project-skbackend/internal/middlewares"
 	"project-skbackend/internal/services/allergyservice"
+	"project-skbackend/internal/services/analyticsservice"
+	"project-skbackend/internal/services/auditservice"
 	"project-skbackend/internal/services/donationservice"
 	"project-skbackend/internal/services/fileservice"
 	"project-skbackend/internal/services/illnessservice"
+	"project-skbackend/internal/services/mealcategoryservice"
 	"project-skbackend/internal/services/mealservice"
 	"project-skbackend/internal/services/memberservice"
 	"project-skbackend/internal/services/partnerservice"
 	"project-skbackend/internal/services/patronservice"
+	"project-skbackend/internal/services/permissionservice"
+	"project-skbackend/internal/services/privacyservice"
+	"project-skbackend/internal/services/securityservice"
+	"project-skbackend/internal/services/serviceaccountservice"
+	"project-skbackend/internal/services/sessionservice"
 	"project-skbackend/packages/consttypes"
 	"project-skbackend/packages/utils/utrequest"
 	"project-skbackend/packages/utils/utresponse"
+	"project-skbackend/packages/utils/uttoken"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -28,6 +40,7 @@ type (
 	manageroutes struct {
 		cfg       *configs.Config
 		smeal     mealservice.IMealService
+		smcat     mealcategoryservice.IMealCategoryService
 		smember   memberservice.IMemberService
 		spartner  partnerservice.IPartnerService
 		spatron   patronservice.IPatronService
@@ -35,6 +48,13 @@ type (
 		sfile     fileservice.IFileService
 		sallergy  allergyservice.IAllergyService
 		sdonation donationservice.IDonationService
+		sperm     permissionservice.IPermissionService
+		ssess     sessionservice.ISessionService
+		sprvc     privacyservice.IPrivacyService
+		ssecu     securityservice.ISecurityService
+		ssvac     serviceaccountservice.IServiceAccountService
+		saudt     auditservice.IAuditService
+		sanly     analyticsservice.IAnalyticsService
 	}
 )
 
@@ -42,6 +62,7 @@ func newManageRoutes(
 	rg *gin.RouterGroup,
 	cfg *configs.Config,
 	smeal mealservice.IMealService,
+	smcat mealcategoryservice.IMealCategoryService,
 	smember memberservice.IMemberService,
 	spartner partnerservice.IPartnerService,
 	spatron patronservice.IPatronService,
@@ -49,10 +70,18 @@ func newManageRoutes(
 	sfile fileservice.IFileService,
 	sallergy allergyservice.IAllergyService,
 	sdonation donationservice.IDonationService,
+	sperm permissionservice.IPermissionService,
+	ssess sessionservice.ISessionService,
+	sprvc privacyservice.IPrivacyService,
+	ssecu securityservice.ISecurityService,
+	ssvac serviceaccountservice.IServiceAccountService,
+	saudt auditservice.IAuditService,
+	sanly analyticsservice.IAnalyticsService,
 ) {
 	r := &manageroutes{
 		cfg:       cfg,
 		smeal:     smeal,
+		smcat:     smcat,
 		smember:   smember,
 		spartner:  spartner,
 		spatron:   spatron,
@@ -60,75 +89,128 @@ func newManageRoutes(
 		sfile:     sfile,
 		sallergy:  sallergy,
 		sdonation: sdonation,
+		sperm:     sperm,
+		ssess:     ssess,
+		sprvc:     sprvc,
+		ssecu:     ssecu,
+		ssvac:     ssvac,
+		saudt:     saudt,
+		sanly:     sanly,
 	}
 
 	gmanage := rg.Group("manages")
-	gmanage.Use(middlewares.JWTAuthMiddleware(
-		cfg,
-		consttypes.UR_ADMIN,
-	))
+	gmanage.Use(middlewares.JWTAuthMiddleware(cfg, ssess))
+	gmanage.Use(middlewares.AuditMiddleware(saudt))
 	{
 		gmeals := gmanage.Group("meals")
 		{
-			gmeals.POST("", r.createMeal)
-			gmeals.GET("", r.findMeals)
-			gmeals.GET("raw", r.findMealsRaw)
-			gmeals.PUT("/:mid", r.updateMeal)
-			gmeals.DELETE("/:mid", r.deleteMeal)
+			gmeals.POST("", middlewares.PermissionMiddleware(sperm, consttypes.P_MEAL_CREATE), r.createMeal)
+			gmeals.GET("", middlewares.PermissionMiddleware(sperm, consttypes.P_MEAL_READ), r.findMeals)
+			gmeals.GET("raw", middlewares.PermissionMiddleware(sperm, consttypes.P_MEAL_READ), r.findMealsRaw)
+			gmeals.PUT("/:mid", middlewares.PermissionMiddleware(sperm, consttypes.P_MEAL_UPDATE), r.updateMeal)
+			gmeals.DELETE("/:mid", middlewares.PermissionMiddleware(sperm, consttypes.P_MEAL_DELETE), r.deleteMeal)
+
+			gmeals.POST("categories", middlewares.PermissionMiddleware(sperm, consttypes.P_MEAL_CATEGORY_CREATE), r.createMealCategory)
+			gmeals.PUT("categories/:mcid", middlewares.PermissionMiddleware(sperm, consttypes.P_MEAL_CATEGORY_UPDATE), r.updateMealCategory)
+			gmeals.DELETE("categories/:mcid", middlewares.PermissionMiddleware(sperm, consttypes.P_MEAL_CATEGORY_DELETE), r.deleteMealCategory)
 		}
 
 		gmember := gmanage.Group("members")
 		{
-			gmember.POST("", r.createMember)
-			gmember.GET("", r.findMembers)
-			gmember.GET("raw", r.findMembersRaw)
-			gmember.PUT("/:mid", r.updateMember)
-			gmember.DELETE("/:mid", r.deleteMember)
+			gmember.POST("", middlewares.PermissionMiddleware(sperm, consttypes.P_MEMBER_CREATE), r.createMember)
+			gmember.GET("", middlewares.PermissionMiddleware(sperm, consttypes.P_MEMBER_READ), r.findMembers)
+			gmember.GET("raw", middlewares.PermissionMiddleware(sperm, consttypes.P_MEMBER_READ), r.findMembersRaw)
+			gmember.PUT("/:mid", middlewares.PermissionMiddleware(sperm, consttypes.P_MEMBER_UPDATE), r.updateMember)
+			gmember.DELETE("/:mid", middlewares.PermissionMiddleware(sperm, consttypes.P_MEMBER_DELETE), r.deleteMember)
 		}
 
 		gpartner := gmanage.Group("partners")
 		{
-			gpartner.POST("", r.createPartner)
-			gpartner.GET("", r.findPartners)
-			gpartner.GET("raw", r.findPartnersRaw)
-			gpartner.PUT("/:pid", r.updatePartner)
-			gpartner.DELETE("/:pid", r.deletePartner)
+			gpartner.POST("", middlewares.PermissionMiddleware(sperm, consttypes.P_PARTNER_CREATE), r.createPartner)
+			gpartner.GET("", middlewares.PermissionMiddleware(sperm, consttypes.P_PARTNER_READ), r.findPartners)
+			gpartner.GET("raw", middlewares.PermissionMiddleware(sperm, consttypes.P_PARTNER_READ), r.findPartnersRaw)
+			gpartner.PUT("/:pid", middlewares.PermissionMiddleware(sperm, consttypes.P_PARTNER_UPDATE), r.updatePartner)
+			gpartner.DELETE("/:pid", middlewares.PermissionMiddleware(sperm, consttypes.P_PARTNER_DELETE), r.deletePartner)
 		}
 
 		gpatron := gmanage.Group("patrons")
 		{
-			gpatron.POST("", r.createPatron)
-			gpatron.GET("", r.findPatrons)
-			gpatron.GET("raw", r.findPatronsRaw)
-			gpatron.PUT("/:pid", r.updatePatron)
-			gpatron.DELETE("/:pid", r.deletePatron)
+			gpatron.POST("", middlewares.PermissionMiddleware(sperm, consttypes.P_PATRON_CREATE), r.createPatron)
+			gpatron.GET("", middlewares.PermissionMiddleware(sperm, consttypes.P_PATRON_READ), r.findPatrons)
+			gpatron.GET("raw", middlewares.PermissionMiddleware(sperm, consttypes.P_PATRON_READ), r.findPatronsRaw)
+			gpatron.PUT("/:pid", middlewares.PermissionMiddleware(sperm, consttypes.P_PATRON_UPDATE), r.updatePatron)
+			gpatron.DELETE("/:pid", middlewares.PermissionMiddleware(sperm, consttypes.P_PATRON_DELETE), r.deletePatron)
 		}
 
 		gillness := gmanage.Group("illnesses")
 		{
-			gillness.POST("", r.createIllness)
-			gillness.GET("", r.findIllnesses)
-			gillness.GET("raw", r.findIllnessesRaw)
-			gillness.PUT("/:iid", r.updateIllness)
-			gillness.DELETE("/:iid", r.deleteIllness)
+			gillness.POST("", middlewares.PermissionMiddleware(sperm, consttypes.P_ILLNESS_CREATE), r.createIllness)
+			gillness.GET("", middlewares.PermissionMiddleware(sperm, consttypes.P_ILLNESS_READ), r.findIllnesses)
+			gillness.GET("raw", middlewares.PermissionMiddleware(sperm, consttypes.P_ILLNESS_READ), r.findIllnessesRaw)
+			gillness.PUT("/:iid", middlewares.PermissionMiddleware(sperm, consttypes.P_ILLNESS_UPDATE), r.updateIllness)
+			gillness.DELETE("/:iid", middlewares.PermissionMiddleware(sperm, consttypes.P_ILLNESS_DELETE), r.deleteIllness)
 		}
 
 		gallergy := gmanage.Group("allergies")
 		{
-			gallergy.POST("", r.createAllergy)
-			gallergy.GET("", r.findAllergies)
-			gallergy.GET("raw", r.findAllergiesRaw)
-			gallergy.PUT("/:aid", r.updateAllergy)
-			gallergy.DELETE("/:aid", r.deleteAllergy)
+			gallergy.POST("", middlewares.PermissionMiddleware(sperm, consttypes.P_ALLERGY_CREATE), r.createAllergy)
+			gallergy.GET("", middlewares.PermissionMiddleware(sperm, consttypes.P_ALLERGY_READ), r.findAllergies)
+			gallergy.GET("raw", middlewares.PermissionMiddleware(sperm, consttypes.P_ALLERGY_READ), r.findAllergiesRaw)
+			gallergy.PUT("/:aid", middlewares.PermissionMiddleware(sperm, consttypes.P_ALLERGY_UPDATE), r.updateAllergy)
+			gallergy.DELETE("/:aid", middlewares.PermissionMiddleware(sperm, consttypes.P_ALLERGY_DELETE), r.deleteAllergy)
 		}
 
 		gdonation := gmanage.Group("donations")
 		{
 			// ! no create donation because admin cannot interfene
-			gdonation.GET("", r.findDonations)
-			gdonation.GET("raw", r.findDonationsRaw)
-			gdonation.PUT("/:did", r.updateDonation)
-			gdonation.DELETE("/:did", r.deleteDonation)
+			gdonation.GET("", middlewares.PermissionMiddleware(sperm, consttypes.P_DONATION_READ), r.findDonations)
+			gdonation.GET("raw", middlewares.PermissionMiddleware(sperm, consttypes.P_DONATION_READ), r.findDonationsRaw)
+			gdonation.PUT("/:did", middlewares.PermissionMiddleware(sperm, consttypes.P_DONATION_UPDATE), r.updateDonation)
+			gdonation.DELETE("/:did", middlewares.PermissionMiddleware(sperm, consttypes.P_DONATION_DELETE), r.deleteDonation)
+		}
+
+		gpermission := gmanage.Group("permissions")
+		{
+			gpermission.GET("", middlewares.PermissionMiddleware(sperm, consttypes.P_PERMISSION_READ), r.findRolePermissions)
+			gpermission.GET("/:role", middlewares.PermissionMiddleware(sperm, consttypes.P_PERMISSION_READ), r.getRolePermissions)
+			gpermission.PUT("/:role", middlewares.PermissionMiddleware(sperm, consttypes.P_PERMISSION_UPDATE), r.updateRolePermissions)
+		}
+
+		guser := gmanage.Group("users")
+		{
+			guser.POST("/:uid/data-erasures", middlewares.PermissionMiddleware(sperm, consttypes.P_USER_ERASE), r.createUserDataErasure)
+			guser.GET("/:uid/data-erasures", middlewares.PermissionMiddleware(sperm, consttypes.P_USER_ERASE), r.findUserDataErasures)
+			guser.GET("/:uid/security-events", middlewares.PermissionMiddleware(sperm, consttypes.P_USER_READ_SECURITY_EVENTS), r.findUserSecurityEvents)
+		}
+
+		gserviceaccount := gmanage.Group("service-accounts")
+		{
+			gserviceaccount.POST("", middlewares.PermissionMiddleware(sperm, consttypes.P_SERVICE_ACCOUNT_MANAGE), r.createServiceAccount)
+			gserviceaccount.GET("", middlewares.PermissionMiddleware(sperm, consttypes.P_SERVICE_ACCOUNT_MANAGE), r.findServiceAccounts)
+			gserviceaccount.GET("/:said", middlewares.PermissionMiddleware(sperm, consttypes.P_SERVICE_ACCOUNT_MANAGE), r.getServiceAccount)
+			gserviceaccount.PUT("/:said", middlewares.PermissionMiddleware(sperm, consttypes.P_SERVICE_ACCOUNT_MANAGE), r.updateServiceAccount)
+			gserviceaccount.DELETE("/:said", middlewares.PermissionMiddleware(sperm, consttypes.P_SERVICE_ACCOUNT_MANAGE), r.deleteServiceAccount)
+			gserviceaccount.POST("/:said/api-keys", middlewares.PermissionMiddleware(sperm, consttypes.P_SERVICE_ACCOUNT_MANAGE), r.createAPIKey)
+			gserviceaccount.GET("/:said/api-keys", middlewares.PermissionMiddleware(sperm, consttypes.P_SERVICE_ACCOUNT_MANAGE), r.findAPIKeys)
+			gserviceaccount.DELETE("/:said/api-keys/:kid", middlewares.PermissionMiddleware(sperm, consttypes.P_SERVICE_ACCOUNT_MANAGE), r.revokeAPIKey)
+		}
+
+		gauditlog := gmanage.Group("audit-logs")
+		{
+			gauditlog.GET("", middlewares.PermissionMiddleware(sperm, consttypes.P_AUDIT_LOG_READ), r.findAuditLogs)
+			gauditlog.GET("export", middlewares.PermissionMiddleware(sperm, consttypes.P_AUDIT_LOG_READ), r.exportAuditLogs)
+			gauditlog.GET("/:alid", middlewares.PermissionMiddleware(sperm, consttypes.P_AUDIT_LOG_READ), r.getAuditLog)
+		}
+
+		ganalytics := gmanage.Group("analytics")
+		{
+			ganalytics.GET("orders", middlewares.PermissionMiddleware(sperm, consttypes.P_ANALYTICS_READ), r.analyticsOrders)
+			ganalytics.GET("orders/cancellations", middlewares.PermissionMiddleware(sperm, consttypes.P_ANALYTICS_READ), r.analyticsCancellations)
+			ganalytics.GET("orders/prep-time", middlewares.PermissionMiddleware(sperm, consttypes.P_ANALYTICS_READ), r.analyticsPrepTime)
+			ganalytics.GET("meals/top", middlewares.PermissionMiddleware(sperm, consttypes.P_ANALYTICS_READ), r.analyticsTopMeals)
+			ganalytics.GET("partners/top", middlewares.PermissionMiddleware(sperm, consttypes.P_ANALYTICS_READ), r.analyticsTopPartners)
+			ganalytics.GET("organizations/active-members", middlewares.PermissionMiddleware(sperm, consttypes.P_ANALYTICS_READ), r.analyticsActiveMembers)
+			ganalytics.GET("donations", middlewares.PermissionMiddleware(sperm, consttypes.P_ANALYTICS_READ), r.analyticsDonations)
 		}
 	}
 }
@@ -155,8 +237,17 @@ func (r *manageroutes) createMeal(ctx *gin.Context) {
 		return
 	}
 
-	resmeal, err := r.smeal.Create(req)
+	resmeal, err := r.smeal.Create(ctx, req)
 	if err != nil {
+		if errors.Is(err, consttypes.ErrMealCategoryNotFound) {
+			utresponse.GeneralNotFound(
+				"meal category",
+				ctx,
+				err,
+			)
+			return
+		}
+
 		utresponse.GeneralInternalServerError(
 			function,
 			ctx,
@@ -178,7 +269,7 @@ func (r *manageroutes) findMeals(ctx *gin.Context) {
 		reqpage = utrequest.GeneratePaginationFromRequest(ctx)
 	)
 
-	meals, err := r.smeal.FindAll(reqpage)
+	meals, err := r.smeal.FindAll(ctx, reqpage)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
@@ -209,7 +300,7 @@ func (r *manageroutes) findMealsRaw(ctx *gin.Context) {
 		entity = "meals"
 	)
 
-	meals, err := r.smeal.Read()
+	meals, err := r.smeal.Read(ctx)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
@@ -264,7 +355,7 @@ func (r *manageroutes) updateMeal(ctx *gin.Context) {
 		return
 	}
 
-	_, err = r.smeal.GetByID(uuid)
+	_, err = r.smeal.GetByID(ctx, uuid)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
@@ -283,8 +374,17 @@ func (r *manageroutes) updateMeal(ctx *gin.Context) {
 		return
 	}
 
-	resmeal, err := r.smeal.Update(uuid, req)
+	resmeal, err := r.smeal.Update(ctx, uuid, req)
 	if err != nil {
+		if errors.Is(err, consttypes.ErrMealCategoryNotFound) {
+			utresponse.GeneralNotFound(
+				"meal category",
+				ctx,
+				err,
+			)
+			return
+		}
+
 		utresponse.GeneralInternalServerError(
 			entity,
 			ctx,
@@ -316,7 +416,7 @@ func (r *manageroutes) deleteMeal(ctx *gin.Context) {
 		return
 	}
 
-	_, err = r.smeal.GetByID(uuid)
+	_, err = r.smeal.GetByID(ctx, uuid)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
@@ -335,8 +435,253 @@ func (r *manageroutes) deleteMeal(ctx *gin.Context) {
 		return
 	}
 
-	err = r.smeal.Delete(uuid)
+	err = r.smeal.Delete(ctx, uuid)
+	if err != nil {
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessDelete(
+		entity,
+		ctx,
+		nil,
+	)
+}
+
+func (r *manageroutes) createMealCategory(ctx *gin.Context) {
+	var (
+		function = "create meal category"
+		entity   = "meal category"
+		req      requests.CreateMealCategory
+	)
+
+	err := ctx.ShouldBind(&req)
+	if err != nil {
+		ve := utresponse.ValidationResponse(err)
+		utresponse.GeneralInvalidRequest(
+			function,
+			ctx,
+			ve,
+			err,
+		)
+		return
+	}
+
+	resmc, err := r.smcat.Create(ctx, req)
+	if err != nil {
+		if errors.Is(err, consttypes.ErrMealCategoryAlreadyExists) {
+			utresponse.GeneralDuplicate(
+				"name",
+				ctx,
+				err,
+			)
+			return
+		}
+
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	// * define the image request
+	reqimg := req.CreateImage
+	// * if the image request is not empty
+	// * validate and upload the image
+	if reqimg != nil {
+		if err := reqimg.Validate(); err != nil {
+			utresponse.GeneralInvalidRequest(
+				function,
+				ctx,
+				nil,
+				err,
+			)
+			return
+		}
+
+		multipart, err := reqimg.GetMultipartFile()
+		if err != nil {
+			utresponse.GeneralInternalServerError(
+				function,
+				ctx,
+				err,
+			)
+			return
+		}
+
+		err = r.sfile.UploadMealCategoryImage(ctx, resmc.ID, multipart)
+		if err != nil {
+			utresponse.GeneralInternalServerError(
+				function,
+				ctx,
+				err,
+			)
+			return
+		}
+
+		// * read the category again so the response has the new image
+		resmc, err = r.smcat.GetByID(ctx, resmc.ID)
+		if err != nil {
+			utresponse.GeneralInternalServerError(
+				function,
+				ctx,
+				err,
+			)
+			return
+		}
+	}
+
+	utresponse.GeneralSuccessCreate(
+		entity,
+		ctx,
+		resmc,
+	)
+}
+
+func (r *manageroutes) updateMealCategory(ctx *gin.Context) {
+	var (
+		function = "update meal category"
+		entity   = "meal category"
+		req      requests.UpdateMealCategory
+	)
+
+	mcid, err := uuid.Parse(ctx.Param("mcid"))
+	if err != nil {
+		utresponse.GeneralInputRequiredError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	err = ctx.ShouldBind(&req)
+	if err != nil {
+		ve := utresponse.ValidationResponse(err)
+		utresponse.GeneralInvalidRequest(
+			function,
+			ctx,
+			ve,
+			err,
+		)
+		return
+	}
+
+	resmc, err := r.smcat.Update(ctx, mcid, req)
+	if err != nil {
+		if errors.Is(err, consttypes.ErrMealCategoryNotFound) {
+			utresponse.GeneralNotFound(
+				entity,
+				ctx,
+				err,
+			)
+			return
+		}
+
+		if errors.Is(err, consttypes.ErrMealCategoryAlreadyExists) {
+			utresponse.GeneralDuplicate(
+				"name",
+				ctx,
+				err,
+			)
+			return
+		}
+
+		utresponse.GeneralFailedUpdate(
+			entity,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	// * define the image request
+	reqimg := req.CreateImage
+	// * if the image request is not empty
+	// * validate and upload the image
+	if reqimg != nil {
+		if err := reqimg.Validate(); err != nil {
+			utresponse.GeneralInvalidRequest(
+				function,
+				ctx,
+				nil,
+				err,
+			)
+			return
+		}
+
+		multipart, err := reqimg.GetMultipartFile()
+		if err != nil {
+			utresponse.GeneralInternalServerError(
+				function,
+				ctx,
+				err,
+			)
+			return
+		}
+
+		err = r.sfile.UploadMealCategoryImage(ctx, resmc.ID, multipart)
+		if err != nil {
+			utresponse.GeneralInternalServerError(
+				function,
+				ctx,
+				err,
+			)
+			return
+		}
+
+		// * read the category again so the response has the new image
+		resmc, err = r.smcat.GetByID(ctx, resmc.ID)
+		if err != nil {
+			utresponse.GeneralInternalServerError(
+				function,
+				ctx,
+				err,
+			)
+			return
+		}
+	}
+
+	utresponse.GeneralSuccessUpdate(
+		entity,
+		ctx,
+		resmc,
+	)
+}
+
+func (r *manageroutes) deleteMealCategory(ctx *gin.Context) {
+	var (
+		function = "delete meal category"
+		entity   = "meal category"
+	)
+
+	mcid, err := uuid.Parse(ctx.Param("mcid"))
+	if err != nil {
+		utresponse.GeneralInputRequiredError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	err = r.smcat.Delete(ctx, mcid)
 	if err != nil {
+		if errors.Is(err, consttypes.ErrMealCategoryNotFound) {
+			utresponse.GeneralNotFound(
+				entity,
+				ctx,
+				err,
+			)
+			return
+		}
+
 		utresponse.GeneralInternalServerError(
 			function,
 			ctx,
@@ -379,7 +724,16 @@ func (r *manageroutes) createMember(ctx *gin.Context) {
 		return
 	}
 
-	resmemb, err := r.smember.Create(req)
+	userres, err := uttoken.GetUser(ctx)
+	if err != nil {
+		utresponse.GeneralUnauthorized(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	resmemb, err := r.smember.Create(ctx, req, &userres.ID)
 	if err != nil {
 		var pgerr *pgconn.PgError
 		if errors.As(err, &pgerr) {
@@ -426,7 +780,7 @@ func (r *manageroutes) createMember(ctx *gin.Context) {
 			return
 		}
 
-		err = r.sfile.UploadProfilePicture(resmemb.User.ID, multipart)
+		err = r.sfile.UploadProfilePicture(ctx, resmemb.User.ID, multipart)
 		if err != nil {
 			utresponse.GeneralInternalServerError(
 				function,
@@ -450,7 +804,7 @@ func (r *manageroutes) findMembers(ctx *gin.Context) {
 		reqpage = utrequest.GeneratePaginationFromRequest(ctx)
 	)
 
-	members, err := r.smember.FindAll(reqpage)
+	members, err := r.smember.FindAll(ctx, reqpage)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
@@ -481,7 +835,7 @@ func (r *manageroutes) findMembersRaw(ctx *gin.Context) {
 		entity = "members"
 	)
 
-	resmemb, err := r.smember.Read()
+	resmemb, err := r.smember.Read(ctx)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
@@ -536,7 +890,7 @@ func (r *manageroutes) updateMember(ctx *gin.Context) {
 		return
 	}
 
-	_, err = r.smember.GetByID(uuid)
+	_, err = r.smember.GetByID(ctx, uuid)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
@@ -555,7 +909,16 @@ func (r *manageroutes) updateMember(ctx *gin.Context) {
 		return
 	}
 
-	resmemb, err := r.smember.Update(uuid, req)
+	userres, err := uttoken.GetUser(ctx)
+	if err != nil {
+		utresponse.GeneralUnauthorized(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	resmemb, err := r.smember.Update(ctx, uuid, req, userres.ID)
 	if err != nil {
 		utresponse.GeneralFailedUpdate(
 			entity,
@@ -590,7 +953,7 @@ func (r *manageroutes) updateMember(ctx *gin.Context) {
 			return
 		}
 
-		err = r.sfile.UploadProfilePicture(resmemb.User.ID, multipart)
+		err = r.sfile.UploadProfilePicture(ctx, resmemb.User.ID, multipart)
 		if err != nil {
 			utresponse.GeneralInternalServerError(
 				function,
@@ -624,7 +987,7 @@ func (r *manageroutes) deleteMember(ctx *gin.Context) {
 		return
 	}
 
-	_, err = r.smember.GetByID(uuid)
+	_, err = r.smember.GetByID(ctx, uuid)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
@@ -643,7 +1006,7 @@ func (r *manageroutes) deleteMember(ctx *gin.Context) {
 		return
 	}
 
-	err = r.smember.Delete(uuid)
+	err = r.smember.Delete(ctx, uuid)
 	if err != nil {
 		utresponse.GeneralInternalServerError(
 			function,
@@ -686,7 +1049,7 @@ func (r *manageroutes) createPartner(ctx *gin.Context) {
 		return
 	}
 
-	respartner, err := r.spartner.Create(req)
+	respartner, err := r.spartner.Create(ctx, req)
 	if err != nil {
 		var pgerr *pgconn.PgError
 		if errors.As(err, &pgerr) {
@@ -733,7 +1096,7 @@ func (r *manageroutes) createPartner(ctx *gin.Context) {
 			return
 		}
 
-		err = r.sfile.UploadProfilePicture(respartner.User.ID, multipart)
+		err = r.sfile.UploadProfilePicture(ctx, respartner.User.ID, multipart)
 		if err != nil {
 			utresponse.GeneralInternalServerError(
 				function,
@@ -757,7 +1120,7 @@ func (r *manageroutes) findPartners(ctx *gin.Context) {
 		reqpage = utrequest.GeneratePaginationFromRequest(ctx)
 	)
 
-	partners, err := r.spartner.FindAll(reqpage)
+	partners, err := r.spartner.FindAll(ctx, reqpage)
 	if err != nil {
 		if err != nil {
 			if err == gorm.ErrRecordNotFound {
@@ -790,7 +1153,7 @@ func (r *manageroutes) findPartnersRaw(ctx *gin.Context) {
 		entity = "partners"
 	)
 
-	partners, err := r.spartner.Read()
+	partners, err := r.spartner.Read(ctx)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
@@ -845,7 +1208,7 @@ func (r *manageroutes) updatePartner(ctx *gin.Context) {
 		return
 	}
 
-	_, err = r.spartner.GetByID(uuid)
+	_, err = r.spartner.GetByID(ctx, uuid)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
@@ -864,7 +1227,7 @@ func (r *manageroutes) updatePartner(ctx *gin.Context) {
 		return
 	}
 
-	respartner, err := r.spartner.Update(uuid, req)
+	respartner, err := r.spartner.Update(ctx, uuid, req)
 	if err != nil {
 		utresponse.GeneralInternalServerError(
 			function,
@@ -899,7 +1262,7 @@ func (r *manageroutes) updatePartner(ctx *gin.Context) {
 			return
 		}
 
-		err = r.sfile.UploadProfilePicture(respartner.User.ID, multipart)
+		err = r.sfile.UploadProfilePicture(ctx, respartner.User.ID, multipart)
 		if err != nil {
 			utresponse.GeneralInternalServerError(
 				function,
@@ -933,7 +1296,7 @@ func (r *manageroutes) deletePartner(ctx *gin.Context) {
 		return
 	}
 
-	_, err = r.spartner.GetByID(uuid)
+	_, err = r.spartner.GetByID(ctx, uuid)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
@@ -952,7 +1315,7 @@ func (r *manageroutes) deletePartner(ctx *gin.Context) {
 		return
 	}
 
-	err = r.spartner.Delete(uuid)
+	err = r.spartner.Delete(ctx, uuid)
 	if err != nil {
 		utresponse.GeneralInternalServerError(
 			function,
@@ -996,7 +1359,7 @@ func (r *manageroutes) createPatron(ctx *gin.Context) {
 		return
 	}
 
-	respatron, err := r.spatron.Create(req)
+	respatron, err := r.spatron.Create(ctx, req)
 	if err != nil {
 		var pgerr *pgconn.PgError
 		if errors.As(err, &pgerr) {
@@ -1043,7 +1406,7 @@ func (r *manageroutes) createPatron(ctx *gin.Context) {
 			return
 		}
 
-		err = r.sfile.UploadProfilePicture(respatron.User.ID, multipart)
+		err = r.sfile.UploadProfilePicture(ctx, respatron.User.ID, multipart)
 		if err != nil {
 			utresponse.GeneralInternalServerError(
 				function,
@@ -1067,7 +1430,7 @@ func (r *manageroutes) findPatrons(ctx *gin.Context) {
 		reqpage = utrequest.GeneratePaginationFromRequest(ctx)
 	)
 
-	patrons, err := r.spatron.FindAll(reqpage)
+	patrons, err := r.spatron.FindAll(ctx, reqpage)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
@@ -1098,7 +1461,7 @@ func (r *manageroutes) findPatronsRaw(ctx *gin.Context) {
 		entity = "patrons"
 	)
 
-	patrons, err := r.spatron.Read()
+	patrons, err := r.spatron.Read(ctx)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
@@ -1153,7 +1516,7 @@ func (r *manageroutes) updatePatron(ctx *gin.Context) {
 		return
 	}
 
-	_, err = r.spatron.GetByID(uuid)
+	_, err = r.spatron.GetByID(ctx, uuid)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
@@ -1172,7 +1535,7 @@ func (r *manageroutes) updatePatron(ctx *gin.Context) {
 		return
 	}
 
-	respatron, err := r.spatron.Update(uuid, req)
+	respatron, err := r.spatron.Update(ctx, uuid, req)
 	if err != nil {
 		utresponse.GeneralInternalServerError(
 			function,
@@ -1207,7 +1570,7 @@ func (r *manageroutes) updatePatron(ctx *gin.Context) {
 			return
 		}
 
-		err = r.sfile.UploadProfilePicture(respatron.User.ID, multipart)
+		err = r.sfile.UploadProfilePicture(ctx, respatron.User.ID, multipart)
 		if err != nil {
 			utresponse.GeneralInternalServerError(
 				function,
@@ -1241,7 +1604,7 @@ func (r *manageroutes) deletePatron(ctx *gin.Context) {
 		return
 	}
 
-	_, err = r.spatron.GetByID(uuid)
+	_, err = r.spatron.GetByID(ctx, uuid)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
@@ -1260,7 +1623,7 @@ func (r *manageroutes) deletePatron(ctx *gin.Context) {
 		return
 	}
 
-	err = r.spatron.Delete(uuid)
+	err = r.spatron.Delete(ctx, uuid)
 	if err != nil {
 		utresponse.GeneralInternalServerError(
 			entity,
@@ -1304,7 +1667,7 @@ func (r *manageroutes) createIllness(ctx *gin.Context) {
 		return
 	}
 
-	resillness, err := r.sillness.Create(req)
+	resillness, err := r.sillness.Create(ctx, req)
 	if err != nil {
 		utresponse.GeneralInternalServerError(
 			function,
@@ -1327,7 +1690,7 @@ func (r *manageroutes) findIllnesses(ctx *gin.Context) {
 		reqpage = utrequest.GeneratePaginationFromRequest(ctx)
 	)
 
-	illnesses, err := r.sillness.FindAll(reqpage)
+	illnesses, err := r.sillness.FindAll(ctx, reqpage)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
@@ -1358,7 +1721,7 @@ func (r *manageroutes) findIllnessesRaw(ctx *gin.Context) {
 		entity = "illnesses"
 	)
 
-	illnesses, err := r.sillness.Read()
+	illnesses, err := r.sillness.Read(ctx)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
@@ -1413,7 +1776,7 @@ func (r *manageroutes) updateIllness(ctx *gin.Context) {
 		return
 	}
 
-	_, err = r.sillness.GetByID(uuid)
+	_, err = r.sillness.GetByID(ctx, uuid)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
@@ -1432,7 +1795,7 @@ func (r *manageroutes) updateIllness(ctx *gin.Context) {
 		return
 	}
 
-	resillness, err := r.sillness.Update(uuid, req)
+	resillness, err := r.sillness.Update(ctx, uuid, req)
 	if err != nil {
 		utresponse.GeneralInternalServerError(
 			function,
@@ -1465,7 +1828,7 @@ func (r *manageroutes) deleteIllness(ctx *gin.Context) {
 		return
 	}
 
-	_, err = r.sillness.GetByID(uuid)
+	_, err = r.sillness.GetByID(ctx, uuid)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
@@ -1484,7 +1847,7 @@ func (r *manageroutes) deleteIllness(ctx *gin.Context) {
 		return
 	}
 
-	err = r.sillness.Delete(uuid)
+	err = r.sillness.Delete(ctx, uuid)
 	if err != nil {
 		utresponse.GeneralInternalServerError(
 			function,
@@ -1528,7 +1891,7 @@ func (r *manageroutes) createAllergy(ctx *gin.Context) {
 		return
 	}
 
-	resallergy, err := r.sallergy.Create(req)
+	resallergy, err := r.sallergy.Create(ctx, req)
 	if err != nil {
 		utresponse.GeneralInternalServerError(
 			function,
@@ -1551,7 +1914,7 @@ func (r *manageroutes) findAllergies(ctx *gin.Context) {
 		reqpage = utrequest.GeneratePaginationFromRequest(ctx)
 	)
 
-	allergies, err := r.sallergy.FindAll(reqpage)
+	allergies, err := r.sallergy.FindAll(ctx, reqpage)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
@@ -1582,7 +1945,7 @@ func (r *manageroutes) findAllergiesRaw(ctx *gin.Context) {
 		entity = "allergies"
 	)
 
-	allergies, err := r.sallergy.Read()
+	allergies, err := r.sallergy.Read(ctx)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
@@ -1637,7 +2000,7 @@ func (r *manageroutes) updateAllergy(ctx *gin.Context) {
 		return
 	}
 
-	_, err = r.sallergy.GetByID(uuid)
+	_, err = r.sallergy.GetByID(ctx, uuid)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
@@ -1656,7 +2019,7 @@ func (r *manageroutes) updateAllergy(ctx *gin.Context) {
 		return
 	}
 
-	resallergy, err := r.sallergy.Update(req, uuid)
+	resallergy, err := r.sallergy.Update(ctx, req, uuid)
 	if err != nil {
 		utresponse.GeneralInternalServerError(
 			function,
@@ -1689,7 +2052,7 @@ func (r *manageroutes) deleteAllergy(ctx *gin.Context) {
 		return
 	}
 
-	_, err = r.sallergy.GetByID(uuid)
+	_, err = r.sallergy.GetByID(ctx, uuid)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
@@ -1708,7 +2071,7 @@ func (r *manageroutes) deleteAllergy(ctx *gin.Context) {
 		return
 	}
 
-	err = r.sallergy.Delete(uuid)
+	err = r.sallergy.Delete(ctx, uuid)
 	if err != nil {
 		utresponse.GeneralInternalServerError(
 			function,
@@ -1739,7 +2102,7 @@ func (r *manageroutes) findDonations(ctx *gin.Context) {
 		reqpage = utrequest.GeneratePaginationFromRequest(ctx)
 	)
 
-	donations, err := r.sdonation.FindAll(reqpage)
+	donations, err := r.sdonation.FindAll(ctx, reqpage)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
@@ -1770,7 +2133,7 @@ func (r *manageroutes) findDonationsRaw(ctx *gin.Context) {
 		entity = "donations"
 	)
 
-	donations, err := r.sdonation.Read()
+	donations, err := r.sdonation.Read(ctx)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
@@ -1825,7 +2188,7 @@ func (r *manageroutes) updateDonation(ctx *gin.Context) {
 		return
 	}
 
-	_, err = r.sdonation.GetByID(uuid)
+	_, err = r.sdonation.GetByID(ctx, uuid)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
@@ -1844,7 +2207,7 @@ func (r *manageroutes) updateDonation(ctx *gin.Context) {
 		return
 	}
 
-	resdonation, err := r.sdonation.Update(req, uuid)
+	resdonation, err := r.sdonation.Update(ctx, req, uuid)
 	if err != nil {
 		utresponse.GeneralInternalServerError(
 			function,
@@ -1877,7 +2240,7 @@ func (r *manageroutes) deleteDonation(ctx *gin.Context) {
 		return
 	}
 
-	_, err = r.sdonation.GetByID(uuid)
+	_, err = r.sdonation.GetByID(ctx, uuid)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
@@ -1896,7 +2259,7 @@ func (r *manageroutes) deleteDonation(ctx *gin.Context) {
 		return
 	}
 
-	err = r.sdonation.Delete(uuid)
+	err = r.sdonation.Delete(ctx, uuid)
 	if err != nil {
 		utresponse.GeneralInternalServerError(
 			function,
@@ -1912,3 +2275,1132 @@ func (r *manageroutes) deleteDonation(ctx *gin.Context) {
 		nil,
 	)
 }
+
+// ! -------------------------------------------------------------------------- ! //
+// !                       end of donation routing group                        ! //
+// ! -------------------------------------------------------------------------- ! //
+
+// ! -------------------------------------------------------------------------- ! //
+// !                     start of permissions routing group                     ! //
+// ! -------------------------------------------------------------------------- ! //
+
+func parseRoleParam(ctx *gin.Context) (consttypes.UserRole, error) {
+	role, err := strconv.ParseUint(ctx.Param("role"), 10, 32)
+	if err != nil {
+		return 0, err
+	}
+
+	if !consttypes.UserRole(role).IsValid() {
+		return 0, consttypes.ErrUserInvalidRole
+	}
+
+	return consttypes.UserRole(role), nil
+}
+
+func (r *manageroutes) findRolePermissions(ctx *gin.Context) {
+	var (
+		entity = "role permissions"
+	)
+
+	rpreses, err := r.sperm.Read(ctx)
+	if err != nil {
+		utresponse.GeneralInternalServerError(
+			entity,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessFetch(
+		entity,
+		ctx,
+		rpreses,
+	)
+}
+
+func (r *manageroutes) getRolePermissions(ctx *gin.Context) {
+	var (
+		function = "get role permissions"
+		entity   = "role permissions"
+	)
+
+	role, err := parseRoleParam(ctx)
+	if err != nil {
+		utresponse.GeneralInputRequiredError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	rpres, err := r.sperm.GetByRole(ctx, role)
+	if err != nil {
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessFetch(
+		entity,
+		ctx,
+		rpres,
+	)
+}
+
+func (r *manageroutes) updateRolePermissions(ctx *gin.Context) {
+	var (
+		function = "update role permissions"
+		entity   = "role permissions"
+		req      requests.UpdateRolePermissions
+	)
+
+	err := ctx.ShouldBind(&req)
+	if err != nil {
+		ve := utresponse.ValidationResponse(err)
+		utresponse.GeneralInvalidRequest(
+			function,
+			ctx,
+			ve,
+			err,
+		)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		utresponse.GeneralInvalidRequest(
+			function,
+			ctx,
+			nil,
+			err,
+		)
+		return
+	}
+
+	role, err := parseRoleParam(ctx)
+	if err != nil {
+		utresponse.GeneralInputRequiredError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	rpres, err := r.sperm.UpdateByRole(ctx, role, req)
+	if err != nil {
+		utresponse.GeneralFailedUpdate(
+			entity,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessUpdate(
+		entity,
+		ctx,
+		rpres,
+	)
+}
+
+// ! -------------------------------------------------------------------------- ! //
+// !                      end of permissions routing group                      ! //
+// ! -------------------------------------------------------------------------- ! //
+
+// ! -------------------------------------------------------------------------- ! //
+// !                        start of users routing group                        ! //
+// ! -------------------------------------------------------------------------- ! //
+func (r *manageroutes) createUserDataErasure(ctx *gin.Context) {
+	var (
+		function = "create user data erasure"
+		entity   = "data erasure"
+	)
+
+	uid, err := uuid.Parse(ctx.Param("uid"))
+	if err != nil {
+		utresponse.GeneralInputRequiredError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	userres, err := uttoken.GetUser(ctx)
+	if err != nil {
+		utresponse.GeneralUnauthorized(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	deres, err := r.sprvc.CreateDataErasure(ctx, uid, userres.ID)
+	if err != nil {
+		if errors.Is(err, consttypes.ErrUserNotFound) {
+			utresponse.GeneralNotFound(
+				"user",
+				ctx,
+				err,
+			)
+			return
+		}
+
+		if errors.Is(err, consttypes.ErrDataErasureAlreadyRequested) {
+			utresponse.GeneralDuplicate(
+				entity,
+				ctx,
+				err,
+			)
+			return
+		}
+
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessCreate(
+		entity,
+		ctx,
+		deres,
+	)
+}
+
+func (r *manageroutes) findUserDataErasures(ctx *gin.Context) {
+	var (
+		function = "find user data erasures"
+		entity   = "data erasures"
+	)
+
+	uid, err := uuid.Parse(ctx.Param("uid"))
+	if err != nil {
+		utresponse.GeneralInputRequiredError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	dereses, err := r.sprvc.FindDataErasuresByUserID(ctx, uid)
+	if err != nil {
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessFetch(
+		entity,
+		ctx,
+		dereses,
+	)
+}
+
+func (r *manageroutes) findUserSecurityEvents(ctx *gin.Context) {
+	var (
+		function = "find user security events"
+		entity   = "security events"
+		reqpage  = utrequest.GeneratePaginationFromRequest(ctx)
+	)
+
+	uid, err := uuid.Parse(ctx.Param("uid"))
+	if err != nil {
+		utresponse.GeneralInputRequiredError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	seses, err := r.ssecu.FindEventsByUserID(ctx, uid, reqpage)
+	if err != nil {
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessFetch(
+		entity,
+		ctx,
+		seses,
+	)
+}
+
+// ! -------------------------------------------------------------------------- ! //
+// !                         end of users routing group                         ! //
+// ! -------------------------------------------------------------------------- ! //
+
+// ! -------------------------------------------------------------------------- ! //
+// !                   start of service accounts routing group                  ! //
+// ! -------------------------------------------------------------------------- ! //
+func (r *manageroutes) createServiceAccount(ctx *gin.Context) {
+	var (
+		function = "create service account"
+		entity   = "service account"
+		req      requests.CreateServiceAccount
+	)
+
+	err := ctx.ShouldBind(&req)
+	if err != nil {
+		ve := utresponse.ValidationResponse(err)
+		utresponse.GeneralInvalidRequest(
+			function,
+			ctx,
+			ve,
+			err,
+		)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		utresponse.GeneralInvalidRequest(
+			function,
+			ctx,
+			nil,
+			err,
+		)
+		return
+	}
+
+	userres, err := uttoken.GetUser(ctx)
+	if err != nil {
+		utresponse.GeneralUnauthorized(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	sares, err := r.ssvac.Create(ctx, req, userres.ID)
+	if err != nil {
+		if errors.Is(err, consttypes.ErrUserNotFound) {
+			utresponse.GeneralNotFound(
+				"user",
+				ctx,
+				err,
+			)
+			return
+		}
+
+		if errors.Is(err, consttypes.ErrServiceAccountOwnerNotAllowed) || errors.Is(err, consttypes.ErrScopeNotGranted) {
+			utresponse.GeneralInvalidRequest(
+				function,
+				ctx,
+				nil,
+				err,
+			)
+			return
+		}
+
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessCreate(
+		entity,
+		ctx,
+		sares,
+	)
+}
+
+func (r *manageroutes) findServiceAccounts(ctx *gin.Context) {
+	var (
+		function = "find service accounts"
+		entity   = "service accounts"
+	)
+
+	sasres, err := r.ssvac.Read(ctx)
+	if err != nil {
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessFetch(
+		entity,
+		ctx,
+		sasres,
+	)
+}
+
+func (r *manageroutes) getServiceAccount(ctx *gin.Context) {
+	var (
+		function = "get service account"
+		entity   = "service account"
+	)
+
+	said, err := uuid.Parse(ctx.Param("said"))
+	if err != nil {
+		utresponse.GeneralInputRequiredError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	sares, err := r.ssvac.GetByID(ctx, said)
+	if err != nil {
+		if errors.Is(err, consttypes.ErrServiceAccountNotFound) {
+			utresponse.GeneralNotFound(
+				entity,
+				ctx,
+				err,
+			)
+			return
+		}
+
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessFetch(
+		entity,
+		ctx,
+		sares,
+	)
+}
+
+func (r *manageroutes) updateServiceAccount(ctx *gin.Context) {
+	var (
+		function = "update service account"
+		entity   = "service account"
+		req      requests.UpdateServiceAccount
+	)
+
+	said, err := uuid.Parse(ctx.Param("said"))
+	if err != nil {
+		utresponse.GeneralInputRequiredError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	err = ctx.ShouldBind(&req)
+	if err != nil {
+		ve := utresponse.ValidationResponse(err)
+		utresponse.GeneralInvalidRequest(
+			function,
+			ctx,
+			ve,
+			err,
+		)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		utresponse.GeneralInvalidRequest(
+			function,
+			ctx,
+			nil,
+			err,
+		)
+		return
+	}
+
+	sares, err := r.ssvac.Update(ctx, said, req)
+	if err != nil {
+		if errors.Is(err, consttypes.ErrServiceAccountNotFound) {
+			utresponse.GeneralNotFound(
+				entity,
+				ctx,
+				err,
+			)
+			return
+		}
+
+		if errors.Is(err, consttypes.ErrServiceAccountOwnerNotAllowed) || errors.Is(err, consttypes.ErrScopeNotGranted) {
+			utresponse.GeneralInvalidRequest(
+				function,
+				ctx,
+				nil,
+				err,
+			)
+			return
+		}
+
+		utresponse.GeneralFailedUpdate(
+			entity,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessUpdate(
+		entity,
+		ctx,
+		sares,
+	)
+}
+
+func (r *manageroutes) deleteServiceAccount(ctx *gin.Context) {
+	var (
+		function = "delete service account"
+		entity   = "service account"
+	)
+
+	said, err := uuid.Parse(ctx.Param("said"))
+	if err != nil {
+		utresponse.GeneralInputRequiredError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	err = r.ssvac.Delete(ctx, said)
+	if err != nil {
+		if errors.Is(err, consttypes.ErrServiceAccountNotFound) {
+			utresponse.GeneralNotFound(
+				entity,
+				ctx,
+				err,
+			)
+			return
+		}
+
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessDelete(
+		entity,
+		ctx,
+		nil,
+	)
+}
+
+func (r *manageroutes) createAPIKey(ctx *gin.Context) {
+	var (
+		function = "create api key"
+		entity   = "api key"
+		req      requests.CreateAPIKey
+	)
+
+	said, err := uuid.Parse(ctx.Param("said"))
+	if err != nil {
+		utresponse.GeneralInputRequiredError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	err = ctx.ShouldBind(&req)
+	if err != nil {
+		ve := utresponse.ValidationResponse(err)
+		utresponse.GeneralInvalidRequest(
+			function,
+			ctx,
+			ve,
+			err,
+		)
+		return
+	}
+
+	akres, err := r.ssvac.CreateAPIKey(ctx, said, req, ctx.ClientIP(), ctx.Request.UserAgent())
+	if err != nil {
+		if errors.Is(err, consttypes.ErrServiceAccountNotFound) {
+			utresponse.GeneralNotFound(
+				"service account",
+				ctx,
+				err,
+			)
+			return
+		}
+
+		if errors.Is(err, consttypes.ErrServiceAccountDisabled) || errors.Is(err, consttypes.ErrAPIKeyLifeTooLong) {
+			utresponse.GeneralInvalidRequest(
+				function,
+				ctx,
+				nil,
+				err,
+			)
+			return
+		}
+
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessCreate(
+		entity,
+		ctx,
+		akres,
+	)
+}
+
+func (r *manageroutes) findAPIKeys(ctx *gin.Context) {
+	var (
+		function = "find api keys"
+		entity   = "api keys"
+	)
+
+	said, err := uuid.Parse(ctx.Param("said"))
+	if err != nil {
+		utresponse.GeneralInputRequiredError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	aksres, err := r.ssvac.FindAPIKeys(ctx, said)
+	if err != nil {
+		if errors.Is(err, consttypes.ErrServiceAccountNotFound) {
+			utresponse.GeneralNotFound(
+				"service account",
+				ctx,
+				err,
+			)
+			return
+		}
+
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessFetch(
+		entity,
+		ctx,
+		aksres,
+	)
+}
+
+func (r *manageroutes) revokeAPIKey(ctx *gin.Context) {
+	var (
+		function = "revoke api key"
+		entity   = "api key"
+	)
+
+	said, err := uuid.Parse(ctx.Param("said"))
+	if err != nil {
+		utresponse.GeneralInputRequiredError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	kid, err := uuid.Parse(ctx.Param("kid"))
+	if err != nil {
+		utresponse.GeneralInputRequiredError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	err = r.ssvac.RevokeAPIKey(ctx, said, kid, ctx.ClientIP(), ctx.Request.UserAgent())
+	if err != nil {
+		if errors.Is(err, consttypes.ErrServiceAccountNotFound) || errors.Is(err, consttypes.ErrAPIKeyNotFound) {
+			utresponse.GeneralNotFound(
+				entity,
+				ctx,
+				err,
+			)
+			return
+		}
+
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessDelete(
+		entity,
+		ctx,
+		nil,
+	)
+}
+
+// ! -------------------------------------------------------------------------- ! //
+// !                    end of service accounts routing group                   ! //
+// ! -------------------------------------------------------------------------- ! //
+
+// ! -------------------------------------------------------------------------- ! //
+// !                      start of audit logs routing group                     ! //
+// ! -------------------------------------------------------------------------- ! //
+func (r *manageroutes) findAuditLogs(ctx *gin.Context) {
+	var (
+		function = "find audit logs"
+		entity   = "audit logs"
+		reqpage  = utrequest.GeneratePaginationFromRequest(ctx)
+	)
+
+	alsres, err := r.saudt.FindAll(ctx, reqpage)
+	if err != nil {
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessFetch(
+		entity,
+		ctx,
+		alsres,
+	)
+}
+
+func (r *manageroutes) getAuditLog(ctx *gin.Context) {
+	var (
+		function = "get audit log"
+		entity   = "audit log"
+	)
+
+	alid, err := uuid.Parse(ctx.Param("alid"))
+	if err != nil {
+		utresponse.GeneralInputRequiredError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	alres, err := r.saudt.GetByID(ctx, alid)
+	if err != nil {
+		if errors.Is(err, consttypes.ErrAuditLogNotFound) {
+			utresponse.GeneralNotFound(
+				entity,
+				ctx,
+				err,
+			)
+			return
+		}
+
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessFetch(
+		entity,
+		ctx,
+		alres,
+	)
+}
+
+// * the export takes the same filters as the list, without the paging
+func (r *manageroutes) exportAuditLogs(ctx *gin.Context) {
+	var (
+		function = "export audit logs"
+		reqpage  = utrequest.GeneratePaginationFromRequest(ctx)
+	)
+
+	export, err := r.saudt.Export(ctx, reqpage.Filter)
+	if err != nil {
+		if errors.Is(err, consttypes.ErrAuditExportLimitExceeded) {
+			utresponse.GeneralInvalidRequest(
+				function,
+				ctx,
+				nil,
+				err,
+			)
+			return
+		}
+
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="audit-logs-%s.csv"`, consttypes.TimeNow().Format(consttypes.DATEFORMAT)))
+	ctx.Data(
+		http.StatusOK,
+		"text/csv",
+		export,
+	)
+}
+
+// ! -------------------------------------------------------------------------- ! //
+// !                       end of audit logs routing group                      ! //
+// ! -------------------------------------------------------------------------- ! //
+
+// ! -------------------------------------------------------------------------- ! //
+// !                      start of analytics routing group                      ! //
+// ! -------------------------------------------------------------------------- ! //
+func (r *manageroutes) analyticsOrders(ctx *gin.Context) {
+	var (
+		function = "get orders analytics"
+		entity   = "orders analytics"
+		req      requests.AnalyticsRange
+	)
+
+	err := ctx.ShouldBindQuery(&req)
+	if err != nil {
+		ve := utresponse.ValidationResponse(err)
+		utresponse.GeneralInvalidRequest(
+			function,
+			ctx,
+			ve,
+			err,
+		)
+		return
+	}
+
+	resords, err := r.sanly.Orders(ctx, req)
+	if err != nil {
+		if errors.Is(err, consttypes.ErrAnalyticsRangeInvalid) || errors.Is(err, consttypes.ErrAnalyticsRangeTooLong) {
+			utresponse.GeneralInvalidRequest(
+				function,
+				ctx,
+				nil,
+				err,
+			)
+			return
+		}
+
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessFetch(
+		entity,
+		ctx,
+		resords,
+	)
+}
+
+func (r *manageroutes) analyticsCancellations(ctx *gin.Context) {
+	var (
+		function = "get cancellations analytics"
+		entity   = "cancellations analytics"
+		req      requests.AnalyticsRange
+	)
+
+	err := ctx.ShouldBindQuery(&req)
+	if err != nil {
+		ve := utresponse.ValidationResponse(err)
+		utresponse.GeneralInvalidRequest(
+			function,
+			ctx,
+			ve,
+			err,
+		)
+		return
+	}
+
+	rescanc, err := r.sanly.Cancellations(ctx, req)
+	if err != nil {
+		if errors.Is(err, consttypes.ErrAnalyticsRangeInvalid) || errors.Is(err, consttypes.ErrAnalyticsRangeTooLong) {
+			utresponse.GeneralInvalidRequest(
+				function,
+				ctx,
+				nil,
+				err,
+			)
+			return
+		}
+
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessFetch(
+		entity,
+		ctx,
+		rescanc,
+	)
+}
+
+func (r *manageroutes) analyticsPrepTime(ctx *gin.Context) {
+	var (
+		function = "get prep time analytics"
+		entity   = "prep time analytics"
+		req      requests.AnalyticsRange
+	)
+
+	err := ctx.ShouldBindQuery(&req)
+	if err != nil {
+		ve := utresponse.ValidationResponse(err)
+		utresponse.GeneralInvalidRequest(
+			function,
+			ctx,
+			ve,
+			err,
+		)
+		return
+	}
+
+	resprep, err := r.sanly.PrepTime(ctx, req)
+	if err != nil {
+		if errors.Is(err, consttypes.ErrAnalyticsRangeInvalid) || errors.Is(err, consttypes.ErrAnalyticsRangeTooLong) {
+			utresponse.GeneralInvalidRequest(
+				function,
+				ctx,
+				nil,
+				err,
+			)
+			return
+		}
+
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessFetch(
+		entity,
+		ctx,
+		resprep,
+	)
+}
+
+func (r *manageroutes) analyticsTopMeals(ctx *gin.Context) {
+	var (
+		function = "get top meals analytics"
+		entity   = "top meals analytics"
+		req      requests.AnalyticsRange
+	)
+
+	err := ctx.ShouldBindQuery(&req)
+	if err != nil {
+		ve := utresponse.ValidationResponse(err)
+		utresponse.GeneralInvalidRequest(
+			function,
+			ctx,
+			ve,
+			err,
+		)
+		return
+	}
+
+	resmeals, err := r.sanly.TopMeals(ctx, req)
+	if err != nil {
+		if errors.Is(err, consttypes.ErrAnalyticsRangeInvalid) || errors.Is(err, consttypes.ErrAnalyticsRangeTooLong) {
+			utresponse.GeneralInvalidRequest(
+				function,
+				ctx,
+				nil,
+				err,
+			)
+			return
+		}
+
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessFetch(
+		entity,
+		ctx,
+		resmeals,
+	)
+}
+
+func (r *manageroutes) analyticsTopPartners(ctx *gin.Context) {
+	var (
+		function = "get top partners analytics"
+		entity   = "top partners analytics"
+		req      requests.AnalyticsRange
+	)
+
+	err := ctx.ShouldBindQuery(&req)
+	if err != nil {
+		ve := utresponse.ValidationResponse(err)
+		utresponse.GeneralInvalidRequest(
+			function,
+			ctx,
+			ve,
+			err,
+		)
+		return
+	}
+
+	respart, err := r.sanly.TopPartners(ctx, req)
+	if err != nil {
+		if errors.Is(err, consttypes.ErrAnalyticsRangeInvalid) || errors.Is(err, consttypes.ErrAnalyticsRangeTooLong) {
+			utresponse.GeneralInvalidRequest(
+				function,
+				ctx,
+				nil,
+				err,
+			)
+			return
+		}
+
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessFetch(
+		entity,
+		ctx,
+		respart,
+	)
+}
+
+func (r *manageroutes) analyticsActiveMembers(ctx *gin.Context) {
+	var (
+		function = "get active members analytics"
+		entity   = "active members analytics"
+		req      requests.AnalyticsRange
+	)
+
+	err := ctx.ShouldBindQuery(&req)
+	if err != nil {
+		ve := utresponse.ValidationResponse(err)
+		utresponse.GeneralInvalidRequest(
+			function,
+			ctx,
+			ve,
+			err,
+		)
+		return
+	}
+
+	resmemb, err := r.sanly.ActiveMembers(ctx, req)
+	if err != nil {
+		if errors.Is(err, consttypes.ErrAnalyticsRangeInvalid) || errors.Is(err, consttypes.ErrAnalyticsRangeTooLong) {
+			utresponse.GeneralInvalidRequest(
+				function,
+				ctx,
+				nil,
+				err,
+			)
+			return
+		}
+
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessFetch(
+		entity,
+		ctx,
+		resmemb,
+	)
+}
+
+func (r *manageroutes) analyticsDonations(ctx *gin.Context) {
+	var (
+		function = "get donations analytics"
+		entity   = "donations analytics"
+		req      requests.AnalyticsRange
+	)
+
+	err := ctx.ShouldBindQuery(&req)
+	if err != nil {
+		ve := utresponse.ValidationResponse(err)
+		utresponse.GeneralInvalidRequest(
+			function,
+			ctx,
+			ve,
+			err,
+		)
+		return
+	}
+
+	resdona, err := r.sanly.Donations(ctx, req)
+	if err != nil {
+		if errors.Is(err, consttypes.ErrAnalyticsRangeInvalid) || errors.Is(err, consttypes.ErrAnalyticsRangeTooLong) {
+			utresponse.GeneralInvalidRequest(
+				function,
+				ctx,
+				nil,
+				err,
+			)
+			return
+		}
+
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessFetch(
+		entity,
+		ctx,
+		resdona,
+	)
+}
+
+// ! -------------------------------------------------------------------------- ! //
+// !                       end of analytics routing group                       ! //
+// ! -------------------------------------------------------------------------- ! //
@@ -1,9 +1,13 @@
 package controllers
 
 import (
+	"errors"
 	"project-skbackend/configs"
+	"project-skbackend/internal/controllers/requests"
 	"project-skbackend/internal/services/mealcategoryservice"
 	"project-skbackend/internal/services/mealservice"
+	"project-skbackend/internal/services/searchservice"
+	"project-skbackend/packages/consttypes"
 	"project-skbackend/packages/utils/utrequest"
 	"project-skbackend/packages/utils/utresponse"
 
@@ -17,6 +21,7 @@ type (
 		cfg   *configs.Config
 		smeal mealservice.IMealService
 		smcat mealcategoryservice.IMealCategoryService
+		ssrch searchservice.ISearchService
 	}
 )
 
@@ -25,17 +30,20 @@ func newMealRoutes(
 	cfg *configs.Config,
 	smeal mealservice.IMealService,
 	smcat mealcategoryservice.IMealCategoryService,
+	ssrch searchservice.ISearchService,
 ) {
 	r := &mealroutes{
 		cfg:   cfg,
 		smeal: smeal,
 		smcat: smcat,
+		ssrch: ssrch,
 	}
 
 	gmealpub := rg.Group("meals")
 	{
 		gmealpub.GET("", r.findMeals)
 		gmealpub.GET("raw", r.findMealsRaw)
+		gmealpub.GET("search", r.searchMeals)
 		gmealpub.GET(":mid", r.getMeal)
 
 		gmcatpub := gmealpub.Group("categories")
@@ -53,7 +61,7 @@ func (r *mealroutes) findMeals(ctx *gin.Context) {
 		reqpage = utrequest.GeneratePaginationFromRequest(ctx)
 	)
 
-	meals, err := r.smeal.FindAll(reqpage)
+	meals, err := r.smeal.FindAll(ctx, reqpage)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
@@ -84,7 +92,7 @@ func (r *mealroutes) findMealsRaw(ctx *gin.Context) {
 		entity = "meals"
 	)
 
-	meals, err := r.smeal.Read()
+	meals, err := r.smeal.Read(ctx)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
@@ -110,6 +118,52 @@ func (r *mealroutes) findMealsRaw(ctx *gin.Context) {
 	)
 }
 
+func (r *mealroutes) searchMeals(ctx *gin.Context) {
+	var (
+		function = "search meals"
+		entity   = "meals"
+		req      requests.MealSearch
+	)
+
+	err := ctx.ShouldBindQuery(&req)
+	if err != nil {
+		ve := utresponse.ValidationResponse(err)
+		utresponse.GeneralInvalidRequest(
+			function,
+			ctx,
+			ve,
+			err,
+		)
+		return
+	}
+
+	ressrch, err := r.ssrch.SearchMeals(ctx, req)
+	if err != nil {
+		if errors.Is(err, consttypes.ErrSearchInvalidID) {
+			utresponse.GeneralInvalidRequest(
+				function,
+				ctx,
+				nil,
+				err,
+			)
+			return
+		}
+
+		utresponse.GeneralInternalServerError(
+			entity,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessFetch(
+		entity,
+		ctx,
+		ressrch,
+	)
+}
+
 func (r *mealroutes) getMeal(ctx *gin.Context) {
 	var (
 		function = "get meal"
@@ -126,7 +180,7 @@ func (r *mealroutes) getMeal(ctx *gin.Context) {
 		return
 	}
 
-	resmeal, err := r.smeal.GetByID(uuid)
+	resmeal, err := r.smeal.GetByID(ctx, uuid)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
@@ -158,7 +212,7 @@ func (r *mealroutes) findMealCategories(ctx *gin.Context) {
 		reqpage = utrequest.GeneratePaginationFromRequest(ctx)
 	)
 
-	mcats, err := r.smcat.FindAll(reqpage)
+	mcats, err := r.smcat.FindAll(ctx, reqpage)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
@@ -189,7 +243,7 @@ func (r *mealroutes) findMealCategoriesRaw(ctx *gin.Context) {
 		entity = "meal categories"
 	)
 
-	mcats, err := r.smcat.Read()
+	mcats, err := r.smcat.Read(ctx)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
@@ -231,7 +285,7 @@ func (r *mealroutes) getMealCategories(ctx *gin.Context) {
 		return
 	}
 
-	resmc, err := r.smcat.GetByID(uuid)
+	resmc, err := r.smcat.GetByID(ctx, uuid)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
@@ -12,6 +12,9 @@ import (
 	"project-skbackend/internal/services/fileservice"
 	"project-skbackend/internal/services/memberservice"
 	"project-skbackend/internal/services/orderservice"
+	"project-skbackend/internal/services/permissionservice"
+	"project-skbackend/internal/services/securityservice"
+	"project-skbackend/internal/services/sessionservice"
 	"project-skbackend/internal/services/userservice"
 	"project-skbackend/packages/consttypes"
 	"project-skbackend/packages/utils/utresponse"
@@ -35,6 +38,9 @@ type (
 		sfile   fileservice.IFileService
 		sbase   baseroleservice.IBaseRoleService
 		scare   caregiverservice.ICaregiverService
+		sperm   permissionservice.IPermissionService
+		ssess   sessionservice.ISessionService
+		ssecu   securityservice.ISecurityService
 	}
 )
 
@@ -49,6 +55,9 @@ func newMemberRoutes(
 	sfile fileservice.IFileService,
 	sbase baseroleservice.IBaseRoleService,
 	scare caregiverservice.ICaregiverService,
+	sperm permissionservice.IPermissionService,
+	ssess sessionservice.ISessionService,
+	ssecu securityservice.ISecurityService,
 ) {
 	r := &memberroutes{
 		cfg:     cfg,
@@ -60,38 +69,63 @@ func newMemberRoutes(
 		sfile:   sfile,
 		sbase:   sbase,
 		scare:   scare,
+		sperm:   sperm,
+		ssess:   ssess,
+		ssecu:   ssecu,
 	}
 
 	gmemberspub := rg.Group("members")
 	{
-		gmemberspub.POST("register", r.memberRegister)
+		gmemberspub.POST("register", middlewares.ThrottleMiddleware(ssecu, consttypes.TA_REGISTER), r.memberRegister)
 	}
 
 	gmemberspvt := rg.Group("members")
-	gmemberspvt.Use(middlewares.JWTAuthMiddleware(cfg, consttypes.UR_MEMBER))
+	gmemberspvt.Use(middlewares.JWTAuthMiddleware(cfg, ssess))
 	{
 		gcart := gmemberspvt.Group("carts")
 		{
-			gcart.POST("", r.memberCreateCart)
-			gcart.PATCH(":cid", r.memberUpdateCart)
-			gcart.DELETE(":cid", r.memberDeleteCart)
+			gcart.POST("", middlewares.PermissionMiddleware(sperm, consttypes.P_CART_CREATE), r.memberCreateCart)
+			gcart.PATCH(":cid", middlewares.PermissionMiddleware(sperm, consttypes.P_CART_UPDATE), r.memberUpdateCart)
+			gcart.DELETE(":cid", middlewares.PermissionMiddleware(sperm, consttypes.P_CART_DELETE), r.memberDeleteCart)
 		}
 
 		gorder := gmemberspvt.Group("orders")
 		{
-			gorder.POST("", r.memberCreateOrder)
-			gorder.GET("remaining", r.memberGetRemainingOrder)
+			gorder.POST("", middlewares.PermissionMiddleware(sperm, consttypes.P_ORDER_CREATE), r.memberCreateOrder)
+			gorder.GET("remaining", middlewares.PermissionMiddleware(sperm, consttypes.P_ORDER_READ), r.memberGetRemainingOrder)
 		}
 
 		gcare := gmemberspvt.Group("caregivers")
 		{
-			gcare.GET("own", r.memberGetOwnCaregiver)
-			gcare.PATCH("", r.memberUpdateOwnCaregiver)
-			gcare.PATCH("password", r.memberUpdateOwnCaregiverPassword)
+			gcare.GET("", middlewares.PermissionMiddleware(sperm, consttypes.P_CAREGIVER_READ), r.memberGetCaregivers)
+			gcare.PATCH(":cgid", middlewares.PermissionMiddleware(sperm, consttypes.P_CAREGIVER_UPDATE), r.memberUpdateCaregiver)
+			gcare.DELETE(":cgid", middlewares.PermissionMiddleware(sperm, consttypes.P_CAREGIVER_UPDATE), r.memberDeleteCaregiver)
+
+			ginvitation := gcare.Group("invitations")
+			{
+				ginvitation.POST("", middlewares.PermissionMiddleware(sperm, consttypes.P_CAREGIVER_UPDATE), r.memberCreateCaregiverInvitation)
+				ginvitation.GET("", middlewares.PermissionMiddleware(sperm, consttypes.P_CAREGIVER_READ), r.memberGetCaregiverInvitations)
+				ginvitation.DELETE(":ciid", middlewares.PermissionMiddleware(sperm, consttypes.P_CAREGIVER_UPDATE), r.memberRevokeCaregiverInvitation)
+			}
 		}
 	}
 }
 
+// * parse the optional member id a caregiver is acting for
+func parseMemberIDQuery(ctx *gin.Context) (*uuid.UUID, error) {
+	query := ctx.Query("member-id")
+	if query == "" {
+		return nil, nil
+	}
+
+	mid, err := uuid.Parse(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mid, nil
+}
+
 func (r *memberroutes) memberRegister(ctx *gin.Context) {
 	var (
 		function = "member register"
@@ -111,7 +145,16 @@ func (r *memberroutes) memberRegister(ctx *gin.Context) {
 		return
 	}
 
-	member, err := r.smember.Create(req)
+	err = r.ssecu.ThrottleAccount(ctx, consttypes.TA_REGISTER, req.ToSignin().Email, ctx.ClientIP())
+	if err != nil {
+		utresponse.GeneralTooManyRequests(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	member, err := r.smember.Create(ctx, req, nil)
 	if err != nil {
 		var pgerr *pgconn.PgError
 		if errors.As(err, &pgerr) {
@@ -158,7 +201,7 @@ func (r *memberroutes) memberRegister(ctx *gin.Context) {
 			return
 		}
 
-		err = r.sfile.UploadProfilePicture(member.User.ID, multipart)
+		err = r.sfile.UploadProfilePicture(ctx, member.User.ID, multipart)
 		if err != nil {
 			utresponse.GeneralInternalServerError(
 				function,
@@ -169,7 +212,7 @@ func (r *memberroutes) memberRegister(ctx *gin.Context) {
 		}
 	}
 
-	resuser, thead, err := r.sauth.Signin(*req.ToSignin(), ctx)
+	resuser, thead, tfch, err := r.sauth.Signin(*req.ToSignin(), ctx)
 	if err != nil {
 		utresponse.GeneralInternalServerError(
 			function,
@@ -179,7 +222,17 @@ func (r *memberroutes) memberRegister(ctx *gin.Context) {
 		return
 	}
 
-	err = r.sauth.SendVerificationEmail(resuser.ID)
+	// * the role requires two factor, the tokens are given after the enrolment
+	if tfch != nil {
+		utresponse.GeneralSuccessCreate(
+			entity,
+			ctx,
+			tfch,
+		)
+		return
+	}
+
+	err = r.sauth.SendVerificationEmail(ctx, resuser.ID)
 	if err != nil {
 		utresponse.GeneralInternalServerError(
 			function,
@@ -226,7 +279,7 @@ func (r *memberroutes) memberCreateCart(ctx *gin.Context) {
 		return
 	}
 
-	roleres, err := r.suser.GetRoleDataByUserID(userres.ID)
+	roleres, err := r.suser.GetRoleDataByUserID(ctx, userres.ID)
 	if err != nil {
 		utresponse.GeneralUnauthorized(
 			ctx,
@@ -244,8 +297,16 @@ func (r *memberroutes) memberCreateCart(ctx *gin.Context) {
 		return
 	}
 
-	rescart, err := r.scart.Create(*req, *roleres)
+	rescart, err := r.scart.Create(ctx, *req, *roleres)
 	if err != nil {
+		if errors.Is(err, consttypes.ErrCaregiverNotLinked) || errors.Is(err, consttypes.ErrCaregiverAccessDenied) {
+			utresponse.GeneralForbidden(
+				ctx,
+				err,
+			)
+			return
+		}
+
 		utresponse.GeneralInternalServerError(
 			function,
 			ctx,
@@ -299,7 +360,7 @@ func (r *memberroutes) memberUpdateCart(ctx *gin.Context) {
 		return
 	}
 
-	roleres, err := r.suser.GetRoleDataByUserID(userres.ID)
+	roleres, err := r.suser.GetRoleDataByUserID(ctx, userres.ID)
 	if err != nil {
 		utresponse.GeneralUnauthorized(
 			ctx,
@@ -317,7 +378,7 @@ func (r *memberroutes) memberUpdateCart(ctx *gin.Context) {
 		return
 	}
 
-	rescart, err := r.scart.Update(uuid, *req)
+	rescart, err := r.scart.Update(ctx, uuid, *req)
 	if err != nil {
 		utresponse.GeneralInternalServerError(
 			function,
@@ -362,8 +423,16 @@ func (r *memberroutes) memberCreateOrder(ctx *gin.Context) {
 		return
 	}
 
-	resorder, err := r.sorder.Create(*req, userres.ID)
+	resorder, err := r.sorder.Create(ctx, *req, userres.ID)
 	if err != nil {
+		if errors.Is(err, consttypes.ErrCaregiverNotLinked) || errors.Is(err, consttypes.ErrCaregiverAccessDenied) {
+			utresponse.GeneralForbidden(
+				ctx,
+				err,
+			)
+			return
+		}
+
 		utresponse.GeneralInternalServerError(
 			function,
 			ctx,
@@ -393,8 +462,27 @@ func (r *memberroutes) memberGetRemainingOrder(ctx *gin.Context) {
 		return
 	}
 
-	resremorder, err := r.sorder.GetMemberRemainingOrder(userres.ID)
+	mid, err := parseMemberIDQuery(ctx)
+	if err != nil {
+		utresponse.GeneralInvalidRequest(
+			entity,
+			ctx,
+			nil,
+			err,
+		)
+		return
+	}
+
+	resremorder, err := r.sorder.GetMemberRemainingOrder(ctx, userres.ID, mid)
 	if err != nil {
+		if errors.Is(err, consttypes.ErrCaregiverNotLinked) || errors.Is(err, consttypes.ErrCaregiverAccessDenied) {
+			utresponse.GeneralForbidden(
+				ctx,
+				err,
+			)
+			return
+		}
+
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
 				entity,
@@ -419,10 +507,44 @@ func (r *memberroutes) memberGetRemainingOrder(ctx *gin.Context) {
 	)
 }
 
-func (r *memberroutes) memberGetOwnCaregiver(ctx *gin.Context) {
+func (r *memberroutes) memberDeleteCart(ctx *gin.Context) {
+	var (
+		function = "delete cart"
+		entity   = "cart"
+		err      error
+	)
+
+	uuid, err := uuid.Parse(ctx.Param("cid"))
+	if err != nil {
+		utresponse.GeneralInputRequiredError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	err = r.scart.Delete(ctx, uuid)
+	if err != nil {
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessDelete(
+		entity,
+		ctx,
+		nil,
+	)
+}
+
+func (r *memberroutes) memberGetCaregivers(ctx *gin.Context) {
 	var (
-		function = "get own caregiver"
-		entity   = "own caregiver"
+		function = "get caregivers"
+		entity   = "caregivers"
 	)
 
 	userres, err := uttoken.GetUser(ctx)
@@ -434,7 +556,7 @@ func (r *memberroutes) memberGetOwnCaregiver(ctx *gin.Context) {
 		return
 	}
 
-	roleres, err := r.suser.GetRoleDataByUserID(userres.ID)
+	roleres, err := r.suser.GetRoleDataByUserID(ctx, userres.ID)
 	if err != nil {
 		utresponse.GeneralUnauthorized(
 			ctx,
@@ -452,7 +574,7 @@ func (r *memberroutes) memberGetOwnCaregiver(ctx *gin.Context) {
 		return
 	}
 
-	resmem, err := r.sbase.GetMemberByBaseRole(*roleres)
+	memres, err := r.sbase.GetMemberByBaseRole(ctx, *roleres, nil, consttypes.CA_VIEW)
 	if err != nil {
 		utresponse.GeneralInternalServerError(
 			function,
@@ -462,9 +584,91 @@ func (r *memberroutes) memberGetOwnCaregiver(ctx *gin.Context) {
 		return
 	}
 
-	rescare, err := r.scare.GetByID(*resmem.CaregiverID)
+	rescares, err := r.scare.FindByMemberID(ctx, memres.ID)
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessFetch(
+		entity,
+		ctx,
+		rescares,
+	)
+}
+
+func (r *memberroutes) memberUpdateCaregiver(ctx *gin.Context) {
+	var (
+		function = "update caregiver"
+		entity   = "caregiver"
+		req      *requests.UpdateMemberCaregiver
+	)
+
+	if err := ctx.ShouldBind(&req); err != nil {
+		ve := utresponse.ValidationResponse(err)
+		utresponse.GeneralInvalidRequest(
+			function,
+			ctx,
+			ve,
+			err,
+		)
+		return
+	}
+
+	cgid, err := uuid.Parse(ctx.Param("cgid"))
+	if err != nil {
+		utresponse.GeneralInputRequiredError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	userres, err := uttoken.GetUser(ctx)
+	if err != nil {
+		utresponse.GeneralUnauthorized(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	roleres, err := r.suser.GetRoleDataByUserID(ctx, userres.ID)
+	if err != nil {
+		utresponse.GeneralUnauthorized(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	if roleres == nil {
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			consttypes.ErrUserInvalidRole,
+		)
+		return
+	}
+
+	memres, err := r.sbase.GetMemberByBaseRole(ctx, *roleres, nil, consttypes.CA_VIEW)
+	if err != nil {
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	rescare, err := r.scare.UpdateLink(ctx, memres.ID, cgid, *req)
+	if err != nil {
+		if errors.Is(err, consttypes.ErrCaregiverNotLinked) {
 			utresponse.GeneralNotFound(
 				entity,
 				ctx,
@@ -474,28 +678,27 @@ func (r *memberroutes) memberGetOwnCaregiver(ctx *gin.Context) {
 		}
 
 		utresponse.GeneralInternalServerError(
-			entity,
+			function,
 			ctx,
 			err,
 		)
 		return
 	}
 
-	utresponse.GeneralSuccessFetch(
+	utresponse.GeneralSuccessUpdate(
 		entity,
 		ctx,
 		rescare,
 	)
 }
 
-func (r *memberroutes) memberDeleteCart(ctx *gin.Context) {
+func (r *memberroutes) memberDeleteCaregiver(ctx *gin.Context) {
 	var (
-		function = "delete cart"
-		entity   = "cart"
-		err      error
+		function = "delete caregiver"
+		entity   = "caregiver"
 	)
 
-	uuid, err := uuid.Parse(ctx.Param("cid"))
+	cgid, err := uuid.Parse(ctx.Param("cgid"))
 	if err != nil {
 		utresponse.GeneralInputRequiredError(
 			function,
@@ -505,8 +708,54 @@ func (r *memberroutes) memberDeleteCart(ctx *gin.Context) {
 		return
 	}
 
-	err = r.scart.Delete(uuid)
+	userres, err := uttoken.GetUser(ctx)
+	if err != nil {
+		utresponse.GeneralUnauthorized(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	roleres, err := r.suser.GetRoleDataByUserID(ctx, userres.ID)
+	if err != nil {
+		utresponse.GeneralUnauthorized(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	if roleres == nil {
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			consttypes.ErrUserInvalidRole,
+		)
+		return
+	}
+
+	memres, err := r.sbase.GetMemberByBaseRole(ctx, *roleres, nil, consttypes.CA_VIEW)
+	if err != nil {
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	err = r.scare.DeleteLink(ctx, memres.ID, cgid)
 	if err != nil {
+		if errors.Is(err, consttypes.ErrCaregiverNotLinked) {
+			utresponse.GeneralNotFound(
+				entity,
+				ctx,
+				err,
+			)
+			return
+		}
+
 		utresponse.GeneralInternalServerError(
 			function,
 			ctx,
@@ -522,35 +771,34 @@ func (r *memberroutes) memberDeleteCart(ctx *gin.Context) {
 	)
 }
 
-func (r *memberroutes) memberUpdateOwnCaregiver(ctx *gin.Context) {
+func (r *memberroutes) memberCreateCaregiverInvitation(ctx *gin.Context) {
 	var (
-		function = "update own caregiver"
-		entity   = "own caregiver"
-		req      *requests.UpdateCaregiver
-		err      error
+		function = "create caregiver invitation"
+		entity   = "caregiver invitation"
+		req      *requests.CreateCaregiverInvitation
 	)
 
-	userres, err := uttoken.GetUser(ctx)
-	if err != nil {
-		utresponse.GeneralUnauthorized(
+	if err := ctx.ShouldBind(&req); err != nil {
+		ve := utresponse.ValidationResponse(err)
+		utresponse.GeneralInvalidRequest(
+			function,
 			ctx,
+			ve,
 			err,
 		)
 		return
 	}
 
-	if err := ctx.ShouldBind(&req); err != nil {
-		ve := utresponse.ValidationResponse(err)
-		utresponse.GeneralInvalidRequest(
-			function,
+	userres, err := uttoken.GetUser(ctx)
+	if err != nil {
+		utresponse.GeneralUnauthorized(
 			ctx,
-			ve,
 			err,
 		)
 		return
 	}
 
-	roleres, err := r.suser.GetRoleDataByUserID(userres.ID)
+	roleres, err := r.suser.GetRoleDataByUserID(ctx, userres.ID)
 	if err != nil {
 		utresponse.GeneralUnauthorized(
 			ctx,
@@ -568,7 +816,7 @@ func (r *memberroutes) memberUpdateOwnCaregiver(ctx *gin.Context) {
 		return
 	}
 
-	memres, err := r.sbase.GetMemberByBaseRole(*roleres)
+	memres, err := r.sbase.GetMemberByBaseRole(ctx, *roleres, nil, consttypes.CA_VIEW)
 	if err != nil {
 		utresponse.GeneralInternalServerError(
 			function,
@@ -578,67 +826,67 @@ func (r *memberroutes) memberUpdateOwnCaregiver(ctx *gin.Context) {
 		return
 	}
 
-	rescare, err := r.smember.UpdateOwnCaregiver(memres.ID, *req)
+	resinv, err := r.scare.CreateInvitation(ctx, *memres, *req)
 	if err != nil {
-		var pgerr *pgconn.PgError
-		if errors.As(err, &pgerr) {
-			if pgerrcode.IsIntegrityConstraintViolation(pgerr.SQLState()) {
-				utresponse.GeneralDuplicate(
-					pgerr.TableName,
-					ctx,
-					pgerr,
-				)
-				return
-			}
-		} else {
-			utresponse.GeneralInternalServerError(
+		if errors.Is(err, consttypes.ErrCaregiverAlreadyLinked) || errors.Is(err, consttypes.ErrCaregiverInvitationAlreadySent) || errors.Is(err, consttypes.ErrCaregiverInvitationSelf) {
+			utresponse.GeneralInvalidRequest(
 				function,
 				ctx,
+				nil,
 				err,
 			)
+			return
 		}
+
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
 		return
 	}
 
-	// * define the image request
-	if req.User != nil {
-		reqimg := req.User.UpdateImage
-		// * if the image request is not empty
-		// * validate and upload the image
-		if reqimg != nil {
-			if err := reqimg.Validate(); err != nil {
-				utresponse.GeneralInvalidRequest(
-					function,
-					ctx,
-					nil,
-					err,
-				)
-				return
-			}
+	utresponse.GeneralSuccessCreate(
+		entity,
+		ctx,
+		resinv,
+	)
+}
 
-			multipart, err := reqimg.GetMultipartFile()
-			if err != nil {
-				utresponse.GeneralInternalServerError(
-					function,
-					ctx,
-					err,
-				)
-				return
-			}
+func (r *memberroutes) memberGetCaregiverInvitations(ctx *gin.Context) {
+	var (
+		function = "get caregiver invitations"
+		entity   = "caregiver invitations"
+	)
 
-			err = r.sfile.UploadProfilePicture(rescare.User.ID, multipart)
-			if err != nil {
-				utresponse.GeneralInternalServerError(
-					function,
-					ctx,
-					err,
-				)
-				return
-			}
-		}
+	userres, err := uttoken.GetUser(ctx)
+	if err != nil {
+		utresponse.GeneralUnauthorized(
+			ctx,
+			err,
+		)
+		return
 	}
 
-	rescare, err = r.scare.GetByID(rescare.ID)
+	roleres, err := r.suser.GetRoleDataByUserID(ctx, userres.ID)
+	if err != nil {
+		utresponse.GeneralUnauthorized(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	if roleres == nil {
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			consttypes.ErrUserInvalidRole,
+		)
+		return
+	}
+
+	memres, err := r.sbase.GetMemberByBaseRole(ctx, *roleres, nil, consttypes.CA_VIEW)
 	if err != nil {
 		utresponse.GeneralInternalServerError(
 			function,
@@ -648,42 +896,49 @@ func (r *memberroutes) memberUpdateOwnCaregiver(ctx *gin.Context) {
 		return
 	}
 
-	utresponse.GeneralSuccessUpdate(
+	resinvs, err := r.scare.FindInvitationsByMemberID(ctx, memres.ID)
+	if err != nil {
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessFetch(
 		entity,
 		ctx,
-		rescare,
+		resinvs,
 	)
 }
 
-func (r *memberroutes) memberUpdateOwnCaregiverPassword(ctx *gin.Context) {
+func (r *memberroutes) memberRevokeCaregiverInvitation(ctx *gin.Context) {
 	var (
-		function = "update own caregiver password"
-		entity   = "own caregiver password"
-		req      *requests.UpdatePassword
-		err      error
+		function = "revoke caregiver invitation"
+		entity   = "caregiver invitation"
 	)
 
-	userres, err := uttoken.GetUser(ctx)
+	ciid, err := uuid.Parse(ctx.Param("ciid"))
 	if err != nil {
-		utresponse.GeneralUnauthorized(
+		utresponse.GeneralInputRequiredError(
+			function,
 			ctx,
 			err,
 		)
 		return
 	}
 
-	if err := ctx.ShouldBind(&req); err != nil {
-		ve := utresponse.ValidationResponse(err)
-		utresponse.GeneralInvalidRequest(
-			function,
+	userres, err := uttoken.GetUser(ctx)
+	if err != nil {
+		utresponse.GeneralUnauthorized(
 			ctx,
-			ve,
 			err,
 		)
 		return
 	}
 
-	roleres, err := r.suser.GetRoleDataByUserID(userres.ID)
+	roleres, err := r.suser.GetRoleDataByUserID(ctx, userres.ID)
 	if err != nil {
 		utresponse.GeneralUnauthorized(
 			ctx,
@@ -701,7 +956,7 @@ func (r *memberroutes) memberUpdateOwnCaregiverPassword(ctx *gin.Context) {
 		return
 	}
 
-	memres, err := r.sbase.GetMemberByBaseRole(*roleres)
+	memres, err := r.sbase.GetMemberByBaseRole(ctx, *roleres, nil, consttypes.CA_VIEW)
 	if err != nil {
 		utresponse.GeneralInternalServerError(
 			function,
@@ -711,8 +966,27 @@ func (r *memberroutes) memberUpdateOwnCaregiverPassword(ctx *gin.Context) {
 		return
 	}
 
-	err = r.smember.UpdateOwnCaregiverPassword(memres.ID, *req)
+	err = r.scare.RevokeInvitation(ctx, memres.ID, ciid)
 	if err != nil {
+		if errors.Is(err, consttypes.ErrCaregiverInvitationNotFound) {
+			utresponse.GeneralNotFound(
+				entity,
+				ctx,
+				err,
+			)
+			return
+		}
+
+		if errors.Is(err, consttypes.ErrCaregiverInvitationNotPending) {
+			utresponse.GeneralInvalidRequest(
+				function,
+				ctx,
+				nil,
+				err,
+			)
+			return
+		}
+
 		utresponse.GeneralInternalServerError(
 			function,
 			ctx,
@@ -721,7 +995,7 @@ func (r *memberroutes) memberUpdateOwnCaregiverPassword(ctx *gin.Context) {
 		return
 	}
 
-	utresponse.GeneralSuccessUpdate(
+	utresponse.GeneralSuccessDelete(
 		entity,
 		ctx,
 		nil,
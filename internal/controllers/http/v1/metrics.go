@@ -0,0 +1,12 @@
+package controllers
+
+import (
+	"project-skbackend/packages/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// * the metrics are scraped by prometheus, they are not under the api
+func newMetricsRoutes(ge *gin.Engine) {
+	ge.GET("metrics", gin.WrapH(metrics.Handler()))
+}
@@ -0,0 +1,341 @@
+package controllers
+
+import (
+	"errors"
+	"project-skbackend/configs"
+	"project-skbackend/internal/controllers/requests"
+	"project-skbackend/internal/middlewares"
+	"project-skbackend/internal/services/authservice"
+	"project-skbackend/internal/services/identityservice"
+	"project-skbackend/internal/services/securityservice"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/utils/utresponse"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type (
+	oidcroutes struct {
+		cfg    *configs.Config
+		sauth  authservice.IAuthService
+		sident identityservice.IIdentityService
+		ssecu  securityservice.ISecurityService
+	}
+)
+
+func newOIDCRoutes(
+	rg *gin.RouterGroup,
+	cfg *configs.Config,
+	sauth authservice.IAuthService,
+	sident identityservice.IIdentityService,
+	ssecu securityservice.ISecurityService,
+) {
+	r := &oidcroutes{
+		cfg:    cfg,
+		sauth:  sauth,
+		sident: sident,
+		ssecu:  ssecu,
+	}
+
+	h := rg.Group("auth/oidc")
+	{
+		h.GET("authorize", r.authorize)
+		h.POST("callback", middlewares.ThrottleMiddleware(ssecu, consttypes.TA_SIGNIN), r.callback)
+
+		gregister := h.Group("register").Use(middlewares.ThrottleMiddleware(ssecu, consttypes.TA_REGISTER))
+		{
+			gregister.POST("members", r.registerMember)
+			gregister.POST("patrons", r.registerPatron)
+			gregister.POST("partners", r.registerPartner)
+		}
+	}
+}
+
+func (r *oidcroutes) authorize(ctx *gin.Context) {
+	var (
+		function = "oidc authorize"
+	)
+
+	resauthz, err := r.sident.Authorize(ctx)
+	if err != nil {
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccess(
+		function,
+		ctx,
+		resauthz,
+	)
+}
+
+// * signs in the user of the oidc account, or returns a signup token
+// * when the account has to be registered to one of the roles first
+func (r *oidcroutes) callback(ctx *gin.Context) {
+	var (
+		function = "oidc callback"
+		req      requests.OIDCCallback
+	)
+
+	err := ctx.ShouldBind(&req)
+	if err != nil {
+		ve := utresponse.ValidationResponse(err)
+		utresponse.GeneralInvalidRequest(
+			function,
+			ctx,
+			ve,
+			err,
+		)
+		return
+	}
+
+	user, ressignup, err := r.sident.Callback(ctx, req)
+	if err != nil {
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	if ressignup != nil {
+		utresponse.GeneralSuccess(
+			function,
+			ctx,
+			ressignup,
+		)
+		return
+	}
+
+	resuser, thead, tfch, err := r.sauth.SigninUser(*user, ctx)
+	if err != nil {
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	if tfch != nil {
+		utresponse.GeneralSuccess(
+			function,
+			ctx,
+			tfch,
+		)
+		return
+	}
+
+	resauth := thead.ToAuthResponse(*resuser)
+	utresponse.GeneralSuccessAuth(
+		function,
+		ctx,
+		resauth,
+		thead,
+	)
+}
+
+func (r *oidcroutes) registerMember(ctx *gin.Context) {
+	var (
+		function = "oidc register member"
+		req      requests.OIDCRegisterMember
+	)
+
+	err := ctx.ShouldBind(&req)
+	if err != nil {
+		ve := utresponse.ValidationResponse(err)
+		utresponse.GeneralInvalidRequest(
+			function,
+			ctx,
+			ve,
+			err,
+		)
+		return
+	}
+
+	user, err := r.sident.RegisterMember(ctx, req)
+	if err != nil {
+		var pgerr *pgconn.PgError
+		if errors.As(err, &pgerr) && pgerrcode.IsIntegrityConstraintViolation(pgerr.SQLState()) {
+			utresponse.GeneralDuplicate(
+				pgerr.TableName,
+				ctx,
+				pgerr,
+			)
+			return
+		}
+
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	resuser, thead, tfch, err := r.sauth.SigninUser(*user, ctx)
+	if err != nil {
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	if tfch != nil {
+		utresponse.GeneralSuccess(
+			function,
+			ctx,
+			tfch,
+		)
+		return
+	}
+
+	resauth := thead.ToAuthResponse(*resuser)
+	utresponse.GeneralSuccessAuth(
+		function,
+		ctx,
+		resauth,
+		thead,
+	)
+}
+
+func (r *oidcroutes) registerPatron(ctx *gin.Context) {
+	var (
+		function = "oidc register patron"
+		req      requests.OIDCRegisterPatron
+	)
+
+	err := ctx.ShouldBind(&req)
+	if err != nil {
+		ve := utresponse.ValidationResponse(err)
+		utresponse.GeneralInvalidRequest(
+			function,
+			ctx,
+			ve,
+			err,
+		)
+		return
+	}
+
+	user, err := r.sident.RegisterPatron(ctx, req)
+	if err != nil {
+		var pgerr *pgconn.PgError
+		if errors.As(err, &pgerr) && pgerrcode.IsIntegrityConstraintViolation(pgerr.SQLState()) {
+			utresponse.GeneralDuplicate(
+				pgerr.TableName,
+				ctx,
+				pgerr,
+			)
+			return
+		}
+
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	resuser, thead, tfch, err := r.sauth.SigninUser(*user, ctx)
+	if err != nil {
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	if tfch != nil {
+		utresponse.GeneralSuccess(
+			function,
+			ctx,
+			tfch,
+		)
+		return
+	}
+
+	resauth := thead.ToAuthResponse(*resuser)
+	utresponse.GeneralSuccessAuth(
+		function,
+		ctx,
+		resauth,
+		thead,
+	)
+}
+
+func (r *oidcroutes) registerPartner(ctx *gin.Context) {
+	var (
+		function = "oidc register partner"
+		req      requests.OIDCRegisterPartner
+	)
+
+	err := ctx.ShouldBind(&req)
+	if err != nil {
+		ve := utresponse.ValidationResponse(err)
+		utresponse.GeneralInvalidRequest(
+			function,
+			ctx,
+			ve,
+			err,
+		)
+		return
+	}
+
+	user, err := r.sident.RegisterPartner(ctx, req)
+	if err != nil {
+		var pgerr *pgconn.PgError
+		if errors.As(err, &pgerr) && pgerrcode.IsIntegrityConstraintViolation(pgerr.SQLState()) {
+			utresponse.GeneralDuplicate(
+				pgerr.TableName,
+				ctx,
+				pgerr,
+			)
+			return
+		}
+
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	resuser, thead, tfch, err := r.sauth.SigninUser(*user, ctx)
+	if err != nil {
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	if tfch != nil {
+		utresponse.GeneralSuccess(
+			function,
+			ctx,
+			tfch,
+		)
+		return
+	}
+
+	resauth := thead.ToAuthResponse(*resuser)
+	utresponse.GeneralSuccessAuth(
+		function,
+		ctx,
+		resauth,
+		thead,
+	)
+}
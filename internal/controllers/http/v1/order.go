@@ -1,9 +1,12 @@
 package controllers
 
 import (
+	"errors"
 	"project-skbackend/configs"
 	"project-skbackend/internal/middlewares"
 	"project-skbackend/internal/services/orderservice"
+	"project-skbackend/internal/services/permissionservice"
+	"project-skbackend/internal/services/sessionservice"
 	"project-skbackend/internal/services/userservice"
 	"project-skbackend/packages/consttypes"
 	"project-skbackend/packages/utils/utresponse"
@@ -17,6 +20,8 @@ type (
 		cfg   *configs.Config
 		sordr orderservice.IOrderService
 		suser userservice.IUserService
+		sperm permissionservice.IPermissionService
+		ssess sessionservice.ISessionService
 	}
 )
 
@@ -25,17 +30,21 @@ func newOrderRoutes(
 	cfg *configs.Config,
 	sordr orderservice.IOrderService,
 	suser userservice.IUserService,
+	sperm permissionservice.IPermissionService,
+	ssess sessionservice.ISessionService,
 ) {
 	r := &orderroutes{
 		cfg:   cfg,
 		sordr: sordr,
 		suser: suser,
+		sperm: sperm,
+		ssess: ssess,
 	}
 
 	gordrpvt := rg.Group("orders")
-	gordrpvt.Use(middlewares.JWTAuthMiddleware(cfg, consttypes.UR_MEMBER, consttypes.UR_CAREGIVER, consttypes.UR_PARTNER))
+	gordrpvt.Use(middlewares.JWTAuthMiddleware(cfg, ssess))
 	{
-		gordrpvt.GET("own", r.getOwnOrder)
+		gordrpvt.GET("own", middlewares.PermissionMiddleware(sperm, consttypes.P_ORDER_READ), r.getOwnOrder)
 	}
 }
 
@@ -55,7 +64,7 @@ func (r *orderroutes) getOwnOrder(ctx *gin.Context) {
 		return
 	}
 
-	roleres, err := r.suser.GetRoleDataByUserID(userres.ID)
+	roleres, err := r.suser.GetRoleDataByUserID(ctx, userres.ID)
 	if err != nil {
 		utresponse.GeneralUnauthorized(
 			ctx,
@@ -73,8 +82,27 @@ func (r *orderroutes) getOwnOrder(ctx *gin.Context) {
 		return
 	}
 
-	resorder, err := r.sordr.FindByRoleRes(*roleres)
+	mid, err := parseMemberIDQuery(ctx)
 	if err != nil {
+		utresponse.GeneralInvalidRequest(
+			function,
+			ctx,
+			nil,
+			err,
+		)
+		return
+	}
+
+	resorder, err := r.sordr.FindByRoleRes(ctx, *roleres, mid)
+	if err != nil {
+		if errors.Is(err, consttypes.ErrCaregiverNotLinked) || errors.Is(err, consttypes.ErrCaregiverAccessDenied) {
+			utresponse.GeneralForbidden(
+				ctx,
+				err,
+			)
+			return
+		}
+
 		utresponse.GeneralInternalServerError(
 			function,
 			ctx,
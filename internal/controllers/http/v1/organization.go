@@ -4,11 +4,23 @@ import (
 	"errors"
 	"project-skbackend/configs"
 	"project-skbackend/internal/controllers/requests"
+	"project-skbackend/internal/middlewares"
 	"project-skbackend/internal/services/authservice"
+	"project-skbackend/internal/services/baseroleservice"
+	"project-skbackend/internal/services/memberservice"
 	"project-skbackend/internal/services/organizationservice"
+	"project-skbackend/internal/services/permissionservice"
+	"project-skbackend/internal/services/securityservice"
+	"project-skbackend/internal/services/serviceaccountservice"
+	"project-skbackend/internal/services/sessionservice"
+	"project-skbackend/internal/services/userservice"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/utils/utrequest"
 	"project-skbackend/packages/utils/utresponse"
+	"project-skbackend/packages/utils/uttoken"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/jackc/pgerrcode"
 	"github.com/jackc/pgx/v5/pgconn"
 )
@@ -18,6 +30,13 @@ type (
 		cfg   *configs.Config
 		sorg  organizationservice.IOrganizationService
 		sauth authservice.IAuthService
+		suser userservice.IUserService
+		smemb memberservice.IMemberService
+		sbase baseroleservice.IBaseRoleService
+		sperm permissionservice.IPermissionService
+		ssess sessionservice.ISessionService
+		ssecu securityservice.ISecurityService
+		ssvac serviceaccountservice.IServiceAccountService
 	}
 )
 
@@ -26,16 +45,40 @@ func newOrganizationRoutes(
 	cfg *configs.Config,
 	sauth authservice.IAuthService,
 	sorg organizationservice.IOrganizationService,
+	suser userservice.IUserService,
+	smemb memberservice.IMemberService,
+	sbase baseroleservice.IBaseRoleService,
+	sperm permissionservice.IPermissionService,
+	ssess sessionservice.ISessionService,
+	ssecu securityservice.ISecurityService,
+	ssvac serviceaccountservice.IServiceAccountService,
 ) {
 	r := &organizationroutes{
 		cfg:   cfg,
 		sauth: sauth,
 		sorg:  sorg,
+		suser: suser,
+		smemb: smemb,
+		sbase: sbase,
+		sperm: sperm,
+		ssess: ssess,
+		ssecu: ssecu,
+		ssvac: ssvac,
 	}
 
 	gorganizationspub := rg.Group("organizations")
 	{
-		gorganizationspub.POST("register", r.organizationRegister)
+		gorganizationspub.POST("register", middlewares.ThrottleMiddleware(ssecu, consttypes.TA_REGISTER), r.organizationRegister)
+	}
+
+	gorganizationspvt := rg.Group("organizations")
+	gorganizationspvt.Use(middlewares.JWTOrAPIKeyAuthMiddleware(cfg, ssess, ssvac))
+	{
+		gmember := gorganizationspvt.Group("members")
+		{
+			gmember.GET(":mid/health/history", middlewares.PermissionMiddleware(sperm, consttypes.P_MEMBER_READ_MEDICAL), r.organizationGetMemberHealthHistory)
+			gmember.GET(":mid/health/trend", middlewares.PermissionMiddleware(sperm, consttypes.P_MEMBER_READ_MEDICAL), r.organizationGetMemberHealthTrend)
+		}
 	}
 }
 
@@ -58,7 +101,16 @@ func (r *organizationroutes) organizationRegister(ctx *gin.Context) {
 		return
 	}
 
-	_, err = r.sorg.Create(req)
+	err = r.ssecu.ThrottleAccount(ctx, consttypes.TA_REGISTER, req.ToSignin().Email, ctx.ClientIP())
+	if err != nil {
+		utresponse.GeneralTooManyRequests(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	_, err = r.sorg.Create(ctx, req)
 	if err != nil {
 		var pgerr *pgconn.PgError
 		if errors.As(err, &pgerr) {
@@ -80,7 +132,7 @@ func (r *organizationroutes) organizationRegister(ctx *gin.Context) {
 		return
 	}
 
-	resuser, thead, err := r.sauth.Signin(*req.ToSignin(), ctx)
+	resuser, thead, tfch, err := r.sauth.Signin(*req.ToSignin(), ctx)
 	if err != nil {
 		utresponse.GeneralInternalServerError(
 			function,
@@ -90,7 +142,17 @@ func (r *organizationroutes) organizationRegister(ctx *gin.Context) {
 		return
 	}
 
-	err = r.sauth.SendVerificationEmail(resuser.ID)
+	// * the role requires two factor, the tokens are given after the enrolment
+	if tfch != nil {
+		utresponse.GeneralSuccessCreate(
+			entity,
+			ctx,
+			tfch,
+		)
+		return
+	}
+
+	err = r.sauth.SendVerificationEmail(ctx, resuser.ID)
 	if err != nil {
 		utresponse.GeneralInternalServerError(
 			function,
@@ -107,3 +169,163 @@ func (r *organizationroutes) organizationRegister(ctx *gin.Context) {
 		resauth,
 	)
 }
+
+func (r *organizationroutes) organizationGetMemberHealthHistory(ctx *gin.Context) {
+	var (
+		function = "get member health history"
+		entity   = "member health history"
+		reqpage  = utrequest.GeneratePaginationFromRequest(ctx)
+	)
+
+	mid, err := uuid.Parse(ctx.Param("mid"))
+	if err != nil {
+		utresponse.GeneralInputRequiredError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	userres, err := uttoken.GetUser(ctx)
+	if err != nil {
+		utresponse.GeneralUnauthorized(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	roleres, err := r.suser.GetRoleDataByUserID(ctx, userres.ID)
+	if err != nil {
+		utresponse.GeneralUnauthorized(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	// * organizations can only see the members registered under them
+	modmem, err := r.sbase.GetOrganizationMemberByBaseRole(ctx, *roleres, mid)
+	if err != nil {
+		if errors.Is(err, consttypes.ErrMemberNotFound) {
+			utresponse.GeneralNotFound(
+				entity,
+				ctx,
+				err,
+			)
+			return
+		}
+
+		if errors.Is(err, consttypes.ErrNotResourceOwner) || errors.Is(err, consttypes.ErrUserInvalidRole) {
+			utresponse.GeneralForbidden(
+				ctx,
+				err,
+			)
+			return
+		}
+
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	reshist, err := r.smemb.FindHealthHistory(ctx, modmem.ID, reqpage)
+	if err != nil {
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessFetch(
+		entity,
+		ctx,
+		reshist,
+	)
+}
+
+func (r *organizationroutes) organizationGetMemberHealthTrend(ctx *gin.Context) {
+	var (
+		function = "get member health trend"
+		entity   = "member health trend"
+		reqpage  = utrequest.GeneratePaginationFromRequest(ctx)
+	)
+
+	mid, err := uuid.Parse(ctx.Param("mid"))
+	if err != nil {
+		utresponse.GeneralInputRequiredError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	userres, err := uttoken.GetUser(ctx)
+	if err != nil {
+		utresponse.GeneralUnauthorized(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	roleres, err := r.suser.GetRoleDataByUserID(ctx, userres.ID)
+	if err != nil {
+		utresponse.GeneralUnauthorized(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	// * organizations can only see the members registered under them
+	modmem, err := r.sbase.GetOrganizationMemberByBaseRole(ctx, *roleres, mid)
+	if err != nil {
+		if errors.Is(err, consttypes.ErrMemberNotFound) {
+			utresponse.GeneralNotFound(
+				entity,
+				ctx,
+				err,
+			)
+			return
+		}
+
+		if errors.Is(err, consttypes.ErrNotResourceOwner) || errors.Is(err, consttypes.ErrUserInvalidRole) {
+			utresponse.GeneralForbidden(
+				ctx,
+				err,
+			)
+			return
+		}
+
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	restrend, err := r.smemb.GetHealthTrend(ctx, modmem.ID, reqpage.Filter)
+	if err != nil {
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessFetch(
+		entity,
+		ctx,
+		restrend,
+	)
+}
@@ -5,9 +5,14 @@ import (
 	"project-skbackend/configs"
 	"project-skbackend/internal/controllers/requests"
 	"project-skbackend/internal/middlewares"
+	"project-skbackend/internal/services/analyticsservice"
 	"project-skbackend/internal/services/authservice"
 	"project-skbackend/internal/services/fileservice"
 	"project-skbackend/internal/services/partnerservice"
+	"project-skbackend/internal/services/permissionservice"
+	"project-skbackend/internal/services/securityservice"
+	"project-skbackend/internal/services/serviceaccountservice"
+	"project-skbackend/internal/services/sessionservice"
 	"project-skbackend/packages/consttypes"
 	"project-skbackend/packages/utils/utrequest"
 	"project-skbackend/packages/utils/utresponse"
@@ -26,6 +31,11 @@ type (
 		sauth    authservice.IAuthService
 		spartner partnerservice.IPartnerService
 		sfile    fileservice.IFileService
+		sperm    permissionservice.IPermissionService
+		ssess    sessionservice.ISessionService
+		ssecu    securityservice.ISecurityService
+		ssvac    serviceaccountservice.IServiceAccountService
+		sanly    analyticsservice.IAnalyticsService
 	}
 )
 
@@ -35,38 +45,57 @@ func newPartnerRoutes(
 	sauth authservice.IAuthService,
 	spartner partnerservice.IPartnerService,
 	sfile fileservice.IFileService,
+	sperm permissionservice.IPermissionService,
+	ssess sessionservice.ISessionService,
+	ssecu securityservice.ISecurityService,
+	ssvac serviceaccountservice.IServiceAccountService,
+	sanly analyticsservice.IAnalyticsService,
 ) {
 	r := &partnerroutes{
 		cfg:      cfg,
 		sauth:    sauth,
 		spartner: spartner,
 		sfile:    sfile,
+		sperm:    sperm,
+		ssess:    ssess,
+		ssecu:    ssecu,
+		ssvac:    ssvac,
+		sanly:    sanly,
 	}
 
 	gpartnerspub := rg.Group("partners")
 	{
-		gpartnerspub.POST("register", r.partnerRegister)
+		gpartnerspub.POST("register", middlewares.ThrottleMiddleware(ssecu, consttypes.TA_REGISTER), r.partnerRegister)
 		gpartnerspub.GET("", r.findPartners)
 		gpartnerspub.GET("raw", r.findPartnersRaw)
 		gpartnerspub.GET(":pid", r.getPartner)
 	}
 
 	gpartnerspvt := rg.Group("partners")
-	gpartnerspvt.Use(middlewares.JWTAuthMiddleware(cfg, consttypes.UR_PARTNER))
+	gpartnerspvt.Use(middlewares.JWTOrAPIKeyAuthMiddleware(cfg, ssess, ssvac))
 	{
 		gmeal := gpartnerspvt.Group("meals")
 		{
-			gmeal.GET("own", r.findOwnMeals)
-			gmeal.GET("own/raw", r.findOwnMealsRaw)
+			gmeal.GET("own", middlewares.PermissionMiddleware(sperm, consttypes.P_MEAL_READ_OWN), r.findOwnMeals)
+			gmeal.GET("own/raw", middlewares.PermissionMiddleware(sperm, consttypes.P_MEAL_READ_OWN), r.findOwnMealsRaw)
 		}
 
 		gorder := gpartnerspvt.Group("orders")
 		{
-			gorder.GET("own", r.findOwnOrders)
-			gorder.PATCH(":oid/confirmed", r.orderConfirmed)
-			gorder.PATCH(":oid/being-prepared", r.orderBeingPrepared)
-			gorder.PATCH(":oid/prepared", r.orderPrepared)
-			gorder.PATCH(":oid/picked-up", r.orderPickedUp)
+			gorder.GET("own", middlewares.PermissionMiddleware(sperm, consttypes.P_PARTNER_ORDER_READ), r.findOwnOrders)
+			gorder.PATCH(":oid/confirmed", middlewares.PermissionMiddleware(sperm, consttypes.P_ORDER_UPDATE_STATUS), r.orderConfirmed)
+			gorder.PATCH(":oid/being-prepared", middlewares.PermissionMiddleware(sperm, consttypes.P_ORDER_UPDATE_STATUS), r.orderBeingPrepared)
+			gorder.PATCH(":oid/prepared", middlewares.PermissionMiddleware(sperm, consttypes.P_ORDER_UPDATE_STATUS), r.orderPrepared)
+			gorder.PATCH(":oid/picked-up", middlewares.PermissionMiddleware(sperm, consttypes.P_ORDER_UPDATE_STATUS), r.orderPickedUp)
+		}
+
+		ganalytics := gpartnerspvt.Group("analytics")
+		{
+			ganalytics.GET("kitchen", middlewares.PermissionMiddleware(sperm, consttypes.P_PARTNER_ANALYTICS_READ), r.partnerKitchen)
+			ganalytics.GET("orders/hourly", middlewares.PermissionMiddleware(sperm, consttypes.P_PARTNER_ANALYTICS_READ), r.partnerOrdersByHour)
+			ganalytics.GET("meals/top", middlewares.PermissionMiddleware(sperm, consttypes.P_PARTNER_ANALYTICS_READ), r.partnerTopMeals)
+			ganalytics.GET("ratings", middlewares.PermissionMiddleware(sperm, consttypes.P_PARTNER_ANALYTICS_READ), r.partnerRatings)
+			ganalytics.GET("prep-list", middlewares.PermissionMiddleware(sperm, consttypes.P_PARTNER_ANALYTICS_READ), r.partnerPrepList)
 		}
 	}
 }
@@ -77,7 +106,7 @@ func (r *partnerroutes) findPartners(ctx *gin.Context) {
 		reqpage = utrequest.GeneratePaginationFromRequest(ctx)
 	)
 
-	partners, err := r.spartner.FindAll(reqpage)
+	partners, err := r.spartner.FindAll(ctx, reqpage)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
@@ -108,7 +137,7 @@ func (r *partnerroutes) findPartnersRaw(ctx *gin.Context) {
 		entity = "partners"
 	)
 
-	partners, err := r.spartner.Read()
+	partners, err := r.spartner.Read(ctx)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
@@ -150,7 +179,7 @@ func (r *partnerroutes) getPartner(ctx *gin.Context) {
 		return
 	}
 
-	respart, err := r.spartner.GetByID(uuid)
+	respart, err := r.spartner.GetByID(ctx, uuid)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
@@ -179,6 +208,7 @@ func (r *partnerroutes) getPartner(ctx *gin.Context) {
 func (r *partnerroutes) partnerRegister(ctx *gin.Context) {
 	var (
 		function = "partner register"
+		entity   = "partner"
 		req      requests.CreatePartner
 		err      error
 	)
@@ -194,7 +224,16 @@ func (r *partnerroutes) partnerRegister(ctx *gin.Context) {
 		return
 	}
 
-	respartner, err := r.spartner.Create(req)
+	err = r.ssecu.ThrottleAccount(ctx, consttypes.TA_REGISTER, req.ToSignin().Email, ctx.ClientIP())
+	if err != nil {
+		utresponse.GeneralTooManyRequests(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	respartner, err := r.spartner.Create(ctx, req)
 	if err != nil {
 		var pgerr *pgconn.PgError
 		if errors.As(err, &pgerr) {
@@ -241,7 +280,7 @@ func (r *partnerroutes) partnerRegister(ctx *gin.Context) {
 			return
 		}
 
-		err = r.sfile.UploadProfilePicture(respartner.User.ID, multipart)
+		err = r.sfile.UploadProfilePicture(ctx, respartner.User.ID, multipart)
 		if err != nil {
 			utresponse.GeneralInternalServerError(
 				function,
@@ -252,7 +291,7 @@ func (r *partnerroutes) partnerRegister(ctx *gin.Context) {
 		}
 	}
 
-	resuser, thead, err := r.sauth.Signin(*req.ToSignin(), ctx)
+	resuser, thead, tfch, err := r.sauth.Signin(*req.ToSignin(), ctx)
 	if err != nil {
 		utresponse.GeneralInternalServerError(
 			function,
@@ -262,7 +301,17 @@ func (r *partnerroutes) partnerRegister(ctx *gin.Context) {
 		return
 	}
 
-	err = r.sauth.SendVerificationEmail(resuser.ID)
+	// * the role requires two factor, the tokens are given after the enrolment
+	if tfch != nil {
+		utresponse.GeneralSuccessCreate(
+			entity,
+			ctx,
+			tfch,
+		)
+		return
+	}
+
+	err = r.sauth.SendVerificationEmail(ctx, resuser.ID)
 	if err != nil {
 		utresponse.GeneralInternalServerError(
 			function,
@@ -296,7 +345,7 @@ func (r *partnerroutes) findOwnOrders(ctx *gin.Context) {
 		return
 	}
 
-	orders, err := r.spartner.FindOwnOrders(userres.ID, reqpage)
+	orders, err := r.spartner.FindOwnOrders(ctx, userres.ID, reqpage)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
@@ -350,7 +399,7 @@ func (r *partnerroutes) orderConfirmed(ctx *gin.Context) {
 		return
 	}
 
-	err = r.spartner.OrderConfirmed(oid, user.ID)
+	err = r.spartner.OrderConfirmed(ctx, oid, user.ID)
 	if err != nil {
 		utresponse.GeneralInternalServerError(
 			function,
@@ -394,7 +443,7 @@ func (r *partnerroutes) orderBeingPrepared(ctx *gin.Context) {
 		return
 	}
 
-	err = r.spartner.OrderBeingPrepared(oid, user.ID)
+	err = r.spartner.OrderBeingPrepared(ctx, oid, user.ID)
 	if err != nil {
 		utresponse.GeneralInternalServerError(
 			function,
@@ -438,7 +487,7 @@ func (r *partnerroutes) orderPrepared(ctx *gin.Context) {
 		return
 	}
 
-	err = r.spartner.OrderPrepared(oid, user.ID)
+	err = r.spartner.OrderPrepared(ctx, oid, user.ID)
 	if err != nil {
 		utresponse.GeneralInternalServerError(
 			function,
@@ -482,7 +531,7 @@ func (r *partnerroutes) orderPickedUp(ctx *gin.Context) {
 		return
 	}
 
-	err = r.spartner.OrderPickedUp(oid, user.ID)
+	err = r.spartner.OrderPickedUp(ctx, oid, user.ID)
 	if err != nil {
 		utresponse.GeneralInternalServerError(
 			function,
@@ -514,7 +563,7 @@ func (r *partnerroutes) findOwnMeals(ctx *gin.Context) {
 		return
 	}
 
-	meals, err := r.spartner.FindOwnMeals(userres.ID, reqpage)
+	meals, err := r.spartner.FindOwnMeals(ctx, userres.ID, reqpage)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
@@ -554,7 +603,7 @@ func (r *partnerroutes) findOwnMealsRaw(ctx *gin.Context) {
 		return
 	}
 
-	meals, err := r.spartner.ReadOwnMeal(userres.ID)
+	meals, err := r.spartner.ReadOwnMeal(ctx, userres.ID)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
@@ -579,3 +628,215 @@ func (r *partnerroutes) findOwnMealsRaw(ctx *gin.Context) {
 		meals,
 	)
 }
+
+func (r *partnerroutes) partnerKitchen(ctx *gin.Context) {
+	var (
+		function = "get kitchen analytics"
+		entity   = "kitchen analytics"
+		req      requests.AnalyticsRange
+	)
+
+	err := ctx.ShouldBindQuery(&req)
+	if err != nil {
+		ve := utresponse.ValidationResponse(err)
+		utresponse.GeneralInvalidRequest(
+			function,
+			ctx,
+			ve,
+			err,
+		)
+		return
+	}
+
+	userres, err := uttoken.GetUser(ctx)
+	if err != nil {
+		utresponse.GeneralUnauthorized(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	kitres, err := r.sanly.PartnerKitchen(ctx, userres.ID, req)
+	if err != nil {
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessFetch(
+		entity,
+		ctx,
+		kitres,
+	)
+}
+
+func (r *partnerroutes) partnerOrdersByHour(ctx *gin.Context) {
+	var (
+		function = "get hourly orders analytics"
+		entity   = "hourly orders analytics"
+		req      requests.AnalyticsRange
+	)
+
+	err := ctx.ShouldBindQuery(&req)
+	if err != nil {
+		ve := utresponse.ValidationResponse(err)
+		utresponse.GeneralInvalidRequest(
+			function,
+			ctx,
+			ve,
+			err,
+		)
+		return
+	}
+
+	userres, err := uttoken.GetUser(ctx)
+	if err != nil {
+		utresponse.GeneralUnauthorized(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	hourres, err := r.sanly.PartnerOrdersByHour(ctx, userres.ID, req)
+	if err != nil {
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessFetch(
+		entity,
+		ctx,
+		hourres,
+	)
+}
+
+func (r *partnerroutes) partnerTopMeals(ctx *gin.Context) {
+	var (
+		function = "get top meals analytics"
+		entity   = "top meals analytics"
+		req      requests.AnalyticsRange
+	)
+
+	err := ctx.ShouldBindQuery(&req)
+	if err != nil {
+		ve := utresponse.ValidationResponse(err)
+		utresponse.GeneralInvalidRequest(
+			function,
+			ctx,
+			ve,
+			err,
+		)
+		return
+	}
+
+	userres, err := uttoken.GetUser(ctx)
+	if err != nil {
+		utresponse.GeneralUnauthorized(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	mealres, err := r.sanly.PartnerTopMeals(ctx, userres.ID, req)
+	if err != nil {
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessFetch(
+		entity,
+		ctx,
+		mealres,
+	)
+}
+
+func (r *partnerroutes) partnerRatings(ctx *gin.Context) {
+	var (
+		function = "get ratings analytics"
+		entity   = "ratings analytics"
+		req      requests.AnalyticsRange
+	)
+
+	err := ctx.ShouldBindQuery(&req)
+	if err != nil {
+		ve := utresponse.ValidationResponse(err)
+		utresponse.GeneralInvalidRequest(
+			function,
+			ctx,
+			ve,
+			err,
+		)
+		return
+	}
+
+	userres, err := uttoken.GetUser(ctx)
+	if err != nil {
+		utresponse.GeneralUnauthorized(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	ratres, err := r.sanly.PartnerRatings(ctx, userres.ID, req)
+	if err != nil {
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessFetch(
+		entity,
+		ctx,
+		ratres,
+	)
+}
+
+func (r *partnerroutes) partnerPrepList(ctx *gin.Context) {
+	var (
+		function = "get prep list"
+		entity   = "prep list"
+	)
+
+	userres, err := uttoken.GetUser(ctx)
+	if err != nil {
+		utresponse.GeneralUnauthorized(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	plres, err := r.sanly.PartnerPrepList(ctx, userres.ID)
+	if err != nil {
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessFetch(
+		entity,
+		ctx,
+		plres,
+	)
+}
@@ -8,6 +8,9 @@ import (
 	"project-skbackend/internal/services/authservice"
 	"project-skbackend/internal/services/fileservice"
 	"project-skbackend/internal/services/patronservice"
+	"project-skbackend/internal/services/permissionservice"
+	"project-skbackend/internal/services/securityservice"
+	"project-skbackend/internal/services/sessionservice"
 	"project-skbackend/packages/consttypes"
 	"project-skbackend/packages/utils/utresponse"
 	"project-skbackend/packages/utils/uttoken"
@@ -23,6 +26,9 @@ type (
 		spatron patronservice.IPatronService
 		sauth   authservice.IAuthService
 		sfile   fileservice.IFileService
+		sperm   permissionservice.IPermissionService
+		ssess   sessionservice.ISessionService
+		ssecu   securityservice.ISecurityService
 	}
 )
 
@@ -32,25 +38,31 @@ func newPatronRoutes(
 	sauth authservice.IAuthService,
 	spatron patronservice.IPatronService,
 	sfile fileservice.IFileService,
+	sperm permissionservice.IPermissionService,
+	ssess sessionservice.ISessionService,
+	ssecu securityservice.ISecurityService,
 ) {
 	r := &patronroutes{
 		cfg:     cfg,
 		sauth:   sauth,
 		spatron: spatron,
 		sfile:   sfile,
+		sperm:   sperm,
+		ssess:   ssess,
+		ssecu:   ssecu,
 	}
 
 	gpatronspub := rg.Group("patrons")
 	{
-		gpatronspub.POST("register", r.patronRegister)
+		gpatronspub.POST("register", middlewares.ThrottleMiddleware(ssecu, consttypes.TA_REGISTER), r.patronRegister)
 	}
 
 	gpatronspvt := rg.Group("patrons")
-	gpatronspvt.Use(middlewares.JWTAuthMiddleware(cfg, consttypes.UR_PATRON))
+	gpatronspvt.Use(middlewares.JWTAuthMiddleware(cfg, ssess))
 	{
 		gdonation := gpatronspvt.Group("donations")
 		{
-			gdonation.POST("", r.patronCreateDonation)
+			gdonation.POST("", middlewares.PermissionMiddleware(sperm, consttypes.P_DONATION_CREATE), r.patronCreateDonation)
 		}
 	}
 }
@@ -74,7 +86,16 @@ func (r *patronroutes) patronRegister(ctx *gin.Context) {
 		return
 	}
 
-	respatron, err := r.spatron.Create(req)
+	err = r.ssecu.ThrottleAccount(ctx, consttypes.TA_REGISTER, req.ToSignin().Email, ctx.ClientIP())
+	if err != nil {
+		utresponse.GeneralTooManyRequests(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	respatron, err := r.spatron.Create(ctx, req)
 	if err != nil {
 		var pgerr *pgconn.PgError
 		if errors.As(err, &pgerr) {
@@ -121,7 +142,7 @@ func (r *patronroutes) patronRegister(ctx *gin.Context) {
 			return
 		}
 
-		err = r.sfile.UploadProfilePicture(respatron.User.ID, multipart)
+		err = r.sfile.UploadProfilePicture(ctx, respatron.User.ID, multipart)
 		if err != nil {
 			utresponse.GeneralInternalServerError(
 				function,
@@ -132,7 +153,7 @@ func (r *patronroutes) patronRegister(ctx *gin.Context) {
 		}
 	}
 
-	resuser, thead, err := r.sauth.Signin(*req.ToSignin(), ctx)
+	resuser, thead, tfch, err := r.sauth.Signin(*req.ToSignin(), ctx)
 	if err != nil {
 		utresponse.GeneralInternalServerError(
 			function,
@@ -142,7 +163,17 @@ func (r *patronroutes) patronRegister(ctx *gin.Context) {
 		return
 	}
 
-	err = r.sauth.SendVerificationEmail(resuser.ID)
+	// * the role requires two factor, the tokens are given after the enrolment
+	if tfch != nil {
+		utresponse.GeneralSuccessCreate(
+			entity,
+			ctx,
+			tfch,
+		)
+		return
+	}
+
+	err = r.sauth.SendVerificationEmail(ctx, resuser.ID)
 	if err != nil {
 		utresponse.GeneralInternalServerError(
 			function,
@@ -188,7 +219,7 @@ func (r *patronroutes) patronCreateDonation(ctx *gin.Context) {
 		)
 	}
 
-	respatron, err := r.spatron.GetByUserID(resuser.ID)
+	respatron, err := r.spatron.GetByUserID(ctx, resuser.ID)
 	if err != nil {
 		utresponse.GeneralInternalServerError(
 			function,
@@ -198,7 +229,7 @@ func (r *patronroutes) patronCreateDonation(ctx *gin.Context) {
 		return
 	}
 
-	resdona, err := r.spatron.CreateDonation(req, respatron.ID)
+	resdona, err := r.spatron.CreateDonation(ctx, req, respatron.ID)
 	if err != nil {
 		utresponse.GeneralInternalServerError(
 			function,
@@ -235,7 +266,7 @@ func (r *patronroutes) patronCreateDonation(ctx *gin.Context) {
 		}
 
 		// * upload the image
-		err = r.sfile.UploadDonationProof(resdona.ID, multipart)
+		err = r.sfile.UploadDonationProof(ctx, resdona.ID, multipart)
 		if err != nil {
 			utresponse.GeneralInternalServerError(
 				function,
@@ -1,18 +1,26 @@
 package controllers
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
 	"project-skbackend/configs"
 	"project-skbackend/internal/controllers/requests"
 	"project-skbackend/internal/middlewares"
 	"project-skbackend/internal/services/baseroleservice"
 	"project-skbackend/internal/services/fileservice"
 	"project-skbackend/internal/services/memberservice"
+	"project-skbackend/internal/services/permissionservice"
+	"project-skbackend/internal/services/privacyservice"
+	"project-skbackend/internal/services/sessionservice"
+	"project-skbackend/internal/services/twofactorservice"
 	"project-skbackend/internal/services/userservice"
 	"project-skbackend/packages/consttypes"
 	"project-skbackend/packages/utils/utresponse"
 	"project-skbackend/packages/utils/uttoken"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
@@ -23,6 +31,10 @@ type (
 		smemb memberservice.IMemberService
 		sfile fileservice.IFileService
 		sbase baseroleservice.IBaseRoleService
+		sperm permissionservice.IPermissionService
+		ssess sessionservice.ISessionService
+		sprvc privacyservice.IPrivacyService
+		stfa  twofactorservice.ITwoFactorService
 	}
 )
 
@@ -33,6 +45,10 @@ func newProfileRoutes(
 	smemb memberservice.IMemberService,
 	sfile fileservice.IFileService,
 	sbase baseroleservice.IBaseRoleService,
+	sperm permissionservice.IPermissionService,
+	ssess sessionservice.ISessionService,
+	sprvc privacyservice.IPrivacyService,
+	stfa twofactorservice.ITwoFactorService,
 ) {
 	r := &profileroutes{
 		cfg:   cfg,
@@ -40,42 +56,69 @@ func newProfileRoutes(
 		smemb: smemb,
 		sfile: sfile,
 		sbase: sbase,
+		sperm: sperm,
+		ssess: ssess,
+		sprvc: sprvc,
+		stfa:  stfa,
 	}
 
 	gprofilepvt := rg.Group("profiles")
-	gprofilepvt.Use(middlewares.JWTAuthMiddleware(
-		cfg,
-		consttypes.UR_MEMBER,
-		consttypes.UR_CAREGIVER,
-		consttypes.UR_ADMIN,
-		consttypes.UR_ORGANIZATION,
-		consttypes.UR_PARTNER,
-		consttypes.UR_PATRON,
-	))
+	gprofilepvt.Use(middlewares.JWTAuthMiddleware(cfg, ssess))
 	{
 		// * global route
-		gprofilepvt.GET("me", r.getOwnProfile)
+		gprofilepvt.GET("me", middlewares.PermissionMiddleware(sperm, consttypes.P_PROFILE_READ), r.getOwnProfile)
 
 		// * picture's route
 		gpicture := gprofilepvt.Group("pictures")
 		{
-			gpicture.PATCH("", r.updateProfilePicture)
+			gpicture.PATCH("", middlewares.PermissionMiddleware(sperm, consttypes.P_PROFILE_UPDATE), r.updateProfilePicture)
 		}
 
 		gpassword := gprofilepvt.Group("passwords")
 		{
-			gpassword.PATCH("own", r.updateOwnPassword)
+			gpassword.PATCH("own", middlewares.PermissionMiddleware(sperm, consttypes.P_PROFILE_UPDATE), r.updateOwnPassword)
 		}
 
 		// * member's route
 		gprofilemem := gprofilepvt.Group("members")
-		gprofilemem.Use(middlewares.JWTAuthMiddleware(
-			cfg,
-			consttypes.UR_MEMBER,
-		))
+		gprofilemem.Use(middlewares.PermissionMiddleware(sperm, consttypes.P_MEMBER_UPDATE_MEDICAL))
 		{
 			gprofilemem.PATCH("own", r.updateOwnMemberProfile)
 		}
+
+		// * session's route
+		gsession := gprofilepvt.Group("sessions")
+		{
+			gsession.GET("", middlewares.PermissionMiddleware(sperm, consttypes.P_PROFILE_READ), r.findOwnSessions)
+			gsession.DELETE("", middlewares.PermissionMiddleware(sperm, consttypes.P_PROFILE_UPDATE), r.revokeOwnOtherSessions)
+			gsession.DELETE("/:sid", middlewares.PermissionMiddleware(sperm, consttypes.P_PROFILE_UPDATE), r.revokeOwnSession)
+		}
+
+		// * two factor's route
+		gtwofactor := gprofilepvt.Group("two-factor")
+		{
+			gtwofactor.GET("", middlewares.PermissionMiddleware(sperm, consttypes.P_PROFILE_READ), r.getOwnTwoFactor)
+			gtwofactor.DELETE("", middlewares.PermissionMiddleware(sperm, consttypes.P_PROFILE_UPDATE), r.disableOwnTwoFactor)
+			gtwofactor.POST("enrol", middlewares.PermissionMiddleware(sperm, consttypes.P_PROFILE_UPDATE), r.beginOwnTwoFactorEnrolment)
+			gtwofactor.POST("enrol/confirm", middlewares.PermissionMiddleware(sperm, consttypes.P_PROFILE_UPDATE), r.confirmOwnTwoFactorEnrolment)
+			gtwofactor.POST("recovery-codes", middlewares.PermissionMiddleware(sperm, consttypes.P_PROFILE_UPDATE), r.regenerateOwnRecoveryCodes)
+		}
+
+		// * personal data's route
+		gdataexport := gprofilepvt.Group("data-exports")
+		gdataexport.Use(middlewares.PermissionMiddleware(sperm, consttypes.P_PROFILE_EXPORT))
+		{
+			gdataexport.POST("", r.createOwnDataExport)
+			gdataexport.GET("", r.findOwnDataExports)
+			gdataexport.GET("/:deid/download", r.downloadOwnDataExport)
+		}
+
+		gdataerasure := gprofilepvt.Group("data-erasures")
+		gdataerasure.Use(middlewares.PermissionMiddleware(sperm, consttypes.P_PROFILE_ERASE))
+		{
+			gdataerasure.POST("", r.createOwnDataErasure)
+			gdataerasure.GET("", r.findOwnDataErasures)
+		}
 	}
 }
 
@@ -93,7 +136,7 @@ func (r *profileroutes) getOwnProfile(ctx *gin.Context) {
 		return
 	}
 
-	roleres, err := r.suser.GetRoleDataByUserID(userres.ID)
+	roleres, err := r.suser.GetRoleDataByUserID(ctx, userres.ID)
 	if err != nil {
 		utresponse.GeneralUnauthorized(
 			ctx,
@@ -147,7 +190,7 @@ func (r *profileroutes) updateOwnMemberProfile(ctx *gin.Context) {
 	}
 
 	// * get its role data by its user id
-	roleres, err := r.suser.GetRoleDataByUserID(userres.ID)
+	roleres, err := r.suser.GetRoleDataByUserID(ctx, userres.ID)
 	if err != nil {
 		utresponse.GeneralUnauthorized(
 			ctx,
@@ -166,7 +209,7 @@ func (r *profileroutes) updateOwnMemberProfile(ctx *gin.Context) {
 		return
 	}
 
-	modmem, err := r.sbase.GetMemberByBaseRole(*roleres)
+	modmem, err := r.sbase.GetMemberByBaseRole(ctx, *roleres, nil, consttypes.CA_EDIT_HEALTH)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
@@ -185,7 +228,7 @@ func (r *profileroutes) updateOwnMemberProfile(ctx *gin.Context) {
 		return
 	}
 
-	resmem, err := r.smemb.Update(modmem.ID, *req)
+	resmem, err := r.smemb.Update(ctx, modmem.ID, *req, userres.ID)
 	if err != nil {
 		utresponse.GeneralInternalServerError(
 			function,
@@ -220,7 +263,7 @@ func (r *profileroutes) updateOwnMemberProfile(ctx *gin.Context) {
 			return
 		}
 
-		err = r.sfile.UploadProfilePicture(resmem.User.ID, multipart)
+		err = r.sfile.UploadProfilePicture(ctx, resmem.User.ID, multipart)
 		if err != nil {
 			utresponse.GeneralInternalServerError(
 				function,
@@ -231,7 +274,7 @@ func (r *profileroutes) updateOwnMemberProfile(ctx *gin.Context) {
 		}
 	}
 
-	resmem, err = r.smemb.GetByID(resmem.ID)
+	resmem, err = r.smemb.GetByID(ctx, resmem.ID)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utresponse.GeneralNotFound(
@@ -306,7 +349,7 @@ func (r *profileroutes) updateProfilePicture(ctx *gin.Context) {
 		return
 	}
 
-	err = r.sfile.UploadProfilePicture(userres.ID, multipart)
+	err = r.sfile.UploadProfilePicture(ctx, userres.ID, multipart)
 	if err != nil {
 		utresponse.GeneralInternalServerError(
 			function,
@@ -351,7 +394,27 @@ func (r *profileroutes) updateOwnPassword(ctx *gin.Context) {
 		return
 	}
 
-	err = r.suser.UpdateOwnPassword(userres.ID, *req)
+	err = r.suser.UpdateOwnPassword(ctx, userres.ID, *req)
+	if err != nil {
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	// * keep the current session and sign out the other devices
+	sid, err := uttoken.GetSessionID(ctx)
+	if err != nil {
+		utresponse.GeneralUnauthorized(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	err = r.ssess.RevokeAll(ctx, userres.ID, &sid)
 	if err != nil {
 		utresponse.GeneralInternalServerError(
 			function,
@@ -367,3 +430,628 @@ func (r *profileroutes) updateOwnPassword(ctx *gin.Context) {
 		nil,
 	)
 }
+
+func (r *profileroutes) findOwnSessions(ctx *gin.Context) {
+	var (
+		function = "find own sessions"
+		entity   = "sessions"
+	)
+
+	userres, err := uttoken.GetUser(ctx)
+	if err != nil {
+		utresponse.GeneralUnauthorized(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	sid, err := uttoken.GetSessionID(ctx)
+	if err != nil {
+		utresponse.GeneralUnauthorized(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	sesreses, err := r.ssess.FindByUserID(ctx, userres.ID, sid)
+	if err != nil {
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessFetch(
+		entity,
+		ctx,
+		sesreses,
+	)
+}
+
+func (r *profileroutes) revokeOwnSession(ctx *gin.Context) {
+	var (
+		function = "revoke own session"
+		entity   = "session"
+	)
+
+	sid, err := uuid.Parse(ctx.Param("sid"))
+	if err != nil {
+		utresponse.GeneralInputRequiredError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	userres, err := uttoken.GetUser(ctx)
+	if err != nil {
+		utresponse.GeneralUnauthorized(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	err = r.ssess.Revoke(ctx, userres.ID, sid)
+	if err != nil {
+		if errors.Is(err, consttypes.ErrSessionNotFound) {
+			utresponse.GeneralNotFound(
+				entity,
+				ctx,
+				err,
+			)
+			return
+		}
+
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessDelete(
+		entity,
+		ctx,
+		nil,
+	)
+}
+
+func (r *profileroutes) revokeOwnOtherSessions(ctx *gin.Context) {
+	var (
+		function = "revoke own other sessions"
+		entity   = "sessions"
+	)
+
+	userres, err := uttoken.GetUser(ctx)
+	if err != nil {
+		utresponse.GeneralUnauthorized(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	sid, err := uttoken.GetSessionID(ctx)
+	if err != nil {
+		utresponse.GeneralUnauthorized(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	err = r.ssess.RevokeAll(ctx, userres.ID, &sid)
+	if err != nil {
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessDelete(
+		entity,
+		ctx,
+		nil,
+	)
+}
+
+func (r *profileroutes) createOwnDataExport(ctx *gin.Context) {
+	var (
+		function = "create own data export"
+		entity   = "data export"
+	)
+
+	userres, err := uttoken.GetUser(ctx)
+	if err != nil {
+		utresponse.GeneralUnauthorized(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	deres, err := r.sprvc.CreateDataExport(ctx, userres.ID)
+	if err != nil {
+		if errors.Is(err, consttypes.ErrDataExportAlreadyRequested) {
+			utresponse.GeneralDuplicate(
+				entity,
+				ctx,
+				err,
+			)
+			return
+		}
+
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessCreate(
+		entity,
+		ctx,
+		deres,
+	)
+}
+
+func (r *profileroutes) findOwnDataExports(ctx *gin.Context) {
+	var (
+		function = "find own data exports"
+		entity   = "data exports"
+	)
+
+	userres, err := uttoken.GetUser(ctx)
+	if err != nil {
+		utresponse.GeneralUnauthorized(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	dereses, err := r.sprvc.FindDataExportsByUserID(ctx, userres.ID)
+	if err != nil {
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessFetch(
+		entity,
+		ctx,
+		dereses,
+	)
+}
+
+func (r *profileroutes) downloadOwnDataExport(ctx *gin.Context) {
+	var (
+		function = "download own data export"
+		entity   = "data export"
+	)
+
+	deid, err := uuid.Parse(ctx.Param("deid"))
+	if err != nil {
+		utresponse.GeneralInputRequiredError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	userres, err := uttoken.GetUser(ctx)
+	if err != nil {
+		utresponse.GeneralUnauthorized(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	file, size, err := r.sprvc.GetDataExportFile(ctx, userres.ID, deid)
+	if err != nil {
+		if errors.Is(err, consttypes.ErrDataExportNotFound) || errors.Is(err, consttypes.ErrDataExportExpired) {
+			utresponse.GeneralNotFound(
+				entity,
+				ctx,
+				err,
+			)
+			return
+		}
+
+		if errors.Is(err, consttypes.ErrDataExportNotReady) {
+			utresponse.GeneralInvalidRequest(
+				function,
+				ctx,
+				nil,
+				err,
+			)
+			return
+		}
+
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+	defer file.Close()
+
+	ctx.DataFromReader(
+		http.StatusOK,
+		size,
+		"application/zip",
+		file,
+		map[string]string{
+			"Content-Disposition": fmt.Sprintf(`attachment; filename="data-export-%s.zip"`, deid),
+		},
+	)
+}
+
+func (r *profileroutes) createOwnDataErasure(ctx *gin.Context) {
+	var (
+		function = "create own data erasure"
+		entity   = "data erasure"
+		req      requests.CreateDataErasure
+	)
+
+	if err := ctx.ShouldBind(&req); err != nil {
+		ve := utresponse.ValidationResponse(err)
+		utresponse.GeneralInvalidRequest(
+			function,
+			ctx,
+			ve,
+			err,
+		)
+		return
+	}
+
+	userres, err := uttoken.GetUser(ctx)
+	if err != nil {
+		utresponse.GeneralUnauthorized(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	deres, err := r.sprvc.CreateOwnDataErasure(ctx, userres.ID, req)
+	if err != nil {
+		if errors.Is(err, consttypes.ErrInvalidEmailOrPassword) {
+			utresponse.GeneralUnauthorized(
+				ctx,
+				err,
+			)
+			return
+		}
+
+		if errors.Is(err, consttypes.ErrDataErasureAlreadyRequested) {
+			utresponse.GeneralDuplicate(
+				entity,
+				ctx,
+				err,
+			)
+			return
+		}
+
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessCreate(
+		entity,
+		ctx,
+		deres,
+	)
+}
+
+func (r *profileroutes) findOwnDataErasures(ctx *gin.Context) {
+	var (
+		function = "find own data erasures"
+		entity   = "data erasures"
+	)
+
+	userres, err := uttoken.GetUser(ctx)
+	if err != nil {
+		utresponse.GeneralUnauthorized(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	dereses, err := r.sprvc.FindDataErasuresByUserID(ctx, userres.ID)
+	if err != nil {
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessFetch(
+		entity,
+		ctx,
+		dereses,
+	)
+}
+
+func (r *profileroutes) getOwnTwoFactor(ctx *gin.Context) {
+	var (
+		function = "get own two factor"
+		entity   = "two factor"
+	)
+
+	userres, err := uttoken.GetUser(ctx)
+	if err != nil {
+		utresponse.GeneralUnauthorized(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	tfsres, err := r.stfa.Status(ctx, userres.ID, userres.Role)
+	if err != nil {
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessFetch(
+		entity,
+		ctx,
+		tfsres,
+	)
+}
+
+func (r *profileroutes) beginOwnTwoFactorEnrolment(ctx *gin.Context) {
+	var (
+		function = "begin own two factor enrolment"
+	)
+
+	userres, err := uttoken.GetUser(ctx)
+	if err != nil {
+		utresponse.GeneralUnauthorized(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	tferes, err := r.stfa.BeginEnrolment(ctx, userres.ID)
+	if err != nil {
+		if errors.Is(err, consttypes.ErrTwoFactorNotAllowed) {
+			utresponse.GeneralForbidden(
+				ctx,
+				err,
+			)
+			return
+		}
+
+		if errors.Is(err, consttypes.ErrTwoFactorAlreadyEnabled) {
+			utresponse.GeneralDuplicate(
+				"two factor",
+				ctx,
+				err,
+			)
+			return
+		}
+
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccess(
+		function,
+		ctx,
+		tferes,
+	)
+}
+
+func (r *profileroutes) confirmOwnTwoFactorEnrolment(ctx *gin.Context) {
+	var (
+		function = "confirm own two factor enrolment"
+		req      requests.ConfirmTwoFactor
+	)
+
+	err := ctx.ShouldBind(&req)
+	if err != nil {
+		ve := utresponse.ValidationResponse(err)
+		utresponse.GeneralInvalidRequest(
+			function,
+			ctx,
+			ve,
+			err,
+		)
+		return
+	}
+
+	userres, err := uttoken.GetUser(ctx)
+	if err != nil {
+		utresponse.GeneralUnauthorized(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	rcres, err := r.stfa.ConfirmEnrolment(ctx, userres.ID, req, ctx.ClientIP(), ctx.Request.UserAgent())
+	if err != nil {
+		if errors.Is(err, consttypes.ErrTwoFactorAlreadyEnabled) {
+			utresponse.GeneralDuplicate(
+				"two factor",
+				ctx,
+				err,
+			)
+			return
+		}
+
+		if errors.Is(err, consttypes.ErrTwoFactorNotEnrolled) || errors.Is(err, consttypes.ErrTwoFactorCodeInvalid) {
+			utresponse.GeneralInvalidRequest(
+				function,
+				ctx,
+				nil,
+				err,
+			)
+			return
+		}
+
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccess(
+		function,
+		ctx,
+		rcres,
+	)
+}
+
+func (r *profileroutes) regenerateOwnRecoveryCodes(ctx *gin.Context) {
+	var (
+		function = "regenerate own recovery codes"
+		req      requests.ConfirmTwoFactor
+	)
+
+	err := ctx.ShouldBind(&req)
+	if err != nil {
+		ve := utresponse.ValidationResponse(err)
+		utresponse.GeneralInvalidRequest(
+			function,
+			ctx,
+			ve,
+			err,
+		)
+		return
+	}
+
+	userres, err := uttoken.GetUser(ctx)
+	if err != nil {
+		utresponse.GeneralUnauthorized(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	rcres, err := r.stfa.RegenerateRecoveryCodes(ctx, userres.ID, req)
+	if err != nil {
+		if errors.Is(err, consttypes.ErrTwoFactorNotEnabled) || errors.Is(err, consttypes.ErrTwoFactorCodeInvalid) {
+			utresponse.GeneralInvalidRequest(
+				function,
+				ctx,
+				nil,
+				err,
+			)
+			return
+		}
+
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccess(
+		function,
+		ctx,
+		rcres,
+	)
+}
+
+func (r *profileroutes) disableOwnTwoFactor(ctx *gin.Context) {
+	var (
+		function = "disable own two factor"
+		entity   = "two factor"
+		req      requests.TwoFactorCode
+	)
+
+	err := ctx.ShouldBind(&req)
+	if err != nil {
+		ve := utresponse.ValidationResponse(err)
+		utresponse.GeneralInvalidRequest(
+			function,
+			ctx,
+			ve,
+			err,
+		)
+		return
+	}
+
+	userres, err := uttoken.GetUser(ctx)
+	if err != nil {
+		utresponse.GeneralUnauthorized(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	err = r.stfa.Disable(ctx, userres.ID, userres.Role, req, ctx.ClientIP(), ctx.Request.UserAgent())
+	if err != nil {
+		if errors.Is(err, consttypes.ErrTwoFactorRequired) {
+			utresponse.GeneralForbidden(
+				ctx,
+				err,
+			)
+			return
+		}
+
+		if errors.Is(err, consttypes.ErrTwoFactorNotEnabled) || errors.Is(err, consttypes.ErrTwoFactorCodeInvalid) || errors.Is(err, consttypes.ErrTwoFactorCodeRequired) {
+			utresponse.GeneralInvalidRequest(
+				function,
+				ctx,
+				nil,
+				err,
+			)
+			return
+		}
+
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessDelete(
+		entity,
+		ctx,
+		nil,
+	)
+}
@@ -1,40 +1,60 @@
 package controllers
 
 import (
-	"net/http"
 	"project-skbackend/configs"
 	"project-skbackend/internal/di"
 	"project-skbackend/internal/middlewares"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/lifecycle"
 
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
 )
 
-func NewRouter(ge *gin.Engine, db *gorm.DB, cfg *configs.Config, di *di.DependencyInjection, rdb *redis.Client) {
-	ge.Use(gin.Logger())
-	ge.Use(gin.Recovery())
+func NewRouter(ge *gin.Engine, db *gorm.DB, cfg *configs.Config, di *di.DependencyInjection, rdb *redis.Client, lm *lifecycle.Manager) {
+	// * the handlers pass the gin context on as the context of the request,
+	// * so it has to answer with the span the tracing middleware started
+	ge.ContextWithFallback = true
+
+	ge.Use(middlewares.RequestIDMiddleware())
+	ge.Use(middlewares.TracingMiddleware(cfg.App.Name))
+	ge.Use(middlewares.LoggerMiddleware())
+	ge.Use(middlewares.RecoveryMiddleware())
 	ge.Use(middlewares.CORSMiddleware())
+	ge.Use(middlewares.MetricsMiddleware())
+	ge.Use(middlewares.ErrorMiddleware())
 
-	ge.GET("/health", func(context *gin.Context) {
-		context.JSON(http.StatusOK, gin.H{"status": "oks"})
+	// * an unknown route is answered as a problem like any other error
+	ge.NoRoute(func(ctx *gin.Context) {
+		ctx.Abort()
+		_ = ctx.Error(consttypes.ErrRouteNotFound)
 	})
 
+	newHealthRoutes(ge, cfg, di.HealthService, lm)
+	newMetricsRoutes(ge)
+
 	h := ge.Group("api/v1")
 	{
-		newAuthRoutes(h, cfg, rdb, di.AuthService, di.UserService)
-		newMemberRoutes(h, cfg, di.MemberService, di.CartService, di.UserService, di.AuthService, di.OrderService, di.FileService, di.BaseRoleService, di.CaregiverService)
-		newPartnerRoutes(h, cfg, di.AuthService, di.PartnerService, di.FileService)
-		newManageRoutes(h, cfg, di.MealService, di.MemberService, di.PartnerService, di.PatronService, di.IllnessService, di.FileService, di.AllergyService, di.DonationService)
-		newPatronRoutes(h, cfg, di.AuthService, di.PatronService, di.FileService)
-		newOrganizationRoutes(h, cfg, di.AuthService, di.OrganizationService)
+		newAuthRoutes(h, cfg, di.AuthService, di.UserService, di.SessionService, di.SecurityService, di.TwoFactorService)
+		newMemberRoutes(h, cfg, di.MemberService, di.CartService, di.UserService, di.AuthService, di.OrderService, di.FileService, di.BaseRoleService, di.CaregiverService, di.PermissionService, di.SessionService, di.SecurityService)
+		newCaregiverRoutes(h, cfg, di.CaregiverService, di.MemberService, di.BaseRoleService, di.PermissionService, di.SessionService)
+		newPartnerRoutes(h, cfg, di.AuthService, di.PartnerService, di.FileService, di.PermissionService, di.SessionService, di.SecurityService, di.ServiceAccountService, di.AnalyticsService)
+		newManageRoutes(h, cfg, di.MealService, di.MealCategoryService, di.MemberService, di.PartnerService, di.PatronService, di.IllnessService, di.FileService, di.AllergyService, di.DonationService, di.PermissionService, di.SessionService, di.PrivacyService, di.SecurityService, di.ServiceAccountService, di.AuditService, di.AnalyticsService)
+		newPatronRoutes(h, cfg, di.AuthService, di.PatronService, di.FileService, di.PermissionService, di.SessionService, di.SecurityService)
+		newOrganizationRoutes(h, cfg, di.AuthService, di.OrganizationService, di.UserService, di.MemberService, di.BaseRoleService, di.PermissionService, di.SessionService, di.SecurityService, di.ServiceAccountService)
 		newFileRoutes(h, cfg, di.FileService)
 		newAllergyRoutes(h, cfg, di.AllergyService)
 		newIllnessRoutes(h, cfg, di.IllnessService)
 		newDonationRoutes(h, cfg, di.DonationService)
-		newCartRoutes(h, cfg, di.CartService, di.UserService)
-		newMealRoutes(h, cfg, di.MealService, di.MealCategoryService)
-		newProfileRoutes(h, cfg, di.UserService, di.MemberService, di.FileService, di.BaseRoleService)
-		newOrderRoutes(h, cfg, di.OrderService, di.UserService)
+		newCartRoutes(h, cfg, di.CartService, di.UserService, di.PermissionService, di.SessionService)
+		newMealRoutes(h, cfg, di.MealService, di.MealCategoryService, di.SearchService)
+		newProfileRoutes(h, cfg, di.UserService, di.MemberService, di.FileService, di.BaseRoleService, di.PermissionService, di.SessionService, di.PrivacyService, di.TwoFactorService)
+		newOrderRoutes(h, cfg, di.OrderService, di.UserService, di.PermissionService, di.SessionService)
+		newWebhookRoutes(h, cfg, di.WebhookService, di.PermissionService, di.SessionService, di.ServiceAccountService)
+
+		if cfg.OIDC.Enabled {
+			newOIDCRoutes(h, cfg, di.AuthService, di.IdentityService, di.SecurityService)
+		}
 	}
 }
@@ -0,0 +1,486 @@
+package controllers
+
+import (
+	"errors"
+	"project-skbackend/configs"
+	"project-skbackend/internal/controllers/requests"
+	"project-skbackend/internal/middlewares"
+	"project-skbackend/internal/services/permissionservice"
+	"project-skbackend/internal/services/serviceaccountservice"
+	"project-skbackend/internal/services/sessionservice"
+	"project-skbackend/internal/services/webhookservice"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/utils/utrequest"
+	"project-skbackend/packages/utils/utresponse"
+	"project-skbackend/packages/utils/uttoken"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type (
+	webhookroutes struct {
+		cfg   *configs.Config
+		swebh webhookservice.IWebhookService
+		sperm permissionservice.IPermissionService
+		ssess sessionservice.ISessionService
+		ssvac serviceaccountservice.IServiceAccountService
+	}
+)
+
+func newWebhookRoutes(
+	rg *gin.RouterGroup,
+	cfg *configs.Config,
+	swebh webhookservice.IWebhookService,
+	sperm permissionservice.IPermissionService,
+	ssess sessionservice.ISessionService,
+	ssvac serviceaccountservice.IServiceAccountService,
+) {
+	r := &webhookroutes{
+		cfg:   cfg,
+		swebh: swebh,
+		sperm: sperm,
+		ssess: ssess,
+		ssvac: ssvac,
+	}
+
+	gwebhook := rg.Group("webhooks")
+	gwebhook.Use(middlewares.JWTOrAPIKeyAuthMiddleware(cfg, ssess, ssvac))
+	{
+		gwebhook.POST("", middlewares.PermissionMiddleware(sperm, consttypes.P_WEBHOOK_MANAGE), r.createWebhook)
+		gwebhook.GET("", middlewares.PermissionMiddleware(sperm, consttypes.P_WEBHOOK_MANAGE), r.readWebhooks)
+		gwebhook.GET("/:wid", middlewares.PermissionMiddleware(sperm, consttypes.P_WEBHOOK_MANAGE), r.getWebhook)
+		gwebhook.PUT("/:wid", middlewares.PermissionMiddleware(sperm, consttypes.P_WEBHOOK_MANAGE), r.updateWebhook)
+		gwebhook.DELETE("/:wid", middlewares.PermissionMiddleware(sperm, consttypes.P_WEBHOOK_MANAGE), r.deleteWebhook)
+		gwebhook.POST("/:wid/ping", middlewares.PermissionMiddleware(sperm, consttypes.P_WEBHOOK_MANAGE), r.pingWebhook)
+		gwebhook.GET("/:wid/deliveries", middlewares.PermissionMiddleware(sperm, consttypes.P_WEBHOOK_MANAGE), r.findWebhookDeliveries)
+		gwebhook.POST("/:wid/deliveries/:did/redeliver", middlewares.PermissionMiddleware(sperm, consttypes.P_WEBHOOK_MANAGE), r.redeliverWebhook)
+	}
+}
+
+func (r *webhookroutes) createWebhook(ctx *gin.Context) {
+	var (
+		function = "create webhook"
+		entity   = "webhook"
+		req      requests.CreateWebhook
+	)
+
+	err := ctx.ShouldBind(&req)
+	if err != nil {
+		ve := utresponse.ValidationResponse(err)
+		utresponse.GeneralInvalidRequest(
+			function,
+			ctx,
+			ve,
+			err,
+		)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		utresponse.GeneralInvalidRequest(
+			function,
+			ctx,
+			nil,
+			err,
+		)
+		return
+	}
+
+	userres, err := uttoken.GetUser(ctx)
+	if err != nil {
+		utresponse.GeneralUnauthorized(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	whres, err := r.swebh.Create(ctx, userres.ID, req)
+	if err != nil {
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessCreate(
+		entity,
+		ctx,
+		whres,
+	)
+}
+
+func (r *webhookroutes) readWebhooks(ctx *gin.Context) {
+	var (
+		function = "read webhooks"
+		entity   = "webhooks"
+	)
+
+	userres, err := uttoken.GetUser(ctx)
+	if err != nil {
+		utresponse.GeneralUnauthorized(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	whsres, err := r.swebh.Read(ctx, userres.ID)
+	if err != nil {
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessFetch(
+		entity,
+		ctx,
+		whsres,
+	)
+}
+
+func (r *webhookroutes) getWebhook(ctx *gin.Context) {
+	var (
+		function = "get webhook"
+		entity   = "webhook"
+	)
+
+	wid, err := uuid.Parse(ctx.Param("wid"))
+	if err != nil {
+		utresponse.GeneralInputRequiredError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	userres, err := uttoken.GetUser(ctx)
+	if err != nil {
+		utresponse.GeneralUnauthorized(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	whres, err := r.swebh.GetByID(ctx, userres.ID, wid)
+	if err != nil {
+		if errors.Is(err, consttypes.ErrWebhookNotFound) {
+			utresponse.GeneralNotFound(
+				entity,
+				ctx,
+				err,
+			)
+			return
+		}
+
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessFetch(
+		entity,
+		ctx,
+		whres,
+	)
+}
+
+func (r *webhookroutes) updateWebhook(ctx *gin.Context) {
+	var (
+		function = "update webhook"
+		entity   = "webhook"
+		req      requests.UpdateWebhook
+	)
+
+	wid, err := uuid.Parse(ctx.Param("wid"))
+	if err != nil {
+		utresponse.GeneralInputRequiredError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	err = ctx.ShouldBind(&req)
+	if err != nil {
+		ve := utresponse.ValidationResponse(err)
+		utresponse.GeneralInvalidRequest(
+			function,
+			ctx,
+			ve,
+			err,
+		)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		utresponse.GeneralInvalidRequest(
+			function,
+			ctx,
+			nil,
+			err,
+		)
+		return
+	}
+
+	userres, err := uttoken.GetUser(ctx)
+	if err != nil {
+		utresponse.GeneralUnauthorized(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	whres, err := r.swebh.Update(ctx, userres.ID, wid, req)
+	if err != nil {
+		if errors.Is(err, consttypes.ErrWebhookNotFound) {
+			utresponse.GeneralNotFound(
+				entity,
+				ctx,
+				err,
+			)
+			return
+		}
+
+		utresponse.GeneralFailedUpdate(
+			entity,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessUpdate(
+		entity,
+		ctx,
+		whres,
+	)
+}
+
+func (r *webhookroutes) deleteWebhook(ctx *gin.Context) {
+	var (
+		function = "delete webhook"
+		entity   = "webhook"
+	)
+
+	wid, err := uuid.Parse(ctx.Param("wid"))
+	if err != nil {
+		utresponse.GeneralInputRequiredError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	userres, err := uttoken.GetUser(ctx)
+	if err != nil {
+		utresponse.GeneralUnauthorized(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	err = r.swebh.Delete(ctx, userres.ID, wid)
+	if err != nil {
+		if errors.Is(err, consttypes.ErrWebhookNotFound) {
+			utresponse.GeneralNotFound(
+				entity,
+				ctx,
+				err,
+			)
+			return
+		}
+
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessDelete(
+		entity,
+		ctx,
+		nil,
+	)
+}
+
+func (r *webhookroutes) pingWebhook(ctx *gin.Context) {
+	var (
+		function = "ping webhook"
+		entity   = "webhook delivery"
+	)
+
+	wid, err := uuid.Parse(ctx.Param("wid"))
+	if err != nil {
+		utresponse.GeneralInputRequiredError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	userres, err := uttoken.GetUser(ctx)
+	if err != nil {
+		utresponse.GeneralUnauthorized(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	wdres, err := r.swebh.Ping(ctx, userres.ID, wid)
+	if err != nil {
+		if errors.Is(err, consttypes.ErrWebhookNotFound) {
+			utresponse.GeneralNotFound(
+				"webhook",
+				ctx,
+				err,
+			)
+			return
+		}
+
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessCreate(
+		entity,
+		ctx,
+		wdres,
+	)
+}
+
+func (r *webhookroutes) findWebhookDeliveries(ctx *gin.Context) {
+	var (
+		function = "find webhook deliveries"
+		entity   = "webhook deliveries"
+		reqpage  = utrequest.GeneratePaginationFromRequest(ctx)
+	)
+
+	wid, err := uuid.Parse(ctx.Param("wid"))
+	if err != nil {
+		utresponse.GeneralInputRequiredError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	userres, err := uttoken.GetUser(ctx)
+	if err != nil {
+		utresponse.GeneralUnauthorized(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	wdsres, err := r.swebh.FindDeliveries(ctx, userres.ID, wid, reqpage)
+	if err != nil {
+		if errors.Is(err, consttypes.ErrWebhookNotFound) {
+			utresponse.GeneralNotFound(
+				"webhook",
+				ctx,
+				err,
+			)
+			return
+		}
+
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessFetch(
+		entity,
+		ctx,
+		wdsres,
+	)
+}
+
+func (r *webhookroutes) redeliverWebhook(ctx *gin.Context) {
+	var (
+		function = "redeliver webhook"
+		entity   = "webhook delivery"
+	)
+
+	wid, err := uuid.Parse(ctx.Param("wid"))
+	if err != nil {
+		utresponse.GeneralInputRequiredError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	did, err := uuid.Parse(ctx.Param("did"))
+	if err != nil {
+		utresponse.GeneralInputRequiredError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	userres, err := uttoken.GetUser(ctx)
+	if err != nil {
+		utresponse.GeneralUnauthorized(
+			ctx,
+			err,
+		)
+		return
+	}
+
+	wdres, err := r.swebh.Redeliver(ctx, userres.ID, wid, did)
+	if err != nil {
+		if errors.Is(err, consttypes.ErrWebhookNotFound) || errors.Is(err, consttypes.ErrWebhookDeliveryNotFound) {
+			utresponse.GeneralNotFound(
+				entity,
+				ctx,
+				err,
+			)
+			return
+		}
+
+		utresponse.GeneralInternalServerError(
+			function,
+			ctx,
+			err,
+		)
+		return
+	}
+
+	utresponse.GeneralSuccessCreate(
+		entity,
+		ctx,
+		wdres,
+	)
+}
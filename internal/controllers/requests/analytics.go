@@ -0,0 +1,63 @@
+package requests
+
+import (
+	"project-skbackend/packages/consttypes"
+	"time"
+)
+
+type (
+	// * the dates are local dates in the api timezone, both are inclusive
+	AnalyticsRange struct {
+		From   string                     `form:"from" binding:"omitempty,datetime=2006-01-02"`
+		To     string                     `form:"to" binding:"omitempty,datetime=2006-01-02"`
+		Bucket consttypes.AnalyticsBucket `form:"bucket" binding:"omitempty,oneof=day week month"`
+		Limit  int                        `form:"limit" binding:"omitempty,min=1,max=100"`
+	}
+)
+
+// * resolves the range to the start of the first day and the start
+// * of the day after the last one, the defaults are the last days
+// * up to today by day
+func (req *AnalyticsRange) Resolve(loc *time.Location, defaultdays int, maxdays int) (time.Time, time.Time, error) {
+	var (
+		now   = time.Now().In(loc)
+		today = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+		to    = today.AddDate(0, 0, 1)
+		from  = to.AddDate(0, 0, -defaultdays)
+	)
+
+	if req.To != "" {
+		date, err := time.ParseInLocation(consttypes.DATEFORMAT, req.To, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, consttypes.ErrAnalyticsRangeInvalid
+		}
+
+		to = date.AddDate(0, 0, 1)
+		if req.From == "" {
+			from = to.AddDate(0, 0, -defaultdays)
+		}
+	}
+
+	if req.From != "" {
+		date, err := time.ParseInLocation(consttypes.DATEFORMAT, req.From, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, consttypes.ErrAnalyticsRangeInvalid
+		}
+
+		from = date
+	}
+
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, consttypes.ErrAnalyticsRangeInvalid
+	}
+
+	if to.Sub(from) > time.Duration(maxdays)*24*time.Hour {
+		return time.Time{}, time.Time{}, consttypes.ErrAnalyticsRangeTooLong
+	}
+
+	if req.Bucket == "" {
+		req.Bucket = consttypes.AB_DAY
+	}
+
+	return from, to, nil
+}
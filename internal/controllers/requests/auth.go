@@ -30,6 +30,10 @@ type (
 		Token           string `json:"token" form:"token" binding:"required"`
 	}
 
+	UnlockAccount struct {
+		Token string `json:"token" form:"token" binding:"required"`
+	}
+
 	ResetPasswordRedirect struct {
 		ResetToken string `uri:"token" binding:"required"`
 	}
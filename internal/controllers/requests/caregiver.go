@@ -5,6 +5,8 @@ import (
 	"project-skbackend/packages/consttypes"
 	"project-skbackend/packages/customs/ctdatatype"
 	"project-skbackend/packages/utils/utlogger"
+	"strings"
+	"time"
 
 	"github.com/jinzhu/copier"
 )
@@ -27,6 +29,28 @@ type (
 		LastName    string              `json:"last_name" form:"last_name" binding:"omitempty"`
 		DateOfBirth ctdatatype.CDT_DATE `json:"date_of_birth" form:"date_of_birth" binding:"omitempty"`
 	}
+
+	UpdateMemberCaregiver struct {
+		CanOrder      *bool `json:"can_order" form:"can_order" binding:"omitempty"`
+		CanEditHealth *bool `json:"can_edit_health" form:"can_edit_health" binding:"omitempty"`
+	}
+
+	CreateCaregiverInvitation struct {
+		Email         string `json:"email" form:"email" binding:"required,email"`
+		CanOrder      bool   `json:"can_order" form:"can_order" binding:"-"`
+		CanEditHealth bool   `json:"can_edit_health" form:"can_edit_health" binding:"-"`
+	}
+
+	AcceptCaregiverInvitation struct {
+		Token string `json:"token" form:"token" binding:"required"`
+
+		// * only required when the invited email does not have an account yet
+		Caregiver *CreateCaregiver `json:"caregiver" form:"caregiver" binding:"omitempty"`
+	}
+
+	DeclineCaregiverInvitation struct {
+		Token string `json:"token" form:"token" binding:"required"`
+	}
 )
 
 func (req *CreateCaregiver) ToModel() (*models.Caregiver, error) {
@@ -127,3 +151,33 @@ func (req *UpdateCaregiver) ToModel(
 
 	return caregiver, nil
 }
+
+func (req *UpdateMemberCaregiver) ToModel(
+	mc models.MemberCaregiver,
+) *models.MemberCaregiver {
+	if req.CanOrder != nil {
+		mc.CanOrder = *req.CanOrder
+	}
+
+	if req.CanEditHealth != nil {
+		mc.CanEditHealth = *req.CanEditHealth
+	}
+
+	return &mc
+}
+
+func (req *CreateCaregiverInvitation) ToModel(
+	member models.Member,
+	token string,
+	expiresat time.Time,
+) *models.CaregiverInvitation {
+	return &models.CaregiverInvitation{
+		MemberID:      member.ID,
+		Email:         strings.ToLower(req.Email),
+		Token:         token,
+		CanOrder:      req.CanOrder,
+		CanEditHealth: req.CanEditHealth,
+		Status:        consttypes.CIS_PENDING,
+		ExpiresAt:     expiresat,
+	}
+}
@@ -12,6 +12,9 @@ type (
 	CreateCart struct {
 		MealID   uuid.UUID `json:"meal_id" form:"meal_id" binding:"required"`
 		Quantity int       `json:"quantity" form:"quantity" binding:"required"`
+
+		// * required when a caregiver adds the cart on behalf of a member
+		MemberID *uuid.UUID `json:"member_id" form:"member_id" binding:"-"`
 	}
 
 	UpdateCart struct {
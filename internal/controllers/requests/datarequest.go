@@ -0,0 +1,8 @@
+package requests
+
+type (
+	// * the password is asked again since the erasure cannot be undone
+	CreateDataErasure struct {
+		Password string `json:"password" form:"password" binding:"required"`
+	}
+)
@@ -22,12 +22,25 @@ type (
 		LinkUrl          string `validate:"required"`
 	}
 
+	SendEmailCaregiverInvitation struct {
+		MemberName string `validate:"required"`
+		Email      string `validate:"required,email"`
+		LinkUrl    string `validate:"required"`
+	}
+
 	SendEmailSurveyResult struct {
 		Name    string `validate:"required"`
 		Email   string `validate:"required,email"`
 		LinkUrl string `validate:"required"`
 	}
 
+	SendEmailAccountLocked struct {
+		Name        string `validate:"required"`
+		Email       string `validate:"required,email"`
+		LockedUntil string `validate:"required"`
+		LinkUrl     string `validate:"required"`
+	}
+
 	SendEmailVerification struct {
 		Name  string `validate:"required"`
 		Email string `validate:"required,email"`
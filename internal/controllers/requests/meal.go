@@ -20,6 +20,8 @@ type (
 		Name        string                `json:"name" form:"name" binding:"required"`
 		Status      consttypes.MealStatus `json:"status" form:"status" binding:"required"`
 		Description string                `json:"description" form:"description" binding:"required"`
+
+		MealCategoryID *uuid.UUID `json:"meal_category_id" form:"meal_category_id" binding:"-"`
 	}
 
 	UpdateMeal struct {
@@ -31,6 +33,9 @@ type (
 		Name        string                `json:"name" form:"name" binding:"required"`
 		Status      consttypes.MealStatus `json:"status" form:"status" binding:"required"`
 		Description string                `json:"description" form:"description" binding:"required"`
+
+		// * an empty category removes the meal from its category
+		MealCategoryID *uuid.UUID `json:"meal_category_id" form:"meal_category_id" binding:"-"`
 	}
 
 	CreateMealCategory struct {
@@ -66,6 +71,7 @@ func (req *CreateMeal) ToModel(
 	meal.Illnesses = illnesses
 	meal.Allergies = allergies
 	meal.Partner = partner
+	meal.MealCategoryID = req.MealCategoryID
 
 	return &meal, nil
 }
@@ -87,6 +93,8 @@ func (req *UpdateMeal) ToModel(
 	meal.Illnesses = illnesses
 	meal.Allergies = allergies
 	meal.Partner = partner
+	meal.MealCategoryID = req.MealCategoryID
+	meal.MealCategory = nil
 
 	return &meal, nil
 }
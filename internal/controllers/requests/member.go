@@ -32,8 +32,6 @@ type (
 	UpdateMember struct {
 		User UpdateUser `json:"user" form:"user" binding:"omitempty,dive"`
 
-		Caregiver *UpdateCaregiver `json:"caregiver" form:"caregiver" binding:"omitempty"`
-
 		Height         float64             `json:"height" form:"height" binding:"-"`
 		Weight         float64             `json:"weight" form:"weight" binding:"-"`
 		FirstName      string              `json:"first_name" form:"first_name" binding:"-"`
@@ -44,11 +42,18 @@ type (
 		IllnessID      []*uuid.UUID        `json:"illness_id" form:"illness_id" binding:"-"`
 		AllergyID      []*uuid.UUID        `json:"allergy_id" form:"allergy_id" binding:"-"`
 	}
+
+	UpdateMemberHealth struct {
+		Height    float64      `json:"height" form:"height" binding:"-"`
+		Weight    float64      `json:"weight" form:"weight" binding:"-"`
+		IllnessID []*uuid.UUID `json:"illness_id" form:"illness_id" binding:"-"`
+		AllergyID []*uuid.UUID `json:"allergy_id" form:"allergy_id" binding:"-"`
+	}
 )
 
 func (req *CreateMember) ToModel(
 	user models.User,
-	caregiver *models.Caregiver,
+	caregivers []*models.MemberCaregiver,
 	allergies []*models.MemberAllergy,
 	illnesses []*models.MemberIllness,
 	organization *models.Organization,
@@ -63,7 +68,7 @@ func (req *CreateMember) ToModel(
 	}
 
 	member.User = user
-	member.Caregiver = caregiver
+	member.Caregivers = caregivers
 	member.Allergies = allergies
 	member.Illnesses = illnesses
 	member.Organization = organization
@@ -75,7 +80,6 @@ func (req *CreateMember) ToModel(
 func (req *UpdateMember) ToModel(
 	member models.Member,
 	user models.User,
-	caregiver *models.Caregiver,
 	allergies []*models.MemberAllergy,
 	illnesses []*models.MemberIllness,
 	organization *models.Organization,
@@ -90,7 +94,6 @@ func (req *UpdateMember) ToModel(
 	member.Illnesses = nil
 
 	member.User = user
-	member.Caregiver = caregiver
 	member.Allergies = allergies
 	member.Illnesses = illnesses
 	member.Organization = organization
@@ -104,6 +107,26 @@ func (req *UpdateMember) ToModel(
 	return &member, nil
 }
 
+func (req *UpdateMemberHealth) ToModel(
+	member models.Member,
+	allergies []*models.MemberAllergy,
+	illnesses []*models.MemberIllness,
+) *models.Member {
+	if req.Height != 0 {
+		member.Height = req.Height
+	}
+
+	if req.Weight != 0 {
+		member.Weight = req.Weight
+	}
+
+	member.Allergies = allergies
+	member.Illnesses = illnesses
+	member.BMI = utmath.BMICalculation(member.Weight, member.Height)
+
+	return &member
+}
+
 func (req *CreateMember) ToSignin() *Signin {
 	return &Signin{
 		Email:    req.User.Email,
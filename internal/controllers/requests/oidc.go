@@ -0,0 +1,82 @@
+package requests
+
+import (
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/customs/ctdatatype"
+	"project-skbackend/packages/utils/utlogger"
+
+	"github.com/google/uuid"
+	"github.com/jinzhu/copier"
+)
+
+type (
+	OIDCCallback struct {
+		Code  string `json:"code" form:"code" binding:"required"`
+		State string `json:"state" form:"state" binding:"required"`
+	}
+
+	OIDCSignup struct {
+		SignupToken string `json:"signup_token" form:"signup_token" binding:"required"`
+	}
+
+	// * the user of the oidc registrations comes from the signup token,
+	// * the rest mirrors the password registration of each role
+	OIDCRegisterMember struct {
+		OIDCSignup
+
+		Caregiver *CreateCaregiver `json:"caregiver" form:"caregiver" binding:"-"`
+
+		Height         float64             `json:"height" form:"height" binding:"required"`
+		Weight         float64             `json:"weight" form:"weight" binding:"required"`
+		FirstName      string              `json:"first_name" form:"first_name" binding:"required"`
+		LastName       string              `json:"last_name" form:"last_name" binding:"required"`
+		Gender         consttypes.Gender   `json:"gender" form:"gender" binding:"required"`
+		DateOfBirth    ctdatatype.CDT_DATE `json:"date_of_birth" form:"date_of_birth" binding:"required"`
+		OrganizationID *uuid.UUID          `json:"organization_id" form:"organization_id" binding:"-"`
+		IllnessID      []*uuid.UUID        `json:"illness_id" form:"illness_id" binding:"-"`
+		AllergyID      []*uuid.UUID        `json:"allergy_id" form:"allergy_id" binding:"-"`
+	}
+
+	OIDCRegisterPatron struct {
+		OIDCSignup
+
+		Type consttypes.PatronType `json:"type" form:"type" binding:"required"`
+		Name string                `json:"name" form:"name" binding:"required"`
+	}
+
+	OIDCRegisterPartner struct {
+		OIDCSignup
+
+		Name string `json:"name" form:"name" binding:"required"`
+	}
+)
+
+func (req *OIDCRegisterMember) ToCreateMember(user CreateUser) (*CreateMember, error) {
+	var (
+		creq CreateMember
+	)
+
+	if err := copier.CopyWithOption(&creq, &req, copier.Option{IgnoreEmpty: true, DeepCopy: true}); err != nil {
+		utlogger.Error(err)
+		return nil, err
+	}
+
+	creq.User = user
+
+	return &creq, nil
+}
+
+func (req *OIDCRegisterPatron) ToCreatePatron(user CreateUser) *CreatePatron {
+	return &CreatePatron{
+		User: user,
+		Type: req.Type,
+		Name: req.Name,
+	}
+}
+
+func (req *OIDCRegisterPartner) ToCreatePartner(user CreateUser) *CreatePartner {
+	return &CreatePartner{
+		User: user,
+		Name: req.Name,
+	}
+}
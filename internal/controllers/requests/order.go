@@ -12,6 +12,9 @@ import (
 type (
 	CreateOrder struct {
 		CartIDs []uuid.UUID `json:"cart_ids" form:"cart_ids" binding:"required"`
+
+		// * required when a caregiver orders on behalf of a member
+		MemberID *uuid.UUID `json:"member_id" form:"member_id" binding:"-"`
 	}
 )
 
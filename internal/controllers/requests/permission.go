@@ -0,0 +1,21 @@
+package requests
+
+import (
+	"project-skbackend/packages/consttypes"
+)
+
+type (
+	UpdateRolePermissions struct {
+		Permissions []consttypes.Permission `json:"permissions" form:"permissions" binding:"required"`
+	}
+)
+
+func (req *UpdateRolePermissions) Validate() error {
+	for _, p := range req.Permissions {
+		if !p.IsValid() {
+			return consttypes.ErrInvalidPermission(p)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package requests
+
+import (
+	"project-skbackend/packages/consttypes"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// * the ids are separated by commas like the meal category filter of
+	// * the meal list, allergy-free leaves out the meals with the allergies
+	MealSearch struct {
+		Query       string `form:"q" binding:"required,max=100"`
+		CategoryIDs string `form:"meal-category-id"`
+		PartnerIDs  string `form:"partner-id"`
+		AllergyFree string `form:"allergy-free"`
+		Page        int    `form:"page" binding:"omitempty,min=1"`
+		Limit       int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	}
+)
+
+func (req *MealSearch) ParseIDs(raw string) ([]uuid.UUID, error) {
+	var (
+		ids []uuid.UUID
+	)
+
+	for _, value := range strings.Split(raw, ",") {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return nil, consttypes.ErrSearchInvalidID
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,48 @@
+package requests
+
+import (
+	"project-skbackend/packages/consttypes"
+
+	"github.com/google/uuid"
+)
+
+type (
+	CreateServiceAccount struct {
+		UserID      uuid.UUID               `json:"user_id" form:"user_id" binding:"required"`
+		Name        string                  `json:"name" form:"name" binding:"required"`
+		Description string                  `json:"description" form:"description"`
+		Scopes      []consttypes.Permission `json:"scopes" form:"scopes" binding:"required,min=1"`
+	}
+
+	UpdateServiceAccount struct {
+		Name        string                  `json:"name" form:"name" binding:"required"`
+		Description string                  `json:"description" form:"description"`
+		Scopes      []consttypes.Permission `json:"scopes" form:"scopes" binding:"required,min=1"`
+		Disabled    bool                    `json:"disabled" form:"disabled"`
+	}
+
+	CreateAPIKey struct {
+		Name string `json:"name" form:"name" binding:"required"`
+
+		// * life of the key in days, the configured default is used when empty
+		Life int `json:"life" form:"life" binding:"omitempty,min=1"`
+	}
+)
+
+func (req *CreateServiceAccount) Validate() error {
+	return validateScopes(req.Scopes)
+}
+
+func (req *UpdateServiceAccount) Validate() error {
+	return validateScopes(req.Scopes)
+}
+
+func validateScopes(scopes []consttypes.Permission) error {
+	for _, scope := range scopes {
+		if !scope.IsValid() {
+			return consttypes.ErrInvalidPermission(scope)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,27 @@
+package requests
+
+type (
+	TwoFactorChallenge struct {
+		ChallengeToken string `json:"challenge_token" form:"challenge_token" binding:"required"`
+	}
+
+	TwoFactorCode struct {
+		// * either the code of the authenticator app or an unused recovery code
+		Code         string `json:"code" form:"code" binding:"omitempty,len=6,numeric"`
+		RecoveryCode string `json:"recovery_code" form:"recovery_code"`
+	}
+
+	VerifyTwoFactor struct {
+		TwoFactorChallenge
+		TwoFactorCode
+	}
+
+	ConfirmTwoFactor struct {
+		Code string `json:"code" form:"code" binding:"required,len=6,numeric"`
+	}
+
+	ConfirmTwoFactorChallenge struct {
+		TwoFactorChallenge
+		ConfirmTwoFactor
+	}
+)
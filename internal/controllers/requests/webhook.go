@@ -0,0 +1,55 @@
+package requests
+
+import (
+	"net/url"
+	"project-skbackend/packages/consttypes"
+
+	"github.com/google/uuid"
+)
+
+type (
+	CreateWebhook struct {
+		URL         string                        `json:"url" form:"url" binding:"required,url"`
+		Description string                        `json:"description" form:"description"`
+		EventTypes  []consttypes.WebhookEventType `json:"event_types" form:"event_types" binding:"required,min=1"`
+
+		// * a random secret is generated when empty
+		Secret string `json:"secret" form:"secret" binding:"omitempty,min=16"`
+	}
+
+	UpdateWebhook struct {
+		URL         string                        `json:"url" form:"url" binding:"required,url"`
+		Description string                        `json:"description" form:"description"`
+		EventTypes  []consttypes.WebhookEventType `json:"event_types" form:"event_types" binding:"required,min=1"`
+		Active      bool                          `json:"active" form:"active"`
+	}
+
+	// * message published to the webhook queue, the delivery
+	// * itself is read from the database by the consumer
+	DeliverWebhook struct {
+		DeliveryID uuid.UUID `json:"delivery_id"`
+	}
+)
+
+func (req *CreateWebhook) Validate() error {
+	return validateWebhook(req.URL, req.EventTypes)
+}
+
+func (req *UpdateWebhook) Validate() error {
+	return validateWebhook(req.URL, req.EventTypes)
+}
+
+func validateWebhook(rawurl string, wets []consttypes.WebhookEventType) error {
+	u, err := url.Parse(rawurl)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return consttypes.ErrWebhookURLInvalid
+	}
+
+	for _, wet := range wets {
+		if !wet.IsValid() {
+			return consttypes.ErrInvalidWebhookEventType(wet)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package responses
+
+import (
+	"project-skbackend/packages/consttypes"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// * the buckets are the local date the bucket starts on,
+	// * the monday of the week for the weekly buckets
+	AnalyticsOrders struct {
+		Bucket   string                           `json:"bucket"`
+		Total    int64                            `json:"total"`
+		Statuses map[consttypes.OrderStatus]int64 `json:"statuses"`
+	}
+
+	AnalyticsOrderStatus struct {
+		Bucket string                 `json:"bucket"`
+		Status consttypes.OrderStatus `json:"status"`
+		Count  int64                  `json:"count"`
+	}
+
+	AnalyticsCancellations struct {
+		Total     int64                         `json:"total"`
+		Cancelled int64                         `json:"cancelled"`
+		Rate      float64                       `json:"rate"`
+		Buckets   []AnalyticsCancellationBucket `json:"buckets"`
+		Causes    []AnalyticsCancellationCause  `json:"causes"`
+	}
+
+	AnalyticsCancellationBucket struct {
+		Bucket    string  `json:"bucket"`
+		Total     int64   `json:"total"`
+		Cancelled int64   `json:"cancelled"`
+		Rate      float64 `json:"rate"`
+	}
+
+	// * the status the order was left in before it was cancelled and
+	// * the role of the user who cancelled it, the admin role stands
+	// * for the automatic cancellation of the orders not confirmed
+	AnalyticsCancellationCause struct {
+		PreviousStatus consttypes.OrderStatus `json:"previous_status"`
+		CancelledBy    *consttypes.UserRole   `json:"cancelled_by"`
+		Count          int64                  `json:"count"`
+	}
+
+	AnalyticsTopMeal struct {
+		Bucket   string    `json:"bucket"`
+		Rank     int       `json:"rank"`
+		MealID   uuid.UUID `json:"meal_id"`
+		Name     string    `json:"name"`
+		Orders   int64     `json:"orders"`
+		Quantity int64     `json:"quantity"`
+	}
+
+	AnalyticsTopPartner struct {
+		Bucket    string    `json:"bucket"`
+		Rank      int       `json:"rank"`
+		PartnerID uuid.UUID `json:"partner_id"`
+		Name      string    `json:"name"`
+		Orders    int64     `json:"orders"`
+		Quantity  int64     `json:"quantity"`
+	}
+
+	// * members are active when they have an order in the bucket
+	AnalyticsActiveMembers struct {
+		Bucket         string    `json:"bucket"`
+		OrganizationID uuid.UUID `json:"organization_id"`
+		Name           string    `json:"name"`
+		ActiveMembers  int64     `json:"active_members"`
+	}
+
+	AnalyticsDonations struct {
+		Bucket     string                    `json:"bucket"`
+		Status     consttypes.DonationStatus `json:"status"`
+		PatronType consttypes.PatronType     `json:"patron_type"`
+		Count      int64                     `json:"count"`
+		Total      float64                   `json:"total"`
+	}
+
+	// * the time from the confirmation to the preparation of the
+	// * orders, bucketed by the time they were confirmed
+	AnalyticsPrepTime struct {
+		Bucket     string  `json:"bucket"`
+		Orders     int64   `json:"orders"`
+		AvgSeconds float64 `json:"avg_seconds"`
+		P90Seconds float64 `json:"p90_seconds"`
+	}
+
+	// * the kitchen performance of a partner, the seconds are
+	// * zero for a bucket without a confirmed or prepared order
+	AnalyticsKitchen struct {
+		Bucket            string  `json:"bucket"`
+		Orders            int64   `json:"orders"`
+		Confirmed         int64   `json:"confirmed"`
+		AvgConfirmSeconds float64 `json:"avg_confirm_seconds"`
+		P90ConfirmSeconds float64 `json:"p90_confirm_seconds"`
+		Prepared          int64   `json:"prepared"`
+		AvgPrepareSeconds float64 `json:"avg_prepare_seconds"`
+		P90PrepareSeconds float64 `json:"p90_prepare_seconds"`
+		Cancelled         int64   `json:"cancelled"`
+		AutoCancelled     int64   `json:"auto_cancelled"`
+	}
+
+	AnalyticsHourlyOrders struct {
+		Hour      int   `json:"hour"`
+		Orders    int64 `json:"orders"`
+		Cancelled int64 `json:"cancelled"`
+	}
+
+	// * the average is weighted by the ratings of every meal
+	AnalyticsRatings struct {
+		Ratings int64                 `json:"ratings"`
+		Average float64               `json:"average"`
+		Meals   []AnalyticsMealRating `json:"meals"`
+	}
+
+	AnalyticsMealRating struct {
+		MealID  uuid.UUID `json:"meal_id"`
+		Name    string    `json:"name"`
+		Ratings int64     `json:"ratings"`
+		Average float64   `json:"average"`
+	}
+
+	AnalyticsPrepList struct {
+		Date     string                  `json:"date"`
+		Orders   int64                   `json:"orders"`
+		Quantity int64                   `json:"quantity"`
+		Meals    []AnalyticsPrepListMeal `json:"meals"`
+	}
+
+	AnalyticsPrepListMeal struct {
+		MealID   uuid.UUID `json:"meal_id"`
+		Name     string    `json:"name"`
+		Orders   int64     `json:"orders"`
+		Quantity int64     `json:"quantity"`
+	}
+)
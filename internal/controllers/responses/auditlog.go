@@ -0,0 +1,39 @@
+package responses
+
+import (
+	"project-skbackend/internal/models/base"
+	"project-skbackend/packages/consttypes"
+
+	"github.com/google/uuid"
+)
+
+type (
+	AuditLog struct {
+		base.Model
+
+		ActorID    uuid.UUID           `json:"actor_id"`
+		ActorEmail string              `json:"actor_email"`
+		ActorRole  consttypes.UserRole `json:"actor_role"`
+
+		Action   consttypes.AuditAction `json:"action"`
+		Entity   consttypes.AuditEntity `json:"entity"`
+		EntityID string                 `json:"entity_id,omitempty"`
+
+		Method     string `json:"method"`
+		Path       string `json:"path"`
+		Route      string `json:"route"`
+		StatusCode int    `json:"status_code"`
+		IP         string `json:"ip,omitempty"`
+		UserAgent  string `json:"user_agent,omitempty"`
+		Duration   int64  `json:"duration"`
+
+		Before  map[string]any         `json:"before,omitempty"`
+		After   map[string]any         `json:"after,omitempty"`
+		Changes map[string]AuditChange `json:"changes,omitempty"`
+	}
+
+	AuditChange struct {
+		From any `json:"from"`
+		To   any `json:"to"`
+	}
+)
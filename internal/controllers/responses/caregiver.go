@@ -4,6 +4,9 @@ import (
 	"project-skbackend/internal/models/base"
 	"project-skbackend/packages/consttypes"
 	"project-skbackend/packages/customs/ctdatatype"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 type (
@@ -17,4 +20,27 @@ type (
 		LastName    string              `json:"last_name"`
 		DateOfBirth ctdatatype.CDT_DATE `json:"date_of_birth"`
 	}
+
+	MemberCaregiver struct {
+		base.Model
+
+		Member    *Member    `json:"member,omitempty"`
+		Caregiver *Caregiver `json:"caregiver,omitempty"`
+
+		CanOrder      bool `json:"can_order"`
+		CanEditHealth bool `json:"can_edit_health"`
+	}
+
+	CaregiverInvitation struct {
+		base.Model
+
+		MemberID uuid.UUID `json:"member_id"`
+
+		Email         string                               `json:"email"`
+		CanOrder      bool                                 `json:"can_order"`
+		CanEditHealth bool                                 `json:"can_edit_health"`
+		Status        consttypes.CaregiverInvitationStatus `json:"status"`
+		ExpiresAt     time.Time                            `json:"expires_at"`
+		RespondedAt   *time.Time                           `json:"responded_at,omitempty"`
+	}
 )
@@ -0,0 +1,34 @@
+package responses
+
+import (
+	"project-skbackend/internal/models/base"
+	"project-skbackend/packages/consttypes"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	DataExport struct {
+		base.Model
+
+		UserID uuid.UUID `json:"user_id"`
+
+		Status      consttypes.DataRequestStatus `json:"status"`
+		Size        int64                        `json:"size,omitempty"`
+		Error       string                       `json:"error,omitempty"`
+		CompletedAt *time.Time                   `json:"completed_at,omitempty"`
+		ExpiresAt   *time.Time                   `json:"expires_at,omitempty"`
+	}
+
+	DataErasure struct {
+		base.Model
+
+		UserID        uuid.UUID `json:"user_id"`
+		RequestedByID uuid.UUID `json:"requested_by_id"`
+
+		Status      consttypes.DataRequestStatus `json:"status"`
+		Error       string                       `json:"error,omitempty"`
+		CompletedAt *time.Time                   `json:"completed_at,omitempty"`
+	}
+)
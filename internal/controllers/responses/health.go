@@ -0,0 +1,32 @@
+package responses
+
+import (
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/lifecycle"
+)
+
+type (
+	Health struct {
+		Status     consttypes.HealthStatus     `json:"status"`
+		Version    HealthVersion               `json:"version"`
+		Checks     []HealthCheck               `json:"checks,omitempty"`
+		Components []lifecycle.ComponentHealth `json:"components"`
+	}
+
+	// * the commit is read from the build info, empty when the binary
+	// * was not built from a git checkout
+	HealthVersion struct {
+		Name      string `json:"name"`
+		Version   string `json:"version"`
+		Env       string `json:"env"`
+		Commit    string `json:"commit,omitempty"`
+		GoVersion string `json:"go_version"`
+	}
+
+	HealthCheck struct {
+		Name      string                  `json:"name"`
+		Status    consttypes.HealthStatus `json:"status"`
+		LatencyMS float64                 `json:"latency_ms"`
+		Error     string                  `json:"error,omitempty"`
+	}
+)
@@ -19,6 +19,8 @@ type (
 
 		Partner Partner `json:"partner"`
 
+		MealCategory *MealCategory `json:"meal_category,omitempty"`
+
 		Name        string                `json:"name" gorm:"required" binding:"required" example:"Nasi Goyeng"`
 		Status      consttypes.MealStatus `json:"status" gorm:"required; type:meal_status_enum" binding:"required" example:"Active"`
 		Description string                `json:"description" gorm:"size:255" example:"This meal is made using chicken and egg."`
@@ -52,7 +54,7 @@ type (
 	}
 
 	MealCategory struct {
-		base.Model `json:"-"`
+		base.Model
 
 		Name string `json:"name" example:"Rice"`
 
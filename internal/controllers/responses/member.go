@@ -12,7 +12,7 @@ type (
 
 		User User `json:"user,omitempty"`
 
-		Caregiver *Caregiver `json:"caregiver,omitempty"`
+		Caregivers []*MemberCaregiver `json:"caregivers,omitempty"`
 
 		Organization *Organization `json:"organization,omitempty"`
 
@@ -0,0 +1,45 @@
+package responses
+
+import (
+	"project-skbackend/internal/models/base"
+	"project-skbackend/packages/consttypes"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	MemberHealthHistory struct {
+		base.Model
+
+		MemberID uuid.UUID `json:"member_id"`
+		Version  int       `json:"version"`
+
+		ChangedByID   *uuid.UUID           `json:"changed_by_id,omitempty"`
+		ChangedByRole *consttypes.UserRole `json:"changed_by_role,omitempty"`
+
+		Previous *MemberHealthSnapshot `json:"previous,omitempty"`
+		Current  MemberHealthSnapshot  `json:"current"`
+	}
+
+	MemberHealthSnapshot struct {
+		Height    float64                    `json:"height"`
+		Weight    float64                    `json:"weight"`
+		BMI       float64                    `json:"bmi"`
+		Illnesses []MemberHealthSnapshotItem `json:"illnesses"`
+		Allergies []MemberHealthSnapshotItem `json:"allergies"`
+	}
+
+	MemberHealthSnapshotItem struct {
+		ID   uuid.UUID `json:"id"`
+		Name string    `json:"name"`
+	}
+
+	MemberHealthTrend struct {
+		Version    int        `json:"version"`
+		RecordedAt *time.Time `json:"recorded_at"`
+		Height     float64    `json:"height"`
+		Weight     float64    `json:"weight"`
+		BMI        float64    `json:"bmi"`
+	}
+)
@@ -0,0 +1,23 @@
+package responses
+
+import (
+	"time"
+)
+
+type (
+	OIDCAuthorize struct {
+		AuthorizationURL string    `json:"authorization_url"`
+		State            string    `json:"state"`
+		ExpiresAt        time.Time `json:"expires_at"`
+	}
+
+	// * returned when the oidc account is not linked to any user yet,
+	// * the token is exchanged on one of the oidc registrations
+	OIDCSignup struct {
+		SignupToken string    `json:"signup_token"`
+		Email       string    `json:"email"`
+		FirstName   string    `json:"first_name,omitempty"`
+		LastName    string    `json:"last_name,omitempty"`
+		ExpiresAt   time.Time `json:"expires_at"`
+	}
+)
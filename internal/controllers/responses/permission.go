@@ -0,0 +1,20 @@
+package responses
+
+import (
+	"project-skbackend/internal/models/base"
+	"project-skbackend/packages/consttypes"
+)
+
+type (
+	RolePermission struct {
+		base.Model
+
+		Role       consttypes.UserRole   `json:"role"`
+		Permission consttypes.Permission `json:"permission"`
+	}
+
+	RolePermissions struct {
+		Role        consttypes.UserRole     `json:"role"`
+		Permissions []consttypes.Permission `json:"permissions"`
+	}
+)
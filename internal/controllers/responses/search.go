@@ -0,0 +1,50 @@
+package responses
+
+import (
+	"github.com/google/uuid"
+)
+
+type (
+	MealSearch struct {
+		Query      string             `json:"query"`
+		Limit      int                `json:"limit"`
+		Page       int                `json:"page"`
+		TotalDatas int64              `json:"total_datas"`
+		TotalPages int                `json:"total_pages"`
+		Datas      []MealSearchResult `json:"datas"`
+		Facets     MealSearchFacets   `json:"facets"`
+	}
+
+	MealSearchResult struct {
+		Meal       Meal                `json:"meal"`
+		Rank       float64             `json:"rank"`
+		Highlights MealSearchHighlight `json:"highlights"`
+	}
+
+	// * html escaped text, the matched words are wrapped in mark tags
+	MealSearchHighlight struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}
+
+	MealSearchHit struct {
+		ID          uuid.UUID `json:"id"`
+		Rank        float64   `json:"rank"`
+		Name        string    `json:"name"`
+		Description string    `json:"description"`
+	}
+
+	// * a facet counts the matches the filters of the other facets
+	// * leave, so picking a category still lists its siblings
+	MealSearchFacets struct {
+		Categories  []MealSearchFacet `json:"categories"`
+		Partners    []MealSearchFacet `json:"partners"`
+		AllergyFree []MealSearchFacet `json:"allergy_free"`
+	}
+
+	MealSearchFacet struct {
+		ID    uuid.UUID `json:"id"`
+		Name  string    `json:"name"`
+		Count int64     `json:"count"`
+	}
+)
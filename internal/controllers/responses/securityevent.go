@@ -0,0 +1,22 @@
+package responses
+
+import (
+	"project-skbackend/internal/models/base"
+	"project-skbackend/packages/consttypes"
+
+	"github.com/google/uuid"
+)
+
+type (
+	SecurityEvent struct {
+		base.Model
+
+		UserID *uuid.UUID `json:"user_id,omitempty"`
+		Email  string     `json:"email,omitempty"`
+
+		Type      consttypes.SecurityEventType `json:"type"`
+		IP        string                       `json:"ip,omitempty"`
+		UserAgent string                       `json:"user_agent,omitempty"`
+		Detail    string                       `json:"detail,omitempty"`
+	}
+)
@@ -0,0 +1,48 @@
+package responses
+
+import (
+	"project-skbackend/internal/models/base"
+	"project-skbackend/packages/consttypes"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	ServiceAccount struct {
+		base.Model
+
+		UserID      uuid.UUID               `json:"user_id"`
+		Name        string                  `json:"name"`
+		Description string                  `json:"description,omitempty"`
+		Scopes      []consttypes.Permission `json:"scopes"`
+		CreatedByID uuid.UUID               `json:"created_by_id"`
+		DisabledAt  *time.Time              `json:"disabled_at,omitempty"`
+	}
+
+	APIKey struct {
+		base.Model
+
+		ServiceAccountID uuid.UUID  `json:"service_account_id"`
+		Name             string     `json:"name"`
+		Prefix           string     `json:"prefix"`
+		ExpiresAt        time.Time  `json:"expires_at"`
+		LastUsedAt       *time.Time `json:"last_used_at,omitempty"`
+		LastUsedIP       string     `json:"last_used_ip,omitempty"`
+		RevokedAt        *time.Time `json:"revoked_at,omitempty"`
+	}
+
+	// * the plain key is only returned once, right after it is created
+	APIKeyCreated struct {
+		APIKey
+		Key string `json:"key"`
+	}
+
+	// * the principal of a request authenticated with an api key
+	APIKeyPrincipal struct {
+		User             User
+		APIKeyID         uuid.UUID
+		ServiceAccountID uuid.UUID
+		Scopes           []consttypes.Permission
+	}
+)
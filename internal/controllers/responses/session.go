@@ -0,0 +1,19 @@
+package responses
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	Session struct {
+		ID         uuid.UUID `json:"id"`
+		Device     string    `json:"device"`
+		IP         string    `json:"ip"`
+		Current    bool      `json:"current"`
+		CreatedAt  time.Time `json:"created_at"`
+		LastSeenAt time.Time `json:"last_seen_at"`
+		ExpiresAt  time.Time `json:"expires_at"`
+	}
+)
@@ -0,0 +1,38 @@
+package responses
+
+import (
+	"project-skbackend/packages/consttypes"
+	"time"
+)
+
+type (
+	TwoFactorChallenge struct {
+		Type           consttypes.TwoFactorChallengeType `json:"type"`
+		ChallengeToken string                            `json:"challenge_token"`
+		ExpiresAt      time.Time                         `json:"expires_at"`
+	}
+
+	TwoFactorEnrolment struct {
+		Secret string `json:"secret"`
+		URI    string `json:"uri"`
+
+		// * png of the uri as a data url, ready to be scanned by an authenticator app
+		QRCode string `json:"qr_code"`
+	}
+
+	TwoFactorRecoveryCodes struct {
+		RecoveryCodes []string `json:"recovery_codes"`
+	}
+
+	TwoFactorStatus struct {
+		Policy                 consttypes.TwoFactorPolicy `json:"policy"`
+		Enabled                bool                       `json:"enabled"`
+		EnabledAt              *time.Time                 `json:"enabled_at,omitempty"`
+		RemainingRecoveryCodes int64                      `json:"remaining_recovery_codes"`
+	}
+
+	TwoFactorAuth struct {
+		Auth
+		RecoveryCodes []string `json:"recovery_codes,omitempty"`
+	}
+)
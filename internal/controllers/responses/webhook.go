@@ -0,0 +1,68 @@
+package responses
+
+import (
+	"project-skbackend/internal/models/base"
+	"project-skbackend/packages/consttypes"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	Webhook struct {
+		base.Model
+
+		UserID      uuid.UUID                     `json:"user_id"`
+		URL         string                        `json:"url"`
+		Description string                        `json:"description,omitempty"`
+		EventTypes  []consttypes.WebhookEventType `json:"event_types"`
+		Active      bool                          `json:"active"`
+	}
+
+	// * the secret is only returned once, right after it is created
+	WebhookCreated struct {
+		Webhook
+		Secret string `json:"secret"`
+	}
+
+	WebhookDelivery struct {
+		base.Model
+
+		WebhookID      uuid.UUID                        `json:"webhook_id"`
+		EventID        uuid.UUID                        `json:"event_id"`
+		EventType      consttypes.WebhookEventType      `json:"event_type"`
+		Payload        string                           `json:"payload"`
+		Status         consttypes.WebhookDeliveryStatus `json:"status"`
+		Attempts       int                              `json:"attempts"`
+		NextAttemptAt  *time.Time                       `json:"next_attempt_at,omitempty"`
+		ResponseStatus int                              `json:"response_status,omitempty"`
+		ResponseBody   string                           `json:"response_body,omitempty"`
+		Error          string                           `json:"error,omitempty"`
+		Duration       int64                            `json:"duration,omitempty"`
+		DeliveredAt    *time.Time                       `json:"delivered_at,omitempty"`
+	}
+
+	// * body of every delivery, the data depends on the event type
+	WebhookEvent struct {
+		ID        uuid.UUID                   `json:"id"`
+		Type      consttypes.WebhookEventType `json:"type"`
+		CreatedAt time.Time                   `json:"created_at"`
+		Data      any                         `json:"data"`
+	}
+
+	WebhookOrder struct {
+		ID             uuid.UUID              `json:"id"`
+		MemberID       uuid.UUID              `json:"member_id"`
+		PartnerID      uuid.UUID              `json:"partner_id"`
+		Status         consttypes.OrderStatus `json:"status"`
+		PreviousStatus consttypes.OrderStatus `json:"previous_status,omitempty"`
+		Meals          []WebhookOrderMeal     `json:"meals"`
+		CreatedAt      *time.Time             `json:"created_at"`
+		UpdatedAt      *time.Time             `json:"updated_at"`
+	}
+
+	WebhookOrderMeal struct {
+		MealID   uuid.UUID `json:"meal_id"`
+		Quantity int       `json:"quantity"`
+	}
+)
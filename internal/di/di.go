@@ -4,17 +4,28 @@ import (
 	"context"
 	"project-skbackend/configs"
 	"project-skbackend/external/services/distancematrixservice"
+	"project-skbackend/external/services/oidcservice"
 	"project-skbackend/internal/repositories/adminrepo"
 	"project-skbackend/internal/repositories/allergyrepo"
+	"project-skbackend/internal/repositories/analyticsrepo"
+	"project-skbackend/internal/repositories/apikeyrepo"
+	"project-skbackend/internal/repositories/auditlogrepo"
+	"project-skbackend/internal/repositories/caregiverinvitationrepo"
 	"project-skbackend/internal/repositories/caregiverrepo"
 	"project-skbackend/internal/repositories/cartrepo"
+	"project-skbackend/internal/repositories/dataerasurerepo"
+	"project-skbackend/internal/repositories/dataexportrepo"
 	"project-skbackend/internal/repositories/donationproofrepo"
 	"project-skbackend/internal/repositories/donationrepo"
+	"project-skbackend/internal/repositories/encryptionrepo"
+	"project-skbackend/internal/repositories/identityrepo"
 	"project-skbackend/internal/repositories/illnessrepo"
 	"project-skbackend/internal/repositories/imagerepo"
 	"project-skbackend/internal/repositories/mealcategoryrepo"
 	"project-skbackend/internal/repositories/mealrepo"
 	"project-skbackend/internal/repositories/memberallergyrepo"
+	"project-skbackend/internal/repositories/membercaregiverrepo"
+	"project-skbackend/internal/repositories/memberhealthhistoryrepo"
 	"project-skbackend/internal/repositories/memberillnessrepo"
 	"project-skbackend/internal/repositories/memberrepo"
 	"project-skbackend/internal/repositories/ordermealrepo"
@@ -22,9 +33,17 @@ import (
 	"project-skbackend/internal/repositories/organizationrepo"
 	"project-skbackend/internal/repositories/partnerrepo"
 	"project-skbackend/internal/repositories/patronrepo"
+	"project-skbackend/internal/repositories/rolepermissionrepo"
+	"project-skbackend/internal/repositories/searchrepo"
+	"project-skbackend/internal/repositories/securityeventrepo"
+	"project-skbackend/internal/repositories/serviceaccountrepo"
+	"project-skbackend/internal/repositories/twofactorrepo"
 	"project-skbackend/internal/repositories/userimagerepo"
 	"project-skbackend/internal/repositories/userrepo"
+	"project-skbackend/internal/repositories/webhookrepo"
 	"project-skbackend/internal/services/allergyservice"
+	"project-skbackend/internal/services/analyticsservice"
+	"project-skbackend/internal/services/auditservice"
 	"project-skbackend/internal/services/authservice"
 	"project-skbackend/internal/services/baseroleservice"
 	"project-skbackend/internal/services/caregiverservice"
@@ -33,6 +52,8 @@ import (
 	"project-skbackend/internal/services/cronservice"
 	"project-skbackend/internal/services/donationservice"
 	"project-skbackend/internal/services/fileservice"
+	"project-skbackend/internal/services/healthservice"
+	"project-skbackend/internal/services/identityservice"
 	"project-skbackend/internal/services/illnessservice"
 	"project-skbackend/internal/services/mailservice"
 	"project-skbackend/internal/services/mealcategoryservice"
@@ -42,9 +63,17 @@ import (
 	"project-skbackend/internal/services/organizationservice"
 	"project-skbackend/internal/services/partnerservice"
 	"project-skbackend/internal/services/patronservice"
+	"project-skbackend/internal/services/permissionservice"
+	"project-skbackend/internal/services/privacyservice"
 	"project-skbackend/internal/services/producerservice"
+	"project-skbackend/internal/services/searchservice"
+	"project-skbackend/internal/services/securityservice"
+	"project-skbackend/internal/services/serviceaccountservice"
+	"project-skbackend/internal/services/sessionservice"
+	"project-skbackend/internal/services/twofactorservice"
 	"project-skbackend/internal/services/userservice"
-	"project-skbackend/packages/utils/utlogger"
+	"project-skbackend/internal/services/webhookservice"
+	"project-skbackend/packages/lifecycle"
 
 	"github.com/minio/minio-go/v7"
 	amqp "github.com/rabbitmq/amqp091-go"
@@ -54,36 +83,44 @@ import (
 
 type DependencyInjection struct {
 	// * internal services
-	UserService         *userservice.UserService
-	AuthService         *authservice.AuthService
-	MailService         *mailservice.MailService
-	MemberService       *memberservice.MemberService
-	PartnerService      *partnerservice.PartnerService
-	MealService         *mealservice.MealService
-	CartService         *cartservice.CartService
-	ConsumerService     *consumerservice.ConsumerService
-	PatronService       *patronservice.PatronService
-	OrganizationService *organizationservice.OrganizationService
-	OrderService        *orderservice.OrderService
-	CronService         *cronservice.CronService
-	IllnessService      *illnessservice.IllnessService
-	FileService         *fileservice.FileService
-	AllergyService      *allergyservice.AllergyService
-	DonationService     *donationservice.DonationService
-	MealCategoryService *mealcategoryservice.MealCategoryService
-	BaseRoleService     *baseroleservice.BaseRoleService
-	CaregiverService    *caregiverservice.CaregiverService
+	UserService           *userservice.UserService
+	AuthService           *authservice.AuthService
+	MailService           *mailservice.MailService
+	MemberService         *memberservice.MemberService
+	PartnerService        *partnerservice.PartnerService
+	MealService           *mealservice.MealService
+	CartService           *cartservice.CartService
+	ConsumerService       *consumerservice.ConsumerService
+	PatronService         *patronservice.PatronService
+	OrganizationService   *organizationservice.OrganizationService
+	OrderService          *orderservice.OrderService
+	CronService           *cronservice.CronService
+	IllnessService        *illnessservice.IllnessService
+	FileService           *fileservice.FileService
+	AllergyService        *allergyservice.AllergyService
+	DonationService       *donationservice.DonationService
+	MealCategoryService   *mealcategoryservice.MealCategoryService
+	BaseRoleService       *baseroleservice.BaseRoleService
+	CaregiverService      *caregiverservice.CaregiverService
+	PermissionService     *permissionservice.PermissionService
+	PrivacyService        *privacyservice.PrivacyService
+	SessionService        *sessionservice.SessionService
+	SecurityService       *securityservice.SecurityService
+	TwoFactorService      *twofactorservice.TwoFactorService
+	IdentityService       *identityservice.IdentityService
+	ServiceAccountService *serviceaccountservice.ServiceAccountService
+	WebhookService        *webhookservice.WebhookService
+	AuditService          *auditservice.AuditService
+	AnalyticsService      *analyticsservice.AnalyticsService
+	SearchService         *searchservice.SearchService
+	HealthService         *healthservice.HealthService
 
 	// * external services
 	DistanceMatrixService *distancematrixservice.DistanceMatrixService
+	OIDCService           *oidcservice.OIDCService
 }
 
 func NewDependencyInjection(ctx context.Context, db *gorm.DB, ch *amqp.Channel, cfg *configs.Config, rdb *redis.Client, minio *minio.Client) *DependencyInjection {
-	// ! -------------------------------- database -------------------------------- ! //
-	if cfg.DB.LogMode {
-		db = db.Debug()
-	}
-
 	// ! ------------------------------- repository ------------------------------- ! //
 	ruser := userrepo.NewUserRepository(db)
 	rpart := partnerrepo.NewPartnerRepository(db)
@@ -106,69 +143,108 @@ func NewDependencyInjection(ctx context.Context, db *gorm.DB, ch *amqp.Channel,
 	rorme := ordermealrepo.NewOrderMealRepository(db, cfg)
 	rmill := memberillnessrepo.NewMemberIllnessRepository(db)
 	rmall := memberallergyrepo.NewMemberAllergyRepository(db)
+	rrlpm := rolepermissionrepo.NewRolePermissionRepository(db)
+	rmcg := membercaregiverrepo.NewMemberCaregiverRepository(db)
+	rcgin := caregiverinvitationrepo.NewCaregiverInvitationRepository(db)
+	rmhh := memberhealthhistoryrepo.NewMemberHealthHistoryRepository(db)
+	renc := encryptionrepo.NewEncryptionRepository(db)
+	rdexp := dataexportrepo.NewDataExportRepository(db)
+	rders := dataerasurerepo.NewDataErasureRepository(db)
+	rsev := securityeventrepo.NewSecurityEventRepository(db)
+	rtwof := twofactorrepo.NewTwoFactorRepository(db)
+	rident := identityrepo.NewIdentityRepository(db)
+	rsvac := serviceaccountrepo.NewServiceAccountRepository(db)
+	rapik := apikeyrepo.NewAPIKeyRepository(db)
+	rwebh := webhookrepo.NewWebhookRepository(db)
+	raudt := auditlogrepo.NewAuditLogRepository(db)
+	ranly := analyticsrepo.NewAnalyticsRepository(db)
+	rsrch := searchrepo.NewSearchRepository(db)
 
 	// ! --------------------------------- service -------------------------------- ! //
 	// * external services
 	sdsmx := distancematrixservice.NewDistanceMatrixService(cfg)
+	soidc := oidcservice.NewOIDCService(cfg)
 
 	// * internal services
-	sbsrl := baseroleservice.NewBaseRoleService(rmemb, rpart)
+	sperm := permissionservice.NewPermissionService(rdb, rrlpm)
+	ssess := sessionservice.NewSessionService(rdb)
+	sbsrl := baseroleservice.NewBaseRoleService(rmemb, rmcg, rpart)
 	sprod := producerservice.NewProducerService(ch, cfg, ctx)
+	swebh := webhookservice.NewWebhookService(cfg, rwebh, sprod)
 	suser := userservice.NewUserService(ruser, radmin, rcare, rmemb, rorg, rpart, rpatron)
-	spart := partnerservice.NewPartnerService(rpart, rordr, rorme, rmeal)
+	spart := partnerservice.NewPartnerService(rpart, rordr, rorme, rmeal, ruser, sperm, swebh)
 	smail := mailservice.NewMailService(cfg, ruser, sprod)
-	sauth := authservice.NewAuthService(cfg, rdb, ruser, smail, suser)
-	smeal := mealservice.NewMealService(rmeal, rill, rall, rpart)
-	smemb := memberservice.NewMemberService(rmemb, ruser, rcare, rall, rill, rorg, rmill, rmall)
+	ssecu := securityservice.NewSecurityService(cfg, rdb, rsev, smail, suser)
+	ssvac := serviceaccountservice.NewServiceAccountService(cfg, rsvac, rapik, ruser, sperm, ssecu)
+	stfa := twofactorservice.NewTwoFactorService(cfg, rdb, rtwof, ruser, ssecu)
+	sauth := authservice.NewAuthService(cfg, ruser, smail, suser, ssess, ssecu, stfa)
+	smeal := mealservice.NewMealService(rmeal, rill, rall, rpart, rmcat, rsrch)
+	smemb := memberservice.NewMemberService(rmemb, ruser, rcare, rall, rill, rorg, rmill, rmall, rmhh)
 	scart := cartservice.NewCartService(rcart, rcare, rmemb, rmeal, sbsrl)
-	scons := consumerservice.NewConsumerService(ch, cfg, smail)
+	scons := consumerservice.NewConsumerService(ch, cfg, smail, swebh, sprod)
 	spatr := patronservice.NewPatronService(rpatron, rdona)
 	sorga := organizationservice.NewOrganizationService(rorg)
-	sordr := orderservice.NewOrderService(cfg, rorder, rmeal, rmemb, ruser, rcare, rcart, rpart, sbsrl)
-	scron := cronservice.NewCronService(cfg, rorder)
+	sident := identityservice.NewIdentityService(cfg, rdb, rident, ruser, soidc, smemb, spatr, spart, ssecu, ssess)
+	sordr := orderservice.NewOrderService(cfg, rorder, rmeal, rmemb, ruser, rcare, rcart, rpart, sbsrl, swebh)
+	sprvc := privacyservice.NewPrivacyService(cfg, *minio, rdexp, rders, ruser, rmhh, rordr, rcart, rdona, rmcg, rimg, rsev, suser, ssess, ssecu, stfa)
+	ssrch := searchservice.NewSearchService(cfg, rsrch, rmeal)
+	scron := cronservice.NewCronService(cfg, rorder, renc, sprvc, swebh, ssrch, rdb)
 	silln := illnessservice.NewIllnessService(rill)
-	sfile := fileservice.NewFileService(cfg, ctx, *minio, ruser, rimg, ruimg, rdona, rdnpr)
+	sfile := fileservice.NewFileService(cfg, *minio, ruser, rimg, ruimg, rdona, rdnpr, rmcat)
 	salle := allergyservice.NewAllergyService(rall)
 	sdona := donationservice.NewDonationService(rdona)
 	smcat := mealcategoryservice.NewMealCategoryService(rmcat)
-	scare := caregiverservice.NewCaregiverService(rcare)
+	scare := caregiverservice.NewCaregiverService(cfg, rcare, rmcg, rcgin, rmemb, ruser, smail)
+	saudt := auditservice.NewAuditService(cfg, raudt)
+	sanly := analyticsservice.NewAnalyticsService(cfg, ranly, rpart)
+	shlth := healthservice.NewHealthService(cfg, db, rdb, ch, minio)
 
 	return &DependencyInjection{
 		// * internal services
-		UserService:         suser,
-		AuthService:         sauth,
-		MailService:         smail,
-		MemberService:       smemb,
-		PartnerService:      spart,
-		MealService:         smeal,
-		CartService:         scart,
-		ConsumerService:     scons,
-		PatronService:       spatr,
-		OrganizationService: sorga,
-		OrderService:        sordr,
-		CronService:         scron,
-		IllnessService:      silln,
-		FileService:         sfile,
-		AllergyService:      salle,
-		DonationService:     sdona,
-		MealCategoryService: smcat,
-		BaseRoleService:     sbsrl,
-		CaregiverService:    scare,
+		UserService:           suser,
+		AuthService:           sauth,
+		MailService:           smail,
+		MemberService:         smemb,
+		PartnerService:        spart,
+		MealService:           smeal,
+		CartService:           scart,
+		ConsumerService:       scons,
+		PatronService:         spatr,
+		OrganizationService:   sorga,
+		OrderService:          sordr,
+		CronService:           scron,
+		IllnessService:        silln,
+		FileService:           sfile,
+		AllergyService:        salle,
+		DonationService:       sdona,
+		MealCategoryService:   smcat,
+		BaseRoleService:       sbsrl,
+		CaregiverService:      scare,
+		PermissionService:     sperm,
+		PrivacyService:        sprvc,
+		SessionService:        ssess,
+		SecurityService:       ssecu,
+		TwoFactorService:      stfa,
+		IdentityService:       sident,
+		ServiceAccountService: ssvac,
+		WebhookService:        swebh,
+		AuditService:          saudt,
+		AnalyticsService:      sanly,
+		SearchService:         ssrch,
+		HealthService:         shlth,
 
 		// * external services
 		DistanceMatrixService: sdsmx,
+		OIDCService:           soidc,
 	}
 }
 
-func (di *DependencyInjection) InitServices() {
-	var (
-		err error
-	)
-
-	// * setup consumer service
-	di.ConsumerService.ConsumeTask()
-
-	// * init cron service
-	_, err = di.CronService.Init()
-	utlogger.Fatal(err)
+// * the worker is the cron and the queue consumers, in the order they
+// * start. they run in the serve command unless the worker command runs
+// * them on its own
+func (di *DependencyInjection) WorkerComponents() []lifecycle.Component {
+	return []lifecycle.Component{
+		di.CronService,
+		di.ConsumerService,
+	}
 }
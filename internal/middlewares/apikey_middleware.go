@@ -0,0 +1,60 @@
+package middlewares
+
+import (
+	"project-skbackend/configs"
+	"project-skbackend/internal/services/serviceaccountservice"
+	"project-skbackend/internal/services/sessionservice"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/utils/utresponse"
+
+	"github.com/gin-gonic/gin"
+)
+
+// * APIKeyAuthMiddleware signs the request in as the user the service
+// * account acts for, PermissionMiddleware then also limits the request
+// * to the scopes of the service account
+func APIKeyAuthMiddleware(ssvac serviceaccountservice.IServiceAccountService) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		key := ctx.GetHeader(consttypes.T_API_KEY)
+		if key == "" {
+			utresponse.GeneralUnauthorized(
+				ctx,
+				consttypes.ErrAPIKeyNotFound,
+			)
+			ctx.Abort()
+			return
+		}
+
+		principal, err := ssvac.Authenticate(ctx, key, ctx.ClientIP())
+		if err != nil {
+			utresponse.GeneralUnauthorized(
+				ctx,
+				err,
+			)
+			ctx.Abort()
+			return
+		}
+
+		ctx.Set("user", principal.User)
+		ctx.Set("api_key_id", principal.APIKeyID)
+		ctx.Set("service_account_id", principal.ServiceAccountID)
+		ctx.Set("api_key_scopes", principal.Scopes)
+		ctx.Next()
+	}
+}
+
+// * JWTOrAPIKeyAuthMiddleware accepts either a signed in user or an api
+// * key, the api key is used whenever its header is present
+func JWTOrAPIKeyAuthMiddleware(cfg *configs.Config, ssess sessionservice.ISessionService, ssvac serviceaccountservice.IServiceAccountService) gin.HandlerFunc {
+	jwtauth := JWTAuthMiddleware(cfg, ssess)
+	apikeyauth := APIKeyAuthMiddleware(ssvac)
+
+	return func(ctx *gin.Context) {
+		if ctx.GetHeader(consttypes.T_API_KEY) != "" {
+			apikeyauth(ctx)
+			return
+		}
+
+		jwtauth(ctx)
+	}
+}
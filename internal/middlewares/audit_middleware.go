@@ -0,0 +1,145 @@
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"project-skbackend/internal/models"
+	"project-skbackend/internal/services/auditservice"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/utils/utresponse"
+	"project-skbackend/packages/utils/uttoken"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// * a created entity is found through the id in the response
+	// * body, larger bodies are not kept to read it
+	auditBodyLimit = 1 << 20
+)
+
+type (
+	auditWriter struct {
+		gin.ResponseWriter
+		body bytes.Buffer
+	}
+)
+
+func (w *auditWriter) Write(b []byte) (int, error) {
+	if w.body.Len()+len(b) <= auditBodyLimit {
+		w.body.Write(b)
+	}
+
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *auditWriter) WriteString(s string) (int, error) {
+	if w.body.Len()+len(s) <= auditBodyLimit {
+		w.body.WriteString(s)
+	}
+
+	return w.ResponseWriter.WriteString(s)
+}
+
+// * the id of the entity in the success response body
+func (w *auditWriter) entityID() string {
+	var (
+		res struct {
+			Data struct {
+				ID string `json:"id"`
+			} `json:"data"`
+		}
+	)
+
+	if err := json.Unmarshal(w.body.Bytes(), &res); err != nil {
+		return ""
+	}
+
+	return res.Data.ID
+}
+
+// * the entity is the last resource of the route, e.g. "api-keys" in
+// * "service-accounts/:said/api-keys/:kid", and its id is the param
+// * after it. a route ending with the resource creates the entity
+func auditTarget(ctx *gin.Context) (consttypes.AuditEntity, string) {
+	var (
+		resource string
+		eid      string
+	)
+
+	for _, segment := range strings.Split(ctx.FullPath(), "/") {
+		if strings.HasPrefix(segment, ":") {
+			eid = ctx.Param(segment[1:])
+			continue
+		}
+
+		if segment != "" {
+			resource = segment
+			eid = ""
+		}
+	}
+
+	return consttypes.AuditEntityFromResource(resource), eid
+}
+
+// * AuditMiddleware must be registered after JWTAuthMiddleware, it records
+// * every state-changing request with the state of the entity before and
+// * after it, including the requests refused by the permission checks
+func AuditMiddleware(saudt auditservice.IAuditService) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		action, ok := consttypes.AuditActionFromMethod(ctx.Request.Method)
+		if !ok {
+			ctx.Next()
+			return
+		}
+
+		user, err := uttoken.GetUser(ctx)
+		if err != nil {
+			ctx.Next()
+			return
+		}
+
+		entity, eid := auditTarget(ctx)
+		before := saudt.Snapshot(ctx, entity, eid)
+
+		writer := &auditWriter{ResponseWriter: ctx.Writer}
+		ctx.Writer = writer
+
+		start := time.Now()
+		ctx.Next()
+		duration := time.Since(start)
+
+		status := utresponse.Status(ctx)
+		if eid == "" && status < 400 {
+			eid = writer.entityID()
+		}
+
+		// * the request is answered, the log is still written when the
+		// * caller has gone away in the meantime
+		rctx := context.WithoutCancel(ctx)
+
+		var after map[string]any
+		if status < 400 {
+			after = saudt.Snapshot(rctx, entity, eid)
+		}
+
+		saudt.Record(rctx, models.AuditLog{
+			ActorID:    user.ID,
+			ActorEmail: user.Email,
+			ActorRole:  user.Role,
+			Action:     action,
+			Entity:     entity,
+			EntityID:   eid,
+			Method:     ctx.Request.Method,
+			Path:       ctx.Request.URL.Path,
+			Route:      ctx.FullPath(),
+			StatusCode: status,
+			IP:         ctx.ClientIP(),
+			UserAgent:  ctx.Request.UserAgent(),
+			Duration:   duration.Milliseconds(),
+		}, before, after)
+	}
+}
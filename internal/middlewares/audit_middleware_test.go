@@ -0,0 +1,100 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"project-skbackend/internal/controllers/responses"
+	"project-skbackend/internal/models"
+	"project-skbackend/internal/models/base"
+	"project-skbackend/internal/services/permissionservice"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/utils/utpagination"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type (
+	fakeAudit struct {
+		logs   []models.AuditLog
+		afters []map[string]any
+	}
+
+	denyPermission struct {
+		permissionservice.IPermissionService
+	}
+)
+
+func (f *fakeAudit) Snapshot(ctx context.Context, ae consttypes.AuditEntity, eid string) map[string]any {
+	return map[string]any{"id": eid}
+}
+
+func (f *fakeAudit) Record(ctx context.Context, al models.AuditLog, before map[string]any, after map[string]any) {
+	f.logs = append(f.logs, al)
+	f.afters = append(f.afters, after)
+}
+
+func (f *fakeAudit) FindAll(ctx context.Context, p utpagination.Pagination) (*utpagination.Pagination, error) {
+	return nil, nil
+}
+
+func (f *fakeAudit) GetByID(ctx context.Context, id uuid.UUID) (*responses.AuditLog, error) {
+	return nil, nil
+}
+
+func (f *fakeAudit) Export(ctx context.Context, fl utpagination.Filter) ([]byte, error) {
+	return nil, nil
+}
+
+func (denyPermission) HasPermission(ctx context.Context, role consttypes.UserRole, perm consttypes.Permission) (bool, error) {
+	return false, nil
+}
+
+func TestAuditMiddlewareRecordsRefusedRequest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	saudt := &fakeAudit{}
+
+	ge := gin.New()
+	ge.Use(ErrorMiddleware())
+
+	uid := uuid.New()
+	g := ge.Group("api/v1/manage", func(ctx *gin.Context) {
+		ctx.Set("user", responses.User{
+			Model: base.Model{ID: uid},
+			Email: "partner@email.com",
+			Role:  consttypes.UR_PARTNER,
+		})
+	}, AuditMiddleware(saudt))
+
+	mid := uuid.NewString()
+	g.PUT("meals/:id", PermissionMiddleware(denyPermission{}, consttypes.P_MEAL_UPDATE), func(ctx *gin.Context) {
+		t.Fatal("handler of a refused request ran")
+	})
+
+	w := httptest.NewRecorder()
+	ge.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/api/v1/manage/meals/"+mid, nil))
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusForbidden)
+	}
+
+	if len(saudt.logs) != 1 {
+		t.Fatalf("recorded %d audit logs, want 1", len(saudt.logs))
+	}
+
+	al := saudt.logs[0]
+	if al.StatusCode != http.StatusForbidden {
+		t.Errorf("audit status = %d, want %d", al.StatusCode, http.StatusForbidden)
+	}
+
+	if al.EntityID != mid || al.ActorID != uid {
+		t.Errorf("audit target = %s by %s, want %s by %s", al.EntityID, al.ActorID, mid, uid)
+	}
+
+	if saudt.afters[0] != nil {
+		t.Errorf("refused request has an after snapshot: %v", saudt.afters[0])
+	}
+}
@@ -1,7 +1,7 @@
 package middlewares
 
 import (
-	"log"
+	"project-skbackend/packages/utils/utlogger"
 
 	"github.com/gin-gonic/gin"
 )
@@ -11,13 +11,13 @@ func CORSMiddleware() gin.HandlerFunc {
 		ctx.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		ctx.Writer.Header().Set("Access-Control-Max-Age", "86400")
 		ctx.Writer.Header().Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE, UPDATE, PATCH")
-		ctx.Writer.Header().Set("Access-Control-Allow-Headers", "Origin, Content-Type, api_key, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-Authorization, Access-Token, Refresh-Token")
-		ctx.Writer.Header().Set("Access-Control-Expose-Headers", "Content-Length, Access-Token, Refresh-Token")
+		ctx.Writer.Header().Set("Access-Control-Allow-Headers", "Origin, Content-Type, api_key, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-Authorization, Access-Token, Refresh-Token, X-Request-ID")
+		ctx.Writer.Header().Set("Access-Control-Expose-Headers", "Content-Length, Access-Token, Refresh-Token, X-Request-ID")
 		ctx.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
 		ctx.Writer.Header().Set("Cache-Control", "no-cache")
 
 		if ctx.Request.Method == "OPTIONS" {
-			log.Println("OPTIONS")
+			utlogger.DebugContext(ctx, "OPTIONS")
 			ctx.AbortWithStatus(200)
 		} else {
 			ctx.Next()
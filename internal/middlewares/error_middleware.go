@@ -0,0 +1,33 @@
+package middlewares
+
+import (
+	"net/http"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/utils/utlogger"
+	"project-skbackend/packages/utils/utresponse"
+
+	"github.com/gin-gonic/gin"
+)
+
+// * answers the last error a handler or a middleware left on the context
+// * as a problem, the cause of an internal error is only logged
+func ErrorMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Next()
+
+		if len(ctx.Errors) == 0 || ctx.Writer.Written() {
+			return
+		}
+
+		gerr := ctx.Errors.Last()
+
+		aerr := consttypes.AsAppError(gerr.Err)
+		if aerr.Status >= http.StatusInternalServerError {
+			utlogger.ErrorContext(ctx, gerr.Err)
+		} else {
+			utlogger.DebugContext(ctx, aerr.Error())
+		}
+
+		utresponse.ProblemResponse(ctx, gerr.Err, gerr.Meta)
+	}
+}
@@ -2,11 +2,11 @@ package middlewares
 
 import (
 	"project-skbackend/configs"
+	"project-skbackend/internal/services/sessionservice"
 	"project-skbackend/packages/consttypes"
 	"project-skbackend/packages/utils/utrequest"
 	"project-skbackend/packages/utils/utresponse"
 	"project-skbackend/packages/utils/uttoken"
-	"slices"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -37,7 +37,7 @@ func extractToken(ctx *gin.Context) (string, error) {
 	return tbearer, nil
 }
 
-func JWTAuthMiddleware(cfg *configs.Config, allowedlevel ...consttypes.UserRole) gin.HandlerFunc {
+func JWTAuthMiddleware(cfg *configs.Config, ssess sessionservice.ISessionService) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		textract, err := extractToken(ctx)
 		if err != nil {
@@ -49,7 +49,7 @@ func JWTAuthMiddleware(cfg *configs.Config, allowedlevel ...consttypes.UserRole)
 			return
 		}
 
-		tparsed, err := uttoken.ParseToken(textract, cfg.JWT.JWTAccessToken.PublicKey)
+		tparsed, err := uttoken.ParseToken(textract, cfg.JWT.JWTAccessToken.PublicKey, cfg.JWT.JWTAccessToken.PreviousPublicKey)
 		if err != nil {
 			utresponse.GeneralUnauthorized(
 				ctx,
@@ -59,7 +59,7 @@ func JWTAuthMiddleware(cfg *configs.Config, allowedlevel ...consttypes.UserRole)
 			return
 		}
 
-		if !slices.Contains(allowedlevel, tparsed.User.Role) || (consttypes.TimeNow().Unix() >= tparsed.Expires.Unix()) {
+		if consttypes.TimeNow().Unix() >= tparsed.Expires.Unix() {
 			utresponse.GeneralUnauthorized(
 				ctx,
 				consttypes.ErrUnauthorized,
@@ -68,6 +68,18 @@ func JWTAuthMiddleware(cfg *configs.Config, allowedlevel ...consttypes.UserRole)
 			return
 		}
 
+		// * the token is rejected once its session is signed out,
+		// * revoked or rotated to a newer token pair
+		_, err = ssess.Validate(ctx, *tparsed)
+		if err != nil {
+			utresponse.GeneralUnauthorized(
+				ctx,
+				err,
+			)
+			ctx.Abort()
+			return
+		}
+
 		if !utrequest.CheckWhitelistUrl(ctx.Request.URL.Path) {
 			if tparsed.User.ConfirmedAt.IsZero() && !strings.Contains(ctx.Request.URL.Path, "verify") {
 				utresponse.GeneralUnauthorized(
@@ -81,6 +93,7 @@ func JWTAuthMiddleware(cfg *configs.Config, allowedlevel ...consttypes.UserRole)
 
 		ctx.Set("user", *tparsed.User)
 		ctx.Set("access_token_uuid", tparsed.TokenUUID.String())
+		ctx.Set("session_id", tparsed.SessionID)
 		ctx.Next()
 	}
 }
@@ -0,0 +1,38 @@
+package middlewares
+
+import (
+	"log/slog"
+	"project-skbackend/packages/utils/utlogger"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// * logs every request once it is answered, the query is left out since
+// * it can carry a token
+func LoggerMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+
+		ctx.Next()
+
+		lvl := slog.LevelInfo
+		switch status := ctx.Writer.Status(); {
+		case status >= 500:
+			lvl = slog.LevelError
+		case status >= 400:
+			lvl = slog.LevelWarn
+		}
+
+		utlogger.Logger().Log(ctx, lvl, "request",
+			slog.String("method", ctx.Request.Method),
+			slog.String("path", ctx.Request.URL.Path),
+			slog.String("route", ctx.FullPath()),
+			slog.Int("status", ctx.Writer.Status()),
+			slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.String("ip", ctx.ClientIP()),
+			slog.String("user_agent", ctx.Request.UserAgent()),
+			slog.Int("size", ctx.Writer.Size()),
+		)
+	}
+}
@@ -0,0 +1,25 @@
+package middlewares
+
+import (
+	"project-skbackend/packages/metrics"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// * the requests are recorded by the route template, not the path, so
+// * the ids in the path do not make a series for every entity
+func MetricsMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+
+		ctx.Next()
+
+		route := ctx.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		metrics.ObserveHTTPRequest(ctx.Request.Method, route, ctx.Writer.Status(), time.Since(start))
+	}
+}
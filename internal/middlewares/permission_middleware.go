@@ -0,0 +1,64 @@
+package middlewares
+
+import (
+	"project-skbackend/internal/services/permissionservice"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/utils/utresponse"
+	"project-skbackend/packages/utils/uttoken"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+)
+
+// * PermissionMiddleware must be registered after JWTAuthMiddleware,
+// * it requires the signed in user role to own every given permission.
+// * requests signed in with an api key also need every permission
+// * among the scopes of the key's service account
+func PermissionMiddleware(sperm permissionservice.IPermissionService, perms ...consttypes.Permission) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		user, err := uttoken.GetUser(ctx)
+		if err != nil {
+			utresponse.GeneralUnauthorized(
+				ctx,
+				err,
+			)
+			ctx.Abort()
+			return
+		}
+
+		scopes, isapikey := uttoken.GetAPIKeyScopes(ctx)
+
+		for _, perm := range perms {
+			if isapikey && !slices.Contains(scopes, perm) {
+				utresponse.GeneralForbidden(
+					ctx,
+					consttypes.ErrPermissionDenied,
+				)
+				ctx.Abort()
+				return
+			}
+
+			ok, err := sperm.HasPermission(ctx, user.Role, perm)
+			if err != nil {
+				utresponse.GeneralInternalServerError(
+					"check permission",
+					ctx,
+					err,
+				)
+				ctx.Abort()
+				return
+			}
+
+			if !ok {
+				utresponse.GeneralForbidden(
+					ctx,
+					consttypes.ErrPermissionDenied,
+				)
+				ctx.Abort()
+				return
+			}
+		}
+
+		ctx.Next()
+	}
+}
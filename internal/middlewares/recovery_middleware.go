@@ -0,0 +1,31 @@
+package middlewares
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/utils/utlogger"
+	"project-skbackend/packages/utils/utresponse"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+)
+
+// * logs a panic of a handler as an error of its request and answers it as a
+// * problem instead of the plain text of gin
+func RecoveryMiddleware() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(io.Discard, func(ctx *gin.Context, err any) {
+		utlogger.ErrorContext(ctx, fmt.Errorf("panic: %v\n%s", err, debug.Stack()))
+		ctx.Abort()
+
+		// * the panic is not told to the caller, only that it failed
+		if !ctx.Writer.Written() {
+			utresponse.ProblemResponse(ctx, consttypes.NewAppError(
+				consttypes.EC_INTERNAL,
+				http.StatusInternalServerError,
+				"Something went wrong",
+			), nil)
+		}
+	})
+}
@@ -0,0 +1,31 @@
+package middlewares
+
+import (
+	"project-skbackend/packages/utils/utlogger"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+var (
+	// * the id of a caller is only kept when it can not break a log line
+	requestidpattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+)
+
+// * keeps the id of the request in its context, so the services, the
+// * queries and the consumers of its messages log with it. the id of the
+// * caller is kept, otherwise a new one is made
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		rid := ctx.GetHeader(utlogger.HeaderRequestID)
+		if !requestidpattern.MatchString(rid) {
+			rid = uuid.NewString()
+		}
+
+		ctx.Request = ctx.Request.WithContext(utlogger.WithRequestID(ctx.Request.Context(), rid))
+		ctx.Header(utlogger.HeaderRequestID, rid)
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,27 @@
+package middlewares
+
+import (
+	"project-skbackend/internal/services/securityservice"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/utils/utresponse"
+
+	"github.com/gin-gonic/gin"
+)
+
+// * ThrottleMiddleware limits how often a client ip can call the action,
+// * the per account limit is checked by the handler once the body is bound
+func ThrottleMiddleware(ssecu securityservice.ISecurityService, action consttypes.ThrottleAction) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		err := ssecu.ThrottleIP(ctx, action, ctx.ClientIP())
+		if err != nil {
+			utresponse.GeneralTooManyRequests(
+				ctx,
+				err,
+			)
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
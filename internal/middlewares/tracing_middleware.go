@@ -0,0 +1,17 @@
+package middlewares
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+)
+
+// * runs every request in a span, continued from the trace context of the
+// * caller. the probes and the scrapes are not traced
+func TracingMiddleware(service string) gin.HandlerFunc {
+	return otelgin.Middleware(service, otelgin.WithFilter(func(req *http.Request) bool {
+		return !strings.HasPrefix(req.URL.Path, "/health") && req.URL.Path != "/metrics"
+	}))
+}
@@ -18,6 +18,6 @@ type (
 		FirstName   string              `json:"first_name" gorm:"required" example:"Jonathan"`
 		LastName    string              `json:"last_name" gorm:"required" example:"Vince"`
 		Gender      consttypes.Gender   `json:"gender" gorm:"required; type:gender_enum" example:"Male"`
-		DateOfBirth ctdatatype.CDT_DATE `json:"date_of_birth" gorm:"required" example:"2000-12-30"`
+		DateOfBirth ctdatatype.CDT_DATE `json:"date_of_birth" gorm:"required;type:text;serializer:encrypted" example:"2000-12-30"`
 	}
 )
@@ -0,0 +1,84 @@
+package models
+
+import (
+	"fmt"
+	"project-skbackend/internal/controllers/responses"
+	"project-skbackend/internal/models/base"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/utils/utlogger"
+
+	"github.com/google/uuid"
+	"github.com/jinzhu/copier"
+)
+
+type (
+	AuditLog struct {
+		base.Model
+
+		ActorID    uuid.UUID           `json:"actor_id" gorm:"required;index" example:"f7fbfa0d-5f95-42e0-839c-d43f0ca757a4"`
+		ActorEmail string              `json:"actor_email" example:"admin@meals.com"`
+		ActorRole  consttypes.UserRole `json:"actor_role" gorm:"required;type:user_role_enum" example:"1"`
+
+		Action   consttypes.AuditAction `json:"action" gorm:"required;type:audit_action_enum" example:"Update"`
+		Entity   consttypes.AuditEntity `json:"entity" gorm:"required;index" example:"meal"`
+		EntityID string                 `json:"entity_id,omitempty" gorm:"index" example:"f7fbfa0d-5f95-42e0-839c-d43f0ca757a4"`
+
+		// * request metadata
+		Method     string `json:"method" example:"PUT"`
+		Path       string `json:"path" example:"/api/v1/manages/meals/f7fbfa0d-5f95-42e0-839c-d43f0ca757a4"`
+		Route      string `json:"route" example:"/api/v1/manages/meals/:mid"`
+		StatusCode int    `json:"status_code" example:"200"`
+		IP         string `json:"ip,omitempty" example:"127.0.0.1"`
+		UserAgent  string `json:"user_agent,omitempty" example:"Mozilla/5.0"`
+		Duration   int64  `json:"duration" example:"120"`
+
+		// * the row of the entity before and after the request, the
+		// * changes only hold the columns whose value is different
+		Before  map[string]any         `json:"before,omitempty" gorm:"type:jsonb;serializer:json"`
+		After   map[string]any         `json:"after,omitempty" gorm:"type:jsonb;serializer:json"`
+		Changes map[string]AuditChange `json:"changes,omitempty" gorm:"type:jsonb;serializer:json"`
+	}
+
+	AuditChange struct {
+		From any `json:"from"`
+		To   any `json:"to"`
+	}
+)
+
+// * the columns that changed between the two rows, a missing row
+// * (before a create or after a hard delete) counts as empty
+func NewAuditChanges(before map[string]any, after map[string]any) map[string]AuditChange {
+	changes := map[string]AuditChange{}
+
+	for column, to := range after {
+		from, ok := before[column]
+		if !ok || !auditEqual(from, to) {
+			changes[column] = AuditChange{From: from, To: to}
+		}
+	}
+
+	for column, from := range before {
+		if _, ok := after[column]; !ok {
+			changes[column] = AuditChange{From: from, To: nil}
+		}
+	}
+
+	return changes
+}
+
+func auditEqual(a any, b any) bool {
+	return fmt.Sprint(a) == fmt.Sprint(b)
+}
+
+func (al *AuditLog) ToResponse() (*responses.AuditLog, error) {
+	var (
+		alres responses.AuditLog
+	)
+
+	if err := copier.CopyWithOption(&alres, &al, copier.Option{IgnoreEmpty: true, DeepCopy: true}); err != nil {
+		utlogger.Error(err)
+		return nil, err
+	}
+
+	return &alres, nil
+}
@@ -6,6 +6,7 @@ import (
 	"project-skbackend/packages/consttypes"
 	"project-skbackend/packages/customs/ctdatatype"
 	"project-skbackend/packages/utils/utlogger"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jinzhu/copier"
@@ -21,7 +22,35 @@ type (
 		Gender      consttypes.Gender   `json:"gender" gorm:"required; type:gender_enum" example:"Male"`
 		FirstName   string              `json:"first_name" gorm:"required" example:"Jonathan"`
 		LastName    string              `json:"last_name" gorm:"required" example:"Vince"`
-		DateOfBirth ctdatatype.CDT_DATE `json:"date_of_birth" gorm:"required" example:"2000-12-30"`
+		DateOfBirth ctdatatype.CDT_DATE `json:"date_of_birth" gorm:"required;type:text;serializer:encrypted" example:"2000-12-30"`
+	}
+
+	MemberCaregiver struct {
+		base.Model
+
+		MemberID uuid.UUID `json:"member_id" gorm:"required; uniqueIndex:idx_member_caregiver" example:"f7fbfa0d-5f95-42e0-839c-d43f0ca757a4"`
+		Member   *Member   `json:"member,omitempty"`
+
+		CaregiverID uuid.UUID `json:"caregiver_id" gorm:"required; uniqueIndex:idx_member_caregiver" example:"f7fbfa0d-5f95-42e0-839c-d43f0ca757a4"`
+		Caregiver   Caregiver `json:"caregiver"`
+
+		CanOrder      bool `json:"can_order" gorm:"not null; default:false" example:"true"`
+		CanEditHealth bool `json:"can_edit_health" gorm:"not null; default:false" example:"false"`
+	}
+
+	CaregiverInvitation struct {
+		base.Model
+
+		MemberID uuid.UUID `json:"member_id" gorm:"required" example:"f7fbfa0d-5f95-42e0-839c-d43f0ca757a4"`
+		Member   Member    `json:"member"`
+
+		Email         string                               `json:"email" gorm:"required" example:"caregiver@test.com"`
+		Token         string                               `json:"-" gorm:"required; uniqueIndex"`
+		CanOrder      bool                                 `json:"can_order" gorm:"not null; default:false" example:"true"`
+		CanEditHealth bool                                 `json:"can_edit_health" gorm:"not null; default:false" example:"false"`
+		Status        consttypes.CaregiverInvitationStatus `json:"status" gorm:"required; type:caregiver_invitation_status_enum" example:"Pending"`
+		ExpiresAt     time.Time                            `json:"expires_at" gorm:"required" example:"2024-01-01T00:00:00Z"`
+		RespondedAt   *time.Time                           `json:"responded_at,omitempty" example:"2024-01-01T00:00:00Z"`
 	}
 )
 
@@ -37,3 +66,68 @@ func (c *Caregiver) ToResponse() (*responses.Caregiver, error) {
 
 	return &cres, nil
 }
+
+func NewMemberCaregiver(
+	caregiver Caregiver,
+	canorder bool,
+	canedithealth bool,
+) *MemberCaregiver {
+	return &MemberCaregiver{
+		CaregiverID:   caregiver.ID,
+		Caregiver:     caregiver,
+		CanOrder:      canorder,
+		CanEditHealth: canedithealth,
+	}
+}
+
+// * check whether the link lets the caregiver do the given access,
+// * viewing is always allowed as long as the link exists
+func (mc *MemberCaregiver) Allows(access consttypes.CaregiverAccess) bool {
+	switch access {
+	case consttypes.CA_VIEW:
+		return true
+	case consttypes.CA_ORDER:
+		return mc.CanOrder
+	case consttypes.CA_EDIT_HEALTH:
+		return mc.CanEditHealth
+	default:
+		return false
+	}
+}
+
+func (mc *MemberCaregiver) ToResponse() (*responses.MemberCaregiver, error) {
+	var (
+		mcres responses.MemberCaregiver
+	)
+
+	if err := copier.CopyWithOption(&mcres, &mc, copier.Option{IgnoreEmpty: true, DeepCopy: true}); err != nil {
+		utlogger.Error(err)
+		return nil, err
+	}
+
+	return &mcres, nil
+}
+
+func (ci *CaregiverInvitation) IsExpired() bool {
+	return consttypes.TimeNow().After(ci.ExpiresAt)
+}
+
+func (ci *CaregiverInvitation) ToMemberCaregiver(caregiver Caregiver) *MemberCaregiver {
+	mc := NewMemberCaregiver(caregiver, ci.CanOrder, ci.CanEditHealth)
+	mc.MemberID = ci.MemberID
+
+	return mc
+}
+
+func (ci *CaregiverInvitation) ToResponse() (*responses.CaregiverInvitation, error) {
+	var (
+		cires responses.CaregiverInvitation
+	)
+
+	if err := copier.CopyWithOption(&cires, &ci, copier.Option{IgnoreEmpty: true, DeepCopy: true}); err != nil {
+		utlogger.Error(err)
+		return nil, err
+	}
+
+	return &cires, nil
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"project-skbackend/internal/controllers/responses"
+	"project-skbackend/internal/models/base"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/utils/utlogger"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jinzhu/copier"
+)
+
+type (
+	DataExport struct {
+		base.Model
+
+		UserID uuid.UUID `json:"user_id" gorm:"required" example:"f7fbfa0d-5f95-42e0-839c-d43f0ca757a4"`
+		User   User      `json:"user"`
+
+		Status      consttypes.DataRequestStatus `json:"status" gorm:"required; type:data_request_status_enum" example:"Pending"`
+		ObjectName  string                       `json:"-"`
+		Size        int64                        `json:"size" example:"1024"`
+		Error       string                       `json:"error,omitempty"`
+		CompletedAt *time.Time                   `json:"completed_at,omitempty" example:"2024-01-01T00:00:00Z"`
+		ExpiresAt   *time.Time                   `json:"expires_at,omitempty" example:"2024-01-01T00:00:00Z"`
+	}
+
+	DataErasure struct {
+		base.Model
+
+		// * the user is kept as an anonymized row once the erasure is completed
+		UserID uuid.UUID `json:"user_id" gorm:"required" example:"f7fbfa0d-5f95-42e0-839c-d43f0ca757a4"`
+
+		RequestedByID uuid.UUID `json:"requested_by_id" gorm:"required" example:"f7fbfa0d-5f95-42e0-839c-d43f0ca757a4"`
+
+		Status      consttypes.DataRequestStatus `json:"status" gorm:"required; type:data_request_status_enum" example:"Pending"`
+		Error       string                       `json:"error,omitempty"`
+		CompletedAt *time.Time                   `json:"completed_at,omitempty" example:"2024-01-01T00:00:00Z"`
+	}
+)
+
+func NewDataExport(user User) *DataExport {
+	return &DataExport{
+		UserID: user.ID,
+		Status: consttypes.DRS_PENDING,
+	}
+}
+
+func (de *DataExport) IsExpired() bool {
+	return de.ExpiresAt != nil && consttypes.TimeNow().After(*de.ExpiresAt)
+}
+
+func (de *DataExport) ToResponse() (*responses.DataExport, error) {
+	var (
+		deres responses.DataExport
+	)
+
+	if err := copier.CopyWithOption(&deres, &de, copier.Option{IgnoreEmpty: true, DeepCopy: true}); err != nil {
+		utlogger.Error(err)
+		return nil, err
+	}
+
+	return &deres, nil
+}
+
+func NewDataErasure(user User, requestedbyid uuid.UUID) *DataErasure {
+	return &DataErasure{
+		UserID:        user.ID,
+		RequestedByID: requestedbyid,
+		Status:        consttypes.DRS_PENDING,
+	}
+}
+
+func (de *DataErasure) ToResponse() (*responses.DataErasure, error) {
+	var (
+		deres responses.DataErasure
+	)
+
+	if err := copier.CopyWithOption(&deres, &de, copier.Option{IgnoreEmpty: true, DeepCopy: true}); err != nil {
+		utlogger.Error(err)
+		return nil, err
+	}
+
+	return &deres, nil
+}
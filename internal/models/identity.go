@@ -0,0 +1,35 @@
+package models
+
+import (
+	"project-skbackend/internal/models/base"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// * an account of an oidc provider linked to a user,
+	// * the issuer and subject pair identifies the account
+	UserIdentity struct {
+		base.Model
+
+		UserID uuid.UUID `json:"user_id" gorm:"required;index" example:"f7fbfa0d-5f95-42e0-839c-d43f0ca757a4"`
+
+		Issuer  string `json:"issuer" gorm:"required;uniqueIndex:idx_user_identity_issuer_subject" example:"https://accounts.google.com"`
+		Subject string `json:"subject" gorm:"required;uniqueIndex:idx_user_identity_issuer_subject" example:"110169484474386276334"`
+		Email   string `json:"email" example:"jonathanvnc@gmail.com"`
+	}
+)
+
+func NewUserIdentity(
+	uid uuid.UUID,
+	issuer string,
+	subject string,
+	email string,
+) *UserIdentity {
+	return &UserIdentity{
+		UserID:  uid,
+		Issuer:  issuer,
+		Subject: subject,
+		Email:   email,
+	}
+}
@@ -26,6 +26,17 @@ func NewProfileImage(
 	}
 }
 
+func NewMealCategoryImage(
+	name string,
+	path string,
+) *Image {
+	return &Image{
+		Name: name,
+		Path: path,
+		Type: consttypes.IT_MEAL_CATEGORY,
+	}
+}
+
 func NewDonationProof(
 	name string,
 	path string,
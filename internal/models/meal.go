@@ -23,6 +23,10 @@ type (
 		PartnerID uuid.UUID `json:"partner_id" gorm:"required" example:"f7fbfa0d-5f95-42e0-839c-d43f0ca757a4"`
 		Partner   Partner   `json:"partner"`
 
+		// * a meal without a category is left out of the category filters
+		MealCategoryID *uuid.UUID    `json:"meal_category_id,omitempty" gorm:"index" example:"f7fbfa0d-5f95-42e0-839c-d43f0ca757a4"`
+		MealCategory   *MealCategory `json:"meal_category,omitempty"`
+
 		Name        string                `json:"name" gorm:"required" example:"Nasi Goyeng"`
 		Status      consttypes.MealStatus `json:"status" gorm:"required; type:meal_status_enum" example:"Active"`
 		Description string                `json:"description" example:"This meal is made using chicken and egg."`
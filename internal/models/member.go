@@ -5,9 +5,11 @@ import (
 	"project-skbackend/internal/models/base"
 	"project-skbackend/packages/consttypes"
 	"project-skbackend/packages/customs/ctdatatype"
+	"slices"
 
 	"github.com/google/uuid"
 	"github.com/jinzhu/copier"
+	"gorm.io/gorm"
 )
 
 type (
@@ -17,8 +19,7 @@ type (
 		UserID uuid.UUID `json:"user_id" gorm:"required" example:"f7fbfa0d-5f95-42e0-839c-d43f0ca757a4"`
 		User   User      `json:"user"`
 
-		CaregiverID *uuid.UUID `json:"caregiver_id,omitempty" example:"f7fbfa0d-5f95-42e0-839c-d43f0ca757a4" default:"null"`
-		Caregiver   *Caregiver `json:"caregiver,omitempty"`
+		Caregivers []*MemberCaregiver `json:"caregivers,omitempty" gorm:"foreignkey:MemberID; references:id"`
 
 		OrganizationID *uuid.UUID    `json:"organization_id,omitempty" example:"f7fbfa0d-5f95-42e0-839c-d43f0ca757a4" default:"null"`
 		Organization   *Organization `json:"organization,omitempty"`
@@ -27,29 +28,34 @@ type (
 
 		Allergies []*MemberAllergy `json:"allergies,omitempty" gorm:"foreignkey:MemberID; references:id"`
 
-		Height      float64             `json:"height" gorm:"required" example:"100"`
-		Weight      float64             `json:"weight" gorm:"required" example:"150"`
-		BMI         float64             `json:"bmi" gorm:"required;type:decimal(10,2)" example:"19"`
+		// * health data and date of birth are encrypted at rest
+		Height      float64             `json:"height" gorm:"required;type:text;serializer:encrypted" example:"100"`
+		Weight      float64             `json:"weight" gorm:"required;type:text;serializer:encrypted" example:"150"`
+		BMI         float64             `json:"bmi" gorm:"required;type:text;serializer:encrypted" example:"19"`
 		FirstName   string              `json:"first_name" gorm:"required" example:"Jonathan"`
 		LastName    string              `json:"last_name" gorm:"required" example:"Vince"`
 		Gender      consttypes.Gender   `json:"gender" gorm:"required; type:gender_enum" example:"Male"`
-		DateOfBirth ctdatatype.CDT_DATE `json:"date_of_birth" gorm:"required" example:"2000-10-20"`
+		DateOfBirth ctdatatype.CDT_DATE `json:"date_of_birth" gorm:"required;type:text;serializer:encrypted" example:"2000-10-20"`
 	}
 
+	// * the illness of a member is health data, so its id is encrypted at
+	// * rest and the illness is read after the link instead of preloaded
 	MemberIllness struct {
 		base.Model
 		MemberID uuid.UUID `json:"member_id" gorm:"required" example:"f7fbfa0d-5f95-42e0-839c-d43f0ca757a4"`
 
-		IllnessID uuid.UUID `json:"illness_id" gorm:"required" example:"f7fbfa0d-5f95-42e0-839c-d43f0ca757a4"`
-		Illness   Illness   `json:"illness"`
+		IllnessID uuid.UUID `json:"illness_id" gorm:"required;type:text;serializer:encrypted" example:"f7fbfa0d-5f95-42e0-839c-d43f0ca757a4"`
+		Illness   Illness   `json:"illness" gorm:"-"`
 	}
 
+	// * the allergy of a member is health data, so its id is encrypted at
+	// * rest and the allergy is read after the link instead of preloaded
 	MemberAllergy struct {
 		base.Model
 		MemberID uuid.UUID `json:"member_id" gorm:"required" example:"f7fbfa0d-5f95-42e0-839c-d43f0ca757a4"`
 
-		AllergyID uuid.UUID `json:"allergy_id" gorm:"required" example:"f7fbfa0d-5f95-42e0-839c-d43f0ca757a4"`
-		Allergy   Allergy   `json:"allergy"`
+		AllergyID uuid.UUID `json:"allergy_id" gorm:"required;type:text;serializer:encrypted" example:"f7fbfa0d-5f95-42e0-839c-d43f0ca757a4"`
+		Allergy   Allergy   `json:"allergy" gorm:"-"`
 	}
 )
 
@@ -62,3 +68,54 @@ func (m *Member) ToResponse() (*responses.Member, error) {
 
 	return &mres, nil
 }
+
+// * the encrypted id can not be joined by the database, a link to a
+// * deleted illness is left with an empty illness like a preload would
+func (mill *MemberIllness) AfterFind(tx *gorm.DB) error {
+	if mill.IllnessID == uuid.Nil {
+		return nil
+	}
+
+	return tx.
+		Session(&gorm.Session{NewDB: true}).
+		Where("id = ?", mill.IllnessID).
+		Limit(1).
+		Find(&mill.Illness).Error
+}
+
+func (mall *MemberAllergy) AfterFind(tx *gorm.DB) error {
+	if mall.AllergyID == uuid.Nil {
+		return nil
+	}
+
+	return tx.
+		Session(&gorm.Session{NewDB: true}).
+		Where("id = ?", mall.AllergyID).
+		Limit(1).
+		Find(&mall.Allergy).Error
+}
+
+// * only keep what is needed to prepare and deliver the meals, the
+// * allergies are limited to the allergies found in the given meals
+func (m *Member) ToRedactedResponse(mealallergyids []uuid.UUID) (*responses.Member, error) {
+	mres, err := m.ToResponse()
+	if err != nil {
+		return nil, err
+	}
+
+	rmres := responses.Member{
+		Model:     mres.Model,
+		User:      mres.User,
+		FirstName: mres.FirstName,
+		LastName:  mres.LastName,
+		Gender:    mres.Gender,
+	}
+
+	for _, mall := range mres.Allergies {
+		if slices.Contains(mealallergyids, mall.Allergy.ID) {
+			rmres.Allergies = append(rmres.Allergies, mall)
+		}
+	}
+
+	return &rmres, nil
+}
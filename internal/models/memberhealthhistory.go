@@ -0,0 +1,160 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"project-skbackend/internal/controllers/responses"
+	"project-skbackend/internal/models/base"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/utils/utlogger"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jinzhu/copier"
+)
+
+type (
+	MemberHealthHistory struct {
+		base.Model
+
+		MemberID uuid.UUID `json:"member_id" gorm:"required; uniqueIndex:idx_member_health_history_version" example:"f7fbfa0d-5f95-42e0-839c-d43f0ca757a4"`
+		Version  int       `json:"version" gorm:"required; uniqueIndex:idx_member_health_history_version" example:"1"`
+
+		// * the user who made the change, empty when the change is made by the system
+		ChangedByID   *uuid.UUID           `json:"changed_by_id,omitempty" example:"f7fbfa0d-5f95-42e0-839c-d43f0ca757a4"`
+		ChangedByRole *consttypes.UserRole `json:"changed_by_role,omitempty" gorm:"type:user_role_enum" example:"3"`
+
+		Previous *MemberHealthSnapshot `json:"previous,omitempty" gorm:"type:text;serializer:encrypted"`
+		Current  MemberHealthSnapshot  `json:"current" gorm:"required;type:text;serializer:encrypted"`
+
+		// * current values are kept in their own columns for the trend queries,
+		// * every health value of the history is encrypted at rest
+		Height float64 `json:"height" gorm:"required;type:text;serializer:encrypted" example:"100"`
+		Weight float64 `json:"weight" gorm:"required;type:text;serializer:encrypted" example:"150"`
+		BMI    float64 `json:"bmi" gorm:"required;type:text;serializer:encrypted" example:"19"`
+	}
+
+	MemberHealthSnapshot struct {
+		Height    float64                    `json:"height"`
+		Weight    float64                    `json:"weight"`
+		BMI       float64                    `json:"bmi"`
+		Illnesses []MemberHealthSnapshotItem `json:"illnesses"`
+		Allergies []MemberHealthSnapshotItem `json:"allergies"`
+	}
+
+	MemberHealthSnapshotItem struct {
+		ID   uuid.UUID `json:"id"`
+		Name string    `json:"name"`
+	}
+)
+
+func NewMemberHealthSnapshot(member Member) *MemberHealthSnapshot {
+	snapshot := MemberHealthSnapshot{
+		Height:    member.Height,
+		Weight:    member.Weight,
+		BMI:       member.BMI,
+		Illnesses: []MemberHealthSnapshotItem{},
+		Allergies: []MemberHealthSnapshotItem{},
+	}
+
+	for _, mill := range member.Illnesses {
+		snapshot.Illnesses = append(snapshot.Illnesses, MemberHealthSnapshotItem{
+			ID:   mill.IllnessID,
+			Name: mill.Illness.Name,
+		})
+	}
+
+	for _, mall := range member.Allergies {
+		snapshot.Allergies = append(snapshot.Allergies, MemberHealthSnapshotItem{
+			ID:   mall.AllergyID,
+			Name: mall.Allergy.Name,
+		})
+	}
+
+	// * sort the items so the snapshots can be compared
+	// * regardless of the order they were saved in
+	sortitems := func(a, b MemberHealthSnapshotItem) int {
+		return strings.Compare(a.ID.String(), b.ID.String())
+	}
+	slices.SortFunc(snapshot.Illnesses, sortitems)
+	slices.SortFunc(snapshot.Allergies, sortitems)
+
+	return &snapshot
+}
+
+func (mhs MemberHealthSnapshot) Equal(other MemberHealthSnapshot) bool {
+	return reflect.DeepEqual(mhs, other)
+}
+
+func (mhs MemberHealthSnapshot) Value() (driver.Value, error) {
+	return json.Marshal(mhs)
+}
+
+func (mhs *MemberHealthSnapshot) Scan(value any) error {
+	var (
+		data []byte
+	)
+
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("unable to convert value to member health snapshot")
+	}
+
+	return json.Unmarshal(data, mhs)
+}
+
+func NewMemberHealthHistory(
+	member Member,
+	version int,
+	previous *MemberHealthSnapshot,
+	changedby *User,
+) *MemberHealthHistory {
+	mhh := MemberHealthHistory{
+		MemberID: member.ID,
+		Version:  version,
+		Previous: previous,
+		Current:  *NewMemberHealthSnapshot(member),
+		Height:   member.Height,
+		Weight:   member.Weight,
+		BMI:      member.BMI,
+	}
+
+	if changedby != nil {
+		mhh.ChangedByID = &changedby.ID
+		mhh.ChangedByRole = &changedby.Role
+	}
+
+	return &mhh
+}
+
+func (mhh *MemberHealthHistory) ToResponse() (*responses.MemberHealthHistory, error) {
+	var (
+		mhhres responses.MemberHealthHistory
+	)
+
+	if err := copier.CopyWithOption(&mhhres, &mhh, copier.Option{IgnoreEmpty: true, DeepCopy: true}); err != nil {
+		utlogger.Error(err)
+		return nil, err
+	}
+
+	return &mhhres, nil
+}
+
+func (mhh *MemberHealthHistory) ToTrendResponse() *responses.MemberHealthTrend {
+	return &responses.MemberHealthTrend{
+		Version:    mhh.Version,
+		RecordedAt: mhh.CreatedAt,
+		Height:     mhh.Height,
+		Weight:     mhh.Weight,
+		BMI:        mhh.BMI,
+	}
+}
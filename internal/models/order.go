@@ -67,6 +67,59 @@ func (o *Order) ToResponse() (*responses.Order, error) {
 	return &ores, nil
 }
 
+// * webhooks only carry the identifiers and the status of the order,
+// * receivers fetch anything else through the api with their own access
+func (o *Order) ToWebhookResponse(previous consttypes.OrderStatus) *responses.WebhookOrder {
+	wores := responses.WebhookOrder{
+		ID:             o.ID,
+		MemberID:       o.MemberID,
+		PartnerID:      o.PartnerID,
+		Status:         o.Status,
+		PreviousStatus: previous,
+		Meals:          []responses.WebhookOrderMeal{},
+		CreatedAt:      o.CreatedAt,
+		UpdatedAt:      o.UpdatedAt,
+	}
+
+	for _, om := range o.Meals {
+		wores.Meals = append(wores.Meals, responses.WebhookOrderMeal{
+			MealID:   om.MealID,
+			Quantity: om.Quantity,
+		})
+	}
+
+	return &wores
+}
+
+// * partners handling the order only see the member's
+// * allergies that are relevant to the ordered meals
+func (o *Order) ToPartnerResponse() (*responses.Order, error) {
+	var (
+		mealallergyids []uuid.UUID
+	)
+
+	ores, err := o.ToResponse()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, omeal := range o.Meals {
+		for _, mall := range omeal.Meal.Allergies {
+			mealallergyids = append(mealallergyids, mall.AllergyID)
+		}
+	}
+
+	mres, err := o.Member.ToRedactedResponse(mealallergyids)
+	if err != nil {
+		utlogger.Error(err)
+		return nil, err
+	}
+
+	ores.Member = *mres
+
+	return ores, nil
+}
+
 func NewCreateOrderMeals(
 	meal Meal,
 	quantity int,
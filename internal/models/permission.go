@@ -0,0 +1,51 @@
+package models
+
+import (
+	"project-skbackend/internal/controllers/responses"
+	"project-skbackend/internal/models/base"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/utils/utlogger"
+	"time"
+
+	"github.com/jinzhu/copier"
+)
+
+type (
+	RolePermission struct {
+		base.Model
+
+		Role       consttypes.UserRole   `json:"role" gorm:"required;type:user_role_enum;uniqueIndex:idx_role_permission" example:"1"`
+		Permission consttypes.Permission `json:"permission" gorm:"required;uniqueIndex:idx_role_permission" example:"order:update_status"`
+	}
+
+	// * a default grant that was seeded, it is kept after an admin
+	// * removes the grant so the grant is not seeded again
+	RolePermissionSeed struct {
+		Role       consttypes.UserRole   `gorm:"primaryKey;type:user_role_enum"`
+		Permission consttypes.Permission `gorm:"primaryKey"`
+		SeededAt   time.Time             `gorm:"not null;default:now()"`
+	}
+)
+
+func NewRolePermission(
+	role consttypes.UserRole,
+	permission consttypes.Permission,
+) *RolePermission {
+	return &RolePermission{
+		Role:       role,
+		Permission: permission,
+	}
+}
+
+func (rp *RolePermission) ToResponse() (*responses.RolePermission, error) {
+	var (
+		rpres responses.RolePermission
+	)
+
+	if err := copier.CopyWithOption(&rpres, &rp, copier.Option{IgnoreEmpty: true, DeepCopy: true}); err != nil {
+		utlogger.Error(err)
+		return nil, err
+	}
+
+	return &rpres, nil
+}
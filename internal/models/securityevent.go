@@ -0,0 +1,57 @@
+package models
+
+import (
+	"project-skbackend/internal/controllers/responses"
+	"project-skbackend/internal/models/base"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/utils/utlogger"
+
+	"github.com/google/uuid"
+	"github.com/jinzhu/copier"
+)
+
+type (
+	SecurityEvent struct {
+		base.Model
+
+		// * empty when the event is about an email that is not registered
+		UserID *uuid.UUID `json:"user_id,omitempty" gorm:"index" example:"f7fbfa0d-5f95-42e0-839c-d43f0ca757a4"`
+		Email  string     `json:"email,omitempty" example:"jonathanvnc@gmail.com"`
+
+		Type      consttypes.SecurityEventType `json:"type" gorm:"required; type:security_event_type_enum" example:"Signin Failed"`
+		IP        string                       `json:"ip,omitempty" example:"127.0.0.1"`
+		UserAgent string                       `json:"user_agent,omitempty" example:"Mozilla/5.0"`
+		Detail    string                       `json:"detail,omitempty"`
+	}
+)
+
+func NewSecurityEvent(
+	set consttypes.SecurityEventType,
+	uid *uuid.UUID,
+	email string,
+	ip string,
+	useragent string,
+	detail string,
+) *SecurityEvent {
+	return &SecurityEvent{
+		UserID:    uid,
+		Email:     email,
+		Type:      set,
+		IP:        ip,
+		UserAgent: useragent,
+		Detail:    detail,
+	}
+}
+
+func (se *SecurityEvent) ToResponse() (*responses.SecurityEvent, error) {
+	var (
+		seres responses.SecurityEvent
+	)
+
+	if err := copier.CopyWithOption(&seres, &se, copier.Option{IgnoreEmpty: true, DeepCopy: true}); err != nil {
+		utlogger.Error(err)
+		return nil, err
+	}
+
+	return &seres, nil
+}
@@ -0,0 +1,131 @@
+package models
+
+import (
+	"project-skbackend/internal/controllers/responses"
+	"project-skbackend/internal/models/base"
+	"project-skbackend/packages/consttypes"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// * a service account lets a machine integration act for the user it
+	// * belongs to, but only within the scopes granted to the account
+	ServiceAccount struct {
+		base.Model
+
+		UserID uuid.UUID `json:"user_id" gorm:"required;index" example:"f7fbfa0d-5f95-42e0-839c-d43f0ca757a4"`
+		User   User      `json:"user"`
+
+		Name        string `json:"name" gorm:"required" example:"Kitchen POS"`
+		Description string `json:"description,omitempty" example:"Point of sale of the main kitchen"`
+
+		Scopes []ServiceAccountScope `json:"scopes"`
+
+		CreatedByID uuid.UUID  `json:"created_by_id" example:"f7fbfa0d-5f95-42e0-839c-d43f0ca757a4"`
+		DisabledAt  *time.Time `json:"disabled_at,omitempty"`
+	}
+
+	ServiceAccountScope struct {
+		base.Model
+
+		ServiceAccountID uuid.UUID             `json:"service_account_id" gorm:"required;uniqueIndex:idx_service_account_scope" example:"f7fbfa0d-5f95-42e0-839c-d43f0ca757a4"`
+		Scope            consttypes.Permission `json:"scope" gorm:"required;uniqueIndex:idx_service_account_scope" example:"order:read"`
+	}
+
+	APIKey struct {
+		base.Model
+
+		ServiceAccountID uuid.UUID      `json:"service_account_id" gorm:"required;index" example:"f7fbfa0d-5f95-42e0-839c-d43f0ca757a4"`
+		ServiceAccount   ServiceAccount `json:"service_account"`
+
+		Name string `json:"name" gorm:"required" example:"Production"`
+
+		// * the prefix is kept in plain text so admins can tell the keys
+		// * apart, only the sha256 hash of the whole key is stored
+		Prefix  string `json:"prefix" gorm:"required" example:"skb_4F9KQ2ZD"`
+		KeyHash string `json:"-" gorm:"required;uniqueIndex"`
+
+		ExpiresAt  time.Time  `json:"expires_at"`
+		LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+		LastUsedIP string     `json:"last_used_ip,omitempty" example:"127.0.0.1"`
+		RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	}
+)
+
+func NewServiceAccount(
+	uid uuid.UUID,
+	name string,
+	description string,
+	scopes []consttypes.Permission,
+	createdby uuid.UUID,
+) *ServiceAccount {
+	sa := ServiceAccount{
+		UserID:      uid,
+		Name:        name,
+		Description: description,
+		CreatedByID: createdby,
+	}
+
+	sa.SetScopes(scopes)
+
+	return &sa
+}
+
+func (sa *ServiceAccount) SetScopes(scopes []consttypes.Permission) {
+	sa.Scopes = nil
+	for _, scope := range scopes {
+		if slices.Contains(sa.ScopeList(), scope) {
+			continue
+		}
+
+		sa.Scopes = append(sa.Scopes, ServiceAccountScope{
+			ServiceAccountID: sa.ID,
+			Scope:            scope,
+		})
+	}
+}
+
+func (sa *ServiceAccount) ScopeList() []consttypes.Permission {
+	scopes := make([]consttypes.Permission, 0, len(sa.Scopes))
+	for _, sas := range sa.Scopes {
+		scopes = append(scopes, sas.Scope)
+	}
+
+	return scopes
+}
+
+func (sa *ServiceAccount) ToResponse() *responses.ServiceAccount {
+	return &responses.ServiceAccount{
+		Model:       sa.Model,
+		UserID:      sa.UserID,
+		Name:        sa.Name,
+		Description: sa.Description,
+		Scopes:      sa.ScopeList(),
+		CreatedByID: sa.CreatedByID,
+		DisabledAt:  sa.DisabledAt,
+	}
+}
+
+func (ak *APIKey) IsExpired() bool {
+	return !consttypes.TimeNow().Before(ak.ExpiresAt)
+}
+
+func (ak *APIKey) IsRevoked() bool {
+	return ak.RevokedAt != nil
+}
+
+func (ak *APIKey) ToResponse() *responses.APIKey {
+	return &responses.APIKey{
+		Model:            ak.Model,
+		ServiceAccountID: ak.ServiceAccountID,
+		Name:             ak.Name,
+		Prefix:           ak.Prefix,
+		ExpiresAt:        ak.ExpiresAt,
+		LastUsedAt:       ak.LastUsedAt,
+		LastUsedIP:       ak.LastUsedIP,
+		RevokedAt:        ak.RevokedAt,
+	}
+}
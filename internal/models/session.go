@@ -0,0 +1,40 @@
+package models
+
+import (
+	"project-skbackend/internal/controllers/responses"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// * a session is the family of every token pair issued from one
+	// * sign in, it is stored in redis instead of the database
+	Session struct {
+		ID     uuid.UUID `json:"id"`
+		UserID uuid.UUID `json:"user_id"`
+
+		Device string `json:"device"`
+		IP     string `json:"ip"`
+
+		// * only the latest token pair of the family is accepted
+		AccessTokenUUID  uuid.UUID `json:"access_token_uuid"`
+		RefreshTokenUUID uuid.UUID `json:"refresh_token_uuid"`
+
+		CreatedAt  time.Time `json:"created_at"`
+		LastSeenAt time.Time `json:"last_seen_at"`
+		ExpiresAt  time.Time `json:"expires_at"`
+	}
+)
+
+func (ses *Session) ToResponse(currentsid uuid.UUID) *responses.Session {
+	return &responses.Session{
+		ID:         ses.ID,
+		Device:     ses.Device,
+		IP:         ses.IP,
+		Current:    ses.ID == currentsid,
+		CreatedAt:  ses.CreatedAt,
+		LastSeenAt: ses.LastSeenAt,
+		ExpiresAt:  ses.ExpiresAt,
+	}
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"project-skbackend/internal/controllers/responses"
+	"project-skbackend/internal/models/base"
+	"project-skbackend/packages/consttypes"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	UserTwoFactor struct {
+		base.Model
+
+		UserID uuid.UUID `json:"user_id" gorm:"required;unique" example:"f7fbfa0d-5f95-42e0-839c-d43f0ca757a4"`
+
+		// * the totp secret is encrypted at rest
+		Secret string `json:"-" gorm:"required;type:text;serializer:encrypted"`
+
+		// * the last accepted time step, a code can not be used twice
+		LastUsedStep int64 `json:"-"`
+
+		// * empty while the enrolment is not confirmed yet
+		EnabledAt *time.Time `json:"enabled_at,omitempty"`
+	}
+
+	UserRecoveryCode struct {
+		base.Model
+
+		UserID uuid.UUID `json:"user_id" gorm:"required;index" example:"f7fbfa0d-5f95-42e0-839c-d43f0ca757a4"`
+
+		// * only the sha256 hash of the code is stored
+		CodeHash string     `json:"-" gorm:"required;index"`
+		UsedAt   *time.Time `json:"used_at,omitempty"`
+	}
+)
+
+func (tf *UserTwoFactor) IsEnabled() bool {
+	return tf != nil && tf.EnabledAt != nil
+}
+
+func (tf *UserTwoFactor) ToStatusResponse(policy consttypes.TwoFactorPolicy, remaining int64) *responses.TwoFactorStatus {
+	tfres := responses.TwoFactorStatus{
+		Policy:                 policy,
+		Enabled:                tf.IsEnabled(),
+		RemainingRecoveryCodes: remaining,
+	}
+
+	if tf.IsEnabled() {
+		tfres.EnabledAt = tf.EnabledAt
+	}
+
+	return &tfres
+}
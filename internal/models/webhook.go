@@ -0,0 +1,100 @@
+package models
+
+import (
+	"project-skbackend/internal/controllers/responses"
+	"project-skbackend/internal/models/base"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/utils/utlogger"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jinzhu/copier"
+)
+
+type (
+	Webhook struct {
+		base.Model
+
+		UserID uuid.UUID `json:"user_id" gorm:"required;index" example:"f7fbfa0d-5f95-42e0-839c-d43f0ca757a4"`
+
+		URL         string                        `json:"url" gorm:"required" example:"https://kitchen.example.com/webhooks"`
+		Description string                        `json:"description,omitempty" example:"Kitchen display system"`
+		EventTypes  []consttypes.WebhookEventType `json:"event_types" gorm:"required;type:jsonb;serializer:json" example:"order.created"`
+
+		// * the secret signs every delivery, so it has to be readable
+		// * and is encrypted at rest instead of hashed
+		Secret string `json:"-" gorm:"required;type:text;serializer:encrypted"`
+
+		Active bool `json:"active" gorm:"not null;default:true" example:"true"`
+	}
+
+	WebhookDelivery struct {
+		base.Model
+
+		WebhookID uuid.UUID `json:"webhook_id" gorm:"required;index" example:"f7fbfa0d-5f95-42e0-839c-d43f0ca757a4"`
+		Webhook   Webhook   `json:"webhook"`
+
+		// * the id of the event, a redelivery keeps the id of the
+		// * event it redelivers so receivers can drop duplicates
+		EventID   uuid.UUID                   `json:"event_id" gorm:"required;index" example:"f7fbfa0d-5f95-42e0-839c-d43f0ca757a4"`
+		EventType consttypes.WebhookEventType `json:"event_type" gorm:"required" example:"order.created"`
+		Payload   string                      `json:"payload" gorm:"required;type:text"`
+
+		Status        consttypes.WebhookDeliveryStatus `json:"status" gorm:"required;type:webhook_delivery_status_enum" example:"Pending"`
+		Attempts      int                              `json:"attempts" example:"1"`
+		NextAttemptAt *time.Time                       `json:"next_attempt_at,omitempty"`
+
+		// * outcome of the last attempt
+		ResponseStatus int        `json:"response_status,omitempty" example:"200"`
+		ResponseBody   string     `json:"response_body,omitempty"`
+		Error          string     `json:"error,omitempty"`
+		Duration       int64      `json:"duration,omitempty" example:"120"`
+		DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	}
+)
+
+func NewWebhookDelivery(
+	wid uuid.UUID,
+	eid uuid.UUID,
+	wet consttypes.WebhookEventType,
+	payload string,
+) *WebhookDelivery {
+	return &WebhookDelivery{
+		WebhookID: wid,
+		EventID:   eid,
+		EventType: wet,
+		Payload:   payload,
+		Status:    consttypes.WDS_PENDING,
+	}
+}
+
+func (wh *Webhook) IsSubscribed(wet consttypes.WebhookEventType) bool {
+	return wh.Active && slices.Contains(wh.EventTypes, wet)
+}
+
+func (wh *Webhook) ToResponse() (*responses.Webhook, error) {
+	var (
+		whres responses.Webhook
+	)
+
+	if err := copier.CopyWithOption(&whres, &wh, copier.Option{IgnoreEmpty: true, DeepCopy: true}); err != nil {
+		utlogger.Error(err)
+		return nil, err
+	}
+
+	return &whres, nil
+}
+
+func (wd *WebhookDelivery) ToResponse() (*responses.WebhookDelivery, error) {
+	var (
+		wdres responses.WebhookDelivery
+	)
+
+	if err := copier.CopyWithOption(&wdres, &wd, copier.Option{IgnoreEmpty: true, DeepCopy: true}); err != nil {
+		utlogger.Error(err)
+		return nil, err
+	}
+
+	return &wdres, nil
+}
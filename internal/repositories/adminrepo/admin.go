@@ -1,6 +1,7 @@
 package adminrepo
 
 import (
+	"context"
 	"fmt"
 	"project-skbackend/internal/controllers/responses"
 	"project-skbackend/internal/models"
@@ -27,6 +28,15 @@ var (
 		created_at,
 		updated_at
 	`
+
+	FILTER_FIELDS = paginationrepo.Fields{
+		"user_id":    {Column: "user_id", Type: consttypes.FFT_UUID},
+		"first_name": {Column: "first_name", Type: consttypes.FFT_STRING},
+		"last_name":  {Column: "last_name", Type: consttypes.FFT_STRING},
+		"gender":     paginationrepo.EnumField("gender", consttypes.G_MALE, consttypes.G_FEMALE, consttypes.G_OTHER),
+		"created_at": {Column: "created_at", Type: consttypes.FFT_TIME},
+		"updated_at": {Column: "updated_at", Type: consttypes.FFT_TIME},
+	}
 )
 
 type (
@@ -35,14 +45,14 @@ type (
 	}
 
 	IAdminRepository interface {
-		Create(a models.Admin) (*models.Admin, error)
-		Read() ([]*models.Admin, error)
-		Update(a models.Admin) (*models.Admin, error)
-		Delete(a models.Admin) error
-		FindAll(p utpagination.Pagination) (*utpagination.Pagination, error)
-		GetByID(id uuid.UUID) (*models.Admin, error)
-		GetByEmail(email string) (*models.Admin, error)
-		GetByUserID(uid uuid.UUID) (*models.Admin, error)
+		Create(ctx context.Context, a models.Admin) (*models.Admin, error)
+		Read(ctx context.Context) ([]*models.Admin, error)
+		Update(ctx context.Context, a models.Admin) (*models.Admin, error)
+		Delete(ctx context.Context, a models.Admin) error
+		FindAll(ctx context.Context, p utpagination.Pagination) (*utpagination.Pagination, error)
+		GetByID(ctx context.Context, id uuid.UUID) (*models.Admin, error)
+		GetByEmail(ctx context.Context, email string) (*models.Admin, error)
+		GetByUserID(ctx context.Context, uid uuid.UUID) (*models.Admin, error)
 	}
 )
 
@@ -50,99 +60,99 @@ func NewAdminRepository(db *gorm.DB) *AdminRepository {
 	return &AdminRepository{db: db}
 }
 
-func (r *AdminRepository) omit() *gorm.DB {
-	return r.db.Omit(
+func (r *AdminRepository) omit(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Omit(
 		"",
 	)
 }
 
-func (r *AdminRepository) preload() *gorm.DB {
-	return r.db.
+func (r *AdminRepository) preload(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).
 		Preload(clause.Associations).
 		Preload("User.Addresses.AddressDetail").
 		Preload("User.Image.Image")
 }
 
-func (r *AdminRepository) Create(a models.Admin) (*models.Admin, error) {
+func (r *AdminRepository) Create(ctx context.Context, a models.Admin) (*models.Admin, error) {
 	err := r.
-		omit().
+		omit(ctx).
 		Session(&gorm.Session{FullSaveAssociations: true}).
 		Create(&a).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
-	anew, err := r.GetByID(a.ID)
+	anew, err := r.GetByID(ctx, a.ID)
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return anew, err
 }
 
-func (r *AdminRepository) Read() ([]*models.Admin, error) {
+func (r *AdminRepository) Read(ctx context.Context) ([]*models.Admin, error) {
 	var (
 		a []*models.Admin
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Find(&a).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return a, nil
 }
 
-func (r *AdminRepository) Update(a models.Admin) (*models.Admin, error) {
+func (r *AdminRepository) Update(ctx context.Context, a models.Admin) (*models.Admin, error) {
 	err := r.
-		omit().
+		omit(ctx).
 		Session(&gorm.Session{FullSaveAssociations: true}).
 		Save(&a).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
-	anew, err := r.GetByID(a.ID)
+	anew, err := r.GetByID(ctx, a.ID)
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return anew, nil
 }
 
-func (r *AdminRepository) Delete(a models.Admin) error {
-	err := r.db.
+func (r *AdminRepository) Delete(ctx context.Context, a models.Admin) error {
+	err := r.db.WithContext(ctx).
 		Delete(&a).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return err
 	}
 
 	return nil
 }
 
-func (r *AdminRepository) FindAll(p utpagination.Pagination) (*utpagination.Pagination, error) {
+func (r *AdminRepository) FindAll(ctx context.Context, p utpagination.Pagination) (*utpagination.Pagination, error) {
 	var (
 		a    []models.Admin
 		ares []responses.Admin
 	)
 
 	result := r.
-		preload().
+		preload(ctx).
 		Model(&a).
 		Select(SELECTED_FIELDS)
 
@@ -150,7 +160,7 @@ func (r *AdminRepository) FindAll(p utpagination.Pagination) (*utpagination.Pagi
 		p.Search = fmt.Sprintf("%%%s%%", p.Search)
 		result = result.
 			Where(
-				r.db.Where(`
+				r.db.WithContext(ctx).Where(`
 					first_name ILIKE ?
 						OR 
 					last_name ILIKE ? 
@@ -168,11 +178,11 @@ func (r *AdminRepository) FindAll(p utpagination.Pagination) (*utpagination.Pagi
 
 	result = result.
 		Group("id").
-		Scopes(paginationrepo.Paginate(&a, &p, result)).
+		Scopes(paginationrepo.Paginate(&a, &p, result, FILTER_FIELDS)).
 		Find(&a)
 
 	if err := result.Error; err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
@@ -183,32 +193,32 @@ func (r *AdminRepository) FindAll(p utpagination.Pagination) (*utpagination.Pagi
 	return &p, nil
 }
 
-func (r *AdminRepository) GetByID(id uuid.UUID) (*models.Admin, error) {
+func (r *AdminRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Admin, error) {
 	var (
 		a *models.Admin
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Where(&models.Admin{Model: base.Model{ID: id}}).
 		First(&a).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return a, nil
 }
 
-func (r *AdminRepository) GetByEmail(email string) (*models.Admin, error) {
+func (r *AdminRepository) GetByEmail(ctx context.Context, email string) (*models.Admin, error) {
 	var (
 		a *models.Admin
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Where(`
 			admins.user_id IN (
@@ -226,26 +236,26 @@ func (r *AdminRepository) GetByEmail(email string) (*models.Admin, error) {
 		First(&a).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return a, nil
 }
 
-func (r *AdminRepository) GetByUserID(uid uuid.UUID) (*models.Admin, error) {
+func (r *AdminRepository) GetByUserID(ctx context.Context, uid uuid.UUID) (*models.Admin, error) {
 	var (
 		a *models.Admin
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Where(&models.Admin{UserID: uid}, uid).
 		First(&a).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
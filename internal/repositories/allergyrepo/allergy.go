@@ -1,6 +1,7 @@
 package allergyrepo
 
 import (
+	"context"
 	"fmt"
 	"project-skbackend/internal/controllers/responses"
 	"project-skbackend/internal/models"
@@ -25,6 +26,13 @@ var (
 		created_at,
 		updated_at
 	`
+
+	FILTER_FIELDS = paginationrepo.Fields{
+		"name":        {Column: "name", Type: consttypes.FFT_STRING},
+		"description": {Column: "description", Type: consttypes.FFT_STRING},
+		"created_at":  {Column: "created_at", Type: consttypes.FFT_TIME},
+		"updated_at":  {Column: "updated_at", Type: consttypes.FFT_TIME},
+	}
 )
 
 type (
@@ -33,12 +41,12 @@ type (
 	}
 
 	IAllergyRepository interface {
-		Create(al models.Allergy) (*models.Allergy, error)
-		Read() ([]*models.Allergy, error)
-		Update(al models.Allergy) (*models.Allergy, error)
-		Delete(al models.Allergy) error
-		FindAll(p utpagination.Pagination) (*utpagination.Pagination, error)
-		GetByID(id uuid.UUID) (*models.Allergy, error)
+		Create(ctx context.Context, al models.Allergy) (*models.Allergy, error)
+		Read(ctx context.Context) ([]*models.Allergy, error)
+		Update(ctx context.Context, al models.Allergy) (*models.Allergy, error)
+		Delete(ctx context.Context, al models.Allergy) error
+		FindAll(ctx context.Context, p utpagination.Pagination) (*utpagination.Pagination, error)
+		GetByID(ctx context.Context, id uuid.UUID) (*models.Allergy, error)
 	}
 )
 
@@ -46,94 +54,94 @@ func NewAllergyRepository(db *gorm.DB) *AllergyRepository {
 	return &AllergyRepository{db: db}
 }
 
-func (r *AllergyRepository) omit() *gorm.DB {
-	return r.db.Omit(
+func (r *AllergyRepository) omit(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Omit(
 		"",
 	)
 }
 
-func (r *AllergyRepository) preload() *gorm.DB {
-	return r.db.
+func (r *AllergyRepository) preload(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).
 		Preload(clause.Associations)
 }
 
-func (r *AllergyRepository) Create(al models.Allergy) (*models.Allergy, error) {
+func (r *AllergyRepository) Create(ctx context.Context, al models.Allergy) (*models.Allergy, error) {
 	err := r.
-		omit().
+		omit(ctx).
 		Session(&gorm.Session{FullSaveAssociations: true}).
 		Create(&al).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
-	alnew, err := r.GetByID(al.ID)
+	alnew, err := r.GetByID(ctx, al.ID)
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return alnew, nil
 }
 
-func (r *AllergyRepository) Read() ([]*models.Allergy, error) {
+func (r *AllergyRepository) Read(ctx context.Context) ([]*models.Allergy, error) {
 	var (
 		al []*models.Allergy
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Find(&al).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return al, nil
 }
 
-func (r *AllergyRepository) Update(al models.Allergy) (*models.Allergy, error) {
-	err := r.db.
+func (r *AllergyRepository) Update(ctx context.Context, al models.Allergy) (*models.Allergy, error) {
+	err := r.db.WithContext(ctx).
 		Save(&al).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
-	alnew, err := r.GetByID(al.ID)
+	alnew, err := r.GetByID(ctx, al.ID)
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return alnew, nil
 }
 
-func (r *AllergyRepository) Delete(al models.Allergy) error {
-	err := r.db.
+func (r *AllergyRepository) Delete(ctx context.Context, al models.Allergy) error {
+	err := r.db.WithContext(ctx).
 		Delete(&al).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return err
 	}
 
 	return nil
 }
 
-func (r *AllergyRepository) FindAll(p utpagination.Pagination) (*utpagination.Pagination, error) {
+func (r *AllergyRepository) FindAll(ctx context.Context, p utpagination.Pagination) (*utpagination.Pagination, error) {
 	var (
 		al    []models.Allergy
 		alres []responses.Allergy
 	)
 
 	result := r.
-		preload().
+		preload(ctx).
 		Model(&al).
 		Select(SELECTED_FIELDS)
 
@@ -141,7 +149,7 @@ func (r *AllergyRepository) FindAll(p utpagination.Pagination) (*utpagination.Pa
 		p.Search = fmt.Sprintf("%%%s%%", p.Search)
 		result = result.
 			Where(
-				r.db.Where(`
+				r.db.WithContext(ctx).Where(`
 					name ILIKE ?
 						OR 
 					description ILIKE ? 
@@ -159,11 +167,11 @@ func (r *AllergyRepository) FindAll(p utpagination.Pagination) (*utpagination.Pa
 
 	result = result.
 		Group("id").
-		Scopes(paginationrepo.Paginate(&al, &p, result)).
+		Scopes(paginationrepo.Paginate(&al, &p, result, FILTER_FIELDS)).
 		Find(&al)
 
 	if err := result.Error; err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
@@ -174,19 +182,19 @@ func (r *AllergyRepository) FindAll(p utpagination.Pagination) (*utpagination.Pa
 	return &p, nil
 }
 
-func (r *AllergyRepository) GetByID(id uuid.UUID) (*models.Allergy, error) {
+func (r *AllergyRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Allergy, error) {
 	var (
 		al *models.Allergy
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Where(&models.Allergy{Model: base.Model{ID: id}}).
 		First(&al).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
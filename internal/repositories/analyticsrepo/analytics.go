@@ -0,0 +1,490 @@
+package analyticsrepo
+
+import (
+	"context"
+	"fmt"
+	"project-skbackend/internal/controllers/responses"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/utils/utlogger"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	AnalyticsRepository struct {
+		db *gorm.DB
+	}
+
+	// * from is inclusive and to is exclusive, the buckets
+	// * are cut in the given timezone
+	Range struct {
+		From     time.Time
+		To       time.Time
+		Bucket   consttypes.AnalyticsBucket
+		Timezone string
+		Limit    int
+
+		// * limits the queries to the orders of one partner, the
+		// * partner queries need it while the others take it as optional
+		PartnerID *uuid.UUID
+	}
+
+	IAnalyticsRepository interface {
+		// * admin
+		OrdersByStatus(ctx context.Context, rg Range) ([]responses.AnalyticsOrderStatus, error)
+		Cancellations(ctx context.Context, rg Range) ([]responses.AnalyticsCancellationBucket, error)
+		CancellationCauses(ctx context.Context, rg Range) ([]responses.AnalyticsCancellationCause, error)
+		TopMeals(ctx context.Context, rg Range) ([]responses.AnalyticsTopMeal, error)
+		TopPartners(ctx context.Context, rg Range) ([]responses.AnalyticsTopPartner, error)
+		ActiveMembers(ctx context.Context, rg Range) ([]responses.AnalyticsActiveMembers, error)
+		Donations(ctx context.Context, rg Range) ([]responses.AnalyticsDonations, error)
+		PrepTime(ctx context.Context, rg Range) ([]responses.AnalyticsPrepTime, error)
+
+		// * partner
+		KitchenTimes(ctx context.Context, rg Range) ([]responses.AnalyticsKitchen, error)
+		OrdersByHour(ctx context.Context, rg Range) ([]responses.AnalyticsHourlyOrders, error)
+		MealRatings(ctx context.Context, rg Range) ([]responses.AnalyticsMealRating, error)
+		PrepList(ctx context.Context, rg Range) ([]responses.AnalyticsPrepListMeal, error)
+	}
+)
+
+func NewAnalyticsRepository(db *gorm.DB) *AnalyticsRepository {
+	return &AnalyticsRepository{db: db}
+}
+
+func (rg Range) args() map[string]any {
+	return map[string]any{
+		"from":      rg.From,
+		"to":        rg.To,
+		"bucket":    rg.Bucket.String(),
+		"tz":        rg.Timezone,
+		"limit":     rg.Limit,
+		"partner":   rg.PartnerID,
+		"admin":     int(consttypes.UR_ADMIN),
+		"cancelled": consttypes.OS_CANCELLED,
+		"confirmed": consttypes.OS_CONFIRMED,
+		"preparing": consttypes.OS_BEING_PREPARED,
+		"prepared":  consttypes.OS_PREPARED,
+	}
+}
+
+// * the local date the bucket of the timestamp column starts on
+func bucket(column string) string {
+	return fmt.Sprintf("to_char(date_trunc(@bucket, %s AT TIME ZONE @tz), 'YYYY-MM-DD')", column)
+}
+
+func (r *AnalyticsRepository) scan(ctx context.Context, query string, rg Range, dest any) error {
+	err := r.db.WithContext(ctx).
+		Raw(query, rg.args()).
+		Scan(dest).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return err
+	}
+
+	return nil
+}
+
+// ! ---------------------------------- admin --------------------------------- ! //
+// * every query filters the orders on created_at first, so they
+// * only read the range through the index on the column
+func (r *AnalyticsRepository) OrdersByStatus(ctx context.Context, rg Range) ([]responses.AnalyticsOrderStatus, error) {
+	var (
+		rows []responses.AnalyticsOrderStatus
+	)
+
+	query := `
+		SELECT
+			` + bucket("o.created_at") + ` AS bucket,
+			o.status,
+			COUNT(*) AS count
+		FROM orders o
+		WHERE o.deleted_at IS NULL
+			AND o.created_at >= @from AND o.created_at < @to
+		GROUP BY 1, 2
+		ORDER BY 1, 2
+	`
+
+	if err := r.scan(ctx, query, rg, &rows); err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func (r *AnalyticsRepository) Cancellations(ctx context.Context, rg Range) ([]responses.AnalyticsCancellationBucket, error) {
+	var (
+		rows []responses.AnalyticsCancellationBucket
+	)
+
+	query := `
+		SELECT
+			` + bucket("o.created_at") + ` AS bucket,
+			COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE o.status = @cancelled) AS cancelled,
+			ROUND(COUNT(*) FILTER (WHERE o.status = @cancelled)::numeric / COUNT(*), 4) AS rate
+		FROM orders o
+		WHERE o.deleted_at IS NULL
+			AND o.created_at >= @from AND o.created_at < @to
+		GROUP BY 1
+		ORDER BY 1
+	`
+
+	if err := r.scan(ctx, query, rg, &rows); err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// * the previous status is the last status recorded before the
+// * cancellation, an order cancelled without a history is counted
+// * with an empty previous status and no role
+func (r *AnalyticsRepository) CancellationCauses(ctx context.Context, rg Range) ([]responses.AnalyticsCancellationCause, error) {
+	var (
+		rows []responses.AnalyticsCancellationCause
+	)
+
+	query := `
+		SELECT
+			COALESCE(prev.status::text, '') AS previous_status,
+			u.role::text::int AS cancelled_by,
+			COUNT(*) AS count
+		FROM orders o
+		LEFT JOIN LATERAL (
+			SELECT h.user_id, h.created_at
+			FROM order_histories h
+			WHERE h.order_id = o.id AND h.status = @cancelled AND h.deleted_at IS NULL
+			ORDER BY h.created_at DESC
+			LIMIT 1
+		) ch ON true
+		LEFT JOIN users u ON u.id = ch.user_id
+		LEFT JOIN LATERAL (
+			SELECT h.status
+			FROM order_histories h
+			WHERE h.order_id = o.id AND h.status <> @cancelled AND h.deleted_at IS NULL
+			ORDER BY h.created_at DESC
+			LIMIT 1
+		) prev ON true
+		WHERE o.deleted_at IS NULL
+			AND o.status = @cancelled
+			AND o.created_at >= @from AND o.created_at < @to
+		GROUP BY 1, 2
+		ORDER BY 3 DESC
+	`
+
+	if err := r.scan(ctx, query, rg, &rows); err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// * the ranking is done per bucket, cancelled orders are left out,
+// * the partner dashboard reuses it for the meals of the partner
+func (r *AnalyticsRepository) TopMeals(ctx context.Context, rg Range) ([]responses.AnalyticsTopMeal, error) {
+	var (
+		rows []responses.AnalyticsTopMeal
+	)
+
+	query := `
+		SELECT bucket, rank, meal_id, name, orders, quantity
+		FROM (
+			SELECT
+				t.*,
+				ROW_NUMBER() OVER (PARTITION BY t.bucket ORDER BY t.quantity DESC, t.meal_id) AS rank
+			FROM (
+				SELECT
+					` + bucket("o.created_at") + ` AS bucket,
+					om.meal_id,
+					m.name,
+					COUNT(DISTINCT o.id) AS orders,
+					SUM(om.quantity) AS quantity
+				FROM orders o
+				JOIN order_meals om ON om.order_id = o.id AND om.deleted_at IS NULL
+				JOIN meals m ON m.id = om.meal_id
+				WHERE o.deleted_at IS NULL
+					AND o.status <> @cancelled
+					AND o.created_at >= @from AND o.created_at < @to
+					AND (CAST(@partner AS uuid) IS NULL OR o.partner_id = @partner)
+				GROUP BY 1, 2, 3
+			) t
+		) ranked
+		WHERE rank <= @limit
+		ORDER BY bucket, rank
+	`
+
+	if err := r.scan(ctx, query, rg, &rows); err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func (r *AnalyticsRepository) TopPartners(ctx context.Context, rg Range) ([]responses.AnalyticsTopPartner, error) {
+	var (
+		rows []responses.AnalyticsTopPartner
+	)
+
+	query := `
+		SELECT bucket, rank, partner_id, name, orders, quantity
+		FROM (
+			SELECT
+				t.*,
+				ROW_NUMBER() OVER (PARTITION BY t.bucket ORDER BY t.orders DESC, t.partner_id) AS rank
+			FROM (
+				SELECT
+					` + bucket("o.created_at") + ` AS bucket,
+					o.partner_id,
+					p.name,
+					COUNT(DISTINCT o.id) AS orders,
+					COALESCE(SUM(om.quantity), 0) AS quantity
+				FROM orders o
+				JOIN partners p ON p.id = o.partner_id
+				LEFT JOIN order_meals om ON om.order_id = o.id AND om.deleted_at IS NULL
+				WHERE o.deleted_at IS NULL
+					AND o.status <> @cancelled
+					AND o.created_at >= @from AND o.created_at < @to
+				GROUP BY 1, 2, 3
+			) t
+		) ranked
+		WHERE rank <= @limit
+		ORDER BY bucket, rank
+	`
+
+	if err := r.scan(ctx, query, rg, &rows); err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func (r *AnalyticsRepository) ActiveMembers(ctx context.Context, rg Range) ([]responses.AnalyticsActiveMembers, error) {
+	var (
+		rows []responses.AnalyticsActiveMembers
+	)
+
+	query := `
+		SELECT
+			` + bucket("o.created_at") + ` AS bucket,
+			org.id AS organization_id,
+			org.name,
+			COUNT(DISTINCT o.member_id) AS active_members
+		FROM orders o
+		JOIN members mb ON mb.id = o.member_id
+		JOIN organizations org ON org.id = mb.organization_id
+		WHERE o.deleted_at IS NULL
+			AND o.created_at >= @from AND o.created_at < @to
+		GROUP BY 1, 2, 3
+		ORDER BY 1, 4 DESC
+	`
+
+	if err := r.scan(ctx, query, rg, &rows); err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func (r *AnalyticsRepository) Donations(ctx context.Context, rg Range) ([]responses.AnalyticsDonations, error) {
+	var (
+		rows []responses.AnalyticsDonations
+	)
+
+	query := `
+		SELECT
+			` + bucket("d.created_at") + ` AS bucket,
+			d.status,
+			p.type AS patron_type,
+			COUNT(*) AS count,
+			COALESCE(SUM(d.value), 0) AS total
+		FROM donations d
+		JOIN patrons p ON p.id = d.patron_id
+		WHERE d.deleted_at IS NULL
+			AND d.created_at >= @from AND d.created_at < @to
+		GROUP BY 1, 2, 3
+		ORDER BY 1, 2, 3
+	`
+
+	if err := r.scan(ctx, query, rg, &rows); err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// * the histories are read once and pivoted per order, the preparation
+// * always comes after the confirmation so both are after the range start
+func (r *AnalyticsRepository) PrepTime(ctx context.Context, rg Range) ([]responses.AnalyticsPrepTime, error) {
+	var (
+		rows []responses.AnalyticsPrepTime
+	)
+
+	query := `
+		WITH transitions AS (
+			SELECT
+				h.order_id,
+				MIN(h.created_at) FILTER (WHERE h.status = @confirmed) AS confirmed_at,
+				MIN(h.created_at) FILTER (WHERE h.status = @prepared) AS prepared_at
+			FROM order_histories h
+			WHERE h.deleted_at IS NULL
+				AND h.status IN (@confirmed, @prepared)
+				AND h.created_at >= @from
+			GROUP BY h.order_id
+		)
+		SELECT
+			` + bucket("t.confirmed_at") + ` AS bucket,
+			COUNT(*) AS orders,
+			ROUND(AVG(EXTRACT(EPOCH FROM t.prepared_at - t.confirmed_at))::numeric, 2) AS avg_seconds,
+			ROUND(PERCENTILE_CONT(0.9) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM t.prepared_at - t.confirmed_at))::numeric, 2) AS p90_seconds
+		FROM transitions t
+		WHERE t.confirmed_at >= @from AND t.confirmed_at < @to
+			AND t.prepared_at IS NOT NULL
+		GROUP BY 1
+		ORDER BY 1
+	`
+
+	if err := r.scan(ctx, query, rg, &rows); err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// ! --------------------------------- partner -------------------------------- ! //
+// * the time to confirm runs from the placement of the order to its
+// * first confirmation and the time to prepare from the confirmation
+// * to the preparation, the orders cancelled by the admin user are
+// * the ones the cron cancelled for not being confirmed in time
+func (r *AnalyticsRepository) KitchenTimes(ctx context.Context, rg Range) ([]responses.AnalyticsKitchen, error) {
+	var (
+		rows []responses.AnalyticsKitchen
+	)
+
+	query := `
+		WITH transitions AS (
+			SELECT
+				o.id,
+				o.created_at,
+				o.status,
+				MIN(h.created_at) FILTER (WHERE h.status = @confirmed) AS confirmed_at,
+				MIN(h.created_at) FILTER (WHERE h.status = @prepared) AS prepared_at,
+				COALESCE(BOOL_OR(h.status = @cancelled AND u.role::text::int = @admin), false) AS auto_cancelled
+			FROM orders o
+			LEFT JOIN order_histories h ON h.order_id = o.id AND h.deleted_at IS NULL
+			LEFT JOIN users u ON u.id = h.user_id
+			WHERE o.deleted_at IS NULL
+				AND o.partner_id = @partner
+				AND o.created_at >= @from AND o.created_at < @to
+			GROUP BY o.id, o.created_at, o.status
+		)
+		SELECT
+			` + bucket("t.created_at") + ` AS bucket,
+			COUNT(*) AS orders,
+			COUNT(t.confirmed_at) AS confirmed,
+			COALESCE(ROUND(AVG(EXTRACT(EPOCH FROM t.confirmed_at - t.created_at))::numeric, 2), 0) AS avg_confirm_seconds,
+			COALESCE(ROUND(PERCENTILE_CONT(0.9) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM t.confirmed_at - t.created_at))::numeric, 2), 0) AS p90_confirm_seconds,
+			COUNT(t.prepared_at - t.confirmed_at) AS prepared,
+			COALESCE(ROUND(AVG(EXTRACT(EPOCH FROM t.prepared_at - t.confirmed_at))::numeric, 2), 0) AS avg_prepare_seconds,
+			COALESCE(ROUND(PERCENTILE_CONT(0.9) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM t.prepared_at - t.confirmed_at))::numeric, 2), 0) AS p90_prepare_seconds,
+			COUNT(*) FILTER (WHERE t.status = @cancelled) AS cancelled,
+			COUNT(*) FILTER (WHERE t.status = @cancelled AND t.auto_cancelled) AS auto_cancelled
+		FROM transitions t
+		GROUP BY 1
+		ORDER BY 1
+	`
+
+	if err := r.scan(ctx, query, rg, &rows); err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// * the hours are local hours, the hours without orders are left out
+func (r *AnalyticsRepository) OrdersByHour(ctx context.Context, rg Range) ([]responses.AnalyticsHourlyOrders, error) {
+	var (
+		rows []responses.AnalyticsHourlyOrders
+	)
+
+	query := `
+		SELECT
+			EXTRACT(HOUR FROM o.created_at AT TIME ZONE @tz)::int AS hour,
+			COUNT(*) AS orders,
+			COUNT(*) FILTER (WHERE o.status = @cancelled) AS cancelled
+		FROM orders o
+		WHERE o.deleted_at IS NULL
+			AND o.partner_id = @partner
+			AND o.created_at >= @from AND o.created_at < @to
+		GROUP BY 1
+		ORDER BY 1
+	`
+
+	if err := r.scan(ctx, query, rg, &rows); err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// * the ratings are read by the time they were given
+func (r *AnalyticsRepository) MealRatings(ctx context.Context, rg Range) ([]responses.AnalyticsMealRating, error) {
+	var (
+		rows []responses.AnalyticsMealRating
+	)
+
+	query := `
+		SELECT
+			m.id AS meal_id,
+			m.name,
+			COUNT(*) AS ratings,
+			ROUND(AVG(rt.value)::numeric, 2) AS average
+		FROM ratings rt
+		JOIN meals m ON m.id = rt.meal_id
+		WHERE rt.deleted_at IS NULL
+			AND m.partner_id = @partner
+			AND rt.created_at >= @from AND rt.created_at < @to
+		GROUP BY 1, 2
+		ORDER BY 4 DESC, 3 DESC
+	`
+
+	if err := r.scan(ctx, query, rg, &rows); err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// * the meals of the orders placed in the range that are confirmed
+// * or being prepared, which is what is left for the kitchen to cook
+func (r *AnalyticsRepository) PrepList(ctx context.Context, rg Range) ([]responses.AnalyticsPrepListMeal, error) {
+	var (
+		rows []responses.AnalyticsPrepListMeal
+	)
+
+	query := `
+		SELECT
+			om.meal_id,
+			m.name,
+			COUNT(DISTINCT o.id) AS orders,
+			SUM(om.quantity) AS quantity
+		FROM orders o
+		JOIN order_meals om ON om.order_id = o.id AND om.deleted_at IS NULL
+		JOIN meals m ON m.id = om.meal_id
+		WHERE o.deleted_at IS NULL
+			AND o.partner_id = @partner
+			AND o.status IN (@confirmed, @preparing)
+			AND o.created_at >= @from AND o.created_at < @to
+		GROUP BY 1, 2
+		ORDER BY 4 DESC, 2
+	`
+
+	if err := r.scan(ctx, query, rg, &rows); err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,152 @@
+package apikeyrepo
+
+import (
+	"context"
+	"project-skbackend/internal/models"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/utils/utlogger"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	SELECTED_FIELDS = `
+		id,
+		service_account_id,
+		name,
+		prefix,
+		key_hash,
+		expires_at,
+		last_used_at,
+		last_used_ip,
+		revoked_at,
+		created_at,
+		updated_at
+	`
+)
+
+type (
+	APIKeyRepository struct {
+		db *gorm.DB
+	}
+
+	IAPIKeyRepository interface {
+		Create(ctx context.Context, ak models.APIKey) (*models.APIKey, error)
+		FindByServiceAccountID(ctx context.Context, said uuid.UUID) ([]*models.APIKey, error)
+		GetByID(ctx context.Context, id uuid.UUID) (*models.APIKey, error)
+		GetByKeyHash(ctx context.Context, keyhash string) (*models.APIKey, error)
+		Revoke(ctx context.Context, id uuid.UUID) (bool, error)
+		Touch(ctx context.Context, id uuid.UUID, ip string, before time.Time) error
+	}
+)
+
+func NewAPIKeyRepository(db *gorm.DB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+func (r *APIKeyRepository) Create(ctx context.Context, ak models.APIKey) (*models.APIKey, error) {
+	err := r.db.WithContext(ctx).
+		Omit("ServiceAccount").
+		Create(&ak).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return &ak, nil
+}
+
+func (r *APIKeyRepository) FindByServiceAccountID(ctx context.Context, said uuid.UUID) ([]*models.APIKey, error) {
+	var (
+		aks []*models.APIKey
+	)
+
+	err := r.db.WithContext(ctx).
+		Select(SELECTED_FIELDS).
+		Where("service_account_id = ?", said).
+		Order("created_at DESC").
+		Find(&aks).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return aks, nil
+}
+
+func (r *APIKeyRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.APIKey, error) {
+	var (
+		ak *models.APIKey
+	)
+
+	err := r.db.WithContext(ctx).
+		Select(SELECTED_FIELDS).
+		Where("id = ?", id).
+		First(&ak).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ak, nil
+}
+
+// * loads the service account, its scopes and the user it acts for
+// * together with the key, everything the middleware needs at once
+func (r *APIKeyRepository) GetByKeyHash(ctx context.Context, keyhash string) (*models.APIKey, error) {
+	var (
+		ak *models.APIKey
+	)
+
+	err := r.db.WithContext(ctx).
+		Preload("ServiceAccount").
+		Preload("ServiceAccount.Scopes").
+		Preload("ServiceAccount.User").
+		Select(SELECTED_FIELDS).
+		Where("key_hash = ?", keyhash).
+		First(&ak).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ak, nil
+}
+
+// * false means the key is already revoked
+func (r *APIKeyRepository) Revoke(ctx context.Context, id uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", consttypes.TimeNow())
+
+	if result.Error != nil {
+		utlogger.ErrorContext(ctx, result.Error)
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// * the last use is only written when the stored one is older than
+// * before, so a busy integration does not write on every request
+func (r *APIKeyRepository) Touch(ctx context.Context, id uuid.UUID, ip string, before time.Time) error {
+	err := r.db.WithContext(ctx).
+		Model(&models.APIKey{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, before).
+		Updates(map[string]any{
+			"last_used_at": consttypes.TimeNow(),
+			"last_used_ip": ip,
+		}).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return err
+	}
+
+	return nil
+}
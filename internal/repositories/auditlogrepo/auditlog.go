@@ -0,0 +1,241 @@
+package auditlogrepo
+
+import (
+	"context"
+	"project-skbackend/internal/controllers/responses"
+	"project-skbackend/internal/models"
+	"project-skbackend/internal/repositories/paginationrepo"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/utils/utlogger"
+	"project-skbackend/packages/utils/utpagination"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/jinzhu/copier"
+	"gorm.io/gorm"
+)
+
+var (
+	SELECTED_FIELDS = `
+		id,
+		actor_id,
+		actor_email,
+		actor_role,
+		action,
+		entity,
+		entity_id,
+		method,
+		path,
+		route,
+		status_code,
+		ip,
+		user_agent,
+		duration,
+		before,
+		after,
+		changes,
+		created_at,
+		updated_at
+	`
+
+	FILTER_FIELDS = paginationrepo.Fields{
+		"actor_id":    {Column: "actor_id", Type: consttypes.FFT_UUID},
+		"actor_email": {Column: "actor_email", Type: consttypes.FFT_STRING},
+		"actor_role":  {Column: "actor_role", Type: consttypes.FFT_STRING},
+		"action":      paginationrepo.EnumField("action", consttypes.AA_CREATE, consttypes.AA_UPDATE, consttypes.AA_DELETE),
+		"entity":      {Column: "entity", Type: consttypes.FFT_STRING},
+		"entity_id":   {Column: "entity_id", Type: consttypes.FFT_STRING},
+		"method":      {Column: "method", Type: consttypes.FFT_STRING},
+		"route":       {Column: "route", Type: consttypes.FFT_STRING},
+		"status_code": {Column: "status_code", Type: consttypes.FFT_NUMBER},
+		"duration":    {Column: "duration", Type: consttypes.FFT_NUMBER},
+		"created_at":  {Column: "created_at", Type: consttypes.FFT_TIME},
+	}
+)
+
+type (
+	AuditLogRepository struct {
+		db *gorm.DB
+	}
+
+	IAuditLogRepository interface {
+		Create(ctx context.Context, al models.AuditLog) (*models.AuditLog, error)
+		GetByID(ctx context.Context, id uuid.UUID) (*models.AuditLog, error)
+		FindAll(ctx context.Context, p utpagination.Pagination) (*utpagination.Pagination, error)
+		FindForExport(ctx context.Context, f utpagination.Filter, limit int) ([]*models.AuditLog, error)
+
+		// * snapshots
+		Snapshot(ctx context.Context, model any, id uuid.UUID) (map[string]any, error)
+		SnapshotRolePermissions(ctx context.Context, role consttypes.UserRole) (map[string]any, error)
+	}
+)
+
+func NewAuditLogRepository(db *gorm.DB) *AuditLogRepository {
+	return &AuditLogRepository{db: db}
+}
+
+func (r *AuditLogRepository) filter(result *gorm.DB, f utpagination.Filter) *gorm.DB {
+	if !f.CreatedFrom.IsZero() {
+		result = result.
+			Where("date(created_at) >= ?", f.CreatedFrom.Format(consttypes.DATEFORMAT))
+	}
+
+	if !f.CreatedTo.IsZero() {
+		result = result.
+			Where("date(created_at) <= ?", f.CreatedTo.Format(consttypes.DATEFORMAT))
+	}
+
+	if f.Audit.ActorID != nil {
+		result = result.
+			Where("actor_id = ?", f.Audit.ActorID)
+	}
+
+	if f.Audit.Action != "" {
+		result = result.
+			Where("action = ?", f.Audit.Action)
+	}
+
+	if f.Audit.Entity != "" {
+		result = result.
+			Where("entity = ?", f.Audit.Entity)
+	}
+
+	if f.Audit.EntityID != "" {
+		result = result.
+			Where("entity_id = ?", f.Audit.EntityID)
+	}
+
+	return result
+}
+
+func (r *AuditLogRepository) Create(ctx context.Context, al models.AuditLog) (*models.AuditLog, error) {
+	err := r.db.WithContext(ctx).
+		Create(&al).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return &al, nil
+}
+
+func (r *AuditLogRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.AuditLog, error) {
+	var (
+		al *models.AuditLog
+	)
+
+	err := r.db.WithContext(ctx).
+		Select(SELECTED_FIELDS).
+		Where("id = ?", id).
+		First(&al).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return al, nil
+}
+
+func (r *AuditLogRepository) FindAll(ctx context.Context, p utpagination.Pagination) (*utpagination.Pagination, error) {
+	var (
+		als    []models.AuditLog
+		alsres []responses.AuditLog
+	)
+
+	result := r.db.WithContext(ctx).
+		Model(&als).
+		Select(SELECTED_FIELDS)
+
+	result = r.filter(result, p.Filter)
+
+	result = result.
+		Scopes(paginationrepo.PaginateKeyset(&als, &p, result, FILTER_FIELDS)).
+		Find(&als)
+
+	if err := result.Error; err != nil {
+		utlogger.ErrorContext(ctx, result.Error)
+		return nil, result.Error
+	}
+
+	if err := paginationrepo.NextCursor(&als, &p, result, FILTER_FIELDS); err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	// * copy the data from model to response
+	copier.CopyWithOption(&alsres, &als, copier.Option{IgnoreEmpty: true, DeepCopy: true})
+
+	p.Data = alsres
+	return &p, nil
+}
+
+// * reads at most limit logs matching the filter, oldest first
+func (r *AuditLogRepository) FindForExport(ctx context.Context, f utpagination.Filter, limit int) ([]*models.AuditLog, error) {
+	var (
+		als []*models.AuditLog
+	)
+
+	result := r.db.WithContext(ctx).
+		Model(&als).
+		Select(SELECTED_FIELDS)
+
+	err := r.filter(result, f).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&als).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return als, nil
+}
+
+// ! -------------------------------- snapshots ------------------------------- ! //
+// * reads the raw row of the model, including the soft deleted one,
+// * an empty snapshot is returned when the row does not exist
+func (r *AuditLogRepository) Snapshot(ctx context.Context, model any, id uuid.UUID) (map[string]any, error) {
+	var (
+		rows []map[string]any
+	)
+
+	err := r.db.WithContext(ctx).
+		Unscoped().
+		Model(model).
+		Where("id = ?", id).
+		Limit(1).
+		Find(&rows).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	return rows[0], nil
+}
+
+func (r *AuditLogRepository) SnapshotRolePermissions(ctx context.Context, role consttypes.UserRole) (map[string]any, error) {
+	var (
+		perms []string
+	)
+
+	err := r.db.WithContext(ctx).
+		Model(&models.RolePermission{}).
+		Where("role = ?", role).
+		Pluck("permission", &perms).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	slices.Sort(perms)
+
+	return map[string]any{"permissions": perms}, nil
+}
@@ -0,0 +1,233 @@
+package caregiverinvitationrepo
+
+import (
+	"context"
+	"project-skbackend/internal/models"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/utils/utlogger"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	SELECTED_FIELDS = `
+		id,
+		member_id,
+		email,
+		token,
+		can_order,
+		can_edit_health,
+		status,
+		expires_at,
+		responded_at,
+		created_at,
+		updated_at
+	`
+)
+
+type (
+	CaregiverInvitationRepository struct {
+		db *gorm.DB
+	}
+
+	ICaregiverInvitationRepository interface {
+		Create(ctx context.Context, ci models.CaregiverInvitation) (*models.CaregiverInvitation, error)
+		Update(ctx context.Context, ci models.CaregiverInvitation) (*models.CaregiverInvitation, error)
+		GetByID(ctx context.Context, id uuid.UUID) (*models.CaregiverInvitation, error)
+		GetByToken(ctx context.Context, token string) (*models.CaregiverInvitation, error)
+		GetPendingByMemberIDAndEmail(ctx context.Context, mid uuid.UUID, email string) (*models.CaregiverInvitation, error)
+		FindByMemberID(ctx context.Context, mid uuid.UUID) ([]*models.CaregiverInvitation, error)
+		Accept(ctx context.Context, ci models.CaregiverInvitation, cg models.Caregiver) (*models.MemberCaregiver, bool, error)
+	}
+)
+
+func NewCaregiverInvitationRepository(db *gorm.DB) *CaregiverInvitationRepository {
+	return &CaregiverInvitationRepository{db: db}
+}
+
+func (r *CaregiverInvitationRepository) omit(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).
+		Omit(
+			"Member",
+		)
+}
+
+func (r *CaregiverInvitationRepository) preload(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).
+		Preload(clause.Associations).
+		Preload("Member.User")
+}
+
+func (r *CaregiverInvitationRepository) Create(ctx context.Context, ci models.CaregiverInvitation) (*models.CaregiverInvitation, error) {
+	err := r.
+		omit(ctx).
+		Create(&ci).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	cinew, err := r.GetByID(ctx, ci.ID)
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return cinew, nil
+}
+
+func (r *CaregiverInvitationRepository) Update(ctx context.Context, ci models.CaregiverInvitation) (*models.CaregiverInvitation, error) {
+	err := r.
+		omit(ctx).
+		Save(&ci).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	cinew, err := r.GetByID(ctx, ci.ID)
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return cinew, nil
+}
+
+func (r *CaregiverInvitationRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.CaregiverInvitation, error) {
+	var (
+		ci *models.CaregiverInvitation
+	)
+
+	err := r.
+		preload(ctx).
+		Select(SELECTED_FIELDS).
+		Where("id = ?", id).
+		First(&ci).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return ci, nil
+}
+
+func (r *CaregiverInvitationRepository) GetByToken(ctx context.Context, token string) (*models.CaregiverInvitation, error) {
+	var (
+		ci *models.CaregiverInvitation
+	)
+
+	err := r.
+		preload(ctx).
+		Select(SELECTED_FIELDS).
+		Where("token = ?", token).
+		First(&ci).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return ci, nil
+}
+
+func (r *CaregiverInvitationRepository) GetPendingByMemberIDAndEmail(ctx context.Context, mid uuid.UUID, email string) (*models.CaregiverInvitation, error) {
+	var (
+		ci *models.CaregiverInvitation
+	)
+
+	err := r.
+		preload(ctx).
+		Select(SELECTED_FIELDS).
+		Where("member_id = ? AND email = ? AND status = ? AND expires_at > ?", mid, email, consttypes.CIS_PENDING, consttypes.TimeNow()).
+		First(&ci).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return ci, nil
+}
+
+func (r *CaregiverInvitationRepository) FindByMemberID(ctx context.Context, mid uuid.UUID) ([]*models.CaregiverInvitation, error) {
+	var (
+		cis []*models.CaregiverInvitation
+	)
+
+	err := r.
+		preload(ctx).
+		Select(SELECTED_FIELDS).
+		Where("member_id = ?", mid).
+		Order("created_at DESC").
+		Find(&cis).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return cis, nil
+}
+
+// * claims the invitation and links the caregiver in one transaction, the
+// * caregiver is created with its user when it has no id yet. false means
+// * the invitation was already answered or expired, so nothing is written
+func (r *CaregiverInvitationRepository) Accept(ctx context.Context, ci models.CaregiverInvitation, cg models.Caregiver) (*models.MemberCaregiver, bool, error) {
+	var (
+		mc      *models.MemberCaregiver
+		claimed bool
+	)
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := consttypes.TimeNow()
+
+		result := tx.
+			Model(&models.CaregiverInvitation{}).
+			Where("id = ? AND status = ? AND expires_at > ?", ci.ID, consttypes.CIS_PENDING, now).
+			Updates(map[string]any{
+				"status":       consttypes.CIS_ACCEPTED,
+				"responded_at": now,
+			})
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		claimed = true
+
+		if cg.ID == uuid.Nil {
+			err := tx.
+				Session(&gorm.Session{FullSaveAssociations: true}).
+				Create(&cg).Error
+
+			if err != nil {
+				return err
+			}
+		}
+
+		mc = ci.ToMemberCaregiver(cg)
+
+		return tx.
+			Omit("Member", "Caregiver").
+			Create(mc).Error
+	})
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, false, err
+	}
+
+	return mc, claimed, nil
+}
@@ -1,6 +1,7 @@
 package caregiverrepo
 
 import (
+	"context"
 	"fmt"
 	"project-skbackend/internal/controllers/responses"
 	"project-skbackend/internal/models"
@@ -27,6 +28,15 @@ var (
 		created_at,
 		updated_at
 	`
+
+	FILTER_FIELDS = paginationrepo.Fields{
+		"user_id":    {Column: "user_id", Type: consttypes.FFT_UUID},
+		"first_name": {Column: "first_name", Type: consttypes.FFT_STRING},
+		"last_name":  {Column: "last_name", Type: consttypes.FFT_STRING},
+		"gender":     paginationrepo.EnumField("gender", consttypes.G_MALE, consttypes.G_FEMALE, consttypes.G_OTHER),
+		"created_at": {Column: "created_at", Type: consttypes.FFT_TIME},
+		"updated_at": {Column: "updated_at", Type: consttypes.FFT_TIME},
+	}
 )
 
 type (
@@ -35,14 +45,14 @@ type (
 	}
 
 	ICaregiverRepository interface {
-		Create(cg models.Caregiver) (*models.Caregiver, error)
-		Read() ([]*models.Caregiver, error)
-		Update(cg models.Caregiver) (*models.Caregiver, error)
-		Delete(cg models.Caregiver) error
-		FindAll(p utpagination.Pagination) (*utpagination.Pagination, error)
-		GetByID(id uuid.UUID) (*models.Caregiver, error)
-		GetByEmail(email string) (*models.Caregiver, error)
-		GetByUserID(uid uuid.UUID) (*models.Caregiver, error)
+		Create(ctx context.Context, cg models.Caregiver) (*models.Caregiver, error)
+		Read(ctx context.Context) ([]*models.Caregiver, error)
+		Update(ctx context.Context, cg models.Caregiver) (*models.Caregiver, error)
+		Delete(ctx context.Context, cg models.Caregiver) error
+		FindAll(ctx context.Context, p utpagination.Pagination) (*utpagination.Pagination, error)
+		GetByID(ctx context.Context, id uuid.UUID) (*models.Caregiver, error)
+		GetByEmail(ctx context.Context, email string) (*models.Caregiver, error)
+		GetByUserID(ctx context.Context, uid uuid.UUID) (*models.Caregiver, error)
 	}
 )
 
@@ -50,99 +60,99 @@ func NewCaregiverRepository(db *gorm.DB) *CaregiverRepository {
 	return &CaregiverRepository{db: db}
 }
 
-func (r *CaregiverRepository) omit() *gorm.DB {
-	return r.db.Omit(
+func (r *CaregiverRepository) omit(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Omit(
 		"",
 	)
 }
 
-func (r *CaregiverRepository) preload() *gorm.DB {
-	return r.db.
+func (r *CaregiverRepository) preload(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).
 		Preload(clause.Associations).
 		Preload("User.Image.Image").
 		Preload("User.Addresses.AddressDetail")
 }
 
-func (r *CaregiverRepository) Create(cg models.Caregiver) (*models.Caregiver, error) {
+func (r *CaregiverRepository) Create(ctx context.Context, cg models.Caregiver) (*models.Caregiver, error) {
 	err := r.
-		omit().
+		omit(ctx).
 		Session(&gorm.Session{FullSaveAssociations: true}).
 		Create(&cg).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
-	cgnew, err := r.GetByID(cg.ID)
+	cgnew, err := r.GetByID(ctx, cg.ID)
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return cgnew, nil
 }
 
-func (r *CaregiverRepository) Read() ([]*models.Caregiver, error) {
+func (r *CaregiverRepository) Read(ctx context.Context) ([]*models.Caregiver, error) {
 	var (
 		cg []*models.Caregiver
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Find(&cg).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return cg, nil
 }
 
-func (r *CaregiverRepository) Update(cg models.Caregiver) (*models.Caregiver, error) {
+func (r *CaregiverRepository) Update(ctx context.Context, cg models.Caregiver) (*models.Caregiver, error) {
 	err := r.
-		omit().
+		omit(ctx).
 		Session(&gorm.Session{FullSaveAssociations: true}).
 		Save(&cg).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
-	cgnew, err := r.GetByID(cg.ID)
+	cgnew, err := r.GetByID(ctx, cg.ID)
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return cgnew, nil
 }
 
-func (r *CaregiverRepository) Delete(cg models.Caregiver) error {
-	err := r.db.
+func (r *CaregiverRepository) Delete(ctx context.Context, cg models.Caregiver) error {
+	err := r.db.WithContext(ctx).
 		Delete(&cg).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return err
 	}
 
 	return nil
 }
 
-func (r *CaregiverRepository) FindAll(p utpagination.Pagination) (*utpagination.Pagination, error) {
+func (r *CaregiverRepository) FindAll(ctx context.Context, p utpagination.Pagination) (*utpagination.Pagination, error) {
 	var (
 		cg    []models.Caregiver
 		cgres []responses.Caregiver
 	)
 
 	result := r.
-		preload().
+		preload(ctx).
 		Model(&cg).
 		Select(SELECTED_FIELDS)
 
@@ -150,7 +160,7 @@ func (r *CaregiverRepository) FindAll(p utpagination.Pagination) (*utpagination.
 		p.Search = fmt.Sprintf("%%%s%%", p.Search)
 		result = result.
 			Where(
-				r.db.Where(`
+				r.db.WithContext(ctx).Where(`
 					first_name ILIKE ?
 						OR 
 					last_name ILIKE ? 
@@ -168,11 +178,11 @@ func (r *CaregiverRepository) FindAll(p utpagination.Pagination) (*utpagination.
 
 	result = result.
 		Group("id").
-		Scopes(paginationrepo.Paginate(&cg, &p, result)).
+		Scopes(paginationrepo.Paginate(&cg, &p, result, FILTER_FIELDS)).
 		Find(&cg)
 
 	if err := result.Error; err != nil {
-		utlogger.Error(result.Error)
+		utlogger.ErrorContext(ctx, result.Error)
 		return nil, result.Error
 	}
 
@@ -183,32 +193,32 @@ func (r *CaregiverRepository) FindAll(p utpagination.Pagination) (*utpagination.
 	return &p, nil
 }
 
-func (r *CaregiverRepository) GetByID(id uuid.UUID) (*models.Caregiver, error) {
+func (r *CaregiverRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Caregiver, error) {
 	var (
 		cg *models.Caregiver
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Where(&models.Caregiver{Model: base.Model{ID: id}}).
 		First(&cg).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return cg, nil
 }
 
-func (r *CaregiverRepository) GetByEmail(email string) (*models.Caregiver, error) {
+func (r *CaregiverRepository) GetByEmail(ctx context.Context, email string) (*models.Caregiver, error) {
 	var (
 		cg *models.Caregiver
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Where(`
 			caregivers.user_id IN (
@@ -226,26 +236,26 @@ func (r *CaregiverRepository) GetByEmail(email string) (*models.Caregiver, error
 		First(&cg).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return cg, nil
 }
 
-func (r *CaregiverRepository) GetByUserID(uid uuid.UUID) (*models.Caregiver, error) {
+func (r *CaregiverRepository) GetByUserID(ctx context.Context, uid uuid.UUID) (*models.Caregiver, error) {
 	var (
 		cg *models.Caregiver
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Where(&models.Caregiver{UserID: uid}, uid).
 		First(&cg).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
@@ -1,6 +1,7 @@
 package cartrepo
 
 import (
+	"context"
 	"project-skbackend/internal/controllers/responses"
 	"project-skbackend/internal/models"
 	"project-skbackend/internal/models/base"
@@ -25,6 +26,15 @@ var (
 		created_at,
 		updated_at
 	`
+
+	FILTER_FIELDS = paginationrepo.Fields{
+		"meal_id":    {Column: "meal_id", Type: consttypes.FFT_UUID},
+		"member_id":  {Column: "member_id", Type: consttypes.FFT_UUID},
+		"partner_id": {Column: "partner_id", Type: consttypes.FFT_UUID},
+		"quantity":   {Column: "quantity", Type: consttypes.FFT_NUMBER},
+		"created_at": {Column: "created_at", Type: consttypes.FFT_TIME},
+		"updated_at": {Column: "updated_at", Type: consttypes.FFT_TIME},
+	}
 )
 
 type (
@@ -33,16 +43,16 @@ type (
 	}
 
 	ICartRepository interface {
-		Create(c models.Cart) (*models.Cart, error)
-		Read() ([]*models.Cart, error)
-		Update(c models.Cart) (*models.Cart, error)
-		Delete(c models.Cart) error
-		DeleteByIDs(ids []uuid.UUID) error
-		FindAll(p utpagination.Pagination) (*utpagination.Pagination, error)
-		GetByID(id uuid.UUID) (*models.Cart, error)
-		FindByMemberID(mid uuid.UUID) ([]*models.Cart, error)
-		FindByMealID(mid uuid.UUID) ([]*models.Cart, error)
-		GetByMealIDAndMemberID(membid uuid.UUID, mealid uuid.UUID) (*models.Cart, error)
+		Create(ctx context.Context, c models.Cart) (*models.Cart, error)
+		Read(ctx context.Context) ([]*models.Cart, error)
+		Update(ctx context.Context, c models.Cart) (*models.Cart, error)
+		Delete(ctx context.Context, c models.Cart) error
+		DeleteByIDs(ctx context.Context, ids []uuid.UUID) error
+		FindAll(ctx context.Context, p utpagination.Pagination) (*utpagination.Pagination, error)
+		GetByID(ctx context.Context, id uuid.UUID) (*models.Cart, error)
+		FindByMemberID(ctx context.Context, mid uuid.UUID) ([]*models.Cart, error)
+		FindByMealID(ctx context.Context, mid uuid.UUID) ([]*models.Cart, error)
+		GetByMealIDAndMemberID(ctx context.Context, membid uuid.UUID, mealid uuid.UUID) (*models.Cart, error)
 	}
 )
 
@@ -50,16 +60,16 @@ func NewCartRepository(db *gorm.DB) *CartRepository {
 	return &CartRepository{db: db}
 }
 
-func (r *CartRepository) omit() *gorm.DB {
-	return r.db.Omit(
+func (r *CartRepository) omit(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Omit(
 		"Meal",
 		"Member",
 		"Partner",
 	)
 }
 
-func (r *CartRepository) preload() *gorm.DB {
-	return r.db.
+func (r *CartRepository) preload(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).
 		Preload(clause.Associations).
 		Preload("Meal.Images.Image").
 		Preload("Meal.Illnesses.Illness").
@@ -68,110 +78,110 @@ func (r *CartRepository) preload() *gorm.DB {
 		Preload("Meal.Partner.User.Image.Image").
 		Preload("Member.User.Image.Image").
 		Preload("Member.User.Addresses.AddressDetail").
-		Preload("Member.Caregiver.User.Image.Image").
-		Preload("Member.Caregiver.User.Addresses.AddressDetail").
+		Preload("Member.Caregivers.Caregiver.User.Image.Image").
+		Preload("Member.Caregivers.Caregiver.User.Addresses.AddressDetail").
 		Preload("Member.Organization").
-		Preload("Member.Allergies.Allergy").
-		Preload("Member.Illnesses.Illness")
+		Preload("Member.Allergies").
+		Preload("Member.Illnesses")
 }
 
-func (r *CartRepository) Create(c models.Cart) (*models.Cart, error) {
+func (r *CartRepository) Create(ctx context.Context, c models.Cart) (*models.Cart, error) {
 	err := r.
-		omit().
+		omit(ctx).
 		Session(&gorm.Session{FullSaveAssociations: true}).
 		Create(&c).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
-	cnew, err := r.GetByID(c.ID)
+	cnew, err := r.GetByID(ctx, c.ID)
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return cnew, nil
 }
 
-func (r *CartRepository) Read() ([]*models.Cart, error) {
+func (r *CartRepository) Read(ctx context.Context) ([]*models.Cart, error) {
 	var (
 		c []*models.Cart
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Find(&c).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return c, nil
 }
 
-func (r *CartRepository) Update(c models.Cart) (*models.Cart, error) {
+func (r *CartRepository) Update(ctx context.Context, c models.Cart) (*models.Cart, error) {
 	err := r.
-		omit().
+		omit(ctx).
 		Session(&gorm.Session{FullSaveAssociations: true}).
 		Model(&models.Cart{}).
 		Where("id = ?", c.ID).
 		Updates(&c).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
-	cnew, err := r.GetByID(c.ID)
+	cnew, err := r.GetByID(ctx, c.ID)
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return cnew, nil
 }
 
-func (r *CartRepository) Delete(c models.Cart) error {
-	err := r.db.
+func (r *CartRepository) Delete(ctx context.Context, c models.Cart) error {
+	err := r.db.WithContext(ctx).
 		Unscoped().
 		Delete(&c).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return err
 	}
 
 	return nil
 }
 
-func (r *CartRepository) DeleteByIDs(ids []uuid.UUID) error {
-	err := r.db.
+func (r *CartRepository) DeleteByIDs(ctx context.Context, ids []uuid.UUID) error {
+	err := r.db.WithContext(ctx).
 		Unscoped().
 		Where("id IN ?", ids).
 		Delete(&models.Cart{}).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return err
 	}
 
 	return nil
 }
 
-func (r *CartRepository) FindAll(p utpagination.Pagination) (*utpagination.Pagination, error) {
+func (r *CartRepository) FindAll(ctx context.Context, p utpagination.Pagination) (*utpagination.Pagination, error) {
 	var (
 		c    []models.Cart
 		cres []responses.Cart
 	)
 
 	result := r.
-		preload().
+		preload(ctx).
 		Model(&c).
 		Select(SELECTED_FIELDS)
 
@@ -185,11 +195,11 @@ func (r *CartRepository) FindAll(p utpagination.Pagination) (*utpagination.Pagin
 
 	result = result.
 		Group("id").
-		Scopes(paginationrepo.Paginate(&c, &p, result)).
+		Scopes(paginationrepo.Paginate(&c, &p, result, FILTER_FIELDS)).
 		Find(&c)
 
 	if result.Error != nil {
-		utlogger.Error(result.Error)
+		utlogger.ErrorContext(ctx, result.Error)
 		return nil, result.Error
 	}
 
@@ -200,76 +210,76 @@ func (r *CartRepository) FindAll(p utpagination.Pagination) (*utpagination.Pagin
 	return &p, nil
 }
 
-func (r *CartRepository) GetByID(id uuid.UUID) (*models.Cart, error) {
+func (r *CartRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Cart, error) {
 	var (
 		c *models.Cart
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Where(&models.Cart{Model: base.Model{ID: id}}).
 		First(&c).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return c, nil
 }
 
-func (r *CartRepository) FindByMemberID(mid uuid.UUID) ([]*models.Cart, error) {
+func (r *CartRepository) FindByMemberID(ctx context.Context, mid uuid.UUID) ([]*models.Cart, error) {
 	var (
 		c []*models.Cart
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Where(&models.Cart{MemberID: mid}).
 		Find(&c).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return c, nil
 }
 
-func (r *CartRepository) FindByMealID(mid uuid.UUID) ([]*models.Cart, error) {
+func (r *CartRepository) FindByMealID(ctx context.Context, mid uuid.UUID) ([]*models.Cart, error) {
 	var (
 		c []*models.Cart
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Where(&models.Cart{MealID: mid}).
 		Find(&c).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return c, nil
 }
 
-func (r *CartRepository) GetByMealIDAndMemberID(membid uuid.UUID, mealid uuid.UUID) (*models.Cart, error) {
+func (r *CartRepository) GetByMealIDAndMemberID(ctx context.Context, membid uuid.UUID, mealid uuid.UUID) (*models.Cart, error) {
 	var (
 		c *models.Cart
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Where(&models.Cart{MemberID: membid, MealID: mealid}).
 		First(&c).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
@@ -0,0 +1,429 @@
+package dataerasurerepo
+
+import (
+	"context"
+	"fmt"
+	"project-skbackend/internal/models"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/customs/ctdatatype"
+	"project-skbackend/packages/utils/utlogger"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	SELECTED_FIELDS = `
+		id,
+		user_id,
+		requested_by_id,
+		status,
+		error,
+		completed_at,
+		created_at,
+		updated_at
+	`
+)
+
+const (
+	// * placeholder for personal data that has to be kept non empty
+	ERASED = "Erased"
+)
+
+type (
+	DataErasureRepository struct {
+		db *gorm.DB
+	}
+
+	IDataErasureRepository interface {
+		Create(ctx context.Context, de models.DataErasure) (*models.DataErasure, error)
+		Update(ctx context.Context, de models.DataErasure) (*models.DataErasure, error)
+		GetByID(ctx context.Context, id uuid.UUID) (*models.DataErasure, error)
+		GetActiveByUserID(ctx context.Context, uid uuid.UUID) (*models.DataErasure, error)
+		FindByUserID(ctx context.Context, uid uuid.UUID) ([]*models.DataErasure, error)
+		ClaimPending(ctx context.Context, limit int, timeout time.Duration) ([]*models.DataErasure, error)
+		Erase(ctx context.Context, uid uuid.UUID) ([]string, []string, error)
+	}
+)
+
+func NewDataErasureRepository(db *gorm.DB) *DataErasureRepository {
+	return &DataErasureRepository{db: db}
+}
+
+func (r *DataErasureRepository) Create(ctx context.Context, de models.DataErasure) (*models.DataErasure, error) {
+	err := r.db.WithContext(ctx).
+		Create(&de).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	denew, err := r.GetByID(ctx, de.ID)
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return denew, nil
+}
+
+func (r *DataErasureRepository) Update(ctx context.Context, de models.DataErasure) (*models.DataErasure, error) {
+	err := r.db.WithContext(ctx).
+		Save(&de).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	denew, err := r.GetByID(ctx, de.ID)
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return denew, nil
+}
+
+func (r *DataErasureRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.DataErasure, error) {
+	var (
+		de *models.DataErasure
+	)
+
+	err := r.db.WithContext(ctx).
+		Select(SELECTED_FIELDS).
+		Where("id = ?", id).
+		First(&de).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return de, nil
+}
+
+// * an erasure that is still waiting for or being processed by the job
+func (r *DataErasureRepository) GetActiveByUserID(ctx context.Context, uid uuid.UUID) (*models.DataErasure, error) {
+	var (
+		de *models.DataErasure
+	)
+
+	err := r.db.WithContext(ctx).
+		Select(SELECTED_FIELDS).
+		Where("user_id = ? AND status IN ?", uid, []consttypes.DataRequestStatus{consttypes.DRS_PENDING, consttypes.DRS_PROCESSING}).
+		First(&de).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return de, nil
+}
+
+func (r *DataErasureRepository) FindByUserID(ctx context.Context, uid uuid.UUID) ([]*models.DataErasure, error) {
+	var (
+		des []*models.DataErasure
+	)
+
+	err := r.db.WithContext(ctx).
+		Select(SELECTED_FIELDS).
+		Where("user_id = ?", uid).
+		Order("created_at DESC").
+		Find(&des).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return des, nil
+}
+
+// * moves the oldest pending requests to processing and returns them. the
+// * rows locked by another replica are skipped, so a request is claimed once.
+// * a request still processing after the timeout was left by a worker that
+// * stopped, it is claimed again
+func (r *DataErasureRepository) ClaimPending(ctx context.Context, limit int, timeout time.Duration) ([]*models.DataErasure, error) {
+	var (
+		des []*models.DataErasure
+		now = consttypes.TimeNow()
+	)
+
+	err := r.db.WithContext(ctx).
+		Raw(`
+			UPDATE data_erasures
+			SET status = ?, updated_at = ?
+			WHERE id IN (
+				SELECT id
+				FROM data_erasures
+				WHERE (status = ? OR (status = ? AND updated_at < ?)) AND deleted_at IS NULL
+				ORDER BY created_at ASC
+				LIMIT ?
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING `+SELECTED_FIELDS,
+			consttypes.DRS_PROCESSING, now, consttypes.DRS_PENDING, consttypes.DRS_PROCESSING, now.Add(-timeout), limit,
+		).
+		Scan(&des).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return des, nil
+}
+
+// * erase every personal data of the user in one transaction. records
+// * needed for accounting (orders, order histories, donations and their
+// * proofs) are kept, but point to the anonymized user and role rows.
+// * returns the image paths and data export objects that have to be
+// * removed from the storage once the transaction is committed.
+func (r *DataErasureRepository) Erase(ctx context.Context, uid uuid.UUID) ([]string, []string, error) {
+	var (
+		imagepaths  []string
+		objectnames []string
+	)
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var (
+			user         models.User
+			imageids     []uuid.UUID
+			addrdetailid []uuid.UUID
+			memberids    []uuid.UUID
+			caregiverids []uuid.UUID
+		)
+
+		if err := tx.Unscoped().Where("id = ?", uid).First(&user).Error; err != nil {
+			return err
+		}
+
+		// ! ------------------------------ user's files ----------------------------- ! //
+		err := tx.
+			Model(&models.UserImage{}).
+			Unscoped().
+			Where("user_id = ?", uid).
+			Pluck("image_id", &imageids).Error
+		if err != nil {
+			return err
+		}
+
+		if len(imageids) > 0 {
+			err = tx.
+				Model(&models.Image{}).
+				Unscoped().
+				Where("id IN ?", imageids).
+				Pluck("path", &imagepaths).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		err = tx.
+			Model(&models.DataExport{}).
+			Unscoped().
+			Where("user_id = ? AND object_name <> ''", uid).
+			Pluck("object_name", &objectnames).Error
+		if err != nil {
+			return err
+		}
+
+		// ! ---------------------------- hard deleted data --------------------------- ! //
+		err = tx.
+			Model(&models.Address{}).
+			Unscoped().
+			Where("user_id = ? AND address_detail_id IS NOT NULL", uid).
+			Pluck("address_detail_id", &addrdetailid).Error
+		if err != nil {
+			return err
+		}
+
+		deletes := []struct {
+			model any
+			query string
+			args  []any
+		}{
+			{&models.UserImage{}, "user_id = ?", []any{uid}},
+			{&models.Image{}, "id IN ?", []any{imageids}},
+			{&models.Address{}, "user_id = ?", []any{uid}},
+			{&models.AddressDetail{}, "id IN ?", []any{addrdetailid}},
+			{&models.DataExport{}, "user_id = ?", []any{uid}},
+			{&models.Rating{}, "user_id = ?", []any{uid}},
+			{&models.CaregiverInvitation{}, "email = ?", []any{user.Email}},
+			{&models.UserIdentity{}, "user_id = ?", []any{uid}},
+			{&models.UserRecoveryCode{}, "user_id = ?", []any{uid}},
+			{&models.UserTwoFactor{}, "user_id = ?", []any{uid}},
+			{&models.SecurityEvent{}, "user_id = ? OR LOWER(email) = LOWER(?)", []any{uid, user.Email}},
+			{&models.APIKey{}, "service_account_id IN (?)", []any{tx.Model(&models.ServiceAccount{}).Unscoped().Select("id").Where("user_id = ?", uid)}},
+			{&models.ServiceAccountScope{}, "service_account_id IN (?)", []any{tx.Model(&models.ServiceAccount{}).Unscoped().Select("id").Where("user_id = ?", uid)}},
+			{&models.ServiceAccount{}, "user_id = ?", []any{uid}},
+			{&models.WebhookDelivery{}, "webhook_id IN (?)", []any{tx.Model(&models.Webhook{}).Unscoped().Select("id").Where("user_id = ?", uid)}},
+			{&models.Webhook{}, "user_id = ?", []any{uid}},
+		}
+
+		for _, del := range deletes {
+			if err := tx.Unscoped().Where(del.query, del.args...).Delete(del.model).Error; err != nil {
+				return err
+			}
+		}
+
+		// * the audit logs are kept as the record of the changes, only
+		// * the email of the erased actor is removed from them
+		err = tx.
+			Model(&models.AuditLog{}).
+			Unscoped().
+			Where("actor_id = ?", uid).
+			Update("actor_email", ERASED).Error
+		if err != nil {
+			return err
+		}
+
+		// ! ---------------------------------- roles --------------------------------- ! //
+		// * the role rows are read and anonymized also when they were
+		// * soft deleted before, the soft delete below keeps them for the
+		// * orders and donations pointing to them
+		switch user.Role {
+		case consttypes.UR_MEMBER:
+			err := tx.Model(&models.Member{}).Unscoped().Where("user_id = ?", uid).Pluck("id", &memberids).Error
+			if err != nil {
+				return err
+			}
+
+			for _, model := range []any{
+				&models.MemberHealthHistory{},
+				&models.MemberAllergy{},
+				&models.MemberIllness{},
+				&models.MemberCaregiver{},
+				&models.CaregiverInvitation{},
+				&models.Cart{},
+			} {
+				if err := tx.Unscoped().Where("member_id IN ?", memberids).Delete(model).Error; err != nil {
+					return err
+				}
+			}
+
+			// * the member row is kept since the orders are pointing to it
+			err = tx.
+				Model(&models.Member{}).
+				Unscoped().
+				Where("user_id = ?", uid).
+				Select("first_name", "last_name", "height", "weight", "bmi", "date_of_birth", "organization_id").
+				Updates(&models.Member{
+					FirstName:   ERASED,
+					LastName:    ERASED,
+					DateOfBirth: ctdatatype.CDT_DATE{},
+				}).Error
+			if err != nil {
+				return err
+			}
+
+			if err := tx.Where("user_id = ?", uid).Delete(&models.Member{}).Error; err != nil {
+				return err
+			}
+		case consttypes.UR_CAREGIVER:
+			err := tx.Model(&models.Caregiver{}).Unscoped().Where("user_id = ?", uid).Pluck("id", &caregiverids).Error
+			if err != nil {
+				return err
+			}
+
+			if err := tx.Unscoped().Where("caregiver_id IN ?", caregiverids).Delete(&models.MemberCaregiver{}).Error; err != nil {
+				return err
+			}
+
+			err = tx.
+				Model(&models.Caregiver{}).
+				Unscoped().
+				Where("user_id = ?", uid).
+				Select("first_name", "last_name", "date_of_birth").
+				Updates(&models.Caregiver{
+					FirstName: ERASED,
+					LastName:  ERASED,
+				}).Error
+			if err != nil {
+				return err
+			}
+
+			if err := tx.Where("user_id = ?", uid).Delete(&models.Caregiver{}).Error; err != nil {
+				return err
+			}
+		case consttypes.UR_ADMIN:
+			err := tx.
+				Model(&models.Admin{}).
+				Unscoped().
+				Where("user_id = ?", uid).
+				Select("first_name", "last_name", "date_of_birth").
+				Updates(&models.Admin{
+					FirstName: ERASED,
+					LastName:  ERASED,
+				}).Error
+			if err != nil {
+				return err
+			}
+
+			if err := tx.Where("user_id = ?", uid).Delete(&models.Admin{}).Error; err != nil {
+				return err
+			}
+		case consttypes.UR_PATRON:
+			// * the donations are kept for accounting
+			err := tx.
+				Model(&models.Patron{}).
+				Unscoped().
+				Where("user_id = ?", uid).
+				Select("name").
+				Updates(&models.Patron{
+					Name: ERASED,
+				}).Error
+			if err != nil {
+				return err
+			}
+
+			if err := tx.Where("user_id = ?", uid).Delete(&models.Patron{}).Error; err != nil {
+				return err
+			}
+		case consttypes.UR_PARTNER:
+			// * the partner's name is a business name, the partner
+			// * row is kept as it is for the orders and meals
+			if err := tx.Where("user_id = ?", uid).Delete(&models.Partner{}).Error; err != nil {
+				return err
+			}
+		case consttypes.UR_ORGANIZATION:
+			if err := tx.Where("user_id = ?", uid).Delete(&models.Organization{}).Error; err != nil {
+				return err
+			}
+		}
+
+		// ! ---------------------------------- user ---------------------------------- ! //
+		// * the empty password can never match a bcrypt hash so
+		// * the anonymized user will not be able to login again
+		err = tx.
+			Model(&models.User{}).
+			Unscoped().
+			Where("id = ?", uid).
+			Updates(map[string]any{
+				"email":                  fmt.Sprintf("erased-%s@erased.invalid", uid),
+				"password":               "",
+				"confirmation_token":     "",
+				"reset_password_token":   "",
+				"confirmed_at":           nil,
+				"confirmation_sent_at":   nil,
+				"reset_password_sent_at": nil,
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Where("id = ?", uid).Delete(&models.User{}).Error
+	})
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, nil, err
+	}
+
+	return imagepaths, objectnames, nil
+}
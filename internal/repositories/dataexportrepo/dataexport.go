@@ -0,0 +1,204 @@
+package dataexportrepo
+
+import (
+	"context"
+	"project-skbackend/internal/models"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/utils/utlogger"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	SELECTED_FIELDS = `
+		id,
+		user_id,
+		status,
+		object_name,
+		size,
+		error,
+		completed_at,
+		expires_at,
+		created_at,
+		updated_at
+	`
+)
+
+type (
+	DataExportRepository struct {
+		db *gorm.DB
+	}
+
+	IDataExportRepository interface {
+		Create(ctx context.Context, de models.DataExport) (*models.DataExport, error)
+		Update(ctx context.Context, de models.DataExport) (*models.DataExport, error)
+		GetByID(ctx context.Context, id uuid.UUID) (*models.DataExport, error)
+		GetActiveByUserID(ctx context.Context, uid uuid.UUID) (*models.DataExport, error)
+		FindByUserID(ctx context.Context, uid uuid.UUID) ([]*models.DataExport, error)
+		ClaimPending(ctx context.Context, limit int, timeout time.Duration) ([]*models.DataExport, error)
+		FindExpired(ctx context.Context, limit int) ([]*models.DataExport, error)
+	}
+)
+
+func NewDataExportRepository(db *gorm.DB) *DataExportRepository {
+	return &DataExportRepository{db: db}
+}
+
+func (r *DataExportRepository) omit(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).
+		Omit(
+			"User",
+		)
+}
+
+func (r *DataExportRepository) Create(ctx context.Context, de models.DataExport) (*models.DataExport, error) {
+	err := r.
+		omit(ctx).
+		Create(&de).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	denew, err := r.GetByID(ctx, de.ID)
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return denew, nil
+}
+
+func (r *DataExportRepository) Update(ctx context.Context, de models.DataExport) (*models.DataExport, error) {
+	err := r.
+		omit(ctx).
+		Save(&de).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	denew, err := r.GetByID(ctx, de.ID)
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return denew, nil
+}
+
+func (r *DataExportRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.DataExport, error) {
+	var (
+		de *models.DataExport
+	)
+
+	err := r.db.WithContext(ctx).
+		Select(SELECTED_FIELDS).
+		Where("id = ?", id).
+		First(&de).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return de, nil
+}
+
+// * an export that is still waiting for or being built by the job
+func (r *DataExportRepository) GetActiveByUserID(ctx context.Context, uid uuid.UUID) (*models.DataExport, error) {
+	var (
+		de *models.DataExport
+	)
+
+	err := r.db.WithContext(ctx).
+		Select(SELECTED_FIELDS).
+		Where("user_id = ? AND status IN ?", uid, []consttypes.DataRequestStatus{consttypes.DRS_PENDING, consttypes.DRS_PROCESSING}).
+		First(&de).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return de, nil
+}
+
+func (r *DataExportRepository) FindByUserID(ctx context.Context, uid uuid.UUID) ([]*models.DataExport, error) {
+	var (
+		des []*models.DataExport
+	)
+
+	err := r.db.WithContext(ctx).
+		Select(SELECTED_FIELDS).
+		Where("user_id = ?", uid).
+		Order("created_at DESC").
+		Find(&des).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return des, nil
+}
+
+// * moves the oldest pending requests to processing and returns them. the
+// * rows locked by another replica are skipped, so a request is claimed once.
+// * a request still processing after the timeout was left by a worker that
+// * stopped, it is claimed again
+func (r *DataExportRepository) ClaimPending(ctx context.Context, limit int, timeout time.Duration) ([]*models.DataExport, error) {
+	var (
+		des []*models.DataExport
+		now = consttypes.TimeNow()
+	)
+
+	err := r.db.WithContext(ctx).
+		Raw(`
+			UPDATE data_exports
+			SET status = ?, updated_at = ?
+			WHERE id IN (
+				SELECT id
+				FROM data_exports
+				WHERE (status = ? OR (status = ? AND updated_at < ?)) AND deleted_at IS NULL
+				ORDER BY created_at ASC
+				LIMIT ?
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING `+SELECTED_FIELDS,
+			consttypes.DRS_PROCESSING, now, consttypes.DRS_PENDING, consttypes.DRS_PROCESSING, now.Add(-timeout), limit,
+		).
+		Scan(&des).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return des, nil
+}
+
+func (r *DataExportRepository) FindExpired(ctx context.Context, limit int) ([]*models.DataExport, error) {
+	var (
+		des []*models.DataExport
+	)
+
+	err := r.db.WithContext(ctx).
+		Select(SELECTED_FIELDS).
+		Where("status = ? AND expires_at <= ?", consttypes.DRS_COMPLETED, consttypes.TimeNow()).
+		Order("expires_at ASC").
+		Limit(limit).
+		Find(&des).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return des, nil
+}
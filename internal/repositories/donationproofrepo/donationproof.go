@@ -1,6 +1,7 @@
 package donationproofrepo
 
 import (
+	"context"
 	"fmt"
 	"project-skbackend/internal/controllers/responses"
 	"project-skbackend/internal/models"
@@ -23,6 +24,13 @@ var (
 		created_at,
 		updated_at
 	`
+
+	FILTER_FIELDS = paginationrepo.Fields{
+		"donation_id": {Column: "donation_id", Type: consttypes.FFT_UUID},
+		"image_id":    {Column: "image_id", Type: consttypes.FFT_UUID},
+		"created_at":  {Column: "created_at", Type: consttypes.FFT_TIME},
+		"updated_at":  {Column: "updated_at", Type: consttypes.FFT_TIME},
+	}
 )
 
 type (
@@ -31,13 +39,13 @@ type (
 	}
 
 	IDonationProofRepository interface {
-		Create(dp models.DonationProof) (*models.DonationProof, error)
-		Read() ([]*models.DonationProof, error)
-		Update(dp models.DonationProof) (*models.DonationProof, error)
-		Delete(dp models.DonationProof) error
-		FindAll(p utpagination.Pagination) (*utpagination.Pagination, error)
-		GetByID(dpid uuid.UUID) (*models.DonationProof, error)
-		FindByDonationID(did uuid.UUID) ([]*models.DonationProof, error)
+		Create(ctx context.Context, dp models.DonationProof) (*models.DonationProof, error)
+		Read(ctx context.Context) ([]*models.DonationProof, error)
+		Update(ctx context.Context, dp models.DonationProof) (*models.DonationProof, error)
+		Delete(ctx context.Context, dp models.DonationProof) error
+		FindAll(ctx context.Context, p utpagination.Pagination) (*utpagination.Pagination, error)
+		GetByID(ctx context.Context, dpid uuid.UUID) (*models.DonationProof, error)
+		FindByDonationID(ctx context.Context, did uuid.UUID) ([]*models.DonationProof, error)
 	}
 )
 
@@ -45,97 +53,97 @@ func NewDonationProofRepository(db *gorm.DB) *DonationProofRepository {
 	return &DonationProofRepository{db: db}
 }
 
-func (r *DonationProofRepository) omit() *gorm.DB {
-	return r.db.Omit(
+func (r *DonationProofRepository) omit(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Omit(
 		"",
 	)
 }
 
-func (r *DonationProofRepository) preload() *gorm.DB {
-	return r.db.
+func (r *DonationProofRepository) preload(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).
 		Preload(clause.Associations)
 }
 
-func (r *DonationProofRepository) Create(dp models.DonationProof) (*models.DonationProof, error) {
+func (r *DonationProofRepository) Create(ctx context.Context, dp models.DonationProof) (*models.DonationProof, error) {
 	err := r.
-		omit().
+		omit(ctx).
 		Session(&gorm.Session{FullSaveAssociations: true}).
 		Create(&dp).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
-	dpnew, err := r.GetByID(dp.ID)
+	dpnew, err := r.GetByID(ctx, dp.ID)
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return dpnew, nil
 }
 
-func (r *DonationProofRepository) Read() ([]*models.DonationProof, error) {
+func (r *DonationProofRepository) Read(ctx context.Context) ([]*models.DonationProof, error) {
 	var (
 		dp []*models.DonationProof
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Find(&dp).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return dp, nil
 }
 
-func (r *DonationProofRepository) Update(dp models.DonationProof) (*models.DonationProof, error) {
+func (r *DonationProofRepository) Update(ctx context.Context, dp models.DonationProof) (*models.DonationProof, error) {
 	err := r.
-		omit().
+		omit(ctx).
 		Session(&gorm.Session{FullSaveAssociations: true}).
 		Save(&dp).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
-	dpnew, err := r.GetByID(dp.ID)
+	dpnew, err := r.GetByID(ctx, dp.ID)
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return dpnew, nil
 }
 
-func (r *DonationProofRepository) Delete(dp models.DonationProof) error {
-	err := r.db.
+func (r *DonationProofRepository) Delete(ctx context.Context, dp models.DonationProof) error {
+	err := r.db.WithContext(ctx).
 		Delete(&dp).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return err
 	}
 
 	return nil
 }
 
-func (r *DonationProofRepository) FindAll(p utpagination.Pagination) (*utpagination.Pagination, error) {
+func (r *DonationProofRepository) FindAll(ctx context.Context, p utpagination.Pagination) (*utpagination.Pagination, error) {
 	var (
 		dp    []models.DonationProof
 		dpres []responses.DonationProof
 	)
 
 	result := r.
-		preload().
+		preload(ctx).
 		Model(&dp).
 		Select(SELECTED_FIELDS)
 
@@ -154,11 +162,11 @@ func (r *DonationProofRepository) FindAll(p utpagination.Pagination) (*utpaginat
 
 	result = result.
 		Group("id").
-		Scopes(paginationrepo.Paginate(&dp, &p, result)).
+		Scopes(paginationrepo.Paginate(&dp, &p, result, FILTER_FIELDS)).
 		Find(&dp)
 
 	if err := result.Error; err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
@@ -169,38 +177,38 @@ func (r *DonationProofRepository) FindAll(p utpagination.Pagination) (*utpaginat
 	return &p, nil
 }
 
-func (r *DonationProofRepository) GetByID(dpid uuid.UUID) (*models.DonationProof, error) {
+func (r *DonationProofRepository) GetByID(ctx context.Context, dpid uuid.UUID) (*models.DonationProof, error) {
 	var (
 		dp *models.DonationProof
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Where("id = ?", dpid).
 		First(&dp).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return dp, nil
 }
 
-func (r *DonationProofRepository) FindByDonationID(did uuid.UUID) ([]*models.DonationProof, error) {
+func (r *DonationProofRepository) FindByDonationID(ctx context.Context, did uuid.UUID) ([]*models.DonationProof, error) {
 	var (
 		dp []*models.DonationProof
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Where("donation_id = ?", did).
 		Find(&dp).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
@@ -1,6 +1,7 @@
 package donationrepo
 
 import (
+	"context"
 	"fmt"
 	"project-skbackend/internal/controllers/responses"
 	"project-skbackend/internal/models"
@@ -24,6 +25,14 @@ var (
 		created_at,
 		updated_at
 	`
+
+	FILTER_FIELDS = paginationrepo.Fields{
+		"patron_id":  {Column: "patron_id", Type: consttypes.FFT_UUID},
+		"value":      {Column: "value", Type: consttypes.FFT_NUMBER},
+		"status":     paginationrepo.EnumField("status", consttypes.DS_ACCEPTED, consttypes.DS_PENDING, consttypes.DS_REJECTED),
+		"created_at": {Column: "created_at", Type: consttypes.FFT_TIME},
+		"updated_at": {Column: "updated_at", Type: consttypes.FFT_TIME},
+	}
 )
 
 type (
@@ -32,13 +41,13 @@ type (
 	}
 
 	IDonationRepository interface {
-		Create(d models.Donation) (*models.Donation, error)
-		Read() ([]*models.Donation, error)
-		Update(d models.Donation) (*models.Donation, error)
-		Delete(d models.Donation) error
-		FindAll(p utpagination.Pagination) (*utpagination.Pagination, error)
-		GetByID(id uuid.UUID) (*models.Donation, error)
-		FindByPatronID(pid uuid.UUID) ([]*models.Donation, error)
+		Create(ctx context.Context, d models.Donation) (*models.Donation, error)
+		Read(ctx context.Context) ([]*models.Donation, error)
+		Update(ctx context.Context, d models.Donation) (*models.Donation, error)
+		Delete(ctx context.Context, d models.Donation) error
+		FindAll(ctx context.Context, p utpagination.Pagination) (*utpagination.Pagination, error)
+		GetByID(ctx context.Context, id uuid.UUID) (*models.Donation, error)
+		FindByPatronID(ctx context.Context, pid uuid.UUID) ([]*models.Donation, error)
 	}
 )
 
@@ -46,97 +55,97 @@ func NewDonationRepository(db *gorm.DB) *DonationRepository {
 	return &DonationRepository{db: db}
 }
 
-func (r *DonationRepository) omit() *gorm.DB {
-	return r.db.Omit(
+func (r *DonationRepository) omit(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Omit(
 		"",
 	)
 }
 
-func (r *DonationRepository) preload() *gorm.DB {
-	return r.db.
+func (r *DonationRepository) preload(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).
 		Preload(clause.Associations)
 }
 
-func (r *DonationRepository) Create(d models.Donation) (*models.Donation, error) {
+func (r *DonationRepository) Create(ctx context.Context, d models.Donation) (*models.Donation, error) {
 	err := r.
-		omit().
+		omit(ctx).
 		Session(&gorm.Session{FullSaveAssociations: true}).
 		Create(&d).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
-	dnew, err := r.GetByID(d.ID)
+	dnew, err := r.GetByID(ctx, d.ID)
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return dnew, nil
 }
 
-func (r *DonationRepository) Read() ([]*models.Donation, error) {
+func (r *DonationRepository) Read(ctx context.Context) ([]*models.Donation, error) {
 	var (
 		d []*models.Donation
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Find(&d).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return d, nil
 }
 
-func (r *DonationRepository) Update(d models.Donation) (*models.Donation, error) {
+func (r *DonationRepository) Update(ctx context.Context, d models.Donation) (*models.Donation, error) {
 	err := r.
-		omit().
+		omit(ctx).
 		Session(&gorm.Session{FullSaveAssociations: true}).
 		Save(&d).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
-	dnew, err := r.GetByID(d.ID)
+	dnew, err := r.GetByID(ctx, d.ID)
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return dnew, nil
 }
 
-func (r *DonationRepository) Delete(d models.Donation) error {
-	err := r.db.
+func (r *DonationRepository) Delete(ctx context.Context, d models.Donation) error {
+	err := r.db.WithContext(ctx).
 		Delete(&d).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return err
 	}
 
 	return nil
 }
 
-func (r *DonationRepository) FindAll(p utpagination.Pagination) (*utpagination.Pagination, error) {
+func (r *DonationRepository) FindAll(ctx context.Context, p utpagination.Pagination) (*utpagination.Pagination, error) {
 	var (
 		d    []models.Donation
 		dres []responses.Donation
 	)
 
 	result := r.
-		preload().
+		preload(ctx).
 		Model(&d).
 		Select(SELECTED_FIELDS)
 
@@ -162,11 +171,11 @@ func (r *DonationRepository) FindAll(p utpagination.Pagination) (*utpagination.P
 
 	result = result.
 		Group("id").
-		Scopes(paginationrepo.Paginate(&d, &p, result)).
+		Scopes(paginationrepo.Paginate(&d, &p, result, FILTER_FIELDS)).
 		Find(&d)
 
 	if err := result.Error; err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
@@ -177,38 +186,38 @@ func (r *DonationRepository) FindAll(p utpagination.Pagination) (*utpagination.P
 	return &p, nil
 }
 
-func (r *DonationRepository) GetByID(id uuid.UUID) (*models.Donation, error) {
+func (r *DonationRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Donation, error) {
 	var (
 		d *models.Donation
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Where("id = ?", id).
 		First(&d).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return d, err
 }
 
-func (r *DonationRepository) FindByPatronID(pid uuid.UUID) ([]*models.Donation, error) {
+func (r *DonationRepository) FindByPatronID(ctx context.Context, pid uuid.UUID) ([]*models.Donation, error) {
 	var (
 		d []*models.Donation
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Where("patron_id = ?", pid).
 		Find(&d).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
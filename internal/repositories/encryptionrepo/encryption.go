@@ -0,0 +1,123 @@
+package encryptionrepo
+
+import (
+	"context"
+	"fmt"
+	"project-skbackend/internal/models"
+	"project-skbackend/packages/utils/utcrypto"
+	"project-skbackend/packages/utils/utlogger"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type (
+	EncryptionRepository struct {
+		db *gorm.DB
+	}
+
+	IEncryptionRepository interface {
+		ReEncrypt(ctx context.Context, batchsize int) (int, error)
+	}
+)
+
+func NewEncryptionRepository(db *gorm.DB) *EncryptionRepository {
+	return &EncryptionRepository{db: db}
+}
+
+// * re-encrypt every encrypted column that is still in plain text or
+// * encrypted with an old key, returns the number of rows re-encrypted
+func (r *EncryptionRepository) ReEncrypt(ctx context.Context, batchsize int) (int, error) {
+	var (
+		total int
+	)
+
+	reencrypts := []func() (int, error){
+		func() (int, error) {
+			return reencrypt[models.Member](r.db.WithContext(ctx), batchsize, "height", "weight", "bmi", "date_of_birth")
+		},
+		func() (int, error) {
+			return reencrypt[models.MemberIllness](r.db.WithContext(ctx), batchsize, "illness_id")
+		},
+		func() (int, error) {
+			return reencrypt[models.MemberAllergy](r.db.WithContext(ctx), batchsize, "allergy_id")
+		},
+		func() (int, error) {
+			return reencrypt[models.Caregiver](r.db.WithContext(ctx), batchsize, "date_of_birth")
+		},
+		func() (int, error) {
+			return reencrypt[models.Admin](r.db.WithContext(ctx), batchsize, "date_of_birth")
+		},
+		func() (int, error) {
+			return reencrypt[models.MemberHealthHistory](r.db.WithContext(ctx), batchsize, "previous", "current", "height", "weight", "bmi")
+		},
+		func() (int, error) {
+			return reencrypt[models.UserTwoFactor](r.db.WithContext(ctx), batchsize, "secret")
+		},
+		func() (int, error) {
+			return reencrypt[models.Webhook](r.db.WithContext(ctx), batchsize, "secret")
+		},
+	}
+
+	for _, reencrypt := range reencrypts {
+		count, err := reencrypt()
+		if err != nil {
+			utlogger.ErrorContext(ctx, err)
+			return total, err
+		}
+
+		total += count
+	}
+
+	return total, nil
+}
+
+func reencrypt[T any](db *gorm.DB, batchsize int, columns ...string) (int, error) {
+	var (
+		total  int
+		conds  []string
+		args   []any
+		prefix = utcrypto.CurrentPrefix()
+	)
+
+	// * null values are skipped since there is nothing to encrypt
+	for _, column := range columns {
+		conds = append(conds, fmt.Sprintf("%s NOT LIKE ?", column))
+		args = append(args, prefix+"%")
+	}
+
+	for {
+		var (
+			rows []*T
+		)
+
+		err := db.
+			Unscoped().
+			Select(append([]string{"id"}, columns...)).
+			Where(strings.Join(conds, " OR "), args...).
+			Limit(batchsize).
+			Find(&rows).Error
+		if err != nil {
+			return total, err
+		}
+
+		// * saving the row again lets the serializer
+		// * encrypt the columns with the current key
+		for _, row := range rows {
+			err := db.
+				Unscoped().
+				Model(row).
+				Select(columns).
+				UpdateColumns(row).Error
+			if err != nil {
+				return total, err
+			}
+		}
+
+		total += len(rows)
+
+		if len(rows) < batchsize {
+			return total, nil
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package identityrepo
+
+import (
+	"context"
+	"project-skbackend/internal/models"
+	"project-skbackend/packages/utils/utlogger"
+
+	"gorm.io/gorm"
+)
+
+var (
+	SELECTED_FIELDS = `
+		id,
+		user_id,
+		issuer,
+		subject,
+		email,
+		created_at,
+		updated_at
+	`
+)
+
+type (
+	IdentityRepository struct {
+		db *gorm.DB
+	}
+
+	IIdentityRepository interface {
+		Create(ctx context.Context, ui models.UserIdentity) (*models.UserIdentity, error)
+		GetByIssuerSubject(ctx context.Context, issuer string, subject string) (*models.UserIdentity, error)
+	}
+)
+
+func NewIdentityRepository(db *gorm.DB) *IdentityRepository {
+	return &IdentityRepository{db: db}
+}
+
+func (r *IdentityRepository) Create(ctx context.Context, ui models.UserIdentity) (*models.UserIdentity, error) {
+	err := r.db.WithContext(ctx).
+		Create(&ui).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return &ui, nil
+}
+
+func (r *IdentityRepository) GetByIssuerSubject(ctx context.Context, issuer string, subject string) (*models.UserIdentity, error) {
+	var (
+		ui *models.UserIdentity
+	)
+
+	err := r.db.WithContext(ctx).
+		Select(SELECTED_FIELDS).
+		Where("issuer = ? AND subject = ?", issuer, subject).
+		First(&ui).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ui, nil
+}
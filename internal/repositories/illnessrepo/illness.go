@@ -1,6 +1,7 @@
 package illnessrepo
 
 import (
+	"context"
 	"fmt"
 	"project-skbackend/internal/controllers/responses"
 	"project-skbackend/internal/models"
@@ -24,6 +25,13 @@ var (
 		created_at,
 		updated_at
 	`
+
+	FILTER_FIELDS = paginationrepo.Fields{
+		"name":        {Column: "name", Type: consttypes.FFT_STRING},
+		"description": {Column: "description", Type: consttypes.FFT_STRING},
+		"created_at":  {Column: "created_at", Type: consttypes.FFT_TIME},
+		"updated_at":  {Column: "updated_at", Type: consttypes.FFT_TIME},
+	}
 )
 
 type (
@@ -32,12 +40,12 @@ type (
 	}
 
 	IIllnessRepository interface {
-		Create(ill models.Illness) (*models.Illness, error)
-		Read() ([]*models.Illness, error)
-		Update(ill models.Illness) (*models.Illness, error)
-		Delete(ill models.Illness) error
-		FindAll(p utpagination.Pagination) (*utpagination.Pagination, error)
-		GetByID(id uuid.UUID) (*models.Illness, error)
+		Create(ctx context.Context, ill models.Illness) (*models.Illness, error)
+		Read(ctx context.Context) ([]*models.Illness, error)
+		Update(ctx context.Context, ill models.Illness) (*models.Illness, error)
+		Delete(ctx context.Context, ill models.Illness) error
+		FindAll(ctx context.Context, p utpagination.Pagination) (*utpagination.Pagination, error)
+		GetByID(ctx context.Context, id uuid.UUID) (*models.Illness, error)
 	}
 )
 
@@ -45,98 +53,98 @@ func NewIllnessRepository(db *gorm.DB) *IllnessRepository {
 	return &IllnessRepository{db: db}
 }
 
-func (r *IllnessRepository) omit() *gorm.DB {
-	return r.db.
+func (r *IllnessRepository) omit(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).
 		Omit(
 			"",
 		)
 }
 
-func (r *IllnessRepository) preload() *gorm.DB {
-	return r.db.
+func (r *IllnessRepository) preload(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).
 		Preload(clause.Associations)
 }
 
-func (r *IllnessRepository) Create(ill models.Illness) (*models.Illness, error) {
+func (r *IllnessRepository) Create(ctx context.Context, ill models.Illness) (*models.Illness, error) {
 	err := r.
-		omit().
+		omit(ctx).
 		Session(&gorm.Session{FullSaveAssociations: true}).
 		Create(&ill).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
-	illnew, err := r.GetByID(ill.ID)
+	illnew, err := r.GetByID(ctx, ill.ID)
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return illnew, nil
 }
 
-func (r *IllnessRepository) Read() ([]*models.Illness, error) {
+func (r *IllnessRepository) Read(ctx context.Context) ([]*models.Illness, error) {
 	var (
 		ill []*models.Illness
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Find(&ill).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return ill, nil
 }
 
-func (r *IllnessRepository) Update(ill models.Illness) (*models.Illness, error) {
+func (r *IllnessRepository) Update(ctx context.Context, ill models.Illness) (*models.Illness, error) {
 	err := r.
-		omit().
+		omit(ctx).
 		Session(&gorm.Session{FullSaveAssociations: true}).
 		Save(&ill).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
-	illnew, err := r.GetByID(ill.ID)
+	illnew, err := r.GetByID(ctx, ill.ID)
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return illnew, nil
 }
 
-func (r *IllnessRepository) Delete(ill models.Illness) error {
-	err := r.db.
+func (r *IllnessRepository) Delete(ctx context.Context, ill models.Illness) error {
+	err := r.db.WithContext(ctx).
 		Delete(&ill).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return err
 	}
 
 	return nil
 }
 
-func (r *IllnessRepository) FindAll(p utpagination.Pagination) (*utpagination.Pagination, error) {
+func (r *IllnessRepository) FindAll(ctx context.Context, p utpagination.Pagination) (*utpagination.Pagination, error) {
 	var (
 		ill    []models.Illness
 		illres []responses.Illness
 	)
 
 	result := r.
-		preload().
+		preload(ctx).
 		Model(&ill).
 		Select(SELECTED_FIELDS)
 
@@ -144,7 +152,7 @@ func (r *IllnessRepository) FindAll(p utpagination.Pagination) (*utpagination.Pa
 		p.Search = fmt.Sprintf("%%%s%%", p.Search)
 		result = result.
 			Where(
-				r.db.Where(`
+				r.db.WithContext(ctx).Where(`
 					name ILIKE ?
 						OR 
 					description ILIKE ? 
@@ -162,11 +170,11 @@ func (r *IllnessRepository) FindAll(p utpagination.Pagination) (*utpagination.Pa
 
 	result = result.
 		Group("id").
-		Scopes(paginationrepo.Paginate(&ill, &p, result)).
+		Scopes(paginationrepo.Paginate(&ill, &p, result, FILTER_FIELDS)).
 		Find(&ill)
 
 	if err := result.Error; err != nil {
-		utlogger.Error(result.Error)
+		utlogger.ErrorContext(ctx, result.Error)
 		return nil, result.Error
 	}
 
@@ -177,19 +185,19 @@ func (r *IllnessRepository) FindAll(p utpagination.Pagination) (*utpagination.Pa
 	return &p, nil
 }
 
-func (r *IllnessRepository) GetByID(id uuid.UUID) (*models.Illness, error) {
+func (r *IllnessRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Illness, error) {
 	var (
 		ill *models.Illness
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Where(&models.Illness{Model: base.Model{ID: id}}).
 		First(&ill).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
@@ -1,6 +1,7 @@
 package imagerepo
 
 import (
+	"context"
 	"project-skbackend/internal/models"
 	"project-skbackend/internal/models/base"
 	"project-skbackend/packages/utils/utlogger"
@@ -27,11 +28,11 @@ type (
 	}
 
 	IImageRepo interface {
-		Create(i models.Image) (*models.Image, error)
-		Read() ([]*models.Image, error)
-		Update(i models.Image) (*models.Image, error)
-		Delete(i models.Image) error
-		GetByID(id uuid.UUID) (*models.Image, error)
+		Create(ctx context.Context, i models.Image) (*models.Image, error)
+		Read(ctx context.Context) ([]*models.Image, error)
+		Update(ctx context.Context, i models.Image) (*models.Image, error)
+		Delete(ctx context.Context, i models.Image) error
+		GetByID(ctx context.Context, id uuid.UUID) (*models.Image, error)
 	}
 )
 
@@ -39,102 +40,102 @@ func NewImageRepository(db *gorm.DB) *ImageRepository {
 	return &ImageRepository{db: db}
 }
 
-func (r *ImageRepository) omit() *gorm.DB {
-	return r.db.Omit(
+func (r *ImageRepository) omit(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Omit(
 		"",
 	)
 }
 
-func (r *ImageRepository) preload() *gorm.DB {
-	return r.db.
+func (r *ImageRepository) preload(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).
 		Preload(clause.Associations)
 }
 
-func (r *ImageRepository) Create(i models.Image) (*models.Image, error) {
+func (r *ImageRepository) Create(ctx context.Context, i models.Image) (*models.Image, error) {
 	err := r.
-		omit().
+		omit(ctx).
 		Session(&gorm.Session{FullSaveAssociations: true}).
 		Create(&i).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
-	inew, err := r.GetByID(i.ID)
+	inew, err := r.GetByID(ctx, i.ID)
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return inew, nil
 }
 
-func (r *ImageRepository) Read() ([]*models.Image, error) {
+func (r *ImageRepository) Read(ctx context.Context) ([]*models.Image, error) {
 	var (
 		i []*models.Image
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Find(&i).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return i, nil
 }
 
-func (r *ImageRepository) Update(i models.Image) (*models.Image, error) {
+func (r *ImageRepository) Update(ctx context.Context, i models.Image) (*models.Image, error) {
 	err := r.
-		omit().
+		omit(ctx).
 		Session(&gorm.Session{FullSaveAssociations: true}).
 		Save(&i).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
-	inew, err := r.GetByID(i.ID)
+	inew, err := r.GetByID(ctx, i.ID)
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return inew, nil
 }
 
-func (r *ImageRepository) Delete(i models.Image) error {
-	err := r.db.
+func (r *ImageRepository) Delete(ctx context.Context, i models.Image) error {
+	err := r.db.WithContext(ctx).
 		Delete(&i).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return err
 	}
 
 	return nil
 }
 
-func (r *ImageRepository) GetByID(id uuid.UUID) (*models.Image, error) {
+func (r *ImageRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Image, error) {
 	var (
 		i *models.Image
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Where(&models.Image{Model: base.Model{ID: id}}).
 		First(&i).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
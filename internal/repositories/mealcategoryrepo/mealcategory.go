@@ -1,6 +1,7 @@
 package mealcategoryrepo
 
 import (
+	"context"
 	"project-skbackend/internal/controllers/responses"
 	"project-skbackend/internal/models"
 	"project-skbackend/internal/models/base"
@@ -23,6 +24,13 @@ var (
 		created_at,
 		updated_at
 	`
+
+	FILTER_FIELDS = paginationrepo.Fields{
+		"name":       {Column: "name", Type: consttypes.FFT_STRING},
+		"image_id":   {Column: "image_id", Type: consttypes.FFT_UUID},
+		"created_at": {Column: "created_at", Type: consttypes.FFT_TIME},
+		"updated_at": {Column: "updated_at", Type: consttypes.FFT_TIME},
+	}
 )
 
 type (
@@ -31,12 +39,14 @@ type (
 	}
 
 	IMealCategoryRepository interface {
-		Create(m models.MealCategory) (*models.MealCategory, error)
-		Read() ([]*models.MealCategory, error)
-		Update(m models.MealCategory) (*models.MealCategory, error)
-		Delete(m models.MealCategory) error
-		FindAll(p utpagination.Pagination) (*utpagination.Pagination, error)
-		GetByID(id uuid.UUID) (*models.MealCategory, error)
+		Create(ctx context.Context, m models.MealCategory) (*models.MealCategory, error)
+		Read(ctx context.Context) ([]*models.MealCategory, error)
+		Update(ctx context.Context, m models.MealCategory) (*models.MealCategory, error)
+		Delete(ctx context.Context, m models.MealCategory) error
+		FindAll(ctx context.Context, p utpagination.Pagination) (*utpagination.Pagination, error)
+		GetByID(ctx context.Context, id uuid.UUID) (*models.MealCategory, error)
+		GetByName(ctx context.Context, name string) (*models.MealCategory, error)
+		UpdateImage(ctx context.Context, id uuid.UUID, iid uuid.UUID) error
 	}
 )
 
@@ -44,98 +54,116 @@ func NewMealCategoryRepository(db *gorm.DB) *MealCategoryRepository {
 	return &MealCategoryRepository{db: db}
 }
 
-func (r *MealCategoryRepository) omit() *gorm.DB {
-	return r.db.Omit(
+func (r *MealCategoryRepository) omit(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Omit(
 		"",
 	)
 }
 
-func (r *MealCategoryRepository) preload() *gorm.DB {
-	return r.db.
+func (r *MealCategoryRepository) preload(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).
 		Preload(clause.Associations).
 		Preload("Image")
 }
 
-func (r *MealCategoryRepository) Create(mc models.MealCategory) (*models.MealCategory, error) {
+func (r *MealCategoryRepository) Create(ctx context.Context, mc models.MealCategory) (*models.MealCategory, error) {
 	err := r.
-		omit().
+		omit(ctx).
 		Session(&gorm.Session{FullSaveAssociations: true}).
 		Create(&mc).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
-	mcnew, err := r.GetByID(mc.ID)
+	mcnew, err := r.GetByID(ctx, mc.ID)
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return mcnew, nil
 }
 
-func (r *MealCategoryRepository) Read() ([]*models.MealCategory, error) {
+func (r *MealCategoryRepository) Read(ctx context.Context) ([]*models.MealCategory, error) {
 	var (
 		mcs []*models.MealCategory
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Find(&mcs).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return mcs, nil
 }
 
-func (r *MealCategoryRepository) Update(mc models.MealCategory) (*models.MealCategory, error) {
+func (r *MealCategoryRepository) Update(ctx context.Context, mc models.MealCategory) (*models.MealCategory, error) {
 	err := r.
-		omit().
+		omit(ctx).
 		Session(&gorm.Session{FullSaveAssociations: true}).
 		Save(&mc).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
-	mcnew, err := r.GetByID(mc.ID)
+	mcnew, err := r.GetByID(ctx, mc.ID)
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return mcnew, nil
 }
 
-func (r *MealCategoryRepository) Delete(mc models.MealCategory) error {
-	err := r.db.
-		Delete(&mc).Error
+// * the meals of the category are left without a category and the
+// * category is taken off the partners that offered it
+func (r *MealCategoryRepository) Delete(ctx context.Context, mc models.MealCategory) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Model(&models.Meal{}).
+			Where("meal_category_id = ?", mc.ID).
+			Update("meal_category_id", nil).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.
+			Exec(`DELETE FROM partner_meal_category_composites WHERE meal_category_id = ?`, mc.ID).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.
+			Delete(&mc).Error
+	})
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return err
 	}
 
 	return nil
 }
 
-func (r *MealCategoryRepository) FindAll(p utpagination.Pagination) (*utpagination.Pagination, error) {
+func (r *MealCategoryRepository) FindAll(ctx context.Context, p utpagination.Pagination) (*utpagination.Pagination, error) {
 	var (
 		mcs    []models.MealCategory
 		mcsres []responses.MealCategory
 	)
 
 	result := r.
-		preload().
+		preload(ctx).
 		Model(&mcs).
 		Select(SELECTED_FIELDS)
 
@@ -154,11 +182,11 @@ func (r *MealCategoryRepository) FindAll(p utpagination.Pagination) (*utpaginati
 
 	result = result.
 		Group("id").
-		Scopes(paginationrepo.Paginate(&mcs, &p, result)).
+		Scopes(paginationrepo.Paginate(&mcs, &p, result, FILTER_FIELDS)).
 		Find(&mcs)
 
 	if err := result.Error; err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
@@ -169,21 +197,54 @@ func (r *MealCategoryRepository) FindAll(p utpagination.Pagination) (*utpaginati
 	return &p, nil
 }
 
-func (r *MealCategoryRepository) GetByID(id uuid.UUID) (*models.MealCategory, error) {
+func (r *MealCategoryRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.MealCategory, error) {
 	var (
 		mc *models.MealCategory
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Where(&models.MealCategory{Model: base.Model{ID: id}}).
 		First(&mc).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return mc, nil
 }
+
+// * the names are compared case insensitively
+func (r *MealCategoryRepository) GetByName(ctx context.Context, name string) (*models.MealCategory, error) {
+	var (
+		mc *models.MealCategory
+	)
+
+	err := r.
+		preload(ctx).
+		Select(SELECTED_FIELDS).
+		Where("LOWER(name) = LOWER(?)", name).
+		First(&mc).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return mc, nil
+}
+
+func (r *MealCategoryRepository) UpdateImage(ctx context.Context, id uuid.UUID, iid uuid.UUID) error {
+	err := r.db.WithContext(ctx).
+		Model(&models.MealCategory{}).
+		Where("id = ?", id).
+		Update("image_id", iid).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return err
+	}
+
+	return nil
+}
@@ -1,7 +1,7 @@
 package mealrepo
 
 import (
-	"fmt"
+	"context"
 	"project-skbackend/internal/controllers/responses"
 	"project-skbackend/internal/models"
 	"project-skbackend/internal/models/base"
@@ -20,12 +20,22 @@ var (
 	SELECTED_FIELDS = `
 		id,
 		partner_id,
+		meal_category_id,
 		name,
 		status,
 		description,
 		created_at,
 		updated_at
 	`
+
+	FILTER_FIELDS = paginationrepo.Fields{
+		"partner_id":       {Column: "partner_id", Type: consttypes.FFT_UUID},
+		"meal_category_id": {Column: "meal_category_id", Type: consttypes.FFT_UUID},
+		"name":             {Column: "name", Type: consttypes.FFT_STRING},
+		"status":           paginationrepo.EnumField("status", consttypes.MS_ACTIVE, consttypes.MS_INACTIVE, consttypes.MS_OUTOFSTOCK),
+		"created_at":       {Column: "created_at", Type: consttypes.FFT_TIME},
+		"updated_at":       {Column: "updated_at", Type: consttypes.FFT_TIME},
+	}
 )
 
 type (
@@ -34,13 +44,15 @@ type (
 	}
 
 	IMealRepository interface {
-		Create(m models.Meal) (*models.Meal, error)
-		Read() ([]*models.Meal, error)
-		Update(m models.Meal) (*models.Meal, error)
-		Delete(m models.Meal) error
-		FindAll(p utpagination.Pagination) (*utpagination.Pagination, error)
-		GetByID(id uuid.UUID) (*models.Meal, error)
-		ReadByPartnerID(pid uuid.UUID) ([]models.Meal, error)
+		Create(ctx context.Context, m models.Meal) (*models.Meal, error)
+		Read(ctx context.Context) ([]*models.Meal, error)
+		Update(ctx context.Context, m models.Meal) (*models.Meal, error)
+		Delete(ctx context.Context, m models.Meal) error
+		FindAll(ctx context.Context, p utpagination.Pagination) (*utpagination.Pagination, error)
+		GetByID(ctx context.Context, id uuid.UUID) (*models.Meal, error)
+		ReadByPartnerID(ctx context.Context, pid uuid.UUID) ([]models.Meal, error)
+		ReadByIDs(ctx context.Context, ids []uuid.UUID) ([]models.Meal, error)
+		SyncPartnerMealCategories(ctx context.Context, pid uuid.UUID) error
 	}
 )
 
@@ -48,118 +60,118 @@ func NewMealRepository(db *gorm.DB) *MealRepository {
 	return &MealRepository{db: db}
 }
 
-func (r *MealRepository) omit() *gorm.DB {
-	return r.db.Omit(
+func (r *MealRepository) omit(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Omit(
 		"Illnesses.Illness",
 		"Allergies.Allergy",
 		"Partner",
+		"MealCategory",
 	)
 }
 
-func (r *MealRepository) preload() *gorm.DB {
-	return r.db.
+func (r *MealRepository) preload(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).
 		Preload(clause.Associations).
 		Preload("Images.Image").
 		Preload("Illnesses.Illness").
 		Preload("Allergies.Allergy").
+		Preload("MealCategory.Image").
 		Preload("Partner.User.Addresses.AddressDetail").
 		Preload("Partner.User.Image.Image")
 }
 
-func (r *MealRepository) Create(m models.Meal) (*models.Meal, error) {
+func (r *MealRepository) Create(ctx context.Context, m models.Meal) (*models.Meal, error) {
 	err := r.
-		omit().
+		omit(ctx).
 		Session(&gorm.Session{FullSaveAssociations: true}).
 		Create(&m).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
-	mnew, err := r.GetByID(m.ID)
+	mnew, err := r.GetByID(ctx, m.ID)
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return mnew, nil
 }
 
-func (r *MealRepository) Read() ([]*models.Meal, error) {
+func (r *MealRepository) Read(ctx context.Context) ([]*models.Meal, error) {
 	var (
 		m []*models.Meal
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Find(&m).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return m, nil
 }
 
-func (r *MealRepository) Update(m models.Meal) (*models.Meal, error) {
+func (r *MealRepository) Update(ctx context.Context, m models.Meal) (*models.Meal, error) {
 	err := r.
-		omit().
+		omit(ctx).
 		Session(&gorm.Session{FullSaveAssociations: true}).
 		Save(&m).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
-	mnew, err := r.GetByID(m.ID)
+	mnew, err := r.GetByID(ctx, m.ID)
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return mnew, nil
 }
 
-func (r *MealRepository) Delete(m models.Meal) error {
-	err := r.db.
+func (r *MealRepository) Delete(ctx context.Context, m models.Meal) error {
+	err := r.db.WithContext(ctx).
 		Delete(&m).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return err
 	}
 
 	return nil
 }
 
-func (r *MealRepository) FindAll(p utpagination.Pagination) (*utpagination.Pagination, error) {
+func (r *MealRepository) FindAll(ctx context.Context, p utpagination.Pagination) (*utpagination.Pagination, error) {
 	var (
 		m     []models.Meal
 		mlres []responses.Meal
 	)
 
 	result := r.
-		preload().
+		preload(ctx).
 		Debug().
 		Model(&m).
 		Select(SELECTED_FIELDS)
 
+	// * the same match as the meal search, without its ranking
 	if p.Search != "" {
-		p.Search = fmt.Sprintf("%%%s%%", p.Search)
 		result = result.
-			Where(
-				r.db.Where(`
-					name ILIKE ?
-						OR 
-					description ILIKE ? 
-			`, p.Search, p.Search),
-			)
+			Where(`
+				search_vector @@ websearch_to_tsquery('simple', ?)
+					OR
+				? <% name
+			`, p.Search, p.Search)
 	}
 
 	if !p.Filter.CreatedFrom.IsZero() && !p.Filter.CreatedTo.IsZero() {
@@ -177,13 +189,20 @@ func (r *MealRepository) FindAll(p utpagination.Pagination) (*utpagination.Pagin
 			)
 	}
 
+	if len(p.Filter.Meal.CategoryIDs) > 0 {
+		result = result.
+			Where("meal_category_id IN ?",
+				p.Filter.Meal.CategoryIDs,
+			)
+	}
+
 	result = result.
 		Group("id").
-		Scopes(paginationrepo.Paginate(&m, &p, result)).
+		Scopes(paginationrepo.Paginate(&m, &p, result, FILTER_FIELDS)).
 		Find(&m)
 
 	if err := result.Error; err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
@@ -194,40 +213,86 @@ func (r *MealRepository) FindAll(p utpagination.Pagination) (*utpagination.Pagin
 	return &p, nil
 }
 
-func (r *MealRepository) GetByID(id uuid.UUID) (*models.Meal, error) {
+func (r *MealRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Meal, error) {
 	var (
 		m *models.Meal
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Where(&models.Meal{Model: base.Model{ID: id}}).
 		First(&m).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return m, nil
 }
 
-func (r *MealRepository) ReadByPartnerID(pid uuid.UUID) ([]models.Meal, error) {
+func (r *MealRepository) ReadByPartnerID(ctx context.Context, pid uuid.UUID) ([]models.Meal, error) {
 	var (
 		m []models.Meal
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Where(&models.Meal{PartnerID: pid}).
 		Find(&m).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func (r *MealRepository) ReadByIDs(ctx context.Context, ids []uuid.UUID) ([]models.Meal, error) {
+	var (
+		m []models.Meal
+	)
+
+	err := r.
+		preload(ctx).
+		Select(SELECTED_FIELDS).
+		Where("id IN ?", ids).
+		Find(&m).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return m, nil
 }
+
+// * the categories of a partner are the categories of its meals,
+// * they are replaced every time the meals of the partner change
+func (r *MealRepository) SyncPartnerMealCategories(ctx context.Context, pid uuid.UUID) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Exec(`DELETE FROM partner_meal_category_composites WHERE partner_id = ?`, pid).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.
+			Exec(`
+				INSERT INTO partner_meal_category_composites (partner_id, meal_category_id)
+				SELECT DISTINCT partner_id, meal_category_id
+				FROM meals
+				WHERE partner_id = ? AND meal_category_id IS NOT NULL AND deleted_at IS NULL
+			`, pid).Error
+	})
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return err
+	}
+
+	return nil
+}
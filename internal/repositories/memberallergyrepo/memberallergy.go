@@ -1,6 +1,7 @@
 package memberallergyrepo
 
 import (
+	"context"
 	"project-skbackend/internal/models"
 	"project-skbackend/packages/utils/utlogger"
 
@@ -25,11 +26,10 @@ type (
 	}
 
 	IMemberAllergyRepository interface {
-		Create(mall models.MemberAllergy) (*models.MemberAllergy, error)
-		Delete(mall models.MemberAllergy) error
-		GetByID(id uuid.UUID) (*models.MemberAllergy, error)
-		GetByMemberIDAndAllergyID(mid uuid.UUID, aid uuid.UUID) (*models.MemberAllergy, error)
-		GetByMemberID(mid uuid.UUID) ([]*models.MemberAllergy, error)
+		Create(ctx context.Context, mall models.MemberAllergy) (*models.MemberAllergy, error)
+		Delete(ctx context.Context, mall models.MemberAllergy) error
+		GetByID(ctx context.Context, id uuid.UUID) (*models.MemberAllergy, error)
+		GetByMemberID(ctx context.Context, mid uuid.UUID) ([]*models.MemberAllergy, error)
 	}
 )
 
@@ -37,102 +37,83 @@ func NewMemberAllergyRepository(db *gorm.DB) *MemberAllergyRepository {
 	return &MemberAllergyRepository{db: db}
 }
 
-func (r *MemberAllergyRepository) omit() *gorm.DB {
-	return r.db.
+func (r *MemberAllergyRepository) omit(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).
 		Omit(
 			"Allergy",
 		)
 }
 
-func (r *MemberAllergyRepository) preload() *gorm.DB {
-	return r.db.
+func (r *MemberAllergyRepository) preload(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).
 		Preload(clause.Associations)
 }
 
-func (r *MemberAllergyRepository) Create(mall models.MemberAllergy) (*models.MemberAllergy, error) {
+func (r *MemberAllergyRepository) Create(ctx context.Context, mall models.MemberAllergy) (*models.MemberAllergy, error) {
 	err := r.
-		omit().
+		omit(ctx).
 		Session(&gorm.Session{FullSaveAssociations: true}).
 		Create(&mall).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
-	mallnew, err := r.GetByID(mall.ID)
+	mallnew, err := r.GetByID(ctx, mall.ID)
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return mallnew, nil
 }
 
-func (r *MemberAllergyRepository) Delete(mall models.MemberAllergy) error {
+func (r *MemberAllergyRepository) Delete(ctx context.Context, mall models.MemberAllergy) error {
 	err := r.
-		omit().
+		omit(ctx).
 		Delete(&mall).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return err
 	}
 
 	return nil
 }
 
-func (r *MemberAllergyRepository) GetByID(id uuid.UUID) (*models.MemberAllergy, error) {
+func (r *MemberAllergyRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.MemberAllergy, error) {
 	var (
 		mall *models.MemberAllergy
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Where("id = ?", id).
 		First(&mall).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return mall, nil
 }
 
-func (r *MemberAllergyRepository) GetByMemberIDAndAllergyID(mid uuid.UUID, iid uuid.UUID) (*models.MemberAllergy, error) {
-	var (
-		mall *models.MemberAllergy
-	)
-
-	err := r.
-		preload().
-		Select(SELECTED_FIELDS).
-		Where("member_id = ? AND allergy_id = ?", mid, iid).
-		First(&mall).Error
-
-	if err != nil {
-		utlogger.Error(err)
-		return nil, err
-	}
-
-	return nil, nil
-}
-
-func (r *MemberAllergyRepository) GetByMemberID(mid uuid.UUID) ([]*models.MemberAllergy, error) {
+func (r *MemberAllergyRepository) GetByMemberID(ctx context.Context, mid uuid.UUID) ([]*models.MemberAllergy, error) {
 	var (
 		malls []*models.MemberAllergy
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Where("member_id = ?", mid).
 		Find(&malls).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
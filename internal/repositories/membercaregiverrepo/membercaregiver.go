@@ -0,0 +1,197 @@
+package membercaregiverrepo
+
+import (
+	"context"
+	"project-skbackend/internal/models"
+	"project-skbackend/packages/utils/utlogger"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	SELECTED_FIELDS = `
+		id,
+		member_id,
+		caregiver_id,
+		can_order,
+		can_edit_health,
+		created_at,
+		updated_at
+	`
+)
+
+type (
+	MemberCaregiverRepository struct {
+		db *gorm.DB
+	}
+
+	IMemberCaregiverRepository interface {
+		Create(ctx context.Context, mc models.MemberCaregiver) (*models.MemberCaregiver, error)
+		Update(ctx context.Context, mc models.MemberCaregiver) (*models.MemberCaregiver, error)
+		Delete(ctx context.Context, mc models.MemberCaregiver) error
+		GetByID(ctx context.Context, id uuid.UUID) (*models.MemberCaregiver, error)
+		GetByMemberIDAndCaregiverID(ctx context.Context, mid uuid.UUID, cgid uuid.UUID) (*models.MemberCaregiver, error)
+		FindByMemberID(ctx context.Context, mid uuid.UUID) ([]*models.MemberCaregiver, error)
+		FindByCaregiverID(ctx context.Context, cgid uuid.UUID) ([]*models.MemberCaregiver, error)
+	}
+)
+
+func NewMemberCaregiverRepository(db *gorm.DB) *MemberCaregiverRepository {
+	return &MemberCaregiverRepository{db: db}
+}
+
+func (r *MemberCaregiverRepository) omit(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).
+		Omit(
+			"Member",
+			"Caregiver",
+		)
+}
+
+func (r *MemberCaregiverRepository) preload(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).
+		Preload(clause.Associations).
+		Preload("Caregiver.User.Image.Image").
+		Preload("Caregiver.User.Addresses.AddressDetail").
+		Preload("Member.User.Image.Image").
+		Preload("Member.User.Addresses.AddressDetail").
+		Preload("Member.Organization").
+		Preload("Member.Allergies").
+		Preload("Member.Illnesses")
+}
+
+func (r *MemberCaregiverRepository) Create(ctx context.Context, mc models.MemberCaregiver) (*models.MemberCaregiver, error) {
+	err := r.
+		omit(ctx).
+		Create(&mc).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	mcnew, err := r.GetByID(ctx, mc.ID)
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return mcnew, nil
+}
+
+func (r *MemberCaregiverRepository) Update(ctx context.Context, mc models.MemberCaregiver) (*models.MemberCaregiver, error) {
+	err := r.
+		omit(ctx).
+		Save(&mc).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	mcnew, err := r.GetByID(ctx, mc.ID)
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return mcnew, nil
+}
+
+func (r *MemberCaregiverRepository) Delete(ctx context.Context, mc models.MemberCaregiver) error {
+	// * hard delete the link, so the same caregiver
+	// * can be invited to the member again later on
+	err := r.
+		omit(ctx).
+		Unscoped().
+		Delete(&mc).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *MemberCaregiverRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.MemberCaregiver, error) {
+	var (
+		mc *models.MemberCaregiver
+	)
+
+	err := r.
+		preload(ctx).
+		Select(SELECTED_FIELDS).
+		Where("id = ?", id).
+		First(&mc).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return mc, nil
+}
+
+func (r *MemberCaregiverRepository) GetByMemberIDAndCaregiverID(ctx context.Context, mid uuid.UUID, cgid uuid.UUID) (*models.MemberCaregiver, error) {
+	var (
+		mc *models.MemberCaregiver
+	)
+
+	err := r.
+		preload(ctx).
+		Select(SELECTED_FIELDS).
+		Where("member_id = ? AND caregiver_id = ?", mid, cgid).
+		First(&mc).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return mc, nil
+}
+
+func (r *MemberCaregiverRepository) FindByMemberID(ctx context.Context, mid uuid.UUID) ([]*models.MemberCaregiver, error) {
+	var (
+		mcs []*models.MemberCaregiver
+	)
+
+	err := r.
+		preload(ctx).
+		Select(SELECTED_FIELDS).
+		Where("member_id = ?", mid).
+		Order("created_at").
+		Find(&mcs).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return mcs, nil
+}
+
+func (r *MemberCaregiverRepository) FindByCaregiverID(ctx context.Context, cgid uuid.UUID) ([]*models.MemberCaregiver, error) {
+	var (
+		mcs []*models.MemberCaregiver
+	)
+
+	err := r.
+		preload(ctx).
+		Select(SELECTED_FIELDS).
+		Where("caregiver_id = ?", cgid).
+		Order("created_at").
+		Find(&mcs).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return mcs, nil
+}
@@ -0,0 +1,210 @@
+package memberhealthhistoryrepo
+
+import (
+	"context"
+	"project-skbackend/internal/controllers/responses"
+	"project-skbackend/internal/models"
+	"project-skbackend/internal/repositories/paginationrepo"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/utils/utlogger"
+	"project-skbackend/packages/utils/utpagination"
+
+	"github.com/google/uuid"
+	"github.com/jinzhu/copier"
+	"gorm.io/gorm"
+)
+
+var (
+	SELECTED_FIELDS = `
+		id,
+		member_id,
+		version,
+		changed_by_id,
+		changed_by_role,
+		previous,
+		current,
+		height,
+		weight,
+		bmi,
+		created_at,
+		updated_at
+	`
+
+	FILTER_FIELDS = paginationrepo.Fields{
+		"member_id":       {Column: "member_id", Type: consttypes.FFT_UUID},
+		"version":         {Column: "version", Type: consttypes.FFT_NUMBER},
+		"changed_by_id":   {Column: "changed_by_id", Type: consttypes.FFT_UUID},
+		"changed_by_role": {Column: "changed_by_role", Type: consttypes.FFT_STRING},
+		"created_at":      {Column: "created_at", Type: consttypes.FFT_TIME},
+	}
+)
+
+type (
+	MemberHealthHistoryRepository struct {
+		db *gorm.DB
+	}
+
+	IMemberHealthHistoryRepository interface {
+		Create(ctx context.Context, mhh models.MemberHealthHistory) (*models.MemberHealthHistory, error)
+		GetByID(ctx context.Context, id uuid.UUID) (*models.MemberHealthHistory, error)
+		GetLatestByMemberID(ctx context.Context, mid uuid.UUID) (*models.MemberHealthHistory, error)
+		FindByMemberID(ctx context.Context, mid uuid.UUID, p utpagination.Pagination) (*utpagination.Pagination, error)
+		FindTrendByMemberID(ctx context.Context, mid uuid.UUID, f utpagination.Filter) ([]*models.MemberHealthHistory, error)
+		FindAllByMemberID(ctx context.Context, mid uuid.UUID) ([]*models.MemberHealthHistory, error)
+	}
+)
+
+func NewMemberHealthHistoryRepository(db *gorm.DB) *MemberHealthHistoryRepository {
+	return &MemberHealthHistoryRepository{db: db}
+}
+
+func (r *MemberHealthHistoryRepository) filter(result *gorm.DB, f utpagination.Filter) *gorm.DB {
+	if !f.CreatedFrom.IsZero() {
+		result = result.
+			Where("date(created_at) >= ?", f.CreatedFrom.Format(consttypes.DATEFORMAT))
+	}
+
+	if !f.CreatedTo.IsZero() {
+		result = result.
+			Where("date(created_at) <= ?", f.CreatedTo.Format(consttypes.DATEFORMAT))
+	}
+
+	return result
+}
+
+func (r *MemberHealthHistoryRepository) Create(ctx context.Context, mhh models.MemberHealthHistory) (*models.MemberHealthHistory, error) {
+	err := r.db.WithContext(ctx).
+		Create(&mhh).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	mhhnew, err := r.GetByID(ctx, mhh.ID)
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return mhhnew, nil
+}
+
+func (r *MemberHealthHistoryRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.MemberHealthHistory, error) {
+	var (
+		mhh *models.MemberHealthHistory
+	)
+
+	err := r.db.WithContext(ctx).
+		Select(SELECTED_FIELDS).
+		Where("id = ?", id).
+		First(&mhh).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return mhh, nil
+}
+
+func (r *MemberHealthHistoryRepository) GetLatestByMemberID(ctx context.Context, mid uuid.UUID) (*models.MemberHealthHistory, error) {
+	var (
+		mhh *models.MemberHealthHistory
+	)
+
+	err := r.db.WithContext(ctx).
+		Select(SELECTED_FIELDS).
+		Where("member_id = ?", mid).
+		Order("version DESC").
+		First(&mhh).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return mhh, nil
+}
+
+func (r *MemberHealthHistoryRepository) FindByMemberID(ctx context.Context, mid uuid.UUID, p utpagination.Pagination) (*utpagination.Pagination, error) {
+	var (
+		mhh    []models.MemberHealthHistory
+		mhhres []responses.MemberHealthHistory
+	)
+
+	result := r.db.WithContext(ctx).
+		Model(&mhh).
+		Select(SELECTED_FIELDS).
+		Where("member_id = ?", mid)
+
+	result = r.filter(result, p.Filter)
+
+	result = result.
+		Scopes(paginationrepo.PaginateKeyset(&mhh, &p, result, FILTER_FIELDS)).
+		Find(&mhh)
+
+	if err := result.Error; err != nil {
+		utlogger.ErrorContext(ctx, result.Error)
+		return nil, result.Error
+	}
+
+	if err := paginationrepo.NextCursor(&mhh, &p, result, FILTER_FIELDS); err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	// * copy the data from model to response
+	copier.CopyWithOption(&mhhres, &mhh, copier.Option{IgnoreEmpty: true, DeepCopy: true})
+
+	p.Data = mhhres
+	return &p, nil
+}
+
+func (r *MemberHealthHistoryRepository) FindTrendByMemberID(ctx context.Context, mid uuid.UUID, f utpagination.Filter) ([]*models.MemberHealthHistory, error) {
+	var (
+		mhh []*models.MemberHealthHistory
+	)
+
+	result := r.db.WithContext(ctx).
+		Select(`
+			id,
+			member_id,
+			version,
+			height,
+			weight,
+			bmi,
+			created_at
+		`).
+		Where("member_id = ?", mid)
+
+	err := r.filter(result, f).
+		Order("version ASC").
+		Find(&mhh).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return mhh, nil
+}
+
+func (r *MemberHealthHistoryRepository) FindAllByMemberID(ctx context.Context, mid uuid.UUID) ([]*models.MemberHealthHistory, error) {
+	var (
+		mhh []*models.MemberHealthHistory
+	)
+
+	err := r.db.WithContext(ctx).
+		Select(SELECTED_FIELDS).
+		Where("member_id = ?", mid).
+		Order("version ASC").
+		Find(&mhh).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return mhh, nil
+}
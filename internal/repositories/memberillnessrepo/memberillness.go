@@ -1,6 +1,7 @@
 package memberillnessrepo
 
 import (
+	"context"
 	"project-skbackend/internal/models"
 	"project-skbackend/packages/utils/utlogger"
 
@@ -25,11 +26,10 @@ type (
 	}
 
 	IMemberIllnessRepository interface {
-		Create(mill models.MemberIllness) (*models.MemberIllness, error)
-		Delete(mill models.MemberIllness) error
-		GetByID(id uuid.UUID) (*models.MemberIllness, error)
-		GetByMemberIDAndIllnessID(mid uuid.UUID, iid uuid.UUID) (*models.MemberIllness, error)
-		GetByMemberID(mid uuid.UUID) ([]*models.MemberIllness, error)
+		Create(ctx context.Context, mill models.MemberIllness) (*models.MemberIllness, error)
+		Delete(ctx context.Context, mill models.MemberIllness) error
+		GetByID(ctx context.Context, id uuid.UUID) (*models.MemberIllness, error)
+		GetByMemberID(ctx context.Context, mid uuid.UUID) ([]*models.MemberIllness, error)
 	}
 )
 
@@ -37,102 +37,83 @@ func NewMemberIllnessRepository(db *gorm.DB) *MemberIllnessRepository {
 	return &MemberIllnessRepository{db: db}
 }
 
-func (r *MemberIllnessRepository) omit() *gorm.DB {
-	return r.db.
+func (r *MemberIllnessRepository) omit(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).
 		Omit(
 			"Illness",
 		)
 }
 
-func (r *MemberIllnessRepository) preload() *gorm.DB {
-	return r.db.
+func (r *MemberIllnessRepository) preload(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).
 		Preload(clause.Associations)
 }
 
-func (r *MemberIllnessRepository) Create(mill models.MemberIllness) (*models.MemberIllness, error) {
+func (r *MemberIllnessRepository) Create(ctx context.Context, mill models.MemberIllness) (*models.MemberIllness, error) {
 	err := r.
-		omit().
+		omit(ctx).
 		Session(&gorm.Session{FullSaveAssociations: true}).
 		Create(&mill).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
-	millnew, err := r.GetByID(mill.ID)
+	millnew, err := r.GetByID(ctx, mill.ID)
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return millnew, nil
 }
 
-func (r *MemberIllnessRepository) Delete(mill models.MemberIllness) error {
+func (r *MemberIllnessRepository) Delete(ctx context.Context, mill models.MemberIllness) error {
 	err := r.
-		omit().
+		omit(ctx).
 		Delete(&mill).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return err
 	}
 
 	return nil
 }
-func (r *MemberIllnessRepository) GetByID(id uuid.UUID) (*models.MemberIllness, error) {
+func (r *MemberIllnessRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.MemberIllness, error) {
 	var (
 		mill *models.MemberIllness
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Where("id = ?", id).
 		First(&mill).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return mill, nil
 }
 
-func (r *MemberIllnessRepository) GetByMemberIDAndIllnessID(mid uuid.UUID, iid uuid.UUID) (*models.MemberIllness, error) {
-	var (
-		mill *models.MemberIllness
-	)
-
-	err := r.
-		preload().
-		Select(SELECTED_FIELDS).
-		Where("member_id = ? AND illness_id = ?", mid, iid).
-		First(&mill).Error
-
-	if err != nil {
-		utlogger.Error(err)
-		return nil, err
-	}
-
-	return mill, nil
-}
-
-func (r *MemberIllnessRepository) GetByMemberID(mid uuid.UUID) ([]*models.MemberIllness, error) {
+func (r *MemberIllnessRepository) GetByMemberID(ctx context.Context, mid uuid.UUID) ([]*models.MemberIllness, error) {
 	var (
 		mills []*models.MemberIllness
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Where("member_id = ?", mid).
 		Find(&mills).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
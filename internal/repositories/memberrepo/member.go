@@ -1,6 +1,7 @@
 package memberrepo
 
 import (
+	"context"
 	"fmt"
 	"project-skbackend/internal/controllers/responses"
 	"project-skbackend/internal/models"
@@ -20,7 +21,6 @@ var (
 	SELECTED_FIELDS = `
 		id,
 		user_id,
-		caregiver_id,
 		organization_id,
 		height,
 		weight,
@@ -32,6 +32,16 @@ var (
 		created_at,
 		updated_at
 	`
+
+	FILTER_FIELDS = paginationrepo.Fields{
+		"user_id":         {Column: "user_id", Type: consttypes.FFT_UUID},
+		"organization_id": {Column: "organization_id", Type: consttypes.FFT_UUID},
+		"first_name":      {Column: "first_name", Type: consttypes.FFT_STRING},
+		"last_name":       {Column: "last_name", Type: consttypes.FFT_STRING},
+		"gender":          paginationrepo.EnumField("gender", consttypes.G_MALE, consttypes.G_FEMALE, consttypes.G_OTHER),
+		"created_at":      {Column: "created_at", Type: consttypes.FFT_TIME},
+		"updated_at":      {Column: "updated_at", Type: consttypes.FFT_TIME},
+	}
 )
 
 type (
@@ -40,15 +50,14 @@ type (
 	}
 
 	IMemberRepository interface {
-		Create(m models.Member) (*models.Member, error)
-		Read() ([]*models.Member, error)
-		Update(m models.Member) (*models.Member, error)
-		Delete(m models.Member) error
-		FindAll(p utpagination.Pagination) (*utpagination.Pagination, error)
-		GetByID(id uuid.UUID) (*models.Member, error)
-		GetByEmail(email string) (*models.Member, error)
-		GetByUserID(uid uuid.UUID) (*models.Member, error)
-		GetByCaregiverID(cgid uuid.UUID) (*models.Member, error)
+		Create(ctx context.Context, m models.Member) (*models.Member, error)
+		Read(ctx context.Context) ([]*models.Member, error)
+		Update(ctx context.Context, m models.Member) (*models.Member, error)
+		Delete(ctx context.Context, m models.Member) error
+		FindAll(ctx context.Context, p utpagination.Pagination) (*utpagination.Pagination, error)
+		GetByID(ctx context.Context, id uuid.UUID) (*models.Member, error)
+		GetByEmail(ctx context.Context, email string) (*models.Member, error)
+		GetByUserID(ctx context.Context, uid uuid.UUID) (*models.Member, error)
 	}
 )
 
@@ -56,107 +65,105 @@ func NewMemberRepository(db *gorm.DB) *MemberRepository {
 	return &MemberRepository{db: db}
 }
 
-func (r *MemberRepository) omit() *gorm.DB {
-	return r.db.
+func (r *MemberRepository) omit(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).
 		Omit(
-			"Illnesses.Illness",
-			"Allergies.Allergy",
 			"Organization",
 		)
 }
 
-func (r *MemberRepository) preload() *gorm.DB {
-	return r.db.
+func (r *MemberRepository) preload(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).
 		Preload(clause.Associations).
 		Preload("User.Image.Image").
 		Preload("User.Addresses.AddressDetail").
-		Preload("Caregiver.User.Image.Image").
-		Preload("Caregiver.User.Addresses.AddressDetail").
+		Preload("Caregivers.Caregiver.User.Image.Image").
+		Preload("Caregivers.Caregiver.User.Addresses.AddressDetail").
 		Preload("Organization").
-		Preload("Allergies.Allergy").
-		Preload("Illnesses.Illness")
+		Preload("Allergies").
+		Preload("Illnesses")
 }
 
-func (r *MemberRepository) Create(m models.Member) (*models.Member, error) {
+func (r *MemberRepository) Create(ctx context.Context, m models.Member) (*models.Member, error) {
 	err := r.
-		omit().
+		omit(ctx).
 		Session(&gorm.Session{FullSaveAssociations: true}).
 		Create(&m).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
-	mnew, err := r.GetByEmail(m.User.Email)
+	mnew, err := r.GetByEmail(ctx, m.User.Email)
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return mnew, err
 }
 
-func (r *MemberRepository) Read() ([]*models.Member, error) {
+func (r *MemberRepository) Read(ctx context.Context) ([]*models.Member, error) {
 	var (
 		m []*models.Member
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Find(&m).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return m, nil
 }
 
-func (r *MemberRepository) Update(m models.Member) (*models.Member, error) {
+func (r *MemberRepository) Update(ctx context.Context, m models.Member) (*models.Member, error) {
 	err := r.
-		omit().
+		omit(ctx).
 		Session(&gorm.Session{FullSaveAssociations: true}).
 		Save(&m).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
-	mnew, err := r.GetByEmail(m.User.Email)
+	mnew, err := r.GetByEmail(ctx, m.User.Email)
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return mnew, nil
 }
 
-func (r *MemberRepository) Delete(m models.Member) error {
-	err := r.db.
+func (r *MemberRepository) Delete(ctx context.Context, m models.Member) error {
+	err := r.db.WithContext(ctx).
 		Delete(&m).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return err
 	}
 
 	return nil
 }
 
-func (r *MemberRepository) FindAll(p utpagination.Pagination) (*utpagination.Pagination, error) {
+func (r *MemberRepository) FindAll(ctx context.Context, p utpagination.Pagination) (*utpagination.Pagination, error) {
 	var (
 		m    []models.Member
 		mres []responses.Member
 	)
 
 	result := r.
-		preload().
+		preload(ctx).
 		Model(&m).
 		Select(SELECTED_FIELDS)
 
@@ -164,7 +171,7 @@ func (r *MemberRepository) FindAll(p utpagination.Pagination) (*utpagination.Pag
 		p.Search = fmt.Sprintf("%%%s%%", p.Search)
 		result = result.
 			Where(
-				r.db.Where(`
+				r.db.WithContext(ctx).Where(`
 					first_name ILIKE ?
 						OR 
 					last_name ILIKE ? 
@@ -182,11 +189,11 @@ func (r *MemberRepository) FindAll(p utpagination.Pagination) (*utpagination.Pag
 
 	result = result.
 		Group("id").
-		Scopes(paginationrepo.Paginate(&m, &p, result)).
+		Scopes(paginationrepo.Paginate(&m, &p, result, FILTER_FIELDS)).
 		Find(&m)
 
 	if err := result.Error; err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
@@ -197,32 +204,32 @@ func (r *MemberRepository) FindAll(p utpagination.Pagination) (*utpagination.Pag
 	return &p, nil
 }
 
-func (r *MemberRepository) GetByID(id uuid.UUID) (*models.Member, error) {
+func (r *MemberRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Member, error) {
 	var (
 		m *models.Member
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Where(&models.Member{Model: base.Model{ID: id}}).
 		First(&m).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return m, nil
 }
 
-func (r *MemberRepository) GetByEmail(email string) (*models.Member, error) {
+func (r *MemberRepository) GetByEmail(ctx context.Context, email string) (*models.Member, error) {
 	var (
 		m *models.Member
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Where(`
 			members.user_id IN (
@@ -240,45 +247,26 @@ func (r *MemberRepository) GetByEmail(email string) (*models.Member, error) {
 		First(&m).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return m, nil
 }
 
-func (r *MemberRepository) GetByUserID(uid uuid.UUID) (*models.Member, error) {
+func (r *MemberRepository) GetByUserID(ctx context.Context, uid uuid.UUID) (*models.Member, error) {
 	var (
 		m *models.Member
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Where(&models.Member{UserID: uid}, uid).
 		First(&m).Error
 
 	if err != nil {
-		utlogger.Error(err)
-		return nil, err
-	}
-
-	return m, nil
-}
-
-func (r *MemberRepository) GetByCaregiverID(cgid uuid.UUID) (*models.Member, error) {
-	var (
-		m *models.Member
-	)
-
-	err := r.
-		preload().
-		Select(SELECTED_FIELDS).
-		Where(&models.Member{CaregiverID: &cgid}).
-		First(&m).Error
-
-	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
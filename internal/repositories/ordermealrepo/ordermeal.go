@@ -1,6 +1,7 @@
 package ordermealrepo
 
 import (
+	"context"
 	"fmt"
 	"project-skbackend/configs"
 	"project-skbackend/internal/controllers/responses"
@@ -26,6 +27,15 @@ var (
 		created_at,
 		updated_at
 	`
+
+	FILTER_FIELDS = paginationrepo.Fields{
+		"order_id":   {Column: "order_id", Type: consttypes.FFT_UUID},
+		"meal_id":    {Column: "meal_id", Type: consttypes.FFT_UUID},
+		"partner_id": {Column: "partner_id", Type: consttypes.FFT_UUID},
+		"quantity":   {Column: "quantity", Type: consttypes.FFT_NUMBER},
+		"created_at": {Column: "created_at", Type: consttypes.FFT_TIME},
+		"updated_at": {Column: "updated_at", Type: consttypes.FFT_TIME},
+	}
 )
 
 type (
@@ -35,7 +45,7 @@ type (
 	}
 
 	IOrderMealRepository interface {
-		FindAll(p utpagination.Pagination) (*utpagination.Pagination, error)
+		FindAll(ctx context.Context, p utpagination.Pagination) (*utpagination.Pagination, error)
 	}
 )
 
@@ -49,15 +59,15 @@ func NewOrderMealRepository(
 	}
 }
 
-func (r *OrderMealRepository) omit() *gorm.DB {
-	return r.db.Omit(
+func (r *OrderMealRepository) omit(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Omit(
 		"Meal",
 		"Member",
 	)
 }
 
-func (r *OrderMealRepository) preload() *gorm.DB {
-	return r.db.
+func (r *OrderMealRepository) preload(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).
 		Preload(clause.Associations).
 		Preload("Meal.Images.Image").
 		Preload("Meal.Illnesses.Illness").
@@ -68,14 +78,14 @@ func (r *OrderMealRepository) preload() *gorm.DB {
 		Preload("Partner.User.Addresses.AddressDetail")
 }
 
-func (r *OrderMealRepository) FindAll(p utpagination.Pagination) (*utpagination.Pagination, error) {
+func (r *OrderMealRepository) FindAll(ctx context.Context, p utpagination.Pagination) (*utpagination.Pagination, error) {
 	var (
 		oms      []models.OrderMeal
 		omresses []responses.OrderMeal
 	)
 
 	result := r.
-		preload().
+		preload(ctx).
 		Debug().
 		Model(&oms).
 		Select(SELECTED_FIELDS)
@@ -102,11 +112,11 @@ func (r *OrderMealRepository) FindAll(p utpagination.Pagination) (*utpagination.
 
 	result = result.
 		Group("id").
-		Scopes(paginationrepo.Paginate(&oms, &p, result)).
+		Scopes(paginationrepo.Paginate(&oms, &p, result, FILTER_FIELDS)).
 		Find(&oms)
 
 	if err := result.Error; err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
@@ -1,6 +1,7 @@
 package orderrepo
 
 import (
+	"context"
 	"fmt"
 	"project-skbackend/configs"
 	"project-skbackend/internal/controllers/responses"
@@ -26,6 +27,14 @@ var (
 		partner_id,
 		status
 	`
+
+	FILTER_FIELDS = paginationrepo.Fields{
+		"member_id":  {Column: "member_id", Type: consttypes.FFT_UUID},
+		"partner_id": {Column: "partner_id", Type: consttypes.FFT_UUID},
+		"status":     paginationrepo.EnumField("status", consttypes.OrderStatuses()...),
+		"created_at": {Column: "created_at", Type: consttypes.FFT_TIME},
+		"updated_at": {Column: "updated_at", Type: consttypes.FFT_TIME},
+	}
 )
 
 type (
@@ -40,19 +49,19 @@ type (
 	}
 
 	IOrderRepository interface {
-		Create(o models.Order) (*models.Order, error)
-		Read() ([]*models.Order, error)
-		Update(o models.Order) (*models.Order, error)
-		Delete(o models.Order) error
-		FindAll(p utpagination.Pagination) (*utpagination.Pagination, error)
-		GetByID(id uuid.UUID) (*models.Order, error)
-		FindByMemberID(id uuid.UUID) ([]*models.Order, error)
-		FindByPartnerID(id uuid.UUID) ([]*models.Order, error)
-		GetByMealID(id uuid.UUID) ([]*models.Order, error)
-		GetMemberDailyOrder(id uuid.UUID) (int, error)
+		Create(ctx context.Context, o models.Order) (*models.Order, error)
+		Read(ctx context.Context) ([]*models.Order, error)
+		Update(ctx context.Context, o models.Order) (*models.Order, error)
+		Delete(ctx context.Context, o models.Order) error
+		FindAll(ctx context.Context, p utpagination.Pagination) (*utpagination.Pagination, error)
+		GetByID(ctx context.Context, id uuid.UUID) (*models.Order, error)
+		FindByMemberID(ctx context.Context, id uuid.UUID) ([]*models.Order, error)
+		FindByPartnerID(ctx context.Context, id uuid.UUID) ([]*models.Order, error)
+		GetByMealID(ctx context.Context, id uuid.UUID) ([]*models.Order, error)
+		GetMemberDailyOrder(ctx context.Context, id uuid.UUID) (int, error)
 
 		// * this is used by cron service for automation
-		UpdateAutomaticallyStatus(status consttypes.OrderStatus, bufferminutes int, trigger []consttypes.OrderStatus) error
+		UpdateAutomaticallyStatus(ctx context.Context, status consttypes.OrderStatus, bufferminutes int, trigger []consttypes.OrderStatus) ([]uuid.UUID, error)
 	}
 )
 
@@ -71,8 +80,8 @@ func NewOrderRepository(
 	}
 }
 
-func (r *OrderRepository) omit() *gorm.DB {
-	return r.db.Omit(
+func (r *OrderRepository) omit(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Omit(
 		"Member",
 		"Meals.Meal",
 		"Meals.Partner",
@@ -80,18 +89,19 @@ func (r *OrderRepository) omit() *gorm.DB {
 	)
 }
 
-func (r *OrderRepository) preload() *gorm.DB {
-	return r.db.
+func (r *OrderRepository) preload(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).
 		Preload(clause.Associations).
 		Preload("Member.User.Image.Image").
 		Preload("Member.User.Addresses.AddressDetail").
-		Preload("Member.Caregiver.User.Image.Image").
-		Preload("Member.Caregiver.User.Addresses.AddressDetail").
+		Preload("Member.Caregivers.Caregiver.User.Image.Image").
+		Preload("Member.Caregivers.Caregiver.User.Addresses.AddressDetail").
 		Preload("Member.Organization").
-		Preload("Member.Allergies.Allergy").
-		Preload("Member.Illnesses.Illness").
+		Preload("Member.Allergies").
+		Preload("Member.Illnesses").
 		Preload("Meals.Meal.Partner.User").
 		Preload("Meals.Meal.Images.Image").
+		Preload("Meals.Meal.Allergies.Allergy").
 		Preload("History.User.Image.Image").
 		Preload("History.User.Addresses.AddressDetail").
 		Preload("Partner.User.Image.Image").
@@ -99,85 +109,85 @@ func (r *OrderRepository) preload() *gorm.DB {
 		Preload("Partner.MealCategories")
 }
 
-func (r *OrderRepository) Create(o models.Order) (*models.Order, error) {
+func (r *OrderRepository) Create(ctx context.Context, o models.Order) (*models.Order, error) {
 	err := r.
-		omit().
+		omit(ctx).
 		Session(&gorm.Session{FullSaveAssociations: true}).
 		Create(&o).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
-	onew, err := r.GetByID(o.ID)
+	onew, err := r.GetByID(ctx, o.ID)
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return onew, nil
 }
 
-func (r *OrderRepository) Read() ([]*models.Order, error) {
+func (r *OrderRepository) Read(ctx context.Context) ([]*models.Order, error) {
 	var (
 		o []*models.Order
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Find(&o).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return o, nil
 }
 
-func (r *OrderRepository) Update(o models.Order) (*models.Order, error) {
+func (r *OrderRepository) Update(ctx context.Context, o models.Order) (*models.Order, error) {
 	err := r.
-		omit().
+		omit(ctx).
 		Save(&o).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
-	onew, err := r.GetByID(o.ID)
+	onew, err := r.GetByID(ctx, o.ID)
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return onew, nil
 }
 
-func (r *OrderRepository) Delete(o models.Order) error {
-	err := r.db.
+func (r *OrderRepository) Delete(ctx context.Context, o models.Order) error {
+	err := r.db.WithContext(ctx).
 		Delete(&o).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return err
 	}
 
 	return nil
 }
 
-func (r *OrderRepository) FindAll(p utpagination.Pagination) (*utpagination.Pagination, error) {
+func (r *OrderRepository) FindAll(ctx context.Context, p utpagination.Pagination) (*utpagination.Pagination, error) {
 	var (
 		o    []models.Order
 		ores []responses.Order
 	)
 
 	result := r.
-		preload().
+		preload(ctx).
 		Model(&o).
 		Select(SELECTED_FIELDS)
 
@@ -203,11 +213,16 @@ func (r *OrderRepository) FindAll(p utpagination.Pagination) (*utpagination.Pagi
 
 	result = result.
 		Group("id").
-		Scopes(paginationrepo.Paginate(&o, &p, result)).
+		Scopes(paginationrepo.PaginateKeyset(&o, &p, result, FILTER_FIELDS)).
 		Find(&o)
 
 	if err := result.Error; err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	if err := paginationrepo.NextCursor(&o, &p, result, FILTER_FIELDS); err != nil {
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
@@ -218,124 +233,127 @@ func (r *OrderRepository) FindAll(p utpagination.Pagination) (*utpagination.Pagi
 	return &p, nil
 }
 
-func (r *OrderRepository) GetByID(id uuid.UUID) (*models.Order, error) {
+func (r *OrderRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Order, error) {
 	var (
 		o *models.Order
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Where("id = ?", id).
 		First(&o).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return o, nil
 }
 
-func (r *OrderRepository) GetByMealID(id uuid.UUID) ([]*models.Order, error) {
+func (r *OrderRepository) GetByMealID(ctx context.Context, id uuid.UUID) ([]*models.Order, error) {
 	var (
 		o []*models.Order
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Where("meal_id = ?", id).
 		Find(&o).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return o, nil
 }
 
-func (r *OrderRepository) FindByMemberID(id uuid.UUID) ([]*models.Order, error) {
+func (r *OrderRepository) FindByMemberID(ctx context.Context, id uuid.UUID) ([]*models.Order, error) {
 	var (
 		o []*models.Order
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Where("member_id = ?", id).
 		Find(&o).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return o, nil
 }
 
-func (r *OrderRepository) FindByPartnerID(id uuid.UUID) ([]*models.Order, error) {
+func (r *OrderRepository) FindByPartnerID(ctx context.Context, id uuid.UUID) ([]*models.Order, error) {
 	var (
 		o []*models.Order
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Debug().
 		Select(SELECTED_FIELDS).
 		Where("partner_id = ?", id).
 		Find(&o).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return o, nil
 }
 
-func (r *OrderRepository) getAdmin() (*models.User, error) {
+func (r *OrderRepository) getAdmin(ctx context.Context) (*models.User, error) {
 	var (
 		admin models.User
 	)
 
-	err := r.db.
+	err := r.db.WithContext(ctx).
 		Where("role = ?", consttypes.UR_ADMIN).
 		First(&admin).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return &admin, nil
 }
 
-func (r *OrderRepository) UpdateAutomaticallyStatus(status consttypes.OrderStatus, bufferminutes int, trigger []consttypes.OrderStatus) error {
+// * returns the ids of the updated orders
+func (r *OrderRepository) UpdateAutomaticallyStatus(ctx context.Context, status consttypes.OrderStatus, bufferminutes int, trigger []consttypes.OrderStatus) ([]uuid.UUID, error) {
 	// * get the buffer time
 	buffer := time.Duration(bufferminutes) * time.Minute
 	buffertime := consttypes.TimeNow().Add(-buffer).Format(consttypes.DATETIMEHOURMINUTESFORMAT)
 
 	// * find orders that meet the condition
 	var orders []models.Order
-	err := r.db.
+	err := r.db.WithContext(ctx).
 		Where("status IN ?", trigger).
 		Where("TO_CHAR(updated_at, 'YYYY-MM-DD HH24:MI') = ?", buffertime).
 		Find(&orders).Error
 
 	if err != nil && err != gorm.ErrRecordNotFound {
-		utlogger.Error(err)
-		return err
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
 	}
 
 	// * get the admin user
-	admin, err := r.getAdmin()
+	admin, err := r.getAdmin(ctx)
 	if err != nil {
-		utlogger.Error(err)
-		return err
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
 	}
 
+	var ids []uuid.UUID
+
 	// * loop through orders and update them
 	for _, order := range orders {
 		// * create new order history
@@ -344,34 +362,57 @@ func (r *OrderRepository) UpdateAutomaticallyStatus(status consttypes.OrderStatu
 			status,
 		)
 
-		// * append history
-		order.History = append(order.History, *oh)
-
-		// * update the order status
-		err := r.db.Model(&order).Updates(models.Order{Status: status}).Error
+		oh.OrderID = order.ID
+
+		// * update the order status together with its history, the
+		// * history is what the analytics read the cancellations from. the
+		// * order is only updated while it still has a trigger status, so
+		// * an order another replica or the user updated first is skipped
+		updated := false
+		err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			res := tx.
+				Model(&order).
+				Where("status IN ?", trigger).
+				Updates(models.Order{Status: status})
+
+			if res.Error != nil {
+				return res.Error
+			}
+
+			if res.RowsAffected == 0 {
+				return nil
+			}
+
+			updated = true
+			return tx.Omit("User").Create(oh).Error
+		})
 		if err != nil {
-			utlogger.Error(err)
-			return err
+			utlogger.ErrorContext(ctx, err)
+			return ids, err
+		}
+
+		if updated {
+			ids = append(ids, order.ID)
 		}
 	}
 
-	return nil
+	return ids, nil
 }
 
-func (r *OrderRepository) GetMemberDailyOrder(id uuid.UUID) (int, error) {
+func (r *OrderRepository) GetMemberDailyOrder(ctx context.Context, id uuid.UUID) (int, error) {
 	var (
 		orders []models.Order
 		qty    int = 0
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Where("member_id = ?", id).
 		Where("DATE(created_at) = ?::DATE", consttypes.TimeNow().Format(consttypes.DATEFORMAT)).
 		Find(&orders).Error
 
 	if err != nil && err != gorm.ErrRecordNotFound {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return 0, err
 	}
 
@@ -1,6 +1,7 @@
 package organizationrepo
 
 import (
+	"context"
 	"fmt"
 	"project-skbackend/internal/controllers/responses"
 	"project-skbackend/internal/models"
@@ -25,6 +26,14 @@ var (
 		created_at,
 		updated_at
 	`
+
+	FILTER_FIELDS = paginationrepo.Fields{
+		"user_id":    {Column: "user_id", Type: consttypes.FFT_UUID},
+		"type":       paginationrepo.EnumField("type", consttypes.OT_NURSINGHOME),
+		"name":       {Column: "name", Type: consttypes.FFT_STRING},
+		"created_at": {Column: "created_at", Type: consttypes.FFT_TIME},
+		"updated_at": {Column: "updated_at", Type: consttypes.FFT_TIME},
+	}
 )
 
 type (
@@ -33,14 +42,14 @@ type (
 	}
 
 	IOrganizationRepository interface {
-		Create(o models.Organization) (*models.Organization, error)
-		Read() ([]*models.Organization, error)
-		Update(o models.Organization) (*models.Organization, error)
-		Delete(o models.Organization) error
-		FindAll(p utpagination.Pagination) (*utpagination.Pagination, error)
-		GetByID(id uuid.UUID) (*models.Organization, error)
-		GetByEmail(email string) (*models.Organization, error)
-		GetByUserID(uid uuid.UUID) (*models.Organization, error)
+		Create(ctx context.Context, o models.Organization) (*models.Organization, error)
+		Read(ctx context.Context) ([]*models.Organization, error)
+		Update(ctx context.Context, o models.Organization) (*models.Organization, error)
+		Delete(ctx context.Context, o models.Organization) error
+		FindAll(ctx context.Context, p utpagination.Pagination) (*utpagination.Pagination, error)
+		GetByID(ctx context.Context, id uuid.UUID) (*models.Organization, error)
+		GetByEmail(ctx context.Context, email string) (*models.Organization, error)
+		GetByUserID(ctx context.Context, uid uuid.UUID) (*models.Organization, error)
 	}
 )
 
@@ -48,97 +57,97 @@ func NewOrganizationRepository(db *gorm.DB) *OrganizationRepository {
 	return &OrganizationRepository{db: db}
 }
 
-func (r *OrganizationRepository) omit() *gorm.DB {
-	return r.db.Omit(
+func (r *OrganizationRepository) omit(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Omit(
 		"",
 	)
 }
 
-func (r *OrganizationRepository) preload() *gorm.DB {
-	return r.db.
+func (r *OrganizationRepository) preload(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).
 		Preload(clause.Associations).
 		Preload("User.Addresses.AddressDetail").
 		Preload("User.Image.Image")
 }
 
-func (r *OrganizationRepository) Create(o models.Organization) (*models.Organization, error) {
+func (r *OrganizationRepository) Create(ctx context.Context, o models.Organization) (*models.Organization, error) {
 	err := r.
-		omit().
+		omit(ctx).
 		Session(&gorm.Session{FullSaveAssociations: true}).
 		Create(&o).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
-	onew, err := r.GetByID(o.ID)
+	onew, err := r.GetByID(ctx, o.ID)
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return onew, nil
 }
 
-func (r *OrganizationRepository) Read() ([]*models.Organization, error) {
+func (r *OrganizationRepository) Read(ctx context.Context) ([]*models.Organization, error) {
 	var (
 		o []*models.Organization
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Find(&o).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return o, nil
 }
 
-func (r *OrganizationRepository) Update(o models.Organization) (*models.Organization, error) {
-	err := r.db.
+func (r *OrganizationRepository) Update(ctx context.Context, o models.Organization) (*models.Organization, error) {
+	err := r.db.WithContext(ctx).
 		Save(&o).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
-	onew, err := r.GetByID(o.ID)
+	onew, err := r.GetByID(ctx, o.ID)
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return onew, nil
 }
 
-func (r *OrganizationRepository) Delete(o models.Organization) error {
-	err := r.db.
+func (r *OrganizationRepository) Delete(ctx context.Context, o models.Organization) error {
+	err := r.db.WithContext(ctx).
 		Delete(&o).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return err
 	}
 
 	return nil
 }
 
-func (r *OrganizationRepository) FindAll(p utpagination.Pagination) (*utpagination.Pagination, error) {
+func (r *OrganizationRepository) FindAll(ctx context.Context, p utpagination.Pagination) (*utpagination.Pagination, error) {
 	var (
 		o    []models.Organization
 		ores []responses.Organization
 	)
 
 	result := r.
-		preload().
+		preload(ctx).
 		Model(&o).
 		Select(SELECTED_FIELDS)
 
@@ -146,7 +155,7 @@ func (r *OrganizationRepository) FindAll(p utpagination.Pagination) (*utpaginati
 		p.Search = fmt.Sprintf("%%%s%%", p.Search)
 		result = result.
 			Where(
-				r.db.Where(`
+				r.db.WithContext(ctx).Where(`
 					name ILIKE ?
 			`, p.Search),
 			)
@@ -162,11 +171,11 @@ func (r *OrganizationRepository) FindAll(p utpagination.Pagination) (*utpaginati
 
 	result = result.
 		Group("id").
-		Scopes(paginationrepo.Paginate(&o, &p, result)).
+		Scopes(paginationrepo.Paginate(&o, &p, result, FILTER_FIELDS)).
 		Find(&o)
 
 	if err := result.Error; err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
@@ -177,32 +186,32 @@ func (r *OrganizationRepository) FindAll(p utpagination.Pagination) (*utpaginati
 	return &p, result.Error
 }
 
-func (r *OrganizationRepository) GetByID(id uuid.UUID) (*models.Organization, error) {
+func (r *OrganizationRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Organization, error) {
 	var (
 		o *models.Organization
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Where(&models.Organization{Model: base.Model{ID: id}}).
 		First(&o).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return o, nil
 }
 
-func (r *OrganizationRepository) GetByEmail(email string) (*models.Organization, error) {
+func (r *OrganizationRepository) GetByEmail(ctx context.Context, email string) (*models.Organization, error) {
 	var (
 		o *models.Organization
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Where(`
 			organizations.user_id IN (
@@ -220,26 +229,26 @@ func (r *OrganizationRepository) GetByEmail(email string) (*models.Organization,
 		First(&o).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return o, nil
 }
 
-func (r *OrganizationRepository) GetByUserID(uid uuid.UUID) (*models.Organization, error) {
+func (r *OrganizationRepository) GetByUserID(ctx context.Context, uid uuid.UUID) (*models.Organization, error) {
 	var (
 		o *models.Organization
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Where(&models.Organization{UserID: uid}, uid).
 		First(&o).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
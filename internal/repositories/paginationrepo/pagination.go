@@ -1,13 +1,66 @@
 package paginationrepo
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"math"
+	"project-skbackend/packages/consttypes"
 	"project-skbackend/packages/utils/utpagination"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+type (
+	// * a column a list can be filtered and sorted on, the
+	// * values of the filters are parsed by the type of the field
+	Field struct {
+		Column string
+		Type   consttypes.FilterFieldType
+
+		// * the values an enum field takes
+		Values []string
+	}
+
+	// * the whitelist of a repository, keyed by the name used in
+	// * the query string. the fields not in it are ignored
+	Fields map[string]Field
+
+	order struct {
+		field Field
+		desc  bool
+	}
+
+	// * the sort is kept with the values, so a cursor is only
+	// * used with the sort of the page it was made from
+	cursor struct {
+		Sort   string   `json:"sort"`
+		Values []string `json:"values"`
+	}
+)
+
+// * the id is a uuid v7, so it follows the creation order and
+// * is the tie-breaker of every sort
+var (
+	idfield = Field{Column: "id", Type: consttypes.FFT_UUID}
+)
+
+// * an enum column, only compared with its own values
+func EnumField[T ~string](column string, values ...T) Field {
+	field := Field{Column: column, Type: consttypes.FFT_ENUM}
+	for _, value := range values {
+		field.Values = append(field.Values, string(value))
+	}
+
+	return field
+}
+
 func getLimit(p *utpagination.Pagination) int {
 	if p.Limit == 0 {
 		p.Limit = 10
@@ -22,40 +75,365 @@ func getPage(p *utpagination.Pagination) int {
 	return p.Page
 }
 
-func getSort(p *utpagination.Pagination) string {
-	builtQuery := "created_at desc"
-	direction := "asc"
+// * keeps the whitelisted sorts, in the given order, and ends
+// * with the id so the order is always the same between pages
+func getOrders(p *utpagination.Pagination, fields Fields) []order {
+	var (
+		orders []order
+		hasid  bool
+	)
+
+	for _, s := range p.Sorts {
+		field, ok := fields[s.Field]
+		if !ok && s.Field == idfield.Column {
+			field, ok = idfield, true
+		}
 
-	if p.Direction != "" {
-		direction = p.Direction
+		if !ok {
+			continue
+		}
+
+		orders = append(orders, order{field: field, desc: s.Direction == consttypes.SD_DESC})
+		hasid = hasid || field.Column == idfield.Column
 	}
 
-	if p.Sort != "" {
-		builtQuery = fmt.Sprintf("%s %s", p.Sort, direction)
+	if !hasid {
+		orders = append(orders, order{field: idfield, desc: p.Direction == consttypes.SD_DESC.String()})
 	}
 
-	return builtQuery
+	return orders
+}
+
+func getSort(orders []order) string {
+	var (
+		parts []string
+	)
+
+	for _, o := range orders {
+		direction := consttypes.SD_ASC
+		if o.desc {
+			direction = consttypes.SD_DESC
+		}
+
+		parts = append(parts, fmt.Sprintf("%s %s", o.field.Column, direction))
+	}
+
+	return strings.Join(parts, ", ")
 }
 
 func getOffset(p *utpagination.Pagination) int {
 	return (getPage(p) - 1) * getLimit(p)
 }
 
+// * parses a value of the query string by the type of the field
+func parseValue(field Field, value string) (any, error) {
+	switch field.Type {
+	case consttypes.FFT_UUID:
+		return uuid.Parse(value)
+	case consttypes.FFT_TIME:
+		return time.Parse(time.RFC3339Nano, value)
+	case consttypes.FFT_NUMBER:
+		return strconv.ParseFloat(value, 64)
+	case consttypes.FFT_BOOL:
+		return strconv.ParseBool(value)
+	}
+
+	return value, nil
+}
+
+// * the time filters also take a date, which is then compared
+// * with the date of the column like the created range filters
+func parseCondition(field Field, c utpagination.Condition) (string, []any, error) {
+	var (
+		column = field.Column
+		values []any
+	)
+
+	if field.Type == consttypes.FFT_ENUM && !c.Operator.IsEnum() {
+		return "", nil, fmt.Errorf("operator %s can not filter the enum %s", c.Operator, field.Column)
+	}
+
+	if c.Operator == consttypes.FO_NULL {
+		isnull, err := strconv.ParseBool(c.Values[0])
+		if err != nil {
+			return "", nil, err
+		}
+
+		if isnull {
+			return fmt.Sprintf("%s IS NULL", column), nil, nil
+		}
+
+		return fmt.Sprintf("%s IS NOT NULL", column), nil, nil
+	}
+
+	if c.Operator == consttypes.FO_LIKE {
+		if field.Type != consttypes.FFT_STRING {
+			return "", nil, fmt.Errorf("operator %s can not filter the %s %s", c.Operator, field.Type, field.Column)
+		}
+
+		return fmt.Sprintf("%s ILIKE ?", column), []any{"%" + c.Values[0] + "%"}, nil
+	}
+
+	if c.Operator.IsRange() && field.Type != consttypes.FFT_TIME && field.Type != consttypes.FFT_NUMBER {
+		return "", nil, fmt.Errorf("operator %s can not filter the %s %s", c.Operator, field.Type, field.Column)
+	}
+
+	for _, raw := range c.Values {
+		if field.Type == consttypes.FFT_TIME {
+			if date, err := time.Parse(consttypes.DATEFORMAT, raw); err == nil {
+				column = fmt.Sprintf("date(%s)", field.Column)
+				values = append(values, date.Format(consttypes.DATEFORMAT))
+				continue
+			}
+		}
+
+		if field.Type == consttypes.FFT_ENUM && !slices.Contains(field.Values, raw) {
+			return "", nil, fmt.Errorf("%q is not a value of the enum %s", raw, field.Column)
+		}
+
+		value, err := parseValue(field, raw)
+		if err != nil {
+			return "", nil, err
+		}
+
+		values = append(values, value)
+	}
+
+	switch c.Operator {
+	case consttypes.FO_IN:
+		return fmt.Sprintf("%s IN ?", column), []any{values}, nil
+	case consttypes.FO_NIN:
+		return fmt.Sprintf("%s NOT IN ?", column), []any{values}, nil
+	}
+
+	operators := map[consttypes.FilterOperator]string{
+		consttypes.FO_EQ:  "=",
+		consttypes.FO_NE:  "<>",
+		consttypes.FO_GT:  ">",
+		consttypes.FO_GTE: ">=",
+		consttypes.FO_LT:  "<",
+		consttypes.FO_LTE: "<=",
+	}
+
+	return fmt.Sprintf("%s %s ?", column, operators[c.Operator]), values[:1], nil
+}
+
+// * applies the whitelisted conditions, the ones on unknown fields are
+// * left out while a condition that does not fit its field fails the
+// * query instead of silently listing the rows unfiltered
+func getConditions(p *utpagination.Pagination, fields Fields) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		for _, c := range p.Conditions {
+			field, ok := fields[c.Field]
+			if !ok {
+				continue
+			}
+
+			query, args, err := parseCondition(field, c)
+			if err != nil {
+				db.AddError(consttypes.ErrInvalidFilter.Wrap(err))
+				return db
+			}
+
+			db = db.Where(query, args...)
+		}
+
+		return db
+	}
+}
+
+// * the cursor holds the values of the sort columns of the last row
+// * of the page, a cursor that can not be read or was made for another
+// * sort fails the query instead of silently starting from the first page
+func getKeyset(p *utpagination.Pagination, orders []order) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if p.Cursor == "" {
+			return db
+		}
+
+		values, err := decodeCursor(p.Cursor, orders)
+		if err != nil {
+			db.AddError(consttypes.ErrInvalidCursor.Wrap(err))
+			return db
+		}
+
+		// * (a > x) or (a = x and b > y) or ..., so every column
+		// * can be sorted on its own direction
+		var (
+			ors  []string
+			args []any
+		)
+
+		for i, o := range orders {
+			var (
+				ands []string
+			)
+
+			for j := 0; j < i; j++ {
+				ands = append(ands, fmt.Sprintf("%s = ?", orders[j].field.Column))
+				args = append(args, values[j])
+			}
+
+			operator := ">"
+			if o.desc {
+				operator = "<"
+			}
+
+			ands = append(ands, fmt.Sprintf("%s %s ?", o.field.Column, operator))
+			args = append(args, values[i])
+
+			ors = append(ors, "("+strings.Join(ands, " AND ")+")")
+		}
+
+		return db.Where(strings.Join(ors, " OR "), args...)
+	}
+}
+
+func decodeCursor(encoded string, orders []order) ([]any, error) {
+	var (
+		c cursor
+	)
+
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, err
+	}
+
+	if c.Sort != getSort(orders) || len(c.Values) != len(orders) {
+		return nil, fmt.Errorf("cursor of sort %q does not match the sort %q", c.Sort, getSort(orders))
+	}
+
+	values := make([]any, len(orders))
+	for i, o := range orders {
+		value, err := parseValue(o.field, c.Values[i])
+		if err != nil {
+			return nil, err
+		}
+
+		values[i] = value
+	}
+
+	return values, nil
+}
+
 func Paginate(
 	data any,
 	pagination *utpagination.Pagination,
 	db *gorm.DB,
+	fields Fields,
 ) func(db *gorm.DB) *gorm.DB {
 	var (
 		totdata int64
 	)
 
-	db.Count(&totdata)
+	conditions := getConditions(pagination, fields)
+	orders := getOrders(pagination, fields)
+
+	// * counted on a copy of the query, so the conditions
+	// * are only added once to the query of the page
+	count := conditions(db.Session(&gorm.Session{})).Count(&totdata)
 
 	pagination.TotalDatas = totdata
-	pagination.TotalPages = int(math.Ceil(float64(totdata) / float64(pagination.Limit)))
+	pagination.TotalPages = int(math.Ceil(float64(totdata) / float64(getLimit(pagination))))
+
+	return func(db *gorm.DB) *gorm.DB {
+		// * the count already failed on the same conditions, the
+		// * page is not read with a total that is not right
+		if count.Error != nil {
+			db.AddError(count.Error)
+			return db
+		}
+
+		return conditions(db).Offset(getOffset(pagination)).Limit(getLimit(pagination)).Order(getSort(orders))
+	}
+}
+
+// * PaginateKeyset pages with the cursor when one is given and falls
+// * back to Paginate otherwise. it reads one row past the page, so
+// * the repository must call NextCursor on the rows it found
+func PaginateKeyset(
+	data any,
+	pagination *utpagination.Pagination,
+	db *gorm.DB,
+	fields Fields,
+) func(db *gorm.DB) *gorm.DB {
+	if !pagination.Keyset {
+		return Paginate(data, pagination, db, fields)
+	}
+
+	conditions := getConditions(pagination, fields)
+	orders := getOrders(pagination, fields)
+
+	// * the total is not counted, counting is what keyset
+	// * pagination avoids on the large tables
+	pagination.Page = 0
 
 	return func(db *gorm.DB) *gorm.DB {
-		return db.Offset(getOffset(pagination)).Limit(getLimit(pagination)).Order(getSort(pagination))
+		return getKeyset(pagination, orders)(conditions(db)).Limit(getLimit(pagination) + 1).Order(getSort(orders))
+	}
+}
+
+// * NextCursor drops the extra row read by PaginateKeyset and sets the
+// * cursor of the next page from the last row, data is a pointer to the
+// * slice of models found with db
+func NextCursor(
+	data any,
+	pagination *utpagination.Pagination,
+	db *gorm.DB,
+	fields Fields,
+) error {
+	if !pagination.Keyset {
+		return nil
 	}
+
+	rows := reflect.ValueOf(data).Elem()
+	if rows.Len() <= getLimit(pagination) {
+		pagination.NextCursor = ""
+		return nil
+	}
+
+	rows.Set(rows.Slice(0, getLimit(pagination)))
+
+	last := reflect.Indirect(rows.Index(rows.Len() - 1))
+	orders := getOrders(pagination, fields)
+	encoded := make([]string, len(orders))
+
+	for i, o := range orders {
+		sf := db.Statement.Schema.LookUpField(o.field.Column)
+		if sf == nil {
+			return fmt.Errorf("cursor column %s not found", o.field.Column)
+		}
+
+		value, zero := sf.ValueOf(db.Statement.Context, last)
+		if zero && o.field.Type != consttypes.FFT_NUMBER && o.field.Type != consttypes.FFT_BOOL {
+			return fmt.Errorf("cursor column %s is empty", o.field.Column)
+		}
+
+		encoded[i] = encodeValue(value)
+	}
+
+	raw, err := json.Marshal(cursor{Sort: getSort(orders), Values: encoded})
+	if err != nil {
+		return err
+	}
+
+	pagination.NextCursor = base64.RawURLEncoding.EncodeToString(raw)
+	return nil
+}
+
+func encodeValue(value any) string {
+	switch v := value.(type) {
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case *time.Time:
+		return v.Format(time.RFC3339Nano)
+	case fmt.Stringer:
+		return v.String()
+	}
+
+	return fmt.Sprint(value)
 }
@@ -0,0 +1,91 @@
+package paginationrepo
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/utils/utpagination"
+	"testing"
+)
+
+var (
+	testFields = Fields{
+		"name":       {Column: "name", Type: consttypes.FFT_STRING},
+		"created_at": {Column: "created_at", Type: consttypes.FFT_TIME},
+	}
+)
+
+func encodeCursor(t *testing.T, c cursor) string {
+	t.Helper()
+
+	raw, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func TestDecodeCursor(t *testing.T) {
+	p := utpagination.Pagination{Sorts: []utpagination.Sort{{Field: "name", Direction: consttypes.SD_ASC}}}
+	orders := getOrders(&p, testFields)
+
+	valid := encodeCursor(t, cursor{Sort: getSort(orders), Values: []string{"meal", "0190a1d2-7b3c-7def-8a12-3456789abcde"}})
+	if _, err := decodeCursor(valid, orders); err != nil {
+		t.Fatalf("valid cursor err = %v", err)
+	}
+
+	// * a cursor of the same length made for another sort
+	other := utpagination.Pagination{Sorts: []utpagination.Sort{{Field: "created_at", Direction: consttypes.SD_ASC}}}
+	stale := encodeCursor(t, cursor{Sort: getSort(getOrders(&other, testFields)), Values: []string{"meal", "0190a1d2-7b3c-7def-8a12-3456789abcde"}})
+
+	for name, c := range map[string]string{
+		"not base64":   "%%%",
+		"not json":     base64.RawURLEncoding.EncodeToString([]byte("meal")),
+		"other sort":   stale,
+		"bad value":    encodeCursor(t, cursor{Sort: getSort(orders), Values: []string{"meal", "not-a-uuid"}}),
+		"short values": encodeCursor(t, cursor{Sort: getSort(orders), Values: []string{"meal"}}),
+	} {
+		if _, err := decodeCursor(c, orders); err == nil {
+			t.Errorf("%s: err = nil, want the cursor rejected", name)
+		}
+	}
+}
+
+func TestParseConditionEnum(t *testing.T) {
+	field := EnumField("status", consttypes.OS_PLACED, consttypes.OS_CONFIRMED)
+
+	for name, c := range map[string]utpagination.Condition{
+		"eq": {Field: "status", Operator: consttypes.FO_EQ, Values: []string{"Placed"}},
+		"ne": {Field: "status", Operator: consttypes.FO_NE, Values: []string{"Confirmed"}},
+		"in": {Field: "status", Operator: consttypes.FO_IN, Values: []string{"Placed", "Confirmed"}},
+	} {
+		if _, _, err := parseCondition(field, c); err != nil {
+			t.Errorf("%s: err = %v, want the condition applied", name, err)
+		}
+	}
+
+	for name, c := range map[string]utpagination.Condition{
+		"like":          {Field: "status", Operator: consttypes.FO_LIKE, Values: []string{"Pla"}},
+		"range":         {Field: "status", Operator: consttypes.FO_GT, Values: []string{"Placed"}},
+		"null":          {Field: "status", Operator: consttypes.FO_NULL, Values: []string{"true"}},
+		"unknown value": {Field: "status", Operator: consttypes.FO_EQ, Values: []string{"Lost"}},
+		"unknown in":    {Field: "status", Operator: consttypes.FO_IN, Values: []string{"Placed", "placed"}},
+	} {
+		if _, _, err := parseCondition(field, c); err == nil {
+			t.Errorf("%s: err = nil, want the condition rejected", name)
+		}
+	}
+}
+
+func TestParseConditionInvalidValue(t *testing.T) {
+	for name, c := range map[string]utpagination.Condition{
+		"like on time":  {Field: "created_at", Operator: consttypes.FO_LIKE, Values: []string{"2024"}},
+		"range on text": {Field: "name", Operator: consttypes.FO_GT, Values: []string{"meal"}},
+		"bad time":      {Field: "created_at", Operator: consttypes.FO_EQ, Values: []string{"yesterday"}},
+	} {
+		if _, _, err := parseCondition(testFields[c.Field], c); err == nil {
+			t.Errorf("%s: err = nil, want the condition rejected", name)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package partnerrepo
 
 import (
+	"context"
 	"fmt"
 	"project-skbackend/internal/controllers/responses"
 	"project-skbackend/internal/models"
@@ -24,6 +25,13 @@ var (
 		created_at,
 		updated_at
 	`
+
+	FILTER_FIELDS = paginationrepo.Fields{
+		"user_id":    {Column: "user_id", Type: consttypes.FFT_UUID},
+		"name":       {Column: "name", Type: consttypes.FFT_STRING},
+		"created_at": {Column: "created_at", Type: consttypes.FFT_TIME},
+		"updated_at": {Column: "updated_at", Type: consttypes.FFT_TIME},
+	}
 )
 
 type (
@@ -32,14 +40,14 @@ type (
 	}
 
 	IPartnerRepository interface {
-		Create(p models.Partner) (*models.Partner, error)
-		Read() ([]*models.Partner, error)
-		Update(p models.Partner) (*models.Partner, error)
-		Delete(p models.Partner) error
-		FindAll(p utpagination.Pagination) (*utpagination.Pagination, error)
-		GetByID(pid uuid.UUID) (*models.Partner, error)
-		GetByEmail(email string) (*models.Partner, error)
-		GetByUserID(uid uuid.UUID) (*models.Partner, error)
+		Create(ctx context.Context, p models.Partner) (*models.Partner, error)
+		Read(ctx context.Context) ([]*models.Partner, error)
+		Update(ctx context.Context, p models.Partner) (*models.Partner, error)
+		Delete(ctx context.Context, p models.Partner) error
+		FindAll(ctx context.Context, p utpagination.Pagination) (*utpagination.Pagination, error)
+		GetByID(ctx context.Context, pid uuid.UUID) (*models.Partner, error)
+		GetByEmail(ctx context.Context, email string) (*models.Partner, error)
+		GetByUserID(ctx context.Context, uid uuid.UUID) (*models.Partner, error)
 	}
 )
 
@@ -47,99 +55,99 @@ func NewPartnerRepository(db *gorm.DB) *PartnerRepository {
 	return &PartnerRepository{db: db}
 }
 
-func (r *PartnerRepository) omit() *gorm.DB {
-	return r.db.Omit(
+func (r *PartnerRepository) omit(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Omit(
 		"MealCategories",
 	)
 }
 
-func (r *PartnerRepository) preload() *gorm.DB {
-	return r.db.
+func (r *PartnerRepository) preload(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).
 		Preload(clause.Associations).
 		Preload("User.Addresses.AddressDetail").
 		Preload("User.Image.Image")
 }
 
-func (r *PartnerRepository) Create(p models.Partner) (*models.Partner, error) {
+func (r *PartnerRepository) Create(ctx context.Context, p models.Partner) (*models.Partner, error) {
 	err := r.
-		omit().
+		omit(ctx).
 		Session(&gorm.Session{FullSaveAssociations: true}).
 		Create(&p).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
-	pnew, err := r.GetByID(p.ID)
+	pnew, err := r.GetByID(ctx, p.ID)
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return pnew, nil
 }
 
-func (r *PartnerRepository) Read() ([]*models.Partner, error) {
+func (r *PartnerRepository) Read(ctx context.Context) ([]*models.Partner, error) {
 	var (
 		p []*models.Partner
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Find(&p).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return p, nil
 }
 
-func (r *PartnerRepository) Update(p models.Partner) (*models.Partner, error) {
+func (r *PartnerRepository) Update(ctx context.Context, p models.Partner) (*models.Partner, error) {
 	err := r.
-		omit().
+		omit(ctx).
 		Session(&gorm.Session{FullSaveAssociations: true}).
 		Save(&p).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
-	pnew, err := r.GetByID(p.ID)
+	pnew, err := r.GetByID(ctx, p.ID)
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return pnew, nil
 }
 
-func (r *PartnerRepository) Delete(p models.Partner) error {
-	err := r.db.
+func (r *PartnerRepository) Delete(ctx context.Context, p models.Partner) error {
+	err := r.db.WithContext(ctx).
 		Delete(&p).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return err
 	}
 
 	return nil
 }
 
-func (r *PartnerRepository) FindAll(p utpagination.Pagination) (*utpagination.Pagination, error) {
+func (r *PartnerRepository) FindAll(ctx context.Context, p utpagination.Pagination) (*utpagination.Pagination, error) {
 	var (
 		pa    []*models.Partner
 		pares []responses.Partner
 	)
 
 	result := r.
-		preload().
+		preload(ctx).
 		Model(&pa).
 		Select(SELECTED_FIELDS)
 
@@ -147,7 +155,7 @@ func (r *PartnerRepository) FindAll(p utpagination.Pagination) (*utpagination.Pa
 		p.Search = fmt.Sprintf("%%%s%%", p.Search)
 		result = result.
 			Where(
-				r.db.Where(`
+				r.db.WithContext(ctx).Where(`
 					name ILIKE ?
 			`, p.Search),
 			)
@@ -163,11 +171,11 @@ func (r *PartnerRepository) FindAll(p utpagination.Pagination) (*utpagination.Pa
 
 	result = result.
 		Group("id").
-		Scopes(paginationrepo.Paginate(&pa, &p, result)).
+		Scopes(paginationrepo.Paginate(&pa, &p, result, FILTER_FIELDS)).
 		Find(&pa)
 
 	if err := result.Error; err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
@@ -178,32 +186,32 @@ func (r *PartnerRepository) FindAll(p utpagination.Pagination) (*utpagination.Pa
 	return &p, nil
 }
 
-func (r *PartnerRepository) GetByID(pid uuid.UUID) (*models.Partner, error) {
+func (r *PartnerRepository) GetByID(ctx context.Context, pid uuid.UUID) (*models.Partner, error) {
 	var (
 		p *models.Partner
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Where(&models.Partner{Model: base.Model{ID: pid}}).
 		First(&p).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return p, nil
 }
 
-func (r *PartnerRepository) GetByEmail(email string) (*models.Partner, error) {
+func (r *PartnerRepository) GetByEmail(ctx context.Context, email string) (*models.Partner, error) {
 	var (
 		p *models.Partner
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Where(`
 			partners.user_id IN (
@@ -221,26 +229,26 @@ func (r *PartnerRepository) GetByEmail(email string) (*models.Partner, error) {
 		First(&p).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return p, nil
 }
 
-func (r *PartnerRepository) GetByUserID(uid uuid.UUID) (*models.Partner, error) {
+func (r *PartnerRepository) GetByUserID(ctx context.Context, uid uuid.UUID) (*models.Partner, error) {
 	var (
 		p *models.Partner
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Where(&models.Partner{UserID: uid}, uid).
 		First(&p).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
@@ -1,6 +1,7 @@
 package patronrepo
 
 import (
+	"context"
 	"fmt"
 	"project-skbackend/internal/controllers/responses"
 	"project-skbackend/internal/models"
@@ -25,6 +26,14 @@ var (
 		created_at,
 		updated_at
 	`
+
+	FILTER_FIELDS = paginationrepo.Fields{
+		"user_id":    {Column: "user_id", Type: consttypes.FFT_UUID},
+		"type":       paginationrepo.EnumField("type", consttypes.PT_ORGANIZATION, consttypes.PT_PERSONAL),
+		"name":       {Column: "name", Type: consttypes.FFT_STRING},
+		"created_at": {Column: "created_at", Type: consttypes.FFT_TIME},
+		"updated_at": {Column: "updated_at", Type: consttypes.FFT_TIME},
+	}
 )
 
 type (
@@ -33,13 +42,13 @@ type (
 	}
 
 	IPatronRepository interface {
-		Create(p models.Patron) (*models.Patron, error)
-		Read() ([]*models.Patron, error)
-		Update(p models.Patron) (*models.Patron, error)
-		Delete(p models.Patron) error
-		FindAll(p utpagination.Pagination) (*utpagination.Pagination, error)
-		GetByID(id uuid.UUID) (*models.Patron, error)
-		GetByUserID(id uuid.UUID) (*models.Patron, error)
+		Create(ctx context.Context, p models.Patron) (*models.Patron, error)
+		Read(ctx context.Context) ([]*models.Patron, error)
+		Update(ctx context.Context, p models.Patron) (*models.Patron, error)
+		Delete(ctx context.Context, p models.Patron) error
+		FindAll(ctx context.Context, p utpagination.Pagination) (*utpagination.Pagination, error)
+		GetByID(ctx context.Context, id uuid.UUID) (*models.Patron, error)
+		GetByUserID(ctx context.Context, id uuid.UUID) (*models.Patron, error)
 	}
 )
 
@@ -47,101 +56,101 @@ func NewPatronRepository(db *gorm.DB) *PatronRepository {
 	return &PatronRepository{db: db}
 }
 
-func (r *PatronRepository) omit() *gorm.DB {
-	return r.db.
+func (r *PatronRepository) omit(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).
 		Omit(
 			"Donations",
 		)
 }
 
-func (r *PatronRepository) preload() *gorm.DB {
-	return r.db.
+func (r *PatronRepository) preload(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).
 		Preload(clause.Associations).
 		Preload("User.Addresses.AddressDetail").
 		Preload("User.Image.Image").
 		Preload("Donations")
 }
 
-func (r *PatronRepository) Create(p models.Patron) (*models.Patron, error) {
+func (r *PatronRepository) Create(ctx context.Context, p models.Patron) (*models.Patron, error) {
 	err := r.
-		omit().
+		omit(ctx).
 		Session(&gorm.Session{FullSaveAssociations: true}).
 		Create(&p).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
-	pnew, err := r.GetByID(p.ID)
+	pnew, err := r.GetByID(ctx, p.ID)
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return pnew, nil
 }
 
-func (r *PatronRepository) Read() ([]*models.Patron, error) {
+func (r *PatronRepository) Read(ctx context.Context) ([]*models.Patron, error) {
 	var (
 		p []*models.Patron
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Find(&p).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return p, nil
 }
 
-func (r *PatronRepository) Update(p models.Patron) (*models.Patron, error) {
+func (r *PatronRepository) Update(ctx context.Context, p models.Patron) (*models.Patron, error) {
 	err := r.
-		omit().
+		omit(ctx).
 		Session(&gorm.Session{FullSaveAssociations: true}).
 		Save(&p).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
-	pnew, err := r.GetByID(p.ID)
+	pnew, err := r.GetByID(ctx, p.ID)
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return pnew, nil
 }
 
-func (r *PatronRepository) Delete(p models.Patron) error {
-	err := r.db.
+func (r *PatronRepository) Delete(ctx context.Context, p models.Patron) error {
+	err := r.db.WithContext(ctx).
 		Delete(&p).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return err
 	}
 
 	return nil
 }
 
-func (r *PatronRepository) FindAll(p utpagination.Pagination) (*utpagination.Pagination, error) {
+func (r *PatronRepository) FindAll(ctx context.Context, p utpagination.Pagination) (*utpagination.Pagination, error) {
 	var (
 		ps     []models.Patron
 		preses []responses.Patron
 	)
 
 	result := r.
-		preload().
+		preload(ctx).
 		Model(&ps).
 		Select(SELECTED_FIELDS)
 
@@ -149,7 +158,7 @@ func (r *PatronRepository) FindAll(p utpagination.Pagination) (*utpagination.Pag
 		p.Search = fmt.Sprintf("%%%s%%", p.Search)
 		result = result.
 			Where(
-				r.db.Where(`
+				r.db.WithContext(ctx).Where(`
 					name ILIKE ?
 			`, p.Search),
 			)
@@ -165,11 +174,11 @@ func (r *PatronRepository) FindAll(p utpagination.Pagination) (*utpagination.Pag
 
 	result = result.
 		Group("id").
-		Scopes(paginationrepo.Paginate(&ps, &p, result)).
+		Scopes(paginationrepo.Paginate(&ps, &p, result, FILTER_FIELDS)).
 		Find(&ps)
 
 	if err := result.Error; err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
@@ -180,38 +189,38 @@ func (r *PatronRepository) FindAll(p utpagination.Pagination) (*utpagination.Pag
 	return &p, nil
 }
 
-func (r *PatronRepository) GetByID(id uuid.UUID) (*models.Patron, error) {
+func (r *PatronRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Patron, error) {
 	var (
 		p *models.Patron
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Where(&models.Patron{Model: base.Model{ID: id}}).
 		First(&p).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return p, nil
 }
 
-func (r *PatronRepository) GetByUserID(id uuid.UUID) (*models.Patron, error) {
+func (r *PatronRepository) GetByUserID(ctx context.Context, id uuid.UUID) (*models.Patron, error) {
 	var (
 		p *models.Patron
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Where(&models.Patron{UserID: id}, id).
 		First(&p).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
@@ -0,0 +1,101 @@
+package rolepermissionrepo
+
+import (
+	"context"
+	"project-skbackend/internal/models"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/utils/utlogger"
+
+	"gorm.io/gorm"
+)
+
+var (
+	SELECTED_FIELDS = `
+		id,
+		role,
+		permission,
+		created_at,
+		updated_at
+	`
+)
+
+type (
+	RolePermissionRepository struct {
+		db *gorm.DB
+	}
+
+	IRolePermissionRepository interface {
+		Read(ctx context.Context) ([]*models.RolePermission, error)
+		FindByRole(ctx context.Context, role consttypes.UserRole) ([]*models.RolePermission, error)
+		ReplaceByRole(ctx context.Context, role consttypes.UserRole, rps []models.RolePermission) ([]*models.RolePermission, error)
+	}
+)
+
+func NewRolePermissionRepository(db *gorm.DB) *RolePermissionRepository {
+	return &RolePermissionRepository{db: db}
+}
+
+func (r *RolePermissionRepository) Read(ctx context.Context) ([]*models.RolePermission, error) {
+	var (
+		rps []*models.RolePermission
+	)
+
+	err := r.db.WithContext(ctx).
+		Select(SELECTED_FIELDS).
+		Order("role, permission").
+		Find(&rps).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return rps, nil
+}
+
+func (r *RolePermissionRepository) FindByRole(ctx context.Context, role consttypes.UserRole) ([]*models.RolePermission, error) {
+	var (
+		rps []*models.RolePermission
+	)
+
+	err := r.db.WithContext(ctx).
+		Select(SELECTED_FIELDS).
+		Where("role = ?", role).
+		Order("permission").
+		Find(&rps).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return rps, nil
+}
+
+func (r *RolePermissionRepository) ReplaceByRole(ctx context.Context, role consttypes.UserRole, rps []models.RolePermission) ([]*models.RolePermission, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// * hard delete so the unique index on role and
+		// * permission does not collide with old rows
+		err := tx.
+			Unscoped().
+			Where("role = ?", role).
+			Delete(&models.RolePermission{}).Error
+
+		if err != nil {
+			return err
+		}
+
+		if len(rps) == 0 {
+			return nil
+		}
+
+		return tx.Create(&rps).Error
+	})
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return r.FindByRole(ctx, role)
+}
@@ -0,0 +1,283 @@
+package searchrepo
+
+import (
+	"context"
+	"fmt"
+	"project-skbackend/internal/controllers/responses"
+	"project-skbackend/packages/utils/utlogger"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	SearchRepository struct {
+		db *gorm.DB
+	}
+
+	// * the text is read as a web search, quoted phrases and a leading
+	// * minus work, and is also matched by trigram against the meal name
+	MealQuery struct {
+		Text        string
+		CategoryIDs []uuid.UUID
+		PartnerIDs  []uuid.UUID
+		AllergyFree []uuid.UUID
+		Limit       int
+		Offset      int
+	}
+
+	ISearchRepository interface {
+		SearchMeals(ctx context.Context, q MealQuery) ([]responses.MealSearchHit, int64, error)
+		MealFacets(ctx context.Context, q MealQuery) (*responses.MealSearchFacets, error)
+		IndexMeal(ctx context.Context, id uuid.UUID) error
+		IndexMeals(ctx context.Context) (int64, error)
+	}
+)
+
+// * the facets a filter is left out of
+const (
+	exceptnone     = ""
+	exceptcategory = "category"
+	exceptpartner  = "partner"
+)
+
+func NewSearchRepository(db *gorm.DB) *SearchRepository {
+	return &SearchRepository{db: db}
+}
+
+func (q MealQuery) args() map[string]any {
+	return map[string]any{
+		"q":           q.Text,
+		"categories":  q.CategoryIDs,
+		"partners":    q.PartnerIDs,
+		"allergyfree": q.AllergyFree,
+		"limit":       q.Limit,
+		"offset":      q.Offset,
+		"namehl":      "StartSel=<mark>, StopSel=</mark>, HighlightAll=true",
+		"deschl":      "StartSel=<mark>, StopSel=</mark>, MaxWords=30, MinWords=10, MaxFragments=2",
+	}
+}
+
+// * the conditions of the matched meals, the text goes through the
+// * gin index of the vector or the trigram index of the name
+func (q MealQuery) where(except string) string {
+	conditions := []string{
+		`m.deleted_at IS NULL`,
+		`(m.search_vector @@ query.tsq OR @q <% m.name)`,
+	}
+
+	if len(q.CategoryIDs) > 0 && except != exceptcategory {
+		conditions = append(conditions, `m.meal_category_id IN @categories`)
+	}
+
+	if len(q.PartnerIDs) > 0 && except != exceptpartner {
+		conditions = append(conditions, `m.partner_id IN @partners`)
+	}
+
+	if len(q.AllergyFree) > 0 {
+		conditions = append(conditions, `NOT EXISTS (
+			SELECT 1 FROM meal_allergies ma
+			WHERE ma.meal_id = m.id AND ma.deleted_at IS NULL AND ma.allergy_id IN @allergyfree
+		)`)
+	}
+
+	return strings.Join(conditions, " AND ")
+}
+
+// * the highlighted text is rendered as html, so the text of the meal is
+// * escaped before ts_headline adds the marks. the parser of postgres reads
+// * the entities as their own tokens, so the words around them still match
+func escapehtml(column string) string {
+	for _, r := range [][2]string{{"&", "&amp;"}, {"<", "&lt;"}, {">", "&gt;"}, {`"`, "&quot;"}, {"'", "&#39;"}} {
+		column = fmt.Sprintf("replace(%s, '%s', '%s')", column, strings.ReplaceAll(r[0], "'", "''"), r[1])
+	}
+
+	return column
+}
+
+// * the websearch query is built once and joined to every meal
+const matchfrom = `
+	FROM meals m
+	CROSS JOIN (SELECT websearch_to_tsquery('simple', @q) AS tsq) query
+`
+
+func (r *SearchRepository) scan(ctx context.Context, query string, q MealQuery, dest any) error {
+	err := r.db.WithContext(ctx).
+		Raw(query, q.args()).
+		Scan(dest).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return err
+	}
+
+	return nil
+}
+
+// * the rank adds the closeness of the name to the rank of the vector, so a
+// * misspelled name still ranks by how close it is. the highlights are only
+// * built for the rows of the page
+func (r *SearchRepository) SearchMeals(ctx context.Context, q MealQuery) ([]responses.MealSearchHit, int64, error) {
+	var (
+		hits    []responses.MealSearchHit
+		totdata int64
+	)
+
+	query := `
+		SELECT
+			hit.id,
+			hit.rank,
+			ts_headline('simple', ` + escapehtml("hit.name") + `, hit.tsq, @namehl) AS name,
+			ts_headline('simple', ` + escapehtml("hit.description") + `, hit.tsq, @deschl) AS description
+		FROM (
+			SELECT
+				m.id,
+				m.name,
+				coalesce(m.description, '') AS description,
+				query.tsq,
+				ts_rank_cd(m.search_vector, query.tsq) + word_similarity(@q, m.name) AS rank
+			` + matchfrom + `
+			WHERE ` + q.where(exceptnone) + `
+			ORDER BY rank DESC, m.id
+			LIMIT @limit OFFSET @offset
+		) hit
+		ORDER BY hit.rank DESC, hit.id
+	`
+
+	if err := r.scan(ctx, query, q, &hits); err != nil {
+		return nil, 0, err
+	}
+
+	count := `
+		SELECT COUNT(*)
+		` + matchfrom + `
+		WHERE ` + q.where(exceptnone) + `
+	`
+
+	if err := r.scan(ctx, count, q, &totdata); err != nil {
+		return nil, 0, err
+	}
+
+	return hits, totdata, nil
+}
+
+func (r *SearchRepository) MealFacets(ctx context.Context, q MealQuery) (*responses.MealSearchFacets, error) {
+	var (
+		facets responses.MealSearchFacets
+	)
+
+	categories := `
+		SELECT mc.id, mc.name, COUNT(*) AS count
+		` + matchfrom + `
+		JOIN meal_categories mc ON mc.id = m.meal_category_id AND mc.deleted_at IS NULL
+		WHERE ` + q.where(exceptcategory) + `
+		GROUP BY mc.id, mc.name
+		ORDER BY count DESC, mc.name
+	`
+
+	if err := r.scan(ctx, categories, q, &facets.Categories); err != nil {
+		return nil, err
+	}
+
+	partners := `
+		SELECT p.id, p.name, COUNT(*) AS count
+		` + matchfrom + `
+		JOIN partners p ON p.id = m.partner_id AND p.deleted_at IS NULL
+		WHERE ` + q.where(exceptpartner) + `
+		GROUP BY p.id, p.name
+		ORDER BY count DESC, p.name
+	`
+
+	if err := r.scan(ctx, partners, q, &facets.Partners); err != nil {
+		return nil, err
+	}
+
+	// * for every allergy, the matches that do not contain it
+	allergyfree := `
+		WITH matched AS (
+			SELECT m.id
+			` + matchfrom + `
+			WHERE ` + q.where(exceptnone) + `
+		)
+		SELECT
+			a.id,
+			a.name,
+			(
+				SELECT COUNT(*) FROM matched mt
+				WHERE NOT EXISTS (
+					SELECT 1 FROM meal_allergies ma
+					WHERE ma.meal_id = mt.id AND ma.allergy_id = a.id AND ma.deleted_at IS NULL
+				)
+			) AS count
+		FROM allergies a
+		WHERE a.deleted_at IS NULL
+		ORDER BY a.name
+	`
+
+	if err := r.scan(ctx, allergyfree, q, &facets.AllergyFree); err != nil {
+		return nil, err
+	}
+
+	return &facets, nil
+}
+
+// * the names the meal is linked to, joined into the tags of the vector
+const tags = `
+	SELECT
+		m.id,
+		concat_ws(' ',
+			mc.name,
+			p.name,
+			(
+				SELECT string_agg(i.name, ' ' ORDER BY i.name)
+				FROM meal_illnesses mi
+				JOIN illnesses i ON i.id = mi.illness_id AND i.deleted_at IS NULL
+				WHERE mi.meal_id = m.id AND mi.deleted_at IS NULL
+			),
+			(
+				SELECT string_agg(a.name, ' ' ORDER BY a.name)
+				FROM meal_allergies ma
+				JOIN allergies a ON a.id = ma.allergy_id AND a.deleted_at IS NULL
+				WHERE ma.meal_id = m.id AND ma.deleted_at IS NULL
+			)
+		) AS tags
+	FROM meals m
+	LEFT JOIN meal_categories mc ON mc.id = m.meal_category_id AND mc.deleted_at IS NULL
+	LEFT JOIN partners p ON p.id = m.partner_id AND p.deleted_at IS NULL
+	WHERE m.deleted_at IS NULL
+`
+
+func (r *SearchRepository) IndexMeal(ctx context.Context, id uuid.UUID) error {
+	err := r.db.WithContext(ctx).
+		Exec(`
+			UPDATE meals SET search_tags = t.tags
+			FROM (`+tags+` AND m.id = ?) t
+			WHERE meals.id = t.id
+		`, id).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return err
+	}
+
+	return nil
+}
+
+// * only writes the meals whose tags changed, so renaming a partner or a
+// * category reaches their meals on the next run without rewriting the rest
+func (r *SearchRepository) IndexMeals(ctx context.Context) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Exec(`
+			UPDATE meals SET search_tags = t.tags
+			FROM (` + tags + `) t
+			WHERE meals.id = t.id AND meals.search_tags IS DISTINCT FROM t.tags
+		`)
+
+	if err := result.Error; err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return 0, err
+	}
+
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,134 @@
+package securityeventrepo
+
+import (
+	"context"
+	"project-skbackend/internal/controllers/responses"
+	"project-skbackend/internal/models"
+	"project-skbackend/internal/repositories/paginationrepo"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/utils/utlogger"
+	"project-skbackend/packages/utils/utpagination"
+
+	"github.com/google/uuid"
+	"github.com/jinzhu/copier"
+	"gorm.io/gorm"
+)
+
+var (
+	SELECTED_FIELDS = `
+		id,
+		user_id,
+		email,
+		type,
+		ip,
+		user_agent,
+		detail,
+		created_at,
+		updated_at
+	`
+
+	FILTER_FIELDS = paginationrepo.Fields{
+		"user_id":    {Column: "user_id", Type: consttypes.FFT_UUID},
+		"email":      {Column: "email", Type: consttypes.FFT_STRING},
+		"type":       paginationrepo.EnumField("type", consttypes.SecurityEventTypes()...),
+		"ip":         {Column: "ip", Type: consttypes.FFT_STRING},
+		"created_at": {Column: "created_at", Type: consttypes.FFT_TIME},
+	}
+)
+
+type (
+	SecurityEventRepository struct {
+		db *gorm.DB
+	}
+
+	ISecurityEventRepository interface {
+		Create(ctx context.Context, se models.SecurityEvent) (*models.SecurityEvent, error)
+		FindByUserID(ctx context.Context, uid uuid.UUID, p utpagination.Pagination) (*utpagination.Pagination, error)
+		FindAllByUser(ctx context.Context, uid uuid.UUID, email string) ([]*models.SecurityEvent, error)
+	}
+)
+
+func NewSecurityEventRepository(db *gorm.DB) *SecurityEventRepository {
+	return &SecurityEventRepository{db: db}
+}
+
+func (r *SecurityEventRepository) filter(result *gorm.DB, f utpagination.Filter) *gorm.DB {
+	if !f.CreatedFrom.IsZero() {
+		result = result.
+			Where("date(created_at) >= ?", f.CreatedFrom.Format(consttypes.DATEFORMAT))
+	}
+
+	if !f.CreatedTo.IsZero() {
+		result = result.
+			Where("date(created_at) <= ?", f.CreatedTo.Format(consttypes.DATEFORMAT))
+	}
+
+	return result
+}
+
+func (r *SecurityEventRepository) Create(ctx context.Context, se models.SecurityEvent) (*models.SecurityEvent, error) {
+	err := r.db.WithContext(ctx).
+		Create(&se).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return &se, nil
+}
+
+func (r *SecurityEventRepository) FindByUserID(ctx context.Context, uid uuid.UUID, p utpagination.Pagination) (*utpagination.Pagination, error) {
+	var (
+		ses    []models.SecurityEvent
+		sesres []responses.SecurityEvent
+	)
+
+	result := r.db.WithContext(ctx).
+		Model(&ses).
+		Select(SELECTED_FIELDS).
+		Where("user_id = ?", uid)
+
+	result = r.filter(result, p.Filter)
+
+	result = result.
+		Scopes(paginationrepo.PaginateKeyset(&ses, &p, result, FILTER_FIELDS)).
+		Find(&ses)
+
+	if err := result.Error; err != nil {
+		utlogger.ErrorContext(ctx, result.Error)
+		return nil, result.Error
+	}
+
+	if err := paginationrepo.NextCursor(&ses, &p, result, FILTER_FIELDS); err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	// * copy the data from model to response
+	copier.CopyWithOption(&sesres, &ses, copier.Option{IgnoreEmpty: true, DeepCopy: true})
+
+	p.Data = sesres
+	return &p, nil
+}
+
+// * the events of the user include the ones recorded by its email
+// * alone, like the failed signins and the throttled requests
+func (r *SecurityEventRepository) FindAllByUser(ctx context.Context, uid uuid.UUID, email string) ([]*models.SecurityEvent, error) {
+	var (
+		ses []*models.SecurityEvent
+	)
+
+	err := r.db.WithContext(ctx).
+		Select(SELECTED_FIELDS).
+		Where("user_id = ? OR LOWER(email) = LOWER(?)", uid, email).
+		Order("created_at ASC").
+		Find(&ses).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return ses, nil
+}
@@ -0,0 +1,174 @@
+package serviceaccountrepo
+
+import (
+	"context"
+	"project-skbackend/internal/models"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/utils/utlogger"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	SELECTED_FIELDS = `
+		id,
+		user_id,
+		name,
+		description,
+		created_by_id,
+		disabled_at,
+		created_at,
+		updated_at
+	`
+)
+
+type (
+	ServiceAccountRepository struct {
+		db *gorm.DB
+	}
+
+	IServiceAccountRepository interface {
+		Create(ctx context.Context, sa models.ServiceAccount) (*models.ServiceAccount, error)
+		Update(ctx context.Context, sa models.ServiceAccount) (*models.ServiceAccount, error)
+		Delete(ctx context.Context, sa models.ServiceAccount) error
+		Read(ctx context.Context) ([]*models.ServiceAccount, error)
+		GetByID(ctx context.Context, id uuid.UUID) (*models.ServiceAccount, error)
+	}
+)
+
+func NewServiceAccountRepository(db *gorm.DB) *ServiceAccountRepository {
+	return &ServiceAccountRepository{db: db}
+}
+
+func (r *ServiceAccountRepository) preload(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).
+		Preload("Scopes", func(db *gorm.DB) *gorm.DB {
+			return db.Order("scope")
+		})
+}
+
+func (r *ServiceAccountRepository) Create(ctx context.Context, sa models.ServiceAccount) (*models.ServiceAccount, error) {
+	err := r.db.WithContext(ctx).
+		Create(&sa).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	sanew, err := r.GetByID(ctx, sa.ID)
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return sanew, nil
+}
+
+// * the scopes are replaced as a whole, the same way the
+// * role-to-permission mapping is replaced
+func (r *ServiceAccountRepository) Update(ctx context.Context, sa models.ServiceAccount) (*models.ServiceAccount, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		scopes := sa.Scopes
+		for i := range scopes {
+			scopes[i].ServiceAccountID = sa.ID
+		}
+
+		if err := tx.Omit("Scopes", "User").Save(&sa).Error; err != nil {
+			return err
+		}
+
+		err := tx.
+			Unscoped().
+			Where("service_account_id = ?", sa.ID).
+			Delete(&models.ServiceAccountScope{}).Error
+
+		if err != nil {
+			return err
+		}
+
+		if len(scopes) == 0 {
+			return nil
+		}
+
+		return tx.Create(&scopes).Error
+	})
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return r.GetByID(ctx, sa.ID)
+}
+
+// * every key of the account is revoked together with it, so a
+// * leaked key can not outlive the account it was issued for
+func (r *ServiceAccountRepository) Delete(ctx context.Context, sa models.ServiceAccount) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Model(&models.APIKey{}).
+			Where("service_account_id = ? AND revoked_at IS NULL", sa.ID).
+			Update("revoked_at", consttypes.TimeNow()).Error
+
+		if err != nil {
+			return err
+		}
+
+		err = tx.
+			Unscoped().
+			Where("service_account_id = ?", sa.ID).
+			Delete(&models.ServiceAccountScope{}).Error
+
+		if err != nil {
+			return err
+		}
+
+		return tx.Delete(&sa).Error
+	})
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *ServiceAccountRepository) Read(ctx context.Context) ([]*models.ServiceAccount, error) {
+	var (
+		sas []*models.ServiceAccount
+	)
+
+	err := r.
+		preload(ctx).
+		Select(SELECTED_FIELDS).
+		Order("created_at DESC").
+		Find(&sas).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return sas, nil
+}
+
+func (r *ServiceAccountRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.ServiceAccount, error) {
+	var (
+		sa *models.ServiceAccount
+	)
+
+	err := r.
+		preload(ctx).
+		Select(SELECTED_FIELDS).
+		Where("id = ?", id).
+		First(&sa).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return sa, nil
+}
@@ -0,0 +1,195 @@
+package twofactorrepo
+
+import (
+	"context"
+	"project-skbackend/internal/models"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/utils/utlogger"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	SELECTED_FIELDS = `
+		id,
+		user_id,
+		secret,
+		last_used_step,
+		enabled_at,
+		created_at,
+		updated_at
+	`
+)
+
+type (
+	TwoFactorRepository struct {
+		db *gorm.DB
+	}
+
+	ITwoFactorRepository interface {
+		Save(ctx context.Context, tf models.UserTwoFactor) (*models.UserTwoFactor, error)
+		Enable(ctx context.Context, tf models.UserTwoFactor, codehashes []string) (*models.UserTwoFactor, error)
+		Delete(ctx context.Context, uid uuid.UUID) error
+		GetByUserID(ctx context.Context, uid uuid.UUID) (*models.UserTwoFactor, error)
+		UseStep(ctx context.Context, uid uuid.UUID, step int64) (bool, error)
+		ReplaceRecoveryCodes(ctx context.Context, uid uuid.UUID, codehashes []string) error
+		UseRecoveryCode(ctx context.Context, uid uuid.UUID, codehash string) (bool, error)
+		CountRecoveryCodes(ctx context.Context, uid uuid.UUID) (int64, error)
+	}
+)
+
+func NewTwoFactorRepository(db *gorm.DB) *TwoFactorRepository {
+	return &TwoFactorRepository{db: db}
+}
+
+func (r *TwoFactorRepository) Save(ctx context.Context, tf models.UserTwoFactor) (*models.UserTwoFactor, error) {
+	err := r.db.WithContext(ctx).
+		Save(&tf).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return &tf, nil
+}
+
+// * enables the two factor and replaces the recovery codes at once, so
+// * the user never ends up with two factor enabled and no recovery codes
+func (r *TwoFactorRepository) Enable(ctx context.Context, tf models.UserTwoFactor, codehashes []string) (*models.UserTwoFactor, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&tf).Error; err != nil {
+			return err
+		}
+
+		return replaceRecoveryCodes(tx, tf.UserID, codehashes)
+	})
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return &tf, nil
+}
+
+func (r *TwoFactorRepository) Delete(ctx context.Context, uid uuid.UUID) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("user_id = ?", uid).Delete(&models.UserRecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Unscoped().Where("user_id = ?", uid).Delete(&models.UserTwoFactor{}).Error
+	})
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *TwoFactorRepository) GetByUserID(ctx context.Context, uid uuid.UUID) (*models.UserTwoFactor, error) {
+	var (
+		tf *models.UserTwoFactor
+	)
+
+	err := r.db.WithContext(ctx).
+		Select(SELECTED_FIELDS).
+		Where("user_id = ?", uid).
+		First(&tf).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return tf, nil
+}
+
+// * moves the last used step forward, false means the step was
+// * already used so the code is a replay of an accepted one
+func (r *TwoFactorRepository) UseStep(ctx context.Context, uid uuid.UUID, step int64) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.UserTwoFactor{}).
+		Where("user_id = ? AND last_used_step < ?", uid, step).
+		Update("last_used_step", step)
+
+	if result.Error != nil {
+		utlogger.ErrorContext(ctx, result.Error)
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (r *TwoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, uid uuid.UUID, codehashes []string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return replaceRecoveryCodes(tx, uid, codehashes)
+	})
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return err
+	}
+
+	return nil
+}
+
+// * marks the recovery code as used, false means there is
+// * no unused recovery code of the user with the given hash
+func (r *TwoFactorRepository) UseRecoveryCode(ctx context.Context, uid uuid.UUID, codehash string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.UserRecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", uid, codehash).
+		Update("used_at", consttypes.TimeNow())
+
+	if result.Error != nil {
+		utlogger.ErrorContext(ctx, result.Error)
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (r *TwoFactorRepository) CountRecoveryCodes(ctx context.Context, uid uuid.UUID) (int64, error) {
+	var (
+		count int64
+	)
+
+	err := r.db.WithContext(ctx).
+		Model(&models.UserRecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", uid).
+		Count(&count).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func replaceRecoveryCodes(tx *gorm.DB, uid uuid.UUID, codehashes []string) error {
+	var (
+		rcs []*models.UserRecoveryCode
+	)
+
+	if err := tx.Unscoped().Where("user_id = ?", uid).Delete(&models.UserRecoveryCode{}).Error; err != nil {
+		return err
+	}
+
+	for _, codehash := range codehashes {
+		rcs = append(rcs, &models.UserRecoveryCode{
+			UserID:   uid,
+			CodeHash: codehash,
+		})
+	}
+
+	if len(rcs) == 0 {
+		return nil
+	}
+
+	return tx.Create(&rcs).Error
+}
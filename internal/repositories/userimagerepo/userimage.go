@@ -1,6 +1,7 @@
 package userimagerepo
 
 import (
+	"context"
 	"project-skbackend/internal/models"
 	"project-skbackend/internal/models/base"
 	"project-skbackend/packages/utils/utlogger"
@@ -26,12 +27,12 @@ type (
 	}
 
 	IUserImageRepo interface {
-		Create(ui models.UserImage) (*models.UserImage, error)
-		Read() ([]*models.UserImage, error)
-		Update(ui models.UserImage) (*models.UserImage, error)
-		Delete(ui models.UserImage) error
-		GetByID(id uuid.UUID) (*models.UserImage, error)
-		GetByUserID(uid uuid.UUID) (*models.UserImage, error)
+		Create(ctx context.Context, ui models.UserImage) (*models.UserImage, error)
+		Read(ctx context.Context) ([]*models.UserImage, error)
+		Update(ctx context.Context, ui models.UserImage) (*models.UserImage, error)
+		Delete(ctx context.Context, ui models.UserImage) error
+		GetByID(ctx context.Context, id uuid.UUID) (*models.UserImage, error)
+		GetByUserID(ctx context.Context, uid uuid.UUID) (*models.UserImage, error)
 	}
 )
 
@@ -39,121 +40,121 @@ func NewUserImageRepository(db *gorm.DB) *UserImageRepository {
 	return &UserImageRepository{db: db}
 }
 
-func (r *UserImageRepository) omit() *gorm.DB {
-	return r.db.Omit(
+func (r *UserImageRepository) omit(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Omit(
 		"",
 	)
 }
 
-func (r *UserImageRepository) preload() *gorm.DB {
-	return r.db.
+func (r *UserImageRepository) preload(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).
 		Preload(clause.Associations)
 }
 
-func (r *UserImageRepository) Create(ui models.UserImage) (*models.UserImage, error) {
+func (r *UserImageRepository) Create(ctx context.Context, ui models.UserImage) (*models.UserImage, error) {
 	err := r.
-		omit().
+		omit(ctx).
 		Session(&gorm.Session{FullSaveAssociations: true}).
 		Create(&ui).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
-	uinew, err := r.GetByID(ui.ID)
+	uinew, err := r.GetByID(ctx, ui.ID)
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return uinew, nil
 }
 
-func (r *UserImageRepository) Read() ([]*models.UserImage, error) {
+func (r *UserImageRepository) Read(ctx context.Context) ([]*models.UserImage, error) {
 	var (
 		ui []*models.UserImage
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Find(&ui).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return ui, nil
 }
 
-func (r *UserImageRepository) Update(ui models.UserImage) (*models.UserImage, error) {
+func (r *UserImageRepository) Update(ctx context.Context, ui models.UserImage) (*models.UserImage, error) {
 	err := r.
-		omit().
+		omit(ctx).
 		Session(&gorm.Session{FullSaveAssociations: true}).
 		Save(&ui).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
-	uinew, err := r.GetByID(ui.ID)
+	uinew, err := r.GetByID(ctx, ui.ID)
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return uinew, nil
 }
 
-func (r *UserImageRepository) Delete(ui models.UserImage) error {
-	err := r.db.
+func (r *UserImageRepository) Delete(ctx context.Context, ui models.UserImage) error {
+	err := r.db.WithContext(ctx).
 		Delete(&ui).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return err
 	}
 
 	return nil
 }
 
-func (r *UserImageRepository) GetByID(id uuid.UUID) (*models.UserImage, error) {
+func (r *UserImageRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.UserImage, error) {
 	var (
 		ui *models.UserImage
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Where(&models.UserImage{Model: base.Model{ID: id}}).
 		First(&ui).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return ui, nil
 }
 
-func (r *UserImageRepository) GetByUserID(uid uuid.UUID) (*models.UserImage, error) {
+func (r *UserImageRepository) GetByUserID(ctx context.Context, uid uuid.UUID) (*models.UserImage, error) {
 	var (
 		ui *models.UserImage
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Where(&models.UserImage{UserID: uid}).
 		First(&ui).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
@@ -1,6 +1,7 @@
 package userrepo
 
 import (
+	"context"
 	"fmt"
 	"project-skbackend/internal/controllers/responses"
 	"project-skbackend/internal/models"
@@ -31,6 +32,14 @@ var (
 		created_at,
 		updated_at
 	`
+
+	FILTER_FIELDS = paginationrepo.Fields{
+		"email":        {Column: "email", Type: consttypes.FFT_STRING},
+		"role":         {Column: "role", Type: consttypes.FFT_STRING},
+		"confirmed_at": {Column: "confirmed_at", Type: consttypes.FFT_TIME},
+		"created_at":   {Column: "created_at", Type: consttypes.FFT_TIME},
+		"updated_at":   {Column: "updated_at", Type: consttypes.FFT_TIME},
+	}
 )
 
 type (
@@ -39,16 +48,17 @@ type (
 	}
 
 	IUserRepository interface {
-		Create(u models.User) (*models.User, error)
-		Read() ([]*models.User, error)
-		Update(u models.User) (*models.User, error)
-		Delete(u models.User) error
-		FindAll(p utpagination.Pagination) (*utpagination.Pagination, error)
-		GetByID(id uuid.UUID) (*models.User, error)
-		GetByEmail(email string) (*models.User, error)
-		FirstOrCreate(u models.User) (*models.User, error)
-
-		UpdatePassword(id uuid.UUID, password string) (*models.User, error)
+		Create(ctx context.Context, u models.User) (*models.User, error)
+		Read(ctx context.Context) ([]*models.User, error)
+		Update(ctx context.Context, u models.User) (*models.User, error)
+		Delete(ctx context.Context, u models.User) error
+		FindAll(ctx context.Context, p utpagination.Pagination) (*utpagination.Pagination, error)
+		GetByID(ctx context.Context, id uuid.UUID) (*models.User, error)
+		GetByIDUnscoped(ctx context.Context, id uuid.UUID) (*models.User, error)
+		GetByEmail(ctx context.Context, email string) (*models.User, error)
+		FirstOrCreate(ctx context.Context, u models.User) (*models.User, error)
+
+		UpdatePassword(ctx context.Context, id uuid.UUID, password string) (*models.User, error)
 	}
 )
 
@@ -56,89 +66,89 @@ func NewUserRepository(db *gorm.DB) *UserRepository {
 	return &UserRepository{db: db}
 }
 
-func (r *UserRepository) preload() *gorm.DB {
-	return r.db.
+func (r *UserRepository) preload(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).
 		Preload(clause.Associations).
 		Preload("Addresses").
 		Preload("Image.Image")
 }
 
-func (r *UserRepository) Create(u models.User) (*models.User, error) {
-	err := r.db.
+func (r *UserRepository) Create(ctx context.Context, u models.User) (*models.User, error) {
+	err := r.db.WithContext(ctx).
 		Create(&u).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
-	unew, err := r.GetByID(u.ID)
+	unew, err := r.GetByID(ctx, u.ID)
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return unew, nil
 }
 
-func (r *UserRepository) Read() ([]*models.User, error) {
+func (r *UserRepository) Read(ctx context.Context) ([]*models.User, error) {
 	var (
 		u []*models.User
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
 		Find(&u).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return u, nil
 }
 
-func (r *UserRepository) Update(u models.User) (*models.User, error) {
-	err := r.db.
+func (r *UserRepository) Update(ctx context.Context, u models.User) (*models.User, error) {
+	err := r.db.WithContext(ctx).
 		Save(&u).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
-	unew, err := r.GetByID(u.ID)
+	unew, err := r.GetByID(ctx, u.ID)
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return unew, nil
 }
 
-func (r *UserRepository) Delete(u models.User) error {
-	err := r.db.
+func (r *UserRepository) Delete(ctx context.Context, u models.User) error {
+	err := r.db.WithContext(ctx).
 		Delete(&u).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return err
 	}
 
 	return nil
 }
 
-func (r *UserRepository) FindAll(p utpagination.Pagination) (*utpagination.Pagination, error) {
+func (r *UserRepository) FindAll(ctx context.Context, p utpagination.Pagination) (*utpagination.Pagination, error) {
 	var (
 		u    []models.User
 		ures []responses.User
 	)
 
 	result := r.
-		preload().
+		preload(ctx).
 		Model(&u).
 		Select(SELECTED_FIELDS)
 
@@ -146,7 +156,7 @@ func (r *UserRepository) FindAll(p utpagination.Pagination) (*utpagination.Pagin
 		p.Search = fmt.Sprintf("%%%s%%", p.Search)
 		result = result.
 			Where(
-				r.db.Where(`
+				r.db.WithContext(ctx).Where(`
 					email ILIKE ?
 		`, p.Search),
 			)
@@ -162,11 +172,11 @@ func (r *UserRepository) FindAll(p utpagination.Pagination) (*utpagination.Pagin
 
 	result = result.
 		Group("id").
-		Scopes(paginationrepo.Paginate(&u, &p, result)).
+		Scopes(paginationrepo.Paginate(&u, &p, result, FILTER_FIELDS)).
 		Find(&u)
 
 	if err := result.Error; err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
@@ -177,75 +187,98 @@ func (r *UserRepository) FindAll(p utpagination.Pagination) (*utpagination.Pagin
 	return &p, nil
 }
 
-func (r *UserRepository) GetByID(id uuid.UUID) (*models.User, error) {
+func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	var (
+		u *models.User
+	)
+
+	err := r.
+		preload(ctx).
+		Select(SELECTED_FIELDS).
+		Where(&models.User{Model: base.Model{ID: id}}).
+		First(&u).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return u, nil
+}
+
+// * also finds the soft deleted user, whose data still has to be erased
+func (r *UserRepository) GetByIDUnscoped(ctx context.Context, id uuid.UUID) (*models.User, error) {
 	var (
 		u *models.User
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
+		Unscoped().
 		Select(SELECTED_FIELDS).
 		Where(&models.User{Model: base.Model{ID: id}}).
 		First(&u).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return u, nil
 }
 
-func (r *UserRepository) GetByEmail(email string) (*models.User, error) {
+// * the email is compared case insensitively, the accounts made before
+// * the emails were lowercased can still be found by their email
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
 	var (
 		u *models.User
 	)
 
 	err := r.
-		preload().
+		preload(ctx).
 		Select(SELECTED_FIELDS).
-		Where(&models.User{Email: email}).
+		Where("LOWER(email) = LOWER(?)", email).
 		First(&u).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return u, nil
 }
 
-func (r *UserRepository) FirstOrCreate(u models.User) (*models.User, error) {
-	err := r.db.
+func (r *UserRepository) FirstOrCreate(ctx context.Context, u models.User) (*models.User, error) {
+	err := r.db.WithContext(ctx).
 		FirstOrCreate(&u, u).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return &u, nil
 }
 
-func (r *UserRepository) UpdatePassword(id uuid.UUID, password string) (*models.User, error) {
+func (r *UserRepository) UpdatePassword(ctx context.Context, id uuid.UUID, password string) (*models.User, error) {
 	password, err := utstring.HashPassword(password)
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
-	err = r.db.
+	err = r.db.WithContext(ctx).
 		Model(&models.User{Model: base.Model{ID: id}}).
 		Update("password", password).Error
 
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
-	u, err := r.GetByID(id)
+	u, err := r.GetByID(ctx, id)
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
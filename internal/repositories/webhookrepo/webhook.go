@@ -0,0 +1,309 @@
+package webhookrepo
+
+import (
+	"context"
+	"project-skbackend/internal/controllers/responses"
+	"project-skbackend/internal/models"
+	"project-skbackend/internal/repositories/paginationrepo"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/utils/utlogger"
+	"project-skbackend/packages/utils/utpagination"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jinzhu/copier"
+	"gorm.io/gorm"
+)
+
+var (
+	SELECTED_FIELDS = `
+		id,
+		user_id,
+		url,
+		description,
+		event_types,
+		secret,
+		active,
+		created_at,
+		updated_at
+	`
+
+	DELIVERY_SELECTED_FIELDS = `
+		id,
+		webhook_id,
+		event_id,
+		event_type,
+		payload,
+		status,
+		attempts,
+		next_attempt_at,
+		response_status,
+		response_body,
+		error,
+		duration,
+		delivered_at,
+		created_at,
+		updated_at
+	`
+
+	DELIVERY_FILTER_FIELDS = paginationrepo.Fields{
+		"event_id":        {Column: "event_id", Type: consttypes.FFT_UUID},
+		"event_type":      {Column: "event_type", Type: consttypes.FFT_STRING},
+		"status":          paginationrepo.EnumField("status", consttypes.WDS_PENDING, consttypes.WDS_RETRYING, consttypes.WDS_DELIVERED, consttypes.WDS_FAILED),
+		"attempts":        {Column: "attempts", Type: consttypes.FFT_NUMBER},
+		"response_status": {Column: "response_status", Type: consttypes.FFT_NUMBER},
+		"next_attempt_at": {Column: "next_attempt_at", Type: consttypes.FFT_TIME},
+		"delivered_at":    {Column: "delivered_at", Type: consttypes.FFT_TIME},
+		"created_at":      {Column: "created_at", Type: consttypes.FFT_TIME},
+		"updated_at":      {Column: "updated_at", Type: consttypes.FFT_TIME},
+	}
+)
+
+type (
+	WebhookRepository struct {
+		db *gorm.DB
+	}
+
+	IWebhookRepository interface {
+		// * webhooks
+		Create(ctx context.Context, wh models.Webhook) (*models.Webhook, error)
+		Update(ctx context.Context, wh models.Webhook) (*models.Webhook, error)
+		Delete(ctx context.Context, wh models.Webhook) error
+		GetByID(ctx context.Context, id uuid.UUID) (*models.Webhook, error)
+		FindByUserID(ctx context.Context, uid uuid.UUID) ([]*models.Webhook, error)
+		FindActiveByUserIDs(ctx context.Context, uids []uuid.UUID) ([]*models.Webhook, error)
+
+		// * deliveries
+		CreateDelivery(ctx context.Context, wd models.WebhookDelivery) (*models.WebhookDelivery, error)
+		UpdateDelivery(ctx context.Context, wd models.WebhookDelivery) (*models.WebhookDelivery, error)
+		GetDeliveryByID(ctx context.Context, id uuid.UUID) (*models.WebhookDelivery, error)
+		FindDeliveriesByWebhookID(ctx context.Context, wid uuid.UUID, p utpagination.Pagination) (*utpagination.Pagination, error)
+		FindDueRetries(ctx context.Context, limit int) ([]*models.WebhookDelivery, error)
+		RequeueDelivery(ctx context.Context, id uuid.UUID) (bool, error)
+	}
+)
+
+func NewWebhookRepository(db *gorm.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+// ! -------------------------------- webhooks -------------------------------- ! //
+func (r *WebhookRepository) Create(ctx context.Context, wh models.Webhook) (*models.Webhook, error) {
+	err := r.db.WithContext(ctx).
+		Create(&wh).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return &wh, nil
+}
+
+func (r *WebhookRepository) Update(ctx context.Context, wh models.Webhook) (*models.Webhook, error) {
+	err := r.db.WithContext(ctx).
+		Save(&wh).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return &wh, nil
+}
+
+// * the deliveries are kept as the log of the webhook, the
+// * pending ones fail once the consumer finds the webhook gone
+func (r *WebhookRepository) Delete(ctx context.Context, wh models.Webhook) error {
+	err := r.db.WithContext(ctx).
+		Delete(&wh).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *WebhookRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Webhook, error) {
+	var (
+		wh *models.Webhook
+	)
+
+	err := r.db.WithContext(ctx).
+		Select(SELECTED_FIELDS).
+		Where("id = ?", id).
+		First(&wh).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return wh, nil
+}
+
+func (r *WebhookRepository) FindByUserID(ctx context.Context, uid uuid.UUID) ([]*models.Webhook, error) {
+	var (
+		whs []*models.Webhook
+	)
+
+	err := r.db.WithContext(ctx).
+		Select(SELECTED_FIELDS).
+		Where("user_id = ?", uid).
+		Order("created_at DESC").
+		Find(&whs).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return whs, nil
+}
+
+func (r *WebhookRepository) FindActiveByUserIDs(ctx context.Context, uids []uuid.UUID) ([]*models.Webhook, error) {
+	var (
+		whs []*models.Webhook
+	)
+
+	err := r.db.WithContext(ctx).
+		Select(SELECTED_FIELDS).
+		Where("user_id IN ? AND active = ?", uids, true).
+		Find(&whs).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return whs, nil
+}
+
+// ! ------------------------------- deliveries ------------------------------- ! //
+func (r *WebhookRepository) CreateDelivery(ctx context.Context, wd models.WebhookDelivery) (*models.WebhookDelivery, error) {
+	err := r.db.WithContext(ctx).
+		Omit("Webhook").
+		Create(&wd).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return &wd, nil
+}
+
+func (r *WebhookRepository) UpdateDelivery(ctx context.Context, wd models.WebhookDelivery) (*models.WebhookDelivery, error) {
+	err := r.db.WithContext(ctx).
+		Omit("Webhook").
+		Save(&wd).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return &wd, nil
+}
+
+// * the webhook is loaded with the delivery, it is left empty
+// * when the webhook was deleted after the delivery was created
+func (r *WebhookRepository) GetDeliveryByID(ctx context.Context, id uuid.UUID) (*models.WebhookDelivery, error) {
+	var (
+		wd *models.WebhookDelivery
+	)
+
+	err := r.db.WithContext(ctx).
+		Preload("Webhook").
+		Select(DELIVERY_SELECTED_FIELDS).
+		Where("id = ?", id).
+		First(&wd).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return wd, nil
+}
+
+func (r *WebhookRepository) FindDeliveriesByWebhookID(ctx context.Context, wid uuid.UUID, p utpagination.Pagination) (*utpagination.Pagination, error) {
+	var (
+		wds    []models.WebhookDelivery
+		wdsres []responses.WebhookDelivery
+	)
+
+	result := r.db.WithContext(ctx).
+		Model(&wds).
+		Select(DELIVERY_SELECTED_FIELDS).
+		Where("webhook_id = ?", wid)
+
+	if !p.Filter.CreatedFrom.IsZero() {
+		result = result.
+			Where("date(created_at) >= ?", p.Filter.CreatedFrom.Format(consttypes.DATEFORMAT))
+	}
+
+	if !p.Filter.CreatedTo.IsZero() {
+		result = result.
+			Where("date(created_at) <= ?", p.Filter.CreatedTo.Format(consttypes.DATEFORMAT))
+	}
+
+	result = result.
+		Scopes(paginationrepo.PaginateKeyset(&wds, &p, result, DELIVERY_FILTER_FIELDS)).
+		Find(&wds)
+
+	if err := result.Error; err != nil {
+		utlogger.ErrorContext(ctx, result.Error)
+		return nil, result.Error
+	}
+
+	if err := paginationrepo.NextCursor(&wds, &p, result, DELIVERY_FILTER_FIELDS); err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	// * copy the data from model to response
+	copier.CopyWithOption(&wdsres, &wds, copier.Option{IgnoreEmpty: true, DeepCopy: true})
+
+	p.Data = wdsres
+	return &p, nil
+}
+
+func (r *WebhookRepository) FindDueRetries(ctx context.Context, limit int) ([]*models.WebhookDelivery, error) {
+	var (
+		wds []*models.WebhookDelivery
+	)
+
+	err := r.db.WithContext(ctx).
+		Select(DELIVERY_SELECTED_FIELDS).
+		Where("status = ? AND next_attempt_at <= ?", consttypes.WDS_RETRYING, consttypes.TimeNow()).
+		Order("next_attempt_at ASC").
+		Limit(limit).
+		Find(&wds).Error
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return wds, nil
+}
+
+// * moves a retrying delivery back to pending, false means another
+// * instance already requeued it so it must not be published twice
+func (r *WebhookRepository) RequeueDelivery(ctx context.Context, id uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.WebhookDelivery{}).
+		Where("id = ? AND status = ?", id, consttypes.WDS_RETRYING).
+		Updates(map[string]any{
+			"status":          consttypes.WDS_PENDING,
+			"next_attempt_at": (*time.Time)(nil),
+		})
+
+	if result.Error != nil {
+		utlogger.ErrorContext(ctx, result.Error)
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
@@ -1,6 +1,7 @@
 package allergyservice
 
 import (
+	"context"
 	"project-skbackend/internal/controllers/requests"
 	"project-skbackend/internal/controllers/responses"
 	"project-skbackend/internal/repositories/allergyrepo"
@@ -15,12 +16,12 @@ type (
 	}
 
 	IAllergyService interface {
-		Create(req requests.CreateAllergy) (*responses.Allergy, error)
-		Read() ([]*responses.Allergy, error)
-		Update(req requests.UpdateAllergy, alid uuid.UUID) (*responses.Allergy, error)
-		Delete(alid uuid.UUID) error
-		FindAll(p utpagination.Pagination) (*utpagination.Pagination, error)
-		GetByID(alid uuid.UUID) (*responses.Allergy, error)
+		Create(ctx context.Context, req requests.CreateAllergy) (*responses.Allergy, error)
+		Read(ctx context.Context) ([]*responses.Allergy, error)
+		Update(ctx context.Context, req requests.UpdateAllergy, alid uuid.UUID) (*responses.Allergy, error)
+		Delete(ctx context.Context, alid uuid.UUID) error
+		FindAll(ctx context.Context, p utpagination.Pagination) (*utpagination.Pagination, error)
+		GetByID(ctx context.Context, alid uuid.UUID) (*responses.Allergy, error)
 	}
 )
 
@@ -32,13 +33,13 @@ func NewAllergyService(
 	}
 }
 
-func (s *AllergyService) Create(req requests.CreateAllergy) (*responses.Allergy, error) {
+func (s *AllergyService) Create(ctx context.Context, req requests.CreateAllergy) (*responses.Allergy, error) {
 	var (
 		err     error
 		allergy = req.ToModel()
 	)
 
-	allergy, err = s.ralle.Create(*allergy)
+	allergy, err = s.ralle.Create(ctx, *allergy)
 	if err != nil {
 		return nil, err
 	}
@@ -51,13 +52,13 @@ func (s *AllergyService) Create(req requests.CreateAllergy) (*responses.Allergy,
 	return allergyres, nil
 }
 
-func (s *AllergyService) Read() ([]*responses.Allergy, error) {
+func (s *AllergyService) Read(ctx context.Context) ([]*responses.Allergy, error) {
 	var (
 		allreses []*responses.Allergy
 		err      error
 	)
 
-	allergies, err := s.ralle.Read()
+	allergies, err := s.ralle.Read(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -74,12 +75,12 @@ func (s *AllergyService) Read() ([]*responses.Allergy, error) {
 	return allreses, nil
 }
 
-func (s *AllergyService) Update(req requests.UpdateAllergy, alid uuid.UUID) (*responses.Allergy, error) {
+func (s *AllergyService) Update(ctx context.Context, req requests.UpdateAllergy, alid uuid.UUID) (*responses.Allergy, error) {
 	var (
 		err error
 	)
 
-	allergy, err := s.ralle.GetByID(alid)
+	allergy, err := s.ralle.GetByID(ctx, alid)
 	if err != nil {
 		return nil, err
 	}
@@ -89,7 +90,7 @@ func (s *AllergyService) Update(req requests.UpdateAllergy, alid uuid.UUID) (*re
 		return nil, err
 	}
 
-	allergy, err = s.ralle.Update(*allergy)
+	allergy, err = s.ralle.Update(ctx, *allergy)
 	if err != nil {
 		return nil, err
 	}
@@ -102,17 +103,17 @@ func (s *AllergyService) Update(req requests.UpdateAllergy, alid uuid.UUID) (*re
 	return allergyres, nil
 }
 
-func (s *AllergyService) Delete(alid uuid.UUID) error {
-	allergy, err := s.ralle.GetByID(alid)
+func (s *AllergyService) Delete(ctx context.Context, alid uuid.UUID) error {
+	allergy, err := s.ralle.GetByID(ctx, alid)
 	if err != nil {
 		return err
 	}
 
-	return s.ralle.Delete(*allergy)
+	return s.ralle.Delete(ctx, *allergy)
 }
 
-func (s *AllergyService) FindAll(p utpagination.Pagination) (*utpagination.Pagination, error) {
-	allergies, err := s.ralle.FindAll(p)
+func (s *AllergyService) FindAll(ctx context.Context, p utpagination.Pagination) (*utpagination.Pagination, error) {
+	allergies, err := s.ralle.FindAll(ctx, p)
 	if err != nil {
 		return nil, err
 	}
@@ -120,8 +121,8 @@ func (s *AllergyService) FindAll(p utpagination.Pagination) (*utpagination.Pagin
 	return allergies, nil
 }
 
-func (s *AllergyService) GetByID(alid uuid.UUID) (*responses.Allergy, error) {
-	allergy, err := s.ralle.GetByID(alid)
+func (s *AllergyService) GetByID(ctx context.Context, alid uuid.UUID) (*responses.Allergy, error) {
+	allergy, err := s.ralle.GetByID(ctx, alid)
 	if err != nil {
 		return nil, err
 	}
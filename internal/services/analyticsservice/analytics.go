@@ -0,0 +1,361 @@
+package analyticsservice
+
+import (
+	"context"
+	"math"
+	"project-skbackend/configs"
+	"project-skbackend/internal/controllers/requests"
+	"project-skbackend/internal/controllers/responses"
+	"project-skbackend/internal/repositories/analyticsrepo"
+	"project-skbackend/internal/repositories/partnerrepo"
+	"project-skbackend/packages/consttypes"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	AnalyticsService struct {
+		cfg *configs.Config
+
+		// * repository
+		ranly analyticsrepo.IAnalyticsRepository
+		rpart partnerrepo.IPartnerRepository
+	}
+
+	IAnalyticsService interface {
+		Orders(ctx context.Context, req requests.AnalyticsRange) ([]responses.AnalyticsOrders, error)
+		Cancellations(ctx context.Context, req requests.AnalyticsRange) (*responses.AnalyticsCancellations, error)
+		TopMeals(ctx context.Context, req requests.AnalyticsRange) ([]responses.AnalyticsTopMeal, error)
+		TopPartners(ctx context.Context, req requests.AnalyticsRange) ([]responses.AnalyticsTopPartner, error)
+		ActiveMembers(ctx context.Context, req requests.AnalyticsRange) ([]responses.AnalyticsActiveMembers, error)
+		Donations(ctx context.Context, req requests.AnalyticsRange) ([]responses.AnalyticsDonations, error)
+		PrepTime(ctx context.Context, req requests.AnalyticsRange) ([]responses.AnalyticsPrepTime, error)
+
+		// * partner
+		PartnerKitchen(ctx context.Context, uid uuid.UUID, req requests.AnalyticsRange) ([]responses.AnalyticsKitchen, error)
+		PartnerOrdersByHour(ctx context.Context, uid uuid.UUID, req requests.AnalyticsRange) ([]responses.AnalyticsHourlyOrders, error)
+		PartnerTopMeals(ctx context.Context, uid uuid.UUID, req requests.AnalyticsRange) ([]responses.AnalyticsTopMeal, error)
+		PartnerRatings(ctx context.Context, uid uuid.UUID, req requests.AnalyticsRange) (*responses.AnalyticsRatings, error)
+		PartnerPrepList(ctx context.Context, uid uuid.UUID) (*responses.AnalyticsPrepList, error)
+	}
+)
+
+func NewAnalyticsService(
+	cfg *configs.Config,
+	// * repository
+	ranly analyticsrepo.IAnalyticsRepository,
+	rpart partnerrepo.IPartnerRepository,
+) *AnalyticsService {
+	return &AnalyticsService{
+		cfg: cfg,
+
+		// * repository
+		ranly: ranly,
+		rpart: rpart,
+	}
+}
+
+func (s *AnalyticsService) toRange(req requests.AnalyticsRange) (analyticsrepo.Range, error) {
+	loc, err := time.LoadLocation(s.cfg.API.Timezone)
+	if err != nil {
+		return analyticsrepo.Range{}, err
+	}
+
+	from, to, err := req.Resolve(loc, s.cfg.Analytics.DefaultRange, s.cfg.Analytics.MaxRange)
+	if err != nil {
+		return analyticsrepo.Range{}, err
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = s.cfg.Analytics.TopLimit
+	}
+
+	return analyticsrepo.Range{
+		From:     from,
+		To:       to,
+		Bucket:   req.Bucket,
+		Timezone: s.cfg.API.Timezone,
+		Limit:    limit,
+	}, nil
+}
+
+func (s *AnalyticsService) Orders(ctx context.Context, req requests.AnalyticsRange) ([]responses.AnalyticsOrders, error) {
+	var (
+		ordsres []responses.AnalyticsOrders
+	)
+
+	rg, err := s.toRange(req)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.ranly.OrdersByStatus(ctx, rg)
+	if err != nil {
+		return nil, consttypes.ErrFailedToReadAnalytics.Wrap(err)
+	}
+
+	// * the rows are ordered by bucket, so a bucket ends
+	// * once a row of the next one is reached
+	for _, row := range rows {
+		if len(ordsres) == 0 || ordsres[len(ordsres)-1].Bucket != row.Bucket {
+			ordsres = append(ordsres, responses.AnalyticsOrders{
+				Bucket:   row.Bucket,
+				Statuses: map[consttypes.OrderStatus]int64{},
+			})
+		}
+
+		last := &ordsres[len(ordsres)-1]
+		last.Total += row.Count
+		last.Statuses[row.Status] = row.Count
+	}
+
+	return ordsres, nil
+}
+
+func (s *AnalyticsService) Cancellations(ctx context.Context, req requests.AnalyticsRange) (*responses.AnalyticsCancellations, error) {
+	rg, err := s.toRange(req)
+	if err != nil {
+		return nil, err
+	}
+
+	buckets, err := s.ranly.Cancellations(ctx, rg)
+	if err != nil {
+		return nil, consttypes.ErrFailedToReadAnalytics.Wrap(err)
+	}
+
+	causes, err := s.ranly.CancellationCauses(ctx, rg)
+	if err != nil {
+		return nil, consttypes.ErrFailedToReadAnalytics.Wrap(err)
+	}
+
+	cnres := responses.AnalyticsCancellations{
+		Buckets: buckets,
+		Causes:  causes,
+	}
+
+	for _, b := range buckets {
+		cnres.Total += b.Total
+		cnres.Cancelled += b.Cancelled
+	}
+
+	if cnres.Total > 0 {
+		cnres.Rate = float64(cnres.Cancelled) / float64(cnres.Total)
+	}
+
+	return &cnres, nil
+}
+
+func (s *AnalyticsService) TopMeals(ctx context.Context, req requests.AnalyticsRange) ([]responses.AnalyticsTopMeal, error) {
+	rg, err := s.toRange(req)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.ranly.TopMeals(ctx, rg)
+	if err != nil {
+		return nil, consttypes.ErrFailedToReadAnalytics.Wrap(err)
+	}
+
+	return rows, nil
+}
+
+func (s *AnalyticsService) TopPartners(ctx context.Context, req requests.AnalyticsRange) ([]responses.AnalyticsTopPartner, error) {
+	rg, err := s.toRange(req)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.ranly.TopPartners(ctx, rg)
+	if err != nil {
+		return nil, consttypes.ErrFailedToReadAnalytics.Wrap(err)
+	}
+
+	return rows, nil
+}
+
+func (s *AnalyticsService) ActiveMembers(ctx context.Context, req requests.AnalyticsRange) ([]responses.AnalyticsActiveMembers, error) {
+	rg, err := s.toRange(req)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.ranly.ActiveMembers(ctx, rg)
+	if err != nil {
+		return nil, consttypes.ErrFailedToReadAnalytics.Wrap(err)
+	}
+
+	return rows, nil
+}
+
+func (s *AnalyticsService) Donations(ctx context.Context, req requests.AnalyticsRange) ([]responses.AnalyticsDonations, error) {
+	rg, err := s.toRange(req)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.ranly.Donations(ctx, rg)
+	if err != nil {
+		return nil, consttypes.ErrFailedToReadAnalytics.Wrap(err)
+	}
+
+	return rows, nil
+}
+
+func (s *AnalyticsService) PrepTime(ctx context.Context, req requests.AnalyticsRange) ([]responses.AnalyticsPrepTime, error) {
+	rg, err := s.toRange(req)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.ranly.PrepTime(ctx, rg)
+	if err != nil {
+		return nil, consttypes.ErrFailedToReadAnalytics.Wrap(err)
+	}
+
+	return rows, nil
+}
+
+// * scopes the range to the partner of the user, so a partner
+// * only ever reads the analytics of its own kitchen
+func (s *AnalyticsService) toPartnerRange(ctx context.Context, uid uuid.UUID, req requests.AnalyticsRange) (analyticsrepo.Range, error) {
+	rg, err := s.toRange(req)
+	if err != nil {
+		return analyticsrepo.Range{}, err
+	}
+
+	partner, err := s.rpart.GetByUserID(ctx, uid)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return analyticsrepo.Range{}, consttypes.ErrPartnerNotFound.Wrap(err)
+		}
+
+		return analyticsrepo.Range{}, consttypes.ErrFailedToReadAnalytics.Wrap(err)
+	}
+
+	rg.PartnerID = &partner.ID
+	return rg, nil
+}
+
+func (s *AnalyticsService) PartnerKitchen(ctx context.Context, uid uuid.UUID, req requests.AnalyticsRange) ([]responses.AnalyticsKitchen, error) {
+	rg, err := s.toPartnerRange(ctx, uid, req)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.ranly.KitchenTimes(ctx, rg)
+	if err != nil {
+		return nil, consttypes.ErrFailedToReadAnalytics.Wrap(err)
+	}
+
+	return rows, nil
+}
+
+// * every hour of the day is returned, the hours without
+// * orders are filled with zero
+func (s *AnalyticsService) PartnerOrdersByHour(ctx context.Context, uid uuid.UUID, req requests.AnalyticsRange) ([]responses.AnalyticsHourlyOrders, error) {
+	rg, err := s.toPartnerRange(ctx, uid, req)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.ranly.OrdersByHour(ctx, rg)
+	if err != nil {
+		return nil, consttypes.ErrFailedToReadAnalytics.Wrap(err)
+	}
+
+	hoursres := make([]responses.AnalyticsHourlyOrders, 24)
+	for hour := range hoursres {
+		hoursres[hour].Hour = hour
+	}
+
+	for _, row := range rows {
+		if row.Hour >= 0 && row.Hour < len(hoursres) {
+			hoursres[row.Hour] = row
+		}
+	}
+
+	return hoursres, nil
+}
+
+func (s *AnalyticsService) PartnerTopMeals(ctx context.Context, uid uuid.UUID, req requests.AnalyticsRange) ([]responses.AnalyticsTopMeal, error) {
+	rg, err := s.toPartnerRange(ctx, uid, req)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.ranly.TopMeals(ctx, rg)
+	if err != nil {
+		return nil, consttypes.ErrFailedToReadAnalytics.Wrap(err)
+	}
+
+	return rows, nil
+}
+
+func (s *AnalyticsService) PartnerRatings(ctx context.Context, uid uuid.UUID, req requests.AnalyticsRange) (*responses.AnalyticsRatings, error) {
+	rg, err := s.toPartnerRange(ctx, uid, req)
+	if err != nil {
+		return nil, err
+	}
+
+	meals, err := s.ranly.MealRatings(ctx, rg)
+	if err != nil {
+		return nil, consttypes.ErrFailedToReadAnalytics.Wrap(err)
+	}
+
+	rtres := responses.AnalyticsRatings{
+		Meals: meals,
+	}
+
+	var (
+		sum float64
+	)
+
+	for _, meal := range meals {
+		rtres.Ratings += meal.Ratings
+		sum += meal.Average * float64(meal.Ratings)
+	}
+
+	if rtres.Ratings > 0 {
+		rtres.Average = math.Round(sum/float64(rtres.Ratings)*100) / 100
+	}
+
+	return &rtres, nil
+}
+
+// * the prep list always covers today in the api timezone
+func (s *AnalyticsService) PartnerPrepList(ctx context.Context, uid uuid.UUID) (*responses.AnalyticsPrepList, error) {
+	loc, err := time.LoadLocation(s.cfg.API.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	today := time.Now().In(loc).Format(consttypes.DATEFORMAT)
+
+	rg, err := s.toPartnerRange(ctx, uid, requests.AnalyticsRange{
+		From: today,
+		To:   today,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	meals, err := s.ranly.PrepList(ctx, rg)
+	if err != nil {
+		return nil, consttypes.ErrFailedToReadAnalytics.Wrap(err)
+	}
+
+	plres := responses.AnalyticsPrepList{
+		Date:  today,
+		Meals: meals,
+	}
+
+	for _, meal := range meals {
+		plres.Orders += meal.Orders
+		plres.Quantity += meal.Quantity
+	}
+
+	return &plres, nil
+}
@@ -0,0 +1,309 @@
+package auditservice
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"project-skbackend/configs"
+	"project-skbackend/internal/controllers/responses"
+	"project-skbackend/internal/models"
+	"project-skbackend/internal/repositories/auditlogrepo"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/utils/utcrypto"
+	"project-skbackend/packages/utils/utlogger"
+	"project-skbackend/packages/utils/utpagination"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	redacted = "[redacted]"
+)
+
+var (
+	// * columns that are never written to the audit log
+	redactedColumns = []string{"password", "secret", "key_hash", "reset_password_token"}
+)
+
+type (
+	AuditService struct {
+		cfg *configs.Config
+
+		// * repository
+		raudt auditlogrepo.IAuditLogRepository
+	}
+
+	IAuditService interface {
+		Snapshot(ctx context.Context, ae consttypes.AuditEntity, eid string) map[string]any
+		Record(ctx context.Context, al models.AuditLog, before map[string]any, after map[string]any)
+
+		FindAll(ctx context.Context, p utpagination.Pagination) (*utpagination.Pagination, error)
+		GetByID(ctx context.Context, id uuid.UUID) (*responses.AuditLog, error)
+		Export(ctx context.Context, f utpagination.Filter) ([]byte, error)
+	}
+)
+
+func NewAuditService(
+	cfg *configs.Config,
+	// * repository
+	raudt auditlogrepo.IAuditLogRepository,
+) *AuditService {
+	return &AuditService{
+		cfg: cfg,
+
+		// * repository
+		raudt: raudt,
+	}
+}
+
+// * the model read for the snapshots of an entity
+func snapshotModel(ae consttypes.AuditEntity) any {
+	switch ae {
+	case consttypes.AE_MEAL:
+		return &models.Meal{}
+	case consttypes.AE_MEAL_CATEGORY:
+		return &models.MealCategory{}
+	case consttypes.AE_MEMBER:
+		return &models.Member{}
+	case consttypes.AE_PARTNER:
+		return &models.Partner{}
+	case consttypes.AE_PATRON:
+		return &models.Patron{}
+	case consttypes.AE_ILLNESS:
+		return &models.Illness{}
+	case consttypes.AE_ALLERGY:
+		return &models.Allergy{}
+	case consttypes.AE_DONATION:
+		return &models.Donation{}
+	case consttypes.AE_DATA_ERASURE:
+		return &models.DataErasure{}
+	case consttypes.AE_SERVICE_ACCOUNT:
+		return &models.ServiceAccount{}
+	case consttypes.AE_API_KEY:
+		return &models.APIKey{}
+	}
+
+	return nil
+}
+
+// * reads the current state of the entity, nil when the entity
+// * is unknown or could not be read. the audit never blocks the
+// * request, so the errors are only logged
+func (s *AuditService) Snapshot(ctx context.Context, ae consttypes.AuditEntity, eid string) map[string]any {
+	if eid == "" {
+		return nil
+	}
+
+	if ae == consttypes.AE_ROLE_PERMISSION {
+		role, err := strconv.ParseUint(eid, 10, 32)
+		if err != nil {
+			return nil
+		}
+
+		row, err := s.raudt.SnapshotRolePermissions(ctx, consttypes.UserRole(role))
+		if err != nil {
+			return nil
+		}
+
+		return row
+	}
+
+	model := snapshotModel(ae)
+	if model == nil {
+		return nil
+	}
+
+	id, err := uuid.Parse(eid)
+	if err != nil {
+		return nil
+	}
+
+	row, err := s.raudt.Snapshot(ctx, model, id)
+	if err != nil {
+		return nil
+	}
+
+	return row
+}
+
+// * stores the audit log with the changes between the snapshots,
+// * the changes are only kept for the requests that succeeded
+func (s *AuditService) Record(ctx context.Context, al models.AuditLog, before map[string]any, after map[string]any) {
+	before, bsensitive := normalize(before)
+	after, asensitive := normalize(after)
+
+	if al.StatusCode < 400 && (before != nil || after != nil) {
+		al.Changes = models.NewAuditChanges(before, after)
+	}
+
+	sensitive := append(bsensitive, asensitive...)
+	al.Before = redact(before, sensitive)
+	al.After = redact(after, sensitive)
+
+	for column := range al.Changes {
+		if slices.Contains(sensitive, column) {
+			al.Changes[column] = models.AuditChange{From: redacted, To: redacted}
+		}
+	}
+
+	if _, err := s.raudt.Create(ctx, al); err != nil {
+		utlogger.ErrorContext(ctx, consttypes.ErrFailedToRecordAuditLog)
+	}
+}
+
+// * turns the raw row into values that can be compared and stored,
+// * the encrypted columns are decrypted so an unchanged value is not
+// * reported as changed, and are returned as sensitive
+func normalize(row map[string]any) (map[string]any, []string) {
+	var (
+		sensitive []string
+	)
+
+	if row == nil {
+		return nil, nil
+	}
+
+	normalized := make(map[string]any, len(row))
+	for column, value := range row {
+		if b, ok := value.([]byte); ok {
+			value = string(b)
+		}
+
+		if str, ok := value.(string); ok && utcrypto.IsEncrypted(str) {
+			sensitive = append(sensitive, column)
+			if plaintext, err := utcrypto.Decrypt(str); err == nil {
+				value = string(plaintext)
+			}
+		}
+
+		if slices.Contains(redactedColumns, column) {
+			sensitive = append(sensitive, column)
+		}
+
+		normalized[column] = value
+	}
+
+	return normalized, sensitive
+}
+
+func redact(row map[string]any, sensitive []string) map[string]any {
+	if row == nil {
+		return nil
+	}
+
+	for _, column := range sensitive {
+		if _, ok := row[column]; ok {
+			row[column] = redacted
+		}
+	}
+
+	return row
+}
+
+func (s *AuditService) FindAll(ctx context.Context, p utpagination.Pagination) (*utpagination.Pagination, error) {
+	als, err := s.raudt.FindAll(ctx, p)
+	if err != nil {
+		if errors.Is(err, consttypes.ErrInvalidCursor) || errors.Is(err, consttypes.ErrInvalidFilter) {
+			return nil, err
+		}
+
+		return nil, consttypes.ErrFailedToReadAuditLogs.Wrap(err)
+	}
+
+	return als, nil
+}
+
+func (s *AuditService) GetByID(ctx context.Context, id uuid.UUID) (*responses.AuditLog, error) {
+	al, err := s.raudt.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, consttypes.ErrAuditLogNotFound.Wrap(err)
+		}
+
+		utlogger.ErrorContext(ctx, err)
+		return nil, consttypes.ErrFailedToReadAuditLogs.Wrap(err)
+	}
+
+	return al.ToResponse()
+}
+
+// * writes the logs matching the filter as csv, the export is refused
+// * instead of being cut when more logs than the limit are matching
+func (s *AuditService) Export(ctx context.Context, f utpagination.Filter) ([]byte, error) {
+	var (
+		buf   bytes.Buffer
+		limit = s.cfg.Audit.ExportLimit
+	)
+
+	als, err := s.raudt.FindForExport(ctx, f, limit+1)
+	if err != nil {
+		return nil, consttypes.ErrFailedToExportAuditLogs.Wrap(err)
+	}
+
+	if len(als) > limit {
+		return nil, consttypes.ErrAuditExportLimitExceeded
+	}
+
+	w := csv.NewWriter(&buf)
+	w.Write([]string{
+		"id", "created_at", "actor_id", "actor_email", "actor_role",
+		"action", "entity", "entity_id", "method", "path", "route",
+		"status_code", "ip", "user_agent", "duration", "changes",
+	})
+
+	for _, al := range als {
+		changes, err := json.Marshal(al.Changes)
+		if err != nil {
+			return nil, consttypes.ErrConvertFailed.Wrap(err)
+		}
+
+		var createdat string
+		if al.CreatedAt != nil {
+			createdat = al.CreatedAt.Format(time.RFC3339)
+		}
+
+		w.Write([]string{
+			al.ID.String(),
+			createdat,
+			al.ActorID.String(),
+			csvSafe(al.ActorEmail),
+			strconv.FormatUint(uint64(al.ActorRole), 10),
+			al.Action.String(),
+			al.Entity.String(),
+			csvSafe(al.EntityID),
+			al.Method,
+			csvSafe(al.Path),
+			al.Route,
+			strconv.Itoa(al.StatusCode),
+			al.IP,
+			csvSafe(al.UserAgent),
+			strconv.FormatInt(al.Duration, 10),
+			string(changes),
+		})
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, consttypes.ErrFailedToExportAuditLogs.Wrap(err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// * a cell starting with a formula character is run by spreadsheets,
+// * the values sent by the client are escaped so they stay plain text
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+
+	return value
+}
@@ -1,6 +1,8 @@
 package authservice
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"project-skbackend/configs"
 	"project-skbackend/internal/controllers/requests"
@@ -8,6 +10,9 @@ import (
 	"project-skbackend/internal/models"
 	"project-skbackend/internal/repositories/userrepo"
 	"project-skbackend/internal/services/mailservice"
+	"project-skbackend/internal/services/securityservice"
+	"project-skbackend/internal/services/sessionservice"
+	"project-skbackend/internal/services/twofactorservice"
 	"project-skbackend/internal/services/userservice"
 	"project-skbackend/packages/consttypes"
 	"project-skbackend/packages/utils/utstring"
@@ -16,82 +21,175 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
-	"github.com/redis/go-redis/v9"
 )
 
 type (
 	AuthService struct {
 		cfg   *configs.Config
-		rdb   *redis.Client
 		ruser userrepo.IUserRepository
 		suser userservice.IUserService
 		smail mailservice.IMailService
+		ssess sessionservice.ISessionService
+		ssecu securityservice.ISecurityService
+		stfa  twofactorservice.ITwoFactorService
 
 		vtl int
 		wu  string
 	}
 
 	IAuthService interface {
-		Signin(req requests.Signin, ctx *gin.Context) (*responses.User, *uttoken.TokenHeader, error)
-		ForgotPassword(req requests.ForgotPassword) error
-		ResetPassword(req requests.ResetPassword) error
-		SendResetPasswordEmail(user models.User) error
+		Signin(req requests.Signin, ctx *gin.Context) (*responses.User, *uttoken.TokenHeader, *responses.TwoFactorChallenge, error)
+		SigninUser(user models.User, ctx *gin.Context) (*responses.User, *uttoken.TokenHeader, *responses.TwoFactorChallenge, error)
+		VerifyTwoFactor(req requests.VerifyTwoFactor, ctx *gin.Context) (*responses.User, *uttoken.TokenHeader, error)
+		ConfirmTwoFactorEnrolment(req requests.ConfirmTwoFactorChallenge, ctx *gin.Context) (*responses.User, *uttoken.TokenHeader, *responses.TwoFactorRecoveryCodes, error)
+		ForgotPassword(ctx context.Context, req requests.ForgotPassword) error
+		ResetPassword(ctx context.Context, req requests.ResetPassword) error
+		SendResetPasswordEmail(ctx context.Context, user models.User) error
 		RefreshAuthToken(trefresh string, ctx *gin.Context) (*responses.User, *uttoken.TokenHeader, error)
-		SendVerificationEmail(id uuid.UUID) error
+		Signout(taccess string, ctx *gin.Context) error
+		SendVerificationEmail(ctx context.Context, id uuid.UUID) error
 		VerifyToken(req requests.VerifyToken, ctx *gin.Context) (*responses.User, *uttoken.TokenHeader, error)
 	}
 )
 
 func NewAuthService(
 	cfg *configs.Config,
-	rdb *redis.Client,
 	ruser userrepo.IUserRepository,
 	smail mailservice.IMailService,
 	suser userservice.IUserService,
+	ssess sessionservice.ISessionService,
+	ssecu securityservice.ISecurityService,
+	stfa twofactorservice.ITwoFactorService,
 ) *AuthService {
 	return &AuthService{
 		cfg:   cfg,
 		ruser: ruser,
 		smail: smail,
 		suser: suser,
-		rdb:   rdb,
+		ssess: ssess,
+		ssecu: ssecu,
+		stfa:  stfa,
 
 		vtl: cfg.VerifyTokenLength,
 		wu:  cfg.Web.URL,
 	}
 }
 
-func (s *AuthService) Signin(req requests.Signin, ctx *gin.Context) (*responses.User, *uttoken.TokenHeader, error) {
-	user, err := s.ruser.GetByEmail(req.Email)
+func (s *AuthService) Signin(req requests.Signin, ctx *gin.Context) (*responses.User, *uttoken.TokenHeader, *responses.TwoFactorChallenge, error) {
+	var (
+		ip        = ctx.ClientIP()
+		useragent = ctx.Request.UserAgent()
+	)
+
+	user, err := s.ruser.GetByEmail(ctx, req.Email)
 	if err != nil {
-		return nil, nil, consttypes.ErrUserNotFound
+		s.ssecu.RecordEvent(ctx, *models.NewSecurityEvent(consttypes.SET_SIGNIN_FAILED, nil, req.Email, ip, useragent, consttypes.ErrUserNotFound.Error()))
+		// * an unknown email is answered like a wrong password,
+		// * so the signin does not tell which emails are registered
+		return nil, nil, nil, consttypes.ErrInvalidEmailOrPassword.Wrap(err)
+	}
+
+	// * a locked account is rejected before the password is checked
+	// * so the password cannot be guessed while the account is locked
+	err = s.ssecu.CheckLockout(ctx, user.ID)
+	if err != nil {
+		return nil, nil, nil, err
 	}
 
 	err = verifyPassword(*user, req.Password)
 	if err != nil {
-		return nil, nil, consttypes.ErrInvalidEmailOrPassword
+		if lerr := s.ssecu.RecordFailedSignin(ctx, *user, ip, useragent); lerr != nil {
+			return nil, nil, nil, lerr
+		}
+
+		return nil, nil, nil, consttypes.ErrInvalidEmailOrPassword.Wrap(err)
+	}
+
+	err = s.ssecu.ResetFailedSignin(ctx, user.ID)
+	if err != nil {
+		return nil, nil, nil, err
 	}
 
-	thead, err := s.generateAuthTokens(user, ctx)
+	return s.SigninUser(*user, ctx)
+}
+
+// * signs in a user that is already authenticated, either by the password
+// * or by an oidc provider, the tokens are only given once the two factor
+// * challenge is passed
+func (s *AuthService) SigninUser(user models.User, ctx *gin.Context) (*responses.User, *uttoken.TokenHeader, *responses.TwoFactorChallenge, error) {
+	tfch, err := s.stfa.Challenge(ctx, user)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if tfch != nil {
+		return nil, nil, tfch, nil
+	}
+
+	thead, err := s.generateAuthTokens(&user, ctx, nil)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	userres, err := user.ToResponse()
+	if err != nil {
+		return nil, nil, nil, consttypes.ErrConvertFailed.Wrap(err)
+	}
+
+	return userres, thead, nil, nil
+}
+
+func (s *AuthService) VerifyTwoFactor(req requests.VerifyTwoFactor, ctx *gin.Context) (*responses.User, *uttoken.TokenHeader, error) {
+	uid, err := s.stfa.VerifyChallenge(ctx, req, ctx.ClientIP(), ctx.Request.UserAgent())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s.signinByID(uid, ctx)
+}
+
+// * completes the enrolment forced by the policy of the role,
+// * the recovery codes are only shown once so they come with the tokens
+func (s *AuthService) ConfirmTwoFactorEnrolment(req requests.ConfirmTwoFactorChallenge, ctx *gin.Context) (*responses.User, *uttoken.TokenHeader, *responses.TwoFactorRecoveryCodes, error) {
+	uid, rcres, err := s.stfa.ConfirmChallengeEnrolment(ctx, req, ctx.ClientIP(), ctx.Request.UserAgent())
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	userres, thead, err := s.signinByID(uid, ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return userres, thead, rcres, nil
+}
+
+func (s *AuthService) signinByID(uid uuid.UUID, ctx *gin.Context) (*responses.User, *uttoken.TokenHeader, error) {
+	user, err := s.ruser.GetByID(ctx, uid)
+	if err != nil {
+		return nil, nil, consttypes.ErrUserNotFound.Wrap(err)
+	}
+
+	thead, err := s.generateAuthTokens(user, ctx, nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	userres, err := user.ToResponse()
 	if err != nil {
-		return nil, nil, consttypes.ErrConvertFailed
+		return nil, nil, consttypes.ErrConvertFailed.Wrap(err)
 	}
 
 	return userres, thead, nil
 }
 
-func (s *AuthService) ForgotPassword(req requests.ForgotPassword) error {
-	user, err := s.ruser.GetByEmail(req.Email)
+func (s *AuthService) ForgotPassword(ctx context.Context, req requests.ForgotPassword) error {
+	user, err := s.ruser.GetByEmail(ctx, req.Email)
 	if err != nil {
-		return consttypes.ErrUserNotFound
+		return consttypes.ErrUserNotFound.Wrap(err)
 	}
 
-	err = s.SendResetPasswordEmail(*user)
+	err = s.SendResetPasswordEmail(ctx, *user)
 	if err != nil {
 		return err
 	}
@@ -99,10 +197,10 @@ func (s *AuthService) ForgotPassword(req requests.ForgotPassword) error {
 	return nil
 }
 
-func (s *AuthService) ResetPassword(req requests.ResetPassword) error {
-	user, err := s.ruser.GetByEmail(req.Email)
+func (s *AuthService) ResetPassword(ctx context.Context, req requests.ResetPassword) error {
+	user, err := s.ruser.GetByEmail(ctx, req.Email)
 	if err != nil {
-		return consttypes.ErrUserNotFound
+		return consttypes.ErrUserNotFound.Wrap(err)
 	}
 
 	if req.Token != user.ResetPasswordToken {
@@ -117,21 +215,27 @@ func (s *AuthService) ResetPassword(req requests.ResetPassword) error {
 
 	user, err = req.ToUserModel(*user)
 	if err != nil {
-		return consttypes.ErrConvertFailed
+		return consttypes.ErrConvertFailed.Wrap(err)
 	}
 
-	_, err = s.ruser.Update(*user)
+	_, err = s.ruser.Update(ctx, *user)
 	if err != nil {
-		return consttypes.ErrFailedToUpdateUser
+		return consttypes.ErrFailedToUpdateUser.Wrap(err)
+	}
+
+	// * sign out every device since the old password may be leaked
+	err = s.ssess.RevokeAll(ctx, user.ID, nil)
+	if err != nil {
+		return err
 	}
 
 	return nil
 }
 
-func (s *AuthService) SendResetPasswordEmail(user models.User) error {
+func (s *AuthService) SendResetPasswordEmail(ctx context.Context, user models.User) error {
 	token, err := utstring.GenerateRandomToken(s.vtl)
 	if err != nil {
-		return consttypes.ErrFailedToGenerateToken
+		return consttypes.ErrFailedToGenerateToken.Wrap(err)
 	}
 
 	if consttypes.TimeNow().Before(user.ResetPasswordSentAt.Add(time.Minute * time.Duration(s.cfg.APIResetPassword.Cooldown))) {
@@ -141,14 +245,14 @@ func (s *AuthService) SendResetPasswordEmail(user models.User) error {
 	user.ResetPasswordToken = token
 	user.ResetPasswordSentAt = consttypes.TimeNow()
 
-	_, err = s.ruser.Update(user)
+	_, err = s.ruser.Update(ctx, user)
 	if err != nil {
-		return consttypes.ErrFailedToUpdateUser
+		return consttypes.ErrFailedToUpdateUser.Wrap(err)
 	}
 
-	firstname, lastname, err := s.suser.GetUserName(user.ID)
+	firstname, lastname, err := s.suser.GetUserName(ctx, user.ID)
 	if err != nil {
-		return consttypes.ErrFailedToGetUserName
+		return consttypes.ErrFailedToGetUserName.Wrap(err)
 	}
 
 	name := utstring.AppendName(firstname, lastname)
@@ -158,9 +262,9 @@ func (s *AuthService) SendResetPasswordEmail(user models.User) error {
 		LinkUrl: fmt.Sprintf("%s/reset-password/%v", s.wu, token),
 	}
 
-	err = s.smail.SendResetPasswordEmail(emreq)
+	err = s.smail.SendResetPasswordEmail(ctx, emreq)
 	if err != nil {
-		return consttypes.ErrFailedToSendEmail
+		return consttypes.ErrFailedToSendEmail.Wrap(err)
 	}
 
 	return nil
@@ -168,14 +272,14 @@ func (s *AuthService) SendResetPasswordEmail(user models.User) error {
 
 func (s *AuthService) RefreshAuthToken(trefresh string, ctx *gin.Context) (*responses.User, *uttoken.TokenHeader, error) {
 	now := consttypes.TimeNow()
-	tparsed, err := uttoken.ParseToken(trefresh, s.cfg.JWT.JWTRefreshToken.PublicKey)
+	tparsed, err := uttoken.ParseToken(trefresh, s.cfg.JWT.JWTRefreshToken.PublicKey, s.cfg.JWT.JWTRefreshToken.PreviousPublicKey)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	user, err := s.ruser.GetByID(tparsed.User.ID)
+	user, err := s.ruser.GetByID(ctx, tparsed.User.ID)
 	if err != nil {
-		return nil, nil, consttypes.ErrUserNotFound
+		return nil, nil, consttypes.ErrUserNotFound.Wrap(err)
 	}
 
 	if now.Unix() >= tparsed.Expires.Unix() {
@@ -184,17 +288,39 @@ func (s *AuthService) RefreshAuthToken(trefresh string, ctx *gin.Context) (*resp
 
 	userres, err := user.ToResponse()
 	if err != nil {
-		return nil, nil, consttypes.ErrConvertFailed
+		return nil, nil, consttypes.ErrConvertFailed.Wrap(err)
 	}
 
-	theader, err := s.generateAuthTokens(user, ctx)
+	theader, err := s.generateAuthTokens(user, ctx, tparsed)
 	if err != nil {
-		return nil, nil, consttypes.ErrFailedToGenerateToken
+		if errors.Is(err, consttypes.ErrRefreshTokenReused) {
+			s.ssecu.RecordEvent(ctx, *models.NewSecurityEvent(consttypes.SET_REFRESH_TOKEN_REUSED, &user.ID, user.Email, ctx.ClientIP(), ctx.Request.UserAgent(), tparsed.SessionID.String()))
+		}
+
+		return nil, nil, err
 	}
 
 	return userres, theader, nil
 }
 
+func (s *AuthService) Signout(taccess string, ctx *gin.Context) error {
+	tparsed, err := uttoken.ParseToken(taccess, s.cfg.JWT.JWTAccessToken.PublicKey, s.cfg.JWT.JWTAccessToken.PreviousPublicKey)
+	if err != nil {
+		return err
+	}
+
+	// * revoking the session revokes both the access
+	// * and the refresh token of the session
+	err = s.ssess.Revoke(ctx, tparsed.User.ID, tparsed.SessionID)
+	if err != nil {
+		return err
+	}
+
+	uttoken.DeleteToken(ctx)
+
+	return nil
+}
+
 func verifyPassword(user models.User, password string) error {
 	ok := utstring.CheckPasswordHash(password, user.Password)
 	if !ok {
@@ -204,69 +330,74 @@ func verifyPassword(user models.User, password string) error {
 	return nil
 }
 
-func (s *AuthService) generateAuthTokens(user *models.User, ctx *gin.Context) (*uttoken.TokenHeader, error) {
+// * a new session is started when there is no previous refresh token,
+// * otherwise the token pair of the previous refresh token's session
+// * is rotated
+func (s *AuthService) generateAuthTokens(user *models.User, ctx *gin.Context, trefreshold *uttoken.Token) (*uttoken.TokenHeader, error) {
 	userres, err := user.ToResponse()
 	if err != nil {
-		return nil, consttypes.ErrConvertFailed
+		return nil, consttypes.ErrConvertFailed.Wrap(err)
+	}
+
+	sid := uuid.New()
+	if trefreshold != nil {
+		sid = trefreshold.SessionID
 	}
 
-	now := consttypes.TimeNow()
 	trefresh, err := uttoken.
 		GenerateToken(
 			userres,
+			sid,
 			s.cfg.JWT.JWTRefreshToken.Life,
 			s.cfg.JWT.TimeUnit,
 			s.cfg.JWT.JWTRefreshToken.PrivateKey,
 		)
 
 	if err != nil {
-		return nil, consttypes.ErrConvertFailed
+		return nil, consttypes.ErrFailedToGenerateToken.Wrap(err)
 	}
 
 	taccess, err := uttoken.
 		GenerateToken(
 			userres,
+			sid,
 			s.cfg.JWT.JWTAccessToken.Life,
 			s.cfg.JWT.TimeUnit,
 			s.cfg.JWT.JWTAccessToken.PrivateKey,
 		)
 
 	if err != nil {
-		return nil, consttypes.ErrFailedToGenerateToken
+		return nil, consttypes.ErrFailedToGenerateToken.Wrap(err)
 	}
 
-	theader := uttoken.NewTokenHeader(*taccess, *trefresh)
-
-	// * setting the access token into header
-	ctx.Header(consttypes.T_ACCESS, *taccess.Token)
+	if trefreshold == nil {
+		_, err = s.ssess.Create(ctx, user.ID, ctx.Request.UserAgent(), ctx.ClientIP(), *taccess, *trefresh)
+	} else {
+		_, err = s.ssess.Rotate(ctx, *trefreshold, ctx.ClientIP(), *taccess, *trefresh)
+	}
 
-	// * setting the access token into redis
-	err = s.rdb.Set(ctx, taccess.TokenUUID.String(), user.ID, time.Unix(taccess.Expires.Unix(), 0).Sub(now)).Err()
 	if err != nil {
-		fmt.Println("failed to set cache: ", err)
+		return nil, err
 	}
 
-	// * setting the refresh token into header
-	ctx.Header(consttypes.T_REFRESH, *trefresh.Token)
+	theader := uttoken.NewTokenHeader(*taccess, *trefresh)
 
-	// * setting the refresh token into redis
-	err = s.rdb.Set(ctx, trefresh.TokenUUID.String(), user.ID, time.Unix(trefresh.Expires.Unix(), 0).Sub(now)).Err()
-	if err != nil {
-		fmt.Println("failed to set cache: ", err)
-	}
+	// * setting the tokens into header
+	ctx.Header(consttypes.T_ACCESS, *taccess.Token)
+	ctx.Header(consttypes.T_REFRESH, *trefresh.Token)
 
 	return theader, nil
 }
 
-func (s *AuthService) SendVerificationEmail(id uuid.UUID) error {
+func (s *AuthService) SendVerificationEmail(ctx context.Context, id uuid.UUID) error {
 	tverif, err := utstring.GenerateRandomToken(s.vtl)
 	if err != nil {
-		return consttypes.ErrFailedToGenerateToken
+		return consttypes.ErrFailedToGenerateToken.Wrap(err)
 	}
 
-	user, err := s.ruser.GetByID(id)
+	user, err := s.ruser.GetByID(ctx, id)
 	if err != nil {
-		return consttypes.ErrUserNotFound
+		return consttypes.ErrUserNotFound.Wrap(err)
 	}
 
 	duration := consttypes.TimeNow().Sub(user.ConfirmationSentAt)
@@ -278,19 +409,19 @@ func (s *AuthService) SendVerificationEmail(id uuid.UUID) error {
 	user.ConfirmationToken = tverif
 	user.ConfirmationSentAt = consttypes.TimeNow()
 
-	user, err = s.ruser.Update(*user)
+	user, err = s.ruser.Update(ctx, *user)
 	if err != nil {
-		return consttypes.ErrFailedToUpdateUser
+		return consttypes.ErrFailedToUpdateUser.Wrap(err)
 	}
 
 	userres, err := user.ToResponse()
 	if err != nil {
-		return consttypes.ErrConvertFailed
+		return consttypes.ErrConvertFailed.Wrap(err)
 	}
 
-	firstname, lastname, err := s.suser.GetUserName(user.ID)
+	firstname, lastname, err := s.suser.GetUserName(ctx, user.ID)
 	if err != nil {
-		return consttypes.ErrFailedToGetUserName
+		return consttypes.ErrFailedToGetUserName.Wrap(err)
 	}
 
 	name := utstring.AppendName(firstname, lastname)
@@ -300,18 +431,18 @@ func (s *AuthService) SendVerificationEmail(id uuid.UUID) error {
 		Token: tverif,
 	}
 
-	err = s.smail.SendVerifyEmail(emailData)
+	err = s.smail.SendVerifyEmail(ctx, emailData)
 	if err != nil {
-		return consttypes.ErrFailedToSendEmail
+		return consttypes.ErrFailedToSendEmail.Wrap(err)
 	}
 
 	return nil
 }
 
 func (s *AuthService) VerifyToken(req requests.VerifyToken, ctx *gin.Context) (*responses.User, *uttoken.TokenHeader, error) {
-	user, err := s.ruser.GetByEmail(req.Email)
+	user, err := s.ruser.GetByEmail(ctx, req.Email)
 	if err != nil {
-		return nil, nil, consttypes.ErrUserNotFound
+		return nil, nil, consttypes.ErrUserNotFound.Wrap(err)
 	}
 
 	duration := consttypes.TimeNow().Sub(user.ConfirmationSentAt)
@@ -331,19 +462,19 @@ func (s *AuthService) VerifyToken(req requests.VerifyToken, ctx *gin.Context) (*
 	user.ConfirmedAt = consttypes.TimeNow()
 	user.ConfirmationToken = ""
 
-	user, err = s.ruser.Update(*user)
+	user, err = s.ruser.Update(ctx, *user)
 	if err != nil {
-		return nil, nil, consttypes.ErrFailedToUpdateUser
+		return nil, nil, consttypes.ErrFailedToUpdateUser.Wrap(err)
 	}
 
-	theader, err := s.generateAuthTokens(user, ctx)
+	theader, err := s.generateAuthTokens(user, ctx, nil)
 	if err != nil {
-		return nil, nil, consttypes.ErrFailedToGenerateToken
+		return nil, nil, err
 	}
 
 	userres, err := user.ToResponse()
 	if err != nil {
-		return nil, nil, consttypes.ErrConvertFailed
+		return nil, nil, consttypes.ErrConvertFailed.Wrap(err)
 	}
 
 	return userres, theader, nil
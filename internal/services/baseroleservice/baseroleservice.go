@@ -1,37 +1,49 @@
 package baseroleservice
 
 import (
+	"context"
 	"project-skbackend/internal/controllers/responses"
 	"project-skbackend/internal/models"
+	"project-skbackend/internal/repositories/membercaregiverrepo"
 	"project-skbackend/internal/repositories/memberrepo"
 	"project-skbackend/internal/repositories/partnerrepo"
 	"project-skbackend/packages/consttypes"
 	"project-skbackend/packages/utils/utrole"
+
+	"github.com/google/uuid"
 )
 
 type (
 	BaseRoleService struct {
 		rmemb memberrepo.IMemberRepository
+		rmcg  membercaregiverrepo.IMemberCaregiverRepository
 		rpart partnerrepo.IPartnerRepository
 	}
 
 	IBaseRoleService interface {
-		GetMemberByBaseRole(roleres responses.BaseRole) (*models.Member, error)
-		GetPartnerByBaseRole(roleres responses.BaseRole) (*models.Partner, error)
+		GetMemberByBaseRole(ctx context.Context, roleres responses.BaseRole, mid *uuid.UUID, access consttypes.CaregiverAccess) (*models.Member, error)
+		GetMemberByCaregiverID(ctx context.Context, cgid uuid.UUID, mid *uuid.UUID, access consttypes.CaregiverAccess) (*models.Member, error)
+		GetOrganizationMemberByBaseRole(ctx context.Context, roleres responses.BaseRole, mid uuid.UUID) (*models.Member, error)
+		GetPartnerByBaseRole(ctx context.Context, roleres responses.BaseRole) (*models.Partner, error)
 	}
 )
 
 func NewBaseRoleService(
 	rmemb memberrepo.IMemberRepository,
+	rmcg membercaregiverrepo.IMemberCaregiverRepository,
 	rpart partnerrepo.IPartnerRepository,
 ) *BaseRoleService {
 	return &BaseRoleService{
 		rmemb: rmemb,
+		rmcg:  rmcg,
 		rpart: rpart,
 	}
 }
 
-func (s *BaseRoleService) GetMemberByBaseRole(roleres responses.BaseRole) (*models.Member, error) {
+// * get the member the role is acting for. members always act for
+// * themselves, caregivers have to choose one of their linked members
+// * and the link has to allow the given access.
+func (s *BaseRoleService) GetMemberByBaseRole(ctx context.Context, roleres responses.BaseRole, mid *uuid.UUID, access consttypes.CaregiverAccess) (*models.Member, error) {
 	var (
 		m   *models.Member
 		err error
@@ -42,22 +54,66 @@ func (s *BaseRoleService) GetMemberByBaseRole(roleres responses.BaseRole) (*mode
 		return nil, consttypes.ErrUserInvalidRole
 	}
 
-	if rtype == consttypes.UR_CAREGIVER {
-		m, err = s.rmemb.GetByCaregiverID(rid)
+	switch rtype {
+	case consttypes.UR_CAREGIVER:
+		m, err = s.GetMemberByCaregiverID(ctx, rid, mid, access)
 		if err != nil {
-			return nil, consttypes.ErrMemberNotFound
+			return nil, err
 		}
-	} else if rtype == consttypes.UR_MEMBER {
-		m, err = s.rmemb.GetByID(rid)
+	case consttypes.UR_MEMBER:
+		m, err = s.rmemb.GetByID(ctx, rid)
 		if err != nil {
-			return nil, consttypes.ErrMemberNotFound
+			return nil, consttypes.ErrMemberNotFound.Wrap(err)
 		}
+	default:
+		return nil, consttypes.ErrUserInvalidRole
+	}
+
+	return m, nil
+}
+
+func (s *BaseRoleService) GetMemberByCaregiverID(ctx context.Context, cgid uuid.UUID, mid *uuid.UUID, access consttypes.CaregiverAccess) (*models.Member, error) {
+	if mid == nil {
+		return nil, consttypes.ErrCaregiverMemberIDRequired
+	}
+
+	mc, err := s.rmcg.GetByMemberIDAndCaregiverID(ctx, *mid, cgid)
+	if err != nil {
+		return nil, consttypes.ErrCaregiverNotLinked.Wrap(err)
+	}
+
+	if !mc.Allows(access) {
+		return nil, consttypes.ErrCaregiverAccessDenied
+	}
+
+	m, err := s.rmemb.GetByID(ctx, *mid)
+	if err != nil {
+		return nil, consttypes.ErrMemberNotFound.Wrap(err)
+	}
+
+	return m, nil
+}
+
+// * get one of the members registered under the organization
+func (s *BaseRoleService) GetOrganizationMemberByBaseRole(ctx context.Context, roleres responses.BaseRole, mid uuid.UUID) (*models.Member, error) {
+	rid, rtype, ok := utrole.RoleTranslate(roleres)
+	if !ok || rtype != consttypes.UR_ORGANIZATION {
+		return nil, consttypes.ErrUserInvalidRole
+	}
+
+	m, err := s.rmemb.GetByID(ctx, mid)
+	if err != nil {
+		return nil, consttypes.ErrMemberNotFound.Wrap(err)
+	}
+
+	if m.OrganizationID == nil || *m.OrganizationID != rid {
+		return nil, consttypes.ErrNotResourceOwner
 	}
 
 	return m, nil
 }
 
-func (s *BaseRoleService) GetPartnerByBaseRole(roleres responses.BaseRole) (*models.Partner, error) {
+func (s *BaseRoleService) GetPartnerByBaseRole(ctx context.Context, roleres responses.BaseRole) (*models.Partner, error) {
 	var (
 		p   *models.Partner
 		err error
@@ -70,11 +126,11 @@ func (s *BaseRoleService) GetPartnerByBaseRole(roleres responses.BaseRole) (*mod
 
 	switch rtype {
 	case consttypes.UR_PARTNER:
-		p, err = s.rpart.GetByID(rid)
+		p, err = s.rpart.GetByID(ctx, rid)
 	}
 
 	if err != nil {
-		return nil, consttypes.ErrPartnerNotFound
+		return nil, consttypes.ErrPartnerNotFound.Wrap(err)
 	}
 
 	return p, nil
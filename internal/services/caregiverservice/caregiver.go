@@ -1,41 +1,103 @@
 package caregiverservice
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"project-skbackend/configs"
+	"project-skbackend/internal/controllers/requests"
 	"project-skbackend/internal/controllers/responses"
+	"project-skbackend/internal/models"
+	"project-skbackend/internal/repositories/caregiverinvitationrepo"
 	"project-skbackend/internal/repositories/caregiverrepo"
+	"project-skbackend/internal/repositories/membercaregiverrepo"
+	"project-skbackend/internal/repositories/memberrepo"
+	"project-skbackend/internal/repositories/userrepo"
+	"project-skbackend/internal/services/mailservice"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/utils/utstring"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// * invitation tokens are sent by email and used without
+	// * authentication, so they are longer than verification tokens
+	invitationTokenLength = 32
 )
 
 type (
 	CaregiverService struct {
 		// * repository
 		rcare caregiverrepo.ICaregiverRepository
+		rmcg  membercaregiverrepo.IMemberCaregiverRepository
+		rcgin caregiverinvitationrepo.ICaregiverInvitationRepository
+		rmemb memberrepo.IMemberRepository
+		ruser userrepo.IUserRepository
+
+		// * service
+		smail mailservice.IMailService
+
+		wu      string
+		invlife int
 	}
 
 	ICaregiverService interface {
-		GetByID(id uuid.UUID) (*responses.Caregiver, error)
-		GetByUserID(uid uuid.UUID) (*responses.Caregiver, error)
+		GetByID(ctx context.Context, id uuid.UUID) (*responses.Caregiver, error)
+		GetByUserID(ctx context.Context, uid uuid.UUID) (*responses.Caregiver, error)
+
+		// * member caregiver links
+		FindByMemberID(ctx context.Context, mid uuid.UUID) ([]*responses.MemberCaregiver, error)
+		FindMembersByCaregiverID(ctx context.Context, cgid uuid.UUID) ([]*responses.MemberCaregiver, error)
+		UpdateLink(ctx context.Context, mid uuid.UUID, cgid uuid.UUID, req requests.UpdateMemberCaregiver) (*responses.MemberCaregiver, error)
+		DeleteLink(ctx context.Context, mid uuid.UUID, cgid uuid.UUID) error
+
+		// * caregiver invitations
+		CreateInvitation(ctx context.Context, member models.Member, req requests.CreateCaregiverInvitation) (*responses.CaregiverInvitation, error)
+		FindInvitationsByMemberID(ctx context.Context, mid uuid.UUID) ([]*responses.CaregiverInvitation, error)
+		RevokeInvitation(ctx context.Context, mid uuid.UUID, ciid uuid.UUID) error
+		AcceptInvitation(ctx context.Context, req requests.AcceptCaregiverInvitation) (*responses.MemberCaregiver, error)
+		DeclineInvitation(ctx context.Context, req requests.DeclineCaregiverInvitation) error
 	}
 )
 
 func NewCaregiverService(
+	cfg *configs.Config,
 	// * repository
 	rcare caregiverrepo.ICaregiverRepository,
+	rmcg membercaregiverrepo.IMemberCaregiverRepository,
+	rcgin caregiverinvitationrepo.ICaregiverInvitationRepository,
+	rmemb memberrepo.IMemberRepository,
+	ruser userrepo.IUserRepository,
+	// * service
+	smail mailservice.IMailService,
 ) *CaregiverService {
 	return &CaregiverService{
 		// * repository
 		rcare: rcare,
+		rmcg:  rmcg,
+		rcgin: rcgin,
+		rmemb: rmemb,
+		ruser: ruser,
+
+		// * service
+		smail: smail,
+
+		wu:      cfg.Web.URL,
+		invlife: cfg.APICaregiverInvitation.Life,
 	}
 }
 
-func (s *CaregiverService) GetByID(id uuid.UUID) (*responses.Caregiver, error) {
+func (s *CaregiverService) GetByID(ctx context.Context, id uuid.UUID) (*responses.Caregiver, error) {
 	var (
 		careres *responses.Caregiver
 		err     error
 	)
 
-	caremod, err := s.rcare.GetByID(id)
+	caremod, err := s.rcare.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -48,13 +110,13 @@ func (s *CaregiverService) GetByID(id uuid.UUID) (*responses.Caregiver, error) {
 	return careres, nil
 }
 
-func (s *CaregiverService) GetByUserID(uid uuid.UUID) (*responses.Caregiver, error) {
+func (s *CaregiverService) GetByUserID(ctx context.Context, uid uuid.UUID) (*responses.Caregiver, error) {
 	var (
 		careres *responses.Caregiver
 		err     error
 	)
 
-	caremod, err := s.rcare.GetByUserID(uid)
+	caremod, err := s.rcare.GetByUserID(ctx, uid)
 	if err != nil {
 		return nil, err
 	}
@@ -66,3 +128,285 @@ func (s *CaregiverService) GetByUserID(uid uuid.UUID) (*responses.Caregiver, err
 
 	return careres, nil
 }
+
+func (s *CaregiverService) FindByMemberID(ctx context.Context, mid uuid.UUID) ([]*responses.MemberCaregiver, error) {
+	var (
+		mcreses []*responses.MemberCaregiver
+	)
+
+	mcs, err := s.rmcg.FindByMemberID(ctx, mid)
+	if err != nil {
+		return nil, consttypes.ErrFailedToReadCaregiverLinks.Wrap(err)
+	}
+
+	for _, mc := range mcs {
+		// * the member is the one asking, no need to send it back
+		mc.Member = nil
+
+		mcres, err := mc.ToResponse()
+		if err != nil {
+			return nil, consttypes.ErrConvertFailed.Wrap(err)
+		}
+
+		mcreses = append(mcreses, mcres)
+	}
+
+	return mcreses, nil
+}
+
+func (s *CaregiverService) FindMembersByCaregiverID(ctx context.Context, cgid uuid.UUID) ([]*responses.MemberCaregiver, error) {
+	var (
+		mcreses []*responses.MemberCaregiver
+	)
+
+	mcs, err := s.rmcg.FindByCaregiverID(ctx, cgid)
+	if err != nil {
+		return nil, consttypes.ErrFailedToReadCaregiverLinks.Wrap(err)
+	}
+
+	for _, mc := range mcs {
+		mcres, err := mc.ToResponse()
+		if err != nil {
+			return nil, consttypes.ErrConvertFailed.Wrap(err)
+		}
+
+		// * the caregiver is the one asking, no need to send it back
+		mcres.Caregiver = nil
+
+		mcreses = append(mcreses, mcres)
+	}
+
+	return mcreses, nil
+}
+
+func (s *CaregiverService) UpdateLink(ctx context.Context, mid uuid.UUID, cgid uuid.UUID, req requests.UpdateMemberCaregiver) (*responses.MemberCaregiver, error) {
+	mc, err := s.rmcg.GetByMemberIDAndCaregiverID(ctx, mid, cgid)
+	if err != nil {
+		return nil, consttypes.ErrCaregiverNotLinked.Wrap(err)
+	}
+
+	mc, err = s.rmcg.Update(ctx, *req.ToModel(*mc))
+	if err != nil {
+		return nil, consttypes.ErrFailedToUpdateCaregiver.Wrap(err)
+	}
+
+	mc.Member = nil
+	mcres, err := mc.ToResponse()
+	if err != nil {
+		return nil, consttypes.ErrConvertFailed.Wrap(err)
+	}
+
+	return mcres, nil
+}
+
+func (s *CaregiverService) DeleteLink(ctx context.Context, mid uuid.UUID, cgid uuid.UUID) error {
+	mc, err := s.rmcg.GetByMemberIDAndCaregiverID(ctx, mid, cgid)
+	if err != nil {
+		return consttypes.ErrCaregiverNotLinked.Wrap(err)
+	}
+
+	if err := s.rmcg.Delete(ctx, *mc); err != nil {
+		return consttypes.ErrFailedToUnlinkCaregiver.Wrap(err)
+	}
+
+	return nil
+}
+
+func (s *CaregiverService) CreateInvitation(ctx context.Context, member models.Member, req requests.CreateCaregiverInvitation) (*responses.CaregiverInvitation, error) {
+	email := strings.ToLower(req.Email)
+
+	// * a member cannot be their own caregiver
+	if email == strings.ToLower(member.User.Email) {
+		return nil, consttypes.ErrCaregiverInvitationSelf
+	}
+
+	// * check whether the caregiver is already linked to the member
+	for _, mc := range member.Caregivers {
+		if strings.ToLower(mc.Caregiver.User.Email) == email {
+			return nil, consttypes.ErrCaregiverAlreadyLinked
+		}
+	}
+
+	// * only keep one pending invitation per email
+	_, err := s.rcgin.GetPendingByMemberIDAndEmail(ctx, member.ID, email)
+	if err == nil {
+		return nil, consttypes.ErrCaregiverInvitationAlreadySent
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, consttypes.ErrFailedToReadCaregiverInvitations
+	}
+
+	token, err := utstring.GenerateRandomToken(invitationTokenLength)
+	if err != nil {
+		return nil, consttypes.ErrFailedToGenerateToken.Wrap(err)
+	}
+
+	expiresat := consttypes.TimeNow().Add(time.Hour * time.Duration(s.invlife))
+	ci, err := s.rcgin.Create(ctx, *req.ToModel(member, token, expiresat))
+	if err != nil {
+		return nil, consttypes.ErrFailedToCreateCaregiverInvitation.Wrap(err)
+	}
+
+	emreq := requests.SendEmailCaregiverInvitation{
+		MemberName: utstring.AppendName(member.FirstName, member.LastName),
+		Email:      ci.Email,
+		LinkUrl:    fmt.Sprintf("%s/caregiver-invitations/%v", s.wu, token),
+	}
+
+	if err := s.smail.SendCaregiverInvitationEmail(ctx, emreq); err != nil {
+		return nil, consttypes.ErrFailedToSendEmail.Wrap(err)
+	}
+
+	cires, err := ci.ToResponse()
+	if err != nil {
+		return nil, consttypes.ErrConvertFailed.Wrap(err)
+	}
+
+	return cires, nil
+}
+
+func (s *CaregiverService) FindInvitationsByMemberID(ctx context.Context, mid uuid.UUID) ([]*responses.CaregiverInvitation, error) {
+	var (
+		cireses []*responses.CaregiverInvitation
+	)
+
+	cis, err := s.rcgin.FindByMemberID(ctx, mid)
+	if err != nil {
+		return nil, consttypes.ErrFailedToReadCaregiverInvitations.Wrap(err)
+	}
+
+	for _, ci := range cis {
+		cires, err := ci.ToResponse()
+		if err != nil {
+			return nil, consttypes.ErrConvertFailed.Wrap(err)
+		}
+
+		cireses = append(cireses, cires)
+	}
+
+	return cireses, nil
+}
+
+func (s *CaregiverService) RevokeInvitation(ctx context.Context, mid uuid.UUID, ciid uuid.UUID) error {
+	ci, err := s.rcgin.GetByID(ctx, ciid)
+	if err != nil || ci.MemberID != mid {
+		return consttypes.ErrCaregiverInvitationNotFound
+	}
+
+	if ci.Status != consttypes.CIS_PENDING {
+		return consttypes.ErrCaregiverInvitationNotPending
+	}
+
+	ci.Status = consttypes.CIS_REVOKED
+	now := consttypes.TimeNow()
+	ci.RespondedAt = &now
+
+	if _, err := s.rcgin.Update(ctx, *ci); err != nil {
+		return consttypes.ErrFailedToUpdateCaregiverInvitation.Wrap(err)
+	}
+
+	return nil
+}
+
+func (s *CaregiverService) AcceptInvitation(ctx context.Context, req requests.AcceptCaregiverInvitation) (*responses.MemberCaregiver, error) {
+	var (
+		caregiver *models.Caregiver
+	)
+
+	ci, err := s.getPendingInvitation(ctx, req.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	// * link the existing caregiver account if there is one,
+	// * otherwise create a new caregiver account with the invited email
+	user, err := s.ruser.GetByEmail(ctx, ci.Email)
+	if err == nil {
+		if user.Role != consttypes.UR_CAREGIVER {
+			return nil, consttypes.ErrCaregiverInvitationInvalidRole
+		}
+
+		caregiver, err = s.rcare.GetByUserID(ctx, user.ID)
+		if err != nil {
+			return nil, consttypes.ErrCaregiverNotFound.Wrap(err)
+		}
+	} else if errors.Is(err, gorm.ErrRecordNotFound) {
+		if req.Caregiver == nil {
+			return nil, consttypes.ErrCaregiverDataRequired
+		}
+
+		// * the invited email is already proven by the token,
+		// * so the account does not need to be verified again
+		req.Caregiver.User.Email = ci.Email
+		caregiver, err = req.Caregiver.FromMemberAddition()
+		if err != nil {
+			return nil, consttypes.ErrConvertFailed.Wrap(err)
+		}
+	} else {
+		return nil, consttypes.ErrUserNotFound
+	}
+
+	if caregiver.ID != uuid.Nil {
+		if _, err := s.rmcg.GetByMemberIDAndCaregiverID(ctx, ci.MemberID, caregiver.ID); err == nil {
+			return nil, consttypes.ErrCaregiverAlreadyLinked
+		}
+	}
+
+	// * the invitation is claimed in the same transaction that creates
+	// * the caregiver and the link, so of two accepts of the same token
+	// * only one writes anything and a failure leaves it pending
+	mc, claimed, err := s.rcgin.Accept(ctx, *ci, *caregiver)
+	if err != nil {
+		return nil, consttypes.ErrFailedToLinkCaregiver.Wrap(err)
+	}
+
+	if !claimed {
+		return nil, consttypes.ErrCaregiverInvitationNotPending
+	}
+
+	mc, err = s.rmcg.GetByID(ctx, mc.ID)
+	if err != nil {
+		return nil, consttypes.ErrFailedToLinkCaregiver.Wrap(err)
+	}
+
+	mcres, err := mc.ToResponse()
+	if err != nil {
+		return nil, consttypes.ErrConvertFailed.Wrap(err)
+	}
+
+	return mcres, nil
+}
+
+func (s *CaregiverService) DeclineInvitation(ctx context.Context, req requests.DeclineCaregiverInvitation) error {
+	ci, err := s.getPendingInvitation(ctx, req.Token)
+	if err != nil {
+		return err
+	}
+
+	ci.Status = consttypes.CIS_DECLINED
+	now := consttypes.TimeNow()
+	ci.RespondedAt = &now
+
+	if _, err := s.rcgin.Update(ctx, *ci); err != nil {
+		return consttypes.ErrFailedToUpdateCaregiverInvitation.Wrap(err)
+	}
+
+	return nil
+}
+
+// * get the invitation by its token and make sure it can still be answered
+func (s *CaregiverService) getPendingInvitation(ctx context.Context, token string) (*models.CaregiverInvitation, error) {
+	ci, err := s.rcgin.GetByToken(ctx, token)
+	if err != nil {
+		return nil, consttypes.ErrCaregiverInvitationNotFound.Wrap(err)
+	}
+
+	if ci.Status != consttypes.CIS_PENDING {
+		return nil, consttypes.ErrCaregiverInvitationNotPending
+	}
+
+	if ci.IsExpired() {
+		return nil, consttypes.ErrCaregiverInvitationExpired
+	}
+
+	return ci, nil
+}
@@ -1,6 +1,7 @@
 package cartservice
 
 import (
+	"context"
 	"project-skbackend/internal/controllers/requests"
 	"project-skbackend/internal/controllers/responses"
 	"project-skbackend/internal/models"
@@ -27,13 +28,13 @@ type (
 	}
 
 	ICartService interface {
-		Create(req requests.CreateCart, roleres responses.BaseRole) (*responses.Cart, error)
-		Read() ([]*responses.Cart, error)
-		Update(cid uuid.UUID, req requests.UpdateCart) (*responses.Cart, error)
-		Delete(id uuid.UUID) error
+		Create(ctx context.Context, req requests.CreateCart, roleres responses.BaseRole) (*responses.Cart, error)
+		Read(ctx context.Context) ([]*responses.Cart, error)
+		Update(ctx context.Context, cid uuid.UUID, req requests.UpdateCart) (*responses.Cart, error)
+		Delete(ctx context.Context, id uuid.UUID) error
 
-		GetByID(id uuid.UUID) (*responses.Cart, error)
-		FindByRoleRes(roleres responses.BaseRole) ([]*responses.Cart, error)
+		GetByID(ctx context.Context, id uuid.UUID) (*responses.Cart, error)
+		FindByRoleRes(ctx context.Context, roleres responses.BaseRole, mid *uuid.UUID) ([]*responses.Cart, error)
 	}
 )
 
@@ -54,18 +55,18 @@ func NewCartService(
 	}
 }
 
-func (s *CartService) Create(req requests.CreateCart, roleres responses.BaseRole) (*responses.Cart, error) {
+func (s *CartService) Create(ctx context.Context, req requests.CreateCart, roleres responses.BaseRole) (*responses.Cart, error) {
 	var (
 		m   *models.Member
 		err error
 	)
 
-	m, err = s.sbsrl.GetMemberByBaseRole(roleres)
+	m, err = s.sbsrl.GetMemberByBaseRole(ctx, roleres, req.MemberID, consttypes.CA_ORDER)
 	if err != nil {
 		return nil, err
 	}
 
-	meal, err := s.rmeal.GetByID(req.MealID)
+	meal, err := s.rmeal.GetByID(ctx, req.MealID)
 	if err != nil {
 		return nil, err
 	}
@@ -73,17 +74,17 @@ func (s *CartService) Create(req requests.CreateCart, roleres responses.BaseRole
 	// * convert request to model
 	cart, err := req.ToModel(*m, *meal)
 	if err != nil {
-		utlogger.Error(err)
-		return nil, consttypes.ErrConvertFailed
+		utlogger.ErrorContext(ctx, err)
+		return nil, consttypes.ErrConvertFailed.Wrap(err)
 	}
 
 	membres, err := m.ToResponse()
 	if err != nil {
-		return nil, consttypes.ErrConvertFailed
+		return nil, consttypes.ErrConvertFailed.Wrap(err)
 	}
 
 	// * try to get existing cart by meal ID and reference
-	existingcart, err := s.rcart.GetByMealIDAndMemberID(cart.MemberID, cart.MealID)
+	existingcart, err := s.rcart.GetByMealIDAndMemberID(ctx, cart.MemberID, cart.MealID)
 	if err != nil && err != gorm.ErrRecordNotFound {
 		return nil, consttypes.ErrGettingCart
 	}
@@ -92,51 +93,51 @@ func (s *CartService) Create(req requests.CreateCart, roleres responses.BaseRole
 	if existingcart != nil {
 		existingcart.Quantity += req.Quantity
 
-		cart, err = s.rcart.Update(*existingcart)
+		cart, err = s.rcart.Update(ctx, *existingcart)
 		if err != nil {
-			return nil, consttypes.ErrFailedToUpdateCart
+			return nil, consttypes.ErrFailedToUpdateCart.Wrap(err)
 		}
 	} else {
 		// * create a new cart
-		cart, err = s.rcart.Create(*cart)
+		cart, err = s.rcart.Create(ctx, *cart)
 		if err != nil {
-			return nil, consttypes.ErrFailedToCreateCart
+			return nil, consttypes.ErrFailedToCreateCart.Wrap(err)
 		}
 	}
 
 	// * convert cart to response
 	cartres, err := cart.ToResponse(membres)
 	if err != nil {
-		return nil, consttypes.ErrConvertFailed
+		return nil, consttypes.ErrConvertFailed.Wrap(err)
 	}
 
 	return cartres, nil
 }
 
-func (s *CartService) Read() ([]*responses.Cart, error) {
+func (s *CartService) Read(ctx context.Context) ([]*responses.Cart, error) {
 	var (
 		cartreses []*responses.Cart
 	)
 
-	carts, err := s.rcart.Read()
+	carts, err := s.rcart.Read(ctx)
 	if err != nil {
-		return nil, consttypes.ErrFailedToReadCart
+		return nil, consttypes.ErrFailedToReadCart.Wrap(err)
 	}
 
 	for _, cart := range carts {
-		memb, err := s.rmemb.GetByID(cart.MemberID)
+		memb, err := s.rmemb.GetByID(ctx, cart.MemberID)
 		if err != nil {
-			return nil, consttypes.ErrCartNotFound
+			return nil, consttypes.ErrCartNotFound.Wrap(err)
 		}
 
 		membres, err := memb.ToResponse()
 		if err != nil {
-			return nil, consttypes.ErrConvertFailed
+			return nil, consttypes.ErrConvertFailed.Wrap(err)
 		}
 
 		cartres, err := cart.ToResponse(membres)
 		if err != nil {
-			return nil, consttypes.ErrConvertFailed
+			return nil, consttypes.ErrConvertFailed.Wrap(err)
 		}
 
 		cartreses = append(cartreses, cartres)
@@ -145,24 +146,24 @@ func (s *CartService) Read() ([]*responses.Cart, error) {
 	return cartreses, nil
 }
 
-func (s *CartService) Update(cid uuid.UUID, req requests.UpdateCart) (*responses.Cart, error) {
-	cart, err := s.rcart.GetByID(cid)
+func (s *CartService) Update(ctx context.Context, cid uuid.UUID, req requests.UpdateCart) (*responses.Cart, error) {
+	cart, err := s.rcart.GetByID(ctx, cid)
 	if err != nil {
-		return nil, consttypes.ErrCartNotFound
+		return nil, consttypes.ErrCartNotFound.Wrap(err)
 	}
 
 	cart, err = req.ToModel(*cart)
 	if err != nil {
-		return nil, consttypes.ErrConvertFailed
+		return nil, consttypes.ErrConvertFailed.Wrap(err)
 	}
 
 	// * check if the result is 0 or not
 	// * if it is 0, then delete the cart
 	tempquantity := int(cart.Quantity) + req.Quantity
 	if tempquantity < 0 || tempquantity == 0 {
-		err := s.rcart.Delete(*cart)
+		err := s.rcart.Delete(ctx, *cart)
 		if err != nil {
-			return nil, consttypes.ErrFailedToDeleteCart
+			return nil, consttypes.ErrFailedToDeleteCart.Wrap(err)
 		}
 
 		// TODO: implement a correct way to return success deletion
@@ -172,85 +173,85 @@ func (s *CartService) Update(cid uuid.UUID, req requests.UpdateCart) (*responses
 	// * if the value is not 0, then assign the variable
 	cart.Quantity = tempquantity
 
-	cart, err = s.rcart.Update(*cart)
+	cart, err = s.rcart.Update(ctx, *cart)
 	if err != nil {
-		return nil, consttypes.ErrFailedToUpdateCart
+		return nil, consttypes.ErrFailedToUpdateCart.Wrap(err)
 	}
 
-	memb, err := s.rmemb.GetByID(cart.MemberID)
+	memb, err := s.rmemb.GetByID(ctx, cart.MemberID)
 	if err != nil {
-		return nil, consttypes.ErrMemberNotFound
+		return nil, consttypes.ErrMemberNotFound.Wrap(err)
 	}
 
 	membres, err := memb.ToResponse()
 	if err != nil {
-		return nil, consttypes.ErrConvertFailed
+		return nil, consttypes.ErrConvertFailed.Wrap(err)
 	}
 
 	cartres, err := cart.ToResponse(membres)
 	if err != nil {
-		return nil, consttypes.ErrConvertFailed
+		return nil, consttypes.ErrConvertFailed.Wrap(err)
 	}
 
 	return cartres, nil
 }
 
-func (s *CartService) Delete(id uuid.UUID) error {
-	cart, err := s.rcart.GetByID(id)
+func (s *CartService) Delete(ctx context.Context, id uuid.UUID) error {
+	cart, err := s.rcart.GetByID(ctx, id)
 	if err != nil {
-		return consttypes.ErrCartNotFound
+		return consttypes.ErrCartNotFound.Wrap(err)
 	}
 
-	err = s.rcart.Delete(*cart)
+	err = s.rcart.Delete(ctx, *cart)
 	if err != nil {
-		return consttypes.ErrFailedToDeleteCart
+		return consttypes.ErrFailedToDeleteCart.Wrap(err)
 	}
 
 	return nil
 }
 
-func (s *CartService) GetByID(id uuid.UUID) (*responses.Cart, error) {
-	cart, err := s.rcart.GetByID(id)
+func (s *CartService) GetByID(ctx context.Context, id uuid.UUID) (*responses.Cart, error) {
+	cart, err := s.rcart.GetByID(ctx, id)
 	if err != nil {
-		return nil, consttypes.ErrCartNotFound
+		return nil, consttypes.ErrCartNotFound.Wrap(err)
 	}
 
-	memb, err := s.rmemb.GetByID(cart.MemberID)
+	memb, err := s.rmemb.GetByID(ctx, cart.MemberID)
 	if err != nil {
-		return nil, consttypes.ErrMemberNotFound
+		return nil, consttypes.ErrMemberNotFound.Wrap(err)
 	}
 
 	membres, err := memb.ToResponse()
 	if err != nil {
-		return nil, consttypes.ErrConvertFailed
+		return nil, consttypes.ErrConvertFailed.Wrap(err)
 	}
 
 	cartres, err := cart.ToResponse(membres)
 	if err != nil {
-		return nil, consttypes.ErrConvertFailed
+		return nil, consttypes.ErrConvertFailed.Wrap(err)
 	}
 
 	return cartres, nil
 }
 
-func (s *CartService) FindByRoleRes(roleres responses.BaseRole) ([]*responses.Cart, error) {
+func (s *CartService) FindByRoleRes(ctx context.Context, roleres responses.BaseRole, mid *uuid.UUID) ([]*responses.Cart, error) {
 	var (
 		cartreses []*responses.Cart
 	)
 
-	m, err := s.sbsrl.GetMemberByBaseRole(roleres)
+	m, err := s.sbsrl.GetMemberByBaseRole(ctx, roleres, mid, consttypes.CA_VIEW)
 	if err != nil {
-		return nil, consttypes.ErrMemberNotFound
+		return nil, err
 	}
 
 	mres, err := m.ToResponse()
 	if err != nil {
-		return nil, consttypes.ErrConvertFailed
+		return nil, consttypes.ErrConvertFailed.Wrap(err)
 	}
 
-	carts, err := s.rcart.FindByMemberID(m.ID)
+	carts, err := s.rcart.FindByMemberID(ctx, m.ID)
 	if err != nil {
-		return nil, consttypes.ErrGettingCart
+		return nil, consttypes.ErrGettingCart.Wrap(err)
 	}
 
 	for _, cart := range carts {
@@ -1,12 +1,22 @@
 package consumerservice
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"project-skbackend/configs"
 	"project-skbackend/internal/controllers/requests"
 	"project-skbackend/internal/services/mailservice"
+	"project-skbackend/internal/services/producerservice"
+	"project-skbackend/internal/services/webhookservice"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/lifecycle"
+	"project-skbackend/packages/metrics"
+	"project-skbackend/packages/tracing"
 	"project-skbackend/packages/utils/utlogger"
+	"sync"
+	"sync/atomic"
 
 	amqp "github.com/rabbitmq/amqp091-go"
 )
@@ -16,10 +26,18 @@ type (
 		ch    *amqp.Channel
 		cfg   *configs.Config
 		smail mailservice.IMailService
+		swebh webhookservice.IWebhookService
+		sprod producerservice.IProducerService
+
+		// * the consumers in flight, stop waits for them
+		wg        sync.WaitGroup
+		consuming atomic.Bool
 	}
 
 	IConsumerService interface {
-		ConsumeMail()
+		lifecycle.Component
+		ConsumeMail() error
+		ConsumeWebhook() error
 	}
 )
 
@@ -27,60 +45,241 @@ func NewConsumerService(
 	ch *amqp.Channel,
 	cfg *configs.Config,
 	smail mailservice.IMailService,
+	swebh webhookservice.IWebhookService,
+	sprod producerservice.IProducerService,
 ) *ConsumerService {
 	return &ConsumerService{
 		ch:    ch,
 		cfg:   cfg,
 		smail: smail,
+		swebh: swebh,
+		sprod: sprod,
 	}
 }
 
-func (s *ConsumerService) ConsumeTask() {
-	s.ConsumeMail()
+func (s *ConsumerService) Name() string {
+	return "queue consumers"
 }
 
-func (s *ConsumerService) ConsumeMail() {
+func (s *ConsumerService) Start(ctx context.Context) error {
+	if err := s.ConsumeMail(); err != nil {
+		return err
+	}
+
+	if err := s.ConsumeWebhook(); err != nil {
+		return err
+	}
+
+	s.consuming.Store(true)
+
+	return nil
+}
+
+// * the consumers are cancelled so no new message is delivered, then the
+// * messages in flight are finished before the channel is closed
+func (s *ConsumerService) Stop(ctx context.Context) error {
+	s.consuming.Store(false)
+
+	var (
+		errs []error
+	)
+
+	for _, qname := range []string{s.cfg.Queue.QueueMail.QueueName, s.cfg.Queue.QueueWebhook.QueueName} {
+		if err := s.ch.Cancel(qname, false); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		errs = append(errs, fmt.Errorf("messages in flight not finished: %w", ctx.Err()))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *ConsumerService) Health() lifecycle.Health {
+	if s.ch.IsClosed() {
+		return lifecycle.Health{Error: "queue channel closed"}
+	}
+
+	return lifecycle.Health{Live: true, Ready: s.consuming.Load()}
+}
+
+func (s *ConsumerService) ConsumeMail() error {
 	var (
 		qname = s.cfg.Queue.QueueMail.QueueName
 	)
 	// Listen to Queue
 	messages, err := s.ch.Consume(
 		qname, // queue
-		"",    // consumer
-		true,  // auto ack
+		qname, // consumer
+		false, // auto ack
 		false, // exclusive
 		false, // no local
 		false, // no wait
 		nil,   // args
 	)
+	if err != nil {
+		utlogger.Error(err)
+		return err
+	}
 
-	utlogger.Fatal(err)
-
+	s.wg.Add(1)
 	go func() {
+		defer s.wg.Done()
+
 		for d := range messages {
-			utlogger.Info(fmt.Sprintf("Received a message: %s", d.Body))
+			s.processMail(d, qname)
+		}
+	}()
 
-			var (
-				data requests.SendEmail
-			)
+	utlogger.Info(fmt.Sprintf("Service for %s is running, waiting for messages from queue!", qname))
 
-			err := json.Unmarshal(d.Body, &data)
-			if err != nil {
-				utlogger.Error(fmt.Errorf("Unable to unmarshal message: %w", err))
-				return
-			}
+	return nil
+}
+
+// * the deliveries are sent by a pool of workers, so a slow
+// * endpoint does not hold back the deliveries of the others
+func (s *ConsumerService) ConsumeWebhook() error {
+	var (
+		qname = s.cfg.Queue.QueueWebhook.QueueName
+	)
+	// Listen to Queue
+	messages, err := s.ch.Consume(
+		qname, // queue
+		qname, // consumer
+		false, // auto ack
+		false, // exclusive
+		false, // no local
+		false, // no wait
+		nil,   // args
+	)
+	if err != nil {
+		utlogger.Error(err)
+		return err
+	}
 
-			utlogger.Info(fmt.Sprintf("Reference data mail: %v", data.Data))
+	for i := 0; i < max(s.cfg.Webhook.Workers, 1); i++ {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
 
-			err = s.smail.SendEmail(data)
-			if err != nil {
-				utlogger.Error(fmt.Errorf("Unable to send email: %v", err))
+			for d := range messages {
+				s.processWebhook(d, qname)
 			}
+		}()
+	}
+
+	utlogger.Info(fmt.Sprintf("Service for %s is running, waiting for messages from queue!", qname))
+
+	return nil
+}
+
+// * each message is processed in a span continued from the trace of
+// * whoever published it
+func (s *ConsumerService) processMail(d amqp.Delivery, qname string) {
+	var (
+		data requests.SendEmail
+		err  error
+	)
+
+	ctx, span := tracing.StartConsume(d, qname)
+	defer func() { tracing.End(span, err) }()
+	ctx = withRequestID(ctx, d)
+
+	err = json.Unmarshal(d.Body, &data)
+	if err != nil {
+		utlogger.ErrorContext(ctx, fmt.Errorf("Unable to unmarshal message: %w", err))
+		s.deadLetter(ctx, d, qname, err)
+		return
+	}
+
+	// * the data of the mail is redacted, it has the links and the tokens
+	utlogger.DebugContext(ctx, "Received a message", data)
+
+	err = s.smail.SendEmail(ctx, data)
+	metrics.ObserveEmail(data.Template, err)
+	if err != nil {
+		utlogger.ErrorContext(ctx, fmt.Errorf("Unable to send email: %v", err))
+		s.deadLetter(ctx, d, qname, err)
+		return
+	}
+
+	utlogger.InfoContext(ctx, fmt.Sprintf("Send mail %s ok", data.Template))
+	metrics.ObserveConsume(qname, nil)
+	s.ack(ctx, d)
+}
+
+func (s *ConsumerService) processWebhook(d amqp.Delivery, qname string) {
+	var (
+		data requests.DeliverWebhook
+		err  error
+	)
 
-			ok := err == nil
-			utlogger.Info(fmt.Sprintf("Send mail ok: %v", ok))
+	ctx, span := tracing.StartConsume(d, qname)
+	defer func() { tracing.End(span, err) }()
+	ctx = withRequestID(ctx, d)
+
+	err = json.Unmarshal(d.Body, &data)
+	if err != nil {
+		utlogger.ErrorContext(ctx, fmt.Errorf("Unable to unmarshal message: %w", err))
+		s.deadLetter(ctx, d, qname, err)
+		return
+	}
+
+	// * a failed send is retried by the webhook itself, only
+	// * a delivery that could not be read is dead lettered
+	err = s.swebh.Deliver(ctx, data.DeliveryID)
+	if err != nil {
+		utlogger.ErrorContext(ctx, fmt.Errorf("Unable to deliver webhook %s: %w", data.DeliveryID, err))
+	}
+
+	if errors.Is(err, consttypes.ErrWebhookDeliveryNotFound) {
+		s.deadLetter(ctx, d, qname, err)
+		return
+	}
+
+	metrics.ObserveConsume(qname, nil)
+	s.ack(ctx, d)
+}
+
+// * the logs of a message share the id of the request it was published in
+func withRequestID(ctx context.Context, d amqp.Delivery) context.Context {
+	if rid, ok := d.Headers[utlogger.HeaderRequestID].(string); ok && rid != "" {
+		return utlogger.WithRequestID(ctx, rid)
+	}
+
+	return ctx
+}
+
+func (s *ConsumerService) ack(ctx context.Context, d amqp.Delivery) {
+	if err := d.Ack(false); err != nil {
+		utlogger.ErrorContext(ctx, fmt.Errorf("Unable to ack message: %w", err))
+	}
+}
+
+// * the message is acked once it is in the dead letter queue, it is
+// * requeued instead when it could not be dead lettered
+func (s *ConsumerService) deadLetter(ctx context.Context, d amqp.Delivery, qname string, reason error) {
+	metrics.ObserveConsume(qname, reason)
+
+	err := s.sprod.PublishDeadLetter(ctx, d, qname, reason)
+	if err != nil {
+		utlogger.ErrorContext(ctx, fmt.Errorf("Unable to dead letter message: %w", err))
+
+		if err := d.Nack(false, true); err != nil {
+			utlogger.ErrorContext(ctx, fmt.Errorf("Unable to nack message: %w", err))
 		}
-	}()
+		return
+	}
 
-	utlogger.Info(fmt.Sprintf("Service for %s is running, waiting for messages from queue!", qname))
+	s.ack(ctx, d)
 }
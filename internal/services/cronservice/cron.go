@@ -1,23 +1,42 @@
 package cronservice
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"project-skbackend/configs"
+	"project-skbackend/internal/repositories/encryptionrepo"
 	"project-skbackend/internal/repositories/orderrepo"
+	"project-skbackend/internal/services/privacyservice"
+	"project-skbackend/internal/services/searchservice"
+	"project-skbackend/internal/services/webhookservice"
 	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/lifecycle"
+	"project-skbackend/packages/metrics"
+	"project-skbackend/packages/tracing"
 	"project-skbackend/packages/utils/utlogger"
 	"time"
 
 	"github.com/go-co-op/gocron/v2"
+	"github.com/redis/go-redis/v9"
 )
 
 type (
 	CronService struct {
 		cfg  *configs.Config
 		rodr orderrepo.IOrderRepository
+		renc encryptionrepo.IEncryptionRepository
+
+		sprvc privacyservice.IPrivacyService
+		swebh webhookservice.IWebhookService
+		ssrch searchservice.ISearchService
+
+		gsch gocron.Scheduler
+		elec *elector
 	}
 
 	ICronService interface {
+		lifecycle.Component
 		Init() (gocron.Scheduler, error)
 	}
 )
@@ -25,10 +44,21 @@ type (
 func NewCronService(
 	cfg *configs.Config,
 	rodr orderrepo.IOrderRepository,
+	renc encryptionrepo.IEncryptionRepository,
+	sprvc privacyservice.IPrivacyService,
+	swebh webhookservice.IWebhookService,
+	ssrch searchservice.ISearchService,
+	rdb *redis.Client,
 ) *CronService {
 	return &CronService{
 		cfg:  cfg,
+		elec: newElector(rdb, time.Duration(cfg.Cron.LeaderTTL)*time.Second),
 		rodr: rodr,
+		renc: renc,
+
+		sprvc: sprvc,
+		swebh: swebh,
+		ssrch: ssrch,
 	}
 }
 
@@ -39,9 +69,15 @@ func (s *CronService) Init() (gocron.Scheduler, error) {
 		return nil, err
 	}
 
-	// * define a new scheduler instance
+	// * define a new scheduler instance, the shutdown waits for the jobs
+	// * running at most the shutdown timeout. the runs of the jobs are
+	// * recorded by their name. every replica schedules the jobs, but only
+	// * the one holding the lease runs them
 	gsch, err := gocron.NewScheduler(
 		gocron.WithLocation(tz),
+		gocron.WithDistributedElector(s.elec),
+		gocron.WithStopTimeout(time.Duration(s.cfg.App.ShutdownTimeout)*time.Second),
+		gocron.WithMonitor(metrics.CronMonitor{}),
 	)
 
 	if err != nil {
@@ -51,12 +87,70 @@ func (s *CronService) Init() (gocron.Scheduler, error) {
 	// * add a order job
 	s.orderSchedule(gsch)
 
+	// * add a encryption job
+	s.encryptionSchedule(gsch)
+
+	// * add a data request job
+	s.dataRequestSchedule(gsch)
+
+	// * add a webhook job
+	s.webhookSchedule(gsch)
+
+	// * add a search job
+	s.searchSchedule(gsch)
+
 	// * start the scheduler
 	gsch.Start()
 
 	return gsch, nil
 }
 
+func (s *CronService) Name() string {
+	return "cron scheduler"
+}
+
+func (s *CronService) Start(ctx context.Context) error {
+	gsch, err := s.Init()
+	if err != nil {
+		return err
+	}
+
+	s.gsch = gsch
+
+	return nil
+}
+
+// * no job is started anymore and the running ones are waited for
+func (s *CronService) Stop(ctx context.Context) error {
+	if s.gsch == nil {
+		return nil
+	}
+
+	err := s.gsch.Shutdown()
+	if rerr := s.elec.release(ctx); rerr != nil {
+		utlogger.ErrorContext(ctx, rerr)
+	}
+
+	return err
+}
+
+func (s *CronService) Health() lifecycle.Health {
+	return lifecycle.Health{Live: true, Ready: s.gsch != nil}
+}
+
+// * every run of a job is a trace of its own, the queries and the messages
+// * of the run are its children
+func (s *CronService) traced(name string, fn func(ctx context.Context) error) func() error {
+	return func() error {
+		ctx, span := tracing.Start(context.Background(), fmt.Sprintf("cron %s", name))
+
+		err := fn(ctx)
+		tracing.End(span, err)
+
+		return err
+	}
+}
+
 func (s *CronService) orderSchedule(gsch gocron.Scheduler) {
 	var (
 		errs []error
@@ -79,16 +173,29 @@ func (s *CronService) scheduleOrderCancelled(gsch gocron.Scheduler) error {
 			time.Duration(1)*time.Minute,
 		),
 		gocron.NewTask(
-			func() error {
-				err := s.rodr.UpdateAutomaticallyStatus(consttypes.OS_CANCELLED, s.cfg.OrderBuffer.AutomaticallyCancelled, []consttypes.OrderStatus{consttypes.OS_PLACED})
+			s.traced("order-cancelled", func(ctx context.Context) error {
+				ids, err := s.rodr.UpdateAutomaticallyStatus(ctx, consttypes.OS_CANCELLED, s.cfg.OrderBuffer.AutomaticallyCancelled, []consttypes.OrderStatus{consttypes.OS_PLACED})
+				metrics.ObserveOrderTransition(consttypes.OS_PLACED.String(), consttypes.OS_CANCELLED.String(), len(ids))
+
+				// * the cancelled orders are sent even when one of them failed
+				for _, id := range ids {
+					order, oerr := s.rodr.GetByID(ctx, id)
+					if oerr != nil {
+						continue
+					}
+
+					s.swebh.DispatchOrder(ctx, consttypes.WET_ORDER_STATUS_CHANGED, *order, consttypes.OS_PLACED)
+				}
+
 				if err != nil {
-					utlogger.Error(err)
+					utlogger.ErrorContext(ctx, err)
 					return err
 				}
 
 				return nil
-			},
+			}),
 		),
+		gocron.WithName("order-cancelled"),
 	)
 
 	if err != nil {
@@ -100,3 +207,241 @@ func (s *CronService) scheduleOrderCancelled(gsch gocron.Scheduler) error {
 
 	return nil
 }
+
+func (s *CronService) encryptionSchedule(gsch gocron.Scheduler) {
+	var (
+		errs []error
+	)
+
+	err := s.scheduleReEncrypt(gsch)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	if len(errs) != 0 {
+		utlogger.Error(err)
+	}
+}
+
+// * moves the data encrypted with an old key or not encrypted yet to
+// * the current key, old keys can be removed once the job is finished
+func (s *CronService) scheduleReEncrypt(gsch gocron.Scheduler) error {
+	_, err := gsch.NewJob(
+		gocron.DurationJob(
+			time.Duration(s.cfg.EncryptionReEncrypt.Interval)*time.Minute,
+		),
+		gocron.NewTask(
+			s.traced("re-encrypt", func(ctx context.Context) error {
+				count, err := s.renc.ReEncrypt(ctx, s.cfg.EncryptionReEncrypt.BatchSize)
+				if err != nil {
+					utlogger.ErrorContext(ctx, err)
+					return consttypes.ErrFailedToReEncrypt.Wrap(err)
+				}
+
+				if count > 0 {
+					utlogger.InfoContext(ctx, fmt.Sprintf("Re-encrypted %d rows", count))
+				}
+
+				return nil
+			}),
+		),
+		gocron.WithName("re-encrypt"),
+		gocron.WithStartAt(
+			gocron.WithStartImmediately(),
+		),
+		gocron.WithSingletonMode(gocron.LimitModeReschedule),
+	)
+
+	if err != nil {
+		utlogger.Error(err)
+		return err
+	}
+
+	utlogger.Info(fmt.Sprintf("Service for Cron %s Running!", "Re-encrypt Data"))
+
+	return nil
+}
+
+func (s *CronService) dataRequestSchedule(gsch gocron.Scheduler) {
+	var (
+		errs []error
+	)
+
+	err := s.scheduleDataExport(gsch)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	err = s.scheduleDataErasure(gsch)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	if len(errs) != 0 {
+		utlogger.Error(errors.Join(errs...))
+	}
+}
+
+// * builds the requested data exports and removes the expired ones
+func (s *CronService) scheduleDataExport(gsch gocron.Scheduler) error {
+	_, err := gsch.NewJob(
+		gocron.DurationJob(
+			time.Duration(1)*time.Minute,
+		),
+		gocron.NewTask(
+			s.traced("data-export", func(ctx context.Context) error {
+				err := s.sprvc.ProcessDataExports(ctx)
+				if err != nil {
+					utlogger.ErrorContext(ctx, err)
+					return err
+				}
+
+				return nil
+			}),
+		),
+		gocron.WithName("data-export"),
+		gocron.WithSingletonMode(gocron.LimitModeReschedule),
+	)
+
+	if err != nil {
+		utlogger.Error(err)
+		return err
+	}
+
+	utlogger.Info(fmt.Sprintf("Service for Cron %s Running!", "Process Data Export"))
+
+	return nil
+}
+
+// * erases the data of the users that requested it
+func (s *CronService) scheduleDataErasure(gsch gocron.Scheduler) error {
+	_, err := gsch.NewJob(
+		gocron.DurationJob(
+			time.Duration(1)*time.Minute,
+		),
+		gocron.NewTask(
+			s.traced("data-erasure", func(ctx context.Context) error {
+				err := s.sprvc.ProcessDataErasures(ctx)
+				if err != nil {
+					utlogger.ErrorContext(ctx, err)
+					return err
+				}
+
+				return nil
+			}),
+		),
+		gocron.WithName("data-erasure"),
+		gocron.WithSingletonMode(gocron.LimitModeReschedule),
+	)
+
+	if err != nil {
+		utlogger.Error(err)
+		return err
+	}
+
+	utlogger.Info(fmt.Sprintf("Service for Cron %s Running!", "Process Data Erasure"))
+
+	return nil
+}
+
+func (s *CronService) webhookSchedule(gsch gocron.Scheduler) {
+	var (
+		errs []error
+	)
+
+	err := s.scheduleWebhookRetry(gsch)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	if len(errs) != 0 {
+		utlogger.Error(errors.Join(errs...))
+	}
+}
+
+// * publishes the failed webhook deliveries again once their backoff passed
+func (s *CronService) scheduleWebhookRetry(gsch gocron.Scheduler) error {
+	_, err := gsch.NewJob(
+		gocron.DurationJob(
+			time.Duration(1)*time.Minute,
+		),
+		gocron.NewTask(
+			s.traced("webhook-retry", func(ctx context.Context) error {
+				count, err := s.swebh.RetryDue(ctx)
+				if err != nil {
+					utlogger.ErrorContext(ctx, err)
+					return err
+				}
+
+				if count > 0 {
+					utlogger.InfoContext(ctx, fmt.Sprintf("Requeued %d webhook deliveries", count))
+				}
+
+				return nil
+			}),
+		),
+		gocron.WithName("webhook-retry"),
+		gocron.WithSingletonMode(gocron.LimitModeReschedule),
+	)
+
+	if err != nil {
+		utlogger.Error(err)
+		return err
+	}
+
+	utlogger.Info(fmt.Sprintf("Service for Cron %s Running!", "Retry Webhook Delivery"))
+
+	return nil
+}
+
+func (s *CronService) searchSchedule(gsch gocron.Scheduler) {
+	var (
+		errs []error
+	)
+
+	err := s.scheduleSearchReindex(gsch)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	if len(errs) != 0 {
+		utlogger.Error(errors.Join(errs...))
+	}
+}
+
+// * rewrites the search tags of the meals whose category, partner, illnesses
+// * or allergies were renamed. it also runs on start for the seeded meals
+func (s *CronService) scheduleSearchReindex(gsch gocron.Scheduler) error {
+	_, err := gsch.NewJob(
+		gocron.DurationJob(
+			time.Duration(s.cfg.Search.Reindex)*time.Minute,
+		),
+		gocron.NewTask(
+			s.traced("search-reindex", func(ctx context.Context) error {
+				count, err := s.ssrch.IndexMeals(ctx)
+				if err != nil {
+					utlogger.ErrorContext(ctx, err)
+					return err
+				}
+
+				if count > 0 {
+					utlogger.InfoContext(ctx, fmt.Sprintf("Reindexed %d meals", count))
+				}
+
+				return nil
+			}),
+		),
+		gocron.WithName("search-reindex"),
+		gocron.WithSingletonMode(gocron.LimitModeReschedule),
+		gocron.WithStartAt(gocron.WithStartImmediately()),
+	)
+
+	if err != nil {
+		utlogger.Error(err)
+		return err
+	}
+
+	utlogger.Info(fmt.Sprintf("Service for Cron %s Running!", "Reindex Meal Search"))
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package cronservice
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-co-op/gocron/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	leaderKey = "cron:leader"
+)
+
+var (
+	// * the lease is only renewed or released by the instance holding it
+	renewLease = redis.NewScript(`
+		if redis.call("GET", KEYS[1]) == ARGV[1] then
+			return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+		end
+		return 0
+	`)
+
+	releaseLease = redis.NewScript(`
+		if redis.call("GET", KEYS[1]) == ARGV[1] then
+			return redis.call("DEL", KEYS[1])
+		end
+		return 0
+	`)
+
+	errNotLeader = errors.New("another instance runs the cron jobs")
+)
+
+type (
+	// * elector lets only one of the replicas run the jobs. the replica
+	// * holding the lease renews it on every run, another one takes it
+	// * over once it expired
+	elector struct {
+		rdb *redis.Client
+		id  string
+		ttl time.Duration
+	}
+)
+
+var _ gocron.Elector = (*elector)(nil)
+
+func newElector(rdb *redis.Client, ttl time.Duration) *elector {
+	return &elector{
+		rdb: rdb,
+		id:  uuid.NewString(),
+		ttl: ttl,
+	}
+}
+
+func (e *elector) IsLeader(ctx context.Context) error {
+	renewed, err := renewLease.Run(ctx, e.rdb, []string{leaderKey}, e.id, e.ttl.Milliseconds()).Int()
+	if err != nil {
+		return err
+	}
+
+	if renewed == 1 {
+		return nil
+	}
+
+	ok, err := e.rdb.SetNX(ctx, leaderKey, e.id, e.ttl).Result()
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return errNotLeader
+	}
+
+	return nil
+}
+
+// * lets another replica take over right away instead of once it expired
+func (e *elector) release(ctx context.Context) error {
+	return releaseLease.Run(ctx, e.rdb, []string{leaderKey}, e.id).Err()
+}
@@ -1,9 +1,11 @@
 package donationservice
 
 import (
+	"context"
 	"project-skbackend/internal/controllers/requests"
 	"project-skbackend/internal/controllers/responses"
 	"project-skbackend/internal/repositories/donationrepo"
+	"project-skbackend/packages/metrics"
 	"project-skbackend/packages/utils/utpagination"
 
 	"github.com/google/uuid"
@@ -15,11 +17,11 @@ type (
 	}
 
 	IDonationService interface {
-		Read() ([]*responses.Donation, error)
-		Update(req requests.UpdateDonation, donid uuid.UUID) (*responses.Donation, error)
-		Delete(donid uuid.UUID) error
-		FindAll(p utpagination.Pagination) (*utpagination.Pagination, error)
-		GetByID(donid uuid.UUID) (*responses.Donation, error)
+		Read(ctx context.Context) ([]*responses.Donation, error)
+		Update(ctx context.Context, req requests.UpdateDonation, donid uuid.UUID) (*responses.Donation, error)
+		Delete(ctx context.Context, donid uuid.UUID) error
+		FindAll(ctx context.Context, p utpagination.Pagination) (*utpagination.Pagination, error)
+		GetByID(ctx context.Context, donid uuid.UUID) (*responses.Donation, error)
 	}
 )
 
@@ -31,13 +33,13 @@ func NewDonationService(
 	}
 }
 
-func (s *DonationService) Read() ([]*responses.Donation, error) {
+func (s *DonationService) Read(ctx context.Context) ([]*responses.Donation, error) {
 	var (
 		donreses []*responses.Donation
 		err      error
 	)
 
-	donations, err := s.rdonation.Read()
+	donations, err := s.rdonation.Read(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -54,26 +56,32 @@ func (s *DonationService) Read() ([]*responses.Donation, error) {
 	return donreses, nil
 }
 
-func (s *DonationService) Update(req requests.UpdateDonation, donid uuid.UUID) (*responses.Donation, error) {
+func (s *DonationService) Update(ctx context.Context, req requests.UpdateDonation, donid uuid.UUID) (*responses.Donation, error) {
 	var (
 		err error
 	)
 
-	donation, err := s.rdonation.GetByID(donid)
+	donation, err := s.rdonation.GetByID(ctx, donid)
 	if err != nil {
 		return nil, err
 	}
 
+	previous := donation.Status
+
 	donation, err = req.ToModel(*donation)
 	if err != nil {
 		return nil, err
 	}
 
-	donation, err = s.rdonation.Update(*donation)
+	donation, err = s.rdonation.Update(ctx, *donation)
 	if err != nil {
 		return nil, err
 	}
 
+	if donation.Status != previous {
+		metrics.ObserveDonation(donation.Status.String())
+	}
+
 	donationres, err := donation.ToResponse()
 	if err != nil {
 		return nil, err
@@ -82,17 +90,17 @@ func (s *DonationService) Update(req requests.UpdateDonation, donid uuid.UUID) (
 	return donationres, nil
 }
 
-func (s *DonationService) Delete(donid uuid.UUID) error {
-	donation, err := s.rdonation.GetByID(donid)
+func (s *DonationService) Delete(ctx context.Context, donid uuid.UUID) error {
+	donation, err := s.rdonation.GetByID(ctx, donid)
 	if err != nil {
 		return err
 	}
 
-	return s.rdonation.Delete(*donation)
+	return s.rdonation.Delete(ctx, *donation)
 }
 
-func (s *DonationService) FindAll(p utpagination.Pagination) (*utpagination.Pagination, error) {
-	donations, err := s.rdonation.FindAll(p)
+func (s *DonationService) FindAll(ctx context.Context, p utpagination.Pagination) (*utpagination.Pagination, error) {
+	donations, err := s.rdonation.FindAll(ctx, p)
 	if err != nil {
 		return nil, err
 	}
@@ -100,8 +108,8 @@ func (s *DonationService) FindAll(p utpagination.Pagination) (*utpagination.Pagi
 	return donations, nil
 }
 
-func (s *DonationService) GetByID(donid uuid.UUID) (*responses.Donation, error) {
-	donation, err := s.rdonation.GetByID(donid)
+func (s *DonationService) GetByID(ctx context.Context, donid uuid.UUID) (*responses.Donation, error) {
+	donation, err := s.rdonation.GetByID(ctx, donid)
 	if err != nil {
 		return nil, err
 	}
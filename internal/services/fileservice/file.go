@@ -10,6 +10,7 @@ import (
 	"project-skbackend/internal/repositories/donationproofrepo"
 	"project-skbackend/internal/repositories/donationrepo"
 	"project-skbackend/internal/repositories/imagerepo"
+	"project-skbackend/internal/repositories/mealcategoryrepo"
 	"project-skbackend/internal/repositories/userimagerepo"
 	"project-skbackend/internal/repositories/userrepo"
 	"project-skbackend/packages/consttypes"
@@ -24,7 +25,6 @@ import (
 type (
 	FileService struct {
 		cfg *configs.Config
-		ctx context.Context
 
 		minio minio.Client
 
@@ -33,6 +33,7 @@ type (
 		ruimg userimagerepo.IUserImageRepo
 		rdona donationrepo.IDonationRepository
 		rdnpr donationproofrepo.IDonationProofRepository
+		rmcat mealcategoryrepo.IMealCategoryRepository
 
 		me    string
 		mussl bool
@@ -43,26 +44,26 @@ type (
 	}
 
 	IFileService interface {
-		Upload(req utfile.FileMultipart) (string, error)
-		UploadProfilePicture(uid uuid.UUID, fileheader *multipart.FileHeader) error
-		UploadDonationProof(did uuid.UUID, fileheader *multipart.FileHeader) error
-		UploadMealImages(mid uuid.UUID, fileheader []*multipart.FileHeader) error
+		Upload(ctx context.Context, req utfile.FileMultipart) (string, error)
+		UploadProfilePicture(ctx context.Context, uid uuid.UUID, fileheader *multipart.FileHeader) error
+		UploadDonationProof(ctx context.Context, did uuid.UUID, fileheader *multipart.FileHeader) error
+		UploadMealImages(ctx context.Context, mid uuid.UUID, fileheader []*multipart.FileHeader) error
+		UploadMealCategoryImage(ctx context.Context, mcid uuid.UUID, fileheader *multipart.FileHeader) error
 	}
 )
 
 func NewFileService(
 	cfg *configs.Config,
-	ctx context.Context,
 	minio minio.Client,
 	ruser userrepo.IUserRepository,
 	rimg imagerepo.IImageRepo,
 	ruimg userimagerepo.IUserImageRepo,
 	rdona donationrepo.IDonationRepository,
 	rdnpr donationproofrepo.IDonationProofRepository,
+	rmcat mealcategoryrepo.IMealCategoryRepository,
 ) *FileService {
 	return &FileService{
 		cfg: cfg,
-		ctx: ctx,
 
 		minio: minio,
 
@@ -71,6 +72,7 @@ func NewFileService(
 		ruimg: ruimg,
 		rdona: rdona,
 		rdnpr: rdnpr,
+		rmcat: rmcat,
 
 		me:    cfg.Minio.Endpoint,
 		mussl: cfg.Minio.UseSSL,
@@ -81,15 +83,15 @@ func NewFileService(
 	}
 }
 
-func (s *FileService) UploadProfilePicture(uid uuid.UUID, fileheader *multipart.FileHeader) error {
+func (s *FileService) UploadProfilePicture(ctx context.Context, uid uuid.UUID, fileheader *multipart.FileHeader) error {
 	var (
 		err error
 	)
 
-	user, err := s.ruser.GetByID(uid)
+	user, err := s.ruser.GetByID(ctx, uid)
 	if err != nil {
-		utlogger.Error(err)
-		return consttypes.ErrUserNotFound
+		utlogger.ErrorContext(ctx, err)
+		return consttypes.ErrUserNotFound.Wrap(err)
 	}
 
 	filename, err := utfile.ValidateFile(fileheader, &utfile.ValidateFileOpts{
@@ -100,46 +102,46 @@ func (s *FileService) UploadProfilePicture(uid uuid.UUID, fileheader *multipart.
 		},
 	})
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return err
 	}
 
 	fileupload := utfile.NewFileUpload(fileheader)
-	url, err := s.Upload(*fileupload)
+	url, err := s.Upload(ctx, *fileupload)
 	if err != nil {
-		utlogger.Error(err)
-		return consttypes.ErrFailedToUploadFile
+		utlogger.ErrorContext(ctx, err)
+		return consttypes.ErrFailedToUploadFile.Wrap(err)
 	}
 
 	image := models.NewProfileImage(
 		*filename,
 		url,
 	)
-	image, err = s.rimg.Create(*image)
+	image, err = s.rimg.Create(ctx, *image)
 	if err != nil {
-		utlogger.Error(err)
-		return consttypes.ErrFailedToCreateImage
+		utlogger.ErrorContext(ctx, err)
+		return consttypes.ErrFailedToCreateImage.Wrap(err)
 	}
 
-	err = s.CheckAndSaveUserImage(*user, *image)
+	err = s.CheckAndSaveUserImage(ctx, *user, *image)
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return consttypes.ErrGeneralFailed("check and save user image", err.Error())
 	}
 
 	return nil
 }
 
-func (s *FileService) UploadDonationProof(did uuid.UUID, fileheader *multipart.FileHeader) error {
+func (s *FileService) UploadDonationProof(ctx context.Context, did uuid.UUID, fileheader *multipart.FileHeader) error {
 	var (
 		err error
 	)
 
 	// * get donation by its id
-	donation, err := s.rdona.GetByID(did)
+	donation, err := s.rdona.GetByID(ctx, did)
 	if err != nil {
-		utlogger.Error(err)
-		return consttypes.ErrUserNotFound
+		utlogger.ErrorContext(ctx, err)
+		return consttypes.ErrUserNotFound.Wrap(err)
 	}
 
 	// * validate the file based on the custom options
@@ -151,17 +153,17 @@ func (s *FileService) UploadDonationProof(did uuid.UUID, fileheader *multipart.F
 		},
 	})
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return err
 	}
 
 	// * construct a new fileupload request
 	// * and then upload the file using the upload service
 	fileupload := utfile.NewFileUpload(fileheader)
-	url, err := s.Upload(*fileupload)
+	url, err := s.Upload(ctx, *fileupload)
 	if err != nil {
-		utlogger.Error(err)
-		return consttypes.ErrFailedToUploadFile
+		utlogger.ErrorContext(ctx, err)
+		return consttypes.ErrFailedToUploadFile.Wrap(err)
 	}
 
 	// * construct a new donation proof image model
@@ -170,10 +172,10 @@ func (s *FileService) UploadDonationProof(did uuid.UUID, fileheader *multipart.F
 		*filename,
 		url,
 	)
-	image, err = s.rimg.Create(*image)
+	image, err = s.rimg.Create(ctx, *image)
 	if err != nil {
-		utlogger.Error(err)
-		return consttypes.ErrFailedToCreateImage
+		utlogger.ErrorContext(ctx, err)
+		return consttypes.ErrFailedToCreateImage.Wrap(err)
 	}
 
 	// * construct a new donation proof model
@@ -181,16 +183,16 @@ func (s *FileService) UploadDonationProof(did uuid.UUID, fileheader *multipart.F
 	donationproof := image.CreateDonationProof(
 		*donation,
 	)
-	if _, err := s.rdnpr.Create(*donationproof); err != nil {
-		utlogger.Error(err)
+	if _, err := s.rdnpr.Create(ctx, *donationproof); err != nil {
+		utlogger.ErrorContext(ctx, err)
 		return consttypes.ErrGeneralFailed("creating donation proof", err.Error())
 	}
 
 	return nil
 }
 
-func (s *FileService) CheckAndSaveUserImage(u models.User, image models.Image) error {
-	ui, err := s.ruimg.GetByUserID(u.ID)
+func (s *FileService) CheckAndSaveUserImage(ctx context.Context, u models.User, image models.Image) error {
+	ui, err := s.ruimg.GetByUserID(ctx, u.ID)
 	if ui == nil || (err != nil && errors.Is(err, gorm.ErrRecordNotFound)) {
 		if !errors.Is(err, gorm.ErrRecordNotFound) {
 			return consttypes.ErrGeneralFailed("get by user id", err.Error())
@@ -200,14 +202,14 @@ func (s *FileService) CheckAndSaveUserImage(u models.User, image models.Image) e
 		updatedui := image.CreateUserImage(
 			u,
 		)
-		if _, err := s.ruimg.Create(*updatedui); err != nil {
-			utlogger.Error(err)
+		if _, err := s.ruimg.Create(ctx, *updatedui); err != nil {
+			utlogger.ErrorContext(ctx, err)
 			return consttypes.ErrGeneralFailed("creating user image", err.Error())
 		}
 	} else {
 		// * handle the case where there is a user image
 		updatedui := image.UpdateUserImage(*ui)
-		if _, err := s.ruimg.Update(*updatedui); err != nil {
+		if _, err := s.ruimg.Update(ctx, *updatedui); err != nil {
 			return consttypes.ErrGeneralFailed("updating user image", err.Error())
 		}
 	}
@@ -215,18 +217,70 @@ func (s *FileService) CheckAndSaveUserImage(u models.User, image models.Image) e
 	return nil
 }
 
-func (s *FileService) UploadMealImages(mid uuid.UUID, fileheader []*multipart.FileHeader) error {
+func (s *FileService) UploadMealImages(ctx context.Context, mid uuid.UUID, fileheader []*multipart.FileHeader) error {
 	// TODO: implement uploading meal images
 	return nil
 }
 
-func (s *FileService) Upload(req utfile.FileMultipart) (string, error) {
+// * the previous image of the category is kept, only the
+// * category is pointed to the new one
+func (s *FileService) UploadMealCategoryImage(ctx context.Context, mcid uuid.UUID, fileheader *multipart.FileHeader) error {
+	var (
+		err error
+	)
+
+	// * get meal category by its id
+	_, err = s.rmcat.GetByID(ctx, mcid)
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return consttypes.ErrMealCategoryNotFound.Wrap(err)
+	}
+
+	// * validate the file based on the custom options
+	filename, err := utfile.ValidateFile(fileheader, &utfile.ValidateFileOpts{
+		AllowedExtensions: []string{".jpg", ".jpeg", ".png"},
+		ValidateFileSizeOpts: utfile.ValidateFileSizeOpts{
+			MaxImageSize:       2,
+			MaxImageSizeSuffix: consttypes.FSS_MB,
+		},
+	})
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return err
+	}
+
+	fileupload := utfile.NewFileUpload(fileheader)
+	url, err := s.Upload(ctx, *fileupload)
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return consttypes.ErrFailedToUploadFile.Wrap(err)
+	}
+
+	image := models.NewMealCategoryImage(
+		*filename,
+		url,
+	)
+	image, err = s.rimg.Create(ctx, *image)
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return consttypes.ErrFailedToCreateImage.Wrap(err)
+	}
+
+	if err := s.rmcat.UpdateImage(ctx, mcid, image.ID); err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return consttypes.ErrGeneralFailed("updating meal category image", err.Error())
+	}
+
+	return nil
+}
+
+func (s *FileService) Upload(ctx context.Context, req utfile.FileMultipart) (string, error) {
 	fileheader := req.File
 
 	// * open the file
 	file, err := fileheader.Open()
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return "", err
 	}
 	defer file.Close()
@@ -235,7 +289,7 @@ func (s *FileService) Upload(req utfile.FileMultipart) (string, error) {
 	// * reason: so people could not guess the path or pattern of the file
 	randuuid, err := uuid.NewV7()
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return "", err
 	}
 
@@ -246,13 +300,13 @@ func (s *FileService) Upload(req utfile.FileMultipart) (string, error) {
 	objname := fmt.Sprintf("%s-%s%s", contenttype, randuuid.String(), fileext)
 
 	// * upload the file
-	info, err := s.minio.PutObject(s.ctx, s.mb, objname, file, fileheader.Size, minio.PutObjectOptions{ContentType: contenttype})
+	info, err := s.minio.PutObject(ctx, s.mb, objname, file, fileheader.Size, minio.PutObjectOptions{ContentType: contenttype})
 	if err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return "", err
 	}
 
-	utlogger.Info(fmt.Sprintf("Successfully uploaded %s of size %d", objname, info.Size))
+	utlogger.InfoContext(ctx, fmt.Sprintf("Successfully uploaded %s of size %d", objname, info.Size))
 
 	miniourl := fmt.Sprintf("%s/%s/%s", s.minio.EndpointURL().String(), s.mb, objname)
 	environment := s.cfg.API.Environment
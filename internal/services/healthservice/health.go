@@ -0,0 +1,197 @@
+package healthservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"project-skbackend/configs"
+	"project-skbackend/internal/controllers/responses"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/lifecycle"
+	"project-skbackend/packages/utils/utlogger"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+type (
+	HealthService struct {
+		cfg *configs.Config
+		gdb *gorm.DB
+		rdb *redis.Client
+		ch  *amqp.Channel
+		mio *minio.Client
+
+		version responses.HealthVersion
+	}
+
+	IHealthService interface {
+		Live(lm *lifecycle.Manager) responses.Health
+		Ready(ctx context.Context, lm *lifecycle.Manager) responses.Health
+	}
+
+	check struct {
+		name string
+		fn   func(ctx context.Context) error
+	}
+)
+
+func NewHealthService(
+	cfg *configs.Config,
+	gdb *gorm.DB,
+	rdb *redis.Client,
+	ch *amqp.Channel,
+	mio *minio.Client,
+) *HealthService {
+	return &HealthService{
+		cfg:     cfg,
+		gdb:     gdb,
+		rdb:     rdb,
+		ch:      ch,
+		mio:     mio,
+		version: buildVersion(cfg),
+	}
+}
+
+func buildVersion(cfg *configs.Config) responses.HealthVersion {
+	version := responses.HealthVersion{
+		Name:      cfg.App.Name,
+		Version:   cfg.App.Version,
+		Env:       cfg.App.Env,
+		GoVersion: runtime.Version(),
+	}
+
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" {
+				version.Commit = setting.Value
+			}
+		}
+	}
+
+	return version
+}
+
+// * the liveness only reports the components of the process, a broken
+// * dependency makes it unready but restarting it would not help
+func (s *HealthService) Live(lm *lifecycle.Manager) responses.Health {
+	status := consttypes.HS_OK
+	if !lm.Live() {
+		status = consttypes.HS_UNAVAILABLE
+	}
+
+	return responses.Health{
+		Status:     status,
+		Version:    s.version,
+		Components: lm.Health(),
+	}
+}
+
+// * the dependencies are checked at the same time, each of them within
+// * the check timeout, so a hanging one does not hold back the others
+func (s *HealthService) Ready(ctx context.Context, lm *lifecycle.Manager) responses.Health {
+	checks := []check{
+		{name: "postgres", fn: s.checkPostgres},
+		{name: "redis", fn: s.checkRedis},
+		{name: "rabbitmq", fn: s.checkRabbitMQ},
+		{name: "minio", fn: s.checkMinio},
+	}
+
+	var (
+		results = make([]responses.HealthCheck, len(checks))
+		timeout = time.Duration(s.cfg.Health.Timeout) * time.Second
+		wg      sync.WaitGroup
+	)
+
+	for i, c := range checks {
+		wg.Add(1)
+		go func(i int, c check) {
+			defer wg.Done()
+
+			cctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			start := time.Now()
+			err := c.fn(cctx)
+
+			results[i] = responses.HealthCheck{
+				Name:      c.name,
+				Status:    consttypes.HS_OK,
+				LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+			}
+
+			// * the error names the hosts of the dependencies, so it is
+			// * only logged in production as the endpoint is public
+			if err != nil {
+				utlogger.ErrorContext(ctx, fmt.Errorf("health check %s: %w", c.name, err))
+
+				results[i].Status = consttypes.HS_UNAVAILABLE
+				if s.cfg.API.Environment != "production" {
+					results[i].Error = err.Error()
+				}
+			}
+		}(i, c)
+	}
+
+	wg.Wait()
+
+	status := consttypes.HS_OK
+	if !lm.Ready() {
+		status = consttypes.HS_UNAVAILABLE
+	}
+
+	for _, result := range results {
+		if result.Status != consttypes.HS_OK {
+			status = consttypes.HS_UNAVAILABLE
+		}
+	}
+
+	return responses.Health{
+		Status:     status,
+		Version:    s.version,
+		Checks:     results,
+		Components: lm.Health(),
+	}
+}
+
+func (s *HealthService) checkPostgres(ctx context.Context) error {
+	sqldb, err := s.gdb.DB()
+	if err != nil {
+		return err
+	}
+
+	return sqldb.PingContext(ctx)
+}
+
+func (s *HealthService) checkRedis(ctx context.Context) error {
+	return s.rdb.Ping(ctx).Err()
+}
+
+// * only the state of the channel is read, a failed passive declare
+// * would close the channel the consumers and producers share
+func (s *HealthService) checkRabbitMQ(ctx context.Context) error {
+	if s.ch == nil || s.ch.IsClosed() {
+		return errors.New("channel closed")
+	}
+
+	return nil
+}
+
+func (s *HealthService) checkMinio(ctx context.Context) error {
+	exists, err := s.mio.BucketExists(ctx, s.cfg.Minio.Bucket)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return fmt.Errorf("bucket %s not found", s.cfg.Minio.Bucket)
+	}
+
+	return nil
+}
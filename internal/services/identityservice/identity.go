@@ -0,0 +1,398 @@
+package identityservice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"project-skbackend/configs"
+	"project-skbackend/external/controllers/exresponses"
+	"project-skbackend/external/services/oidcservice"
+	"project-skbackend/internal/controllers/requests"
+	"project-skbackend/internal/controllers/responses"
+	"project-skbackend/internal/models"
+	"project-skbackend/internal/repositories/identityrepo"
+	"project-skbackend/internal/repositories/userrepo"
+	"project-skbackend/internal/services/memberservice"
+	"project-skbackend/internal/services/partnerservice"
+	"project-skbackend/internal/services/patronservice"
+	"project-skbackend/internal/services/securityservice"
+	"project-skbackend/internal/services/sessionservice"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/utils/utlogger"
+	"project-skbackend/packages/utils/utstring"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
+)
+
+const (
+	stateLength       = 32
+	signupTokenLength = 32
+	passwordLength    = 32
+)
+
+type (
+	IdentityService struct {
+		cfg *configs.Config
+		rdb *redis.Client
+
+		// * repository
+		rident identityrepo.IIdentityRepository
+		ruser  userrepo.IUserRepository
+
+		// * service
+		soidc oidcservice.IOIDCService
+		smemb memberservice.IMemberService
+		spatr patronservice.IPatronService
+		spart partnerservice.IPartnerService
+		ssecu securityservice.ISecurityService
+		ssess sessionservice.ISessionService
+	}
+
+	IIdentityService interface {
+		Authorize(ctx context.Context) (*responses.OIDCAuthorize, error)
+		Callback(ctx context.Context, req requests.OIDCCallback) (*models.User, *responses.OIDCSignup, error)
+		RegisterMember(ctx context.Context, req requests.OIDCRegisterMember) (*models.User, error)
+		RegisterPatron(ctx context.Context, req requests.OIDCRegisterPatron) (*models.User, error)
+		RegisterPartner(ctx context.Context, req requests.OIDCRegisterPartner) (*models.User, error)
+	}
+)
+
+func NewIdentityService(
+	cfg *configs.Config,
+	rdb *redis.Client,
+	// * repository
+	rident identityrepo.IIdentityRepository,
+	ruser userrepo.IUserRepository,
+	// * service
+	soidc oidcservice.IOIDCService,
+	smemb memberservice.IMemberService,
+	spatr patronservice.IPatronService,
+	spart partnerservice.IPartnerService,
+	ssecu securityservice.ISecurityService,
+	ssess sessionservice.ISessionService,
+) *IdentityService {
+	return &IdentityService{
+		cfg: cfg,
+		rdb: rdb,
+
+		// * repository
+		rident: rident,
+		ruser:  ruser,
+
+		// * service
+		soidc: soidc,
+		smemb: smemb,
+		spatr: spatr,
+		spart: spart,
+		ssecu: ssecu,
+		ssess: ssess,
+	}
+}
+
+func stateKey(state string) string {
+	return fmt.Sprintf("oidc_state:%s", state)
+}
+
+func signupKey(token string) string {
+	return fmt.Sprintf("oidc_signup:%s", token)
+}
+
+// * starts the authorization code flow, the state binds the callback to
+// * this request and keeps the nonce and the pkce verifier of the flow
+func (s *IdentityService) Authorize(ctx context.Context) (*responses.OIDCAuthorize, error) {
+	var (
+		life = time.Duration(s.cfg.OIDC.StateLife) * time.Minute
+	)
+
+	state, err := utstring.GenerateRandomToken(stateLength)
+	if err != nil {
+		return nil, consttypes.ErrFailedToGenerateToken.Wrap(err)
+	}
+
+	nonce := uuid.NewString()
+	verifier := oauth2.GenerateVerifier()
+
+	url, err := s.soidc.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, stateKey(state), "nonce", nonce, "verifier", verifier)
+		pipe.Expire(ctx, stateKey(state), life)
+		return nil
+	})
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, consttypes.ErrFailedToGenerateToken.Wrap(err)
+	}
+
+	return &responses.OIDCAuthorize{
+		AuthorizationURL: url,
+		State:            state,
+		ExpiresAt:        consttypes.TimeNow().Add(life),
+	}, nil
+}
+
+// * returns the user of the oidc account, linking it by the verified email
+// * when needed, or a signup token when there is no user to link yet
+func (s *IdentityService) Callback(ctx context.Context, req requests.OIDCCallback) (*models.User, *responses.OIDCSignup, error) {
+	values, err := s.rdb.HGetAll(ctx, stateKey(req.State)).Result()
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, nil, consttypes.ErrOIDCStateInvalid.Wrap(err)
+	}
+
+	if len(values) == 0 {
+		return nil, nil, consttypes.ErrOIDCStateInvalid
+	}
+
+	// * a state can only be used once
+	s.rdb.Del(ctx, stateKey(req.State))
+
+	claims, err := s.soidc.Exchange(ctx, req.Code, values["nonce"], values["verifier"])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ui, err := s.rident.GetByIssuerSubject(ctx, claims.Issuer, claims.Subject)
+	if err == nil {
+		user, err := s.ruser.GetByID(ctx, ui.UserID)
+		if err != nil {
+			return nil, nil, consttypes.ErrUserNotFound.Wrap(err)
+		}
+
+		// * a locked account cannot be signed in through its provider
+		// * either, the lock holds whichever way the user signs in
+		if err := s.ssecu.CheckLockout(ctx, user.ID); err != nil {
+			return nil, nil, err
+		}
+
+		return user, nil, nil
+	}
+
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		utlogger.ErrorContext(ctx, err)
+		return nil, nil, err
+	}
+
+	// * an unverified email could belong to someone else,
+	// * so it can neither be linked nor registered
+	if !claims.EmailVerified || claims.Email == "" {
+		return nil, nil, consttypes.ErrOIDCEmailNotVerified
+	}
+
+	user, err := s.ruser.GetByEmail(ctx, strings.ToLower(claims.Email))
+	if err == nil {
+		if err := s.ssecu.CheckLockout(ctx, user.ID); err != nil {
+			return nil, nil, err
+		}
+
+		// * anyone could have registered an unconfirmed account with
+		// * this email, so the provider's owner of the email takes it over
+		if user.ConfirmedAt.IsZero() {
+			user, err = s.claim(ctx, *user)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
+		if err := s.link(ctx, user.ID, *claims); err != nil {
+			return nil, nil, err
+		}
+
+		return user, nil, nil
+	}
+
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		utlogger.ErrorContext(ctx, err)
+		return nil, nil, err
+	}
+
+	signup, err := s.createSignup(ctx, *claims)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return nil, signup, nil
+}
+
+func (s *IdentityService) RegisterMember(ctx context.Context, req requests.OIDCRegisterMember) (*models.User, error) {
+	return s.register(ctx, req.OIDCSignup, func(ureq requests.CreateUser) (uuid.UUID, error) {
+		creq, err := req.ToCreateMember(ureq)
+		if err != nil {
+			return uuid.Nil, consttypes.ErrConvertFailed.Wrap(err)
+		}
+
+		member, err := s.smemb.Create(ctx, *creq, nil)
+		if err != nil {
+			return uuid.Nil, err
+		}
+
+		return member.User.ID, nil
+	})
+}
+
+func (s *IdentityService) RegisterPatron(ctx context.Context, req requests.OIDCRegisterPatron) (*models.User, error) {
+	return s.register(ctx, req.OIDCSignup, func(ureq requests.CreateUser) (uuid.UUID, error) {
+		patron, err := s.spatr.Create(ctx, *req.ToCreatePatron(ureq))
+		if err != nil {
+			return uuid.Nil, err
+		}
+
+		return patron.User.ID, nil
+	})
+}
+
+func (s *IdentityService) RegisterPartner(ctx context.Context, req requests.OIDCRegisterPartner) (*models.User, error) {
+	return s.register(ctx, req.OIDCSignup, func(ureq requests.CreateUser) (uuid.UUID, error) {
+		partner, err := s.spart.Create(ctx, *req.ToCreatePartner(ureq))
+		if err != nil {
+			return uuid.Nil, err
+		}
+
+		return partner.User.ID, nil
+	})
+}
+
+// * creates the account through the registration service of the role,
+// * the email is already verified by the provider so the account is
+// * confirmed right away and gets a random password that can be reset
+func (s *IdentityService) register(ctx context.Context, req requests.OIDCSignup, create func(ureq requests.CreateUser) (uuid.UUID, error)) (*models.User, error) {
+	var (
+		claims exresponses.OIDCClaims
+	)
+
+	data, err := s.rdb.Get(ctx, signupKey(req.SignupToken)).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			utlogger.ErrorContext(ctx, err)
+		}
+
+		return nil, consttypes.ErrOIDCSignupTokenInvalid.Wrap(err)
+	}
+
+	if err := json.Unmarshal(data, &claims); err != nil {
+		return nil, consttypes.ErrOIDCSignupTokenInvalid.Wrap(err)
+	}
+
+	password, err := utstring.GenerateRandomToken(passwordLength)
+	if err != nil {
+		return nil, consttypes.ErrFailedToGenerateToken.Wrap(err)
+	}
+
+	uid, err := create(requests.CreateUser{
+		Email:           claims.Email,
+		Password:        password,
+		ConfirmPassword: password,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.rdb.Del(ctx, signupKey(req.SignupToken))
+
+	user, err := s.ruser.GetByID(ctx, uid)
+	if err != nil {
+		return nil, consttypes.ErrUserNotFound.Wrap(err)
+	}
+
+	user.ConfirmedAt = consttypes.TimeNow()
+
+	user, err = s.ruser.Update(ctx, *user)
+	if err != nil {
+		return nil, consttypes.ErrFailedToUpdateUser.Wrap(err)
+	}
+
+	if err := s.link(ctx, user.ID, claims); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// * confirms an account whose email is proven by the provider, whoever
+// * registered it without confirming loses every way back in, so the
+// * password is replaced, the pending tokens are dropped and the
+// * sessions are revoked
+func (s *IdentityService) claim(ctx context.Context, user models.User) (*models.User, error) {
+	password, err := utstring.GenerateRandomToken(passwordLength)
+	if err != nil {
+		return nil, consttypes.ErrFailedToGenerateToken.Wrap(err)
+	}
+
+	hash, err := utstring.HashPassword(password)
+	if err != nil {
+		return nil, consttypes.ErrFailedToGenerateToken.Wrap(err)
+	}
+
+	user.Password = hash
+	user.ConfirmationToken = ""
+	user.ResetPasswordToken = ""
+	user.ConfirmedAt = consttypes.TimeNow()
+
+	claimed, err := s.ruser.Update(ctx, user)
+	if err != nil {
+		return nil, consttypes.ErrFailedToUpdateUser.Wrap(err)
+	}
+
+	err = s.ssess.RevokeAll(ctx, user.ID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	utlogger.InfoContext(ctx, fmt.Sprintf("Claimed unconfirmed user %s through its oidc email", user.ID))
+
+	return claimed, nil
+}
+
+func (s *IdentityService) link(ctx context.Context, uid uuid.UUID, claims exresponses.OIDCClaims) error {
+	_, err := s.rident.Create(ctx, *models.NewUserIdentity(uid, claims.Issuer, claims.Subject, strings.ToLower(claims.Email)))
+	if err != nil {
+		return consttypes.ErrFailedToLinkIdentity.Wrap(err)
+	}
+
+	utlogger.InfoContext(ctx, fmt.Sprintf("Linked oidc identity %s of %s to user %s", claims.Subject, claims.Issuer, uid))
+
+	return nil
+}
+
+func (s *IdentityService) createSignup(ctx context.Context, claims exresponses.OIDCClaims) (*responses.OIDCSignup, error) {
+	var (
+		life = time.Duration(s.cfg.OIDC.SignupLife) * time.Minute
+	)
+
+	token, err := utstring.GenerateRandomToken(signupTokenLength)
+	if err != nil {
+		return nil, consttypes.ErrFailedToGenerateToken.Wrap(err)
+	}
+
+	claims.Email = strings.ToLower(claims.Email)
+
+	data, err := json.Marshal(claims)
+	if err != nil {
+		return nil, consttypes.ErrConvertFailed.Wrap(err)
+	}
+
+	err = s.rdb.Set(ctx, signupKey(token), data, life).Err()
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, consttypes.ErrFailedToGenerateToken.Wrap(err)
+	}
+
+	return &responses.OIDCSignup{
+		SignupToken: token,
+		Email:       claims.Email,
+		FirstName:   claims.GivenName,
+		LastName:    claims.FamilyName,
+		ExpiresAt:   consttypes.TimeNow().Add(life),
+	}, nil
+}
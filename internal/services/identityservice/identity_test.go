@@ -0,0 +1,377 @@
+package identityservice
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"project-skbackend/configs"
+	"project-skbackend/external/services/oidcservice"
+	"project-skbackend/external/services/oidcservice/oidctest"
+	"project-skbackend/internal/controllers/requests"
+	"project-skbackend/internal/controllers/responses"
+	"project-skbackend/internal/models"
+	"project-skbackend/internal/models/base"
+	"project-skbackend/internal/repositories/identityrepo"
+	"project-skbackend/internal/repositories/userrepo"
+	"project-skbackend/internal/services/patronservice"
+	"project-skbackend/internal/services/securityservice"
+	"project-skbackend/internal/services/sessionservice"
+	"project-skbackend/packages/consttypes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+type (
+	fakeIdentities struct {
+		identityrepo.IIdentityRepository
+
+		identities []models.UserIdentity
+	}
+
+	// * the emails are compared case insensitively like the repository does
+	fakeUsers struct {
+		userrepo.IUserRepository
+
+		users map[uuid.UUID]*models.User
+	}
+
+	fakePatrons struct {
+		patronservice.IPatronService
+
+		ruser *fakeUsers
+	}
+
+	fakeSecurity struct {
+		securityservice.ISecurityService
+
+		locked map[uuid.UUID]bool
+	}
+
+	fakeSessions struct {
+		sessionservice.ISessionService
+
+		revoked []uuid.UUID
+	}
+
+	testIdentity struct {
+		s      *IdentityService
+		iss    *oidctest.Issuer
+		rident *fakeIdentities
+		ruser  *fakeUsers
+		ssecu  *fakeSecurity
+		ssess  *fakeSessions
+	}
+)
+
+func (f *fakeIdentities) Create(ctx context.Context, ui models.UserIdentity) (*models.UserIdentity, error) {
+	f.identities = append(f.identities, ui)
+	return &ui, nil
+}
+
+func (f *fakeIdentities) GetByIssuerSubject(ctx context.Context, issuer string, subject string) (*models.UserIdentity, error) {
+	for _, ui := range f.identities {
+		if ui.Issuer == issuer && ui.Subject == subject {
+			return &ui, nil
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeUsers) add(email string) *models.User {
+	user := &models.User{Model: base.Model{ID: uuid.New()}, Email: email, ConfirmedAt: consttypes.TimeNow()}
+	f.users[user.ID] = user
+	return user
+}
+
+func (f *fakeUsers) GetByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	if user, ok := f.users[id]; ok {
+		return user, nil
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeUsers) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	for _, user := range f.users {
+		if strings.EqualFold(user.Email, email) {
+			return user, nil
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeUsers) Update(ctx context.Context, u models.User) (*models.User, error) {
+	f.users[u.ID] = &u
+	return &u, nil
+}
+
+func (f *fakePatrons) Create(ctx context.Context, req requests.CreatePatron) (*responses.Patron, error) {
+	user := f.ruser.add(req.User.Email)
+	return &responses.Patron{User: responses.User{Model: user.Model, Email: user.Email}}, nil
+}
+
+func (f *fakeSecurity) CheckLockout(ctx context.Context, uid uuid.UUID) error {
+	if f.locked[uid] {
+		return consttypes.ErrRetryAfter(consttypes.ErrAccountLocked, time.Minute)
+	}
+
+	return nil
+}
+
+func (f *fakeSessions) RevokeAll(ctx context.Context, uid uuid.UUID, exceptsid *uuid.UUID) error {
+	f.revoked = append(f.revoked, uid)
+	return nil
+}
+
+func newTestIdentity(t *testing.T) *testIdentity {
+	iss := oidctest.NewIssuer(t, "client")
+
+	cfg := &configs.Config{}
+	cfg.OIDC.Issuer = iss.URL
+	cfg.OIDC.ClientID = "client"
+	cfg.OIDC.RedirectURL = "http://localhost/callback"
+	cfg.OIDC.Scopes = []string{"openid", "email"}
+	cfg.OIDC.Timeout = 5
+	cfg.OIDC.StateLife = 10
+	cfg.OIDC.SignupLife = 30
+
+	rdb := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+
+	ti := &testIdentity{
+		iss:    iss,
+		rident: &fakeIdentities{},
+		ruser:  &fakeUsers{users: map[uuid.UUID]*models.User{}},
+		ssecu:  &fakeSecurity{locked: map[uuid.UUID]bool{}},
+		ssess:  &fakeSessions{},
+	}
+
+	ti.s = NewIdentityService(
+		cfg,
+		rdb,
+		ti.rident,
+		ti.ruser,
+		oidcservice.NewOIDCService(cfg),
+		nil,
+		&fakePatrons{ruser: ti.ruser},
+		nil,
+		ti.ssecu,
+		ti.ssess,
+	)
+
+	return ti
+}
+
+// * goes through the provider like a browser would and returns the
+// * callback request the provider redirects back with
+func (ti *testIdentity) signin(t *testing.T, claims oidctest.Claims) requests.OIDCCallback {
+	t.Helper()
+
+	resauthz, err := ti.s.Authorize(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	u, err := url.Parse(resauthz.AuthorizationURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if u.Query().Get("state") != resauthz.State {
+		t.Fatalf("state of the authorization url = %q, want %q", u.Query().Get("state"), resauthz.State)
+	}
+
+	return requests.OIDCCallback{
+		Code:  ti.iss.Grant(t, resauthz.AuthorizationURL, claims),
+		State: resauthz.State,
+	}
+}
+
+func TestCallbackRegistersNewAccount(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		claims = oidctest.Claims{Subject: "new", Email: "New@Example.com", EmailVerified: true, GivenName: "New"}
+	)
+
+	ti := newTestIdentity(t)
+	req := ti.signin(t, claims)
+
+	user, ressignup, err := ti.s.Callback(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if user != nil || ressignup == nil {
+		t.Fatalf("user = %v, signup = %v, want only a signup", user, ressignup)
+	}
+
+	if ressignup.Email != "new@example.com" || ressignup.FirstName != "New" {
+		t.Fatalf("signup = %+v", ressignup)
+	}
+
+	// * the state is used up by the first callback
+	if _, _, err := ti.s.Callback(ctx, req); !errors.Is(err, consttypes.ErrOIDCStateInvalid) {
+		t.Fatalf("second callback err = %v, want %v", err, consttypes.ErrOIDCStateInvalid)
+	}
+
+	rreq := requests.OIDCRegisterPatron{
+		OIDCSignup: requests.OIDCSignup{SignupToken: ressignup.SignupToken},
+		Type:       consttypes.PT_PERSONAL,
+		Name:       "New",
+	}
+
+	user, err = ti.s.RegisterPatron(ctx, rreq)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if user.Email != "new@example.com" || user.ConfirmedAt.IsZero() {
+		t.Fatalf("user = %+v, want a confirmed account of the provider email", user)
+	}
+
+	if len(ti.rident.identities) != 1 || ti.rident.identities[0].UserID != user.ID || ti.rident.identities[0].Subject != "new" {
+		t.Fatalf("identities = %+v, want the registered user linked", ti.rident.identities)
+	}
+
+	// * the signup token is used up by the registration
+	if _, err := ti.s.RegisterPatron(ctx, rreq); !errors.Is(err, consttypes.ErrOIDCSignupTokenInvalid) {
+		t.Fatalf("second registration err = %v, want %v", err, consttypes.ErrOIDCSignupTokenInvalid)
+	}
+}
+
+func TestCallbackLinksExistingAccount(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		claims = oidctest.Claims{Subject: "existing", Email: "someone@example.com", EmailVerified: true}
+	)
+
+	ti := newTestIdentity(t)
+	existing := ti.ruser.add("Someone@Example.com")
+
+	user, ressignup, err := ti.s.Callback(ctx, ti.signin(t, claims))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ressignup != nil || user == nil || user.ID != existing.ID {
+		t.Fatalf("user = %v, signup = %v, want the existing user", user, ressignup)
+	}
+
+	if len(ti.rident.identities) != 1 || ti.rident.identities[0].UserID != existing.ID {
+		t.Fatalf("identities = %+v, want the existing user linked", ti.rident.identities)
+	}
+
+	if len(ti.ssess.revoked) != 0 {
+		t.Fatalf("revoked = %v, want the sessions of a confirmed user kept", ti.ssess.revoked)
+	}
+
+	// * the next sign in finds the user by the linked identity
+	user, _, err = ti.s.Callback(ctx, ti.signin(t, claims))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if user == nil || user.ID != existing.ID || len(ti.rident.identities) != 1 {
+		t.Fatalf("user = %v, identities = %+v, want the linked user", user, ti.rident.identities)
+	}
+}
+
+func TestCallbackClaimsUnconfirmedAccount(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		claims = oidctest.Claims{Subject: "victim", Email: "victim@example.com", EmailVerified: true}
+	)
+
+	ti := newTestIdentity(t)
+
+	// * someone registered the email with a password of their own
+	// * and never confirmed it
+	existing := ti.ruser.add("victim@example.com")
+	existing.ConfirmedAt = time.Time{}
+	existing.Password = "attacker"
+	existing.ConfirmationToken = "confirmation"
+	existing.ResetPasswordToken = "reset"
+
+	user, ressignup, err := ti.s.Callback(ctx, ti.signin(t, claims))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ressignup != nil || user == nil || user.ID != existing.ID {
+		t.Fatalf("user = %v, signup = %v, want the existing user", user, ressignup)
+	}
+
+	claimed := ti.ruser.users[existing.ID]
+	if claimed.ConfirmedAt.IsZero() {
+		t.Fatal("claimed user is not confirmed")
+	}
+
+	if claimed.Password == "attacker" || claimed.ConfirmationToken != "" || claimed.ResetPasswordToken != "" {
+		t.Fatalf("claimed user = %+v, want the password replaced and the tokens cleared", claimed)
+	}
+
+	if len(ti.ssess.revoked) != 1 || ti.ssess.revoked[0] != existing.ID {
+		t.Fatalf("revoked = %v, want the sessions of the claimed user revoked", ti.ssess.revoked)
+	}
+
+	if len(ti.rident.identities) != 1 || ti.rident.identities[0].UserID != existing.ID {
+		t.Fatalf("identities = %+v, want the claimed user linked", ti.rident.identities)
+	}
+}
+
+func TestCallbackRejectsLockedAccount(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		claims = oidctest.Claims{Subject: "locked", Email: "locked@example.com", EmailVerified: true}
+	)
+
+	ti := newTestIdentity(t)
+	existing := ti.ruser.add("locked@example.com")
+	ti.ssecu.locked[existing.ID] = true
+
+	if _, _, err := ti.s.Callback(ctx, ti.signin(t, claims)); !errors.Is(err, consttypes.ErrAccountLocked) {
+		t.Fatalf("link err = %v, want %v", err, consttypes.ErrAccountLocked)
+	}
+
+	if len(ti.rident.identities) != 0 {
+		t.Fatalf("identities = %+v, want none linked to a locked account", ti.rident.identities)
+	}
+
+	ti.rident.identities = append(ti.rident.identities, *models.NewUserIdentity(existing.ID, ti.iss.URL, "locked", existing.Email))
+
+	if _, _, err := ti.s.Callback(ctx, ti.signin(t, claims)); !errors.Is(err, consttypes.ErrAccountLocked) {
+		t.Fatalf("linked err = %v, want %v", err, consttypes.ErrAccountLocked)
+	}
+}
+
+func TestCallbackRejectsUnverifiedEmail(t *testing.T) {
+	ti := newTestIdentity(t)
+	ti.ruser.add("someone@example.com")
+
+	_, _, err := ti.s.Callback(context.Background(), ti.signin(t, oidctest.Claims{Subject: "unverified", Email: "someone@example.com"}))
+	if !errors.Is(err, consttypes.ErrOIDCEmailNotVerified) {
+		t.Fatalf("err = %v, want %v", err, consttypes.ErrOIDCEmailNotVerified)
+	}
+
+	if len(ti.rident.identities) != 0 {
+		t.Fatalf("identities = %+v, want none linked by an unverified email", ti.rident.identities)
+	}
+}
+
+func TestCallbackRejectsUnknownState(t *testing.T) {
+	ti := newTestIdentity(t)
+
+	req := ti.signin(t, oidctest.Claims{Subject: "sub", Email: "someone@example.com", EmailVerified: true})
+	req.State = "unknown"
+
+	if _, _, err := ti.s.Callback(context.Background(), req); !errors.Is(err, consttypes.ErrOIDCStateInvalid) {
+		t.Fatalf("err = %v, want %v", err, consttypes.ErrOIDCStateInvalid)
+	}
+}
@@ -1,6 +1,7 @@
 package illnessservice
 
 import (
+	"context"
 	"project-skbackend/internal/controllers/requests"
 	"project-skbackend/internal/controllers/responses"
 	"project-skbackend/internal/repositories/illnessrepo"
@@ -15,12 +16,12 @@ type (
 	}
 
 	IIllnessService interface {
-		Create(req requests.CreateIllness) (*responses.Illness, error)
-		Read() ([]*responses.Illness, error)
-		Update(id uuid.UUID, req requests.UpdateIllness) (*responses.Illness, error)
-		Delete(id uuid.UUID) error
-		FindAll(p utpagination.Pagination) (*utpagination.Pagination, error)
-		GetByID(id uuid.UUID) (*responses.Illness, error)
+		Create(ctx context.Context, req requests.CreateIllness) (*responses.Illness, error)
+		Read(ctx context.Context) ([]*responses.Illness, error)
+		Update(ctx context.Context, id uuid.UUID, req requests.UpdateIllness) (*responses.Illness, error)
+		Delete(ctx context.Context, id uuid.UUID) error
+		FindAll(ctx context.Context, p utpagination.Pagination) (*utpagination.Pagination, error)
+		GetByID(ctx context.Context, id uuid.UUID) (*responses.Illness, error)
 	}
 )
 
@@ -32,13 +33,13 @@ func NewIllnessService(
 	}
 }
 
-func (s *IllnessService) Create(req requests.CreateIllness) (*responses.Illness, error) {
+func (s *IllnessService) Create(ctx context.Context, req requests.CreateIllness) (*responses.Illness, error) {
 	var (
 		err     error
 		illness = req.ToModel()
 	)
 
-	illness, err = s.rill.Create(*illness)
+	illness, err = s.rill.Create(ctx, *illness)
 	if err != nil {
 		return nil, err
 	}
@@ -51,13 +52,13 @@ func (s *IllnessService) Create(req requests.CreateIllness) (*responses.Illness,
 	return illress, nil
 }
 
-func (s *IllnessService) Read() ([]*responses.Illness, error) {
+func (s *IllnessService) Read(ctx context.Context) ([]*responses.Illness, error) {
 	var (
 		illreses []*responses.Illness
 		err      error
 	)
 
-	illnesses, err := s.rill.Read()
+	illnesses, err := s.rill.Read(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -74,8 +75,8 @@ func (s *IllnessService) Read() ([]*responses.Illness, error) {
 	return illreses, nil
 }
 
-func (s *IllnessService) Update(id uuid.UUID, req requests.UpdateIllness) (*responses.Illness, error) {
-	illness, err := s.rill.GetByID(id)
+func (s *IllnessService) Update(ctx context.Context, id uuid.UUID, req requests.UpdateIllness) (*responses.Illness, error) {
+	illness, err := s.rill.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -85,7 +86,7 @@ func (s *IllnessService) Update(id uuid.UUID, req requests.UpdateIllness) (*resp
 		return nil, err
 	}
 
-	illness, err = s.rill.Update(*illness)
+	illness, err = s.rill.Update(ctx, *illness)
 	if err != nil {
 		return nil, err
 	}
@@ -98,17 +99,17 @@ func (s *IllnessService) Update(id uuid.UUID, req requests.UpdateIllness) (*resp
 	return illress, nil
 }
 
-func (s *IllnessService) Delete(id uuid.UUID) error {
-	illness, err := s.rill.GetByID(id)
+func (s *IllnessService) Delete(ctx context.Context, id uuid.UUID) error {
+	illness, err := s.rill.GetByID(ctx, id)
 	if err != nil {
 		return err
 	}
 
-	return s.rill.Delete(*illness)
+	return s.rill.Delete(ctx, *illness)
 }
 
-func (s *IllnessService) FindAll(p utpagination.Pagination) (*utpagination.Pagination, error) {
-	illnesses, err := s.rill.FindAll(p)
+func (s *IllnessService) FindAll(ctx context.Context, p utpagination.Pagination) (*utpagination.Pagination, error) {
+	illnesses, err := s.rill.FindAll(ctx, p)
 	if err != nil {
 		return nil, err
 	}
@@ -116,8 +117,8 @@ func (s *IllnessService) FindAll(p utpagination.Pagination) (*utpagination.Pagin
 	return illnesses, nil
 }
 
-func (s *IllnessService) GetByID(id uuid.UUID) (*responses.Illness, error) {
-	illness, err := s.rill.GetByID(id)
+func (s *IllnessService) GetByID(ctx context.Context, id uuid.UUID) (*responses.Illness, error) {
+	illness, err := s.rill.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -2,6 +2,7 @@ package mailservice
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"fmt"
 	"html/template"
@@ -10,9 +11,12 @@ import (
 	"project-skbackend/internal/repositories/userrepo"
 	"project-skbackend/internal/services/producerservice"
 	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/tracing"
 	"project-skbackend/packages/utils/utlogger"
 	"project-skbackend/packages/utils/uttemplate"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"gopkg.in/gomail.v2"
 )
 
@@ -33,9 +37,11 @@ type (
 	}
 
 	IMailService interface {
-		SendEmail(req requests.SendEmail) error
-		SendResetPasswordEmail(data requests.SendEmailResetPassword) error
-		SendVerifyEmail(req requests.SendEmailVerification) error
+		SendEmail(ctx context.Context, req requests.SendEmail) error
+		SendResetPasswordEmail(ctx context.Context, data requests.SendEmailResetPassword) error
+		SendVerifyEmail(ctx context.Context, req requests.SendEmailVerification) error
+		SendCaregiverInvitationEmail(ctx context.Context, req requests.SendEmailCaregiverInvitation) error
+		SendAccountLockedEmail(ctx context.Context, req requests.SendEmailAccountLocked) error
 	}
 )
 
@@ -64,18 +70,19 @@ func parseTemplate(templateFileName string, data any) (string, error) {
 	t, err := template.ParseFiles(templateFileName)
 	if err != nil {
 		utlogger.Error(err)
-		return "", consttypes.ErrFailedToParseFile
+		return "", consttypes.ErrFailedToParseFile.Wrap(err)
 	}
 
 	buf := new(bytes.Buffer)
 	if err = t.Execute(buf, data); err != nil {
 		utlogger.Error(err)
-		return "", consttypes.ErrFailedToWriteFile
+		return "", consttypes.ErrFailedToWriteFile.Wrap(err)
 	}
 
 	return buf.String(), nil
 }
 func (s *MailService) SendEmail(
+	ctx context.Context,
 	req requests.SendEmail,
 ) error {
 	var (
@@ -84,16 +91,16 @@ func (s *MailService) SendEmail(
 
 	templates, err := uttemplate.ParseTemplateDir(s.mailtemdir, req.Template)
 	if err != nil {
-		utlogger.Error(err)
-		return consttypes.ErrFailedToParseFile
+		utlogger.ErrorContext(ctx, err)
+		return consttypes.ErrFailedToParseFile.Wrap(err)
 	}
 
 	templates = templates.Lookup(req.Template)
 
 	err = templates.Execute(&body, &req.Data)
 	if err != nil {
-		utlogger.Error(err)
-		return consttypes.ErrFailedToWriteFile
+		utlogger.ErrorContext(ctx, err)
+		return consttypes.ErrFailedToWriteFile.Wrap(err)
 	}
 
 	m := gomail.NewMessage()
@@ -108,14 +115,26 @@ func (s *MailService) SendEmail(
 	d := gomail.NewDialer(s.mailhost, s.mailport, s.mailfrom, s.mailpass)
 	d.TLSConfig = &tls.Config{InsecureSkipVerify: true}
 
-	if err := d.DialAndSend(m); err != nil {
+	// * the recipient is left out of the span, only the template is kept
+	_, span := tracing.Start(ctx, "smtp send",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("peer.service", "smtp"),
+			attribute.String("server.address", s.mailhost),
+			attribute.String("email.template", req.Template),
+		),
+	)
+
+	err = d.DialAndSend(m)
+	tracing.End(span, err)
+	if err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (s *MailService) SendResetPasswordEmail(req requests.SendEmailResetPassword) error {
+func (s *MailService) SendResetPasswordEmail(ctx context.Context, req requests.SendEmailResetPassword) error {
 	sereq := requests.SendEmail{
 		Template: "reset_password.html",
 		Subject:  "Reset Password Request on Meals to Heals",
@@ -128,16 +147,14 @@ func (s *MailService) SendResetPasswordEmail(req requests.SendEmailResetPassword
 		},
 	}
 
-	utlogger.Info(template.URL(req.LinkUrl))
-
-	if err := s.sprod.PublishEmail(sereq); err != nil {
-		return consttypes.ErrFailedToPublishMessage
+	if err := s.sprod.PublishEmail(ctx, sereq); err != nil {
+		return consttypes.ErrFailedToPublishMessage.Wrap(err)
 	}
 
 	return nil
 }
 
-func (s *MailService) SendVerifyEmail(req requests.SendEmailVerification) error {
+func (s *MailService) SendVerifyEmail(ctx context.Context, req requests.SendEmailVerification) error {
 	sereq := requests.SendEmail{
 		Template: "verify_email.html",
 		Subject:  fmt.Sprintf("Verify Your Email Address on Meals to Heals"),
@@ -150,8 +167,49 @@ func (s *MailService) SendVerifyEmail(req requests.SendEmailVerification) error
 		},
 	}
 
-	if err := s.sprod.PublishEmail(sereq); err != nil {
-		return consttypes.ErrFailedToPublishMessage
+	if err := s.sprod.PublishEmail(ctx, sereq); err != nil {
+		return consttypes.ErrFailedToPublishMessage.Wrap(err)
+	}
+
+	return nil
+}
+
+func (s *MailService) SendCaregiverInvitationEmail(ctx context.Context, req requests.SendEmailCaregiverInvitation) error {
+	sereq := requests.SendEmail{
+		Template: "caregiver_invitation.html",
+		Subject:  "Caregiver Invitation on Meals to Heals",
+		Email:    req.Email,
+		Data: map[string]any{
+			"LogoUrl":    s.logourl,
+			"MemberName": req.MemberName,
+			"Email":      req.Email,
+			"LinkUrl":    template.URL(req.LinkUrl),
+		},
+	}
+
+	if err := s.sprod.PublishEmail(ctx, sereq); err != nil {
+		return consttypes.ErrFailedToPublishMessage.Wrap(err)
+	}
+
+	return nil
+}
+
+func (s *MailService) SendAccountLockedEmail(ctx context.Context, req requests.SendEmailAccountLocked) error {
+	sereq := requests.SendEmail{
+		Template: "account_locked.html",
+		Subject:  "Your Account on Meals to Heals is Locked",
+		Email:    req.Email,
+		Data: map[string]any{
+			"LogoUrl":     s.logourl,
+			"Name":        req.Name,
+			"Email":       req.Email,
+			"LockedUntil": req.LockedUntil,
+			"LinkUrl":     template.URL(req.LinkUrl),
+		},
+	}
+
+	if err := s.sprod.PublishEmail(ctx, sereq); err != nil {
+		return consttypes.ErrFailedToPublishMessage.Wrap(err)
 	}
 
 	return nil
@@ -1,12 +1,16 @@
 package mealcategoryservice
 
 import (
+	"context"
+	"errors"
 	"project-skbackend/internal/controllers/requests"
 	"project-skbackend/internal/controllers/responses"
 	"project-skbackend/internal/repositories/mealcategoryrepo"
+	"project-skbackend/packages/consttypes"
 	"project-skbackend/packages/utils/utpagination"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type (
@@ -15,12 +19,12 @@ type (
 	}
 
 	IMealCategoryService interface {
-		Create(req requests.CreateMealCategory) (*responses.MealCategory, error)
-		Read() ([]*responses.MealCategory, error)
-		Update(id uuid.UUID, req requests.UpdateMealCategory) (*responses.MealCategory, error)
-		Delete(id uuid.UUID) error
-		FindAll(preq utpagination.Pagination) (*utpagination.Pagination, error)
-		GetByID(id uuid.UUID) (*responses.MealCategory, error)
+		Create(ctx context.Context, req requests.CreateMealCategory) (*responses.MealCategory, error)
+		Read(ctx context.Context) ([]*responses.MealCategory, error)
+		Update(ctx context.Context, id uuid.UUID, req requests.UpdateMealCategory) (*responses.MealCategory, error)
+		Delete(ctx context.Context, id uuid.UUID) error
+		FindAll(ctx context.Context, preq utpagination.Pagination) (*utpagination.Pagination, error)
+		GetByID(ctx context.Context, id uuid.UUID) (*responses.MealCategory, error)
 	}
 )
 
@@ -32,19 +36,23 @@ func NewMealCategoryService(
 	}
 }
 
-func (s *MealCategoryService) Create(req requests.CreateMealCategory) (*responses.MealCategory, error) {
+func (s *MealCategoryService) Create(ctx context.Context, req requests.CreateMealCategory) (*responses.MealCategory, error) {
 	var (
 		err error
 	)
 
+	if _, err := s.rmcat.GetByName(ctx, req.Name); err == nil {
+		return nil, consttypes.ErrMealCategoryAlreadyExists
+	}
+
 	mcat, err := req.ToModel()
 	if err != nil {
-		return nil, err
+		return nil, consttypes.ErrConvertFailed.Wrap(err)
 	}
 
-	mcat, err = s.rmcat.Create(*mcat)
+	mcat, err = s.rmcat.Create(ctx, *mcat)
 	if err != nil {
-		return nil, err
+		return nil, consttypes.ErrFailedToCreateMealCategory.Wrap(err)
 	}
 
 	mcatres, err := mcat.ToResponse()
@@ -55,12 +63,12 @@ func (s *MealCategoryService) Create(req requests.CreateMealCategory) (*response
 	return mcatres, nil
 }
 
-func (s *MealCategoryService) Read() ([]*responses.MealCategory, error) {
+func (s *MealCategoryService) Read(ctx context.Context) ([]*responses.MealCategory, error) {
 	var (
 		mcatreses []*responses.MealCategory
 	)
 
-	mcats, err := s.rmcat.Read()
+	mcats, err := s.rmcat.Read(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -77,20 +85,27 @@ func (s *MealCategoryService) Read() ([]*responses.MealCategory, error) {
 	return mcatreses, nil
 }
 
-func (s *MealCategoryService) Update(id uuid.UUID, req requests.UpdateMealCategory) (*responses.MealCategory, error) {
-	mcat, err := s.rmcat.GetByID(id)
+func (s *MealCategoryService) Update(ctx context.Context, id uuid.UUID, req requests.UpdateMealCategory) (*responses.MealCategory, error) {
+	mcat, err := s.rmcat.GetByID(ctx, id)
 	if err != nil {
-		return nil, err
+		return nil, consttypes.ErrMealCategoryNotFound.Wrap(err)
+	}
+
+	if same, err := s.rmcat.GetByName(ctx, req.Name); err == nil && same.ID != mcat.ID {
+		return nil, consttypes.ErrMealCategoryAlreadyExists
 	}
 
 	mcat, err = req.ToModel(mcat)
 	if err != nil {
-		return nil, err
+		return nil, consttypes.ErrConvertFailed.Wrap(err)
 	}
 
-	mcat, err = s.rmcat.Update(*mcat)
+	// * the image is changed through the file service only
+	mcat.Image = nil
+
+	mcat, err = s.rmcat.Update(ctx, *mcat)
 	if err != nil {
-		return nil, err
+		return nil, consttypes.ErrFailedToUpdateMealCategory.Wrap(err)
 	}
 
 	mcatres, err := mcat.ToResponse()
@@ -101,21 +116,29 @@ func (s *MealCategoryService) Update(id uuid.UUID, req requests.UpdateMealCatego
 	return mcatres, nil
 }
 
-func (s *MealCategoryService) Delete(id uuid.UUID) error {
-	mcat, err := s.rmcat.GetByID(id)
+func (s *MealCategoryService) Delete(ctx context.Context, id uuid.UUID) error {
+	mcat, err := s.rmcat.GetByID(ctx, id)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return consttypes.ErrMealCategoryNotFound.Wrap(err)
+		}
+
 		return err
 	}
 
-	return s.rmcat.Delete(*mcat)
+	if err := s.rmcat.Delete(ctx, *mcat); err != nil {
+		return consttypes.ErrFailedToDeleteMealCategory.Wrap(err)
+	}
+
+	return nil
 }
 
-func (s *MealCategoryService) FindAll(preq utpagination.Pagination) (*utpagination.Pagination, error) {
-	return s.rmcat.FindAll(preq)
+func (s *MealCategoryService) FindAll(ctx context.Context, preq utpagination.Pagination) (*utpagination.Pagination, error) {
+	return s.rmcat.FindAll(ctx, preq)
 }
 
-func (s *MealCategoryService) GetByID(id uuid.UUID) (*responses.MealCategory, error) {
-	mcat, err := s.rmcat.GetByID(id)
+func (s *MealCategoryService) GetByID(ctx context.Context, id uuid.UUID) (*responses.MealCategory, error) {
+	mcat, err := s.rmcat.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
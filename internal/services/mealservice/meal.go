@@ -1,14 +1,18 @@
 package mealservice
 
 import (
+	"context"
+	"errors"
 	"project-skbackend/internal/controllers/requests"
 	"project-skbackend/internal/controllers/responses"
 	"project-skbackend/internal/models"
 	"project-skbackend/internal/models/base"
 	"project-skbackend/internal/repositories/allergyrepo"
 	"project-skbackend/internal/repositories/illnessrepo"
+	"project-skbackend/internal/repositories/mealcategoryrepo"
 	"project-skbackend/internal/repositories/mealrepo"
 	"project-skbackend/internal/repositories/partnerrepo"
+	"project-skbackend/internal/repositories/searchrepo"
 	"project-skbackend/packages/consttypes"
 	"project-skbackend/packages/utils/utpagination"
 
@@ -21,15 +25,17 @@ type (
 		rill  illnessrepo.IIllnessRepository
 		rall  allergyrepo.IAllergyRepository
 		rpart partnerrepo.IPartnerRepository
+		rmcat mealcategoryrepo.IMealCategoryRepository
+		rsrch searchrepo.ISearchRepository
 	}
 
 	IMealService interface {
-		Create(req requests.CreateMeal) (*responses.Meal, error)
-		Read() ([]*responses.Meal, error)
-		Update(id uuid.UUID, req requests.UpdateMeal) (*responses.Meal, error)
-		Delete(id uuid.UUID) error
-		FindAll(preq utpagination.Pagination) (*utpagination.Pagination, error)
-		GetByID(id uuid.UUID) (*responses.Meal, error)
+		Create(ctx context.Context, req requests.CreateMeal) (*responses.Meal, error)
+		Read(ctx context.Context) ([]*responses.Meal, error)
+		Update(ctx context.Context, id uuid.UUID, req requests.UpdateMeal) (*responses.Meal, error)
+		Delete(ctx context.Context, id uuid.UUID) error
+		FindAll(ctx context.Context, preq utpagination.Pagination) (*utpagination.Pagination, error)
+		GetByID(ctx context.Context, id uuid.UUID) (*responses.Meal, error)
 	}
 )
 
@@ -38,16 +44,20 @@ func NewMealService(
 	rill illnessrepo.IIllnessRepository,
 	rall allergyrepo.IAllergyRepository,
 	rpart partnerrepo.IPartnerRepository,
+	rmcat mealcategoryrepo.IMealCategoryRepository,
+	rsrch searchrepo.ISearchRepository,
 ) *MealService {
 	return &MealService{
 		rmeal: rmeal,
 		rill:  rill,
 		rall:  rall,
 		rpart: rpart,
+		rmcat: rmcat,
+		rsrch: rsrch,
 	}
 }
 
-func (s *MealService) Create(req requests.CreateMeal) (*responses.Meal, error) {
+func (s *MealService) Create(ctx context.Context, req requests.CreateMeal) (*responses.Meal, error) {
 	var (
 		illnesses []*models.MealIllness
 		allergies []*models.MealAllergy
@@ -57,9 +67,9 @@ func (s *MealService) Create(req requests.CreateMeal) (*responses.Meal, error) {
 
 	// * find illness object and append to the array.
 	for _, ill := range req.IllnessID {
-		illness, err := s.rill.GetByID(*ill)
+		illness, err := s.rill.GetByID(ctx, *ill)
 		if err != nil {
-			return nil, consttypes.ErrIllnessNotFound
+			return nil, consttypes.ErrIllnessNotFound.Wrap(err)
 		}
 
 		millness := illness.ToMealIllness()
@@ -69,9 +79,9 @@ func (s *MealService) Create(req requests.CreateMeal) (*responses.Meal, error) {
 
 	// * find allergy object and append to the array.
 	for _, all := range req.AllergyID {
-		allergy, err := s.rall.GetByID(*all)
+		allergy, err := s.rall.GetByID(ctx, *all)
 		if err != nil {
-			return nil, consttypes.ErrAllergiesNotFound
+			return nil, consttypes.ErrAllergiesNotFound.Wrap(err)
 		}
 
 		mallergy := allergy.ToMealAllergy()
@@ -79,44 +89,55 @@ func (s *MealService) Create(req requests.CreateMeal) (*responses.Meal, error) {
 		allergies = append(allergies, mallergy)
 	}
 
-	partner, err := s.rpart.GetByID(req.PartnerID)
+	partner, err := s.rpart.GetByID(ctx, req.PartnerID)
 	if err != nil {
-		return nil, consttypes.ErrPartnerNotFound
+		return nil, consttypes.ErrPartnerNotFound.Wrap(err)
+	}
+
+	if err := s.checkMealCategory(ctx, req.MealCategoryID); err != nil {
+		return nil, err
 	}
 
 	meal, err := req.ToModel(images, illnesses, allergies, *partner)
 	if err != nil {
-		return nil, consttypes.ErrConvertFailed
+		return nil, consttypes.ErrConvertFailed.Wrap(err)
 	}
 
-	meal, err = s.rmeal.Create(*meal)
+	meal, err = s.rmeal.Create(ctx, *meal)
 	if err != nil {
-		return nil, consttypes.ErrFailedToCreateMeal
+		return nil, consttypes.ErrFailedToCreateMeal.Wrap(err)
 	}
 
+	if err := s.rmeal.SyncPartnerMealCategories(ctx, meal.PartnerID); err != nil {
+		return nil, consttypes.ErrFailedToSyncPartnerCategories.Wrap(err)
+	}
+
+	// * a meal missed here is indexed by the next reindex of the cron
+	_ = s.rsrch.IndexMeal(ctx, meal.ID)
+
 	meres, err := meal.ToResponse()
 	if err != nil {
-		return nil, consttypes.ErrConvertFailed
+		return nil, consttypes.ErrConvertFailed.Wrap(err)
 	}
 
 	return meres, nil
 }
 
-func (s *MealService) Read() ([]*responses.Meal, error) {
+func (s *MealService) Read(ctx context.Context) ([]*responses.Meal, error) {
 	var (
 		mealreses []*responses.Meal
 		err       error
 	)
 
-	meals, err := s.rmeal.Read()
+	meals, err := s.rmeal.Read(ctx)
 	if err != nil {
-		return nil, consttypes.ErrFailedToReadMeals
+		return nil, consttypes.ErrFailedToReadMeals.Wrap(err)
 	}
 
 	for _, meal := range meals {
 		mealres, err := meal.ToResponse()
 		if err != nil {
-			return nil, consttypes.ErrConvertFailed
+			return nil, consttypes.ErrConvertFailed.Wrap(err)
 		}
 
 		mealreses = append(mealreses, mealres)
@@ -125,7 +146,7 @@ func (s *MealService) Read() ([]*responses.Meal, error) {
 	return mealreses, nil
 }
 
-func (s *MealService) Update(id uuid.UUID, req requests.UpdateMeal) (*responses.Meal, error) {
+func (s *MealService) Update(ctx context.Context, id uuid.UUID, req requests.UpdateMeal) (*responses.Meal, error) {
 	var (
 		images    []*models.MealImage
 		illnesses []*models.MealIllness
@@ -133,16 +154,16 @@ func (s *MealService) Update(id uuid.UUID, req requests.UpdateMeal) (*responses.
 		partner   *models.Partner
 	)
 
-	meal, err := s.rmeal.GetByID(id)
+	meal, err := s.rmeal.GetByID(ctx, id)
 	if err != nil {
-		return nil, consttypes.ErrMealsNotFound
+		return nil, consttypes.ErrMealsNotFound.Wrap(err)
 	}
 
 	// * find illness object and append to the array.
 	for _, ill := range req.IllnessID {
-		illness, err := s.rill.GetByID(*ill)
+		illness, err := s.rill.GetByID(ctx, *ill)
 		if err != nil {
-			return nil, consttypes.ErrIllnessNotFound
+			return nil, consttypes.ErrIllnessNotFound.Wrap(err)
 		}
 
 		millness := illness.ToMealIllness()
@@ -152,9 +173,9 @@ func (s *MealService) Update(id uuid.UUID, req requests.UpdateMeal) (*responses.
 
 	// * find allergy object and append to the array.
 	for _, all := range req.AllergyID {
-		allergy, err := s.rall.GetByID(*all)
+		allergy, err := s.rall.GetByID(ctx, *all)
 		if err != nil {
-			return nil, consttypes.ErrAllergiesNotFound
+			return nil, consttypes.ErrAllergiesNotFound.Wrap(err)
 		}
 
 		mallergy := allergy.ToMealAllergy()
@@ -162,61 +183,102 @@ func (s *MealService) Update(id uuid.UUID, req requests.UpdateMeal) (*responses.
 		allergies = append(allergies, mallergy)
 	}
 
-	partner, err = s.rpart.GetByID(req.PartnerID)
+	partner, err = s.rpart.GetByID(ctx, req.PartnerID)
 	if err != nil {
-		return nil, consttypes.ErrPartnerNotFound
+		return nil, consttypes.ErrPartnerNotFound.Wrap(err)
+	}
+
+	if err := s.checkMealCategory(ctx, req.MealCategoryID); err != nil {
+		return nil, err
 	}
 
+	// * the meal can move to another partner, so both partners
+	// * get their categories synced after the update
+	prevpid := meal.PartnerID
+
 	meal, err = req.ToModel(*meal, images, illnesses, allergies, *partner)
 	if err != nil {
-		return nil, consttypes.ErrConvertFailed
+		return nil, consttypes.ErrConvertFailed.Wrap(err)
 	}
 
-	meal, err = s.rmeal.Update(*meal)
+	meal, err = s.rmeal.Update(ctx, *meal)
 	if err != nil {
-		return nil, consttypes.ErrFailedToUpdateMeal
+		return nil, consttypes.ErrFailedToUpdateMeal.Wrap(err)
 	}
 
+	for _, pid := range []uuid.UUID{prevpid, meal.PartnerID} {
+		if err := s.rmeal.SyncPartnerMealCategories(ctx, pid); err != nil {
+			return nil, consttypes.ErrFailedToSyncPartnerCategories.Wrap(err)
+		}
+	}
+
+	// * a meal missed here is indexed by the next reindex of the cron
+	_ = s.rsrch.IndexMeal(ctx, meal.ID)
+
 	mres, err := meal.ToResponse()
 	if err != nil {
-		return nil, consttypes.ErrConvertFailed
+		return nil, consttypes.ErrConvertFailed.Wrap(err)
 	}
 
 	return mres, nil
 }
 
-func (s *MealService) Delete(id uuid.UUID) error {
-	meal := models.Meal{
-		Model: base.Model{ID: id},
+func (s *MealService) Delete(ctx context.Context, id uuid.UUID) error {
+	meal, err := s.rmeal.GetByID(ctx, id)
+	if err != nil {
+		return consttypes.ErrMealsNotFound.Wrap(err)
 	}
 
-	err := s.rmeal.Delete(meal)
+	err = s.rmeal.Delete(ctx, models.Meal{
+		Model: base.Model{ID: id},
+	})
 	if err != nil {
-		return consttypes.ErrFailedToDeleteMeal
+		return consttypes.ErrFailedToDeleteMeal.Wrap(err)
+	}
+
+	if err := s.rmeal.SyncPartnerMealCategories(ctx, meal.PartnerID); err != nil {
+		return consttypes.ErrFailedToSyncPartnerCategories.Wrap(err)
 	}
 
 	return nil
 }
 
-func (s *MealService) FindAll(preq utpagination.Pagination) (*utpagination.Pagination, error) {
-	meals, err := s.rmeal.FindAll(preq)
+func (s *MealService) FindAll(ctx context.Context, preq utpagination.Pagination) (*utpagination.Pagination, error) {
+	meals, err := s.rmeal.FindAll(ctx, preq)
 	if err != nil {
-		return nil, consttypes.ErrFailedToFindAllMeals
+		if errors.Is(err, consttypes.ErrInvalidFilter) {
+			return nil, err
+		}
+
+		return nil, consttypes.ErrFailedToFindAllMeals.Wrap(err)
 	}
 
 	return meals, nil
 }
 
-func (s *MealService) GetByID(id uuid.UUID) (*responses.Meal, error) {
-	meal, err := s.rmeal.GetByID(id)
+func (s *MealService) GetByID(ctx context.Context, id uuid.UUID) (*responses.Meal, error) {
+	meal, err := s.rmeal.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
 	mres, err := meal.ToResponse()
 	if err != nil {
-		return nil, consttypes.ErrConvertFailed
+		return nil, consttypes.ErrConvertFailed.Wrap(err)
 	}
 
 	return mres, nil
 }
+
+// * a meal may be left without a category
+func (s *MealService) checkMealCategory(ctx context.Context, mcid *uuid.UUID) error {
+	if mcid == nil {
+		return nil
+	}
+
+	if _, err := s.rmcat.GetByID(ctx, *mcid); err != nil {
+		return consttypes.ErrMealCategoryNotFound.Wrap(err)
+	}
+
+	return nil
+}
@@ -1,6 +1,8 @@
 package memberservice
 
 import (
+	"context"
+	"errors"
 	"project-skbackend/internal/controllers/requests"
 	"project-skbackend/internal/controllers/responses"
 	"project-skbackend/internal/models"
@@ -8,17 +10,17 @@ import (
 	"project-skbackend/internal/repositories/caregiverrepo"
 	"project-skbackend/internal/repositories/illnessrepo"
 	"project-skbackend/internal/repositories/memberallergyrepo"
+	"project-skbackend/internal/repositories/memberhealthhistoryrepo"
 	"project-skbackend/internal/repositories/memberillnessrepo"
 	"project-skbackend/internal/repositories/memberrepo"
 	"project-skbackend/internal/repositories/organizationrepo"
 	"project-skbackend/internal/repositories/userrepo"
 
 	"project-skbackend/packages/consttypes"
-	"project-skbackend/packages/utils/utlogger"
 	"project-skbackend/packages/utils/utpagination"
-	"project-skbackend/packages/utils/utstring"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type (
@@ -32,19 +34,20 @@ type (
 		rorg  organizationrepo.IOrganizationRepository
 		rmill memberillnessrepo.IMemberIllnessRepository
 		rmall memberallergyrepo.IMemberAllergyRepository
+		rmhh  memberhealthhistoryrepo.IMemberHealthHistoryRepository
 	}
 
 	IMemberService interface {
-		Create(req requests.CreateMember) (*responses.Member, error)
-		Read() ([]*responses.Member, error)
-		Update(id uuid.UUID, req requests.UpdateMember) (*responses.Member, error)
-		Delete(id uuid.UUID) error
-		FindAll(preq utpagination.Pagination) (*utpagination.Pagination, error)
-		GetByID(id uuid.UUID) (*responses.Member, error)
-		GetByCaregiverID(cgid uuid.UUID) (*responses.Member, error)
+		Create(ctx context.Context, req requests.CreateMember, actorid *uuid.UUID) (*responses.Member, error)
+		Read(ctx context.Context) ([]*responses.Member, error)
+		Update(ctx context.Context, id uuid.UUID, req requests.UpdateMember, actorid uuid.UUID) (*responses.Member, error)
+		Delete(ctx context.Context, id uuid.UUID) error
+		FindAll(ctx context.Context, preq utpagination.Pagination) (*utpagination.Pagination, error)
+		GetByID(ctx context.Context, id uuid.UUID) (*responses.Member, error)
 
-		UpdateOwnCaregiver(mid uuid.UUID, req requests.UpdateCaregiver) (*responses.Caregiver, error)
-		UpdateOwnCaregiverPassword(mid uuid.UUID, req requests.UpdatePassword) error
+		UpdateHealth(ctx context.Context, id uuid.UUID, req requests.UpdateMemberHealth, actorid uuid.UUID) (*responses.Member, error)
+		FindHealthHistory(ctx context.Context, id uuid.UUID, preq utpagination.Pagination) (*utpagination.Pagination, error)
+		GetHealthTrend(ctx context.Context, id uuid.UUID, filter utpagination.Filter) ([]*responses.MemberHealthTrend, error)
 	}
 )
 
@@ -58,6 +61,7 @@ func NewMemberService(
 	rorg organizationrepo.IOrganizationRepository,
 	rmill memberillnessrepo.IMemberIllnessRepository,
 	rmall memberallergyrepo.IMemberAllergyRepository,
+	rmhh memberhealthhistoryrepo.IMemberHealthHistoryRepository,
 ) *MemberService {
 	return &MemberService{
 		// * repository
@@ -69,44 +73,48 @@ func NewMemberService(
 		rorg:  rorg,
 		rmill: rmill,
 		rmall: rmall,
+		rmhh:  rmhh,
 	}
 }
 
-func (s *MemberService) Create(req requests.CreateMember) (*responses.Member, error) {
+func (s *MemberService) Create(ctx context.Context, req requests.CreateMember, actorid *uuid.UUID) (*responses.Member, error) {
 	var (
 		illnesses    []*models.MemberIllness
 		allergies    []*models.MemberAllergy
-		caregiver    *models.Caregiver
+		caregivers   []*models.MemberCaregiver
 		organization *models.Organization
 		err          error
 	)
 
 	user, err := req.User.ToModel(consttypes.UR_MEMBER)
 	if err != nil {
-		return nil, consttypes.ErrConvertFailed
+		return nil, consttypes.ErrConvertFailed.Wrap(err)
 	}
 
-	// * if caregiver request is not empty, then convert it to model.
+	// * if caregiver request is not empty, then convert it to model
+	// * and link it to the member with full access.
 	if req.Caregiver != nil {
-		caregiver, err = req.Caregiver.FromMemberAddition()
+		caregiver, err := req.Caregiver.FromMemberAddition()
 		if err != nil {
-			return nil, consttypes.ErrConvertFailed
+			return nil, consttypes.ErrConvertFailed.Wrap(err)
 		}
+
+		caregivers = append(caregivers, models.NewMemberCaregiver(*caregiver, true, true))
 	}
 
 	// * check the organization id and assign it to the object.
 	if req.OrganizationID != nil {
-		organization, err = s.rorg.GetByID(*req.OrganizationID)
+		organization, err = s.rorg.GetByID(ctx, *req.OrganizationID)
 		if err != nil {
-			return nil, consttypes.ErrOrganizationNotFound
+			return nil, consttypes.ErrOrganizationNotFound.Wrap(err)
 		}
 	}
 
 	// * find illness object and append to the array.
 	for _, ill := range req.IllnessID {
-		illness, err := s.rill.GetByID(*ill)
+		illness, err := s.rill.GetByID(ctx, *ill)
 		if err != nil {
-			return nil, consttypes.ErrIllnessNotFound
+			return nil, consttypes.ErrIllnessNotFound.Wrap(err)
 		}
 
 		millness := illness.ToMemberIllness()
@@ -116,9 +124,9 @@ func (s *MemberService) Create(req requests.CreateMember) (*responses.Member, er
 
 	// * find allergy object and append to the array.
 	for _, all := range req.AllergyID {
-		allergy, err := s.rall.GetByID(*all)
+		allergy, err := s.rall.GetByID(ctx, *all)
 		if err != nil {
-			return nil, consttypes.ErrAllergiesNotFound
+			return nil, consttypes.ErrAllergiesNotFound.Wrap(err)
 		}
 
 		mallergy := allergy.ToMemberAllergy()
@@ -126,38 +134,47 @@ func (s *MemberService) Create(req requests.CreateMember) (*responses.Member, er
 		allergies = append(allergies, mallergy)
 	}
 
-	member, err := req.ToModel(*user, caregiver, allergies, illnesses, organization)
+	member, err := req.ToModel(*user, caregivers, allergies, illnesses, organization)
 	if err != nil {
-		return nil, consttypes.ErrConvertFailed
+		return nil, consttypes.ErrConvertFailed.Wrap(err)
 	}
 
-	member, err = s.rmemb.Create(*member)
+	member, err = s.rmemb.Create(ctx, *member)
 	if err != nil {
-		return nil, consttypes.ErrFailedToCreateMember
+		return nil, consttypes.ErrFailedToCreateMember.Wrap(err)
+	}
+
+	// * members registering themselves are the actor of their first record
+	if actorid == nil {
+		actorid = &member.UserID
+	}
+
+	if err := s.recordHealthHistory(ctx, *member, nil, *actorid); err != nil {
+		return nil, err
 	}
 
 	mres, err := member.ToResponse()
 	if err != nil {
-		return nil, consttypes.ErrConvertFailed
+		return nil, consttypes.ErrConvertFailed.Wrap(err)
 	}
 
 	return mres, nil
 }
 
-func (s *MemberService) Read() ([]*responses.Member, error) {
+func (s *MemberService) Read(ctx context.Context) ([]*responses.Member, error) {
 	var (
 		mereses []*responses.Member
 	)
 
-	members, err := s.rmemb.Read()
+	members, err := s.rmemb.Read(ctx)
 	if err != nil {
-		return nil, consttypes.ErrFailedToReadMembers
+		return nil, consttypes.ErrFailedToReadMembers.Wrap(err)
 	}
 
 	for _, member := range members {
 		meres, err := member.ToResponse()
 		if err != nil {
-			return nil, consttypes.ErrConvertFailed
+			return nil, consttypes.ErrConvertFailed.Wrap(err)
 		}
 
 		mereses = append(mereses, meres)
@@ -166,68 +183,47 @@ func (s *MemberService) Read() ([]*responses.Member, error) {
 	return mereses, nil
 }
 
-func (s *MemberService) Update(id uuid.UUID, req requests.UpdateMember) (*responses.Member, error) {
+func (s *MemberService) Update(ctx context.Context, id uuid.UUID, req requests.UpdateMember, actorid uuid.UUID) (*responses.Member, error) {
 	var (
 		illnesses    []*models.MemberIllness
 		allergies    []*models.MemberAllergy
-		caregiver    *models.Caregiver
 		organization *models.Organization
 		err          error
 	)
 
-	member, err := s.rmemb.GetByID(id)
+	member, err := s.rmemb.GetByID(ctx, id)
 	if err != nil {
-		return nil, consttypes.ErrMemberNotFound
+		return nil, consttypes.ErrMemberNotFound.Wrap(err)
 	}
 
+	// * keep the health data before the update for the history
+	previous := models.NewMemberHealthSnapshot(*member)
+
 	user, err := req.User.ToModel(member.User, consttypes.UR_MEMBER)
 	if err != nil {
-		return nil, consttypes.ErrConvertFailed
-	}
-
-	// * if caregiver request is not empty, check whether the member already has one.
-	// * if not, then convert it to model.
-	if req.Caregiver != nil {
-		if member.Caregiver != nil {
-			if member.Caregiver.User.Email != req.Caregiver.User.Email {
-				err := consttypes.ErrCannotChangeEmail
-
-				utlogger.Error(err)
-				return nil, err
-			}
-
-			caregiver, err = s.rcare.GetByID(*member.CaregiverID)
-			if err != nil {
-				return nil, consttypes.ErrCaregiverNotFound
-			}
-		}
-
-		caregiver, err = req.Caregiver.ToModel(caregiver)
-		if err != nil {
-			return nil, consttypes.ErrConvertFailed
-		}
+		return nil, consttypes.ErrConvertFailed.Wrap(err)
 	}
 
 	// * check the organization id and assign it to the object.
 	if req.OrganizationID != nil {
-		organization, err = s.rorg.GetByID(*req.OrganizationID)
+		organization, err = s.rorg.GetByID(ctx, *req.OrganizationID)
 		if err != nil {
-			return nil, consttypes.ErrOrganizationNotFound
+			return nil, consttypes.ErrOrganizationNotFound.Wrap(err)
 		}
 	}
 
 	// * delete the existing illnesses and allergies
-	mills, _ := s.rmill.GetByMemberID(member.ID)
+	mills, _ := s.rmill.GetByMemberID(ctx, member.ID)
 	for _, mill := range mills {
-		err = s.rmill.Delete(*mill)
+		err = s.rmill.Delete(ctx, *mill)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	malls, _ := s.rmall.GetByMemberID(member.ID)
+	malls, _ := s.rmall.GetByMemberID(ctx, member.ID)
 	for _, mall := range malls {
-		err = s.rmall.Delete(*mall)
+		err = s.rmall.Delete(ctx, *mall)
 		if err != nil {
 			return nil, err
 		}
@@ -235,9 +231,9 @@ func (s *MemberService) Update(id uuid.UUID, req requests.UpdateMember) (*respon
 
 	// * find illness object and append to the array.
 	for _, ill := range req.IllnessID {
-		illness, err := s.rill.GetByID(*ill)
+		illness, err := s.rill.GetByID(ctx, *ill)
 		if err != nil {
-			return nil, consttypes.ErrIllnessNotFound
+			return nil, consttypes.ErrIllnessNotFound.Wrap(err)
 		}
 
 		millness := illness.ToMemberIllness()
@@ -247,9 +243,9 @@ func (s *MemberService) Update(id uuid.UUID, req requests.UpdateMember) (*respon
 
 	// * find allergy object and append to the array.
 	for _, all := range req.AllergyID {
-		allergy, err := s.rall.GetByID(*all)
+		allergy, err := s.rall.GetByID(ctx, *all)
 		if err != nil {
-			return nil, consttypes.ErrAllergiesNotFound
+			return nil, consttypes.ErrAllergiesNotFound.Wrap(err)
 		}
 
 		mallergy := allergy.ToMemberAllergy()
@@ -258,137 +254,199 @@ func (s *MemberService) Update(id uuid.UUID, req requests.UpdateMember) (*respon
 	}
 
 	// * copy the request to the member model.
-	member, err = req.ToModel(*member, *user, caregiver, allergies, illnesses, organization)
+	member, err = req.ToModel(*member, *user, allergies, illnesses, organization)
 	if err != nil {
-		return nil, consttypes.ErrConvertFailed
+		return nil, consttypes.ErrConvertFailed.Wrap(err)
 	}
 
-	member, err = s.rmemb.Update(*member)
+	member, err = s.rmemb.Update(ctx, *member)
 	if err != nil {
-		return nil, consttypes.ErrFailedToUpdateMember
+		return nil, consttypes.ErrFailedToUpdateMember.Wrap(err)
+	}
+
+	if err := s.recordHealthHistory(ctx, *member, previous, actorid); err != nil {
+		return nil, err
 	}
 
 	mres, err := member.ToResponse()
 	if err != nil {
-		return nil, consttypes.ErrConvertFailed
+		return nil, consttypes.ErrConvertFailed.Wrap(err)
 	}
 
 	return mres, nil
 }
 
-func (s *MemberService) Delete(id uuid.UUID) error {
-	member, err := s.rmemb.GetByID(id)
+func (s *MemberService) Delete(ctx context.Context, id uuid.UUID) error {
+	member, err := s.rmemb.GetByID(ctx, id)
 	if err != nil {
-		return consttypes.ErrMemberNotFound
+		return consttypes.ErrMemberNotFound.Wrap(err)
 	}
 
-	if err := s.rmemb.Delete(*member); err != nil {
-		return consttypes.ErrFailedToDeleteMember
+	if err := s.rmemb.Delete(ctx, *member); err != nil {
+		return consttypes.ErrFailedToDeleteMember.Wrap(err)
 	}
 
 	return nil
 }
 
-func (s *MemberService) FindAll(preq utpagination.Pagination) (*utpagination.Pagination, error) {
-	members, err := s.rmemb.FindAll(preq)
+func (s *MemberService) FindAll(ctx context.Context, preq utpagination.Pagination) (*utpagination.Pagination, error) {
+	members, err := s.rmemb.FindAll(ctx, preq)
 	if err != nil {
-		return nil, consttypes.ErrFailedToFindAllMembers
+		if errors.Is(err, consttypes.ErrInvalidFilter) {
+			return nil, err
+		}
+
+		return nil, consttypes.ErrFailedToFindAllMembers.Wrap(err)
 	}
 
 	return members, nil
 }
 
-func (s *MemberService) GetByID(id uuid.UUID) (*responses.Member, error) {
-	member, err := s.rmemb.GetByID(id)
+func (s *MemberService) GetByID(ctx context.Context, id uuid.UUID) (*responses.Member, error) {
+	member, err := s.rmemb.GetByID(ctx, id)
 	if err != nil {
-		return nil, consttypes.ErrMemberNotFound
+		return nil, consttypes.ErrMemberNotFound.Wrap(err)
 	}
 
 	mres, err := member.ToResponse()
 	if err != nil {
-		return nil, consttypes.ErrConvertFailed
+		return nil, consttypes.ErrConvertFailed.Wrap(err)
 	}
 
 	return mres, nil
 }
 
-func (s *MemberService) GetByCaregiverID(cgid uuid.UUID) (*responses.Member, error) {
-	member, err := s.rmemb.GetByCaregiverID(cgid)
-	if err != nil {
-		return nil, consttypes.ErrMemberNotFound
-	}
+func (s *MemberService) UpdateHealth(ctx context.Context, id uuid.UUID, req requests.UpdateMemberHealth, actorid uuid.UUID) (*responses.Member, error) {
+	var (
+		illnesses []*models.MemberIllness
+		allergies []*models.MemberAllergy
+		err       error
+	)
 
-	mres, err := member.ToResponse()
+	member, err := s.rmemb.GetByID(ctx, id)
 	if err != nil {
-		return nil, consttypes.ErrConvertFailed
+		return nil, consttypes.ErrMemberNotFound.Wrap(err)
 	}
 
-	return mres, nil
-}
+	// * keep the health data before the update for the history
+	previous := models.NewMemberHealthSnapshot(*member)
 
-func (s *MemberService) UpdateOwnCaregiver(mid uuid.UUID, req requests.UpdateCaregiver) (*responses.Caregiver, error) {
-	var (
-		caregiver *models.Caregiver
-	)
+	// * delete the existing illnesses and allergies
+	mills, _ := s.rmill.GetByMemberID(ctx, member.ID)
+	for _, mill := range mills {
+		err = s.rmill.Delete(ctx, *mill)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	member, err := s.rmemb.GetByID(mid)
-	if err != nil {
-		return nil, consttypes.ErrMemberNotFound
+	malls, _ := s.rmall.GetByMemberID(ctx, member.ID)
+	for _, mall := range malls {
+		err = s.rmall.Delete(ctx, *mall)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	if member.Caregiver != nil {
-		if req.User != nil {
-			if member.Caregiver.User.Email != req.User.Email && req.User.Email != "" {
-				return nil, consttypes.ErrCannotChangeEmail
-			}
+	// * find illness object and append to the array.
+	for _, ill := range req.IllnessID {
+		illness, err := s.rill.GetByID(ctx, *ill)
+		if err != nil {
+			return nil, consttypes.ErrIllnessNotFound.Wrap(err)
 		}
 
-		caregiver, err = s.rcare.GetByID(*member.CaregiverID)
+		millness := illness.ToMemberIllness()
+
+		illnesses = append(illnesses, millness)
+	}
+
+	// * find allergy object and append to the array.
+	for _, all := range req.AllergyID {
+		allergy, err := s.rall.GetByID(ctx, *all)
 		if err != nil {
-			return nil, consttypes.ErrCaregiverNotFound
+			return nil, consttypes.ErrAllergiesNotFound.Wrap(err)
 		}
+
+		mallergy := allergy.ToMemberAllergy()
+
+		allergies = append(allergies, mallergy)
 	}
 
-	caregiver, err = req.ToModel(caregiver)
+	member, err = s.rmemb.Update(ctx, *req.ToModel(*member, allergies, illnesses))
 	if err != nil {
-		return nil, consttypes.ErrConvertFailed
+		return nil, consttypes.ErrFailedToUpdateMember.Wrap(err)
 	}
 
-	caregiver, err = s.rcare.Update(*caregiver)
-	if err != nil {
+	if err := s.recordHealthHistory(ctx, *member, previous, actorid); err != nil {
 		return nil, err
 	}
 
-	cres, err := caregiver.ToResponse()
+	mres, err := member.ToResponse()
+	if err != nil {
+		return nil, consttypes.ErrConvertFailed.Wrap(err)
+	}
+
+	return mres, nil
+}
+
+func (s *MemberService) FindHealthHistory(ctx context.Context, id uuid.UUID, preq utpagination.Pagination) (*utpagination.Pagination, error) {
+	mhhs, err := s.rmhh.FindByMemberID(ctx, id, preq)
 	if err != nil {
-		return nil, consttypes.ErrConvertFailed
+		if errors.Is(err, consttypes.ErrInvalidCursor) || errors.Is(err, consttypes.ErrInvalidFilter) {
+			return nil, err
+		}
+
+		return nil, consttypes.ErrFailedToReadHealthHistory.Wrap(err)
 	}
 
-	return cres, nil
+	return mhhs, nil
 }
 
-func (s *MemberService) UpdateOwnCaregiverPassword(mid uuid.UUID, req requests.UpdatePassword) error {
-	// * get the member by its id
-	member, err := s.rmemb.GetByID(mid)
+func (s *MemberService) GetHealthTrend(ctx context.Context, id uuid.UUID, filter utpagination.Filter) ([]*responses.MemberHealthTrend, error) {
+	var (
+		mhtreses []*responses.MemberHealthTrend
+	)
+
+	mhhs, err := s.rmhh.FindTrendByMemberID(ctx, id, filter)
 	if err != nil {
-		return err
+		return nil, consttypes.ErrFailedToReadHealthHistory.Wrap(err)
 	}
 
-	// * check if the caregiver exist or not
-	if member.Caregiver == nil {
-		return consttypes.ErrCaregiverNotFound
+	for _, mhh := range mhhs {
+		mhtreses = append(mhtreses, mhh.ToTrendResponse())
 	}
 
-	// * check if the old password is correct
-	ok := utstring.CheckPasswordHash(req.OldPassword, member.Caregiver.User.Password)
-	if !ok {
-		return consttypes.ErrInvalidEmailOrPassword
+	return mhtreses, nil
+}
+
+// * store a new version of the member's health data when it changed,
+// * previous is empty for the very first record of the member
+func (s *MemberService) recordHealthHistory(ctx context.Context, member models.Member, previous *models.MemberHealthSnapshot, actorid uuid.UUID) error {
+	var (
+		version = 1
+	)
+
+	if previous != nil && previous.Equal(*models.NewMemberHealthSnapshot(member)) {
+		return nil
 	}
 
-	// * update the caregiver's password
-	_, err = s.ruser.UpdatePassword(member.Caregiver.User.ID, req.NewPassword)
+	latest, err := s.rmhh.GetLatestByMemberID(ctx, member.ID)
+	if err == nil {
+		version = latest.Version + 1
+	} else if err != gorm.ErrRecordNotFound {
+		return consttypes.ErrFailedToRecordHealthHistory
+	}
+
+	// * the actor is optional, the history is still recorded
+	// * when the user who made the change cannot be found
+	actor, err := s.ruser.GetByID(ctx, actorid)
 	if err != nil {
-		return err
+		actor = nil
+	}
+
+	mhh := models.NewMemberHealthHistory(member, version, previous, actor)
+	if _, err := s.rmhh.Create(ctx, *mhh); err != nil {
+		return consttypes.ErrFailedToRecordHealthHistory.Wrap(err)
 	}
 
 	return nil
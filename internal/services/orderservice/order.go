@@ -1,6 +1,7 @@
 package orderservice
 
 import (
+	"context"
 	"project-skbackend/configs"
 	"project-skbackend/internal/controllers/requests"
 	"project-skbackend/internal/controllers/responses"
@@ -13,7 +14,9 @@ import (
 	"project-skbackend/internal/repositories/partnerrepo"
 	"project-skbackend/internal/repositories/userrepo"
 	"project-skbackend/internal/services/baseroleservice"
+	"project-skbackend/internal/services/webhookservice"
 	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/metrics"
 	"project-skbackend/packages/utils/utlogger"
 	"project-skbackend/packages/utils/utpagination"
 	"project-skbackend/packages/utils/utslice"
@@ -33,19 +36,20 @@ type (
 		rpart partnerrepo.IPartnerRepository
 
 		sbsrl baseroleservice.IBaseRoleService
+		swebh webhookservice.IWebhookService
 
 		maxord int
 	}
 
 	IOrderService interface {
-		Create(req requests.CreateOrder, useroderid uuid.UUID) (*responses.Order, error)
-		Read() ([]*responses.Order, error)
-		Delete(id uuid.UUID) error
-		FindAll(preq utpagination.Pagination) (*utpagination.Pagination, error)
-		GetByID(id uuid.UUID) (*responses.Order, error)
+		Create(ctx context.Context, req requests.CreateOrder, useroderid uuid.UUID) (*responses.Order, error)
+		Read(ctx context.Context) ([]*responses.Order, error)
+		Delete(ctx context.Context, id uuid.UUID) error
+		FindAll(ctx context.Context, preq utpagination.Pagination) (*utpagination.Pagination, error)
+		GetByID(ctx context.Context, id uuid.UUID) (*responses.Order, error)
 
-		GetMemberRemainingOrder(uid uuid.UUID) (*responses.OrderRemaining, error)
-		FindByRoleRes(roleres responses.BaseRole) ([]*responses.Order, error)
+		GetMemberRemainingOrder(ctx context.Context, uid uuid.UUID, mid *uuid.UUID) (*responses.OrderRemaining, error)
+		FindByRoleRes(ctx context.Context, roleres responses.BaseRole, mid *uuid.UUID) ([]*responses.Order, error)
 	}
 )
 
@@ -59,6 +63,7 @@ func NewOrderService(
 	rcart cartrepo.ICartRepository,
 	rpart partnerrepo.IPartnerRepository,
 	sbsrl baseroleservice.IBaseRoleService,
+	swebh webhookservice.IWebhookService,
 ) *OrderService {
 	return &OrderService{
 		rord:  rord,
@@ -70,26 +75,27 @@ func NewOrderService(
 		rpart: rpart,
 
 		sbsrl: sbsrl,
+		swebh: swebh,
 
 		maxord: cfg.OrderMax.Member,
 	}
 }
 
-func (s *OrderService) Create(req requests.CreateOrder, useroderid uuid.UUID) (*responses.Order, error) {
+func (s *OrderService) Create(ctx context.Context, req requests.CreateOrder, useroderid uuid.UUID) (*responses.Order, error) {
 	// * retrieves the member and user order based on the provided useroderid
-	member, userorder, err := s.getMemberAndUserOrder(useroderid)
+	member, userorder, err := s.getMemberAndUserOrder(ctx, useroderid, req.MemberID)
 	if err != nil {
 		return nil, err
 	}
 
 	// * processes the cart items and calculates the total quantity
-	omeals, partner, qty, err := s.processCarts(req.CartIDs)
+	omeals, partner, qty, err := s.processCarts(ctx, req.CartIDs)
 	if err != nil {
 		return nil, err
 	}
 
 	// * checks if the daily order limit has been reached
-	_, err = s.checkDailyOrderLimit(member.ID, qty)
+	_, err = s.checkDailyOrderLimit(ctx, member.ID, qty)
 	if err != nil {
 		return nil, err
 	}
@@ -97,38 +103,42 @@ func (s *OrderService) Create(req requests.CreateOrder, useroderid uuid.UUID) (*
 	// * converts the request to an order model
 	order, err := req.ToModel(*member, *userorder, omeals, *partner)
 	if err != nil {
-		return nil, consttypes.ErrConvertFailed
+		return nil, consttypes.ErrConvertFailed.Wrap(err)
 	}
 
 	// * creates the order in the repository
-	order, err = s.rord.Create(*order)
+	order, err = s.rord.Create(ctx, *order)
 	if err != nil {
-		return nil, consttypes.ErrFailedToCreateOrder
+		return nil, consttypes.ErrFailedToCreateOrder.Wrap(err)
 	}
 
 	// * converts the order model to a response
 	ordres, err := order.ToResponse()
 	if err != nil {
-		return nil, consttypes.ErrConvertFailed
+		return nil, consttypes.ErrConvertFailed.Wrap(err)
 	}
 
 	// * delete the cart after processing
-	err = s.rcart.DeleteByIDs(req.CartIDs)
+	err = s.rcart.DeleteByIDs(ctx, req.CartIDs)
 	if err != nil {
 		return nil, err
 	}
 
+	// * notify the partner and the organization of the new order
+	metrics.ObserveOrderTransition("", order.Status.String(), 1)
+	s.swebh.DispatchOrder(ctx, consttypes.WET_ORDER_CREATED, *order, "")
+
 	return ordres, nil
 }
 
 // * retrieves the member and user order based on the provided useroderid
-func (s *OrderService) getMemberAndUserOrder(useroderid uuid.UUID) (*models.Member, *models.User, error) {
-	userorder, err := s.ruser.GetByID(useroderid)
+func (s *OrderService) getMemberAndUserOrder(ctx context.Context, useroderid uuid.UUID, mid *uuid.UUID) (*models.Member, *models.User, error) {
+	userorder, err := s.ruser.GetByID(ctx, useroderid)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	member, err := s.getMemberByUserID(useroderid)
+	member, err := s.getMemberByUserID(ctx, useroderid, mid, consttypes.CA_ORDER)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -137,7 +147,7 @@ func (s *OrderService) getMemberAndUserOrder(useroderid uuid.UUID) (*models.Memb
 }
 
 // * processes the cart items and calculates the total quantity
-func (s *OrderService) processCarts(cartIDs []uuid.UUID) ([]models.OrderMeal, *models.Partner, int, error) {
+func (s *OrderService) processCarts(ctx context.Context, cartIDs []uuid.UUID) ([]models.OrderMeal, *models.Partner, int, error) {
 	var (
 		omeals []models.OrderMeal
 		qty    int
@@ -145,9 +155,9 @@ func (s *OrderService) processCarts(cartIDs []uuid.UUID) ([]models.OrderMeal, *m
 	)
 
 	for _, cid := range cartIDs {
-		cart, err := s.rcart.GetByID(cid)
+		cart, err := s.rcart.GetByID(ctx, cid)
 		if err != nil {
-			return nil, nil, 0, consttypes.ErrCartNotFound
+			return nil, nil, 0, consttypes.ErrCartNotFound.Wrap(err)
 		}
 
 		// * append all of the cart partner ids
@@ -164,19 +174,19 @@ func (s *OrderService) processCarts(cartIDs []uuid.UUID) ([]models.OrderMeal, *m
 	}
 
 	// * get the partner
-	partner, err := s.rpart.GetByID(pids[0])
+	partner, err := s.rpart.GetByID(ctx, pids[0])
 	if err != nil {
-		return nil, nil, 0, consttypes.ErrPartnerNotFound
+		return nil, nil, 0, consttypes.ErrPartnerNotFound.Wrap(err)
 	}
 
 	return omeals, partner, qty, nil
 }
 
 // * checks if the daily order limit has been reached
-func (s *OrderService) checkDailyOrderLimit(mid uuid.UUID, qty int) (int, error) {
-	dailyorder, err := s.rord.GetMemberDailyOrder(mid)
+func (s *OrderService) checkDailyOrderLimit(ctx context.Context, mid uuid.UUID, qty int) (int, error) {
+	dailyorder, err := s.rord.GetMemberDailyOrder(ctx, mid)
 	if err != nil {
-		return 0, consttypes.ErrFailedToGetDailyOrder
+		return 0, consttypes.ErrFailedToGetDailyOrder.Wrap(err)
 	}
 
 	// * add the order to the daily order
@@ -190,33 +200,35 @@ func (s *OrderService) checkDailyOrderLimit(mid uuid.UUID, qty int) (int, error)
 	return qty, nil
 }
 
-func (s *OrderService) getMemberByUserID(uid uuid.UUID) (*models.Member, error) {
+// * get the member the user is acting for, caregivers have to pass the
+// * member id explicitly since they can be linked to multiple members
+func (s *OrderService) getMemberByUserID(ctx context.Context, uid uuid.UUID, mid *uuid.UUID, access consttypes.CaregiverAccess) (*models.Member, error) {
 	var (
 		member *models.Member
 	)
 
 	// * get the user who is ordering
-	userorder, err := s.ruser.GetByID(uid)
+	userorder, err := s.ruser.GetByID(ctx, uid)
 	if err != nil {
-		return nil, consttypes.ErrUserNotFound
+		return nil, consttypes.ErrUserNotFound.Wrap(err)
 	}
 
 	// * get the member who is ordering
 	switch userorder.Role {
 	case consttypes.UR_MEMBER:
-		member, err = s.rmemb.GetByUserID(uid)
+		member, err = s.rmemb.GetByUserID(ctx, uid)
 		if err != nil {
-			return nil, consttypes.ErrMemberNotFound
+			return nil, consttypes.ErrMemberNotFound.Wrap(err)
 		}
 	case consttypes.UR_CAREGIVER:
-		caregiver, err := s.rcare.GetByUserID(uid)
+		caregiver, err := s.rcare.GetByUserID(ctx, uid)
 		if err != nil {
-			return nil, consttypes.ErrCaregiverNotFound
+			return nil, consttypes.ErrCaregiverNotFound.Wrap(err)
 		}
 
-		member, err = s.rmemb.GetByCaregiverID(caregiver.ID)
+		member, err = s.sbsrl.GetMemberByCaregiverID(ctx, caregiver.ID, mid, access)
 		if err != nil {
-			return nil, consttypes.ErrMemberNotFound
+			return nil, err
 		}
 	default:
 		return nil, consttypes.ErrUserNotFound
@@ -225,64 +237,64 @@ func (s *OrderService) getMemberByUserID(uid uuid.UUID) (*models.Member, error)
 	return member, nil
 }
 
-func (s *OrderService) Read() ([]*responses.Order, error) {
+func (s *OrderService) Read(ctx context.Context) ([]*responses.Order, error) {
 	var (
 		orderreses []*responses.Order
 	)
 
-	orders, err := s.rord.Read()
+	orders, err := s.rord.Read(ctx)
 	if err != nil {
-		return nil, consttypes.ErrFailedToReadOrder
+		return nil, consttypes.ErrFailedToReadOrder.Wrap(err)
 	}
 
 	if err := copier.CopyWithOption(&orderreses, &orders, copier.Option{IgnoreEmpty: true, DeepCopy: true}); err != nil {
-		utlogger.Error(err)
-		return nil, consttypes.ErrConvertFailed
+		utlogger.ErrorContext(ctx, err)
+		return nil, consttypes.ErrConvertFailed.Wrap(err)
 	}
 
 	return orderreses, nil
 }
 
-func (s *OrderService) Delete(id uuid.UUID) error {
-	order, err := s.rord.GetByID(id)
+func (s *OrderService) Delete(ctx context.Context, id uuid.UUID) error {
+	order, err := s.rord.GetByID(ctx, id)
 	if err != nil {
-		return consttypes.ErrOrderNotFound
+		return consttypes.ErrOrderNotFound.Wrap(err)
 	}
 
-	if err := s.rord.Delete(*order); err != nil {
-		return consttypes.ErrFailedToDeleteOrder
+	if err := s.rord.Delete(ctx, *order); err != nil {
+		return consttypes.ErrFailedToDeleteOrder.Wrap(err)
 	}
 
 	return nil
 }
 
-func (s *OrderService) FindAll(preq utpagination.Pagination) (*utpagination.Pagination, error) {
-	return s.rord.FindAll(preq)
+func (s *OrderService) FindAll(ctx context.Context, preq utpagination.Pagination) (*utpagination.Pagination, error) {
+	return s.rord.FindAll(ctx, preq)
 }
 
-func (s *OrderService) GetByID(id uuid.UUID) (*responses.Order, error) {
-	order, err := s.rord.GetByID(id)
+func (s *OrderService) GetByID(ctx context.Context, id uuid.UUID) (*responses.Order, error) {
+	order, err := s.rord.GetByID(ctx, id)
 	if err != nil {
-		return nil, consttypes.ErrOrderNotFound
+		return nil, consttypes.ErrOrderNotFound.Wrap(err)
 	}
 
 	ordres, err := order.ToResponse()
 	if err != nil {
-		return nil, consttypes.ErrConvertFailed
+		return nil, consttypes.ErrConvertFailed.Wrap(err)
 	}
 
 	return ordres, nil
 }
 
-func (s *OrderService) GetMemberRemainingOrder(uid uuid.UUID) (*responses.OrderRemaining, error) {
-	member, err := s.getMemberByUserID(uid)
+func (s *OrderService) GetMemberRemainingOrder(ctx context.Context, uid uuid.UUID, mid *uuid.UUID) (*responses.OrderRemaining, error) {
+	member, err := s.getMemberByUserID(ctx, uid, mid, consttypes.CA_VIEW)
 	if err != nil {
 		return nil, err
 	}
 
-	dailyorder, err := s.rord.GetMemberDailyOrder(member.ID)
+	dailyorder, err := s.rord.GetMemberDailyOrder(ctx, member.ID)
 	if err != nil {
-		return nil, consttypes.ErrFailedToGetDailyOrder
+		return nil, consttypes.ErrFailedToGetDailyOrder.Wrap(err)
 	}
 
 	remorder := s.maxord - dailyorder
@@ -295,17 +307,17 @@ func (s *OrderService) GetMemberRemainingOrder(uid uuid.UUID) (*responses.OrderR
 	return remorderrer, nil
 }
 
-func (s *OrderService) FindByRoleRes(roleres responses.BaseRole) ([]*responses.Order, error) {
+func (s *OrderService) FindByRoleRes(ctx context.Context, roleres responses.BaseRole, mid *uuid.UUID) ([]*responses.Order, error) {
 	var (
 		orderreses []*responses.Order
 		err        error
 	)
 
 	switch roleres.Role {
-	case consttypes.UR_MEMBER:
-		orderreses, err = s.FindMemberOrder(roleres)
+	case consttypes.UR_MEMBER, consttypes.UR_CAREGIVER:
+		orderreses, err = s.FindMemberOrder(ctx, roleres, mid)
 	case consttypes.UR_PARTNER:
-		orderreses, err = s.FindPartnerOrder(roleres)
+		orderreses, err = s.FindPartnerOrder(ctx, roleres)
 	default:
 		return nil, consttypes.ErrFailedToReadOrder
 	}
@@ -317,25 +329,25 @@ func (s *OrderService) FindByRoleRes(roleres responses.BaseRole) ([]*responses.O
 	return orderreses, nil
 }
 
-func (s *OrderService) FindMemberOrder(roleres responses.BaseRole) ([]*responses.Order, error) {
+func (s *OrderService) FindMemberOrder(ctx context.Context, roleres responses.BaseRole, mid *uuid.UUID) ([]*responses.Order, error) {
 	var (
 		orderreses []*responses.Order
 	)
 
-	m, err := s.sbsrl.GetMemberByBaseRole(roleres)
+	m, err := s.sbsrl.GetMemberByBaseRole(ctx, roleres, mid, consttypes.CA_VIEW)
 	if err != nil {
 		return nil, err
 	}
 
-	orders, err := s.rord.FindByMemberID(m.ID)
+	orders, err := s.rord.FindByMemberID(ctx, m.ID)
 	if err != nil {
-		return nil, consttypes.ErrFailedToReadOrder
+		return nil, consttypes.ErrFailedToReadOrder.Wrap(err)
 	}
 
 	for _, order := range orders {
 		ordres, err := order.ToResponse()
 		if err != nil {
-			return nil, consttypes.ErrConvertFailed
+			return nil, consttypes.ErrConvertFailed.Wrap(err)
 		}
 
 		orderreses = append(orderreses, ordres)
@@ -344,25 +356,25 @@ func (s *OrderService) FindMemberOrder(roleres responses.BaseRole) ([]*responses
 	return orderreses, nil
 }
 
-func (s *OrderService) FindPartnerOrder(roleres responses.BaseRole) ([]*responses.Order, error) {
+func (s *OrderService) FindPartnerOrder(ctx context.Context, roleres responses.BaseRole) ([]*responses.Order, error) {
 	var (
 		orderreses []*responses.Order
 	)
 
-	p, err := s.sbsrl.GetPartnerByBaseRole(roleres)
+	p, err := s.sbsrl.GetPartnerByBaseRole(ctx, roleres)
 	if err != nil {
 		return nil, err
 	}
 
-	orders, err := s.rord.FindByPartnerID(p.ID)
+	orders, err := s.rord.FindByPartnerID(ctx, p.ID)
 	if err != nil {
-		return nil, consttypes.ErrFailedToReadOrder
+		return nil, consttypes.ErrFailedToReadOrder.Wrap(err)
 	}
 
 	for _, order := range orders {
-		ordres, err := order.ToResponse()
+		ordres, err := order.ToPartnerResponse()
 		if err != nil {
-			return nil, consttypes.ErrConvertFailed
+			return nil, consttypes.ErrConvertFailed.Wrap(err)
 		}
 
 		orderreses = append(orderreses, ordres)
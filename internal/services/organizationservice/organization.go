@@ -1,6 +1,7 @@
 package organizationservice
 
 import (
+	"context"
 	"project-skbackend/internal/controllers/requests"
 	"project-skbackend/internal/controllers/responses"
 	"project-skbackend/internal/repositories/organizationrepo"
@@ -18,12 +19,12 @@ type (
 	}
 
 	IOrganizationService interface {
-		Create(req requests.CreateOrganization) (*responses.Organization, error)
-		Read() ([]*responses.Organization, error)
-		Update(id uuid.UUID, req requests.UpdateOrganization) (*responses.Organization, error)
-		Delete(id uuid.UUID) error
-		FindAll(preq utpagination.Pagination) (*utpagination.Pagination, error)
-		GetByID(id uuid.UUID) (*responses.Organization, error)
+		Create(ctx context.Context, req requests.CreateOrganization) (*responses.Organization, error)
+		Read(ctx context.Context) ([]*responses.Organization, error)
+		Update(ctx context.Context, id uuid.UUID, req requests.UpdateOrganization) (*responses.Organization, error)
+		Delete(ctx context.Context, id uuid.UUID) error
+		FindAll(ctx context.Context, preq utpagination.Pagination) (*utpagination.Pagination, error)
+		GetByID(ctx context.Context, id uuid.UUID) (*responses.Organization, error)
 	}
 )
 
@@ -35,7 +36,7 @@ func NewOrganizationService(
 	}
 }
 
-func (s *OrganizationService) Create(req requests.CreateOrganization) (*responses.Organization, error) {
+func (s *OrganizationService) Create(ctx context.Context, req requests.CreateOrganization) (*responses.Organization, error) {
 	user, err := req.User.ToModel(consttypes.UR_ORGANIZATION)
 	if err != nil {
 		return nil, err
@@ -46,7 +47,7 @@ func (s *OrganizationService) Create(req requests.CreateOrganization) (*response
 		return nil, err
 	}
 
-	org, err = s.rorg.Create(*org)
+	org, err = s.rorg.Create(ctx, *org)
 	if err != nil {
 		return nil, err
 	}
@@ -59,26 +60,26 @@ func (s *OrganizationService) Create(req requests.CreateOrganization) (*response
 	return orgres, nil
 }
 
-func (s *OrganizationService) Read() ([]*responses.Organization, error) {
+func (s *OrganizationService) Read(ctx context.Context) ([]*responses.Organization, error) {
 	var (
 		orgreses []*responses.Organization
 	)
 
-	org, err := s.rorg.Read()
+	org, err := s.rorg.Read(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	if err := copier.CopyWithOption(&orgreses, &org, copier.Option{IgnoreEmpty: true, DeepCopy: true}); err != nil {
-		utlogger.Error(err)
+		utlogger.ErrorContext(ctx, err)
 		return nil, err
 	}
 
 	return orgreses, nil
 }
 
-func (s *OrganizationService) Update(id uuid.UUID, req requests.UpdateOrganization) (*responses.Organization, error) {
-	org, err := s.rorg.GetByID(id)
+func (s *OrganizationService) Update(ctx context.Context, id uuid.UUID, req requests.UpdateOrganization) (*responses.Organization, error) {
+	org, err := s.rorg.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -93,7 +94,7 @@ func (s *OrganizationService) Update(id uuid.UUID, req requests.UpdateOrganizati
 		return nil, err
 	}
 
-	org, err = s.rorg.Update(*org)
+	org, err = s.rorg.Update(ctx, *org)
 	if err != nil {
 		return nil, err
 	}
@@ -106,17 +107,17 @@ func (s *OrganizationService) Update(id uuid.UUID, req requests.UpdateOrganizati
 	return orgres, nil
 }
 
-func (s *OrganizationService) Delete(id uuid.UUID) error {
-	org, err := s.rorg.GetByID(id)
+func (s *OrganizationService) Delete(ctx context.Context, id uuid.UUID) error {
+	org, err := s.rorg.GetByID(ctx, id)
 	if err != nil {
 		return err
 	}
 
-	return s.rorg.Delete(*org)
+	return s.rorg.Delete(ctx, *org)
 }
 
-func (s *OrganizationService) FindAll(preq utpagination.Pagination) (*utpagination.Pagination, error) {
-	orgs, err := s.rorg.FindAll(preq)
+func (s *OrganizationService) FindAll(ctx context.Context, preq utpagination.Pagination) (*utpagination.Pagination, error) {
+	orgs, err := s.rorg.FindAll(ctx, preq)
 	if err != nil {
 		return nil, err
 	}
@@ -124,8 +125,8 @@ func (s *OrganizationService) FindAll(preq utpagination.Pagination) (*utpaginati
 	return orgs, nil
 }
 
-func (s *OrganizationService) GetByID(id uuid.UUID) (*responses.Organization, error) {
-	org, err := s.rorg.GetByID(id)
+func (s *OrganizationService) GetByID(ctx context.Context, id uuid.UUID) (*responses.Organization, error) {
+	org, err := s.rorg.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
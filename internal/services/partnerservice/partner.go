@@ -1,6 +1,7 @@
 package partnerservice
 
 import (
+	"context"
 	"project-skbackend/internal/controllers/requests"
 	"project-skbackend/internal/controllers/responses"
 	"project-skbackend/internal/models"
@@ -9,7 +10,10 @@ import (
 	"project-skbackend/internal/repositories/orderrepo"
 	"project-skbackend/internal/repositories/partnerrepo"
 	"project-skbackend/internal/repositories/userrepo"
+	"project-skbackend/internal/services/permissionservice"
+	"project-skbackend/internal/services/webhookservice"
 	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/metrics"
 	"project-skbackend/packages/utils/utpagination"
 
 	"github.com/google/uuid"
@@ -22,26 +26,29 @@ type (
 		ruser userrepo.IUserRepository
 		rorme ordermealrepo.IOrderMealRepository
 		rmeal mealrepo.IMealRepository
+
+		sperm permissionservice.IPermissionService
+		swebh webhookservice.IWebhookService
 	}
 
 	IPartnerService interface {
-		Create(req requests.CreatePartner) (*responses.Partner, error)
-		Read() ([]*models.Partner, error)
-		Update(id uuid.UUID, req requests.UpdatePartner) (*responses.Partner, error)
-		Delete(id uuid.UUID) error
-		FindAll(preq utpagination.Pagination) (*utpagination.Pagination, error)
-		GetByID(id uuid.UUID) (*responses.Partner, error)
+		Create(ctx context.Context, req requests.CreatePartner) (*responses.Partner, error)
+		Read(ctx context.Context) ([]*models.Partner, error)
+		Update(ctx context.Context, id uuid.UUID, req requests.UpdatePartner) (*responses.Partner, error)
+		Delete(ctx context.Context, id uuid.UUID) error
+		FindAll(ctx context.Context, preq utpagination.Pagination) (*utpagination.Pagination, error)
+		GetByID(ctx context.Context, id uuid.UUID) (*responses.Partner, error)
 
 		// * order related
-		FindOwnOrders(uid uuid.UUID, preq utpagination.Pagination) (*utpagination.Pagination, error)
-		OrderConfirmed(oid uuid.UUID, uid uuid.UUID) error
-		OrderBeingPrepared(oid uuid.UUID, uid uuid.UUID) error
-		OrderPrepared(oid uuid.UUID, uid uuid.UUID) error
-		OrderPickedUp(oid uuid.UUID, uid uuid.UUID) error
+		FindOwnOrders(ctx context.Context, uid uuid.UUID, preq utpagination.Pagination) (*utpagination.Pagination, error)
+		OrderConfirmed(ctx context.Context, oid uuid.UUID, uid uuid.UUID) error
+		OrderBeingPrepared(ctx context.Context, oid uuid.UUID, uid uuid.UUID) error
+		OrderPrepared(ctx context.Context, oid uuid.UUID, uid uuid.UUID) error
+		OrderPickedUp(ctx context.Context, oid uuid.UUID, uid uuid.UUID) error
 
 		// * meal related
-		ReadOwnMeal(uid uuid.UUID) ([]*responses.Meal, error)
-		FindOwnMeals(uid uuid.UUID, preq utpagination.Pagination) (*utpagination.Pagination, error)
+		ReadOwnMeal(ctx context.Context, uid uuid.UUID) ([]*responses.Meal, error)
+		FindOwnMeals(ctx context.Context, uid uuid.UUID, preq utpagination.Pagination) (*utpagination.Pagination, error)
 	}
 )
 
@@ -50,16 +57,23 @@ func NewPartnerService(
 	rordr orderrepo.IOrderRepository,
 	rorme ordermealrepo.IOrderMealRepository,
 	rmeal mealrepo.IMealRepository,
+	ruser userrepo.IUserRepository,
+	sperm permissionservice.IPermissionService,
+	swebh webhookservice.IWebhookService,
 ) *PartnerService {
 	return &PartnerService{
 		rpart: rpart,
 		rordr: rordr,
+		ruser: ruser,
 		rorme: rorme,
 		rmeal: rmeal,
+
+		sperm: sperm,
+		swebh: swebh,
 	}
 }
 
-func (s *PartnerService) Create(req requests.CreatePartner) (*responses.Partner, error) {
+func (s *PartnerService) Create(ctx context.Context, req requests.CreatePartner) (*responses.Partner, error) {
 	user, err := req.User.ToModel(consttypes.UR_PARTNER)
 	if err != nil {
 		return nil, err
@@ -70,7 +84,7 @@ func (s *PartnerService) Create(req requests.CreatePartner) (*responses.Partner,
 		return nil, err
 	}
 
-	partner, err = s.rpart.Create(*partner)
+	partner, err = s.rpart.Create(ctx, *partner)
 	if err != nil {
 		return nil, err
 	}
@@ -83,8 +97,8 @@ func (s *PartnerService) Create(req requests.CreatePartner) (*responses.Partner,
 	return pres, nil
 }
 
-func (s *PartnerService) Read() ([]*models.Partner, error) {
-	partners, err := s.rpart.Read()
+func (s *PartnerService) Read(ctx context.Context) ([]*models.Partner, error) {
+	partners, err := s.rpart.Read(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -92,8 +106,8 @@ func (s *PartnerService) Read() ([]*models.Partner, error) {
 	return partners, nil
 }
 
-func (s *PartnerService) Update(id uuid.UUID, req requests.UpdatePartner) (*responses.Partner, error) {
-	partner, err := s.rpart.GetByID(id)
+func (s *PartnerService) Update(ctx context.Context, id uuid.UUID, req requests.UpdatePartner) (*responses.Partner, error) {
+	partner, err := s.rpart.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -108,7 +122,7 @@ func (s *PartnerService) Update(id uuid.UUID, req requests.UpdatePartner) (*resp
 		return nil, err
 	}
 
-	partner, err = s.rpart.Update(*partner)
+	partner, err = s.rpart.Update(ctx, *partner)
 	if err != nil {
 		return nil, err
 	}
@@ -121,17 +135,17 @@ func (s *PartnerService) Update(id uuid.UUID, req requests.UpdatePartner) (*resp
 	return pres, nil
 }
 
-func (s *PartnerService) Delete(id uuid.UUID) error {
-	partner, err := s.rpart.GetByID(id)
+func (s *PartnerService) Delete(ctx context.Context, id uuid.UUID) error {
+	partner, err := s.rpart.GetByID(ctx, id)
 	if err != nil {
 		return err
 	}
 
-	return s.rpart.Delete(*partner)
+	return s.rpart.Delete(ctx, *partner)
 }
 
-func (s *PartnerService) FindAll(preq utpagination.Pagination) (*utpagination.Pagination, error) {
-	partners, err := s.rpart.FindAll(preq)
+func (s *PartnerService) FindAll(ctx context.Context, preq utpagination.Pagination) (*utpagination.Pagination, error) {
+	partners, err := s.rpart.FindAll(ctx, preq)
 	if err != nil {
 		return nil, err
 	}
@@ -139,8 +153,8 @@ func (s *PartnerService) FindAll(preq utpagination.Pagination) (*utpagination.Pa
 	return partners, nil
 }
 
-func (s *PartnerService) GetByID(id uuid.UUID) (*responses.Partner, error) {
-	partner, err := s.rpart.GetByID(id)
+func (s *PartnerService) GetByID(ctx context.Context, id uuid.UUID) (*responses.Partner, error) {
+	partner, err := s.rpart.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -153,8 +167,8 @@ func (s *PartnerService) GetByID(id uuid.UUID) (*responses.Partner, error) {
 	return pres, nil
 }
 
-func (s *PartnerService) FindOwnOrders(uid uuid.UUID, preq utpagination.Pagination) (*utpagination.Pagination, error) {
-	partner, err := s.rpart.GetByUserID(uid)
+func (s *PartnerService) FindOwnOrders(ctx context.Context, uid uuid.UUID, preq utpagination.Pagination) (*utpagination.Pagination, error) {
+	partner, err := s.rpart.GetByUserID(ctx, uid)
 	if err != nil {
 		return nil, err
 	}
@@ -162,7 +176,7 @@ func (s *PartnerService) FindOwnOrders(uid uuid.UUID, preq utpagination.Paginati
 	// * assigning partner id to the filter
 	preq.Filter.Partner.ID = &partner.ID
 
-	ordermeals, err := s.rordr.FindAll(preq)
+	ordermeals, err := s.rordr.FindAll(ctx, preq)
 	if err != nil {
 		return nil, err
 	}
@@ -170,15 +184,21 @@ func (s *PartnerService) FindOwnOrders(uid uuid.UUID, preq utpagination.Paginati
 	return ordermeals, nil
 }
 
-func (s *PartnerService) OrderConfirmed(oid uuid.UUID, uid uuid.UUID) error {
+func (s *PartnerService) OrderConfirmed(ctx context.Context, oid uuid.UUID, uid uuid.UUID) error {
 	// * get the user who confirms the order
-	user, err := s.ruser.GetByID(uid)
+	user, err := s.ruser.GetByID(ctx, uid)
 	if err != nil {
 		return err
 	}
 
 	// * get the corresponding order
-	order, err := s.rordr.GetByID(oid)
+	order, err := s.rordr.GetByID(ctx, oid)
+	if err != nil {
+		return err
+	}
+
+	// * only the partner who owns the order could update its status
+	err = s.sperm.Authorize(ctx, *user, consttypes.P_ORDER_UPDATE_STATUS, order)
 	if err != nil {
 		return err
 	}
@@ -192,23 +212,32 @@ func (s *PartnerService) OrderConfirmed(oid uuid.UUID, uid uuid.UUID) error {
 	conorder := order.OrderConfirmed(*user)
 
 	// * update the order in the database
-	_, err = s.rordr.Update(*conorder)
+	uporder, err := s.rordr.Update(ctx, *conorder)
 	if err != nil {
 		return err
 	}
 
+	metrics.ObserveOrderTransition(consttypes.OS_PLACED.String(), uporder.Status.String(), 1)
+	s.swebh.DispatchOrder(ctx, consttypes.WET_ORDER_STATUS_CHANGED, *uporder, consttypes.OS_PLACED)
+
 	return nil
 }
 
-func (s *PartnerService) OrderBeingPrepared(oid uuid.UUID, uid uuid.UUID) error {
+func (s *PartnerService) OrderBeingPrepared(ctx context.Context, oid uuid.UUID, uid uuid.UUID) error {
 	// * get the user who confirms the order
-	user, err := s.ruser.GetByID(uid)
+	user, err := s.ruser.GetByID(ctx, uid)
 	if err != nil {
 		return err
 	}
 
 	// * get the corresponding order
-	order, err := s.rordr.GetByID(oid)
+	order, err := s.rordr.GetByID(ctx, oid)
+	if err != nil {
+		return err
+	}
+
+	// * only the partner who owns the order could update its status
+	err = s.sperm.Authorize(ctx, *user, consttypes.P_ORDER_UPDATE_STATUS, order)
 	if err != nil {
 		return err
 	}
@@ -222,23 +251,32 @@ func (s *PartnerService) OrderBeingPrepared(oid uuid.UUID, uid uuid.UUID) error
 	beporder := order.OrderBeingPrepared(*user)
 
 	// * update the order in the database
-	_, err = s.rordr.Update(*beporder)
+	uporder, err := s.rordr.Update(ctx, *beporder)
 	if err != nil {
 		return err
 	}
 
+	metrics.ObserveOrderTransition(consttypes.OS_CONFIRMED.String(), uporder.Status.String(), 1)
+	s.swebh.DispatchOrder(ctx, consttypes.WET_ORDER_STATUS_CHANGED, *uporder, consttypes.OS_CONFIRMED)
+
 	return nil
 }
 
-func (s *PartnerService) OrderPrepared(oid uuid.UUID, uid uuid.UUID) error {
+func (s *PartnerService) OrderPrepared(ctx context.Context, oid uuid.UUID, uid uuid.UUID) error {
 	// * get the user who confirms the order
-	user, err := s.ruser.GetByID(uid)
+	user, err := s.ruser.GetByID(ctx, uid)
 	if err != nil {
 		return err
 	}
 
 	// * get the corresponding order
-	order, err := s.rordr.GetByID(oid)
+	order, err := s.rordr.GetByID(ctx, oid)
+	if err != nil {
+		return err
+	}
+
+	// * only the partner who owns the order could update its status
+	err = s.sperm.Authorize(ctx, *user, consttypes.P_ORDER_UPDATE_STATUS, order)
 	if err != nil {
 		return err
 	}
@@ -252,23 +290,32 @@ func (s *PartnerService) OrderPrepared(oid uuid.UUID, uid uuid.UUID) error {
 	preporder := order.OrderPrepared(*user)
 
 	// * update the order in the database
-	_, err = s.rordr.Update(*preporder)
+	uporder, err := s.rordr.Update(ctx, *preporder)
 	if err != nil {
 		return err
 	}
 
+	metrics.ObserveOrderTransition(consttypes.OS_BEING_PREPARED.String(), uporder.Status.String(), 1)
+	s.swebh.DispatchOrder(ctx, consttypes.WET_ORDER_STATUS_CHANGED, *uporder, consttypes.OS_BEING_PREPARED)
+
 	return nil
 }
 
-func (s *PartnerService) OrderPickedUp(oid uuid.UUID, uid uuid.UUID) error {
+func (s *PartnerService) OrderPickedUp(ctx context.Context, oid uuid.UUID, uid uuid.UUID) error {
 	// * get the user who confirms the order
-	user, err := s.ruser.GetByID(uid)
+	user, err := s.ruser.GetByID(ctx, uid)
 	if err != nil {
 		return err
 	}
 
 	// * get the corresponding order
-	order, err := s.rordr.GetByID(oid)
+	order, err := s.rordr.GetByID(ctx, oid)
+	if err != nil {
+		return err
+	}
+
+	// * only the partner who owns the order could update its status
+	err = s.sperm.Authorize(ctx, *user, consttypes.P_ORDER_UPDATE_STATUS, order)
 	if err != nil {
 		return err
 	}
@@ -282,25 +329,28 @@ func (s *PartnerService) OrderPickedUp(oid uuid.UUID, uid uuid.UUID) error {
 	preporder := order.OrderPickedUp(*user)
 
 	// * update the order in the database
-	_, err = s.rordr.Update(*preporder)
+	uporder, err := s.rordr.Update(ctx, *preporder)
 	if err != nil {
 		return err
 	}
 
+	metrics.ObserveOrderTransition(consttypes.OS_PREPARED.String(), uporder.Status.String(), 1)
+	s.swebh.DispatchOrder(ctx, consttypes.WET_ORDER_STATUS_CHANGED, *uporder, consttypes.OS_PREPARED)
+
 	return nil
 }
 
-func (s *PartnerService) ReadOwnMeal(uid uuid.UUID) ([]*responses.Meal, error) {
+func (s *PartnerService) ReadOwnMeal(ctx context.Context, uid uuid.UUID) ([]*responses.Meal, error) {
 	var (
 		mealreses []*responses.Meal
 	)
 
-	partner, err := s.rpart.GetByUserID(uid)
+	partner, err := s.rpart.GetByUserID(ctx, uid)
 	if err != nil {
 		return nil, err
 	}
 
-	mealsmodels, err := s.rmeal.ReadByPartnerID(partner.ID)
+	mealsmodels, err := s.rmeal.ReadByPartnerID(ctx, partner.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -317,8 +367,8 @@ func (s *PartnerService) ReadOwnMeal(uid uuid.UUID) ([]*responses.Meal, error) {
 	return mealreses, nil
 }
 
-func (s *PartnerService) FindOwnMeals(uid uuid.UUID, preq utpagination.Pagination) (*utpagination.Pagination, error) {
-	partner, err := s.rpart.GetByUserID(uid)
+func (s *PartnerService) FindOwnMeals(ctx context.Context, uid uuid.UUID, preq utpagination.Pagination) (*utpagination.Pagination, error) {
+	partner, err := s.rpart.GetByUserID(ctx, uid)
 	if err != nil {
 		return nil, err
 	}
@@ -326,7 +376,7 @@ func (s *PartnerService) FindOwnMeals(uid uuid.UUID, preq utpagination.Paginatio
 	// * assigning partner id to the filter
 	preq.Filter.Partner.ID = &partner.ID
 
-	meals, err := s.rmeal.FindAll(preq)
+	meals, err := s.rmeal.FindAll(ctx, preq)
 	if err != nil {
 		return nil, err
 	}
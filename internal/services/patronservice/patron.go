@@ -1,12 +1,14 @@
 package patronservice
 
 import (
+	"context"
 	"project-skbackend/internal/controllers/requests"
 	"project-skbackend/internal/controllers/responses"
 	"project-skbackend/internal/models"
 	"project-skbackend/internal/repositories/donationrepo"
 	"project-skbackend/internal/repositories/patronrepo"
 	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/metrics"
 	"project-skbackend/packages/utils/utpagination"
 
 	"github.com/google/uuid"
@@ -19,16 +21,16 @@ type (
 	}
 
 	IPatronService interface {
-		Create(req requests.CreatePatron) (*responses.Patron, error)
-		Read() ([]*models.Patron, error)
-		Update(id uuid.UUID, req requests.UpdatePatron) (*responses.Patron, error)
-		Delete(id uuid.UUID) error
-		FindAll(preq utpagination.Pagination) (*utpagination.Pagination, error)
-		GetByID(id uuid.UUID) (*responses.Patron, error)
-		GetByUserID(uid uuid.UUID) (*responses.Patron, error)
+		Create(ctx context.Context, req requests.CreatePatron) (*responses.Patron, error)
+		Read(ctx context.Context) ([]*models.Patron, error)
+		Update(ctx context.Context, id uuid.UUID, req requests.UpdatePatron) (*responses.Patron, error)
+		Delete(ctx context.Context, id uuid.UUID) error
+		FindAll(ctx context.Context, preq utpagination.Pagination) (*utpagination.Pagination, error)
+		GetByID(ctx context.Context, id uuid.UUID) (*responses.Patron, error)
+		GetByUserID(ctx context.Context, uid uuid.UUID) (*responses.Patron, error)
 
 		// * donation transaction
-		CreateDonation(req requests.CreateDonation, pid uuid.UUID) (*responses.Donation, error)
+		CreateDonation(ctx context.Context, req requests.CreateDonation, pid uuid.UUID) (*responses.Donation, error)
 	}
 )
 
@@ -42,7 +44,7 @@ func NewPatronService(
 	}
 }
 
-func (s *PatronService) Create(req requests.CreatePatron) (*responses.Patron, error) {
+func (s *PatronService) Create(ctx context.Context, req requests.CreatePatron) (*responses.Patron, error) {
 	user, err := req.User.ToModel(consttypes.UR_PATRON)
 	if err != nil {
 		return nil, err
@@ -53,7 +55,7 @@ func (s *PatronService) Create(req requests.CreatePatron) (*responses.Patron, er
 		return nil, err
 	}
 
-	patron, err = s.rpatr.Create(*patron)
+	patron, err = s.rpatr.Create(ctx, *patron)
 	if err != nil {
 		return nil, err
 	}
@@ -66,8 +68,8 @@ func (s *PatronService) Create(req requests.CreatePatron) (*responses.Patron, er
 	return pres, nil
 }
 
-func (s *PatronService) Read() ([]*models.Patron, error) {
-	patrons, err := s.rpatr.Read()
+func (s *PatronService) Read(ctx context.Context) ([]*models.Patron, error) {
+	patrons, err := s.rpatr.Read(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -75,8 +77,8 @@ func (s *PatronService) Read() ([]*models.Patron, error) {
 	return patrons, nil
 }
 
-func (s *PatronService) Update(id uuid.UUID, req requests.UpdatePatron) (*responses.Patron, error) {
-	patron, err := s.rpatr.GetByID(id)
+func (s *PatronService) Update(ctx context.Context, id uuid.UUID, req requests.UpdatePatron) (*responses.Patron, error) {
+	patron, err := s.rpatr.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -91,7 +93,7 @@ func (s *PatronService) Update(id uuid.UUID, req requests.UpdatePatron) (*respon
 		return nil, err
 	}
 
-	patron, err = s.rpatr.Update(*patron)
+	patron, err = s.rpatr.Update(ctx, *patron)
 	if err != nil {
 		return nil, err
 	}
@@ -104,13 +106,13 @@ func (s *PatronService) Update(id uuid.UUID, req requests.UpdatePatron) (*respon
 	return pres, nil
 }
 
-func (s *PatronService) Delete(id uuid.UUID) error {
-	patron, err := s.rpatr.GetByID(id)
+func (s *PatronService) Delete(ctx context.Context, id uuid.UUID) error {
+	patron, err := s.rpatr.GetByID(ctx, id)
 	if err != nil {
 		return err
 	}
 
-	err = s.rpatr.Delete(*patron)
+	err = s.rpatr.Delete(ctx, *patron)
 	if err != nil {
 		return err
 	}
@@ -118,8 +120,8 @@ func (s *PatronService) Delete(id uuid.UUID) error {
 	return nil
 }
 
-func (s *PatronService) FindAll(preq utpagination.Pagination) (*utpagination.Pagination, error) {
-	patrons, err := s.rpatr.FindAll(preq)
+func (s *PatronService) FindAll(ctx context.Context, preq utpagination.Pagination) (*utpagination.Pagination, error) {
+	patrons, err := s.rpatr.FindAll(ctx, preq)
 	if err != nil {
 		return nil, err
 	}
@@ -127,8 +129,8 @@ func (s *PatronService) FindAll(preq utpagination.Pagination) (*utpagination.Pag
 	return patrons, nil
 }
 
-func (s *PatronService) GetByID(id uuid.UUID) (*responses.Patron, error) {
-	patron, err := s.rpatr.GetByID(id)
+func (s *PatronService) GetByID(ctx context.Context, id uuid.UUID) (*responses.Patron, error) {
+	patron, err := s.rpatr.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -141,8 +143,8 @@ func (s *PatronService) GetByID(id uuid.UUID) (*responses.Patron, error) {
 	return pres, nil
 }
 
-func (s *PatronService) GetByUserID(uid uuid.UUID) (*responses.Patron, error) {
-	patron, err := s.rpatr.GetByUserID(uid)
+func (s *PatronService) GetByUserID(ctx context.Context, uid uuid.UUID) (*responses.Patron, error) {
+	patron, err := s.rpatr.GetByUserID(ctx, uid)
 	if err != nil {
 		return nil, err
 	}
@@ -158,8 +160,8 @@ func (s *PatronService) GetByUserID(uid uuid.UUID) (*responses.Patron, error) {
 // ! -------------------------------------------------------------------------- ! //
 // !                            donation transaction                            ! //
 // ! -------------------------------------------------------------------------- ! //
-func (s *PatronService) CreateDonation(req requests.CreateDonation, pid uuid.UUID) (*responses.Donation, error) {
-	patron, err := s.rpatr.GetByID(pid)
+func (s *PatronService) CreateDonation(ctx context.Context, req requests.CreateDonation, pid uuid.UUID) (*responses.Donation, error) {
+	patron, err := s.rpatr.GetByID(ctx, pid)
 	if err != nil {
 		return nil, err
 	}
@@ -169,11 +171,13 @@ func (s *PatronService) CreateDonation(req requests.CreateDonation, pid uuid.UUI
 		return nil, err
 	}
 
-	dona, err = s.rdona.Create(*dona)
+	dona, err = s.rdona.Create(ctx, *dona)
 	if err != nil {
 		return nil, err
 	}
 
+	metrics.ObserveDonation(dona.Status.String())
+
 	donares, err := dona.ToResponse()
 	if err != nil {
 		return nil, err
@@ -0,0 +1,276 @@
+package permissionservice
+
+import (
+	"context"
+	"errors"
+	"project-skbackend/internal/controllers/requests"
+	"project-skbackend/internal/controllers/responses"
+	"project-skbackend/internal/models"
+	"project-skbackend/internal/repositories/rolepermissionrepo"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/utils/utlogger"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// * bumped on every change of the mapping, every replica compares
+	// * it with the version its cache was loaded at
+	versionKey = "role_permissions_version"
+)
+
+type (
+	PermissionService struct {
+		rdb   *redis.Client
+		rrlpm rolepermissionrepo.IRolePermissionRepository
+
+		mu      sync.RWMutex
+		cache   map[consttypes.UserRole]map[consttypes.Permission]bool
+		version int64
+	}
+
+	IPermissionService interface {
+		HasPermission(ctx context.Context, role consttypes.UserRole, perm consttypes.Permission) (bool, error)
+		Authorize(ctx context.Context, user models.User, perm consttypes.Permission, resource any) error
+		IsOwner(ctx context.Context, uid uuid.UUID, role consttypes.UserRole, resource any) bool
+
+		Read(ctx context.Context) ([]*responses.RolePermissions, error)
+		GetByRole(ctx context.Context, role consttypes.UserRole) (*responses.RolePermissions, error)
+		UpdateByRole(ctx context.Context, role consttypes.UserRole, req requests.UpdateRolePermissions) (*responses.RolePermissions, error)
+	}
+)
+
+func NewPermissionService(
+	rdb *redis.Client,
+	rrlpm rolepermissionrepo.IRolePermissionRepository,
+) *PermissionService {
+	return &PermissionService{
+		rdb:   rdb,
+		rrlpm: rrlpm,
+	}
+}
+
+// * load every role-to-permission mapping into memory, the cache is
+// * reloaded once the shared version moves, so a change made through
+// * any replica reaches every other one on its next check
+func (s *PermissionService) load(ctx context.Context) (map[consttypes.UserRole]map[consttypes.Permission]bool, error) {
+	version, err := s.rdb.Get(ctx, versionKey).Int64()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		utlogger.ErrorContext(ctx, err)
+		return nil, consttypes.ErrFailedToReadPermissions.Wrap(err)
+	}
+
+	s.mu.RLock()
+	cache, cached := s.cache, s.version
+	s.mu.RUnlock()
+
+	if cache != nil && cached == version {
+		return cache, nil
+	}
+
+	rps, err := s.rrlpm.Read(ctx)
+	if err != nil {
+		return nil, consttypes.ErrFailedToReadPermissions.Wrap(err)
+	}
+
+	cache = make(map[consttypes.UserRole]map[consttypes.Permission]bool)
+	for _, rp := range rps {
+		if cache[rp.Role] == nil {
+			cache[rp.Role] = make(map[consttypes.Permission]bool)
+		}
+
+		cache[rp.Role][rp.Permission] = true
+	}
+
+	s.mu.Lock()
+	s.cache = cache
+	s.version = version
+	s.mu.Unlock()
+
+	return cache, nil
+}
+
+func (s *PermissionService) invalidate(ctx context.Context) error {
+	s.mu.Lock()
+	s.cache = nil
+	s.mu.Unlock()
+
+	err := s.rdb.Incr(ctx, versionKey).Err()
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return consttypes.ErrFailedToUpdatePermission.Wrap(err)
+	}
+
+	return nil
+}
+
+func (s *PermissionService) HasPermission(ctx context.Context, role consttypes.UserRole, perm consttypes.Permission) (bool, error) {
+	cache, err := s.load(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return cache[role][perm], nil
+}
+
+func (s *PermissionService) Authorize(ctx context.Context, user models.User, perm consttypes.Permission, resource any) error {
+	ok, err := s.HasPermission(ctx, user.Role, perm)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return consttypes.ErrPermissionDenied
+	}
+
+	if resource != nil && !s.IsOwner(ctx, user.ID, user.Role, resource) {
+		return consttypes.ErrNotResourceOwner
+	}
+
+	return nil
+}
+
+// * resource-ownership rules, admins own every resource and the
+// * other roles only own the resources linked to their own user
+func (s *PermissionService) IsOwner(ctx context.Context, uid uuid.UUID, role consttypes.UserRole, resource any) bool {
+	if role == consttypes.UR_ADMIN {
+		return true
+	}
+
+	switch res := resource.(type) {
+	case *models.Order:
+		return s.isOrderOwner(uid, role, *res)
+	case models.Order:
+		return s.isOrderOwner(uid, role, res)
+	case *models.Member:
+		return s.isMemberOwner(uid, role, *res)
+	case models.Member:
+		return s.isMemberOwner(uid, role, res)
+	case *models.Cart:
+		return s.isMemberOwner(uid, role, res.Member)
+	case models.Cart:
+		return s.isMemberOwner(uid, role, res.Member)
+	case *models.Partner:
+		return role == consttypes.UR_PARTNER && res.UserID == uid
+	case models.Partner:
+		return role == consttypes.UR_PARTNER && res.UserID == uid
+	default:
+		return false
+	}
+}
+
+func (s *PermissionService) isOrderOwner(uid uuid.UUID, role consttypes.UserRole, order models.Order) bool {
+	switch role {
+	case consttypes.UR_PARTNER:
+		return order.Partner.UserID == uid
+	case consttypes.UR_MEMBER, consttypes.UR_CAREGIVER, consttypes.UR_ORGANIZATION:
+		return s.isMemberOwner(uid, role, order.Member)
+	default:
+		return false
+	}
+}
+
+func (s *PermissionService) isMemberOwner(uid uuid.UUID, role consttypes.UserRole, member models.Member) bool {
+	switch role {
+	case consttypes.UR_MEMBER:
+		return member.UserID == uid
+	case consttypes.UR_CAREGIVER:
+		for _, mc := range member.Caregivers {
+			if mc.Caregiver.UserID == uid {
+				return true
+			}
+		}
+
+		return false
+	case consttypes.UR_ORGANIZATION:
+		return member.Organization != nil && member.Organization.UserID == uid
+	default:
+		return false
+	}
+}
+
+func (s *PermissionService) Read(ctx context.Context) ([]*responses.RolePermissions, error) {
+	var (
+		rpreses []*responses.RolePermissions
+		index   = make(map[consttypes.UserRole]*responses.RolePermissions)
+	)
+
+	rps, err := s.rrlpm.Read(ctx)
+	if err != nil {
+		return nil, consttypes.ErrFailedToReadPermissions.Wrap(err)
+	}
+
+	for _, rp := range rps {
+		rpres, ok := index[rp.Role]
+		if !ok {
+			rpres = &responses.RolePermissions{Role: rp.Role}
+			index[rp.Role] = rpres
+			rpreses = append(rpreses, rpres)
+		}
+
+		rpres.Permissions = append(rpres.Permissions, rp.Permission)
+	}
+
+	return rpreses, nil
+}
+
+func (s *PermissionService) GetByRole(ctx context.Context, role consttypes.UserRole) (*responses.RolePermissions, error) {
+	rps, err := s.rrlpm.FindByRole(ctx, role)
+	if err != nil {
+		return nil, consttypes.ErrFailedToReadPermissions.Wrap(err)
+	}
+
+	return toRolePermissions(role, rps), nil
+}
+
+func (s *PermissionService) UpdateByRole(ctx context.Context, role consttypes.UserRole, req requests.UpdateRolePermissions) (*responses.RolePermissions, error) {
+	var (
+		rps  []models.RolePermission
+		seen = make(map[consttypes.Permission]bool)
+	)
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	for _, perm := range req.Permissions {
+		if seen[perm] {
+			continue
+		}
+
+		seen[perm] = true
+		rps = append(rps, *models.NewRolePermission(role, perm))
+	}
+
+	for _, perm := range consttypes.ProtectedRolePermissions()[role] {
+		if !seen[perm] {
+			return nil, consttypes.ErrProtectedPermission(role, perm)
+		}
+	}
+
+	rpnews, err := s.rrlpm.ReplaceByRole(ctx, role, rps)
+	if err != nil {
+		return nil, consttypes.ErrFailedToUpdatePermission.Wrap(err)
+	}
+
+	if err := s.invalidate(ctx); err != nil {
+		return nil, err
+	}
+
+	return toRolePermissions(role, rpnews), nil
+}
+
+func toRolePermissions(role consttypes.UserRole, rps []*models.RolePermission) *responses.RolePermissions {
+	rpres := responses.RolePermissions{
+		Role:        role,
+		Permissions: []consttypes.Permission{},
+	}
+
+	for _, rp := range rps {
+		rpres.Permissions = append(rpres.Permissions, rp.Permission)
+	}
+
+	return &rpres
+}
@@ -0,0 +1,573 @@
+package privacyservice
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"project-skbackend/configs"
+	"project-skbackend/internal/controllers/requests"
+	"project-skbackend/internal/controllers/responses"
+	"project-skbackend/internal/models"
+	"project-skbackend/internal/repositories/cartrepo"
+	"project-skbackend/internal/repositories/dataerasurerepo"
+	"project-skbackend/internal/repositories/dataexportrepo"
+	"project-skbackend/internal/repositories/donationrepo"
+	"project-skbackend/internal/repositories/imagerepo"
+	"project-skbackend/internal/repositories/membercaregiverrepo"
+	"project-skbackend/internal/repositories/memberhealthhistoryrepo"
+	"project-skbackend/internal/repositories/orderrepo"
+	"project-skbackend/internal/repositories/securityeventrepo"
+	"project-skbackend/internal/repositories/userrepo"
+	"project-skbackend/internal/services/securityservice"
+	"project-skbackend/internal/services/sessionservice"
+	"project-skbackend/internal/services/twofactorservice"
+	"project-skbackend/internal/services/userservice"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/utils/utlogger"
+	"project-skbackend/packages/utils/utrole"
+	"project-skbackend/packages/utils/utstring"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/minio/minio-go/v7"
+	"gorm.io/gorm"
+)
+
+const (
+	// * number of requests processed by the job on every run
+	dataRequestBatchSize = 10
+
+	// * a request processing for longer was left by a worker that stopped
+	dataRequestClaimTimeout = 30 * time.Minute
+)
+
+type (
+	PrivacyService struct {
+		minio minio.Client
+
+		// * repository
+		rdexp dataexportrepo.IDataExportRepository
+		rders dataerasurerepo.IDataErasureRepository
+		ruser userrepo.IUserRepository
+		rmhh  memberhealthhistoryrepo.IMemberHealthHistoryRepository
+		rordr orderrepo.IOrderRepository
+		rcart cartrepo.ICartRepository
+		rdona donationrepo.IDonationRepository
+		rmcg  membercaregiverrepo.IMemberCaregiverRepository
+		rimg  imagerepo.IImageRepo
+		rsev  securityeventrepo.ISecurityEventRepository
+
+		// * service
+		suser userservice.IUserService
+		ssess sessionservice.ISessionService
+		ssecu securityservice.ISecurityService
+		stfa  twofactorservice.ITwoFactorService
+
+		mb      string
+		explife int
+	}
+
+	IPrivacyService interface {
+		// * data exports
+		CreateDataExport(ctx context.Context, uid uuid.UUID) (*responses.DataExport, error)
+		FindDataExportsByUserID(ctx context.Context, uid uuid.UUID) ([]*responses.DataExport, error)
+		GetDataExportFile(ctx context.Context, uid uuid.UUID, deid uuid.UUID) (io.ReadCloser, int64, error)
+		ProcessDataExports(ctx context.Context) error
+
+		// * data erasures
+		CreateDataErasure(ctx context.Context, uid uuid.UUID, requestedbyid uuid.UUID) (*responses.DataErasure, error)
+		CreateOwnDataErasure(ctx context.Context, uid uuid.UUID, req requests.CreateDataErasure) (*responses.DataErasure, error)
+		FindDataErasuresByUserID(ctx context.Context, uid uuid.UUID) ([]*responses.DataErasure, error)
+		ProcessDataErasures(ctx context.Context) error
+	}
+)
+
+func NewPrivacyService(
+	cfg *configs.Config,
+	minio minio.Client,
+	// * repository
+	rdexp dataexportrepo.IDataExportRepository,
+	rders dataerasurerepo.IDataErasureRepository,
+	ruser userrepo.IUserRepository,
+	rmhh memberhealthhistoryrepo.IMemberHealthHistoryRepository,
+	rordr orderrepo.IOrderRepository,
+	rcart cartrepo.ICartRepository,
+	rdona donationrepo.IDonationRepository,
+	rmcg membercaregiverrepo.IMemberCaregiverRepository,
+	rimg imagerepo.IImageRepo,
+	rsev securityeventrepo.ISecurityEventRepository,
+	// * service
+	suser userservice.IUserService,
+	ssess sessionservice.ISessionService,
+	ssecu securityservice.ISecurityService,
+	stfa twofactorservice.ITwoFactorService,
+) *PrivacyService {
+	return &PrivacyService{
+		minio: minio,
+
+		// * repository
+		rdexp: rdexp,
+		rders: rders,
+		ruser: ruser,
+		rmhh:  rmhh,
+		rordr: rordr,
+		rcart: rcart,
+		rdona: rdona,
+		rmcg:  rmcg,
+		rimg:  rimg,
+		rsev:  rsev,
+
+		// * service
+		suser: suser,
+		ssess: ssess,
+		ssecu: ssecu,
+		stfa:  stfa,
+
+		mb:      cfg.Minio.Bucket,
+		explife: cfg.APIDataExport.Life,
+	}
+}
+
+// ! ------------------------------ data exports ------------------------------ ! //
+func (s *PrivacyService) CreateDataExport(ctx context.Context, uid uuid.UUID) (*responses.DataExport, error) {
+	user, err := s.ruser.GetByID(ctx, uid)
+	if err != nil {
+		return nil, consttypes.ErrUserNotFound.Wrap(err)
+	}
+
+	// * only one export can be prepared at a time
+	_, err = s.rdexp.GetActiveByUserID(ctx, uid)
+	if err == nil {
+		return nil, consttypes.ErrDataExportAlreadyRequested
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, consttypes.ErrFailedToReadDataExports
+	}
+
+	de, err := s.rdexp.Create(ctx, *models.NewDataExport(*user))
+	if err != nil {
+		return nil, consttypes.ErrFailedToCreateDataExport.Wrap(err)
+	}
+
+	deres, err := de.ToResponse()
+	if err != nil {
+		return nil, consttypes.ErrConvertFailed.Wrap(err)
+	}
+
+	return deres, nil
+}
+
+func (s *PrivacyService) FindDataExportsByUserID(ctx context.Context, uid uuid.UUID) ([]*responses.DataExport, error) {
+	var (
+		dereses []*responses.DataExport
+	)
+
+	des, err := s.rdexp.FindByUserID(ctx, uid)
+	if err != nil {
+		return nil, consttypes.ErrFailedToReadDataExports.Wrap(err)
+	}
+
+	for _, de := range des {
+		deres, err := de.ToResponse()
+		if err != nil {
+			return nil, consttypes.ErrConvertFailed.Wrap(err)
+		}
+
+		dereses = append(dereses, deres)
+	}
+
+	return dereses, nil
+}
+
+func (s *PrivacyService) GetDataExportFile(ctx context.Context, uid uuid.UUID, deid uuid.UUID) (io.ReadCloser, int64, error) {
+	de, err := s.rdexp.GetByID(ctx, deid)
+	if err != nil || de.UserID != uid {
+		return nil, 0, consttypes.ErrDataExportNotFound
+	}
+
+	if de.Status == consttypes.DRS_EXPIRED || de.IsExpired() {
+		return nil, 0, consttypes.ErrDataExportExpired
+	}
+
+	if de.Status != consttypes.DRS_COMPLETED {
+		return nil, 0, consttypes.ErrDataExportNotReady
+	}
+
+	obj, err := s.minio.GetObject(ctx, s.mb, de.ObjectName, minio.GetObjectOptions{})
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, 0, consttypes.ErrDataExportNotFound.Wrap(err)
+	}
+
+	return obj, de.Size, nil
+}
+
+// * build the pending exports and remove the expired ones,
+// * called periodically by the cron service
+func (s *PrivacyService) ProcessDataExports(ctx context.Context) error {
+	var (
+		errs []error
+	)
+
+	des, err := s.rdexp.ClaimPending(ctx, dataRequestBatchSize, dataRequestClaimTimeout)
+	if err != nil {
+		return consttypes.ErrFailedToReadDataExports.Wrap(err)
+	}
+
+	for _, de := range des {
+		if err := s.processDataExport(ctx, *de); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	expdes, err := s.rdexp.FindExpired(ctx, dataRequestBatchSize)
+	if err != nil {
+		return consttypes.ErrFailedToReadDataExports.Wrap(err)
+	}
+
+	for _, de := range expdes {
+		if err := s.minio.RemoveObject(ctx, s.mb, de.ObjectName, minio.RemoveObjectOptions{}); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		de.Status = consttypes.DRS_EXPIRED
+		de.ObjectName = ""
+		if _, err := s.rdexp.Update(ctx, *de); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// * the export is already claimed as processing
+func (s *PrivacyService) processDataExport(ctx context.Context, de models.DataExport) error {
+	data, err := s.buildDataExport(ctx, de.UserID)
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+
+		de.Status = consttypes.DRS_FAILED
+		de.Error = consttypes.ErrFailedToBuildDataExport.Error()
+		_, uerr := s.rdexp.Update(ctx, de)
+
+		return errors.Join(err, uerr)
+	}
+
+	objname := fmt.Sprintf("data-exports/%s/%s.zip", de.UserID, de.ID)
+	info, err := s.minio.PutObject(ctx, s.mb, objname, bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{ContentType: "application/zip"})
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+
+		de.Status = consttypes.DRS_FAILED
+		de.Error = consttypes.ErrFailedToBuildDataExport.Error()
+		_, uerr := s.rdexp.Update(ctx, de)
+
+		return errors.Join(err, uerr)
+	}
+
+	now := consttypes.TimeNow()
+	expiresat := now.Add(time.Duration(s.explife) * time.Hour)
+
+	de.Status = consttypes.DRS_COMPLETED
+	de.ObjectName = objname
+	de.Size = info.Size
+	de.CompletedAt = &now
+	de.ExpiresAt = &expiresat
+
+	_, err = s.rdexp.Update(ctx, de)
+	return err
+}
+
+// * bundle every data of the user into a zip file, every
+// * entry is a json file except the uploaded images
+func (s *PrivacyService) buildDataExport(ctx context.Context, uid uuid.UUID) ([]byte, error) {
+	var (
+		buf        = new(bytes.Buffer)
+		files      = map[string]any{}
+		imagepaths []string
+	)
+
+	user, err := s.ruser.GetByID(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	ures, err := user.ToResponse()
+	if err != nil {
+		return nil, err
+	}
+	files["profile.json"] = ures
+
+	if user.Image != nil {
+		imagepaths = append(imagepaths, user.Image.Image.Path)
+	}
+
+	roleres, err := s.suser.GetRoleDataByUserID(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	files["role.json"] = roleres
+
+	ses, err := s.rsev.FindAllByUser(ctx, uid, user.Email)
+	if err != nil {
+		return nil, err
+	}
+	files["security_events.json"] = ses
+
+	rid, rtype, ok := utrole.RoleTranslate(*roleres)
+	if ok {
+		switch rtype {
+		case consttypes.UR_MEMBER:
+			mhhs, err := s.rmhh.FindAllByMemberID(ctx, rid)
+			if err != nil {
+				return nil, err
+			}
+			files["health_histories.json"] = mhhs
+
+			orders, err := s.rordr.FindByMemberID(ctx, rid)
+			if err != nil {
+				return nil, err
+			}
+			files["orders.json"] = orders
+
+			carts, err := s.rcart.FindByMemberID(ctx, rid)
+			if err != nil {
+				return nil, err
+			}
+			files["carts.json"] = carts
+		case consttypes.UR_CAREGIVER:
+			mcs, err := s.rmcg.FindByCaregiverID(ctx, rid)
+			if err != nil {
+				return nil, err
+			}
+			files["caregiver_links.json"] = mcs
+		case consttypes.UR_PARTNER:
+			orders, err := s.rordr.FindByPartnerID(ctx, rid)
+			if err != nil {
+				return nil, err
+			}
+			files["orders.json"] = orders
+		case consttypes.UR_PATRON:
+			donations, err := s.rdona.FindByPatronID(ctx, rid)
+			if err != nil {
+				return nil, err
+			}
+			files["donations.json"] = donations
+
+			for _, donation := range donations {
+				if donation.Proof == nil {
+					continue
+				}
+
+				image, err := s.rimg.GetByID(ctx, donation.Proof.ImageID)
+				if err != nil {
+					return nil, err
+				}
+
+				imagepaths = append(imagepaths, image.Path)
+			}
+		}
+	}
+
+	zw := zip.NewWriter(buf)
+
+	for name, data := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			return nil, err
+		}
+
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "\t")
+		if err := enc.Encode(data); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, imagepath := range imagepaths {
+		objname := s.getObjectName(imagepath)
+
+		obj, err := s.minio.GetObject(ctx, s.mb, objname, minio.GetObjectOptions{})
+		if err != nil {
+			return nil, err
+		}
+
+		w, err := zw.Create(path.Join("images", path.Base(objname)))
+		if err != nil {
+			obj.Close()
+			return nil, err
+		}
+
+		_, err = io.Copy(w, obj)
+		obj.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// * images are saved with their full url, the object
+// * name is everything after the bucket name
+func (s *PrivacyService) getObjectName(imagepath string) string {
+	parts := strings.SplitN(imagepath, fmt.Sprintf("/%s/", s.mb), 2)
+	if len(parts) != 2 {
+		return imagepath
+	}
+
+	return parts[1]
+}
+
+// ! ------------------------------ data erasures ----------------------------- ! //
+func (s *PrivacyService) CreateDataErasure(ctx context.Context, uid uuid.UUID, requestedbyid uuid.UUID) (*responses.DataErasure, error) {
+	// * a soft deleted user still has personal data to erase
+	user, err := s.ruser.GetByIDUnscoped(ctx, uid)
+	if err != nil {
+		return nil, consttypes.ErrUserNotFound.Wrap(err)
+	}
+
+	_, err = s.rders.GetActiveByUserID(ctx, uid)
+	if err == nil {
+		return nil, consttypes.ErrDataErasureAlreadyRequested
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, consttypes.ErrFailedToReadDataErasures
+	}
+
+	de, err := s.rders.Create(ctx, *models.NewDataErasure(*user, requestedbyid))
+	if err != nil {
+		return nil, consttypes.ErrFailedToCreateDataErasure.Wrap(err)
+	}
+
+	deres, err := de.ToResponse()
+	if err != nil {
+		return nil, consttypes.ErrConvertFailed.Wrap(err)
+	}
+
+	return deres, nil
+}
+
+func (s *PrivacyService) CreateOwnDataErasure(ctx context.Context, uid uuid.UUID, req requests.CreateDataErasure) (*responses.DataErasure, error) {
+	user, err := s.ruser.GetByID(ctx, uid)
+	if err != nil {
+		return nil, consttypes.ErrUserNotFound.Wrap(err)
+	}
+
+	if ok := utstring.CheckPasswordHash(req.Password, user.Password); !ok {
+		return nil, consttypes.ErrInvalidEmailOrPassword
+	}
+
+	return s.CreateDataErasure(ctx, uid, uid)
+}
+
+func (s *PrivacyService) FindDataErasuresByUserID(ctx context.Context, uid uuid.UUID) ([]*responses.DataErasure, error) {
+	var (
+		dereses []*responses.DataErasure
+	)
+
+	des, err := s.rders.FindByUserID(ctx, uid)
+	if err != nil {
+		return nil, consttypes.ErrFailedToReadDataErasures.Wrap(err)
+	}
+
+	for _, de := range des {
+		deres, err := de.ToResponse()
+		if err != nil {
+			return nil, consttypes.ErrConvertFailed.Wrap(err)
+		}
+
+		dereses = append(dereses, deres)
+	}
+
+	return dereses, nil
+}
+
+// * erase the data of the pending requests, called
+// * periodically by the cron service
+func (s *PrivacyService) ProcessDataErasures(ctx context.Context) error {
+	var (
+		errs []error
+	)
+
+	des, err := s.rders.ClaimPending(ctx, dataRequestBatchSize, dataRequestClaimTimeout)
+	if err != nil {
+		return consttypes.ErrFailedToReadDataErasures.Wrap(err)
+	}
+
+	for _, de := range des {
+		if err := s.processDataErasure(ctx, *de); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// * the erasure is already claimed as processing
+func (s *PrivacyService) processDataErasure(ctx context.Context, de models.DataErasure) error {
+	// * the email is anonymized by the erasure, but the throttles
+	// * in redis are still counted by the original one
+	user, err := s.ruser.GetByIDUnscoped(ctx, de.UserID)
+	if err != nil {
+		de.Status = consttypes.DRS_FAILED
+		de.Error = consttypes.ErrFailedToEraseData.Error()
+		_, uerr := s.rders.Update(ctx, de)
+
+		return errors.Join(err, uerr)
+	}
+
+	imagepaths, objnames, err := s.rders.Erase(ctx, de.UserID)
+	if err != nil {
+		de.Status = consttypes.DRS_FAILED
+		de.Error = consttypes.ErrFailedToEraseData.Error()
+		_, uerr := s.rders.Update(ctx, de)
+
+		return errors.Join(err, uerr)
+	}
+
+	// * the erased user cannot sign in again, but the issued
+	// * tokens are still valid until their sessions are revoked
+	if err := s.ssess.RevokeAll(ctx, de.UserID, nil); err != nil {
+		utlogger.ErrorContext(ctx, err)
+	}
+
+	// * the lockouts, throttles and two factor challenges of the user
+	// * expire by themselves, a failure is only logged like the sessions
+	if err := s.ssecu.Forget(ctx, de.UserID, user.Email); err != nil {
+		utlogger.ErrorContext(ctx, err)
+	}
+
+	if err := s.stfa.Forget(ctx, de.UserID); err != nil {
+		utlogger.ErrorContext(ctx, err)
+	}
+
+	// * the database is already erased, files that failed to be
+	// * removed are only logged so they can be removed manually
+	for _, imagepath := range imagepaths {
+		objnames = append(objnames, s.getObjectName(imagepath))
+	}
+
+	for _, objname := range objnames {
+		if err := s.minio.RemoveObject(ctx, s.mb, objname, minio.RemoveObjectOptions{}); err != nil {
+			utlogger.ErrorContext(ctx, err)
+		}
+	}
+
+	now := consttypes.TimeNow()
+
+	de.Status = consttypes.DRS_COMPLETED
+	de.CompletedAt = &now
+
+	_, err = s.rders.Update(ctx, de)
+	return err
+}
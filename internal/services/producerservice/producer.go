@@ -5,6 +5,10 @@ import (
 	"encoding/json"
 	"project-skbackend/configs"
 	"project-skbackend/internal/controllers/requests"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/metrics"
+	"project-skbackend/packages/tracing"
+	"project-skbackend/packages/utils/utlogger"
 
 	amqp "github.com/rabbitmq/amqp091-go"
 )
@@ -17,10 +21,20 @@ type (
 	}
 
 	IProducerService interface {
-		PublishEmail(message requests.SendEmail) error
+		PublishEmail(ctx context.Context, message requests.SendEmail) error
+		PublishWebhook(ctx context.Context, message requests.DeliverWebhook) error
+		PublishDeadLetter(ctx context.Context, d amqp.Delivery, qname string, reason error) error
+		ReplayDeadLetter(qname string, limit int) (int, error)
 	}
 )
 
+const (
+	// * the headers a dead letter keeps of where it was published to
+	hexchange   = "x-original-exchange"
+	hroutingkey = "x-original-routing-key"
+	herror      = "x-error"
+)
+
 func NewProducerService(
 	ch *amqp.Channel,
 	cfg *configs.Config,
@@ -33,25 +47,171 @@ func NewProducerService(
 	}
 }
 
-func (s *ProducerService) PublishEmail(message requests.SendEmail) error {
+func (s *ProducerService) PublishEmail(ctx context.Context, message requests.SendEmail) error {
 	jsonData, err := json.Marshal(message)
 	if err != nil {
 		return err
 	}
 
+	// * the consumer continues the trace and the request id of the caller
+	// * from the headers
+	headers := amqp.Table{}
+	if rid := utlogger.RequestID(ctx); rid != "" {
+		headers[utlogger.HeaderRequestID] = rid
+	}
+	ctx, span := tracing.StartPublish(ctx, s.cfg.Queue.QueueMail.ExchangeName, s.cfg.Queue.QueueMail.BindingKey, headers)
+	defer func() { tracing.End(span, err) }()
+
 	err = s.ch.PublishWithContext(
-		s.ctx,
+		ctx,
 		s.cfg.Queue.QueueMail.ExchangeName, // exchange
 		s.cfg.Queue.QueueMail.BindingKey,   // routing key
 		false,                              // mandatory
 		false,                              // immediate
 		amqp.Publishing{
+			Headers:     headers,
 			ContentType: "text/plain",
 			Body:        jsonData,
 		})
+	metrics.ObservePublish(s.cfg.Queue.QueueMail.ExchangeName, s.cfg.Queue.QueueMail.BindingKey, err)
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+func (s *ProducerService) PublishWebhook(ctx context.Context, message requests.DeliverWebhook) error {
+	jsonData, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	// * the consumer continues the trace and the request id of the caller
+	// * from the headers
+	headers := amqp.Table{}
+	if rid := utlogger.RequestID(ctx); rid != "" {
+		headers[utlogger.HeaderRequestID] = rid
+	}
+	ctx, span := tracing.StartPublish(ctx, s.cfg.Queue.QueueWebhook.ExchangeName, s.cfg.Queue.QueueWebhook.BindingKey, headers)
+	defer func() { tracing.End(span, err) }()
+
+	err = s.ch.PublishWithContext(
+		ctx,
+		s.cfg.Queue.QueueWebhook.ExchangeName, // exchange
+		s.cfg.Queue.QueueWebhook.BindingKey,   // routing key
+		false,                                 // mandatory
+		false,                                 // immediate
+		amqp.Publishing{
+			Headers:      headers,
+			ContentType:  "application/json",
+			DeliveryMode: amqp.Persistent,
+			Body:         jsonData,
+		})
+	metrics.ObservePublish(s.cfg.Queue.QueueWebhook.ExchangeName, s.cfg.Queue.QueueWebhook.BindingKey, err)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// * the failed message is published to the dead letter queue of qname
+// * with where it came from, so the replay can publish it back there
+func (s *ProducerService) PublishDeadLetter(ctx context.Context, d amqp.Delivery, qname string, reason error) error {
+	headers := amqp.Table{}
+	for key, value := range d.Headers {
+		headers[key] = value
+	}
+
+	// * the trace headers are kept too, a replay continues the same trace
+	headers[hexchange] = d.Exchange
+	headers[hroutingkey] = d.RoutingKey
+	headers[herror] = reason.Error()
+
+	err := s.ch.PublishWithContext(
+		ctx,
+		"", // exchange
+		s.cfg.Queue.QueueDeadLetter.QueueName(qname), // routing key
+		false, // mandatory
+		false, // immediate
+		amqp.Publishing{
+			Headers:      headers,
+			ContentType:  d.ContentType,
+			DeliveryMode: amqp.Persistent,
+			Timestamp:    consttypes.TimeNow(),
+			Body:         d.Body,
+		})
+	metrics.ObservePublish("", s.cfg.Queue.QueueDeadLetter.QueueName(qname), err)
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return err
+	}
+
+	return nil
+}
+
+// * moves up to limit messages of the dead letter queue of qname back to
+// * where they were published, all of them when the limit is zero. each
+// * message is only acked after it is published, so none of them is lost
+func (s *ProducerService) ReplayDeadLetter(qname string, limit int) (int, error) {
+	var (
+		dlqname  = s.cfg.Queue.QueueDeadLetter.QueueName(qname)
+		replayed int
+	)
+
+	for limit == 0 || replayed < limit {
+		d, ok, err := s.ch.Get(dlqname, false)
+		if err != nil {
+			utlogger.Error(err)
+			return replayed, err
+		}
+
+		if !ok {
+			break
+		}
+
+		xname, _ := d.Headers[hexchange].(string)
+		rkey, _ := d.Headers[hroutingkey].(string)
+		if rkey == "" {
+			rkey = qname
+		}
+
+		headers := amqp.Table{}
+		for key, value := range d.Headers {
+			headers[key] = value
+		}
+
+		delete(headers, hexchange)
+		delete(headers, hroutingkey)
+		delete(headers, herror)
+
+		err = s.ch.PublishWithContext(
+			s.ctx,
+			xname, // exchange
+			rkey,  // routing key
+			false, // mandatory
+			false, // immediate
+			amqp.Publishing{
+				Headers:      headers,
+				ContentType:  d.ContentType,
+				DeliveryMode: amqp.Persistent,
+				Body:         d.Body,
+			})
+		metrics.ObservePublish(xname, rkey, err)
+		if err != nil {
+			utlogger.Error(err)
+			_ = d.Nack(false, true)
+			return replayed, err
+		}
+
+		if err := d.Ack(false); err != nil {
+			utlogger.Error(err)
+			return replayed, err
+		}
+
+		replayed++
+	}
+
+	return replayed, nil
+}
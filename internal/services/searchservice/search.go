@@ -0,0 +1,158 @@
+package searchservice
+
+import (
+	"context"
+	"math"
+	"project-skbackend/configs"
+	"project-skbackend/internal/controllers/requests"
+	"project-skbackend/internal/controllers/responses"
+	"project-skbackend/internal/repositories/mealrepo"
+	"project-skbackend/internal/repositories/searchrepo"
+	"project-skbackend/packages/consttypes"
+
+	"github.com/google/uuid"
+)
+
+type (
+	SearchService struct {
+		cfg *configs.Config
+
+		// * repository
+		rsrch searchrepo.ISearchRepository
+		rmeal mealrepo.IMealRepository
+	}
+
+	ISearchService interface {
+		SearchMeals(ctx context.Context, req requests.MealSearch) (*responses.MealSearch, error)
+		IndexMeals(ctx context.Context) (int64, error)
+	}
+)
+
+func NewSearchService(
+	cfg *configs.Config,
+	// * repository
+	rsrch searchrepo.ISearchRepository,
+	rmeal mealrepo.IMealRepository,
+) *SearchService {
+	return &SearchService{
+		cfg: cfg,
+
+		// * repository
+		rsrch: rsrch,
+		rmeal: rmeal,
+	}
+}
+
+func (s *SearchService) toMealQuery(req requests.MealSearch) (searchrepo.MealQuery, error) {
+	var (
+		q   = searchrepo.MealQuery{Text: req.Query}
+		err error
+	)
+
+	if q.CategoryIDs, err = req.ParseIDs(req.CategoryIDs); err != nil {
+		return q, err
+	}
+
+	if q.PartnerIDs, err = req.ParseIDs(req.PartnerIDs); err != nil {
+		return q, err
+	}
+
+	if q.AllergyFree, err = req.ParseIDs(req.AllergyFree); err != nil {
+		return q, err
+	}
+
+	q.Limit = req.Limit
+	if q.Limit == 0 {
+		q.Limit = s.cfg.Search.DefaultLimit
+	}
+
+	page := req.Page
+	if page == 0 {
+		page = 1
+	}
+
+	q.Offset = (page - 1) * q.Limit
+
+	return q, nil
+}
+
+// * the hits only hold the ids, the meals are read afterwards and
+// * put back in the order of the hits
+func (s *SearchService) SearchMeals(ctx context.Context, req requests.MealSearch) (*responses.MealSearch, error) {
+	q, err := s.toMealQuery(req)
+	if err != nil {
+		return nil, err
+	}
+
+	hits, totdata, err := s.rsrch.SearchMeals(ctx, q)
+	if err != nil {
+		return nil, consttypes.ErrFailedToSearchMeals.Wrap(err)
+	}
+
+	facets, err := s.rsrch.MealFacets(ctx, q)
+	if err != nil {
+		return nil, consttypes.ErrFailedToSearchMeals.Wrap(err)
+	}
+
+	srres := responses.MealSearch{
+		Query:      q.Text,
+		Limit:      q.Limit,
+		Page:       q.Offset/q.Limit + 1,
+		TotalDatas: totdata,
+		TotalPages: int(math.Ceil(float64(totdata) / float64(q.Limit))),
+		Datas:      []responses.MealSearchResult{},
+		Facets:     *facets,
+	}
+
+	if len(hits) == 0 {
+		return &srres, nil
+	}
+
+	ids := make([]uuid.UUID, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.ID
+	}
+
+	meals, err := s.rmeal.ReadByIDs(ctx, ids)
+	if err != nil {
+		return nil, consttypes.ErrFailedToSearchMeals.Wrap(err)
+	}
+
+	mealreses := make(map[uuid.UUID]*responses.Meal, len(meals))
+	for _, meal := range meals {
+		mealres, err := meal.ToResponse()
+		if err != nil {
+			return nil, consttypes.ErrConvertFailed.Wrap(err)
+		}
+
+		mealreses[meal.ID] = mealres
+	}
+
+	for _, hit := range hits {
+		// * a meal deleted between the two reads is left out
+		mealres, ok := mealreses[hit.ID]
+		if !ok {
+			continue
+		}
+
+		srres.Datas = append(srres.Datas, responses.MealSearchResult{
+			Meal: *mealres,
+			Rank: hit.Rank,
+			Highlights: responses.MealSearchHighlight{
+				Name:        hit.Name,
+				Description: hit.Description,
+			},
+		})
+	}
+
+	return &srres, nil
+}
+
+func (s *SearchService) IndexMeals(ctx context.Context) (int64, error) {
+	count, err := s.rsrch.IndexMeals(ctx)
+	if err != nil {
+		return 0, consttypes.ErrFailedToIndexMealSearch.Wrap(err)
+	}
+
+	return count, nil
+}
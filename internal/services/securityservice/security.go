@@ -0,0 +1,409 @@
+package securityservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"project-skbackend/configs"
+	"project-skbackend/internal/controllers/requests"
+	"project-skbackend/internal/models"
+	"project-skbackend/internal/repositories/securityeventrepo"
+	"project-skbackend/internal/services/mailservice"
+	"project-skbackend/internal/services/userservice"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/utils/utlogger"
+	"project-skbackend/packages/utils/utpagination"
+	"project-skbackend/packages/utils/utstring"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	unlockTokenLength = 32
+)
+
+type (
+	ThrottleScope string
+
+	SecurityService struct {
+		cfg *configs.Config
+		rdb *redis.Client
+
+		// * repository
+		rsev securityeventrepo.ISecurityEventRepository
+
+		// * service
+		smail mailservice.IMailService
+		suser userservice.IUserService
+
+		wu string
+	}
+
+	ISecurityService interface {
+		// * throttles
+		ThrottleIP(ctx context.Context, action consttypes.ThrottleAction, ip string) error
+		ThrottleAccount(ctx context.Context, action consttypes.ThrottleAction, account string, ip string) error
+
+		// * lockouts
+		CheckLockout(ctx context.Context, uid uuid.UUID) error
+		RecordFailedSignin(ctx context.Context, user models.User, ip string, useragent string) error
+		ResetFailedSignin(ctx context.Context, uid uuid.UUID) error
+		Unlock(ctx context.Context, req requests.UnlockAccount, ip string, useragent string) error
+		Forget(ctx context.Context, uid uuid.UUID, email string) error
+
+		// * security events
+		RecordEvent(ctx context.Context, se models.SecurityEvent)
+		FindEventsByUserID(ctx context.Context, uid uuid.UUID, p utpagination.Pagination) (*utpagination.Pagination, error)
+	}
+)
+
+const (
+	TS_IP      ThrottleScope = "ip"
+	TS_ACCOUNT ThrottleScope = "account"
+)
+
+// * sliding window log, every allowed request is kept in a sorted set
+// * scored by its time, the request is rejected when the set is full and
+// * the time until the oldest request leaves the window is returned
+var slidingWindow = redis.NewScript(`
+local key = KEYS[1]
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+local member = ARGV[4]
+
+redis.call("ZREMRANGEBYSCORE", key, 0, now - window)
+
+if redis.call("ZCARD", key) >= limit then
+	local oldest = redis.call("ZRANGE", key, 0, 0, "WITHSCORES")
+	return tonumber(oldest[2]) + window - now
+end
+
+redis.call("ZADD", key, now, member)
+redis.call("PEXPIRE", key, window)
+
+return 0
+`)
+
+func NewSecurityService(
+	cfg *configs.Config,
+	rdb *redis.Client,
+	// * repository
+	rsev securityeventrepo.ISecurityEventRepository,
+	// * service
+	smail mailservice.IMailService,
+	suser userservice.IUserService,
+) *SecurityService {
+	return &SecurityService{
+		cfg: cfg,
+		rdb: rdb,
+
+		// * repository
+		rsev: rsev,
+
+		// * service
+		smail: smail,
+		suser: suser,
+
+		wu: cfg.Web.URL,
+	}
+}
+
+// ! -------------------------------- throttles ------------------------------- ! //
+func (s *SecurityService) ThrottleIP(ctx context.Context, action consttypes.ThrottleAction, ip string) error {
+	err := s.throttle(ctx, action, TS_IP, ip)
+	if err != nil && errors.Is(err, consttypes.ErrTooManyRequests) {
+		utlogger.WarnContext(ctx, fmt.Sprintf("Throttled %s requests from ip %s", action, ip))
+	}
+
+	return err
+}
+
+func (s *SecurityService) ThrottleAccount(ctx context.Context, action consttypes.ThrottleAction, account string, ip string) error {
+	account = strings.ToLower(strings.TrimSpace(account))
+
+	err := s.throttle(ctx, action, TS_ACCOUNT, account)
+	if err != nil && errors.Is(err, consttypes.ErrTooManyRequests) {
+		s.RecordEvent(ctx, *models.NewSecurityEvent(consttypes.SET_THROTTLED, nil, account, ip, "", action.String()))
+	}
+
+	return err
+}
+
+func (s *SecurityService) throttle(ctx context.Context, action consttypes.ThrottleAction, scope ThrottleScope, id string) error {
+	limit := s.throttleLimit(action, scope)
+	if limit <= 0 {
+		return nil
+	}
+
+	now := consttypes.TimeNow()
+	window := time.Duration(s.cfg.SecurityThrottle.Window) * time.Minute
+	key := throttleKey(action, scope, id)
+
+	retryafter, err := slidingWindow.Run(
+		ctx,
+		s.rdb,
+		[]string{key},
+		now.UnixMilli(),
+		window.Milliseconds(),
+		limit,
+		uuid.NewString(),
+	).Int64()
+
+	if err != nil {
+		// * the request is let through when redis is not available,
+		// * the lockout still protects the accounts from brute force
+		utlogger.ErrorContext(ctx, err)
+		return nil
+	}
+
+	if retryafter > 0 {
+		return consttypes.ErrRetryAfter(consttypes.ErrTooManyRequests, time.Duration(retryafter)*time.Millisecond)
+	}
+
+	return nil
+}
+
+func throttleKey(action consttypes.ThrottleAction, scope ThrottleScope, id string) string {
+	return fmt.Sprintf("throttle:%s:%s:%s", action, scope, id)
+}
+
+func (s *SecurityService) throttleLimit(action consttypes.ThrottleAction, scope ThrottleScope) int {
+	var (
+		t = s.cfg.SecurityThrottle
+	)
+
+	switch action {
+	case consttypes.TA_SIGNIN:
+		if scope == TS_IP {
+			return t.SigninIP
+		}
+		return t.SigninAccount
+	case consttypes.TA_FORGOT_PASSWORD:
+		if scope == TS_IP {
+			return t.ForgotPasswordIP
+		}
+		return t.ForgotPasswordAccount
+	case consttypes.TA_VERIFY_SEND:
+		if scope == TS_IP {
+			return t.VerifySendIP
+		}
+		return t.VerifySendAccount
+	case consttypes.TA_REGISTER:
+		if scope == TS_IP {
+			return t.RegisterIP
+		}
+		return t.RegisterAccount
+	}
+
+	return 0
+}
+
+// ! -------------------------------- lockouts -------------------------------- ! //
+func lockKey(uid uuid.UUID) string {
+	return fmt.Sprintf("lockout:lock:%s", uid)
+}
+
+func failsKey(uid uuid.UUID) string {
+	return fmt.Sprintf("lockout:fails:%s", uid)
+}
+
+func levelKey(uid uuid.UUID) string {
+	return fmt.Sprintf("lockout:level:%s", uid)
+}
+
+func unlockKey(token string) string {
+	return fmt.Sprintf("lockout:unlock:%s", token)
+}
+
+func (s *SecurityService) CheckLockout(ctx context.Context, uid uuid.UUID) error {
+	ttl, err := s.rdb.PTTL(ctx, lockKey(uid)).Result()
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil
+	}
+
+	// * a negative ttl means the key does not exist
+	if ttl > 0 {
+		return consttypes.ErrRetryAfter(consttypes.ErrAccountLocked, ttl)
+	}
+
+	return nil
+}
+
+// * counts the failed signin of the account, the account is locked once
+// * the threshold is reached and every next lock within a day doubles
+// * the lock duration up to the max duration
+func (s *SecurityService) RecordFailedSignin(ctx context.Context, user models.User, ip string, useragent string) error {
+	var (
+		lo     = s.cfg.SecurityLockout
+		window = time.Duration(s.cfg.SecurityThrottle.Window) * time.Minute
+	)
+
+	s.RecordEvent(ctx, *models.NewSecurityEvent(consttypes.SET_SIGNIN_FAILED, &user.ID, user.Email, ip, useragent, ""))
+
+	fails, err := s.rdb.Incr(ctx, failsKey(user.ID)).Result()
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil
+	}
+
+	// * the window starts on the first failed signin
+	if fails == 1 {
+		s.rdb.Expire(ctx, failsKey(user.ID), window)
+	}
+
+	if fails < int64(lo.Threshold) {
+		return nil
+	}
+
+	level, err := s.rdb.Incr(ctx, levelKey(user.ID)).Result()
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return consttypes.ErrFailedToLockAccount.Wrap(err)
+	}
+	s.rdb.Expire(ctx, levelKey(user.ID), 24*time.Hour)
+
+	duration := time.Duration(lo.Duration) * time.Minute
+	for i := int64(1); i < level && duration < time.Duration(lo.MaxDuration)*time.Minute; i++ {
+		duration *= 2
+	}
+	duration = min(duration, time.Duration(lo.MaxDuration)*time.Minute)
+
+	token, err := utstring.GenerateRandomToken(unlockTokenLength)
+	if err != nil {
+		return consttypes.ErrFailedToGenerateToken.Wrap(err)
+	}
+
+	_, err = s.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, lockKey(user.ID), token, duration)
+		pipe.Set(ctx, unlockKey(token), user.ID.String(), duration)
+		pipe.Del(ctx, failsKey(user.ID))
+		return nil
+	})
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return consttypes.ErrFailedToLockAccount.Wrap(err)
+	}
+
+	s.RecordEvent(ctx, *models.NewSecurityEvent(consttypes.SET_ACCOUNT_LOCKED, &user.ID, user.Email, ip, useragent, fmt.Sprintf("locked for %s", duration)))
+
+	if err := s.sendAccountLockedEmail(ctx, user, token, consttypes.TimeNow().Add(duration)); err != nil {
+		// * the lock still expires by itself when the email is not sent
+		utlogger.ErrorContext(ctx, err)
+	}
+
+	return consttypes.ErrRetryAfter(consttypes.ErrAccountLocked, duration)
+}
+
+func (s *SecurityService) ResetFailedSignin(ctx context.Context, uid uuid.UUID) error {
+	err := s.rdb.Del(ctx, failsKey(uid), levelKey(uid)).Err()
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *SecurityService) Unlock(ctx context.Context, req requests.UnlockAccount, ip string, useragent string) error {
+	uidstr, err := s.rdb.Get(ctx, unlockKey(req.Token)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return consttypes.ErrUnlockTokenInvalid.Wrap(err)
+		}
+
+		utlogger.ErrorContext(ctx, err)
+		return err
+	}
+
+	uid, err := uuid.Parse(uidstr)
+	if err != nil {
+		return consttypes.ErrUnlockTokenInvalid.Wrap(err)
+	}
+
+	// * the lock level is kept so a brute force that continues
+	// * after the unlock is locked for longer the next time
+	err = s.rdb.Del(ctx, unlockKey(req.Token), lockKey(uid), failsKey(uid)).Err()
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return err
+	}
+
+	s.RecordEvent(ctx, *models.NewSecurityEvent(consttypes.SET_ACCOUNT_UNLOCKED, &uid, "", ip, useragent, ""))
+
+	return nil
+}
+
+// * drops every lockout and throttle of an erased user, the email
+// * is the account the throttles were counted by
+func (s *SecurityService) Forget(ctx context.Context, uid uuid.UUID, email string) error {
+	keys := []string{lockKey(uid), failsKey(uid), levelKey(uid)}
+
+	token, err := s.rdb.Get(ctx, lockKey(uid)).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		utlogger.ErrorContext(ctx, err)
+		return err
+	}
+
+	if token != "" {
+		keys = append(keys, unlockKey(token))
+	}
+
+	account := strings.ToLower(strings.TrimSpace(email))
+	for _, action := range consttypes.ThrottleActions() {
+		keys = append(keys, throttleKey(action, TS_ACCOUNT, account))
+	}
+
+	err = s.rdb.Del(ctx, keys...).Err()
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *SecurityService) sendAccountLockedEmail(ctx context.Context, user models.User, token string, lockeduntil time.Time) error {
+	firstname, lastname, err := s.suser.GetUserName(ctx, user.ID)
+	if err != nil {
+		return consttypes.ErrFailedToGetUserName.Wrap(err)
+	}
+
+	emreq := requests.SendEmailAccountLocked{
+		Name:        utstring.AppendName(firstname, lastname),
+		Email:       user.Email,
+		LockedUntil: lockeduntil.Format(consttypes.DATETIMEHOURMINUTESFORMAT),
+		LinkUrl:     fmt.Sprintf("%s/unlock-account/%s", s.wu, token),
+	}
+
+	return s.smail.SendAccountLockedEmail(ctx, emreq)
+}
+
+// ! ----------------------------- security events ---------------------------- ! //
+// * security events are recorded on a best effort basis, a failure is
+// * only logged so it never blocks the request that triggered it
+func (s *SecurityService) RecordEvent(ctx context.Context, se models.SecurityEvent) {
+	utlogger.InfoContext(ctx, fmt.Sprintf("Security event %s, user: %v, email: %s, ip: %s, detail: %s", se.Type, se.UserID, se.Email, se.IP, se.Detail))
+
+	if _, err := s.rsev.Create(ctx, se); err != nil {
+		utlogger.ErrorContext(ctx, consttypes.ErrFailedToRecordSecurityEvent)
+	}
+}
+
+func (s *SecurityService) FindEventsByUserID(ctx context.Context, uid uuid.UUID, p utpagination.Pagination) (*utpagination.Pagination, error) {
+	ses, err := s.rsev.FindByUserID(ctx, uid, p)
+	if err != nil {
+		if errors.Is(err, consttypes.ErrInvalidCursor) || errors.Is(err, consttypes.ErrInvalidFilter) {
+			return nil, err
+		}
+
+		return nil, consttypes.ErrFailedToReadSecurityEvents.Wrap(err)
+	}
+
+	return ses, nil
+}
@@ -0,0 +1,376 @@
+package serviceaccountservice
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"project-skbackend/configs"
+	"project-skbackend/internal/controllers/requests"
+	"project-skbackend/internal/controllers/responses"
+	"project-skbackend/internal/models"
+	"project-skbackend/internal/repositories/apikeyrepo"
+	"project-skbackend/internal/repositories/serviceaccountrepo"
+	"project-skbackend/internal/repositories/userrepo"
+	"project-skbackend/internal/services/permissionservice"
+	"project-skbackend/internal/services/securityservice"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/utils/utlogger"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	keyPrefix       = "skb_"
+	keyPrefixLength = 4
+	keySecretLength = 32
+)
+
+type (
+	ServiceAccountService struct {
+		cfg *configs.Config
+
+		// * repository
+		rsvac serviceaccountrepo.IServiceAccountRepository
+		rapik apikeyrepo.IAPIKeyRepository
+		ruser userrepo.IUserRepository
+
+		// * service
+		sperm permissionservice.IPermissionService
+		ssecu securityservice.ISecurityService
+	}
+
+	IServiceAccountService interface {
+		// * service accounts
+		Create(ctx context.Context, req requests.CreateServiceAccount, createdby uuid.UUID) (*responses.ServiceAccount, error)
+		Read(ctx context.Context) ([]*responses.ServiceAccount, error)
+		GetByID(ctx context.Context, id uuid.UUID) (*responses.ServiceAccount, error)
+		Update(ctx context.Context, id uuid.UUID, req requests.UpdateServiceAccount) (*responses.ServiceAccount, error)
+		Delete(ctx context.Context, id uuid.UUID) error
+
+		// * api keys
+		CreateAPIKey(ctx context.Context, said uuid.UUID, req requests.CreateAPIKey, ip string, useragent string) (*responses.APIKeyCreated, error)
+		FindAPIKeys(ctx context.Context, said uuid.UUID) ([]*responses.APIKey, error)
+		RevokeAPIKey(ctx context.Context, said uuid.UUID, kid uuid.UUID, ip string, useragent string) error
+		Authenticate(ctx context.Context, key string, ip string) (*responses.APIKeyPrincipal, error)
+	}
+)
+
+func NewServiceAccountService(
+	cfg *configs.Config,
+	// * repository
+	rsvac serviceaccountrepo.IServiceAccountRepository,
+	rapik apikeyrepo.IAPIKeyRepository,
+	ruser userrepo.IUserRepository,
+	// * service
+	sperm permissionservice.IPermissionService,
+	ssecu securityservice.ISecurityService,
+) *ServiceAccountService {
+	return &ServiceAccountService{
+		cfg: cfg,
+
+		// * repository
+		rsvac: rsvac,
+		rapik: rapik,
+		ruser: ruser,
+
+		// * service
+		sperm: sperm,
+		ssecu: ssecu,
+	}
+}
+
+// ! ---------------------------- service accounts ---------------------------- ! //
+func (s *ServiceAccountService) Create(ctx context.Context, req requests.CreateServiceAccount, createdby uuid.UUID) (*responses.ServiceAccount, error) {
+	user, err := s.ruser.GetByID(ctx, req.UserID)
+	if err != nil {
+		return nil, consttypes.ErrUserNotFound.Wrap(err)
+	}
+
+	if err := s.checkScopes(ctx, user.Role, req.Scopes); err != nil {
+		return nil, err
+	}
+
+	sa, err := s.rsvac.Create(ctx, *models.NewServiceAccount(user.ID, req.Name, req.Description, req.Scopes, createdby))
+	if err != nil {
+		return nil, consttypes.ErrFailedToCreateServiceAccount.Wrap(err)
+	}
+
+	return sa.ToResponse(), nil
+}
+
+func (s *ServiceAccountService) Read(ctx context.Context) ([]*responses.ServiceAccount, error) {
+	var (
+		sasres []*responses.ServiceAccount
+	)
+
+	sas, err := s.rsvac.Read(ctx)
+	if err != nil {
+		return nil, consttypes.ErrFailedToReadServiceAccounts.Wrap(err)
+	}
+
+	for _, sa := range sas {
+		sasres = append(sasres, sa.ToResponse())
+	}
+
+	return sasres, nil
+}
+
+func (s *ServiceAccountService) GetByID(ctx context.Context, id uuid.UUID) (*responses.ServiceAccount, error) {
+	sa, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return sa.ToResponse(), nil
+}
+
+func (s *ServiceAccountService) Update(ctx context.Context, id uuid.UUID, req requests.UpdateServiceAccount) (*responses.ServiceAccount, error) {
+	sa, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.ruser.GetByID(ctx, sa.UserID)
+	if err != nil {
+		return nil, consttypes.ErrUserNotFound.Wrap(err)
+	}
+
+	if err := s.checkScopes(ctx, user.Role, req.Scopes); err != nil {
+		return nil, err
+	}
+
+	sa.Name = req.Name
+	sa.Description = req.Description
+	sa.SetScopes(req.Scopes)
+
+	switch {
+	case req.Disabled && sa.DisabledAt == nil:
+		now := consttypes.TimeNow()
+		sa.DisabledAt = &now
+	case !req.Disabled:
+		sa.DisabledAt = nil
+	}
+
+	sa, err = s.rsvac.Update(ctx, *sa)
+	if err != nil {
+		return nil, consttypes.ErrFailedToUpdateServiceAccount.Wrap(err)
+	}
+
+	return sa.ToResponse(), nil
+}
+
+func (s *ServiceAccountService) Delete(ctx context.Context, id uuid.UUID) error {
+	sa, err := s.get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.rsvac.Delete(ctx, *sa); err != nil {
+		return consttypes.ErrFailedToDeleteServiceAccount.Wrap(err)
+	}
+
+	return nil
+}
+
+func (s *ServiceAccountService) get(ctx context.Context, id uuid.UUID) (*models.ServiceAccount, error) {
+	sa, err := s.rsvac.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, consttypes.ErrServiceAccountNotFound.Wrap(err)
+		}
+
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return sa, nil
+}
+
+// * a service account never acts for an admin and every scope has to be
+// * granted to the owner role, a key can not do more than its owner
+func (s *ServiceAccountService) checkScopes(ctx context.Context, role consttypes.UserRole, scopes []consttypes.Permission) error {
+	if role == consttypes.UR_ADMIN {
+		return consttypes.ErrServiceAccountOwnerNotAllowed
+	}
+
+	for _, scope := range scopes {
+		ok, err := s.sperm.HasPermission(ctx, role, scope)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			return fmt.Errorf("%w: %s", consttypes.ErrScopeNotGranted, scope)
+		}
+	}
+
+	return nil
+}
+
+// ! --------------------------------- api keys -------------------------------- ! //
+func (s *ServiceAccountService) CreateAPIKey(ctx context.Context, said uuid.UUID, req requests.CreateAPIKey, ip string, useragent string) (*responses.APIKeyCreated, error) {
+	var (
+		life = req.Life
+	)
+
+	sa, err := s.get(ctx, said)
+	if err != nil {
+		return nil, err
+	}
+
+	if sa.DisabledAt != nil {
+		return nil, consttypes.ErrServiceAccountDisabled
+	}
+
+	if life == 0 {
+		life = s.cfg.SecurityAPIKey.Life
+	}
+
+	if life > s.cfg.SecurityAPIKey.MaxLife {
+		return nil, consttypes.ErrAPIKeyLifeTooLong
+	}
+
+	key, prefix, err := generateKey(ctx)
+	if err != nil {
+		return nil, consttypes.ErrFailedToGenerateToken.Wrap(err)
+	}
+
+	ak, err := s.rapik.Create(ctx, models.APIKey{
+		ServiceAccountID: sa.ID,
+		Name:             req.Name,
+		Prefix:           prefix,
+		KeyHash:          hashKey(key),
+		ExpiresAt:        consttypes.TimeNow().AddDate(0, 0, life),
+	})
+
+	if err != nil {
+		return nil, consttypes.ErrFailedToCreateAPIKey.Wrap(err)
+	}
+
+	s.ssecu.RecordEvent(ctx, *models.NewSecurityEvent(consttypes.SET_API_KEY_CREATED, &sa.UserID, "", ip, useragent, fmt.Sprintf("key %s of service account %s", prefix, sa.Name)))
+
+	return &responses.APIKeyCreated{
+		APIKey: *ak.ToResponse(),
+		Key:    key,
+	}, nil
+}
+
+func (s *ServiceAccountService) FindAPIKeys(ctx context.Context, said uuid.UUID) ([]*responses.APIKey, error) {
+	var (
+		aksres []*responses.APIKey
+	)
+
+	if _, err := s.get(ctx, said); err != nil {
+		return nil, err
+	}
+
+	aks, err := s.rapik.FindByServiceAccountID(ctx, said)
+	if err != nil {
+		return nil, consttypes.ErrFailedToReadAPIKeys.Wrap(err)
+	}
+
+	for _, ak := range aks {
+		aksres = append(aksres, ak.ToResponse())
+	}
+
+	return aksres, nil
+}
+
+func (s *ServiceAccountService) RevokeAPIKey(ctx context.Context, said uuid.UUID, kid uuid.UUID, ip string, useragent string) error {
+	sa, err := s.get(ctx, said)
+	if err != nil {
+		return err
+	}
+
+	ak, err := s.rapik.GetByID(ctx, kid)
+	if err != nil || ak.ServiceAccountID != sa.ID {
+		return consttypes.ErrAPIKeyNotFound
+	}
+
+	revoked, err := s.rapik.Revoke(ctx, ak.ID)
+	if err != nil {
+		return consttypes.ErrFailedToRevokeAPIKey.Wrap(err)
+	}
+
+	// * revoking an already revoked key changes nothing
+	if revoked {
+		s.ssecu.RecordEvent(ctx, *models.NewSecurityEvent(consttypes.SET_API_KEY_REVOKED, &sa.UserID, "", ip, useragent, fmt.Sprintf("key %s of service account %s", ak.Prefix, sa.Name)))
+	}
+
+	return nil
+}
+
+// * resolves the key into the user the service account acts for and the
+// * scopes the request is limited to
+func (s *ServiceAccountService) Authenticate(ctx context.Context, key string, ip string) (*responses.APIKeyPrincipal, error) {
+	ak, err := s.rapik.GetByKeyHash(ctx, hashKey(key))
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			utlogger.ErrorContext(ctx, err)
+		}
+
+		return nil, consttypes.ErrAPIKeyInvalid.Wrap(err)
+	}
+
+	// * a deleted service account or user is not preloaded
+	if ak.IsRevoked() || ak.ServiceAccount.ID == uuid.Nil || ak.ServiceAccount.User.ID == uuid.Nil {
+		return nil, consttypes.ErrAPIKeyInvalid
+	}
+
+	if ak.IsExpired() {
+		return nil, consttypes.ErrAPIKeyExpired
+	}
+
+	if ak.ServiceAccount.DisabledAt != nil {
+		return nil, consttypes.ErrServiceAccountDisabled
+	}
+
+	userres, err := ak.ServiceAccount.User.ToResponse()
+	if err != nil {
+		return nil, consttypes.ErrConvertFailed.Wrap(err)
+	}
+
+	before := consttypes.TimeNow().Add(-time.Duration(s.cfg.SecurityAPIKey.TouchInterval) * time.Second)
+	if ak.LastUsedAt == nil || ak.LastUsedAt.Before(before) {
+		// * tracking the last use is best effort and never blocks the request
+		_ = s.rapik.Touch(ctx, ak.ID, ip, before)
+	}
+
+	return &responses.APIKeyPrincipal{
+		User:             *userres,
+		APIKeyID:         ak.ID,
+		ServiceAccountID: ak.ServiceAccountID,
+		Scopes:           ak.ServiceAccount.ScopeList(),
+	}, nil
+}
+
+// * the key is the prefix followed by a random secret, the prefix
+// * is kept in plain text so the key can be recognized later on
+func generateKey(ctx context.Context) (string, string, error) {
+	bprefix := make([]byte, keyPrefixLength)
+	if _, err := rand.Read(bprefix); err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return "", "", err
+	}
+
+	bsecret := make([]byte, keySecretLength)
+	if _, err := rand.Read(bsecret); err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return "", "", err
+	}
+
+	prefix := keyPrefix + hex.EncodeToString(bprefix)
+
+	return prefix + "_" + base64.RawURLEncoding.EncodeToString(bsecret), prefix, nil
+}
+
+func hashKey(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,323 @@
+package sessionservice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"project-skbackend/internal/controllers/responses"
+	"project-skbackend/internal/models"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/utils/utlogger"
+	"project-skbackend/packages/utils/uttoken"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// * the last seen time is only written once in a while
+	// * instead of on every authenticated request
+	lastSeenInterval = time.Minute
+)
+
+type (
+	SessionService struct {
+		rdb *redis.Client
+	}
+
+	ISessionService interface {
+		Create(ctx context.Context, uid uuid.UUID, device string, ip string, taccess uttoken.Token, trefresh uttoken.Token) (*models.Session, error)
+		Rotate(ctx context.Context, trefreshold uttoken.Token, ip string, taccess uttoken.Token, trefresh uttoken.Token) (*models.Session, error)
+		Validate(ctx context.Context, taccess uttoken.Token) (*models.Session, error)
+		Revoke(ctx context.Context, uid uuid.UUID, sid uuid.UUID) error
+		RevokeAll(ctx context.Context, uid uuid.UUID, exceptsid *uuid.UUID) error
+		FindByUserID(ctx context.Context, uid uuid.UUID, currentsid uuid.UUID) ([]*responses.Session, error)
+	}
+)
+
+func NewSessionService(
+	rdb *redis.Client,
+) *SessionService {
+	return &SessionService{
+		rdb: rdb,
+	}
+}
+
+func sessionKey(sid uuid.UUID) string {
+	return fmt.Sprintf("session:%s", sid)
+}
+
+// * the last seen time is kept apart from the session, so writing it
+// * never races with a rotation of the token pair of the session
+func lastSeenKey(sid uuid.UUID) string {
+	return fmt.Sprintf("session_last_seen:%s", sid)
+}
+
+func userSessionsKey(uid uuid.UUID) string {
+	return fmt.Sprintf("user_sessions:%s", uid)
+}
+
+func (s *SessionService) Create(ctx context.Context, uid uuid.UUID, device string, ip string, taccess uttoken.Token, trefresh uttoken.Token) (*models.Session, error) {
+	now := consttypes.TimeNow()
+
+	ses := models.Session{
+		ID:               trefresh.SessionID,
+		UserID:           uid,
+		Device:           device,
+		IP:               ip,
+		AccessTokenUUID:  taccess.TokenUUID,
+		RefreshTokenUUID: trefresh.TokenUUID,
+		CreatedAt:        now,
+		LastSeenAt:       now,
+		ExpiresAt:        *trefresh.Expires,
+	}
+
+	data, err := json.Marshal(ses)
+	if err != nil {
+		return nil, consttypes.ErrConvertFailed.Wrap(err)
+	}
+
+	ttl := ses.ExpiresAt.Sub(now)
+
+	_, err = s.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, sessionKey(ses.ID), data, ttl)
+		pipe.SAdd(ctx, userSessionsKey(uid), ses.ID.String())
+		// * every session has the same life, so the latest
+		// * session is always the last one to expire
+		pipe.Expire(ctx, userSessionsKey(uid), ttl)
+		return nil
+	})
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, consttypes.ErrFailedToCreateSession.Wrap(err)
+	}
+
+	return &ses, nil
+}
+
+// * replaces the token pair of the session, a refresh token that is not
+// * the latest one of its family means it was stolen or replayed, so the
+// * whole family is revoked and both parties have to sign in again
+func (s *SessionService) Rotate(ctx context.Context, trefreshold uttoken.Token, ip string, taccess uttoken.Token, trefresh uttoken.Token) (*models.Session, error) {
+	var (
+		ses    *models.Session
+		reused bool
+		key    = sessionKey(trefreshold.SessionID)
+	)
+
+	err := s.rdb.Watch(ctx, func(tx *redis.Tx) error {
+		var err error
+
+		ses, err = s.get(ctx, tx, trefreshold.SessionID)
+		if err != nil {
+			return err
+		}
+
+		if ses.RefreshTokenUUID != trefreshold.TokenUUID {
+			reused = true
+			return nil
+		}
+
+		now := consttypes.TimeNow()
+
+		ses.IP = ip
+		ses.AccessTokenUUID = taccess.TokenUUID
+		ses.RefreshTokenUUID = trefresh.TokenUUID
+		ses.LastSeenAt = now
+		ses.ExpiresAt = *trefresh.Expires
+
+		data, err := json.Marshal(ses)
+		if err != nil {
+			return consttypes.ErrConvertFailed.Wrap(err)
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, key, data, ses.ExpiresAt.Sub(now))
+			pipe.Del(ctx, lastSeenKey(ses.ID))
+			pipe.Expire(ctx, userSessionsKey(ses.UserID), ses.ExpiresAt.Sub(now))
+			return nil
+		})
+
+		return err
+	}, key)
+
+	// * the session changed between the read and the write, so another
+	// * refresh with the same token won the race, which is a reuse too
+	if errors.Is(err, redis.TxFailedErr) {
+		reused = true
+		err = nil
+	}
+
+	if err != nil {
+		if errors.Is(err, consttypes.ErrSessionRevoked) {
+			return nil, err
+		}
+
+		utlogger.ErrorContext(ctx, err)
+		return nil, consttypes.ErrFailedToUpdateSession.Wrap(err)
+	}
+
+	if reused {
+		// * the session may already be revoked by a concurrent reuse
+		if err := s.Revoke(ctx, ses.UserID, ses.ID); err != nil && !errors.Is(err, consttypes.ErrSessionNotFound) {
+			return nil, err
+		}
+
+		utlogger.WarnContext(ctx, fmt.Sprintf("Refresh token reuse detected, session %s of user %s is revoked", ses.ID, ses.UserID))
+		return nil, consttypes.ErrRefreshTokenReused
+	}
+
+	return ses, nil
+}
+
+// * an access token is only accepted while its session is active and
+// * it is still the latest access token issued for the session
+func (s *SessionService) Validate(ctx context.Context, taccess uttoken.Token) (*models.Session, error) {
+	ses, err := s.get(ctx, s.rdb, taccess.SessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if ses.AccessTokenUUID != taccess.TokenUUID {
+		return nil, consttypes.ErrSessionRevoked
+	}
+
+	now := consttypes.TimeNow()
+	if now.Sub(ses.LastSeenAt) >= lastSeenInterval {
+		ses.LastSeenAt = now
+
+		// * failing to update the last seen time should not block the request
+		err := s.rdb.SetArgs(ctx, lastSeenKey(ses.ID), now.Format(time.RFC3339Nano), redis.SetArgs{ExpireAt: ses.ExpiresAt}).Err()
+		if err != nil {
+			utlogger.ErrorContext(ctx, err)
+		}
+	}
+
+	return ses, nil
+}
+
+// * a session can only be revoked by its own user
+func (s *SessionService) Revoke(ctx context.Context, uid uuid.UUID, sid uuid.UUID) error {
+	ses, err := s.get(ctx, s.rdb, sid)
+	if err != nil && !errors.Is(err, consttypes.ErrSessionRevoked) {
+		utlogger.ErrorContext(ctx, err)
+		return consttypes.ErrFailedToRevokeSession
+	}
+
+	if ses != nil && ses.UserID != uid {
+		return consttypes.ErrSessionNotFound
+	}
+
+	_, err = s.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, sessionKey(sid), lastSeenKey(sid))
+		pipe.SRem(ctx, userSessionsKey(uid), sid.String())
+		return nil
+	})
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return consttypes.ErrFailedToRevokeSession.Wrap(err)
+	}
+
+	// * the stale id is removed above even if the session has expired
+	if ses == nil {
+		return consttypes.ErrSessionNotFound
+	}
+
+	return nil
+}
+
+// * revokes every session of the user, except the given one
+// * so the user can sign out the other devices
+func (s *SessionService) RevokeAll(ctx context.Context, uid uuid.UUID, exceptsid *uuid.UUID) error {
+	sids, err := s.rdb.SMembers(ctx, userSessionsKey(uid)).Result()
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return consttypes.ErrFailedToRevokeSession.Wrap(err)
+	}
+
+	for _, sidstr := range sids {
+		sid, err := uuid.Parse(sidstr)
+		if err != nil {
+			continue
+		}
+
+		if exceptsid != nil && sid == *exceptsid {
+			continue
+		}
+
+		if err := s.Revoke(ctx, uid, sid); err != nil && !errors.Is(err, consttypes.ErrSessionNotFound) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *SessionService) FindByUserID(ctx context.Context, uid uuid.UUID, currentsid uuid.UUID) ([]*responses.Session, error) {
+	var (
+		sesreses []*responses.Session
+	)
+
+	sids, err := s.rdb.SMembers(ctx, userSessionsKey(uid)).Result()
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, consttypes.ErrFailedToReadSessions.Wrap(err)
+	}
+
+	for _, sidstr := range sids {
+		sid, err := uuid.Parse(sidstr)
+		if err != nil {
+			continue
+		}
+
+		ses, err := s.get(ctx, s.rdb, sid)
+		if err != nil {
+			if errors.Is(err, consttypes.ErrSessionRevoked) {
+				// * the session has expired, clean up the stale id
+				s.rdb.SRem(ctx, userSessionsKey(uid), sidstr)
+				continue
+			}
+
+			return nil, consttypes.ErrFailedToReadSessions.Wrap(err)
+		}
+
+		sesreses = append(sesreses, ses.ToResponse(currentsid))
+	}
+
+	sort.Slice(sesreses, func(i, j int) bool {
+		return sesreses[i].LastSeenAt.After(sesreses[j].LastSeenAt)
+	})
+
+	return sesreses, nil
+}
+
+func (s *SessionService) get(ctx context.Context, rc redis.Cmdable, sid uuid.UUID) (*models.Session, error) {
+	var (
+		ses *models.Session
+	)
+
+	data, err := rc.Get(ctx, sessionKey(sid)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, consttypes.ErrSessionRevoked.Wrap(err)
+		}
+
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &ses); err != nil {
+		return nil, consttypes.ErrConvertFailed.Wrap(err)
+	}
+
+	lastseen, err := rc.Get(ctx, lastSeenKey(sid)).Time()
+	if err == nil && lastseen.After(ses.LastSeenAt) {
+		ses.LastSeenAt = lastseen
+	}
+
+	return ses, nil
+}
@@ -0,0 +1,184 @@
+package sessionservice
+
+import (
+	"context"
+	"errors"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/utils/uttoken"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestSessions(t *testing.T) (*SessionService, *miniredis.Miniredis) {
+	mr := miniredis.RunT(t)
+
+	return NewSessionService(redis.NewClient(&redis.Options{Addr: mr.Addr()})), mr
+}
+
+// * a new token of the session, like the pair issued on a sign in or refresh
+func newToken(sid uuid.UUID) uttoken.Token {
+	expires := consttypes.TimeNow().Add(time.Hour)
+
+	return uttoken.Token{
+		TokenUUID: uuid.New(),
+		SessionID: sid,
+		Expires:   &expires,
+	}
+}
+
+func TestRotate(t *testing.T) {
+	var (
+		ctx = context.Background()
+		uid = uuid.New()
+		sid = uuid.New()
+	)
+
+	s, _ := newTestSessions(t)
+
+	trefresh := newToken(sid)
+	if _, err := s.Create(ctx, uid, "device", "127.0.0.1", newToken(sid), trefresh); err != nil {
+		t.Fatal(err)
+	}
+
+	taccessnew, trefreshnew := newToken(sid), newToken(sid)
+
+	ses, err := s.Rotate(ctx, trefresh, "10.0.0.1", taccessnew, trefreshnew)
+	if err != nil {
+		t.Fatalf("rotate err = %v", err)
+	}
+
+	if ses.RefreshTokenUUID != trefreshnew.TokenUUID || ses.AccessTokenUUID != taccessnew.TokenUUID || ses.IP != "10.0.0.1" {
+		t.Errorf("rotated session = %+v, want the new token pair and ip", ses)
+	}
+
+	// * the new access token is accepted, the new refresh token rotates again
+	if _, err := s.Validate(ctx, taccessnew); err != nil {
+		t.Errorf("validate new access err = %v", err)
+	}
+
+	if _, err := s.Rotate(ctx, trefreshnew, "10.0.0.1", newToken(sid), newToken(sid)); err != nil {
+		t.Errorf("rotate new refresh err = %v", err)
+	}
+}
+
+func TestRotateReuseRevokesSession(t *testing.T) {
+	var (
+		ctx = context.Background()
+		uid = uuid.New()
+		sid = uuid.New()
+	)
+
+	s, mr := newTestSessions(t)
+
+	trefresh := newToken(sid)
+	if _, err := s.Create(ctx, uid, "device", "127.0.0.1", newToken(sid), trefresh); err != nil {
+		t.Fatal(err)
+	}
+
+	// * another session of the user is left as it is
+	other := uuid.New()
+	if _, err := s.Create(ctx, uid, "other", "127.0.0.1", newToken(other), newToken(other)); err != nil {
+		t.Fatal(err)
+	}
+
+	taccessnew, trefreshnew := newToken(sid), newToken(sid)
+	if _, err := s.Rotate(ctx, trefresh, "127.0.0.1", taccessnew, trefreshnew); err != nil {
+		t.Fatal(err)
+	}
+
+	// * the rotated token is replayed
+	_, err := s.Rotate(ctx, trefresh, "10.6.6.6", newToken(sid), newToken(sid))
+	if !errors.Is(err, consttypes.ErrRefreshTokenReused) {
+		t.Fatalf("reuse err = %v, want %v", err, consttypes.ErrRefreshTokenReused)
+	}
+
+	// * the whole session is gone, also for the party holding the latest pair
+	if mr.Exists(sessionKey(sid)) {
+		t.Error("session still exists after the reuse")
+	}
+
+	if _, err := s.Validate(ctx, taccessnew); !errors.Is(err, consttypes.ErrSessionRevoked) {
+		t.Errorf("validate latest access err = %v, want %v", err, consttypes.ErrSessionRevoked)
+	}
+
+	if _, err := s.Rotate(ctx, trefreshnew, "127.0.0.1", newToken(sid), newToken(sid)); !errors.Is(err, consttypes.ErrSessionRevoked) {
+		t.Errorf("rotate latest refresh err = %v, want %v", err, consttypes.ErrSessionRevoked)
+	}
+
+	if ok, _ := mr.SIsMember(userSessionsKey(uid), sid.String()); ok {
+		t.Error("revoked session is still listed for the user")
+	}
+
+	if !mr.Exists(sessionKey(other)) {
+		t.Error("the other session of the user was revoked")
+	}
+}
+
+func TestRotateConcurrent(t *testing.T) {
+	const (
+		refreshes = 8
+	)
+
+	var (
+		ctx = context.Background()
+		uid = uuid.New()
+		sid = uuid.New()
+	)
+
+	s, mr := newTestSessions(t)
+
+	trefresh := newToken(sid)
+	if _, err := s.Create(ctx, uid, "device", "127.0.0.1", newToken(sid), trefresh); err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		wg    sync.WaitGroup
+		start = make(chan struct{})
+		errs  = make(chan error, refreshes)
+	)
+
+	for i := 0; i < refreshes; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+
+			_, err := s.Rotate(ctx, trefresh, "127.0.0.1", newToken(sid), newToken(sid))
+			errs <- err
+		}()
+	}
+
+	close(start)
+	wg.Wait()
+	close(errs)
+
+	var (
+		rotated int
+	)
+
+	// * the first write wins, every other refresh with the same token
+	// * is a reuse, or finds the session already revoked by one
+	for err := range errs {
+		switch {
+		case err == nil:
+			rotated++
+		case errors.Is(err, consttypes.ErrRefreshTokenReused), errors.Is(err, consttypes.ErrSessionRevoked):
+		default:
+			t.Errorf("rotate err = %v, want a reuse", err)
+		}
+	}
+
+	if rotated != 1 {
+		t.Errorf("%d refreshes rotated the session, want 1", rotated)
+	}
+
+	if mr.Exists(sessionKey(sid)) {
+		t.Error("session still exists after the concurrent refreshes")
+	}
+}
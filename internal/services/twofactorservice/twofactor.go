@@ -0,0 +1,537 @@
+package twofactorservice
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"project-skbackend/configs"
+	"project-skbackend/internal/controllers/requests"
+	"project-skbackend/internal/controllers/responses"
+	"project-skbackend/internal/models"
+	"project-skbackend/internal/repositories/twofactorrepo"
+	"project-skbackend/internal/repositories/userrepo"
+	"project-skbackend/internal/services/securityservice"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/utils/utlogger"
+	"project-skbackend/packages/utils/utstring"
+	"project-skbackend/packages/utils/uttotp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+const (
+	challengeTokenLength = 32
+	recoveryCodeLength   = 10
+)
+
+type (
+	TwoFactorService struct {
+		cfg *configs.Config
+		rdb *redis.Client
+
+		// * repository
+		rtwof twofactorrepo.ITwoFactorRepository
+		ruser userrepo.IUserRepository
+
+		// * service
+		ssecu securityservice.ISecurityService
+	}
+
+	ITwoFactorService interface {
+		// * policies
+		Policy(ctx context.Context, role consttypes.UserRole) consttypes.TwoFactorPolicy
+		Status(ctx context.Context, uid uuid.UUID, role consttypes.UserRole) (*responses.TwoFactorStatus, error)
+
+		// * challenges
+		Challenge(ctx context.Context, user models.User) (*responses.TwoFactorChallenge, error)
+		VerifyChallenge(ctx context.Context, req requests.VerifyTwoFactor, ip string, useragent string) (uuid.UUID, error)
+		BeginChallengeEnrolment(ctx context.Context, req requests.TwoFactorChallenge) (*responses.TwoFactorEnrolment, error)
+		ConfirmChallengeEnrolment(ctx context.Context, req requests.ConfirmTwoFactorChallenge, ip string, useragent string) (uuid.UUID, *responses.TwoFactorRecoveryCodes, error)
+		Forget(ctx context.Context, uid uuid.UUID) error
+
+		// * enrolments
+		BeginEnrolment(ctx context.Context, uid uuid.UUID) (*responses.TwoFactorEnrolment, error)
+		ConfirmEnrolment(ctx context.Context, uid uuid.UUID, req requests.ConfirmTwoFactor, ip string, useragent string) (*responses.TwoFactorRecoveryCodes, error)
+		RegenerateRecoveryCodes(ctx context.Context, uid uuid.UUID, req requests.ConfirmTwoFactor) (*responses.TwoFactorRecoveryCodes, error)
+		Disable(ctx context.Context, uid uuid.UUID, role consttypes.UserRole, req requests.TwoFactorCode, ip string, useragent string) error
+	}
+)
+
+func NewTwoFactorService(
+	cfg *configs.Config,
+	rdb *redis.Client,
+	// * repository
+	rtwof twofactorrepo.ITwoFactorRepository,
+	ruser userrepo.IUserRepository,
+	// * service
+	ssecu securityservice.ISecurityService,
+) *TwoFactorService {
+	return &TwoFactorService{
+		cfg: cfg,
+		rdb: rdb,
+
+		// * repository
+		rtwof: rtwof,
+		ruser: ruser,
+
+		// * service
+		ssecu: ssecu,
+	}
+}
+
+// ! -------------------------------- policies -------------------------------- ! //
+// * only admins and partners can use two factor, each with its own policy,
+// * an invalid policy in the config falls back to the safest one
+func (s *TwoFactorService) Policy(ctx context.Context, role consttypes.UserRole) consttypes.TwoFactorPolicy {
+	var (
+		policy consttypes.TwoFactorPolicy
+	)
+
+	switch role {
+	case consttypes.UR_ADMIN:
+		policy = consttypes.TwoFactorPolicy(s.cfg.SecurityTwoFactor.AdminPolicy)
+		if !policy.IsValid() {
+			return consttypes.TFP_REQUIRED
+		}
+	case consttypes.UR_PARTNER:
+		policy = consttypes.TwoFactorPolicy(s.cfg.SecurityTwoFactor.PartnerPolicy)
+		if !policy.IsValid() {
+			return consttypes.TFP_OPTIONAL
+		}
+	default:
+		return consttypes.TFP_DISABLED
+	}
+
+	return policy
+}
+
+func (s *TwoFactorService) Status(ctx context.Context, uid uuid.UUID, role consttypes.UserRole) (*responses.TwoFactorStatus, error) {
+	tf, err := s.get(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	remaining := int64(0)
+	if tf.IsEnabled() {
+		remaining, err = s.rtwof.CountRecoveryCodes(ctx, uid)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return tf.ToStatusResponse(s.Policy(ctx, role), remaining), nil
+}
+
+// ! ------------------------------- challenges ------------------------------- ! //
+func challengeKey(token string) string {
+	return fmt.Sprintf("two_factor_challenge:%s", token)
+}
+
+// * returns the challenge the user has to pass after the password was
+// * verified, or nil when the user can get the tokens right away
+func (s *TwoFactorService) Challenge(ctx context.Context, user models.User) (*responses.TwoFactorChallenge, error) {
+	var (
+		tfct consttypes.TwoFactorChallengeType
+		life = time.Duration(s.cfg.SecurityTwoFactor.ChallengeLife) * time.Minute
+	)
+
+	policy := s.Policy(ctx, user.Role)
+	if policy == consttypes.TFP_DISABLED {
+		return nil, nil
+	}
+
+	tf, err := s.get(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case tf.IsEnabled():
+		tfct = consttypes.TFCT_VERIFY
+	case policy == consttypes.TFP_REQUIRED:
+		// * the user has to enrol before getting any token
+		tfct = consttypes.TFCT_ENROL
+	default:
+		return nil, nil
+	}
+
+	token, err := utstring.GenerateRandomToken(challengeTokenLength)
+	if err != nil {
+		return nil, consttypes.ErrFailedToGenerateToken.Wrap(err)
+	}
+
+	_, err = s.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, challengeKey(token), "user_id", user.ID.String(), "type", tfct.String(), "attempts", 0)
+		pipe.Expire(ctx, challengeKey(token), life)
+		return nil
+	})
+
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, consttypes.ErrFailedToCreateTwoFactorChallenge.Wrap(err)
+	}
+
+	return &responses.TwoFactorChallenge{
+		Type:           tfct,
+		ChallengeToken: token,
+		ExpiresAt:      consttypes.TimeNow().Add(life),
+	}, nil
+}
+
+func (s *TwoFactorService) VerifyChallenge(ctx context.Context, req requests.VerifyTwoFactor, ip string, useragent string) (uuid.UUID, error) {
+	uid, err := s.resolveChallenge(ctx, req.ChallengeToken, consttypes.TFCT_VERIFY)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	err = s.verify(ctx, uid, req.TwoFactorCode, ip, useragent)
+	if err != nil {
+		s.failChallenge(ctx, req.ChallengeToken)
+		return uuid.Nil, err
+	}
+
+	s.rdb.Del(ctx, challengeKey(req.ChallengeToken))
+
+	return uid, nil
+}
+
+func (s *TwoFactorService) BeginChallengeEnrolment(ctx context.Context, req requests.TwoFactorChallenge) (*responses.TwoFactorEnrolment, error) {
+	uid, err := s.resolveChallenge(ctx, req.ChallengeToken, consttypes.TFCT_ENROL)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.BeginEnrolment(ctx, uid)
+}
+
+func (s *TwoFactorService) ConfirmChallengeEnrolment(ctx context.Context, req requests.ConfirmTwoFactorChallenge, ip string, useragent string) (uuid.UUID, *responses.TwoFactorRecoveryCodes, error) {
+	uid, err := s.resolveChallenge(ctx, req.ChallengeToken, consttypes.TFCT_ENROL)
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+
+	rcres, err := s.ConfirmEnrolment(ctx, uid, req.ConfirmTwoFactor, ip, useragent)
+	if err != nil {
+		if errors.Is(err, consttypes.ErrTwoFactorCodeInvalid) {
+			s.failChallenge(ctx, req.ChallengeToken)
+		}
+
+		return uuid.Nil, nil, err
+	}
+
+	s.rdb.Del(ctx, challengeKey(req.ChallengeToken))
+
+	return uid, rcres, nil
+}
+
+func (s *TwoFactorService) resolveChallenge(ctx context.Context, token string, tfct consttypes.TwoFactorChallengeType) (uuid.UUID, error) {
+	values, err := s.rdb.HGetAll(ctx, challengeKey(token)).Result()
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return uuid.Nil, consttypes.ErrTwoFactorChallengeInvalid.Wrap(err)
+	}
+
+	if len(values) == 0 || values["type"] != tfct.String() {
+		return uuid.Nil, consttypes.ErrTwoFactorChallengeInvalid
+	}
+
+	uid, err := uuid.Parse(values["user_id"])
+	if err != nil {
+		return uuid.Nil, consttypes.ErrTwoFactorChallengeInvalid.Wrap(err)
+	}
+
+	return uid, nil
+}
+
+// * the challenge is dropped after too many wrong codes,
+// * the user has to sign in with the password again
+func (s *TwoFactorService) failChallenge(ctx context.Context, token string) {
+	attempts, err := s.rdb.HIncrBy(ctx, challengeKey(token), "attempts", 1).Result()
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return
+	}
+
+	if attempts >= int64(s.cfg.SecurityTwoFactor.ChallengeAttempts) {
+		s.rdb.Del(ctx, challengeKey(token))
+	}
+}
+
+// * drops the pending challenges of an erased user, the challenges are
+// * only keyed by their token so they are found by scanning them
+func (s *TwoFactorService) Forget(ctx context.Context, uid uuid.UUID) error {
+	var (
+		keys []string
+	)
+
+	iter := s.rdb.Scan(ctx, 0, challengeKey("*"), 0).Iterator()
+	for iter.Next(ctx) {
+		owner, err := s.rdb.HGet(ctx, iter.Val(), "user_id").Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			utlogger.ErrorContext(ctx, err)
+			return err
+		}
+
+		if owner == uid.String() {
+			keys = append(keys, iter.Val())
+		}
+	}
+
+	if err := iter.Err(); err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return err
+	}
+
+	if len(keys) == 0 {
+		return nil
+	}
+
+	err := s.rdb.Del(ctx, keys...).Err()
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return err
+	}
+
+	return nil
+}
+
+// ! ------------------------------- enrolments ------------------------------- ! //
+// * starts or restarts the enrolment with a new secret, the two factor
+// * is only enabled once a code of the new secret is confirmed
+func (s *TwoFactorService) BeginEnrolment(ctx context.Context, uid uuid.UUID) (*responses.TwoFactorEnrolment, error) {
+	user, err := s.ruser.GetByID(ctx, uid)
+	if err != nil {
+		return nil, consttypes.ErrUserNotFound.Wrap(err)
+	}
+
+	if s.Policy(ctx, user.Role) == consttypes.TFP_DISABLED {
+		return nil, consttypes.ErrTwoFactorNotAllowed
+	}
+
+	tf, err := s.get(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if tf.IsEnabled() {
+		return nil, consttypes.ErrTwoFactorAlreadyEnabled
+	}
+
+	key, err := uttotp.GenerateKey(s.cfg.SecurityTwoFactor.Issuer, user.Email)
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return nil, consttypes.ErrFailedToCreateTwoFactor.Wrap(err)
+	}
+
+	tf.UserID = user.ID
+	tf.Secret = key.Secret
+	tf.LastUsedStep = 0
+
+	_, err = s.rtwof.Save(ctx, *tf)
+	if err != nil {
+		return nil, consttypes.ErrFailedToCreateTwoFactor.Wrap(err)
+	}
+
+	return &responses.TwoFactorEnrolment{
+		Secret: key.Secret,
+		URI:    key.URI,
+		QRCode: key.QRCode,
+	}, nil
+}
+
+func (s *TwoFactorService) ConfirmEnrolment(ctx context.Context, uid uuid.UUID, req requests.ConfirmTwoFactor, ip string, useragent string) (*responses.TwoFactorRecoveryCodes, error) {
+	tf, err := s.get(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	if tf.IsEnabled() {
+		return nil, consttypes.ErrTwoFactorAlreadyEnabled
+	}
+
+	if tf.Secret == "" {
+		return nil, consttypes.ErrTwoFactorNotEnrolled
+	}
+
+	step, ok := uttotp.Validate(tf.Secret, req.Code, consttypes.TimeNow())
+	if !ok {
+		return nil, consttypes.ErrTwoFactorCodeInvalid
+	}
+
+	codes, hashes, err := s.generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	now := consttypes.TimeNow()
+	tf.EnabledAt = &now
+	tf.LastUsedStep = step
+
+	_, err = s.rtwof.Enable(ctx, *tf, hashes)
+	if err != nil {
+		return nil, consttypes.ErrFailedToUpdateTwoFactor.Wrap(err)
+	}
+
+	s.ssecu.RecordEvent(ctx, *models.NewSecurityEvent(consttypes.SET_TWO_FACTOR_ENABLED, &uid, "", ip, useragent, ""))
+
+	return &responses.TwoFactorRecoveryCodes{
+		RecoveryCodes: codes,
+	}, nil
+}
+
+func (s *TwoFactorService) RegenerateRecoveryCodes(ctx context.Context, uid uuid.UUID, req requests.ConfirmTwoFactor) (*responses.TwoFactorRecoveryCodes, error) {
+	err := s.verifyCode(ctx, uid, req.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := s.generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.rtwof.ReplaceRecoveryCodes(ctx, uid, hashes)
+	if err != nil {
+		return nil, consttypes.ErrFailedToGenerateRecoveryCodes.Wrap(err)
+	}
+
+	return &responses.TwoFactorRecoveryCodes{
+		RecoveryCodes: codes,
+	}, nil
+}
+
+func (s *TwoFactorService) Disable(ctx context.Context, uid uuid.UUID, role consttypes.UserRole, req requests.TwoFactorCode, ip string, useragent string) error {
+	if s.Policy(ctx, role) == consttypes.TFP_REQUIRED {
+		return consttypes.ErrTwoFactorRequired
+	}
+
+	err := s.verify(ctx, uid, req, ip, useragent)
+	if err != nil {
+		return err
+	}
+
+	err = s.rtwof.Delete(ctx, uid)
+	if err != nil {
+		return consttypes.ErrFailedToDeleteTwoFactor.Wrap(err)
+	}
+
+	s.ssecu.RecordEvent(ctx, *models.NewSecurityEvent(consttypes.SET_TWO_FACTOR_DISABLED, &uid, "", ip, useragent, ""))
+
+	return nil
+}
+
+// ! ------------------------------ verifications ----------------------------- ! //
+// * accepts either a code of the authenticator app or an unused recovery code
+func (s *TwoFactorService) verify(ctx context.Context, uid uuid.UUID, req requests.TwoFactorCode, ip string, useragent string) error {
+	var (
+		err error
+	)
+
+	switch {
+	case req.Code != "":
+		err = s.verifyCode(ctx, uid, req.Code)
+	case req.RecoveryCode != "":
+		err = s.verifyRecoveryCode(ctx, uid, req.RecoveryCode)
+		if err == nil {
+			s.ssecu.RecordEvent(ctx, *models.NewSecurityEvent(consttypes.SET_RECOVERY_CODE_USED, &uid, "", ip, useragent, ""))
+		}
+	default:
+		return consttypes.ErrTwoFactorCodeRequired
+	}
+
+	if errors.Is(err, consttypes.ErrTwoFactorCodeInvalid) {
+		s.ssecu.RecordEvent(ctx, *models.NewSecurityEvent(consttypes.SET_TWO_FACTOR_FAILED, &uid, "", ip, useragent, ""))
+	}
+
+	return err
+}
+
+func (s *TwoFactorService) verifyCode(ctx context.Context, uid uuid.UUID, code string) error {
+	tf, err := s.get(ctx, uid)
+	if err != nil {
+		return err
+	}
+
+	if !tf.IsEnabled() {
+		return consttypes.ErrTwoFactorNotEnabled
+	}
+
+	step, ok := uttotp.Validate(tf.Secret, code, consttypes.TimeNow())
+	if !ok {
+		return consttypes.ErrTwoFactorCodeInvalid
+	}
+
+	// * a code can only be used once, even within its time step
+	ok, err = s.rtwof.UseStep(ctx, uid, step)
+	if err != nil {
+		return consttypes.ErrFailedToUpdateTwoFactor.Wrap(err)
+	}
+
+	if !ok {
+		return consttypes.ErrTwoFactorCodeInvalid
+	}
+
+	return nil
+}
+
+func (s *TwoFactorService) verifyRecoveryCode(ctx context.Context, uid uuid.UUID, code string) error {
+	ok, err := s.rtwof.UseRecoveryCode(ctx, uid, hashRecoveryCode(code))
+	if err != nil {
+		return consttypes.ErrFailedToUpdateTwoFactor.Wrap(err)
+	}
+
+	if !ok {
+		return consttypes.ErrTwoFactorCodeInvalid
+	}
+
+	return nil
+}
+
+func (s *TwoFactorService) generateRecoveryCodes() ([]string, []string, error) {
+	var (
+		codes  []string
+		hashes []string
+	)
+
+	for i := 0; i < s.cfg.SecurityTwoFactor.RecoveryCodes; i++ {
+		code, err := utstring.GenerateRandomToken(recoveryCodeLength)
+		if err != nil {
+			return nil, nil, consttypes.ErrFailedToGenerateRecoveryCodes.Wrap(err)
+		}
+
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+
+	return codes, hashes, nil
+}
+
+// * the recovery codes are random enough that a plain sha256 is
+// * sufficient, it also lets the code be looked up by its hash
+func hashRecoveryCode(code string) string {
+	code = strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	hash := sha256.Sum256([]byte(code))
+
+	return hex.EncodeToString(hash[:])
+}
+
+// * the two factor of the user, an empty one when the user never enrolled
+func (s *TwoFactorService) get(ctx context.Context, uid uuid.UUID) (*models.UserTwoFactor, error) {
+	tf, err := s.rtwof.GetByUserID(ctx, uid)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &models.UserTwoFactor{UserID: uid}, nil
+		}
+
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	return tf, nil
+}
@@ -0,0 +1,336 @@
+package twofactorservice
+
+import (
+	"context"
+	"errors"
+	"project-skbackend/configs"
+	"project-skbackend/internal/controllers/requests"
+	"project-skbackend/internal/models"
+	"project-skbackend/internal/models/base"
+	"project-skbackend/internal/repositories/twofactorrepo"
+	"project-skbackend/internal/services/securityservice"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/utils/uttotp"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+type (
+	// * the steps and recovery codes are used like the repository does,
+	// * only when they were not used before
+	fakeTwoFactors struct {
+		twofactorrepo.ITwoFactorRepository
+
+		tfs   map[uuid.UUID]models.UserTwoFactor
+		codes map[string]bool
+	}
+
+	fakeSecurity struct {
+		securityservice.ISecurityService
+
+		events []consttypes.SecurityEventType
+	}
+
+	testTwoFactor struct {
+		s     *TwoFactorService
+		mr    *miniredis.Miniredis
+		rtwof *fakeTwoFactors
+		ssecu *fakeSecurity
+	}
+)
+
+func (f *fakeTwoFactors) GetByUserID(ctx context.Context, uid uuid.UUID) (*models.UserTwoFactor, error) {
+	if tf, ok := f.tfs[uid]; ok {
+		return &tf, nil
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeTwoFactors) Save(ctx context.Context, tf models.UserTwoFactor) (*models.UserTwoFactor, error) {
+	f.tfs[tf.UserID] = tf
+	return &tf, nil
+}
+
+func (f *fakeTwoFactors) Enable(ctx context.Context, tf models.UserTwoFactor, codehashes []string) (*models.UserTwoFactor, error) {
+	f.tfs[tf.UserID] = tf
+	return &tf, f.ReplaceRecoveryCodes(ctx, tf.UserID, codehashes)
+}
+
+func (f *fakeTwoFactors) ReplaceRecoveryCodes(ctx context.Context, uid uuid.UUID, codehashes []string) error {
+	f.codes = map[string]bool{}
+	for _, hash := range codehashes {
+		f.codes[hash] = false
+	}
+
+	return nil
+}
+
+func (f *fakeTwoFactors) UseStep(ctx context.Context, uid uuid.UUID, step int64) (bool, error) {
+	tf := f.tfs[uid]
+	if tf.LastUsedStep >= step {
+		return false, nil
+	}
+
+	tf.LastUsedStep = step
+	f.tfs[uid] = tf
+
+	return true, nil
+}
+
+func (f *fakeTwoFactors) UseRecoveryCode(ctx context.Context, uid uuid.UUID, codehash string) (bool, error) {
+	used, ok := f.codes[codehash]
+	if !ok || used {
+		return false, nil
+	}
+
+	f.codes[codehash] = true
+
+	return true, nil
+}
+
+func (f *fakeSecurity) RecordEvent(ctx context.Context, se models.SecurityEvent) {
+	f.events = append(f.events, se.Type)
+}
+
+func newTestTwoFactor(t *testing.T) *testTwoFactor {
+	cfg := &configs.Config{}
+	cfg.SecurityTwoFactor.Issuer = "meals-app"
+	cfg.SecurityTwoFactor.ChallengeLife = 5
+	cfg.SecurityTwoFactor.ChallengeAttempts = 3
+	cfg.SecurityTwoFactor.RecoveryCodes = 4
+	cfg.SecurityTwoFactor.AdminPolicy = consttypes.TFP_REQUIRED.String()
+
+	mr := miniredis.RunT(t)
+
+	tt := &testTwoFactor{
+		mr:    mr,
+		rtwof: &fakeTwoFactors{tfs: map[uuid.UUID]models.UserTwoFactor{}, codes: map[string]bool{}},
+		ssecu: &fakeSecurity{},
+	}
+
+	tt.s = NewTwoFactorService(cfg, redis.NewClient(&redis.Options{Addr: mr.Addr()}), tt.rtwof, nil, tt.ssecu)
+
+	return tt
+}
+
+func code(t *testing.T, secret string, at time.Time) string {
+	t.Helper()
+
+	c, err := totp.GenerateCodeCustom(secret, at, totp.ValidateOpts{
+		Period:    30,
+		Digits:    otp.DigitsSix,
+		Algorithm: otp.AlgorithmSHA1,
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+// * a code that is not valid around the current time
+func wrongCode(t *testing.T, secret string) string {
+	t.Helper()
+
+	for _, c := range []string{"000000", "111111", "222222", "333333"} {
+		if _, ok := uttotp.Validate(secret, c, consttypes.TimeNow()); !ok {
+			return c
+		}
+	}
+
+	t.Fatal("no wrong code found")
+	return ""
+}
+
+// * enrols an admin with the code of the current step, which is then
+// * already used, and returns the admin with the recovery codes
+func (tt *testTwoFactor) enrol(t *testing.T) (models.User, string, []string) {
+	t.Helper()
+
+	key, err := uttotp.GenerateKey("meals-app", "admin@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user := models.User{Model: base.Model{ID: uuid.New()}, Email: "admin@example.com", Role: consttypes.UR_ADMIN}
+	tt.rtwof.tfs[user.ID] = models.UserTwoFactor{UserID: user.ID, Secret: key.Secret}
+
+	rcres, err := tt.s.ConfirmEnrolment(context.Background(), user.ID, requests.ConfirmTwoFactor{Code: code(t, key.Secret, consttypes.TimeNow())}, "127.0.0.1", "test")
+	if err != nil {
+		t.Fatalf("confirm enrolment err = %v", err)
+	}
+
+	if len(rcres.RecoveryCodes) != 4 {
+		t.Fatalf("%d recovery codes, want 4", len(rcres.RecoveryCodes))
+	}
+
+	return user, key.Secret, rcres.RecoveryCodes
+}
+
+func (tt *testTwoFactor) challenge(t *testing.T, user models.User) string {
+	t.Helper()
+
+	tfcres, err := tt.s.Challenge(context.Background(), user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if tfcres == nil || tfcres.Type != consttypes.TFCT_VERIFY {
+		t.Fatalf("challenge = %+v, want a verify challenge", tfcres)
+	}
+
+	return tfcres.ChallengeToken
+}
+
+func (tt *testTwoFactor) verify(user models.User, token string, tfc requests.TwoFactorCode) (uuid.UUID, error) {
+	return tt.s.VerifyChallenge(context.Background(), requests.VerifyTwoFactor{
+		TwoFactorChallenge: requests.TwoFactorChallenge{ChallengeToken: token},
+		TwoFactorCode:      tfc,
+	}, "127.0.0.1", "test")
+}
+
+func TestVerifyChallengeRejectsReusedStep(t *testing.T) {
+	tt := newTestTwoFactor(t)
+	user, secret, _ := tt.enrol(t)
+
+	// * the code that confirmed the enrolment can not sign in
+	enrolled := code(t, secret, time.Unix(tt.rtwof.tfs[user.ID].LastUsedStep*30, 0))
+
+	token := tt.challenge(t, user)
+	if _, err := tt.verify(user, token, requests.TwoFactorCode{Code: enrolled}); !errors.Is(err, consttypes.ErrTwoFactorCodeInvalid) {
+		t.Fatalf("reused step err = %v, want %v", err, consttypes.ErrTwoFactorCodeInvalid)
+	}
+
+	// * the code of the next step is still inside the window
+	next := code(t, secret, consttypes.TimeNow().Add(30*time.Second))
+
+	uid, err := tt.verify(user, token, requests.TwoFactorCode{Code: next})
+	if err != nil || uid != user.ID {
+		t.Fatalf("next step: uid = %v, err = %v, want %v", uid, err, user.ID)
+	}
+
+	if tt.mr.Exists(challengeKey(token)) {
+		t.Error("challenge still exists after it was passed")
+	}
+
+	// * and neither that code nor an older one can be used again
+	token = tt.challenge(t, user)
+	for name, c := range map[string]string{
+		"same step":     next,
+		"previous step": enrolled,
+	} {
+		if _, err := tt.verify(user, token, requests.TwoFactorCode{Code: c}); !errors.Is(err, consttypes.ErrTwoFactorCodeInvalid) {
+			t.Errorf("%s: err = %v, want %v", name, err, consttypes.ErrTwoFactorCodeInvalid)
+		}
+	}
+}
+
+func TestVerifyChallengeAttemptLimit(t *testing.T) {
+	tt := newTestTwoFactor(t)
+	user, secret, _ := tt.enrol(t)
+
+	token := tt.challenge(t, user)
+	wrong := wrongCode(t, secret)
+
+	for i := 1; i <= 3; i++ {
+		if _, err := tt.verify(user, token, requests.TwoFactorCode{Code: wrong}); !errors.Is(err, consttypes.ErrTwoFactorCodeInvalid) {
+			t.Fatalf("attempt %d: err = %v, want %v", i, err, consttypes.ErrTwoFactorCodeInvalid)
+		}
+
+		if exists := tt.mr.Exists(challengeKey(token)); exists != (i < 3) {
+			t.Errorf("attempt %d: challenge exists = %v, want %v", i, exists, i < 3)
+		}
+	}
+
+	// * the right code does not pass the dropped challenge anymore
+	next := code(t, secret, consttypes.TimeNow().Add(30*time.Second))
+	if _, err := tt.verify(user, token, requests.TwoFactorCode{Code: next}); !errors.Is(err, consttypes.ErrTwoFactorChallengeInvalid) {
+		t.Errorf("dropped challenge err = %v, want %v", err, consttypes.ErrTwoFactorChallengeInvalid)
+	}
+
+	var (
+		failed int
+	)
+
+	for _, set := range tt.ssecu.events {
+		if set == consttypes.SET_TWO_FACTOR_FAILED {
+			failed++
+		}
+	}
+
+	if failed != 3 {
+		t.Errorf("%d failed events recorded, want 3", failed)
+	}
+}
+
+func TestVerifyChallengeRejectsOtherType(t *testing.T) {
+	tt := newTestTwoFactor(t)
+
+	// * an admin that did not enrol yet only gets an enrol challenge
+	user := models.User{Model: base.Model{ID: uuid.New()}, Email: "admin@example.com", Role: consttypes.UR_ADMIN}
+
+	tfcres, err := tt.s.Challenge(context.Background(), user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if tfcres == nil || tfcres.Type != consttypes.TFCT_ENROL {
+		t.Fatalf("challenge = %+v, want an enrol challenge", tfcres)
+	}
+
+	if _, err := tt.verify(user, tfcres.ChallengeToken, requests.TwoFactorCode{Code: "000000"}); !errors.Is(err, consttypes.ErrTwoFactorChallengeInvalid) {
+		t.Errorf("enrol challenge err = %v, want %v", err, consttypes.ErrTwoFactorChallengeInvalid)
+	}
+
+	if _, err := tt.verify(user, "unknown", requests.TwoFactorCode{Code: "000000"}); !errors.Is(err, consttypes.ErrTwoFactorChallengeInvalid) {
+		t.Errorf("unknown challenge err = %v, want %v", err, consttypes.ErrTwoFactorChallengeInvalid)
+	}
+}
+
+func TestRecoveryCodeSingleUse(t *testing.T) {
+	tt := newTestTwoFactor(t)
+	user, _, codes := tt.enrol(t)
+
+	token := tt.challenge(t, user)
+	if uid, err := tt.verify(user, token, requests.TwoFactorCode{RecoveryCode: codes[0]}); err != nil || uid != user.ID {
+		t.Fatalf("first use: uid = %v, err = %v, want %v", uid, err, user.ID)
+	}
+
+	if n := len(tt.ssecu.events); n == 0 || tt.ssecu.events[n-1] != consttypes.SET_RECOVERY_CODE_USED {
+		t.Errorf("events = %v, want the recovery code use recorded", tt.ssecu.events)
+	}
+
+	token = tt.challenge(t, user)
+	if _, err := tt.verify(user, token, requests.TwoFactorCode{RecoveryCode: codes[0]}); !errors.Is(err, consttypes.ErrTwoFactorCodeInvalid) {
+		t.Errorf("second use err = %v, want %v", err, consttypes.ErrTwoFactorCodeInvalid)
+	}
+
+	// * the other codes are left as they are
+	if _, err := tt.verify(user, token, requests.TwoFactorCode{RecoveryCode: codes[1]}); err != nil {
+		t.Errorf("other code err = %v", err)
+	}
+
+	// * regenerating the codes drops the unused ones
+	rcres, err := tt.s.RegenerateRecoveryCodes(context.Background(), user.ID, requests.ConfirmTwoFactor{Code: code(t, tt.rtwof.tfs[user.ID].Secret, consttypes.TimeNow().Add(30*time.Second))})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token = tt.challenge(t, user)
+	if _, err := tt.verify(user, token, requests.TwoFactorCode{RecoveryCode: codes[2]}); !errors.Is(err, consttypes.ErrTwoFactorCodeInvalid) {
+		t.Errorf("replaced code err = %v, want %v", err, consttypes.ErrTwoFactorCodeInvalid)
+	}
+
+	if _, err := tt.verify(user, token, requests.TwoFactorCode{RecoveryCode: rcres.RecoveryCodes[0]}); err != nil {
+		t.Errorf("new code err = %v", err)
+	}
+}
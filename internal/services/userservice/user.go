@@ -1,6 +1,7 @@
 package userservice
 
 import (
+	"context"
 	"encoding/json"
 	"project-skbackend/internal/controllers/requests"
 	"project-skbackend/internal/controllers/responses"
@@ -32,15 +33,15 @@ type (
 	}
 
 	IUserService interface {
-		Create(req requests.CreateUser) (*responses.User, error)
-		GetByID(uid uuid.UUID) (*responses.User, error)
-		FindAll(p utpagination.Pagination) (*utpagination.Pagination, error)
-		Delete(uid uuid.UUID) error
-		Update(req requests.UpdateUser, uid uuid.UUID) (*responses.User, error)
-		GetUserName(uid uuid.UUID) (string, string, error)
-		GetRoleDataByUserID(uid uuid.UUID) (*responses.BaseRole, error)
-
-		UpdateOwnPassword(uid uuid.UUID, req requests.UpdatePassword) error
+		Create(ctx context.Context, req requests.CreateUser) (*responses.User, error)
+		GetByID(ctx context.Context, uid uuid.UUID) (*responses.User, error)
+		FindAll(ctx context.Context, p utpagination.Pagination) (*utpagination.Pagination, error)
+		Delete(ctx context.Context, uid uuid.UUID) error
+		Update(ctx context.Context, req requests.UpdateUser, uid uuid.UUID) (*responses.User, error)
+		GetUserName(ctx context.Context, uid uuid.UUID) (string, string, error)
+		GetRoleDataByUserID(ctx context.Context, uid uuid.UUID) (*responses.BaseRole, error)
+
+		UpdateOwnPassword(ctx context.Context, uid uuid.UUID, req requests.UpdatePassword) error
 	}
 )
 
@@ -64,7 +65,7 @@ func NewUserService(
 	}
 }
 
-func (s *UserService) Create(req requests.CreateUser) (*responses.User, error) {
+func (s *UserService) Create(ctx context.Context, req requests.CreateUser) (*responses.User, error) {
 	var (
 		ures *responses.User
 	)
@@ -74,7 +75,7 @@ func (s *UserService) Create(req requests.CreateUser) (*responses.User, error) {
 		Password: req.Password,
 	}
 
-	u, err := s.ruser.Create(*u)
+	u, err := s.ruser.Create(ctx, *u)
 	if err != nil {
 		return nil, err
 	}
@@ -88,8 +89,8 @@ func (s *UserService) Create(req requests.CreateUser) (*responses.User, error) {
 	return ures, err
 }
 
-func (s *UserService) GetByID(uid uuid.UUID) (*responses.User, error) {
-	u, err := s.ruser.GetByID(uid)
+func (s *UserService) GetByID(ctx context.Context, uid uuid.UUID) (*responses.User, error) {
+	u, err := s.ruser.GetByID(ctx, uid)
 	if err != nil {
 		return nil, err
 	}
@@ -102,8 +103,8 @@ func (s *UserService) GetByID(uid uuid.UUID) (*responses.User, error) {
 	return ures, err
 }
 
-func (s *UserService) FindAll(p utpagination.Pagination) (*utpagination.Pagination, error) {
-	users, err := s.ruser.FindAll(p)
+func (s *UserService) FindAll(ctx context.Context, p utpagination.Pagination) (*utpagination.Pagination, error) {
+	users, err := s.ruser.FindAll(ctx, p)
 	if err != nil {
 		return nil, err
 	}
@@ -111,12 +112,12 @@ func (s *UserService) FindAll(p utpagination.Pagination) (*utpagination.Paginati
 	return users, nil
 }
 
-func (s *UserService) Delete(uid uuid.UUID) error {
+func (s *UserService) Delete(ctx context.Context, uid uuid.UUID) error {
 	u := models.User{
 		Model: base.Model{ID: uid},
 	}
 
-	err := s.ruser.Delete(u)
+	err := s.ruser.Delete(ctx, u)
 	if err != nil {
 		return err
 	}
@@ -124,11 +125,11 @@ func (s *UserService) Delete(uid uuid.UUID) error {
 	return nil
 }
 
-func (s *UserService) Update(
+func (s *UserService) Update(ctx context.Context,
 	req requests.UpdateUser,
 	uid uuid.UUID,
 ) (*responses.User, error) {
-	u, err := s.ruser.GetByID(uid)
+	u, err := s.ruser.GetByID(ctx, uid)
 	if err != nil {
 		return nil, err
 	}
@@ -138,7 +139,7 @@ func (s *UserService) Update(
 		return nil, err
 	}
 
-	u, err = s.ruser.Update(*u)
+	u, err = s.ruser.Update(ctx, *u)
 	if err != nil {
 		return nil, err
 	}
@@ -151,20 +152,20 @@ func (s *UserService) Update(
 	return ures, err
 }
 
-func (s *UserService) GetUserName(uid uuid.UUID) (string, string, error) {
+func (s *UserService) GetUserName(ctx context.Context, uid uuid.UUID) (string, string, error) {
 	var (
 		firstname string = ""
 		lastname  string = ""
 	)
 
-	user, err := s.ruser.GetByID(uid)
+	user, err := s.ruser.GetByID(ctx, uid)
 	if err != nil {
 		return "", "", err
 	}
 
 	switch user.Role {
 	case consttypes.UR_ADMIN:
-		a, err := s.radmn.GetByUserID(uid)
+		a, err := s.radmn.GetByUserID(ctx, uid)
 		if err != nil {
 			return "", "", err
 		}
@@ -172,7 +173,7 @@ func (s *UserService) GetUserName(uid uuid.UUID) (string, string, error) {
 		firstname = a.FirstName
 		lastname = a.LastName
 	case consttypes.UR_CAREGIVER:
-		c, err := s.rcare.GetByUserID(uid)
+		c, err := s.rcare.GetByUserID(ctx, uid)
 		if err != nil {
 			return "", "", err
 		}
@@ -180,7 +181,7 @@ func (s *UserService) GetUserName(uid uuid.UUID) (string, string, error) {
 		firstname = c.FirstName
 		lastname = c.LastName
 	case consttypes.UR_MEMBER:
-		m, err := s.rmemb.GetByUserID(uid)
+		m, err := s.rmemb.GetByUserID(ctx, uid)
 		if err != nil {
 			return "", "", err
 		}
@@ -188,21 +189,21 @@ func (s *UserService) GetUserName(uid uuid.UUID) (string, string, error) {
 		firstname = m.FirstName
 		lastname = m.LastName
 	case consttypes.UR_ORGANIZATION:
-		o, err := s.rorga.GetByUserID(uid)
+		o, err := s.rorga.GetByUserID(ctx, uid)
 		if err != nil {
 			return "", "", err
 		}
 
 		firstname = o.Name
 	case consttypes.UR_PARTNER:
-		p, err := s.rpart.GetByUserID(uid)
+		p, err := s.rpart.GetByUserID(ctx, uid)
 		if err != nil {
 			return "", "", err
 		}
 
 		firstname = p.Name
 	case consttypes.UR_PATRON:
-		p, err := s.rpatr.GetByUserID(uid)
+		p, err := s.rpatr.GetByUserID(ctx, uid)
 		if err != nil {
 			return "", "", err
 		}
@@ -215,54 +216,54 @@ func (s *UserService) GetUserName(uid uuid.UUID) (string, string, error) {
 	return firstname, lastname, nil
 }
 
-func (s *UserService) GetRoleDataByUserID(uid uuid.UUID) (*responses.BaseRole, error) {
+func (s *UserService) GetRoleDataByUserID(ctx context.Context, uid uuid.UUID) (*responses.BaseRole, error) {
 	var (
 		data any
 	)
 
-	user, err := s.ruser.GetByID(uid)
+	user, err := s.ruser.GetByID(ctx, uid)
 	if err != nil {
 		return nil, err
 	}
 
 	switch user.Role {
 	case consttypes.UR_ADMIN:
-		a, err := s.radmn.GetByUserID(user.ID)
+		a, err := s.radmn.GetByUserID(ctx, user.ID)
 		if err != nil {
 			return nil, err
 		}
 
 		data = a
 	case consttypes.UR_CAREGIVER:
-		c, err := s.rcare.GetByUserID(user.ID)
+		c, err := s.rcare.GetByUserID(ctx, user.ID)
 		if err != nil {
 			return nil, err
 		}
 
 		data = c
 	case consttypes.UR_MEMBER:
-		m, err := s.rmemb.GetByUserID(user.ID)
+		m, err := s.rmemb.GetByUserID(ctx, user.ID)
 		if err != nil {
 			return nil, err
 		}
 
 		data = m
 	case consttypes.UR_ORGANIZATION:
-		o, err := s.rorga.GetByUserID(user.ID)
+		o, err := s.rorga.GetByUserID(ctx, user.ID)
 		if err != nil {
 			return nil, err
 		}
 
 		data = o
 	case consttypes.UR_PARTNER:
-		p, err := s.rpart.GetByUserID(user.ID)
+		p, err := s.rpart.GetByUserID(ctx, user.ID)
 		if err != nil {
 			return nil, err
 		}
 
 		data = p
 	case consttypes.UR_PATRON:
-		p, err := s.rpatr.GetByUserID(user.ID)
+		p, err := s.rpatr.GetByUserID(ctx, user.ID)
 		if err != nil {
 			return nil, err
 		}
@@ -278,8 +279,8 @@ func (s *UserService) GetRoleDataByUserID(uid uuid.UUID) (*responses.BaseRole, e
 	}, nil
 }
 
-func (s *UserService) UpdateOwnPassword(uid uuid.UUID, req requests.UpdatePassword) error {
-	user, err := s.ruser.GetByID(uid)
+func (s *UserService) UpdateOwnPassword(ctx context.Context, uid uuid.UUID, req requests.UpdatePassword) error {
+	user, err := s.ruser.GetByID(ctx, uid)
 	if err != nil {
 		return err
 	}
@@ -289,7 +290,7 @@ func (s *UserService) UpdateOwnPassword(uid uuid.UUID, req requests.UpdatePasswo
 		return consttypes.ErrInvalidEmailOrPassword
 	}
 
-	_, err = s.ruser.UpdatePassword(uid, req.NewPassword)
+	_, err = s.ruser.UpdatePassword(ctx, uid, req.NewPassword)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,586 @@
+package webhookservice
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"project-skbackend/configs"
+	"project-skbackend/internal/controllers/requests"
+	"project-skbackend/internal/controllers/responses"
+	"project-skbackend/internal/models"
+	"project-skbackend/internal/repositories/webhookrepo"
+	"project-skbackend/internal/services/producerservice"
+	"project-skbackend/packages/consttypes"
+	"project-skbackend/packages/tracing"
+	"project-skbackend/packages/utils/utlogger"
+	"project-skbackend/packages/utils/utnetwork"
+	"project-skbackend/packages/utils/utpagination"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	secretPrefix      = "whsec_"
+	secretLength      = 32
+	responseBodyLimit = 1024
+	userAgent         = "meals-webhook/1.0"
+)
+
+type (
+	WebhookService struct {
+		cfg    *configs.Config
+		client *http.Client
+
+		// * repository
+		rwebh webhookrepo.IWebhookRepository
+
+		// * service
+		sprod producerservice.IProducerService
+	}
+
+	IWebhookService interface {
+		// * webhooks
+		Create(ctx context.Context, uid uuid.UUID, req requests.CreateWebhook) (*responses.WebhookCreated, error)
+		Read(ctx context.Context, uid uuid.UUID) ([]*responses.Webhook, error)
+		GetByID(ctx context.Context, uid uuid.UUID, wid uuid.UUID) (*responses.Webhook, error)
+		Update(ctx context.Context, uid uuid.UUID, wid uuid.UUID, req requests.UpdateWebhook) (*responses.Webhook, error)
+		Delete(ctx context.Context, uid uuid.UUID, wid uuid.UUID) error
+
+		// * deliveries
+		Ping(ctx context.Context, uid uuid.UUID, wid uuid.UUID) (*responses.WebhookDelivery, error)
+		FindDeliveries(ctx context.Context, uid uuid.UUID, wid uuid.UUID, p utpagination.Pagination) (*utpagination.Pagination, error)
+		Redeliver(ctx context.Context, uid uuid.UUID, wid uuid.UUID, did uuid.UUID) (*responses.WebhookDelivery, error)
+		Deliver(ctx context.Context, did uuid.UUID) error
+		RetryDue(ctx context.Context) (int, error)
+
+		// * events
+		DispatchOrder(ctx context.Context, wet consttypes.WebhookEventType, order models.Order, previous consttypes.OrderStatus)
+	}
+)
+
+func NewWebhookService(
+	cfg *configs.Config,
+	// * repository
+	rwebh webhookrepo.IWebhookRepository,
+	// * service
+	sprod producerservice.IProducerService,
+) *WebhookService {
+	return &WebhookService{
+		cfg: cfg,
+		client: &http.Client{
+			Transport: tracing.NewTransport("webhook", newTransport(cfg)),
+			Timeout:   time.Duration(cfg.Webhook.Timeout) * time.Second,
+			// * a redirect could point the signed payload somewhere else
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+
+		// * repository
+		rwebh: rwebh,
+
+		// * service
+		sprod: sprod,
+	}
+}
+
+// * the endpoints are given by the users, so a delivery may only connect
+// * to a public address. the proxy is not used, it would connect instead
+func newTransport(cfg *configs.Config) http.RoundTripper {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.Webhook.AllowPrivate {
+		return transport
+	}
+
+	transport.Proxy = nil
+	transport.DialContext = utnetwork.PublicDialer(time.Duration(cfg.Webhook.Timeout) * time.Second).DialContext
+
+	return transport
+}
+
+// ! -------------------------------- webhooks -------------------------------- ! //
+func (s *WebhookService) Create(ctx context.Context, uid uuid.UUID, req requests.CreateWebhook) (*responses.WebhookCreated, error) {
+	var (
+		secret = req.Secret
+	)
+
+	if err := s.validateURL(req.URL); err != nil {
+		return nil, err
+	}
+
+	if secret == "" {
+		bsecret := make([]byte, secretLength)
+		if _, err := rand.Read(bsecret); err != nil {
+			utlogger.ErrorContext(ctx, err)
+			return nil, consttypes.ErrFailedToGenerateToken.Wrap(err)
+		}
+
+		secret = secretPrefix + base64.RawURLEncoding.EncodeToString(bsecret)
+	}
+
+	wh, err := s.rwebh.Create(ctx, models.Webhook{
+		UserID:      uid,
+		URL:         req.URL,
+		Description: req.Description,
+		EventTypes:  req.EventTypes,
+		Secret:      secret,
+		Active:      true,
+	})
+
+	if err != nil {
+		return nil, consttypes.ErrFailedToCreateWebhook.Wrap(err)
+	}
+
+	whres, err := wh.ToResponse()
+	if err != nil {
+		return nil, consttypes.ErrConvertFailed.Wrap(err)
+	}
+
+	return &responses.WebhookCreated{
+		Webhook: *whres,
+		Secret:  secret,
+	}, nil
+}
+
+func (s *WebhookService) Read(ctx context.Context, uid uuid.UUID) ([]*responses.Webhook, error) {
+	var (
+		whsres []*responses.Webhook
+	)
+
+	whs, err := s.rwebh.FindByUserID(ctx, uid)
+	if err != nil {
+		return nil, consttypes.ErrFailedToReadWebhooks.Wrap(err)
+	}
+
+	for _, wh := range whs {
+		whres, err := wh.ToResponse()
+		if err != nil {
+			return nil, consttypes.ErrConvertFailed.Wrap(err)
+		}
+
+		whsres = append(whsres, whres)
+	}
+
+	return whsres, nil
+}
+
+func (s *WebhookService) GetByID(ctx context.Context, uid uuid.UUID, wid uuid.UUID) (*responses.Webhook, error) {
+	wh, err := s.get(ctx, uid, wid)
+	if err != nil {
+		return nil, err
+	}
+
+	whres, err := wh.ToResponse()
+	if err != nil {
+		return nil, consttypes.ErrConvertFailed.Wrap(err)
+	}
+
+	return whres, nil
+}
+
+func (s *WebhookService) Update(ctx context.Context, uid uuid.UUID, wid uuid.UUID, req requests.UpdateWebhook) (*responses.Webhook, error) {
+	wh, err := s.get(ctx, uid, wid)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validateURL(req.URL); err != nil {
+		return nil, err
+	}
+
+	wh.URL = req.URL
+	wh.Description = req.Description
+	wh.EventTypes = req.EventTypes
+	wh.Active = req.Active
+
+	wh, err = s.rwebh.Update(ctx, *wh)
+	if err != nil {
+		return nil, consttypes.ErrFailedToUpdateWebhook.Wrap(err)
+	}
+
+	whres, err := wh.ToResponse()
+	if err != nil {
+		return nil, consttypes.ErrConvertFailed.Wrap(err)
+	}
+
+	return whres, nil
+}
+
+func (s *WebhookService) Delete(ctx context.Context, uid uuid.UUID, wid uuid.UUID) error {
+	wh, err := s.get(ctx, uid, wid)
+	if err != nil {
+		return err
+	}
+
+	if err := s.rwebh.Delete(ctx, *wh); err != nil {
+		return consttypes.ErrFailedToDeleteWebhook.Wrap(err)
+	}
+
+	return nil
+}
+
+// * an https url outside development, pointing to a public address. the
+// * address is checked again on every delivery, the host can change it
+func (s *WebhookService) validateURL(rawurl string) error {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return consttypes.ErrWebhookURLInvalid.Wrap(err)
+	}
+
+	if u.Scheme != "https" && s.cfg.App.Env != "development" {
+		return consttypes.ErrWebhookURLInsecure
+	}
+
+	if s.cfg.Webhook.AllowPrivate {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(s.cfg.Webhook.Timeout)*time.Second)
+	defer cancel()
+
+	if err := utnetwork.IsPublicHost(ctx, u.Hostname()); err != nil {
+		return consttypes.ErrWebhookURLNotAllowed.Wrap(err)
+	}
+
+	return nil
+}
+
+// * a webhook of another user is reported as not found
+// * so the ids of other users' webhooks are not disclosed
+func (s *WebhookService) get(ctx context.Context, uid uuid.UUID, wid uuid.UUID) (*models.Webhook, error) {
+	wh, err := s.rwebh.GetByID(ctx, wid)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, consttypes.ErrWebhookNotFound.Wrap(err)
+		}
+
+		utlogger.ErrorContext(ctx, err)
+		return nil, err
+	}
+
+	if wh.UserID != uid {
+		return nil, consttypes.ErrWebhookNotFound
+	}
+
+	return wh, nil
+}
+
+// ! ------------------------------- deliveries ------------------------------- ! //
+// * sends a ping to the webhook, even when it is inactive,
+// * so the receiver can be tested before it is switched on
+func (s *WebhookService) Ping(ctx context.Context, uid uuid.UUID, wid uuid.UUID) (*responses.WebhookDelivery, error) {
+	wh, err := s.get(ctx, uid, wid)
+	if err != nil {
+		return nil, err
+	}
+
+	eid := uuid.New()
+	payload, err := newPayload(eid, consttypes.WET_PING, map[string]any{
+		"webhook_id": wh.ID,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	wd, err := s.enqueue(ctx, *models.NewWebhookDelivery(wh.ID, eid, consttypes.WET_PING, payload))
+	if err != nil {
+		return nil, err
+	}
+
+	return wd.ToResponse()
+}
+
+func (s *WebhookService) FindDeliveries(ctx context.Context, uid uuid.UUID, wid uuid.UUID, p utpagination.Pagination) (*utpagination.Pagination, error) {
+	wh, err := s.get(ctx, uid, wid)
+	if err != nil {
+		return nil, err
+	}
+
+	wds, err := s.rwebh.FindDeliveriesByWebhookID(ctx, wh.ID, p)
+	if err != nil {
+		if errors.Is(err, consttypes.ErrInvalidCursor) || errors.Is(err, consttypes.ErrInvalidFilter) {
+			return nil, err
+		}
+
+		return nil, consttypes.ErrFailedToReadWebhookDeliveries.Wrap(err)
+	}
+
+	return wds, nil
+}
+
+// * a redelivery is a new delivery of the same event with a fresh
+// * number of attempts, the original delivery is kept in the log
+func (s *WebhookService) Redeliver(ctx context.Context, uid uuid.UUID, wid uuid.UUID, did uuid.UUID) (*responses.WebhookDelivery, error) {
+	wh, err := s.get(ctx, uid, wid)
+	if err != nil {
+		return nil, err
+	}
+
+	wdold, err := s.rwebh.GetDeliveryByID(ctx, did)
+	if err != nil || wdold.WebhookID != wh.ID {
+		return nil, consttypes.ErrWebhookDeliveryNotFound
+	}
+
+	wd, err := s.enqueue(ctx, *models.NewWebhookDelivery(wh.ID, wdold.EventID, wdold.EventType, wdold.Payload))
+	if err != nil {
+		return nil, err
+	}
+
+	return wd.ToResponse()
+}
+
+func (s *WebhookService) enqueue(ctx context.Context, wd models.WebhookDelivery) (*models.WebhookDelivery, error) {
+	wdnew, err := s.rwebh.CreateDelivery(ctx, wd)
+	if err != nil {
+		return nil, consttypes.ErrFailedToCreateWebhookDelivery.Wrap(err)
+	}
+
+	// * a delivery that could not be published is picked up by the retry job
+	if err := s.publish(ctx, wdnew.ID); err != nil {
+		s.reschedule(ctx, wdnew, err.Error())
+	}
+
+	return wdnew, nil
+}
+
+// * the delivery is part of the trace of the request or the job that
+// * dispatched it, but is published even when the request is cancelled
+func (s *WebhookService) publish(ctx context.Context, did uuid.UUID) error {
+	err := s.sprod.PublishWebhook(context.WithoutCancel(ctx), requests.DeliverWebhook{DeliveryID: did})
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return consttypes.ErrFailedToPublishWebhook.Wrap(err)
+	}
+
+	return nil
+}
+
+// * sends a pending delivery, called by the consumer of the webhook queue
+func (s *WebhookService) Deliver(ctx context.Context, did uuid.UUID) error {
+	wd, err := s.rwebh.GetDeliveryByID(ctx, did)
+	if err != nil {
+		return consttypes.ErrWebhookDeliveryNotFound.Wrap(err)
+	}
+
+	// * the message was already handled, e.g. a requeue after a restart
+	if wd.Status != consttypes.WDS_PENDING {
+		return nil
+	}
+
+	if wd.Webhook.ID == uuid.Nil {
+		s.fail(ctx, wd, consttypes.ErrWebhookNotFound.Error())
+		return consttypes.ErrWebhookNotFound
+	}
+
+	if !wd.Webhook.Active && wd.EventType != consttypes.WET_PING {
+		s.fail(ctx, wd, consttypes.ErrWebhookInactive.Error())
+		return consttypes.ErrWebhookInactive
+	}
+
+	wd.Attempts++
+
+	status, body, duration, err := s.send(ctx, wd.Webhook, *wd)
+
+	wd.ResponseStatus = status
+	wd.ResponseBody = body
+	wd.Duration = duration.Milliseconds()
+
+	if err != nil {
+		s.reschedule(ctx, wd, err.Error())
+		return err
+	}
+
+	now := consttypes.TimeNow()
+	wd.Status = consttypes.WDS_DELIVERED
+	wd.Error = ""
+	wd.DeliveredAt = &now
+	wd.NextAttemptAt = nil
+
+	if _, err := s.rwebh.UpdateDelivery(ctx, *wd); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// * the body is signed together with the timestamp so a captured
+// * request can not be replayed later with a fresh timestamp, the
+// * receiver recomputes hmac-sha256(secret, "<timestamp>.<body>")
+func (s *WebhookService) send(ctx context.Context, wh models.Webhook, wd models.WebhookDelivery) (int, string, time.Duration, error) {
+	timestamp := strconv.FormatInt(consttypes.TimeNow().Unix(), 10)
+
+	mac := hmac.New(sha256.New, []byte(wh.Secret))
+	mac.Write([]byte(timestamp + "." + wd.Payload))
+	signature := hex.EncodeToString(mac.Sum(nil))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, wh.URL, bytes.NewBufferString(wd.Payload))
+	if err != nil {
+		return 0, "", 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("X-Webhook-ID", wh.ID.String())
+	req.Header.Set("X-Webhook-Event", wd.EventType.String())
+	req.Header.Set("X-Webhook-Event-ID", wd.EventID.String())
+	req.Header.Set("X-Webhook-Delivery", wd.ID.String())
+	req.Header.Set("X-Webhook-Timestamp", timestamp)
+	req.Header.Set("X-Webhook-Signature", fmt.Sprintf("t=%s,v1=%s", timestamp, signature))
+
+	start := time.Now()
+	res, err := s.client.Do(req)
+	duration := time.Since(start)
+	if err != nil {
+		return 0, "", duration, err
+	}
+
+	defer res.Body.Close()
+
+	rbody, _ := io.ReadAll(io.LimitReader(res.Body, responseBodyLimit))
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, string(rbody), duration, consttypes.ErrWebhookDeliveryFailed
+	}
+
+	return res.StatusCode, string(rbody), duration, nil
+}
+
+// * schedules the next attempt with an exponential backoff, or
+// * gives up once the delivery used every allowed attempt
+func (s *WebhookService) reschedule(ctx context.Context, wd *models.WebhookDelivery, reason string) {
+	wd.Error = reason
+
+	if wd.Attempts >= s.cfg.Webhook.MaxAttempts {
+		s.fail(ctx, wd, reason)
+		return
+	}
+
+	backoff := time.Duration(s.cfg.Webhook.Backoff) * time.Second
+	for i := 1; i < wd.Attempts; i++ {
+		backoff *= 2
+	}
+
+	if maxbackoff := time.Duration(s.cfg.Webhook.MaxBackoff) * time.Second; backoff > maxbackoff {
+		backoff = maxbackoff
+	}
+
+	next := consttypes.TimeNow().Add(backoff)
+	wd.Status = consttypes.WDS_RETRYING
+	wd.NextAttemptAt = &next
+
+	if _, err := s.rwebh.UpdateDelivery(ctx, *wd); err != nil {
+		utlogger.ErrorContext(ctx, err)
+	}
+}
+
+func (s *WebhookService) fail(ctx context.Context, wd *models.WebhookDelivery, reason string) {
+	wd.Status = consttypes.WDS_FAILED
+	wd.Error = reason
+	wd.NextAttemptAt = nil
+
+	if _, err := s.rwebh.UpdateDelivery(ctx, *wd); err != nil {
+		utlogger.ErrorContext(ctx, err)
+	}
+}
+
+// * publishes the deliveries whose backoff has passed, called by the cron
+func (s *WebhookService) RetryDue(ctx context.Context) (int, error) {
+	var (
+		count int
+	)
+
+	wds, err := s.rwebh.FindDueRetries(ctx, s.cfg.Webhook.RetryBatchSize)
+	if err != nil {
+		return 0, consttypes.ErrFailedToReadWebhookDeliveries.Wrap(err)
+	}
+
+	for _, wd := range wds {
+		ok, err := s.rwebh.RequeueDelivery(ctx, wd.ID)
+		if err != nil || !ok {
+			continue
+		}
+
+		if err := s.publish(ctx, wd.ID); err != nil {
+			wd.Status = consttypes.WDS_PENDING
+			s.reschedule(ctx, wd, err.Error())
+			continue
+		}
+
+		count++
+	}
+
+	return count, nil
+}
+
+// ! --------------------------------- events --------------------------------- ! //
+// * the order is sent to the partner handling it and to the organization
+// * of the member, the order has to be loaded with its partner and member.
+// * dispatching is best effort and never fails the change of the order.
+func (s *WebhookService) DispatchOrder(ctx context.Context, wet consttypes.WebhookEventType, order models.Order, previous consttypes.OrderStatus) {
+	var (
+		uids []uuid.UUID
+	)
+
+	if order.Partner.UserID != uuid.Nil {
+		uids = append(uids, order.Partner.UserID)
+	}
+
+	if order.Member.Organization != nil && order.Member.Organization.UserID != uuid.Nil {
+		uids = append(uids, order.Member.Organization.UserID)
+	}
+
+	if len(uids) == 0 {
+		return
+	}
+
+	whs, err := s.rwebh.FindActiveByUserIDs(ctx, uids)
+	if err != nil {
+		return
+	}
+
+	eid := uuid.New()
+	payload, err := newPayload(eid, wet, order.ToWebhookResponse(previous))
+	if err != nil {
+		utlogger.ErrorContext(ctx, err)
+		return
+	}
+
+	for _, wh := range whs {
+		if !wh.IsSubscribed(wet) {
+			continue
+		}
+
+		if _, err := s.enqueue(ctx, *models.NewWebhookDelivery(wh.ID, eid, wet, payload)); err != nil {
+			utlogger.ErrorContext(ctx, err)
+		}
+	}
+}
+
+func newPayload(eid uuid.UUID, wet consttypes.WebhookEventType, data any) (string, error) {
+	payload, err := json.Marshal(responses.WebhookEvent{
+		ID:        eid,
+		Type:      wet,
+		CreatedAt: consttypes.TimeNow(),
+		Data:      data,
+	})
+
+	if err != nil {
+		return "", consttypes.ErrConvertFailed.Wrap(err)
+	}
+
+	return string(payload), nil
+}
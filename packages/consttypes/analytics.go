@@ -0,0 +1,21 @@
+package consttypes
+
+import "slices"
+
+type (
+	AnalyticsBucket string
+)
+
+const (
+	AB_DAY   AnalyticsBucket = "day"
+	AB_WEEK  AnalyticsBucket = "week"
+	AB_MONTH AnalyticsBucket = "month"
+)
+
+func (enum AnalyticsBucket) String() string {
+	return string(enum)
+}
+
+func (enum AnalyticsBucket) IsValid() bool {
+	return slices.Contains([]AnalyticsBucket{AB_DAY, AB_WEEK, AB_MONTH}, enum)
+}
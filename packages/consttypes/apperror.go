@@ -0,0 +1,101 @@
+package consttypes
+
+import (
+	"errors"
+	"net/http"
+)
+
+type (
+	// * the machine readable code of an app error, the clients branch on
+	// * it instead of on the message
+	ErrorCode string
+
+	// * AppError is an error of the app with the status it is answered
+	// * with, a message that is safe to show to the user and the cause
+	// * it was wrapped around, which is only logged
+	AppError struct {
+		Code    ErrorCode
+		Status  int
+		Message string
+		Err     error
+	}
+)
+
+func (enum ErrorCode) String() string {
+	return string(enum)
+}
+
+func NewAppError(code ErrorCode, status int, message string) *AppError {
+	return &AppError{
+		Code:    code,
+		Status:  status,
+		Message: message,
+	}
+}
+
+func (e *AppError) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+
+	return e.Message
+}
+
+func (e *AppError) Unwrap() error {
+	return e.Err
+}
+
+// * a wrapped error is still the same app error, so errors.Is matches
+// * it against the sentinel it was made from
+func (e *AppError) Is(target error) bool {
+	t, ok := target.(*AppError)
+	if !ok {
+		return false
+	}
+
+	return e.Code == t.Code
+}
+
+// * keeps the cause of the error, the sentinel itself is not changed
+func (e *AppError) Wrap(err error) error {
+	if err == nil {
+		return e
+	}
+
+	return &AppError{
+		Code:    e.Code,
+		Status:  e.Status,
+		Message: e.Message,
+		Err:     err,
+	}
+}
+
+// * the app error of err, an error that is not one is answered as an
+// * internal error
+func AsAppError(err error) *AppError {
+	var aerr *AppError
+	if errors.As(err, &aerr) {
+		return aerr
+	}
+
+	return &AppError{
+		Code:    EC_INTERNAL,
+		Status:  http.StatusInternalServerError,
+		Message: "something went wrong",
+		Err:     err,
+	}
+}
+
+const (
+	// * the codes of the errors the handlers fall back to
+	EC_INTERNAL       ErrorCode = "internal"
+	EC_INVALID_INPUT  ErrorCode = "invalid_input"
+	EC_NOT_FOUND      ErrorCode = "not_found"
+	EC_UNAUTHORIZED   ErrorCode = "unauthorized"
+	EC_FORBIDDEN      ErrorCode = "forbidden"
+	EC_CONFLICT       ErrorCode = "conflict"
+	EC_UNPROCESSABLE  ErrorCode = "unprocessable"
+	EC_TOO_MANY       ErrorCode = "too_many_requests"
+	EC_LOCKED         ErrorCode = "locked"
+	EC_INPUT_REQUIRED ErrorCode = "input_required"
+)
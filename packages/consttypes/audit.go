@@ -0,0 +1,77 @@
+package consttypes
+
+import "net/http"
+
+type (
+	AuditAction string
+	AuditEntity string
+)
+
+const (
+	AA_CREATE AuditAction = "Create"
+	AA_UPDATE AuditAction = "Update"
+	AA_DELETE AuditAction = "Delete"
+)
+
+const (
+	AE_MEAL            AuditEntity = "meal"
+	AE_MEAL_CATEGORY   AuditEntity = "meal_category"
+	AE_MEMBER          AuditEntity = "member"
+	AE_PARTNER         AuditEntity = "partner"
+	AE_PATRON          AuditEntity = "patron"
+	AE_ILLNESS         AuditEntity = "illness"
+	AE_ALLERGY         AuditEntity = "allergy"
+	AE_DONATION        AuditEntity = "donation"
+	AE_ROLE_PERMISSION AuditEntity = "role_permission"
+	AE_DATA_ERASURE    AuditEntity = "data_erasure"
+	AE_SERVICE_ACCOUNT AuditEntity = "service_account"
+	AE_API_KEY         AuditEntity = "api_key"
+)
+
+func (enum AuditAction) String() string {
+	return string(enum)
+}
+
+func (enum AuditEntity) String() string {
+	return string(enum)
+}
+
+// * the action of a state-changing http method, false for
+// * the methods that only read
+func AuditActionFromMethod(method string) (AuditAction, bool) {
+	switch method {
+	case http.MethodPost:
+		return AA_CREATE, true
+	case http.MethodPut, http.MethodPatch:
+		return AA_UPDATE, true
+	case http.MethodDelete:
+		return AA_DELETE, true
+	}
+
+	return "", false
+}
+
+// * the entity of a resource segment of the manage routes,
+// * unknown resources are audited under their own name
+func AuditEntityFromResource(resource string) AuditEntity {
+	entities := map[string]AuditEntity{
+		"meals":            AE_MEAL,
+		"categories":       AE_MEAL_CATEGORY,
+		"members":          AE_MEMBER,
+		"partners":         AE_PARTNER,
+		"patrons":          AE_PATRON,
+		"illnesses":        AE_ILLNESS,
+		"allergies":        AE_ALLERGY,
+		"donations":        AE_DONATION,
+		"permissions":      AE_ROLE_PERMISSION,
+		"data-erasures":    AE_DATA_ERASURE,
+		"service-accounts": AE_SERVICE_ACCOUNT,
+		"api-keys":         AE_API_KEY,
+	}
+
+	if ae, ok := entities[resource]; ok {
+		return ae
+	}
+
+	return AuditEntity(resource)
+}
@@ -7,4 +7,5 @@ type (
 const (
 	T_ACCESS  = "Access-Token"
 	T_REFRESH = "Refresh-Token"
+	T_API_KEY = "X-API-Key"
 )
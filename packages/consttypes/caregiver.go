@@ -0,0 +1,29 @@
+package consttypes
+
+type (
+	CaregiverAccess           string
+	CaregiverInvitationStatus string
+)
+
+const (
+	// * every linked caregiver can view the member's data,
+	// * ordering and editing health data must be granted per link
+	CA_VIEW        CaregiverAccess = "View"
+	CA_ORDER       CaregiverAccess = "Order"
+	CA_EDIT_HEALTH CaregiverAccess = "Edit Health"
+)
+
+const (
+	CIS_PENDING  CaregiverInvitationStatus = "Pending"
+	CIS_ACCEPTED CaregiverInvitationStatus = "Accepted"
+	CIS_DECLINED CaregiverInvitationStatus = "Declined"
+	CIS_REVOKED  CaregiverInvitationStatus = "Revoked"
+)
+
+func (enum CaregiverAccess) String() string {
+	return string(enum)
+}
+
+func (enum CaregiverInvitationStatus) String() string {
+	return string(enum)
+}
@@ -0,0 +1,17 @@
+package consttypes
+
+type (
+	DataRequestStatus string
+)
+
+const (
+	DRS_PENDING    DataRequestStatus = "Pending"
+	DRS_PROCESSING DataRequestStatus = "Processing"
+	DRS_COMPLETED  DataRequestStatus = "Completed"
+	DRS_FAILED     DataRequestStatus = "Failed"
+	DRS_EXPIRED    DataRequestStatus = "Expired"
+)
+
+func (enum DataRequestStatus) String() string {
+	return string(enum)
+}
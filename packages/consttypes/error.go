@@ -1,9 +1,12 @@
 package consttypes
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -26,148 +29,362 @@ func GetResetPasswordCooldown() int {
 }
 
 func ErrGeneralFailed(function string, err string) error {
-	return fmt.Errorf("failed to %s, error: %s", function, err)
+	// * the error is the cause, it is logged but not answered
+	return NewAppError("general_failed", http.StatusInternalServerError, fmt.Sprintf("failed to %s", function)).Wrap(errors.New(err))
 }
 
 func ErrDailyMaxOrderReached(maxord int) error {
-	return fmt.Errorf("daily max order of %v reached", maxord)
+	return NewAppError("daily_max_order_reached", http.StatusUnprocessableEntity, fmt.Sprintf("daily max order of %v reached", maxord))
 }
 
 func ErrUnexpectedStatusCode(code int) error {
-	return fmt.Errorf("unexpected status code: %d", code)
+	return NewAppError("unexpected_status_code", http.StatusBadGateway, fmt.Sprintf("unexpected status code: %d", code))
 }
 
 func ErrFileSizeTooBig(ext any, maxSize float64, maxSizeSuffix string) error {
-	return fmt.Errorf("%s size is too big. Maximum size is %f %s", ext, maxSize, maxSizeSuffix)
+	return NewAppError("file_size_too_big", http.StatusRequestEntityTooLarge, fmt.Sprintf("%s size is too big. Maximum size is %f %s", ext, maxSize, maxSizeSuffix))
 }
 
 func ErrUnsupportedFileExtension(ext any) error {
-	return fmt.Errorf("unsupported file extension: %s", ext)
+	return NewAppError("unsupported_file_extension", http.StatusUnsupportedMediaType, fmt.Sprintf("unsupported file extension: %s", ext))
 }
 
 func ErrUnsupportedFileType(filetype any) error {
-	return fmt.Errorf("unsupported file type: %s", filetype)
+	return NewAppError("unsupported_file_type", http.StatusUnsupportedMediaType, fmt.Sprintf("unsupported file type: %s", filetype))
+}
+
+func ErrInvalidPermission(permission any) error {
+	return NewAppError("invalid_permission", http.StatusBadRequest, fmt.Sprintf("invalid permission: %s", permission))
+}
+
+func ErrProtectedPermission(role any, permission any) error {
+	return NewAppError("protected_permission", http.StatusBadRequest, fmt.Sprintf("permission %s cannot be removed from role %v", permission, role))
+}
+
+func ErrInvalidWebhookEventType(eventtype any) error {
+	return NewAppError("invalid_webhook_event_type", http.StatusBadRequest, fmt.Sprintf("invalid webhook event type: %s", eventtype))
+}
+
+func ErrEncryptionKeyNotFound(keyid any) error {
+	return NewAppError("encryption_key_not_found", http.StatusInternalServerError, fmt.Sprintf("encryption key not found: %s", keyid))
+}
+
+func ErrEncryptionKeyInvalid(keyid any) error {
+	return NewAppError("encryption_key_invalid", http.StatusInternalServerError, fmt.Sprintf("encryption key is invalid: %s", keyid))
+}
+
+// * wraps an error of a request that can only be retried after
+// * some time, the duration is returned as the retry after header
+type RetryAfterError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func ErrRetryAfter(err error, retryafter time.Duration) error {
+	return &RetryAfterError{
+		Err:        err,
+		RetryAfter: retryafter,
+	}
+}
+
+func (e *RetryAfterError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RetryAfterError) Unwrap() error {
+	return e.Err
 }
 
 var (
 	// * external
-	ErrFailedToDeclareNewRequest = fmt.Errorf("failed to declare new request")
-	ErrFailedToCallExternalAPI   = fmt.Errorf("failed to call external api")
+	ErrFailedToDeclareNewRequest = NewAppError("failed_to_declare_new_request", http.StatusInternalServerError, "failed to declare new request")
+	ErrFailedToCallExternalAPI   = NewAppError("failed_to_call_external_api", http.StatusBadGateway, "failed to call external api")
+	ErrAddressNotAllowed         = NewAppError("address_not_allowed", http.StatusBadRequest, "address is not a public address")
+	ErrHostNotResolved           = NewAppError("host_not_resolved", http.StatusBadRequest, "host could not be resolved")
 
 	// * queues
-	ErrFailedToPublishMessage = fmt.Errorf("failed to publish message")
+	ErrFailedToPublishMessage = NewAppError("failed_to_publish_message", http.StatusInternalServerError, "failed to publish message")
+
+	// * migrations
+	ErrMigrationInvalidFile  = NewAppError("migration_invalid_file", http.StatusInternalServerError, "migration file name must be <version>_<name>.<up|down>.sql")
+	ErrMigrationDuplicate    = NewAppError("migration_duplicate", http.StatusInternalServerError, "migration version is used by more than one migration")
+	ErrMigrationMissingUp    = NewAppError("migration_missing_up", http.StatusInternalServerError, "migration has no up file")
+	ErrMigrationIrreversible = NewAppError("migration_irreversible", http.StatusInternalServerError, "migration has no down file")
+	ErrMigrationUnknown      = NewAppError("migration_unknown", http.StatusInternalServerError, "applied migration is not in the migration files")
+
+	// * commands
+	ErrSeederUnknown     = NewAppError("seeder_unknown", http.StatusInternalServerError, "seeder not found")
+	ErrDeadLetterUnknown = NewAppError("dead_letter_unknown", http.StatusInternalServerError, "queue has no dead letter queue")
 
 	// * generals
-	ErrConvertFailed          = fmt.Errorf("data type conversion failed")
-	ErrInvalidReference       = fmt.Errorf("invalid reference")
-	ErrUnauthorized           = fmt.Errorf("you are unauthorized to access this resource")
-	ErrAccountIsNotVerified   = fmt.Errorf("your account is not verified yet")
-	ErrInvalidEmailOrPassword = fmt.Errorf("invalid email or password")
-	ErrFailedToGetUserName    = fmt.Errorf("failed to get user's name")
+	ErrConvertFailed          = NewAppError("convert_failed", http.StatusInternalServerError, "data type conversion failed")
+	ErrInvalidReference       = NewAppError("invalid_reference", http.StatusInternalServerError, "invalid reference")
+	ErrUnauthorized           = NewAppError("unauthorized", http.StatusUnauthorized, "you are unauthorized to access this resource")
+	ErrAccountIsNotVerified   = NewAppError("account_is_not_verified", http.StatusForbidden, "your account is not verified yet")
+	ErrInvalidEmailOrPassword = NewAppError("invalid_email_or_password", http.StatusUnauthorized, "invalid email or password")
+	ErrFailedToGetUserName    = NewAppError("failed_to_get_user_name", http.StatusInternalServerError, "failed to get user's name")
+	ErrRouteNotFound          = NewAppError("route_not_found", http.StatusNotFound, "route not found")
 
 	// * fields
-	ErrFieldIsEmpty             = fmt.Errorf("field should not be empty")
-	ErrFieldInvalidFormat       = fmt.Errorf("field format is invalid")
-	ErrFieldInvalidEmailAddress = fmt.Errorf("invalid email address format")
+	ErrFieldIsEmpty             = NewAppError("field_is_empty", http.StatusBadRequest, "field should not be empty")
+	ErrFieldInvalidFormat       = NewAppError("field_invalid_format", http.StatusBadRequest, "field format is invalid")
+	ErrFieldInvalidEmailAddress = NewAppError("field_invalid_email_address", http.StatusBadRequest, "invalid email address format")
+
+	// * paginations
+	ErrInvalidCursor = NewAppError("invalid_cursor", http.StatusBadRequest, "cursor is invalid or does not match the sort of the list")
+	ErrInvalidFilter = NewAppError("invalid_filter", http.StatusBadRequest, "filter is invalid for the field it filters")
 
 	// * tokens
-	ErrTokenUnverifiable          = fmt.Errorf("token is unverifiable")
-	ErrTokenMismatch              = fmt.Errorf("token is mismatch")
-	ErrTokenIsNotTheSame          = fmt.Errorf("token is not the same")
-	ErrTokenIsExpired             = fmt.Errorf("token is expired")
-	ErrTokenNotFound              = fmt.Errorf("token is not found")
-	ErrTokenInvalidFormat         = fmt.Errorf("token format is invalid")
-	ErrTokenCannotDecodePublicKey = fmt.Errorf("cannot decode token public key")
-	ErrFailedToGenerateToken      = fmt.Errorf("failed to generate token")
+	ErrTokenUnverifiable          = NewAppError("token_unverifiable", http.StatusUnauthorized, "token is unverifiable")
+	ErrTokenMismatch              = NewAppError("token_mismatch", http.StatusUnauthorized, "token is mismatch")
+	ErrTokenIsNotTheSame          = NewAppError("token_is_not_the_same", http.StatusUnauthorized, "token is not the same")
+	ErrTokenIsExpired             = NewAppError("token_is_expired", http.StatusUnauthorized, "token is expired")
+	ErrTokenNotFound              = NewAppError("token_not_found", http.StatusUnauthorized, "token is not found")
+	ErrTokenInvalidFormat         = NewAppError("token_invalid_format", http.StatusUnauthorized, "token format is invalid")
+	ErrTokenCannotDecodePublicKey = NewAppError("token_cannot_decode_public_key", http.StatusInternalServerError, "cannot decode token public key")
+	ErrFailedToGenerateToken      = NewAppError("failed_to_generate_token", http.StatusInternalServerError, "failed to generate token")
+
+	// * throttles
+	ErrTooManyRequests     = NewAppError("too_many_requests", http.StatusTooManyRequests, "too many requests, please try again later")
+	ErrAccountLocked       = NewAppError("account_locked", http.StatusLocked, "account is locked because of too many failed signin attempts")
+	ErrUnlockTokenInvalid  = NewAppError("unlock_token_invalid", http.StatusUnauthorized, "unlock token is invalid or has expired")
+	ErrFailedToThrottle    = NewAppError("failed_to_throttle", http.StatusInternalServerError, "failed to check request throttle")
+	ErrFailedToLockAccount = NewAppError("failed_to_lock_account", http.StatusInternalServerError, "failed to lock account")
+
+	// * two factor
+	ErrTwoFactorNotAllowed              = NewAppError("two_factor_not_allowed", http.StatusForbidden, "two factor authentication is not available for this role")
+	ErrTwoFactorRequired                = NewAppError("two_factor_required", http.StatusForbidden, "two factor authentication is required for this role")
+	ErrTwoFactorAlreadyEnabled          = NewAppError("two_factor_already_enabled", http.StatusConflict, "two factor authentication is already enabled")
+	ErrTwoFactorNotEnabled              = NewAppError("two_factor_not_enabled", http.StatusConflict, "two factor authentication is not enabled")
+	ErrTwoFactorNotEnrolled             = NewAppError("two_factor_not_enrolled", http.StatusConflict, "two factor enrolment has not been started")
+	ErrTwoFactorCodeInvalid             = NewAppError("two_factor_code_invalid", http.StatusUnauthorized, "two factor code is invalid")
+	ErrTwoFactorCodeRequired            = NewAppError("two_factor_code_required", http.StatusBadRequest, "either a two factor code or a recovery code is required")
+	ErrTwoFactorChallengeInvalid        = NewAppError("two_factor_challenge_invalid", http.StatusUnauthorized, "two factor challenge is invalid or has expired")
+	ErrFailedToCreateTwoFactor          = NewAppError("failed_to_create_two_factor", http.StatusInternalServerError, "failed to create two factor authentication")
+	ErrFailedToUpdateTwoFactor          = NewAppError("failed_to_update_two_factor", http.StatusInternalServerError, "failed to update two factor authentication")
+	ErrFailedToDeleteTwoFactor          = NewAppError("failed_to_delete_two_factor", http.StatusInternalServerError, "failed to delete two factor authentication")
+	ErrFailedToCreateTwoFactorChallenge = NewAppError("failed_to_create_two_factor_challenge", http.StatusInternalServerError, "failed to create two factor challenge")
+	ErrFailedToGenerateRecoveryCodes    = NewAppError("failed_to_generate_recovery_codes", http.StatusInternalServerError, "failed to generate recovery codes")
+
+	// * oidc
+	ErrOIDCProviderUnavailable = NewAppError("oidc_provider_unavailable", http.StatusServiceUnavailable, "oidc provider is unavailable")
+	ErrOIDCStateInvalid        = NewAppError("oidc_state_invalid", http.StatusUnauthorized, "oidc state is invalid or has expired")
+	ErrOIDCExchangeFailed      = NewAppError("oidc_exchange_failed", http.StatusBadGateway, "failed to exchange the oidc authorization code")
+	ErrOIDCTokenInvalid        = NewAppError("oidc_token_invalid", http.StatusUnauthorized, "oidc id token is invalid")
+	ErrOIDCEmailNotVerified    = NewAppError("oidc_email_not_verified", http.StatusForbidden, "the email of the oidc account is not verified")
+	ErrOIDCSignupTokenInvalid  = NewAppError("oidc_signup_token_invalid", http.StatusUnauthorized, "oidc signup token is invalid or has expired")
+	ErrFailedToLinkIdentity    = NewAppError("failed_to_link_identity", http.StatusInternalServerError, "failed to link the oidc identity")
+
+	// * service accounts
+	ErrServiceAccountNotFound        = NewAppError("service_account_not_found", http.StatusNotFound, "service account not found")
+	ErrServiceAccountOwnerNotAllowed = NewAppError("service_account_owner_not_allowed", http.StatusForbidden, "service accounts cannot act for an admin")
+	ErrServiceAccountDisabled        = NewAppError("service_account_disabled", http.StatusForbidden, "service account is disabled")
+	ErrScopeNotGranted               = NewAppError("scope_not_granted", http.StatusForbidden, "scope is not granted to the role of the service account owner")
+	ErrFailedToCreateServiceAccount  = NewAppError("failed_to_create_service_account", http.StatusInternalServerError, "failed to create service account")
+	ErrFailedToUpdateServiceAccount  = NewAppError("failed_to_update_service_account", http.StatusInternalServerError, "failed to update service account")
+	ErrFailedToDeleteServiceAccount  = NewAppError("failed_to_delete_service_account", http.StatusInternalServerError, "failed to delete service account")
+	ErrFailedToReadServiceAccounts   = NewAppError("failed_to_read_service_accounts", http.StatusInternalServerError, "failed to read service accounts")
+	ErrAPIKeyNotFound                = NewAppError("api_key_not_found", http.StatusNotFound, "api key not found")
+	ErrAPIKeyInvalid                 = NewAppError("api_key_invalid", http.StatusUnauthorized, "api key is invalid or has been revoked")
+	ErrAPIKeyExpired                 = NewAppError("api_key_expired", http.StatusUnauthorized, "api key has expired")
+	ErrAPIKeyLifeTooLong             = NewAppError("api_key_life_too_long", http.StatusBadRequest, "api key life exceeds the allowed maximum")
+	ErrFailedToCreateAPIKey          = NewAppError("failed_to_create_api_key", http.StatusInternalServerError, "failed to create api key")
+	ErrFailedToRevokeAPIKey          = NewAppError("failed_to_revoke_api_key", http.StatusInternalServerError, "failed to revoke api key")
+	ErrFailedToReadAPIKeys           = NewAppError("failed_to_read_api_keys", http.StatusInternalServerError, "failed to read api keys")
+
+	// * webhooks
+	ErrWebhookNotFound               = NewAppError("webhook_not_found", http.StatusNotFound, "webhook not found")
+	ErrWebhookInactive               = NewAppError("webhook_inactive", http.StatusConflict, "webhook is inactive")
+	ErrWebhookURLInvalid             = NewAppError("webhook_url_invalid", http.StatusBadRequest, "webhook url must be an absolute http or https url")
+	ErrWebhookURLInsecure            = NewAppError("webhook_url_insecure", http.StatusBadRequest, "webhook url must be an https url")
+	ErrWebhookURLNotAllowed          = NewAppError("webhook_url_not_allowed", http.StatusBadRequest, "webhook url must point to a public address")
+	ErrWebhookDeliveryNotFound       = NewAppError("webhook_delivery_not_found", http.StatusNotFound, "webhook delivery not found")
+	ErrWebhookDeliveryFailed         = NewAppError("webhook_delivery_failed", http.StatusInternalServerError, "webhook endpoint did not answer with a 2xx status")
+	ErrFailedToCreateWebhook         = NewAppError("failed_to_create_webhook", http.StatusInternalServerError, "failed to create webhook")
+	ErrFailedToUpdateWebhook         = NewAppError("failed_to_update_webhook", http.StatusInternalServerError, "failed to update webhook")
+	ErrFailedToDeleteWebhook         = NewAppError("failed_to_delete_webhook", http.StatusInternalServerError, "failed to delete webhook")
+	ErrFailedToReadWebhooks          = NewAppError("failed_to_read_webhooks", http.StatusInternalServerError, "failed to read webhooks")
+	ErrFailedToCreateWebhookDelivery = NewAppError("failed_to_create_webhook_delivery", http.StatusInternalServerError, "failed to create webhook delivery")
+	ErrFailedToReadWebhookDeliveries = NewAppError("failed_to_read_webhook_deliveries", http.StatusInternalServerError, "failed to read webhook deliveries")
+	ErrFailedToPublishWebhook        = NewAppError("failed_to_publish_webhook", http.StatusInternalServerError, "failed to publish webhook delivery")
+
+	// * audit logs
+	ErrAuditLogNotFound         = NewAppError("audit_log_not_found", http.StatusNotFound, "audit log not found")
+	ErrFailedToRecordAuditLog   = NewAppError("failed_to_record_audit_log", http.StatusInternalServerError, "failed to record audit log")
+	ErrFailedToReadAuditLogs    = NewAppError("failed_to_read_audit_logs", http.StatusInternalServerError, "failed to read audit logs")
+	ErrFailedToExportAuditLogs  = NewAppError("failed_to_export_audit_logs", http.StatusInternalServerError, "failed to export audit logs")
+	ErrAuditExportLimitExceeded = NewAppError("audit_export_limit_exceeded", http.StatusBadRequest, "too many audit logs to export, narrow down the filters")
+
+	// * analytics
+	ErrAnalyticsRangeInvalid = NewAppError("analytics_range_invalid", http.StatusBadRequest, "analytics range is invalid, the dates must be YYYY-MM-DD and from must not be after to")
+	ErrAnalyticsRangeTooLong = NewAppError("analytics_range_too_long", http.StatusBadRequest, "analytics range is too long")
+	ErrFailedToReadAnalytics = NewAppError("failed_to_read_analytics", http.StatusInternalServerError, "failed to read analytics")
+
+	// * security events
+	ErrFailedToRecordSecurityEvent = NewAppError("failed_to_record_security_event", http.StatusInternalServerError, "failed to record security event")
+	ErrFailedToReadSecurityEvents  = NewAppError("failed_to_read_security_events", http.StatusInternalServerError, "failed to read security events")
+
+	// * sessions
+	ErrSessionNotFound       = NewAppError("session_not_found", http.StatusNotFound, "session not found")
+	ErrSessionRevoked        = NewAppError("session_revoked", http.StatusUnauthorized, "session has been revoked")
+	ErrRefreshTokenReused    = NewAppError("refresh_token_reused", http.StatusUnauthorized, "refresh token has already been used, every token of the session is revoked")
+	ErrFailedToCreateSession = NewAppError("failed_to_create_session", http.StatusInternalServerError, "failed to create session")
+	ErrFailedToUpdateSession = NewAppError("failed_to_update_session", http.StatusInternalServerError, "failed to update session")
+	ErrFailedToRevokeSession = NewAppError("failed_to_revoke_session", http.StatusInternalServerError, "failed to revoke session")
+	ErrFailedToReadSessions  = NewAppError("failed_to_read_sessions", http.StatusInternalServerError, "failed to read sessions")
 
 	// * orders
-	ErrFailedToCreateOrder   = fmt.Errorf("failed to create order")
-	ErrFailedToReadOrder     = fmt.Errorf("failed to read orders")
-	ErrOrderNotFound         = fmt.Errorf("order not found")
-	ErrFailedToDeleteOrder   = fmt.Errorf("failed to delete order")
-	ErrFailedToFindAllOrders = fmt.Errorf("failed to find all orders")
+	ErrFailedToCreateOrder   = NewAppError("failed_to_create_order", http.StatusInternalServerError, "failed to create order")
+	ErrFailedToReadOrder     = NewAppError("failed_to_read_order", http.StatusInternalServerError, "failed to read orders")
+	ErrOrderNotFound         = NewAppError("order_not_found", http.StatusNotFound, "order not found")
+	ErrFailedToDeleteOrder   = NewAppError("failed_to_delete_order", http.StatusInternalServerError, "failed to delete order")
+	ErrFailedToFindAllOrders = NewAppError("failed_to_find_all_orders", http.StatusInternalServerError, "failed to find all orders")
 
 	// * partners
-	ErrPartnerNotFound = fmt.Errorf("partner not found")
+	ErrPartnerNotFound = NewAppError("partner_not_found", http.StatusNotFound, "partner not found")
 
 	// * members
-	ErrMemberNotFound         = fmt.Errorf("member not found")
-	ErrFailedToCreateMember   = fmt.Errorf("failed to create member")
-	ErrFailedToReadMembers    = fmt.Errorf("failed to read members")
-	ErrFailedToUpdateMember   = fmt.Errorf("failed to update member")
-	ErrFailedToDeleteMember   = fmt.Errorf("failed to delete member")
-	ErrFailedToFindAllMembers = fmt.Errorf("failed to find all members")
+	ErrMemberNotFound         = NewAppError("member_not_found", http.StatusNotFound, "member not found")
+	ErrFailedToCreateMember   = NewAppError("failed_to_create_member", http.StatusInternalServerError, "failed to create member")
+	ErrFailedToReadMembers    = NewAppError("failed_to_read_members", http.StatusInternalServerError, "failed to read members")
+	ErrFailedToUpdateMember   = NewAppError("failed_to_update_member", http.StatusInternalServerError, "failed to update member")
+	ErrFailedToDeleteMember   = NewAppError("failed_to_delete_member", http.StatusInternalServerError, "failed to delete member")
+	ErrFailedToFindAllMembers = NewAppError("failed_to_find_all_members", http.StatusInternalServerError, "failed to find all members")
+
+	// * member health histories
+	ErrFailedToRecordHealthHistory = NewAppError("failed_to_record_health_history", http.StatusInternalServerError, "failed to record member health history")
+	ErrFailedToReadHealthHistory   = NewAppError("failed_to_read_health_history", http.StatusInternalServerError, "failed to read member health history")
 
 	// * orders
-	ErrFailedToGetDailyOrder    = fmt.Errorf("failed to get daily order")
-	ErrInvalidOrderStatus       = fmt.Errorf("invalid order status")
-	ErrOrderShouldBeSamePartner = fmt.Errorf("order should be from the same partner")
+	ErrFailedToGetDailyOrder    = NewAppError("failed_to_get_daily_order", http.StatusInternalServerError, "failed to get daily order")
+	ErrInvalidOrderStatus       = NewAppError("invalid_order_status", http.StatusBadRequest, "invalid order status")
+	ErrOrderShouldBeSamePartner = NewAppError("order_should_be_same_partner", http.StatusBadRequest, "order should be from the same partner")
 
 	// * caregivers
-	ErrCaregiverNotFound = fmt.Errorf("caregiver not found")
+	ErrCaregiverNotFound          = NewAppError("caregiver_not_found", http.StatusNotFound, "caregiver not found")
+	ErrCaregiverNotLinked         = NewAppError("caregiver_not_linked", http.StatusForbidden, "caregiver is not linked to the member")
+	ErrCaregiverAlreadyLinked     = NewAppError("caregiver_already_linked", http.StatusConflict, "caregiver is already linked to the member")
+	ErrCaregiverAccessDenied      = NewAppError("caregiver_access_denied", http.StatusForbidden, "caregiver is not allowed to do this for the member")
+	ErrCaregiverMemberIDRequired  = NewAppError("caregiver_member_id_required", http.StatusBadRequest, "member id is required when acting as a caregiver")
+	ErrCaregiverDataRequired      = NewAppError("caregiver_data_required", http.StatusBadRequest, "caregiver data is required to create a new caregiver account")
+	ErrFailedToReadCaregiverLinks = NewAppError("failed_to_read_caregiver_links", http.StatusInternalServerError, "failed to read caregiver links")
+	ErrFailedToCreateCaregiver    = NewAppError("failed_to_create_caregiver", http.StatusInternalServerError, "failed to create caregiver")
+	ErrFailedToLinkCaregiver      = NewAppError("failed_to_link_caregiver", http.StatusInternalServerError, "failed to link caregiver")
+	ErrFailedToUpdateCaregiver    = NewAppError("failed_to_update_caregiver", http.StatusInternalServerError, "failed to update caregiver link")
+	ErrFailedToUnlinkCaregiver    = NewAppError("failed_to_unlink_caregiver", http.StatusInternalServerError, "failed to unlink caregiver")
+
+	// * caregiver invitations
+	ErrCaregiverInvitationNotFound       = NewAppError("caregiver_invitation_not_found", http.StatusNotFound, "caregiver invitation not found")
+	ErrCaregiverInvitationExpired        = NewAppError("caregiver_invitation_expired", http.StatusGone, "caregiver invitation has expired")
+	ErrCaregiverInvitationNotPending     = NewAppError("caregiver_invitation_not_pending", http.StatusConflict, "caregiver invitation is no longer pending")
+	ErrCaregiverInvitationAlreadySent    = NewAppError("caregiver_invitation_already_sent", http.StatusConflict, "a pending caregiver invitation was already sent to this email")
+	ErrCaregiverInvitationSelf           = NewAppError("caregiver_invitation_self", http.StatusBadRequest, "member cannot invite themselves as a caregiver")
+	ErrCaregiverInvitationInvalidRole    = NewAppError("caregiver_invitation_invalid_role", http.StatusBadRequest, "invited email belongs to a user that is not a caregiver")
+	ErrFailedToCreateCaregiverInvitation = NewAppError("failed_to_create_caregiver_invitation", http.StatusInternalServerError, "failed to create caregiver invitation")
+	ErrFailedToReadCaregiverInvitations  = NewAppError("failed_to_read_caregiver_invitations", http.StatusInternalServerError, "failed to read caregiver invitations")
+	ErrFailedToUpdateCaregiverInvitation = NewAppError("failed_to_update_caregiver_invitation", http.StatusInternalServerError, "failed to update caregiver invitation")
 
 	// * meals
-	ErrMealsNotFound        = fmt.Errorf("meals not found")
-	ErrFailedToCreateMeal   = fmt.Errorf("failed to create meal")
-	ErrFailedToReadMeals    = fmt.Errorf("failed to read meals")
-	ErrFailedToUpdateMeal   = fmt.Errorf("failed to update meal")
-	ErrFailedToDeleteMeal   = fmt.Errorf("failed to delete meal")
-	ErrFailedToFindAllMeals = fmt.Errorf("failed to find all meals")
+	ErrMealsNotFound        = NewAppError("meals_not_found", http.StatusNotFound, "meals not found")
+	ErrFailedToCreateMeal   = NewAppError("failed_to_create_meal", http.StatusInternalServerError, "failed to create meal")
+	ErrFailedToReadMeals    = NewAppError("failed_to_read_meals", http.StatusInternalServerError, "failed to read meals")
+	ErrFailedToUpdateMeal   = NewAppError("failed_to_update_meal", http.StatusInternalServerError, "failed to update meal")
+	ErrFailedToDeleteMeal   = NewAppError("failed_to_delete_meal", http.StatusInternalServerError, "failed to delete meal")
+	ErrFailedToFindAllMeals = NewAppError("failed_to_find_all_meals", http.StatusInternalServerError, "failed to find all meals")
+
+	// * meal categories
+	ErrMealCategoryNotFound          = NewAppError("meal_category_not_found", http.StatusNotFound, "meal category not found")
+	ErrMealCategoryAlreadyExists     = NewAppError("meal_category_already_exists", http.StatusConflict, "meal category already exists")
+	ErrFailedToCreateMealCategory    = NewAppError("failed_to_create_meal_category", http.StatusInternalServerError, "failed to create meal category")
+	ErrFailedToUpdateMealCategory    = NewAppError("failed_to_update_meal_category", http.StatusInternalServerError, "failed to update meal category")
+	ErrFailedToDeleteMealCategory    = NewAppError("failed_to_delete_meal_category", http.StatusInternalServerError, "failed to delete meal category")
+	ErrFailedToSyncPartnerCategories = NewAppError("failed_to_sync_partner_categories", http.StatusInternalServerError, "failed to sync partner meal categories")
+
+	// * search
+	ErrSearchInvalidID         = NewAppError("search_invalid_id", http.StatusBadRequest, "search filter ids must be uuids separated by commas")
+	ErrFailedToSearchMeals     = NewAppError("failed_to_search_meals", http.StatusInternalServerError, "failed to search meals")
+	ErrFailedToIndexMealSearch = NewAppError("failed_to_index_meal_search", http.StatusInternalServerError, "failed to index meal search")
 
 	// * illnesses
-	ErrIllnessNotFound = fmt.Errorf("illness not found")
+	ErrIllnessNotFound = NewAppError("illness_not_found", http.StatusNotFound, "illness not found")
 
 	// * allergies
-	ErrAllergiesNotFound = fmt.Errorf("allergies not found")
+	ErrAllergiesNotFound = NewAppError("allergies_not_found", http.StatusNotFound, "allergies not found")
 
 	// * carts
-	ErrGettingCart        = fmt.Errorf("failed to get cart")
-	ErrFailedToUpdateCart = fmt.Errorf("failed to update cart")
-	ErrFailedToCreateCart = fmt.Errorf("failed to create cart")
-	ErrFailedToReadCart   = fmt.Errorf("failed to read cart")
-	ErrCartNotFound       = fmt.Errorf("cart not found")
-	ErrFailedToDeleteCart = fmt.Errorf("failed to delete cart")
+	ErrGettingCart        = NewAppError("getting_cart", http.StatusInternalServerError, "failed to get cart")
+	ErrFailedToUpdateCart = NewAppError("failed_to_update_cart", http.StatusInternalServerError, "failed to update cart")
+	ErrFailedToCreateCart = NewAppError("failed_to_create_cart", http.StatusInternalServerError, "failed to create cart")
+	ErrFailedToReadCart   = NewAppError("failed_to_read_cart", http.StatusInternalServerError, "failed to read cart")
+	ErrCartNotFound       = NewAppError("cart_not_found", http.StatusNotFound, "cart not found")
+	ErrFailedToDeleteCart = NewAppError("failed_to_delete_cart", http.StatusInternalServerError, "failed to delete cart")
 
 	// * organizations
-	ErrOrganizationNotFound = fmt.Errorf("organization not found")
+	ErrOrganizationNotFound = NewAppError("organization_not_found", http.StatusNotFound, "organization not found")
 
 	// * users
-	ErrUserNotFound         = fmt.Errorf("user not found")
-	ErrIncorrectPassword    = fmt.Errorf("incorrect password")
-	ErrUserIDNotFound       = fmt.Errorf("user ID is not found")
-	ErrUserAlreadyExist     = fmt.Errorf("user already exists")
-	ErrUserAlreadyConfirmed = fmt.Errorf("this user is already confirmed")
-	ErrUserNotSignedIn      = fmt.Errorf("you are not signed in")
-	ErrUserInvalidRole      = fmt.Errorf("invalid user role")
-	ErrFailedToUpdateUser   = fmt.Errorf("failed to update user")
+	ErrUserNotFound         = NewAppError("user_not_found", http.StatusNotFound, "user not found")
+	ErrIncorrectPassword    = NewAppError("incorrect_password", http.StatusUnauthorized, "incorrect password")
+	ErrUserIDNotFound       = NewAppError("user_id_not_found", http.StatusNotFound, "user ID is not found")
+	ErrUserAlreadyExist     = NewAppError("user_already_exist", http.StatusConflict, "user already exists")
+	ErrUserAlreadyConfirmed = NewAppError("user_already_confirmed", http.StatusConflict, "this user is already confirmed")
+	ErrUserNotSignedIn      = NewAppError("user_not_signed_in", http.StatusUnauthorized, "you are not signed in")
+	ErrUserInvalidRole      = NewAppError("user_invalid_role", http.StatusForbidden, "invalid user role")
+	ErrFailedToUpdateUser   = NewAppError("failed_to_update_user", http.StatusInternalServerError, "failed to update user")
 
 	// * files
-	ErrInvalidFileType         = fmt.Errorf("invalid file type")
-	ErrFailedToUploadFile      = fmt.Errorf("failed to upload file")
-	ErrFailedToCreateDirectory = fmt.Errorf("failed to create directory")
-	ErrFailedToParseFile       = fmt.Errorf("failed to parse file")
-	ErrFailedToWriteFile       = fmt.Errorf("failed to write file")
-	ErrFailedToOpenFile        = fmt.Errorf("failed to open file")
-	ErrFailedToReadFile        = fmt.Errorf("failed to read file")
-	ErrFailedToValidateFile    = fmt.Errorf("failed to validate file")
-	ErrTooManyFiles            = fmt.Errorf("too many files provided")
-	ErrNoFiles                 = fmt.Errorf("no files provided")
+	ErrInvalidFileType         = NewAppError("invalid_file_type", http.StatusBadRequest, "invalid file type")
+	ErrFailedToUploadFile      = NewAppError("failed_to_upload_file", http.StatusInternalServerError, "failed to upload file")
+	ErrFailedToCreateDirectory = NewAppError("failed_to_create_directory", http.StatusInternalServerError, "failed to create directory")
+	ErrFailedToParseFile       = NewAppError("failed_to_parse_file", http.StatusInternalServerError, "failed to parse file")
+	ErrFailedToWriteFile       = NewAppError("failed_to_write_file", http.StatusInternalServerError, "failed to write file")
+	ErrFailedToOpenFile        = NewAppError("failed_to_open_file", http.StatusInternalServerError, "failed to open file")
+	ErrFailedToReadFile        = NewAppError("failed_to_read_file", http.StatusInternalServerError, "failed to read file")
+	ErrFailedToValidateFile    = NewAppError("failed_to_validate_file", http.StatusBadRequest, "failed to validate file")
+	ErrTooManyFiles            = NewAppError("too_many_files", http.StatusBadRequest, "too many files provided")
+	ErrNoFiles                 = NewAppError("no_files", http.StatusBadRequest, "no files provided")
 
 	// * caches
-	ErrFailedToSetCache = fmt.Errorf("failed to set cache")
-	ErrFailedToGetCache = fmt.Errorf("failed to get cache")
+	ErrFailedToSetCache = NewAppError("failed_to_set_cache", http.StatusInternalServerError, "failed to set cache")
+	ErrFailedToGetCache = NewAppError("failed_to_get_cache", http.StatusInternalServerError, "failed to get cache")
 
 	// * email
-	ErrCannotChangeEmail = fmt.Errorf("cannot change existing email")
-	ErrTooQuickSendEmail = fmt.Errorf("an email was sent just under %v minutes ago", GetResetPasswordCooldown())
-	ErrDuplicateEmail    = fmt.Errorf("email address already exists")
-	ErrFailedToSendEmail = fmt.Errorf("failed to send email")
+	ErrCannotChangeEmail = NewAppError("cannot_change_email", http.StatusBadRequest, "cannot change existing email")
+	ErrTooQuickSendEmail = NewAppError("too_quick_send_email", http.StatusTooManyRequests, fmt.Sprintf("an email was sent just under %v minutes ago", GetResetPasswordCooldown()))
+	ErrDuplicateEmail    = NewAppError("duplicate_email", http.StatusConflict, "email address already exists")
+	ErrFailedToSendEmail = NewAppError("failed_to_send_email", http.StatusInternalServerError, "failed to send email")
 
 	// * geolocation
-	ErrGeolocationNotFound   = fmt.Errorf("location not found")
-	ErrInvalidGeolocation    = fmt.Errorf("invalid geolocation")
-	ErrInvalidDistanceMatrix = fmt.Errorf("invalid distance matrix")
+	ErrGeolocationNotFound   = NewAppError("geolocation_not_found", http.StatusNotFound, "location not found")
+	ErrInvalidGeolocation    = NewAppError("invalid_geolocation", http.StatusBadRequest, "invalid geolocation")
+	ErrInvalidDistanceMatrix = NewAppError("invalid_distance_matrix", http.StatusBadRequest, "invalid distance matrix")
 
 	// * images
-	ErrFailedToCreateImage = fmt.Errorf("failed to create image")
+	ErrFailedToCreateImage = NewAppError("failed_to_create_image", http.StatusInternalServerError, "failed to create image")
+
+	// * permissions
+	ErrPermissionDenied         = NewAppError("permission_denied", http.StatusForbidden, "you do not have the permission to perform this action")
+	ErrNotResourceOwner         = NewAppError("not_resource_owner", http.StatusForbidden, "you do not own this resource")
+	ErrFailedToReadPermissions  = NewAppError("failed_to_read_permissions", http.StatusInternalServerError, "failed to read permissions")
+	ErrFailedToUpdatePermission = NewAppError("failed_to_update_permission", http.StatusInternalServerError, "failed to update permissions")
+
+	// * data exports
+	ErrDataExportNotFound         = NewAppError("data_export_not_found", http.StatusNotFound, "data export not found")
+	ErrDataExportAlreadyRequested = NewAppError("data_export_already_requested", http.StatusConflict, "a data export is already being prepared")
+	ErrDataExportNotReady         = NewAppError("data_export_not_ready", http.StatusConflict, "data export is not ready yet")
+	ErrDataExportExpired          = NewAppError("data_export_expired", http.StatusGone, "data export has expired")
+	ErrFailedToCreateDataExport   = NewAppError("failed_to_create_data_export", http.StatusInternalServerError, "failed to create data export")
+	ErrFailedToReadDataExports    = NewAppError("failed_to_read_data_exports", http.StatusInternalServerError, "failed to read data exports")
+	ErrFailedToBuildDataExport    = NewAppError("failed_to_build_data_export", http.StatusInternalServerError, "failed to build data export")
+
+	// * data erasures
+	ErrDataErasureAlreadyRequested = NewAppError("data_erasure_already_requested", http.StatusConflict, "a data erasure is already requested")
+	ErrFailedToCreateDataErasure   = NewAppError("failed_to_create_data_erasure", http.StatusInternalServerError, "failed to create data erasure")
+	ErrFailedToReadDataErasures    = NewAppError("failed_to_read_data_erasures", http.StatusInternalServerError, "failed to read data erasures")
+	ErrFailedToEraseData           = NewAppError("failed_to_erase_data", http.StatusInternalServerError, "failed to erase data")
+
+	// * encryptions
+	ErrEncryptionNotInitialized = NewAppError("encryption_not_initialized", http.StatusInternalServerError, "encryption is not initialized")
+	ErrInvalidCiphertext        = NewAppError("invalid_ciphertext", http.StatusInternalServerError, "invalid ciphertext")
+	ErrFailedToReEncrypt        = NewAppError("failed_to_re_encrypt", http.StatusInternalServerError, "failed to re-encrypt data")
 )
@@ -0,0 +1,14 @@
+package consttypes
+
+type (
+	HealthStatus string
+)
+
+const (
+	HS_OK          HealthStatus = "ok"
+	HS_UNAVAILABLE HealthStatus = "unavailable"
+)
+
+func (enum HealthStatus) String() string {
+	return string(enum)
+}
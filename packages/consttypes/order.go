@@ -28,6 +28,18 @@ const (
 	OS_CANCELLED OrderStatus = "Cancelled"
 )
 
+func OrderStatuses() []OrderStatus {
+	return []OrderStatus{
+		OS_PLACED,
+		OS_CONFIRMED,
+		OS_BEING_PREPARED,
+		OS_PREPARED,
+		OS_PICKED_UP,
+		OS_COMPLETED,
+		OS_CANCELLED,
+	}
+}
+
 func (enum OrderStatus) String() string {
 	return string(enum)
 }
@@ -0,0 +1,69 @@
+package consttypes
+
+import "slices"
+
+type (
+	FilterOperator  string
+	FilterFieldType string
+	SortDirection   string
+)
+
+const (
+	FO_EQ   FilterOperator = "eq"
+	FO_NE   FilterOperator = "ne"
+	FO_IN   FilterOperator = "in"
+	FO_NIN  FilterOperator = "nin"
+	FO_GT   FilterOperator = "gt"
+	FO_GTE  FilterOperator = "gte"
+	FO_LT   FilterOperator = "lt"
+	FO_LTE  FilterOperator = "lte"
+	FO_LIKE FilterOperator = "like"
+
+	// * takes true or false, for is null and is not null
+	FO_NULL FilterOperator = "null"
+)
+
+const (
+	FFT_STRING FilterFieldType = "string"
+	FFT_UUID   FilterFieldType = "uuid"
+	FFT_TIME   FilterFieldType = "time"
+	FFT_NUMBER FilterFieldType = "number"
+	FFT_BOOL   FilterFieldType = "bool"
+	FFT_ENUM   FilterFieldType = "enum"
+)
+
+const (
+	SD_ASC  SortDirection = "asc"
+	SD_DESC SortDirection = "desc"
+)
+
+func (enum FilterOperator) String() string {
+	return string(enum)
+}
+
+func (enum FilterOperator) IsValid() bool {
+	return slices.Contains([]FilterOperator{FO_EQ, FO_NE, FO_IN, FO_NIN, FO_GT, FO_GTE, FO_LT, FO_LTE, FO_LIKE, FO_NULL}, enum)
+}
+
+// * the operators that compare the order of the values
+func (enum FilterOperator) IsRange() bool {
+	return slices.Contains([]FilterOperator{FO_GT, FO_GTE, FO_LT, FO_LTE}, enum)
+}
+
+// * the operators that can filter an enum, its values have no order
+// * and a part of a value is not a value
+func (enum FilterOperator) IsEnum() bool {
+	return slices.Contains([]FilterOperator{FO_EQ, FO_NE, FO_IN}, enum)
+}
+
+func (enum FilterFieldType) String() string {
+	return string(enum)
+}
+
+func (enum SortDirection) String() string {
+	return string(enum)
+}
+
+func (enum SortDirection) IsValid() bool {
+	return slices.Contains([]SortDirection{SD_ASC, SD_DESC}, enum)
+}
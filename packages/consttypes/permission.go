@@ -0,0 +1,185 @@
+package consttypes
+
+import "slices"
+
+type (
+	Permission string
+)
+
+const (
+	// * meals
+	P_MEAL_CREATE   Permission = "meal:create"
+	P_MEAL_READ     Permission = "meal:read"
+	P_MEAL_UPDATE   Permission = "meal:update"
+	P_MEAL_DELETE   Permission = "meal:delete"
+	P_MEAL_READ_OWN Permission = "meal:read_own"
+
+	// * meal categories, they are read publicly
+	P_MEAL_CATEGORY_CREATE Permission = "meal_category:create"
+	P_MEAL_CATEGORY_UPDATE Permission = "meal_category:update"
+	P_MEAL_CATEGORY_DELETE Permission = "meal_category:delete"
+
+	// * members
+	P_MEMBER_CREATE         Permission = "member:create"
+	P_MEMBER_READ           Permission = "member:read"
+	P_MEMBER_UPDATE         Permission = "member:update"
+	P_MEMBER_DELETE         Permission = "member:delete"
+	P_MEMBER_READ_MEDICAL   Permission = "member:read_medical"
+	P_MEMBER_UPDATE_MEDICAL Permission = "member:update_medical"
+
+	// * caregivers
+	P_CAREGIVER_READ   Permission = "caregiver:read"
+	P_CAREGIVER_UPDATE Permission = "caregiver:update"
+
+	// * partners
+	P_PARTNER_CREATE Permission = "partner:create"
+	P_PARTNER_READ   Permission = "partner:read"
+	P_PARTNER_UPDATE Permission = "partner:update"
+	P_PARTNER_DELETE Permission = "partner:delete"
+
+	// * patrons
+	P_PATRON_CREATE Permission = "patron:create"
+	P_PATRON_READ   Permission = "patron:read"
+	P_PATRON_UPDATE Permission = "patron:update"
+	P_PATRON_DELETE Permission = "patron:delete"
+
+	// * illnesses
+	P_ILLNESS_CREATE Permission = "illness:create"
+	P_ILLNESS_READ   Permission = "illness:read"
+	P_ILLNESS_UPDATE Permission = "illness:update"
+	P_ILLNESS_DELETE Permission = "illness:delete"
+
+	// * allergies
+	P_ALLERGY_CREATE Permission = "allergy:create"
+	P_ALLERGY_READ   Permission = "allergy:read"
+	P_ALLERGY_UPDATE Permission = "allergy:update"
+	P_ALLERGY_DELETE Permission = "allergy:delete"
+
+	// * donations
+	P_DONATION_CREATE Permission = "donation:create"
+	P_DONATION_READ   Permission = "donation:read"
+	P_DONATION_UPDATE Permission = "donation:update"
+	P_DONATION_DELETE Permission = "donation:delete"
+
+	// * carts
+	P_CART_CREATE Permission = "cart:create"
+	P_CART_READ   Permission = "cart:read"
+	P_CART_UPDATE Permission = "cart:update"
+	P_CART_DELETE Permission = "cart:delete"
+
+	// * orders
+	P_ORDER_CREATE        Permission = "order:create"
+	P_ORDER_READ          Permission = "order:read"
+	P_ORDER_UPDATE_STATUS Permission = "order:update_status"
+
+	// * partner orders, the orders placed at the partner's kitchen
+	P_PARTNER_ORDER_READ Permission = "partner_order:read"
+
+	// * profiles
+	P_PROFILE_READ   Permission = "profile:read"
+	P_PROFILE_UPDATE Permission = "profile:update"
+	P_PROFILE_EXPORT Permission = "profile:export"
+	P_PROFILE_ERASE  Permission = "profile:erase"
+
+	// * users
+	P_USER_ERASE                Permission = "user:erase"
+	P_USER_READ_SECURITY_EVENTS Permission = "user:read_security_events"
+
+	// * permissions
+	P_PERMISSION_READ   Permission = "permission:read"
+	P_PERMISSION_UPDATE Permission = "permission:update"
+
+	// * service accounts
+	P_SERVICE_ACCOUNT_MANAGE Permission = "service_account:manage"
+
+	// * webhooks
+	P_WEBHOOK_MANAGE Permission = "webhook:manage"
+
+	// * audit logs
+	P_AUDIT_LOG_READ Permission = "audit_log:read"
+
+	// * analytics
+	P_ANALYTICS_READ         Permission = "analytics:read"
+	P_PARTNER_ANALYTICS_READ Permission = "partner_analytics:read"
+)
+
+func (enum Permission) String() string {
+	return string(enum)
+}
+
+// * list of every permission known by the system, used to
+// * validate role-to-permission mapping changes made by admins
+func Permissions() []Permission {
+	return []Permission{
+		P_MEAL_CREATE, P_MEAL_READ, P_MEAL_UPDATE, P_MEAL_DELETE, P_MEAL_READ_OWN,
+		P_MEAL_CATEGORY_CREATE, P_MEAL_CATEGORY_UPDATE, P_MEAL_CATEGORY_DELETE,
+		P_MEMBER_CREATE, P_MEMBER_READ, P_MEMBER_UPDATE, P_MEMBER_DELETE, P_MEMBER_READ_MEDICAL, P_MEMBER_UPDATE_MEDICAL,
+		P_CAREGIVER_READ, P_CAREGIVER_UPDATE,
+		P_PARTNER_CREATE, P_PARTNER_READ, P_PARTNER_UPDATE, P_PARTNER_DELETE,
+		P_PATRON_CREATE, P_PATRON_READ, P_PATRON_UPDATE, P_PATRON_DELETE,
+		P_ILLNESS_CREATE, P_ILLNESS_READ, P_ILLNESS_UPDATE, P_ILLNESS_DELETE,
+		P_ALLERGY_CREATE, P_ALLERGY_READ, P_ALLERGY_UPDATE, P_ALLERGY_DELETE,
+		P_DONATION_CREATE, P_DONATION_READ, P_DONATION_UPDATE, P_DONATION_DELETE,
+		P_CART_CREATE, P_CART_READ, P_CART_UPDATE, P_CART_DELETE,
+		P_ORDER_CREATE, P_ORDER_READ, P_ORDER_UPDATE_STATUS,
+		P_PARTNER_ORDER_READ,
+		P_PROFILE_READ, P_PROFILE_UPDATE, P_PROFILE_EXPORT, P_PROFILE_ERASE,
+		P_USER_ERASE, P_USER_READ_SECURITY_EVENTS,
+		P_PERMISSION_READ, P_PERMISSION_UPDATE,
+		P_SERVICE_ACCOUNT_MANAGE,
+		P_WEBHOOK_MANAGE,
+		P_AUDIT_LOG_READ,
+		P_ANALYTICS_READ, P_PARTNER_ANALYTICS_READ,
+	}
+}
+
+func (enum Permission) IsValid() bool {
+	return slices.Contains(Permissions(), enum)
+}
+
+// * permissions a role always keeps, without them no admin could
+// * manage the mapping anymore and nothing could undo the change
+func ProtectedRolePermissions() map[UserRole][]Permission {
+	return map[UserRole][]Permission{
+		UR_ADMIN: {P_PERMISSION_READ, P_PERMISSION_UPDATE},
+	}
+}
+
+// * default role-to-permission mapping, seeded into the database on
+// * the first boot. admins can change the mapping afterwards.
+func DefaultRolePermissions() map[UserRole][]Permission {
+	return map[UserRole][]Permission{
+		UR_ADMIN: Permissions(),
+		UR_MEMBER: {
+			P_MEMBER_READ_MEDICAL, P_MEMBER_UPDATE_MEDICAL,
+			P_CAREGIVER_READ, P_CAREGIVER_UPDATE,
+			P_CART_CREATE, P_CART_READ, P_CART_UPDATE, P_CART_DELETE,
+			P_ORDER_CREATE, P_ORDER_READ,
+			P_PROFILE_READ, P_PROFILE_UPDATE, P_PROFILE_EXPORT, P_PROFILE_ERASE,
+		},
+		// * caregivers act for their linked members, what they can do
+		// * for each member is further limited by the link itself
+		UR_CAREGIVER: {
+			P_MEMBER_READ_MEDICAL, P_MEMBER_UPDATE_MEDICAL,
+			P_CART_CREATE, P_CART_READ, P_CART_UPDATE, P_CART_DELETE,
+			P_ORDER_CREATE, P_ORDER_READ,
+			P_PROFILE_READ, P_PROFILE_UPDATE, P_PROFILE_EXPORT, P_PROFILE_ERASE,
+		},
+		UR_PARTNER: {
+			P_MEAL_READ_OWN,
+			P_PARTNER_ORDER_READ, P_ORDER_UPDATE_STATUS,
+			P_PROFILE_READ, P_PROFILE_UPDATE, P_PROFILE_EXPORT, P_PROFILE_ERASE,
+			P_WEBHOOK_MANAGE,
+			P_PARTNER_ANALYTICS_READ,
+		},
+		UR_PATRON: {
+			P_DONATION_CREATE,
+			P_PROFILE_READ, P_PROFILE_UPDATE, P_PROFILE_EXPORT, P_PROFILE_ERASE,
+		},
+		UR_ORGANIZATION: {
+			P_MEMBER_READ_MEDICAL,
+			P_PROFILE_READ, P_PROFILE_UPDATE, P_PROFILE_EXPORT, P_PROFILE_ERASE,
+			P_WEBHOOK_MANAGE,
+		},
+	}
+}
@@ -17,3 +17,7 @@ const (
 func (enum UserRole) Uint() uint {
 	return uint(enum)
 }
+
+func (enum UserRole) IsValid() bool {
+	return enum <= UR_ORGANIZATION
+}
@@ -0,0 +1,80 @@
+package consttypes
+
+type (
+	ThrottleAction         string
+	SecurityEventType      string
+	TwoFactorPolicy        string
+	TwoFactorChallengeType string
+)
+
+const (
+	TA_SIGNIN          ThrottleAction = "signin"
+	TA_FORGOT_PASSWORD ThrottleAction = "forgot-password"
+	TA_VERIFY_SEND     ThrottleAction = "verify-send"
+	TA_REGISTER        ThrottleAction = "register"
+)
+
+const (
+	SET_SIGNIN_FAILED        SecurityEventType = "Signin Failed"
+	SET_THROTTLED            SecurityEventType = "Throttled"
+	SET_ACCOUNT_LOCKED       SecurityEventType = "Account Locked"
+	SET_ACCOUNT_UNLOCKED     SecurityEventType = "Account Unlocked"
+	SET_REFRESH_TOKEN_REUSED SecurityEventType = "Refresh Token Reused"
+	SET_TWO_FACTOR_FAILED    SecurityEventType = "Two Factor Failed"
+	SET_TWO_FACTOR_ENABLED   SecurityEventType = "Two Factor Enabled"
+	SET_TWO_FACTOR_DISABLED  SecurityEventType = "Two Factor Disabled"
+	SET_RECOVERY_CODE_USED   SecurityEventType = "Recovery Code Used"
+	SET_API_KEY_CREATED      SecurityEventType = "API Key Created"
+	SET_API_KEY_REVOKED      SecurityEventType = "API Key Revoked"
+)
+
+const (
+	TFP_REQUIRED TwoFactorPolicy = "required"
+	TFP_OPTIONAL TwoFactorPolicy = "optional"
+	TFP_DISABLED TwoFactorPolicy = "disabled"
+)
+
+const (
+	TFCT_VERIFY TwoFactorChallengeType = "verify"
+	TFCT_ENROL  TwoFactorChallengeType = "enrol"
+)
+
+func ThrottleActions() []ThrottleAction {
+	return []ThrottleAction{TA_SIGNIN, TA_FORGOT_PASSWORD, TA_VERIFY_SEND, TA_REGISTER}
+}
+
+func (enum ThrottleAction) String() string {
+	return string(enum)
+}
+
+func SecurityEventTypes() []SecurityEventType {
+	return []SecurityEventType{
+		SET_SIGNIN_FAILED,
+		SET_THROTTLED,
+		SET_ACCOUNT_LOCKED,
+		SET_ACCOUNT_UNLOCKED,
+		SET_REFRESH_TOKEN_REUSED,
+		SET_TWO_FACTOR_FAILED,
+		SET_TWO_FACTOR_ENABLED,
+		SET_TWO_FACTOR_DISABLED,
+		SET_RECOVERY_CODE_USED,
+		SET_API_KEY_CREATED,
+		SET_API_KEY_REVOKED,
+	}
+}
+
+func (enum SecurityEventType) String() string {
+	return string(enum)
+}
+
+func (enum TwoFactorPolicy) String() string {
+	return string(enum)
+}
+
+func (enum TwoFactorPolicy) IsValid() bool {
+	return enum == TFP_REQUIRED || enum == TFP_OPTIONAL || enum == TFP_DISABLED
+}
+
+func (enum TwoFactorChallengeType) String() string {
+	return string(enum)
+}
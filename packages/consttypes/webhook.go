@@ -0,0 +1,45 @@
+package consttypes
+
+import "slices"
+
+type (
+	WebhookEventType      string
+	WebhookDeliveryStatus string
+)
+
+const (
+	WET_PING                 WebhookEventType = "ping"
+	WET_ORDER_CREATED        WebhookEventType = "order.created"
+	WET_ORDER_STATUS_CHANGED WebhookEventType = "order.status_changed"
+)
+
+const (
+	// * waiting in the queue for the next attempt
+	WDS_PENDING WebhookDeliveryStatus = "Pending"
+
+	// * the last attempt failed, waiting for the backoff to pass
+	WDS_RETRYING  WebhookDeliveryStatus = "Retrying"
+	WDS_DELIVERED WebhookDeliveryStatus = "Delivered"
+	WDS_FAILED    WebhookDeliveryStatus = "Failed"
+)
+
+func (enum WebhookEventType) String() string {
+	return string(enum)
+}
+
+// * list of every event type a webhook can subscribe to, pings are
+// * always sent on request so they can not be subscribed to
+func WebhookEventTypes() []WebhookEventType {
+	return []WebhookEventType{
+		WET_ORDER_CREATED,
+		WET_ORDER_STATUS_CHANGED,
+	}
+}
+
+func (enum WebhookEventType) IsValid() bool {
+	return slices.Contains(WebhookEventTypes(), enum)
+}
+
+func (enum WebhookDeliveryStatus) String() string {
+	return string(enum)
+}
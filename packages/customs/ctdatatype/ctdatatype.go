@@ -55,11 +55,25 @@ func (date *CDT_DATE) Scan(value any) error {
 		return nil
 	}
 
-	t, ok := value.(time.Time)
-	if !ok {
-		return fmt.Errorf("unable to convert value to time.Time")
+	switch v := value.(type) {
+	case time.Time:
+		*date = CDT_DATE{Time: v}
+		return nil
+	case string:
+		// * dates stored as text, e.g. from a date column that was
+		// * converted into an encrypted text column
+		for _, layout := range []string{
+			"2006-01-02 15:04:05.999999999-07",
+			"2006-01-02 15:04:05.999999999-07:00",
+			time.RFC3339Nano,
+			consttypes.DATEFORMAT,
+		} {
+			if t, err := time.Parse(layout, v); err == nil {
+				*date = CDT_DATE{Time: t}
+				return nil
+			}
+		}
 	}
 
-	*date = CDT_DATE{Time: t}
-	return nil
+	return fmt.Errorf("unable to convert value to time.Time")
 }
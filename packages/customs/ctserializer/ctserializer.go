@@ -0,0 +1,80 @@
+package ctserializer
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"project-skbackend/packages/utils/utcrypto"
+	"reflect"
+
+	"gorm.io/gorm/schema"
+)
+
+type (
+	EncryptedSerializer struct{}
+)
+
+func Register() {
+	schema.RegisterSerializer("encrypted", EncryptedSerializer{})
+}
+
+// ! ------------------------------- ENCRYPTED -------------------------------- ! //
+func (EncryptedSerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue any) error {
+	var (
+		data []byte
+	)
+
+	fieldValue := reflect.New(field.FieldType)
+
+	switch v := dbValue.(type) {
+	case nil:
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("unable to convert value to encrypted field: %#v", dbValue)
+	}
+
+	if len(data) > 0 {
+		if err := scan(fieldValue, data); err != nil {
+			return err
+		}
+	}
+
+	field.ReflectValueOf(ctx, dst).Set(fieldValue.Elem())
+	return nil
+}
+
+func (EncryptedSerializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue any) (any, error) {
+	plaintext, err := json.Marshal(fieldValue)
+	if err != nil {
+		return nil, err
+	}
+
+	if string(plaintext) == "null" {
+		return nil, nil
+	}
+
+	return utcrypto.Encrypt(plaintext)
+}
+
+func scan(fieldValue reflect.Value, data []byte) error {
+	if utcrypto.IsEncrypted(string(data)) {
+		plaintext, err := utcrypto.Decrypt(string(data))
+		if err != nil {
+			return err
+		}
+
+		return json.Unmarshal(plaintext, fieldValue.Interface())
+	}
+
+	// * the value was written before the column was encrypted, it
+	// * is read as it is until the re-encryption job picks it up
+	if scanner, ok := fieldValue.Interface().(sql.Scanner); ok {
+		return scanner.Scan(string(data))
+	}
+
+	return json.Unmarshal(data, fieldValue.Interface())
+}
@@ -0,0 +1,239 @@
+package ctserializer
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"project-skbackend/packages/customs/ctdatatype"
+	"project-skbackend/packages/utils/utcrypto"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm/schema"
+)
+
+type (
+	snapshot struct {
+		Height float64 `json:"height"`
+	}
+
+	record struct {
+		ID       uint
+		Height   float64             `gorm:"serializer:encrypted"`
+		Birth    ctdatatype.CDT_DATE `gorm:"serializer:encrypted"`
+		RefID    uuid.UUID           `gorm:"serializer:encrypted"`
+		Previous *snapshot           `gorm:"serializer:encrypted"`
+		Secret   string              `gorm:"serializer:encrypted"`
+	}
+)
+
+func setup(t *testing.T, keys map[string]string, current string) *schema.Schema {
+	t.Helper()
+
+	if err := utcrypto.Init(keys, current); err != nil {
+		t.Fatal(err)
+	}
+
+	Register()
+
+	s, err := schema.Parse(&record{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s
+}
+
+func newKey(t *testing.T) string {
+	t.Helper()
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+// * writes the value of the field through the serializer and reads it back
+// * into an empty record, like a save and a find of gorm
+func roundtrip(t *testing.T, s *schema.Schema, name string, value any) (any, *record) {
+	t.Helper()
+
+	field := s.LookUpField(name)
+
+	stored, err := EncryptedSerializer{}.Value(context.Background(), field, reflect.Value{}, value)
+	if err != nil {
+		t.Fatalf("%s: value err = %v", name, err)
+	}
+
+	var (
+		dst record
+	)
+
+	if err := (EncryptedSerializer{}).Scan(context.Background(), field, reflect.ValueOf(&dst), stored); err != nil {
+		t.Fatalf("%s: scan err = %v", name, err)
+	}
+
+	return stored, &dst
+}
+
+func TestEncryptedRoundTrip(t *testing.T) {
+	s := setup(t, map[string]string{"k1": newKey(t)}, "k1")
+
+	var (
+		birth = ctdatatype.CDT_DATE{Time: time.Date(2000, 10, 20, 0, 0, 0, 0, time.UTC)}
+		ref   = uuid.New()
+	)
+
+	cases := []struct {
+		name  string
+		value any
+		check func(r *record) bool
+	}{
+		{"Height", 19.5, func(r *record) bool { return r.Height == 19.5 }},
+		{"Height", 0.0, func(r *record) bool { return r.Height == 0 }},
+		{"Birth", birth, func(r *record) bool { return r.Birth.Equal(birth.Time) }},
+		{"Birth", ctdatatype.CDT_DATE{}, func(r *record) bool { return r.Birth.IsZero() }},
+		{"RefID", ref, func(r *record) bool { return r.RefID == ref }},
+		{"RefID", uuid.Nil, func(r *record) bool { return r.RefID == uuid.Nil }},
+		{"Previous", &snapshot{Height: 100}, func(r *record) bool { return r.Previous != nil && r.Previous.Height == 100 }},
+		{"Secret", "JBSWY3DPEHPK3PXP", func(r *record) bool { return r.Secret == "JBSWY3DPEHPK3PXP" }},
+		{"Secret", "", func(r *record) bool { return r.Secret == "" }},
+	}
+
+	for _, c := range cases {
+		stored, r := roundtrip(t, s, c.name, c.value)
+
+		str, ok := stored.(string)
+		if !ok || !strings.HasPrefix(str, "enc:k1:") {
+			t.Errorf("%s %v: stored %#v, want an encrypted value", c.name, c.value, stored)
+		}
+
+		if !c.check(r) {
+			t.Errorf("%s %v: read back %+v", c.name, c.value, r)
+		}
+	}
+}
+
+func TestEncryptedNil(t *testing.T) {
+	s := setup(t, map[string]string{"k1": newKey(t)}, "k1")
+
+	// * a nil pointer is stored as null instead of an encrypted null
+	stored, r := roundtrip(t, s, "Previous", (*snapshot)(nil))
+	if stored != nil {
+		t.Errorf("stored %#v, want nil", stored)
+	}
+
+	if r.Previous != nil {
+		t.Errorf("read back %+v, want nil", r.Previous)
+	}
+
+	var (
+		dst = record{Height: 1}
+	)
+
+	if err := (EncryptedSerializer{}).Scan(context.Background(), s.LookUpField("Height"), reflect.ValueOf(&dst), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if dst.Height != 0 {
+		t.Errorf("null scanned into %v, want the zero value", dst.Height)
+	}
+}
+
+func TestEncryptedKeyRotation(t *testing.T) {
+	var (
+		k1 = newKey(t)
+		k2 = newKey(t)
+	)
+
+	s := setup(t, map[string]string{"k1": k1}, "k1")
+
+	old, _ := roundtrip(t, s, "Height", 100.0)
+
+	s = setup(t, map[string]string{"k1": k1, "k2": k2}, "k2")
+
+	var (
+		dst record
+	)
+
+	if err := (EncryptedSerializer{}).Scan(context.Background(), s.LookUpField("Height"), reflect.ValueOf(&dst), old); err != nil || dst.Height != 100 {
+		t.Fatalf("old key: height = %v, err = %v, want 100", dst.Height, err)
+	}
+
+	stored, _ := roundtrip(t, s, "Height", 100.0)
+	if !strings.HasPrefix(stored.(string), "enc:k2:") {
+		t.Errorf("stored %q, want it written with the new key", stored)
+	}
+}
+
+func TestEncryptedTampered(t *testing.T) {
+	s := setup(t, map[string]string{"k1": newKey(t)}, "k1")
+
+	stored, _ := roundtrip(t, s, "Height", 100.0)
+	value := stored.(string)
+
+	for name, tampered := range map[string]any{
+		"flipped byte": value[:len(value)-2] + string(value[len(value)-2]^0x01) + value[len(value)-1:],
+		"unknown key":  strings.Replace(value, "enc:k1:", "enc:k9:", 1),
+		"truncated":    value[:len("enc:k1:")+4],
+		"as bytes":     []byte(value[:len(value)-4] + "AAAA"),
+	} {
+		var (
+			dst record
+		)
+
+		if err := (EncryptedSerializer{}).Scan(context.Background(), s.LookUpField("Height"), reflect.ValueOf(&dst), tampered); err == nil {
+			t.Errorf("%s: err = nil, want the value rejected", name)
+		}
+	}
+}
+
+// * the values written before a column was encrypted are read as they are
+func TestEncryptedPlaintext(t *testing.T) {
+	s := setup(t, map[string]string{"k1": newKey(t)}, "k1")
+
+	var (
+		ref = uuid.New()
+	)
+
+	cases := []struct {
+		name  string
+		value any
+		check func(r *record) bool
+	}{
+		{"Height", "19.5", func(r *record) bool { return r.Height == 19.5 }},
+		{"Height", []byte("100"), func(r *record) bool { return r.Height == 100 }},
+		{"Birth", "2000-10-20", func(r *record) bool { return r.Birth.Format("2006-01-02") == "2000-10-20" }},
+		{"Birth", "2000-10-20 00:00:00+00", func(r *record) bool { return r.Birth.Format("2006-01-02") == "2000-10-20" }},
+		{"RefID", ref.String(), func(r *record) bool { return r.RefID == ref }},
+		{"Height", "", func(r *record) bool { return r.Height == 0 }},
+	}
+
+	for _, c := range cases {
+		var (
+			dst record
+		)
+
+		if err := (EncryptedSerializer{}).Scan(context.Background(), s.LookUpField(c.name), reflect.ValueOf(&dst), c.value); err != nil {
+			t.Fatalf("%s %v: err = %v", c.name, c.value, err)
+		}
+
+		if !c.check(&dst) {
+			t.Errorf("%s %v: read back %+v", c.name, c.value, dst)
+		}
+	}
+
+	var (
+		dst record
+	)
+
+	if err := (EncryptedSerializer{}).Scan(context.Background(), s.LookUpField("Height"), reflect.ValueOf(&dst), 19.5); err == nil {
+		t.Error("a value that is not text: err = nil, want an error")
+	}
+}
@@ -0,0 +1,224 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"project-skbackend/packages/utils/utlogger"
+	"sync"
+	"time"
+)
+
+const (
+	DEFAULT_SHUTDOWN_TIMEOUT = 30 * time.Second
+)
+
+type (
+	// * live is false when the component is broken and the process should
+	// * be restarted, ready is false when it can not take work right now
+	Health struct {
+		Live  bool   `json:"live"`
+		Ready bool   `json:"ready"`
+		Error string `json:"error,omitempty"`
+	}
+
+	ComponentHealth struct {
+		Name string `json:"name"`
+		Health
+	}
+
+	// * a part of the process started and stopped by the manager, start
+	// * returns once it runs and stop returns once its work is finished
+	Component interface {
+		Name() string
+		Start(ctx context.Context) error
+		Stop(ctx context.Context) error
+		Health() Health
+	}
+
+	// * a component that can fail while it runs, e.g. the http server
+	Notifier interface {
+		Notify() <-chan error
+	}
+
+	Manager struct {
+		components      []Component
+		shutdownTimeout time.Duration
+
+		mu       sync.RWMutex
+		started  []Component
+		stopping bool
+
+		notify chan error
+	}
+
+	Option func(*Manager)
+)
+
+func ShutdownTimeout(timeout time.Duration) Option {
+	return func(m *Manager) {
+		m.shutdownTimeout = timeout
+	}
+}
+
+// * the components are started in the given order and stopped in the
+// * reverse one, so a component is given after the ones it depends on
+func NewManager(opts ...Option) *Manager {
+	m := &Manager{
+		shutdownTimeout: DEFAULT_SHUTDOWN_TIMEOUT,
+		notify:          make(chan error, 1),
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
+}
+
+func (m *Manager) Add(components ...Component) {
+	m.components = append(m.components, components...)
+}
+
+// * Start starts the components in order, the started ones are stopped
+// * again when one of them fails to start
+func (m *Manager) Start(ctx context.Context) error {
+	for _, c := range m.components {
+		utlogger.InfoContext(ctx, fmt.Sprintf("Starting %s", c.Name()))
+
+		if err := c.Start(ctx); err != nil {
+			err = fmt.Errorf("start %s: %w", c.Name(), err)
+			utlogger.ErrorContext(ctx, err)
+
+			sctx, cancel := context.WithTimeout(context.Background(), m.shutdownTimeout)
+			defer cancel()
+
+			return errors.Join(err, m.Stop(sctx))
+		}
+
+		m.mu.Lock()
+		m.started = append(m.started, c)
+		m.mu.Unlock()
+
+		if n, ok := c.(Notifier); ok {
+			go m.watch(c.Name(), n.Notify())
+		}
+	}
+
+	return nil
+}
+
+func (m *Manager) watch(name string, notify <-chan error) {
+	err, ok := <-notify
+	if !ok || err == nil {
+		return
+	}
+
+	select {
+	case m.notify <- fmt.Errorf("%s: %w", name, err):
+	default:
+	}
+}
+
+// * Stop stops the started components in the reverse order, every one of
+// * them is stopped even when one fails, within the deadline of the ctx
+func (m *Manager) Stop(ctx context.Context) error {
+	m.mu.Lock()
+	m.stopping = true
+	started := m.started
+	m.started = nil
+	m.mu.Unlock()
+
+	var (
+		errs []error
+	)
+
+	for i := len(started) - 1; i >= 0; i-- {
+		c := started[i]
+		utlogger.InfoContext(ctx, fmt.Sprintf("Stopping %s", c.Name()))
+
+		if err := c.Stop(ctx); err != nil {
+			err = fmt.Errorf("stop %s: %w", c.Name(), err)
+			utlogger.ErrorContext(ctx, err)
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// * Run starts the components and stops them once the ctx is done, e.g.
+// * on a signal, or once a component fails while it runs
+func (m *Manager) Run(ctx context.Context) error {
+	if err := m.Start(ctx); err != nil {
+		return err
+	}
+
+	var (
+		err error
+	)
+
+	select {
+	case <-ctx.Done():
+		utlogger.InfoContext(ctx, "Shutting down: " + context.Cause(ctx).Error())
+	case err = <-m.notify:
+		utlogger.ErrorContext(ctx, err)
+	}
+
+	sctx, cancel := context.WithTimeout(context.Background(), m.shutdownTimeout)
+	defer cancel()
+
+	return errors.Join(err, m.Stop(sctx))
+}
+
+// * Health reports every component, one that is not started yet or is
+// * already stopped is live but not ready
+func (m *Manager) Health() []ComponentHealth {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	healths := make([]ComponentHealth, 0, len(m.components))
+	for _, c := range m.components {
+		health := Health{Live: true}
+		for _, s := range m.started {
+			if s == c {
+				health = c.Health()
+				break
+			}
+		}
+
+		healths = append(healths, ComponentHealth{Name: c.Name(), Health: health})
+	}
+
+	return healths
+}
+
+func (m *Manager) Live() bool {
+	for _, ch := range m.Health() {
+		if !ch.Live {
+			return false
+		}
+	}
+
+	return true
+}
+
+// * the process is not ready while it shuts down, so no new work is
+// * routed to it while the components drain
+func (m *Manager) Ready() bool {
+	m.mu.RLock()
+	stopping := m.stopping
+	m.mu.RUnlock()
+
+	if stopping {
+		return false
+	}
+
+	for _, ch := range m.Health() {
+		if !ch.Ready {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,23 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/go-co-op/gocron/v2"
+	"github.com/google/uuid"
+)
+
+type (
+	// * records the runs of the named jobs of a scheduler
+	CronMonitor struct{}
+)
+
+var _ gocron.Monitor = CronMonitor{}
+
+func (CronMonitor) IncrementJob(id uuid.UUID, name string, tags []string, status gocron.JobStatus) {
+	cronruns.WithLabelValues(name, string(status)).Inc()
+}
+
+func (CronMonitor) RecordJobTiming(start time.Time, end time.Time, id uuid.UUID, name string, tags []string) {
+	cronduration.WithLabelValues(name).Observe(end.Sub(start).Seconds())
+}
@@ -0,0 +1,139 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// * the collectors are registered to the default registry, which also
+// * holds the go runtime and process collectors
+var (
+	// ! ---------------------------------- http ---------------------------------- ! //
+	httprequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "The http requests by route template, method and status.",
+	}, []string{"method", "route", "status"})
+
+	httpduration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "The duration of the http requests by route template, method and status.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	// ! -------------------------------- database -------------------------------- ! //
+	dbduration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_query_duration_seconds",
+		Help:    "The duration of the gorm queries by operation and table.",
+		Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"operation", "table"})
+
+	dberrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "db_query_errors_total",
+		Help: "The failed gorm queries by operation and table, a record not found is not a failure.",
+	}, []string{"operation", "table"})
+
+	// ! ---------------------------------- queue --------------------------------- ! //
+	queuepublished = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "queue_messages_published_total",
+		Help: "The messages published by exchange and routing key.",
+	}, []string{"exchange", "routing_key"})
+
+	queuepublishfailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "queue_publish_failures_total",
+		Help: "The messages that failed to be published by exchange and routing key.",
+	}, []string{"exchange", "routing_key"})
+
+	queueconsumed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "queue_messages_consumed_total",
+		Help: "The messages consumed by queue.",
+	}, []string{"queue"})
+
+	queueconsumefailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "queue_consume_failures_total",
+		Help: "The consumed messages that failed and were dead lettered by queue.",
+	}, []string{"queue"})
+
+	// ! ---------------------------------- cron ---------------------------------- ! //
+	cronruns = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "cron_job_runs_total",
+		Help: "The cron job runs by job and status, the status is success, fail or skip.",
+	}, []string{"job", "status"})
+
+	cronduration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "cron_job_duration_seconds",
+		Help:    "The duration of the cron job runs by job.",
+		Buckets: []float64{.01, .05, .1, .5, 1, 5, 10, 30, 60, 300},
+	}, []string{"job"})
+
+	// ! --------------------------------- domain --------------------------------- ! //
+	ordertransitions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "order_status_transitions_total",
+		Help: "The order status changes by the previous and the new status, the previous is none for a new order.",
+	}, []string{"from", "to"})
+
+	donations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "donation_status_total",
+		Help: "The donations that were set to a status, by status.",
+	}, []string{"status"})
+
+	emails = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "emails_total",
+		Help: "The emails by template and result, the result is sent or failed.",
+	}, []string{"template", "result"})
+)
+
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+func ObserveHTTPRequest(method string, route string, status int, duration time.Duration) {
+	code := strconv.Itoa(status)
+
+	httprequests.WithLabelValues(method, route, code).Inc()
+	httpduration.WithLabelValues(method, route, code).Observe(duration.Seconds())
+}
+
+func ObservePublish(exchange string, rkey string, err error) {
+	if err != nil {
+		queuepublishfailures.WithLabelValues(exchange, rkey).Inc()
+		return
+	}
+
+	queuepublished.WithLabelValues(exchange, rkey).Inc()
+}
+
+// * a message is counted once when it is consumed and once more as a
+// * failure when it is dead lettered
+func ObserveConsume(queue string, err error) {
+	queueconsumed.WithLabelValues(queue).Inc()
+
+	if err != nil {
+		queueconsumefailures.WithLabelValues(queue).Inc()
+	}
+}
+
+func ObserveOrderTransition(from string, to string, count int) {
+	if from == "" {
+		from = "none"
+	}
+
+	ordertransitions.WithLabelValues(from, to).Add(float64(count))
+}
+
+func ObserveDonation(status string) {
+	donations.WithLabelValues(status).Inc()
+}
+
+func ObserveEmail(template string, err error) {
+	result := "sent"
+	if err != nil {
+		result = "failed"
+	}
+
+	emails.WithLabelValues(template, result).Inc()
+}
@@ -2,7 +2,11 @@ package servers
 
 import (
 	"context"
+	"errors"
+	"net"
 	"net/http"
+	"project-skbackend/packages/lifecycle"
+	"sync/atomic"
 	"time"
 )
 
@@ -17,6 +21,9 @@ type Server struct {
 	server          *http.Server
 	notify          chan error
 	shutdownTimeout time.Duration
+
+	serving atomic.Bool
+	failed  atomic.Bool
 }
 
 func NewServer(handler http.Handler, opts ...Option) *Server {
@@ -37,25 +44,56 @@ func NewServer(handler http.Handler, opts ...Option) *Server {
 		opt(s)
 	}
 
-	s.start()
-
 	return s
 }
 
-func (s *Server) start() {
+func (s *Server) Name() string {
+	return "http server"
+}
+
+// * the address is bound before returning, so a port in use fails the
+// * start instead of the server later
+func (s *Server) Start(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.server.Addr)
+	if err != nil {
+		return err
+	}
+
+	s.serving.Store(true)
+
 	go func() {
-		s.notify <- s.server.ListenAndServe()
+		err := s.server.Serve(listener)
+		s.serving.Store(false)
+
+		if !errors.Is(err, http.ErrServerClosed) {
+			s.failed.Store(true)
+			s.notify <- err
+		}
 		close(s.notify)
 	}()
+
+	return nil
 }
 
 func (s *Server) Notify() <-chan error {
 	return s.notify
 }
 
-func (s *Server) Shutdown() error {
-	ctx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+// * stops accepting connections and waits for the requests in flight,
+// * at most the shutdown timeout or the deadline of the ctx
+func (s *Server) Stop(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, s.shutdownTimeout)
 	defer cancel()
 
+	s.serving.Store(false)
+
 	return s.server.Shutdown(ctx)
 }
+
+func (s *Server) Health() lifecycle.Health {
+	if s.failed.Load() {
+		return lifecycle.Health{Error: "server stopped serving"}
+	}
+
+	return lifecycle.Health{Live: true, Ready: s.serving.Load()}
+}
@@ -0,0 +1,75 @@
+package tracing
+
+import (
+	"context"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type (
+	// * AMQPCarrier carries the trace context in the headers of a message
+	AMQPCarrier amqp.Table
+)
+
+var _ propagation.TextMapCarrier = AMQPCarrier{}
+
+func (c AMQPCarrier) Get(key string) string {
+	value, _ := c[key].(string)
+	return value
+}
+
+func (c AMQPCarrier) Set(key string, value string) {
+	c[key] = value
+}
+
+func (c AMQPCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// * starts the span of a publish and writes its context into the headers,
+// * the consumer continues the trace from them
+func StartPublish(ctx context.Context, exchange string, rkey string, headers amqp.Table) (context.Context, trace.Span) {
+	ctx, span := Start(ctx, "publish "+rkey,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "rabbitmq"),
+			attribute.String("messaging.operation", "publish"),
+			attribute.String("messaging.destination.name", exchange),
+			attribute.String("messaging.rabbitmq.destination.routing_key", rkey),
+		),
+	)
+
+	otel.GetTextMapPropagator().Inject(ctx, AMQPCarrier(headers))
+
+	return ctx, span
+}
+
+// * starts the span of a consume as a child of the publish of the message
+func StartConsume(d amqp.Delivery, qname string) (context.Context, trace.Span) {
+	headers := d.Headers
+	if headers == nil {
+		headers = amqp.Table{}
+	}
+
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), AMQPCarrier(headers))
+
+	return Start(ctx, "process "+qname,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "rabbitmq"),
+			attribute.String("messaging.operation", "process"),
+			attribute.String("messaging.destination.name", d.Exchange),
+			attribute.String("messaging.rabbitmq.destination.routing_key", d.RoutingKey),
+			attribute.String("messaging.source.name", qname),
+		),
+	)
+}
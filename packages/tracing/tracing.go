@@ -0,0 +1,40 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const (
+	name = "project-skbackend"
+)
+
+// * the tracer of the global provider, which is a no-op one until the
+// * tracing is configured, so the spans cost nothing in local runs
+func Tracer() trace.Tracer {
+	return otel.Tracer(name)
+}
+
+func Start(ctx context.Context, spname string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return Tracer().Start(ctx, spname, opts...)
+}
+
+// * ends the span, marking it as failed when there is an error. a record
+// * not found is an answer, not a failure
+func End(span trace.Span, err error) {
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
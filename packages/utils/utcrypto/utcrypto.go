@@ -0,0 +1,140 @@
+package utcrypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"project-skbackend/packages/consttypes"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+const (
+	// * every encrypted value is stored as enc:<key id>:<nonce + ciphertext>
+	// * so the key used to encrypt it can be found again after a rotation
+	prefix    = "enc"
+	separator = ":"
+)
+
+var (
+	mu      sync.RWMutex
+	keyring map[string]cipher.AEAD
+	current string
+
+	keyidregex = regexp.MustCompile(`^[a-zA-Z0-9]+$`)
+)
+
+// * keys are base64 encoded 32 bytes AES-256 keys mapped by their id,
+// * the current key is used for new data while the rest are only kept
+// * to decrypt data that has not been re-encrypted yet
+func Init(keys map[string]string, currentkeyid string) error {
+	aeads := make(map[string]cipher.AEAD, len(keys))
+
+	for keyid, key := range keys {
+		if !keyidregex.MatchString(keyid) {
+			return consttypes.ErrEncryptionKeyInvalid(keyid)
+		}
+
+		rawkey, err := base64.StdEncoding.DecodeString(key)
+		if err != nil || len(rawkey) != 32 {
+			return consttypes.ErrEncryptionKeyInvalid(keyid)
+		}
+
+		block, err := aes.NewCipher(rawkey)
+		if err != nil {
+			return consttypes.ErrEncryptionKeyInvalid(keyid)
+		}
+
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return consttypes.ErrEncryptionKeyInvalid(keyid)
+		}
+
+		aeads[keyid] = aead
+	}
+
+	if _, ok := aeads[currentkeyid]; !ok {
+		return consttypes.ErrEncryptionKeyNotFound(currentkeyid)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	keyring = aeads
+	current = currentkeyid
+
+	return nil
+}
+
+// * prefix of the values that are encrypted using the current key
+func CurrentPrefix() string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return prefix + separator + current + separator
+}
+
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix+separator)
+}
+
+func Encrypt(plaintext []byte) (string, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	aead, ok := keyring[current]
+	if !ok {
+		return "", consttypes.ErrEncryptionNotInitialized
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	// * the key id is used as additional data so a value
+	// * cannot be moved to another key id without failing
+	sealed := aead.Seal(nonce, nonce, plaintext, []byte(current))
+
+	return strings.Join([]string{
+		prefix,
+		current,
+		base64.StdEncoding.EncodeToString(sealed),
+	}, separator), nil
+}
+
+func Decrypt(value string) ([]byte, error) {
+	parts := strings.SplitN(value, separator, 3)
+	if len(parts) != 3 || parts[0] != prefix {
+		return nil, consttypes.ErrInvalidCiphertext
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if keyring == nil {
+		return nil, consttypes.ErrEncryptionNotInitialized
+	}
+
+	aead, ok := keyring[parts[1]]
+	if !ok {
+		return nil, consttypes.ErrEncryptionKeyNotFound(parts[1])
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return nil, consttypes.ErrInvalidCiphertext
+	}
+
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(parts[1]))
+	if err != nil {
+		return nil, consttypes.ErrInvalidCiphertext.Wrap(err)
+	}
+
+	return plaintext, nil
+}
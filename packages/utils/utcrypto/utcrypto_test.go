@@ -0,0 +1,170 @@
+package utcrypto
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"project-skbackend/packages/consttypes"
+	"strings"
+	"testing"
+)
+
+func newKey(t *testing.T) string {
+	t.Helper()
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	if err := Init(map[string]string{"k1": newKey(t)}, "k1"); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, plaintext := range map[string][]byte{
+		"nil":     nil,
+		"empty":   {},
+		"number":  []byte("19.5"),
+		"json":    []byte(`{"height":100,"weight":150}`),
+		"unicode": []byte(`"Jönathan 🍲"`),
+	} {
+		value, err := Encrypt(plaintext)
+		if err != nil {
+			t.Fatalf("%s: encrypt err = %v", name, err)
+		}
+
+		if !IsEncrypted(value) || !strings.HasPrefix(value, CurrentPrefix()) {
+			t.Errorf("%s: value %q does not start with %q", name, value, CurrentPrefix())
+		}
+
+		got, err := Decrypt(value)
+		if err != nil {
+			t.Fatalf("%s: decrypt err = %v", name, err)
+		}
+
+		if !bytes.Equal(got, plaintext) {
+			t.Errorf("%s: decrypt = %q, want %q", name, got, plaintext)
+		}
+	}
+}
+
+func TestEncryptUsesFreshNonce(t *testing.T) {
+	if err := Init(map[string]string{"k1": newKey(t)}, "k1"); err != nil {
+		t.Fatal(err)
+	}
+
+	a, _ := Encrypt([]byte("same"))
+	b, _ := Encrypt([]byte("same"))
+	if a == b {
+		t.Errorf("the same plaintext was encrypted to the same value %q", a)
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	var (
+		k1 = newKey(t)
+		k2 = newKey(t)
+	)
+
+	if err := Init(map[string]string{"k1": k1}, "k1"); err != nil {
+		t.Fatal(err)
+	}
+
+	old, err := Encrypt([]byte("old"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Init(map[string]string{"k1": k1, "k2": k2}, "k2"); err != nil {
+		t.Fatal(err)
+	}
+
+	// * the value of the old key is still read after the rotation
+	got, err := Decrypt(old)
+	if err != nil || string(got) != "old" {
+		t.Fatalf("decrypt old = %q, %v, want %q", got, err, "old")
+	}
+
+	// * while the new values are written with the new key
+	value, err := Encrypt([]byte("new"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if CurrentPrefix() != "enc:k2:" || !strings.HasPrefix(value, "enc:k2:") {
+		t.Errorf("value %q with prefix %q, want the prefix enc:k2:", value, CurrentPrefix())
+	}
+
+	// * a value of a key that was dropped can not be read anymore
+	if err := Init(map[string]string{"k2": k2}, "k2"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Decrypt(old); err == nil {
+		t.Error("decrypt of a dropped key err = nil, want an error")
+	}
+}
+
+func TestDecryptRejectsTampered(t *testing.T) {
+	var (
+		k1 = newKey(t)
+		k2 = newKey(t)
+	)
+
+	if err := Init(map[string]string{"k1": k1, "k2": k2}, "k1"); err != nil {
+		t.Fatal(err)
+	}
+
+	value, err := Encrypt([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parts := strings.SplitN(value, ":", 3)
+	sealed, _ := base64.StdEncoding.DecodeString(parts[2])
+
+	flipped := bytes.Clone(sealed)
+	flipped[len(flipped)-1] ^= 0x01
+
+	for name, tampered := range map[string]string{
+		"flipped byte": "enc:k1:" + base64.StdEncoding.EncodeToString(flipped),
+		"other key id": "enc:k2:" + parts[2],
+		"truncated":    "enc:k1:" + base64.StdEncoding.EncodeToString(sealed[:8]),
+		"not base64":   "enc:k1:%%%",
+		"no prefix":    "plain:k1:" + parts[2],
+		"missing part": "enc:k1",
+		"empty":        "",
+	} {
+		if _, err := Decrypt(tampered); err == nil {
+			t.Errorf("%s: err = nil, want the value rejected", name)
+		}
+	}
+
+	if _, err := Decrypt("enc:k3:" + parts[2]); err == nil {
+		t.Error("unknown key id: err = nil, want the value rejected")
+	}
+
+	if _, err := Decrypt("enc:k1:" + base64.StdEncoding.EncodeToString(flipped)); !errors.Is(err, consttypes.ErrInvalidCiphertext) {
+		t.Errorf("flipped byte: err = %v, want %v", err, consttypes.ErrInvalidCiphertext)
+	}
+}
+
+func TestInitRejectsInvalidKeys(t *testing.T) {
+	for name, keys := range map[string]map[string]string{
+		"not base64":  {"k1": "%%%"},
+		"short key":   {"k1": base64.StdEncoding.EncodeToString([]byte("short"))},
+		"bad key id":  {"k:1": newKey(t)},
+		"no current":  {"k2": newKey(t)},
+		"no keys":     {},
+		"empty keyid": {"": newKey(t)},
+	} {
+		if err := Init(keys, "k1"); err == nil {
+			t.Errorf("%s: err = nil, want the keys rejected", name)
+		}
+	}
+}
@@ -218,12 +218,12 @@ func ValidateExtension(ext string, allowedExtensions []string) error {
 func ReadRequestFile(file *multipart.FileHeader) (*bytes.Reader, error) {
 	ogFile, err := file.Open()
 	if err != nil {
-		return nil, consttypes.ErrFailedToOpenFile
+		return nil, consttypes.ErrFailedToOpenFile.Wrap(err)
 	}
 
 	fileBytes, err := io.ReadAll(ogFile)
 	if err != nil {
-		return nil, consttypes.ErrFailedToReadFile
+		return nil, consttypes.ErrFailedToReadFile.Wrap(err)
 	}
 
 	fileReader := bytes.NewReader(fileBytes)
@@ -1,6 +1,7 @@
 package utgeolocation
 
 import (
+	"context"
 	"project-skbackend/configs"
 	"project-skbackend/external/controllers/exrequests"
 	"project-skbackend/external/controllers/exresponses"
@@ -15,7 +16,7 @@ var (
 )
 
 func GetGeolocation(geoloc exrequests.Geolocation) (*exresponses.AddressDetail, error) {
-	geocode, err := sdsmt.GetGeocoding(geoloc)
+	geocode, err := sdsmt.GetGeocoding(context.Background(), geoloc)
 	if err != nil {
 		return nil, err
 	}
@@ -30,7 +31,7 @@ func GetGeolocation(geoloc exrequests.Geolocation) (*exresponses.AddressDetail,
 }
 
 func GetLocationDistance(dismat exrequests.DistanceMatrix) (*exresponses.RouteDetails, error) {
-	distancematrix, err := sdsmt.GetDistanceMatrix(dismat)
+	distancematrix, err := sdsmt.GetDistanceMatrix(context.Background(), dismat)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,25 @@
+package utlogger
+
+import (
+	"context"
+)
+
+const (
+	// * the header a request id is taken from and answered with
+	HeaderRequestID = "X-Request-ID"
+)
+
+type (
+	requestidkey struct{}
+)
+
+// * keeps the id of the request in the context, every record logged with
+// * the context has it
+func WithRequestID(ctx context.Context, rid string) context.Context {
+	return context.WithValue(ctx, requestidkey{}, rid)
+}
+
+func RequestID(ctx context.Context) string {
+	rid, _ := ctx.Value(requestidkey{}).(string)
+	return rid
+}
@@ -0,0 +1,120 @@
+package utlogger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	glogger "gorm.io/gorm/logger"
+)
+
+const (
+	pkgname = "project-skbackend/packages/utils/utlogger."
+)
+
+type (
+	// * GormLogger writes the queries of gorm as records of the logger, a
+	// * query at debug, a slow one as a warning and a failed one as an error
+	GormLogger struct {
+		slow time.Duration
+	}
+)
+
+var (
+	_ glogger.Interface = GormLogger{}
+	_ gorm.ParamsFilter = GormLogger{}
+)
+
+func NewGormLogger(slow time.Duration) GormLogger {
+	return GormLogger{
+		slow: slow,
+	}
+}
+
+// * the level is the one of the logger, not the one gorm asks for
+func (l GormLogger) LogMode(glogger.LogLevel) glogger.Interface {
+	return l
+}
+
+func (l GormLogger) Info(ctx context.Context, msg string, data ...any) {
+	l.log(ctx, slog.LevelInfo, fmt.Sprintf(msg, data...))
+}
+
+func (l GormLogger) Warn(ctx context.Context, msg string, data ...any) {
+	l.log(ctx, slog.LevelWarn, fmt.Sprintf(msg, data...))
+}
+
+func (l GormLogger) Error(ctx context.Context, msg string, data ...any) {
+	l.log(ctx, slog.LevelError, fmt.Sprintf(msg, data...))
+}
+
+func (l GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	elapsed := time.Since(begin)
+
+	var (
+		lvl = slog.LevelDebug
+		msg = "query"
+	)
+
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
+		lvl, msg = slog.LevelError, "query failed"
+	case l.slow > 0 && elapsed > l.slow:
+		lvl, msg = slog.LevelWarn, "slow query"
+	}
+
+	if !logger.Enabled(ctx, lvl) {
+		return
+	}
+
+	sql, rows := fc()
+	attrs := []any{
+		slog.String("sql", sql),
+		slog.Int64("rows", rows),
+		slog.Float64("duration_ms", float64(elapsed.Microseconds())/1000),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+
+	l.log(ctx, lvl, msg, attrs...)
+}
+
+// * the values of a query are left out, only the placeholders are logged
+func (l GormLogger) ParamsFilter(ctx context.Context, sql string, params ...any) (string, []any) {
+	return sql, nil
+}
+
+// * the source is the line of the repository that ran the query
+func (l GormLogger) log(ctx context.Context, lvl slog.Level, msg string, attrs ...any) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	record := slog.NewRecord(time.Now(), lvl, msg, querycaller())
+	record.Add(attrs...)
+
+	_ = logger.Handler().Handle(ctx, record)
+}
+
+func querycaller() uintptr {
+	var pcs [32]uintptr
+	n := runtime.Callers(3, pcs[:])
+
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, "gorm.io/") && !strings.HasPrefix(frame.Function, pkgname) {
+			return frame.PC
+		}
+
+		if !more {
+			return 0
+		}
+	}
+}
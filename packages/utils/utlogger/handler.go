@@ -0,0 +1,159 @@
+package utlogger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	redacted = "[REDACTED]"
+)
+
+var (
+	// * a key that has one of these in it is a credential
+	secretkeys = []string{
+		"password", "passwd", "secret", "token", "authorization", "cookie",
+		"apikey", "privatekey", "signature", "otp", "recoverycode", "linkurl",
+	}
+
+	// * the health data of a member, matched by the whole key
+	healthkeys = map[string]bool{
+		"height":      true,
+		"weight":      true,
+		"bmi":         true,
+		"illnesses":   true,
+		"allergies":   true,
+		"dateofbirth": true,
+	}
+)
+
+type (
+	// * Handler writes the records as json with the request id and the
+	// * trace of the context, and redacts the credentials and the health
+	// * data wherever they are in a record
+	Handler struct {
+		slog.Handler
+	}
+)
+
+var _ slog.Handler = Handler{}
+
+func NewHandler(w io.Writer, lvl slog.Leveler) Handler {
+	return Handler{
+		Handler: slog.NewJSONHandler(w, &slog.HandlerOptions{
+			AddSource:   true,
+			Level:       lvl,
+			ReplaceAttr: replaceAttr,
+		}),
+	}
+}
+
+func (h Handler) Handle(ctx context.Context, record slog.Record) error {
+	if ctx != nil {
+		if rid := RequestID(ctx); rid != "" {
+			record.AddAttrs(slog.String("request_id", rid))
+		}
+
+		if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+			record.AddAttrs(
+				slog.String("trace_id", sc.TraceID().String()),
+				slog.String("span_id", sc.SpanID().String()),
+			)
+		}
+	}
+
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return Handler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h Handler) WithGroup(name string) slog.Handler {
+	return Handler{Handler: h.Handler.WithGroup(name)}
+}
+
+func replaceAttr(groups []string, attr slog.Attr) slog.Attr {
+	if sensitive(attr.Key) {
+		return slog.String(attr.Key, redacted)
+	}
+
+	if attr.Value.Kind() == slog.KindAny {
+		attr.Value = slog.AnyValue(redact(attr.Value.Any()))
+	}
+
+	return attr
+}
+
+func sensitive(key string) bool {
+	key = strings.NewReplacer("_", "", "-", "", " ", "").Replace(strings.ToLower(key))
+
+	if healthkeys[key] {
+		return true
+	}
+
+	for _, skey := range secretkeys {
+		if strings.Contains(key, skey) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// * the value is walked through its json form, so the fields of a struct
+// * are redacted by the name they are sent with
+func redact(value any) any {
+	switch v := value.(type) {
+	case nil, string, bool, int, int32, int64, uint, uint32, uint64, float32, float64, time.Time, time.Duration:
+		return v
+	case error:
+		return v.Error()
+	case json.RawMessage:
+		var data any
+		if err := json.Unmarshal(v, &data); err != nil {
+			return string(v)
+		}
+
+		return redactJSON(data)
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%+v", value)
+	}
+
+	var data any
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return string(raw)
+	}
+
+	return redactJSON(data)
+}
+
+func redactJSON(data any) any {
+	switch v := data.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if sensitive(key) {
+				v[key] = redacted
+				continue
+			}
+
+			v[key] = redactJSON(value)
+		}
+	case []any:
+		for i, value := range v {
+			v[i] = redactJSON(value)
+		}
+	}
+
+	return data
+}
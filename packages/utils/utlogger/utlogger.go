@@ -1,24 +1,100 @@
 package utlogger
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
+	"os"
 	"project-skbackend/packages/utils/uttelegram"
 	"runtime"
+	"time"
 )
 
+var (
+	// * the level is shared, so it can be set once the config is read
+	level  = new(slog.LevelVar)
+	logger = slog.New(NewHandler(os.Stdout, level))
+)
+
+// * the records of slog and of the log package are written the same way
+func init() {
+	slog.SetDefault(logger)
+}
+
+// * sets the lowest level that is written
+func SetLevel(lvl slog.Level) {
+	level.Set(lvl)
+}
+
+// * the logger of the package, for the code that logs with slog itself
+func Logger() *slog.Logger {
+	return logger
+}
+
 func Error(errs ...error) {
+	logErrors(context.Background(), caller(), errs...)
+}
+
+func ErrorContext(ctx context.Context, errs ...error) {
+	logErrors(ctx, caller(), errs...)
+}
+
+func Warn(data ...any) {
+	write(context.Background(), slog.LevelWarn, caller(), data...)
+}
+
+func WarnContext(ctx context.Context, data ...any) {
+	write(ctx, slog.LevelWarn, caller(), data...)
+}
+
+func Info(data ...any) {
+	write(context.Background(), slog.LevelInfo, caller(), data...)
+}
+
+func InfoContext(ctx context.Context, data ...any) {
+	write(ctx, slog.LevelInfo, caller(), data...)
+}
+
+func Debug(data ...any) {
+	write(context.Background(), slog.LevelDebug, caller(), data...)
+}
+
+func DebugContext(ctx context.Context, data ...any) {
+	write(ctx, slog.LevelDebug, caller(), data...)
+}
+
+func Fatal(err error) {
+	if err != nil {
+		record := slog.NewRecord(time.Now(), slog.LevelError, err.Error(), caller())
+		_ = logger.Handler().Handle(context.Background(), record)
+
+		os.Exit(1)
+	}
+}
+
+// * the pc of the caller of the exported func, so the source of a record
+// * is the line that logged it and not this package
+func caller() uintptr {
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:])
+
+	return pcs[0]
+}
+
+func logErrors(ctx context.Context, pc uintptr, errs ...error) {
 	for _, err := range errs {
 		if err != nil {
-			pc := make([]uintptr, 15)
-			n := runtime.Callers(2, pc)
-			frames := runtime.CallersFrames(pc[:n])
-			frame, _ := frames.Next()
+			record := slog.NewRecord(time.Now(), slog.LevelError, err.Error(), pc)
+			_ = logger.Handler().Handle(ctx, record)
+
+			frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
 
 			// * wrap the error in a custom error
 			err = fmt.Errorf("Error occurred at: %s:%d, Error: %s", frame.File, frame.Line, err.Error())
-			fmt.Println(err)
+			if rid := RequestID(ctx); rid != "" {
+				err = fmt.Errorf("%w, Request: %s", err, rid)
+			}
 
 			// * wrap the message in the json format to be sent to telegram
 			msg, _ := json.MarshalIndent(err.Error(), "", "\t")
@@ -27,24 +103,28 @@ func Error(errs ...error) {
 	}
 }
 
-func Info(data ...any) {
-	pc := make([]uintptr, 15)
-	n := runtime.Callers(2, pc)
-	frames := runtime.CallersFrames(pc[:n])
-	frame, _ := frames.Next()
-
-	jsondata, _ := json.MarshalIndent(data, "", "\t")
-
-	fmt.Printf("\nInfo occurred at: %s:%d\nInfo: %s\n\n", frame.File, frame.Line, jsondata)
-}
+// * the first string is the message, the rest is kept as the data of the
+// * record, which is redacted by the handler
+func write(ctx context.Context, lvl slog.Level, pc uintptr, data ...any) {
+	if !logger.Enabled(ctx, lvl) {
+		return
+	}
 
-func Fatal(err error) {
-	if err != nil {
-		pc := make([]uintptr, 15)
-		n := runtime.Callers(2, pc)
-		frames := runtime.CallersFrames(pc[:n])
-		frame, _ := frames.Next()
+	msg := ""
+	if len(data) > 0 {
+		if text, ok := data[0].(string); ok {
+			msg, data = text, data[1:]
+		}
+	}
 
-		log.Fatalf("\nFatal error occurred at: %s:%d\nError: %s\n\n", frame.File, frame.Line, err.Error())
+	record := slog.NewRecord(time.Now(), lvl, msg, pc)
+	switch len(data) {
+	case 0:
+	case 1:
+		record.AddAttrs(slog.Any("data", data[0]))
+	default:
+		record.AddAttrs(slog.Any("data", data))
 	}
+
+	_ = logger.Handler().Handle(ctx, record)
 }
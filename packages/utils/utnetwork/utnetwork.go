@@ -0,0 +1,100 @@
+package utnetwork
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"project-skbackend/packages/consttypes"
+	"syscall"
+	"time"
+)
+
+var (
+	// * the ranges that are not covered by the methods of netip.Addr
+	reserved = []netip.Prefix{
+		netip.MustParsePrefix("0.0.0.0/8"),
+		netip.MustParsePrefix("100.64.0.0/10"),
+		netip.MustParsePrefix("192.0.0.0/24"),
+		netip.MustParsePrefix("192.0.2.0/24"),
+		netip.MustParsePrefix("198.18.0.0/15"),
+		netip.MustParsePrefix("198.51.100.0/24"),
+		netip.MustParsePrefix("203.0.113.0/24"),
+		netip.MustParsePrefix("240.0.0.0/4"),
+		netip.MustParsePrefix("64:ff9b::/96"),
+		netip.MustParsePrefix("64:ff9b:1::/48"),
+		netip.MustParsePrefix("100::/64"),
+		netip.MustParsePrefix("2001::/23"),
+		netip.MustParsePrefix("2001:db8::/32"),
+		netip.MustParsePrefix("2002::/16"),
+	}
+)
+
+// * an address on the internet, not one of the loopback, private,
+// * link local (which has the metadata of the clouds) or reserved ranges
+func IsPublicIP(ip netip.Addr) bool {
+	ip = ip.Unmap()
+
+	if !ip.IsValid() ||
+		ip.IsUnspecified() ||
+		ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() {
+		return false
+	}
+
+	for _, prefix := range reserved {
+		if prefix.Contains(ip) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// * every address the host resolves to has to be public
+func IsPublicHost(ctx context.Context, host string) error {
+	if ip, err := netip.ParseAddr(host); err == nil {
+		if !IsPublicIP(ip) {
+			return consttypes.ErrAddressNotAllowed
+		}
+
+		return nil
+	}
+
+	ips, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return consttypes.ErrHostNotResolved.Wrap(err)
+	}
+
+	for _, ip := range ips {
+		if !IsPublicIP(ip) {
+			return consttypes.ErrAddressNotAllowed
+		}
+	}
+
+	return nil
+}
+
+// * PublicDialer only connects to public addresses. the address is checked
+// * once it is resolved and right before the connection, so a host can not
+// * answer a public address to the check and a private one to the dial
+func PublicDialer(timeout time.Duration) *net.Dialer {
+	return &net.Dialer{
+		Timeout: timeout,
+		Control: func(network string, address string, c syscall.RawConn) error {
+			ap, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return consttypes.ErrAddressNotAllowed.Wrap(err)
+			}
+
+			if !IsPublicIP(ap.Addr()) {
+				return consttypes.ErrAddressNotAllowed
+			}
+
+			return nil
+		},
+	}
+}
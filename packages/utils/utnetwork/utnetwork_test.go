@@ -0,0 +1,55 @@
+package utnetwork
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"project-skbackend/packages/consttypes"
+	"testing"
+	"time"
+)
+
+func TestIsPublicIP(t *testing.T) {
+	cases := map[string]bool{
+		"8.8.8.8":            true,
+		"2606:4700::1111":    true,
+		"127.0.0.1":          false,
+		"10.1.2.3":           false,
+		"172.16.0.1":         false,
+		"192.168.1.1":        false,
+		"169.254.169.254":    false,
+		"100.64.0.1":         false,
+		"0.0.0.0":            false,
+		"::1":                false,
+		"fd00::1":            false,
+		"fe80::1":            false,
+		"::ffff:127.0.0.1":   false,
+		"::ffff:10.0.0.1":    false,
+		"64:ff9b::a9fe:a9fe": false,
+	}
+
+	for addr, want := range cases {
+		if got := IsPublicIP(netip.MustParseAddr(addr)); got != want {
+			t.Errorf("IsPublicIP(%s) = %v, want %v", addr, got, want)
+		}
+	}
+}
+
+func TestPublicDialerRefusesLoopback(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request reached the loopback server")
+	}))
+	defer srv.Close()
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: PublicDialer(time.Second).DialContext,
+		},
+	}
+
+	_, err := client.Get(srv.URL)
+	if !errors.Is(err, consttypes.ErrAddressNotAllowed) {
+		t.Fatalf("err = %v, want %v", err, consttypes.ErrAddressNotAllowed)
+	}
+}
@@ -1,6 +1,7 @@
 package utpagination
 
 import (
+	"project-skbackend/packages/consttypes"
 	"time"
 
 	"github.com/google/uuid"
@@ -17,6 +18,29 @@ type (
 		TotalDatas int64  `json:"total_datas"`
 		TotalPages int    `json:"total_pages"`
 		Data       any    `json:"datas"`
+
+		// * the filter and sort expressions of the query string, the
+		// * fields are checked against the whitelist of the repository
+		Conditions []Condition `json:"-"`
+		Sorts      []Sort      `json:"-"`
+
+		// * keyset pagination, requested by giving a cursor even an empty
+		// * one for the first page. the next cursor is empty on the last page
+		Keyset     bool   `json:"-"`
+		Cursor     string `json:"-"`
+		NextCursor string `json:"next_cursor,omitempty"`
+	}
+
+	// * field:operator:values, the values of in and nin are separated by commas
+	Condition struct {
+		Field    string
+		Operator consttypes.FilterOperator
+		Values   []string
+	}
+
+	Sort struct {
+		Field     string
+		Direction consttypes.SortDirection
 	}
 
 	Filter struct {
@@ -25,6 +49,7 @@ type (
 		Meal        Meal
 		Partner     Partner
 		Patron      Patron
+		Audit       Audit
 	}
 
 	Patron struct {
@@ -32,10 +57,18 @@ type (
 	}
 
 	Meal struct {
-		ID *uuid.UUID `json:"meal_id"`
+		ID          *uuid.UUID  `json:"meal_id"`
+		CategoryIDs []uuid.UUID `json:"meal_category_ids"`
 	}
 
 	Partner struct {
 		ID *uuid.UUID `json:"partner_id"`
 	}
+
+	Audit struct {
+		ActorID  *uuid.UUID `json:"actor_id"`
+		Action   string     `json:"action"`
+		Entity   string     `json:"entity"`
+		EntityID string     `json:"entity_id"`
+	}
 )
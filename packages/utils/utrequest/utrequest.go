@@ -31,6 +31,12 @@ func GeneratePaginationFromRequest(ctx *gin.Context) utpagination.Pagination {
 		createdFrom time.Time
 		createdTo   time.Time
 		partnerID   = uuid.UUID{}
+		meal        = utpagination.Meal{}
+		audit       = utpagination.Audit{}
+		conditions  []utpagination.Condition
+		sorts       []utpagination.Sort
+		keyset      bool
+		cursor      string
 	)
 
 	query := ctx.Request.URL.Query()
@@ -44,10 +50,32 @@ func GeneratePaginationFromRequest(ctx *gin.Context) utpagination.Pagination {
 		case "search":
 			search = queryValue
 		case "sort":
+			// * several fields are given separated by commas, each
+			// * with an optional direction like created_at:desc
 			if queryValue != "" {
-				str := stringy.New(queryValue)
-				snakeStr := str.SnakeCase("?", "")
-				sort = snakeStr.ToLower()
+				var (
+					fields []string
+				)
+
+				for _, item := range strings.Split(queryValue, ",") {
+					field, dir, _ := strings.Cut(strings.TrimSpace(item), ":")
+					if field == "" {
+						continue
+					}
+
+					str := stringy.New(field)
+					snakeStr := str.SnakeCase("?", "").ToLower()
+
+					fields = append(fields, snakeStr)
+					sorts = append(sorts, utpagination.Sort{
+						Field:     snakeStr,
+						Direction: consttypes.SortDirection(strings.ToLower(dir)),
+					})
+				}
+
+				if len(fields) > 0 {
+					sort = strings.Join(fields, ",")
+				}
 			}
 		case "direction":
 			queryValue = strings.ToLower(queryValue)
@@ -78,22 +106,93 @@ func GeneratePaginationFromRequest(ctx *gin.Context) utpagination.Pagination {
 					partnerID = uuid
 				}
 			}
+		case "meal-category-id":
+			// * several categories are given separated by commas
+			for _, id := range strings.Split(queryValue, ",") {
+				uuid, err := uuid.Parse(strings.TrimSpace(id))
+				if err == nil {
+					meal.CategoryIDs = append(meal.CategoryIDs, uuid)
+				}
+			}
+		case "filter":
+			// * every filter is given as its own filter parameter
+			for _, expr := range value {
+				if condition, ok := parseCondition(expr); ok {
+					conditions = append(conditions, condition)
+				}
+			}
+		case "cursor":
+			keyset = true
+			cursor = queryValue
+		case "actor-id":
+			if queryValue != "" {
+				uuid, err := uuid.Parse(queryValue)
+				if err == nil {
+					audit.ActorID = &uuid
+				}
+			}
+		case "action":
+			audit.Action = queryValue
+		case "entity":
+			audit.Entity = queryValue
+		case "entity-id":
+			audit.EntityID = queryValue
+		}
+	}
+
+	// * the sorts without a direction take the direction parameter
+	for i := range sorts {
+		if !sorts[i].Direction.IsValid() {
+			sorts[i].Direction = consttypes.SortDirection(direction)
 		}
 	}
 
 	return utpagination.Pagination{
-		Limit:     limit,
-		Page:      page,
-		Sort:      sort,
-		Direction: direction,
-		Search:    search,
+		Limit:      limit,
+		Page:       page,
+		Sort:       sort,
+		Direction:  direction,
+		Search:     search,
+		Conditions: conditions,
+		Sorts:      sorts,
+		Keyset:     keyset,
+		Cursor:     cursor,
 		Filter: utpagination.Filter{
 			CreatedFrom: createdFrom,
 			CreatedTo:   createdTo,
 
+			Meal: meal,
+
 			Partner: utpagination.Partner{
 				ID: &partnerID,
 			},
+
+			Audit: audit,
 		},
 	}
 }
+
+// * parses field:operator:values, the expressions with an
+// * unknown operator or without a value are left out
+func parseCondition(expr string) (utpagination.Condition, bool) {
+	parts := strings.SplitN(expr, ":", 3)
+	if len(parts) != 3 || parts[0] == "" || parts[2] == "" {
+		return utpagination.Condition{}, false
+	}
+
+	operator := consttypes.FilterOperator(strings.ToLower(parts[1]))
+	if !operator.IsValid() {
+		return utpagination.Condition{}, false
+	}
+
+	values := []string{parts[2]}
+	if operator == consttypes.FO_IN || operator == consttypes.FO_NIN {
+		values = strings.Split(parts[2], ",")
+	}
+
+	return utpagination.Condition{
+		Field:    stringy.New(parts[0]).SnakeCase("?", "").ToLower(),
+		Operator: operator,
+		Values:   values,
+	}, true
+}
@@ -1,12 +1,14 @@
 package utresponse
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"project-skbackend/packages/consttypes"
 	"project-skbackend/packages/utils/utlogger"
 	"project-skbackend/packages/utils/uttoken"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -120,20 +122,80 @@ type (
 		Message   string `json:"message"`
 	}
 
-	ErrorRes struct {
-		Status  consttypes.ResponseStatusType `json:"status"`
-		Message string                        `json:"message"`
-		Data    *ErrorData                    `json:"data,omitempty"`
+	// * Problem is the rfc 7807 body of every error response, the code and
+	// * the request id are members of the app
+	Problem struct {
+		Type      string                   `json:"type"`
+		Title     string                   `json:"title"`
+		Status    int                      `json:"status"`
+		Detail    string                   `json:"detail,omitempty"`
+		Instance  string                   `json:"instance,omitempty"`
+		Code      consttypes.ErrorCode     `json:"code"`
+		RequestID string                   `json:"request_id,omitempty"`
+		Errors    []ValidationErrorMessage `json:"errors,omitempty"`
 	}
+)
 
-	ErrorData struct {
-		Debug  *error `json:"debug,omitempty"`
-		Errors any    `json:"errors"`
-	}
+const (
+	ProblemContentType = "application/problem+json"
 )
 
-func ErrorResponse(ctx *gin.Context, code int, res ErrorRes) {
-	ctx.JSON(code, res)
+// * writes the problem of err, the meta of the error can carry the
+// * validation errors of the request
+func ProblemResponse(ctx *gin.Context, err error, meta any) {
+	aerr := consttypes.AsAppError(err)
+
+	problem := Problem{
+		Type:      "about:blank",
+		Title:     http.StatusText(aerr.Status),
+		Status:    aerr.Status,
+		Detail:    aerr.Message,
+		Instance:  ctx.Request.URL.Path,
+		Code:      aerr.Code,
+		RequestID: utlogger.RequestID(ctx),
+	}
+
+	if ve, ok := meta.([]ValidationErrorMessage); ok && len(ve) > 0 {
+		problem.Errors = ve
+	}
+
+	setRetryAfter(ctx, err)
+
+	body, jerr := json.Marshal(problem)
+	if jerr != nil {
+		utlogger.ErrorContext(ctx, jerr)
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	ctx.Data(aerr.Status, ProblemContentType, body)
+}
+
+// * hands the error to the error middleware. the app error the service
+// * returned wins over the fallback of the handler, a plain error is
+// * wrapped in the fallback
+func abortWithError(ctx *gin.Context, fallback *consttypes.AppError, err error, meta any) {
+	var (
+		aerr *consttypes.AppError
+	)
+
+	if !errors.As(err, &aerr) {
+		err = fallback.Wrap(err)
+	}
+
+	ctx.Abort()
+	_ = ctx.Error(err).SetMeta(meta)
+}
+
+// * the status the request is answered with. an error left for the error
+// * middleware is only written once the middlewares inside it returned,
+// * so until then its status is the one of the error
+func Status(ctx *gin.Context) int {
+	if len(ctx.Errors) > 0 && !ctx.Writer.Written() {
+		return consttypes.AsAppError(ctx.Errors.Last().Err).Status
+	}
+
+	return ctx.Writer.Status()
 }
 
 func getErrorMsg(fe validator.FieldError) string {
@@ -180,14 +242,11 @@ func GeneralInputRequiredError(
 	ctx *gin.Context,
 	err error,
 ) {
-	ErrorResponse(ctx, http.StatusBadRequest, ErrorRes{
-		Status:  consttypes.RST_ERROR,
-		Message: fmt.Sprintf("Input required on %s", function),
-		Data: &ErrorData{
-			Debug:  &err,
-			Errors: err.Error(),
-		},
-	})
+	abortWithError(ctx, consttypes.NewAppError(
+		consttypes.EC_INPUT_REQUIRED,
+		http.StatusBadRequest,
+		fmt.Sprintf("Input required on %s", function),
+	), err, nil)
 }
 
 func GeneralInternalServerError(
@@ -195,14 +254,11 @@ func GeneralInternalServerError(
 	ctx *gin.Context,
 	err error,
 ) {
-	ErrorResponse(ctx, http.StatusInternalServerError, ErrorRes{
-		Status:  consttypes.RST_ERROR,
-		Message: fmt.Sprintf("Something went wrong on %s", function),
-		Data: &ErrorData{
-			Debug:  &err,
-			Errors: err.Error(),
-		},
-	})
+	abortWithError(ctx, consttypes.NewAppError(
+		consttypes.EC_INTERNAL,
+		http.StatusInternalServerError,
+		fmt.Sprintf("Something went wrong on %s", function),
+	), err, nil)
 }
 
 func GeneralInvalidRequest(
@@ -211,25 +267,18 @@ func GeneralInvalidRequest(
 	ve []ValidationErrorMessage,
 	err error,
 ) {
-	// * create new default error data
-	var (
-		errdata = &ErrorData{
-			Debug:  &err,
-			Errors: ve,
-		}
-	)
-
-	// * if ve is empty, then change the
-	// * error to the original error
-	if len(ve) == 0 {
-		errdata.Errors = err.Error()
+	// * without validation errors the error of the binding is the detail,
+	// * it only tells what is wrong with the input
+	message := fmt.Sprintf("Invalid request on %s", function)
+	if len(ve) == 0 && err != nil {
+		message = fmt.Sprintf("%s: %s", message, err.Error())
 	}
 
-	ErrorResponse(ctx, http.StatusBadRequest, ErrorRes{
-		Status:  consttypes.RST_FAIL,
-		Message: fmt.Sprintf("Invalid request on %s", function),
-		Data:    errdata,
-	})
+	abortWithError(ctx, consttypes.NewAppError(
+		consttypes.EC_INVALID_INPUT,
+		http.StatusBadRequest,
+		message,
+	), err, ve)
 }
 
 func GeneralNotFound(
@@ -237,28 +286,22 @@ func GeneralNotFound(
 	ctx *gin.Context,
 	err error,
 ) {
-	ErrorResponse(ctx, http.StatusNotFound, ErrorRes{
-		Status:  consttypes.RST_FAIL,
-		Message: fmt.Sprintf("Entity %s is not found", entity),
-		Data: &ErrorData{
-			Debug:  &err,
-			Errors: err.Error(),
-		},
-	})
+	abortWithError(ctx, consttypes.NewAppError(
+		consttypes.EC_NOT_FOUND,
+		http.StatusNotFound,
+		fmt.Sprintf("Entity %s is not found", entity),
+	), err, nil)
 }
 
 func GeneralUnauthorized(
 	ctx *gin.Context,
 	err error,
 ) {
-	ErrorResponse(ctx, http.StatusUnauthorized, ErrorRes{
-		Status:  consttypes.RST_FAIL,
-		Message: "You are unauthorized to perform this action",
-		Data: &ErrorData{
-			Debug:  &err,
-			Errors: err.Error(),
-		},
-	})
+	abortWithError(ctx, consttypes.NewAppError(
+		consttypes.EC_UNAUTHORIZED,
+		http.StatusUnauthorized,
+		"You are unauthorized to perform this action",
+	), err, nil)
 }
 
 func GeneralFailedCreate(
@@ -266,14 +309,11 @@ func GeneralFailedCreate(
 	ctx *gin.Context,
 	err error,
 ) {
-	ErrorResponse(ctx, http.StatusUnprocessableEntity, ErrorRes{
-		Status:  consttypes.RST_FAIL,
-		Message: fmt.Sprintf("Failed to create %s", entity),
-		Data: &ErrorData{
-			Debug:  &err,
-			Errors: err.Error(),
-		},
-	})
+	abortWithError(ctx, consttypes.NewAppError(
+		consttypes.EC_UNPROCESSABLE,
+		http.StatusUnprocessableEntity,
+		fmt.Sprintf("Failed to create %s", entity),
+	), err, nil)
 }
 
 func GeneralFailedUpdate(
@@ -281,14 +321,11 @@ func GeneralFailedUpdate(
 	ctx *gin.Context,
 	err error,
 ) {
-	ErrorResponse(ctx, http.StatusUnprocessableEntity, ErrorRes{
-		Status:  consttypes.RST_FAIL,
-		Message: fmt.Sprintf("Failed to update %s", entity),
-		Data: &ErrorData{
-			Debug:  &err,
-			Errors: err.Error(),
-		},
-	})
+	abortWithError(ctx, consttypes.NewAppError(
+		consttypes.EC_UNPROCESSABLE,
+		http.StatusUnprocessableEntity,
+		fmt.Sprintf("Failed to update %s", entity),
+	), err, nil)
 }
 
 func GeneralDuplicate(
@@ -296,26 +333,59 @@ func GeneralDuplicate(
 	ctx *gin.Context,
 	err error,
 ) {
-	ErrorResponse(ctx, http.StatusConflict, ErrorRes{
-		Status:  consttypes.RST_FAIL,
-		Message: fmt.Sprintf("Failed to process, there is a duplicate %s", field),
-		Data: &ErrorData{
-			Debug:  &err,
-			Errors: err.Error(),
-		},
-	})
+	abortWithError(ctx, consttypes.NewAppError(
+		consttypes.EC_CONFLICT,
+		http.StatusConflict,
+		fmt.Sprintf("Failed to process, there is a duplicate %s", field),
+	), err, nil)
 }
 
 func GeneralForbidden(
 	ctx *gin.Context,
 	err error,
 ) {
-	ErrorResponse(ctx, http.StatusForbidden, ErrorRes{
-		Status:  consttypes.RST_FAIL,
-		Message: fmt.Sprintf("You are not allowed to do this request"),
-		Data: &ErrorData{
-			Debug:  &err,
-			Errors: err.Error(),
-		},
-	})
+	abortWithError(ctx, consttypes.NewAppError(
+		consttypes.EC_FORBIDDEN,
+		http.StatusForbidden,
+		"You are not allowed to do this request",
+	), err, nil)
+}
+
+// * sets the retry after header in seconds when the error carries
+// * the time the request can be retried
+func setRetryAfter(ctx *gin.Context, err error) {
+	var (
+		raerr *consttypes.RetryAfterError
+	)
+
+	if errors.As(err, &raerr) {
+		seconds := int(raerr.RetryAfter.Seconds())
+		if raerr.RetryAfter > 0 && seconds == 0 {
+			seconds = 1
+		}
+
+		ctx.Header("Retry-After", strconv.Itoa(seconds))
+	}
+}
+
+func GeneralTooManyRequests(
+	ctx *gin.Context,
+	err error,
+) {
+	abortWithError(ctx, consttypes.NewAppError(
+		consttypes.EC_TOO_MANY,
+		http.StatusTooManyRequests,
+		"Too many requests, please try again later",
+	), err, nil)
+}
+
+func GeneralLocked(
+	ctx *gin.Context,
+	err error,
+) {
+	abortWithError(ctx, consttypes.NewAppError(
+		consttypes.EC_LOCKED,
+		http.StatusLocked,
+		"This account is temporarily locked",
+	), err, nil)
 }
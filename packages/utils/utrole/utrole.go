@@ -28,6 +28,12 @@ func RoleTranslate(role responses.BaseRole) (uuid.UUID, consttypes.UserRole, boo
 			return uuid.UUID{}, consttypes.UserRole(0), false
 		}
 		return res.ID, res.User.Role, true
+	case consttypes.UR_ORGANIZATION:
+		res, ok := role.Data.(*models.Organization)
+		if !ok {
+			return uuid.UUID{}, consttypes.UserRole(0), false
+		}
+		return res.ID, res.User.Role, true
 	default:
 		return uuid.UUID{}, consttypes.UserRole(0), false
 	}
@@ -1,7 +1,7 @@
 package uttelegram
 
 import (
-	"fmt"
+	"log/slog"
 	"project-skbackend/external/services/telegram"
 	"sync"
 )
@@ -23,7 +23,7 @@ func SendMessage(msg string) error {
 	// Handle errors
 	for err := range errChan {
 		if err != nil {
-			fmt.Println("Error: ", err)
+			slog.Error("Unable to send the telegram message", "error", err)
 		}
 	}
 
@@ -1,7 +1,11 @@
 package uttoken
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
 	"encoding/base64"
+	"encoding/pem"
 	"fmt"
 	"project-skbackend/internal/controllers/responses"
 	"project-skbackend/packages/consttypes"
@@ -27,11 +31,13 @@ type (
 		User       *responses.User `json:"user"`
 		Expire     int64           `json:"expire"`
 		TokenUUID  uuid.UUID       `json:"token_uuid"`
+		SessionID  uuid.UUID       `json:"session_id"`
 	}
 
 	Token struct {
 		Token     *string         `json:"token"`
 		TokenUUID uuid.UUID       `json:"token_uuid"`
+		SessionID uuid.UUID       `json:"session_id"`
 		Expires   *time.Time      `json:"expires"`
 		User      *responses.User `json:"user"`
 	}
@@ -60,8 +66,11 @@ func (token *TokenHeader) ToAuthResponse(user responses.User) *responses.Auth {
 	}
 }
 
+// * every token pair of a sign in shares the same session id, the
+// * session is the token family used to detect refresh token reuse
 func GenerateToken(
 	ures *responses.User,
+	sid uuid.UUID,
 	lifespan int,
 	timeunit,
 	privatekey string,
@@ -74,10 +83,12 @@ func GenerateToken(
 		User:       ures,
 		Expire:     exptime.Unix(),
 		TokenUUID:  tuuid,
+		SessionID:  sid,
 	}
 
 	token := &Token{
 		TokenUUID: tuuid,
+		SessionID: sid,
 		Expires:   &exptime,
 		User:      ures,
 	}
@@ -116,7 +127,55 @@ func getDuration(lifespan int, timeunit string) time.Duration {
 	}
 }
 
-func ParseToken(token string, pubkey string) (*Token, error) {
+// * the token is checked against every given public key in order, so the
+// * tokens signed before a key rotation stay valid with the previous key
+func ParseToken(token string, pubkeys ...string) (*Token, error) {
+	var (
+		tparsed *jwt.Token
+		err     error
+	)
+
+	for _, pubkey := range pubkeys {
+		if pubkey == "" {
+			continue
+		}
+
+		// * the error of the current key is the one returned
+		tkey, errkey := parseToken(token, pubkey)
+		if errkey == nil {
+			tparsed = tkey
+			break
+		}
+
+		if err == nil {
+			err = errkey
+		}
+	}
+
+	if tparsed == nil {
+		if err == nil {
+			err = fmt.Errorf("token public key not found")
+		}
+		return nil, err
+	}
+
+	claims, ok := tparsed.Claims.(*TokenClaims)
+	if !ok || !tparsed.Valid {
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+
+	expires := time.Unix(claims.Expire, 0)
+
+	return &Token{
+		Token:     &token,
+		TokenUUID: claims.TokenUUID,
+		SessionID: claims.SessionID,
+		Expires:   &expires,
+		User:      claims.User,
+	}, nil
+}
+
+func parseToken(token string, pubkey string) (*jwt.Token, error) {
 	decodedpubkey, err := base64.StdEncoding.DecodeString(pubkey)
 	if err != nil {
 		utlogger.Error(err)
@@ -129,8 +188,7 @@ func ParseToken(token string, pubkey string) (*Token, error) {
 		return nil, fmt.Errorf("could not parse token public key: %w", err)
 	}
 
-	claims := &TokenClaims{}
-	tparsed, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (any, error) {
+	tparsed, err := jwt.ParseWithClaims(token, &TokenClaims{}, func(token *jwt.Token) (any, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %s", token.Header["alg"])
 		}
@@ -144,19 +202,28 @@ func ParseToken(token string, pubkey string) (*Token, error) {
 		return nil, fmt.Errorf("could not parse token: %w", err)
 	}
 
-	claims, ok := tparsed.Claims.(*TokenClaims)
-	if !ok || !tparsed.Valid {
-		return nil, fmt.Errorf("invalid token: %w", err)
+	return tparsed, nil
+}
+
+// * GenerateKeyPair returns a new rsa private and public key as base64
+// * encoded pem, the same format as the token keys of the env
+func GenerateKeyPair(bits int) (string, string, error) {
+	key, err := rsa.GenerateKey(rand.Reader, bits)
+	if err != nil {
+		utlogger.Error(err)
+		return "", "", fmt.Errorf("could not generate token key: %w", err)
 	}
 
-	expires := time.Unix(claims.Expire, 0)
+	pubkey, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		utlogger.Error(err)
+		return "", "", fmt.Errorf("could not marshal token public key: %w", err)
+	}
 
-	return &Token{
-		Token:     &token,
-		TokenUUID: claims.TokenUUID,
-		Expires:   &expires,
-		User:      claims.User,
-	}, nil
+	privatepem := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	publicpem := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubkey})
+
+	return base64.StdEncoding.EncodeToString(privatepem), base64.StdEncoding.EncodeToString(publicpem), nil
 }
 
 func GetToken(ctx *gin.Context) (string, string, error) {
@@ -197,3 +264,33 @@ func GetUser(ctx *gin.Context) (*responses.User, error) {
 
 	return &userres, nil
 }
+
+// * the scopes of the api key the request is signed in with,
+// * false when the request is signed in with a token instead
+func GetAPIKeyScopes(ctx *gin.Context) ([]consttypes.Permission, bool) {
+	scopesctx, exists := ctx.Get("api_key_scopes")
+	if !exists {
+		return nil, false
+	}
+
+	scopes, ok := scopesctx.([]consttypes.Permission)
+	if !ok {
+		return nil, true
+	}
+
+	return scopes, true
+}
+
+func GetSessionID(ctx *gin.Context) (uuid.UUID, error) {
+	sidctx, exists := ctx.Get("session_id")
+	if !exists {
+		return uuid.Nil, consttypes.ErrSessionNotFound
+	}
+
+	sid, ok := sidctx.(uuid.UUID)
+	if !ok {
+		return uuid.Nil, consttypes.ErrSessionNotFound
+	}
+
+	return sid, nil
+}
@@ -0,0 +1,84 @@
+package uttotp
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"image/png"
+	"time"
+
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+const (
+	period = 30
+	skew   = 1
+	qrsize = 256
+)
+
+type (
+	Key struct {
+		Secret string
+		URI    string
+		QRCode string
+	}
+)
+
+// * generates a new secret for the account together with its otpauth
+// * uri and the qr code of the uri as a png data url
+func GenerateKey(issuer string, account string) (*Key, error) {
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      issuer,
+		AccountName: account,
+		Period:      period,
+		Digits:      otp.DigitsSix,
+		Algorithm:   otp.AlgorithmSHA1,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	img, err := key.Image(qrsize, qrsize)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+
+	return &Key{
+		Secret: key.Secret(),
+		URI:    key.URL(),
+		QRCode: fmt.Sprintf("data:image/png;base64,%s", base64.StdEncoding.EncodeToString(buf.Bytes())),
+	}, nil
+}
+
+// * validates the code against the current time step and one step
+// * around it for clock drift, returns the step the code belongs to
+// * so the caller can reject a code that was already used
+func Validate(secret string, code string, t time.Time) (int64, bool) {
+	for i := -skew; i <= skew; i++ {
+		st := t.Add(time.Duration(i*period) * time.Second)
+
+		expected, err := totp.GenerateCodeCustom(secret, st, totp.ValidateOpts{
+			Period:    period,
+			Digits:    otp.DigitsSix,
+			Algorithm: otp.AlgorithmSHA1,
+		})
+
+		if err != nil {
+			return 0, false
+		}
+
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return st.Unix() / period, true
+		}
+	}
+
+	return 0, false
+}
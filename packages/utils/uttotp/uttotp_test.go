@@ -0,0 +1,115 @@
+package uttotp
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+func newSecret(t *testing.T) string {
+	t.Helper()
+
+	key, err := GenerateKey("meals-app", "admin@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return key.Secret
+}
+
+func code(t *testing.T, secret string, at time.Time) string {
+	t.Helper()
+
+	c, err := totp.GenerateCodeCustom(secret, at, totp.ValidateOpts{
+		Period:    period,
+		Digits:    otp.DigitsSix,
+		Algorithm: otp.AlgorithmSHA1,
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+func TestGenerateKey(t *testing.T) {
+	key, err := GenerateKey("meals-app", "admin@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if key.Secret == "" {
+		t.Error("secret is empty")
+	}
+
+	if !strings.HasPrefix(key.URI, "otpauth://totp/") || !strings.Contains(key.URI, "secret="+key.Secret) {
+		t.Errorf("uri = %q, want an otpauth uri of the secret", key.URI)
+	}
+
+	if !strings.HasPrefix(key.QRCode, "data:image/png;base64,") {
+		t.Errorf("qr code = %.40q, want a png data url", key.QRCode)
+	}
+
+	if other := newSecret(t); other == key.Secret {
+		t.Errorf("two keys share the secret %q", other)
+	}
+}
+
+func TestValidateWindow(t *testing.T) {
+	var (
+		secret = newSecret(t)
+		now    = time.Unix(1_700_000_010, 0)
+		step   = now.Unix() / period
+	)
+
+	// * one step around the current one is accepted for clock drift
+	for name, c := range map[string]struct {
+		offset time.Duration
+		step   int64
+		ok     bool
+	}{
+		"current":       {0, step, true},
+		"previous":      {-period * time.Second, step - 1, true},
+		"next":          {period * time.Second, step + 1, true},
+		"two before":    {-2 * period * time.Second, 0, false},
+		"two after":     {2 * period * time.Second, 0, false},
+		"end of window": {(period - 10) * time.Second, step, true},
+	} {
+		got, ok := Validate(secret, code(t, secret, now.Add(c.offset)), now)
+		if ok != c.ok {
+			t.Errorf("%s: ok = %v, want %v", name, ok, c.ok)
+			continue
+		}
+
+		if ok && got != c.step {
+			t.Errorf("%s: step = %d, want %d", name, got, c.step)
+		}
+	}
+}
+
+func TestValidateRejectsInvalid(t *testing.T) {
+	var (
+		secret = newSecret(t)
+		now    = time.Unix(1_700_000_010, 0)
+		valid  = code(t, secret, now)
+	)
+
+	for name, c := range map[string]string{
+		"empty":        "",
+		"short":        valid[:5],
+		"long":         valid + "0",
+		"other secret": code(t, newSecret(t), now),
+	} {
+		if _, ok := Validate(secret, c, now); ok {
+			t.Errorf("%s: ok = true, want the code rejected", name)
+		}
+	}
+
+	if _, ok := Validate("not base32!", valid, now); ok {
+		t.Error("invalid secret: ok = true, want the code rejected")
+	}
+}